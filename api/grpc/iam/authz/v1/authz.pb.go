@@ -9,6 +9,7 @@ package authzv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -308,11 +309,15 @@ func (x *GetAuthorizationSnapshotResponse) GetAuthzVersion() int64 {
 }
 
 type GrantAssignmentRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Subject       string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
-	Domain        string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
-	RoleName      string                 `protobuf:"bytes,3,opt,name=role_name,json=roleName,proto3" json:"role_name,omitempty"`
-	GrantedBy     string                 `protobuf:"bytes,4,opt,name=granted_by,json=grantedBy,proto3" json:"granted_by,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Subject   string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Domain    string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	RoleName  string                 `protobuf:"bytes,3,opt,name=role_name,json=roleName,proto3" json:"role_name,omitempty"`
+	GrantedBy string                 `protobuf:"bytes,4,opt,name=granted_by,json=grantedBy,proto3" json:"granted_by,omitempty"`
+	// 生效时间，缺省立即生效；晚于当前时间时由调度器到点授予
+	NotBefore *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	// 失效时间，缺省永久有效；到点由调度器撤销，赋权记录保留为已过期
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -375,6 +380,20 @@ func (x *GrantAssignmentRequest) GetGrantedBy() string {
 	return ""
 }
 
+func (x *GrantAssignmentRequest) GetNotBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotBefore
+	}
+	return nil
+}
+
+func (x *GrantAssignmentRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
 type GrantAssignmentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -511,7 +530,7 @@ var File_iam_authz_v1_authz_proto protoreflect.FileDescriptor
 
 const file_iam_authz_v1_authz_proto_rawDesc = "" +
 	"\n" +
-	"\x18iam/authz/v1/authz.proto\x12\fiam.authz.v1\"p\n" +
+	"\x18iam/authz/v1/authz.proto\x12\fiam.authz.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"p\n" +
 	"\fCheckRequest\x12\x18\n" +
 	"\asubject\x18\x01 \x01(\tR\asubject\x12\x16\n" +
 	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x16\n" +
@@ -529,13 +548,17 @@ const file_iam_authz_v1_authz_proto_rawDesc = "" +
 	" GetAuthorizationSnapshotResponse\x12\x14\n" +
 	"\x05roles\x18\x01 \x03(\tR\x05roles\x12?\n" +
 	"\vpermissions\x18\x02 \x03(\v2\x1d.iam.authz.v1.PermissionEntryR\vpermissions\x12#\n" +
-	"\rauthz_version\x18\x03 \x01(\x03R\fauthzVersion\"\x86\x01\n" +
+	"\rauthz_version\x18\x03 \x01(\x03R\fauthzVersion\"\xfc\x01\n" +
 	"\x16GrantAssignmentRequest\x12\x18\n" +
 	"\asubject\x18\x01 \x01(\tR\asubject\x12\x16\n" +
 	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x1b\n" +
 	"\trole_name\x18\x03 \x01(\tR\broleName\x12\x1d\n" +
 	"\n" +
-	"granted_by\x18\x04 \x01(\tR\tgrantedBy\"\x19\n" +
+	"granted_by\x18\x04 \x01(\tR\tgrantedBy\x129\n" +
+	"\n" +
+	"not_before\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tnotBefore\x129\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\x19\n" +
 	"\x17GrantAssignmentResponse\"h\n" +
 	"\x17RevokeAssignmentRequest\x12\x18\n" +
 	"\asubject\x18\x01 \x01(\tR\asubject\x12\x16\n" +
@@ -571,22 +594,25 @@ var file_iam_authz_v1_authz_proto_goTypes = []any{
 	(*GrantAssignmentResponse)(nil),          // 6: iam.authz.v1.GrantAssignmentResponse
 	(*RevokeAssignmentRequest)(nil),          // 7: iam.authz.v1.RevokeAssignmentRequest
 	(*RevokeAssignmentResponse)(nil),         // 8: iam.authz.v1.RevokeAssignmentResponse
+	(*timestamppb.Timestamp)(nil),            // 9: google.protobuf.Timestamp
 }
 var file_iam_authz_v1_authz_proto_depIdxs = []int32{
 	2, // 0: iam.authz.v1.GetAuthorizationSnapshotResponse.permissions:type_name -> iam.authz.v1.PermissionEntry
-	0, // 1: iam.authz.v1.AuthorizationService.Check:input_type -> iam.authz.v1.CheckRequest
-	3, // 2: iam.authz.v1.AuthorizationService.GetAuthorizationSnapshot:input_type -> iam.authz.v1.GetAuthorizationSnapshotRequest
-	5, // 3: iam.authz.v1.AuthorizationService.GrantAssignment:input_type -> iam.authz.v1.GrantAssignmentRequest
-	7, // 4: iam.authz.v1.AuthorizationService.RevokeAssignment:input_type -> iam.authz.v1.RevokeAssignmentRequest
-	1, // 5: iam.authz.v1.AuthorizationService.Check:output_type -> iam.authz.v1.CheckResponse
-	4, // 6: iam.authz.v1.AuthorizationService.GetAuthorizationSnapshot:output_type -> iam.authz.v1.GetAuthorizationSnapshotResponse
-	6, // 7: iam.authz.v1.AuthorizationService.GrantAssignment:output_type -> iam.authz.v1.GrantAssignmentResponse
-	8, // 8: iam.authz.v1.AuthorizationService.RevokeAssignment:output_type -> iam.authz.v1.RevokeAssignmentResponse
-	5, // [5:9] is the sub-list for method output_type
-	1, // [1:5] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	9, // 1: iam.authz.v1.GrantAssignmentRequest.not_before:type_name -> google.protobuf.Timestamp
+	9, // 2: iam.authz.v1.GrantAssignmentRequest.expires_at:type_name -> google.protobuf.Timestamp
+	0, // 3: iam.authz.v1.AuthorizationService.Check:input_type -> iam.authz.v1.CheckRequest
+	3, // 4: iam.authz.v1.AuthorizationService.GetAuthorizationSnapshot:input_type -> iam.authz.v1.GetAuthorizationSnapshotRequest
+	5, // 5: iam.authz.v1.AuthorizationService.GrantAssignment:input_type -> iam.authz.v1.GrantAssignmentRequest
+	7, // 6: iam.authz.v1.AuthorizationService.RevokeAssignment:input_type -> iam.authz.v1.RevokeAssignmentRequest
+	1, // 7: iam.authz.v1.AuthorizationService.Check:output_type -> iam.authz.v1.CheckResponse
+	4, // 8: iam.authz.v1.AuthorizationService.GetAuthorizationSnapshot:output_type -> iam.authz.v1.GetAuthorizationSnapshotResponse
+	6, // 9: iam.authz.v1.AuthorizationService.GrantAssignment:output_type -> iam.authz.v1.GrantAssignmentResponse
+	8, // 10: iam.authz.v1.AuthorizationService.RevokeAssignment:output_type -> iam.authz.v1.RevokeAssignmentResponse
+	7, // [7:11] is the sub-list for method output_type
+	3, // [3:7] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_iam_authz_v1_authz_proto_init() }
@@ -0,0 +1,8 @@
+// iamctl 是 IAM 的管理命令行工具
+package main
+
+import "github.com/FangcunMount/iam-contracts/internal/iamctl"
+
+func main() {
+	iamctl.NewApp("iamctl").Run()
+}
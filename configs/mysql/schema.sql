@@ -48,6 +48,7 @@ CREATE TABLE IF NOT EXISTS `users`
 CREATE TABLE IF NOT EXISTS `children`
 (
     `id`         BIGINT UNSIGNED NOT NULL PRIMARY KEY COMMENT '儿童ID',
+    `tenant_id`  VARCHAR(64)     NOT NULL DEFAULT 'fangcun' COMMENT '所属租户ID',
     `name`       VARCHAR(64)     NOT NULL COMMENT '儿童姓名',
     `id_card`    VARCHAR(20)              DEFAULT NULL COMMENT '身份证号码',
     `gender`     TINYINT         NOT NULL DEFAULT 0 COMMENT '性别: 0-未知, 1-男, 2-女',
@@ -62,6 +63,7 @@ CREATE TABLE IF NOT EXISTS `children`
     `deleted_by` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '删除人ID',
     `version`    INT UNSIGNED    NOT NULL DEFAULT 1 COMMENT '乐观锁版本号',
     UNIQUE KEY `uk_id_card` (`id_card`),
+    KEY `idx_tenant_id` (`tenant_id`),
     KEY `idx_deleted_at` (`deleted_at`),
     KEY `idx_name_gender_birthday` (`name`, `gender`, `birthday`)
 ) ENGINE = InnoDB
@@ -99,7 +101,7 @@ CREATE TABLE IF NOT EXISTS `auth_accounts`
 (
     `id`               BIGINT UNSIGNED NOT NULL COMMENT '账户ID（Snowflake）',
     `user_id`          BIGINT UNSIGNED NOT NULL COMMENT '关联用户ID',
-    `type`             VARCHAR(32)     NOT NULL COMMENT '账户类型: wc-minip|wc-offi|wc-com|opera',
+    `type`             VARCHAR(32)     NOT NULL COMMENT '账户类型: wc-minip|wc-offi|wc-com|opera|oidc',
     `app_id`           VARCHAR(64)     NOT NULL DEFAULT '' COMMENT '应用ID: 微信appid|企业微信corpid|运营后台为空',
     `external_id`      VARCHAR(128)    NOT NULL COMMENT '外部平台用户标识: openid|userid|username',
     `scoped_tenant_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '运营账号租户作用域，仅 type=opera 有效',
@@ -133,7 +135,7 @@ CREATE TABLE IF NOT EXISTS `auth_credentials`
 (
     `id`               BIGINT          NOT NULL AUTO_INCREMENT COMMENT '凭据ID',
     `account_id`       BIGINT UNSIGNED NOT NULL COMMENT '关联账户ID',
-    `type`             VARCHAR(32)     NOT NULL COMMENT '凭据类型: password|phone_otp|oauth_wx_minip|oauth_wecom',
+    `type`             VARCHAR(32)     NOT NULL COMMENT '凭据类型: password|phone_otp|oauth_wx_minip|oauth_wx_mp|oauth_wecom|oauth_oidc',
     `idp`              VARCHAR(32)              DEFAULT NULL COMMENT 'IDP类型: wechat|wecom|phone|NULL(本地)',
     `idp_identifier`   VARCHAR(256)    NOT NULL DEFAULT '' COMMENT 'IDP标识符: unionid|openid@appid|userid|+E164|空',
     `app_id`           VARCHAR(64)              DEFAULT NULL COMMENT '应用ID: wechat=appid|wecom=corpid|NULL(本地)',
@@ -145,6 +147,7 @@ CREATE TABLE IF NOT EXISTS `auth_credentials`
     `locked_until`     DATETIME                 DEFAULT NULL COMMENT '锁定截止时间（仅password）',
     `last_success_at`  DATETIME                 DEFAULT NULL COMMENT '最近成功时间',
     `last_failure_at`  DATETIME                 DEFAULT NULL COMMENT '最近失败时间',
+    `password_changed_at` DATETIME              DEFAULT NULL COMMENT '密码设置时间（仅password）',
     `created_at`       DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     `updated_at`       DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
     `deleted_at`       DATETIME                 DEFAULT NULL COMMENT '删除时间（软删除）',
@@ -165,6 +168,19 @@ CREATE TABLE IF NOT EXISTS `auth_credentials`
   COLLATE = utf8mb4_unicode_ci
     COMMENT ='认证凭据表 - 统一管理所有类型的认证凭据';
 
+-- 2.2.1 密码历史表（禁止复用最近 N 个密码）
+CREATE TABLE IF NOT EXISTS `auth_password_history`
+(
+    `id`            BIGINT UNSIGNED NOT NULL PRIMARY KEY COMMENT '记录ID',
+    `credential_id` BIGINT UNSIGNED NOT NULL COMMENT '密码凭据ID（auth_credentials.id）',
+    `material`      VARBINARY(512)  NOT NULL COMMENT '历史密码 PHC 哈希',
+    `algo`          VARCHAR(32)     NOT NULL COMMENT '哈希算法',
+    `created_at`    DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '写入时间（即该密码被替换的时间）',
+    KEY `idx_credential_created` (`credential_id`, `created_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='密码历史表';
+
 -- 2.3 Token 审计表（主存储在 Redis，此表仅用于审计）
 CREATE TABLE IF NOT EXISTS `auth_token_audit`
 (
@@ -219,6 +235,19 @@ CREATE TABLE IF NOT EXISTS `jwks_keys`
   DEFAULT CHARSET = utf8mb4
   COLLATE = utf8mb4_unicode_ci COMMENT ='JWKS 密钥表';
 
+-- 2.4.1 JWKS 加密私钥表（jwks.storage.backend=mysql）
+CREATE TABLE IF NOT EXISTS `jwks_private_keys`
+(
+    `id`         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY COMMENT '记录ID',
+    `kid`        VARCHAR(64)     NOT NULL COMMENT 'Key ID（jwks_keys.kid）',
+    `alg`        VARCHAR(32)     NOT NULL COMMENT '算法: RS256/ES256/EdDSA 等',
+    `ciphertext` VARBINARY(8192) NOT NULL COMMENT 'PKCS#8 私钥密文（AES-256-GCM）',
+    `created_at` DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    UNIQUE KEY `uk_private_kid` (`kid`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='JWKS 加密私钥表';
+
 -- 2.5 会话表
 CREATE TABLE IF NOT EXISTS `auth_sessions`
 (
@@ -259,6 +288,36 @@ CREATE TABLE IF NOT EXISTS `auth_revoked_access_token`
   DEFAULT CHARSET = utf8mb4
   COLLATE = utf8mb4_unicode_ci COMMENT ='已撤销访问令牌表';
 
+-- 2.7 SAML IdP 登记表
+CREATE TABLE IF NOT EXISTS `auth_saml_idps`
+(
+    `id`                BIGINT UNSIGNED NOT NULL COMMENT '主键 ID (Snowflake)',
+    `tenant_id`         BIGINT UNSIGNED NOT NULL COMMENT '登记所属租户，即时开通与登录令牌使用该租户',
+    `slug`              VARCHAR(64)     NOT NULL COMMENT 'IdP 标识，写入联合登录凭据 app_id 与 ACS 路径',
+    `name`              VARCHAR(255)    NOT NULL COMMENT 'IdP 名称',
+    `entity_id`         VARCHAR(512)    NOT NULL COMMENT 'IdP EntityID（断言 Issuer 校验）',
+    `sso_url`           VARCHAR(1024)   NOT NULL COMMENT 'HTTP-Redirect 绑定的 SSO 地址',
+    `certificates`      JSON                     DEFAULT NULL COMMENT 'IdP 签名证书列表（Base64 DER）',
+    `attribute_mapping` JSON                     DEFAULT NULL COMMENT 'SAML 属性名到 Claims 键的映射',
+    `auto_provision`    TINYINT(1)      NOT NULL DEFAULT 0 COMMENT '首次登录是否即时开通账户',
+    `status`            VARCHAR(32)     NOT NULL DEFAULT 'Enabled' COMMENT '状态 (Enabled/Disabled)',
+    `metadata_xml`      MEDIUMTEXT               DEFAULT NULL COMMENT '登记时提交的 IdP 元数据原文',
+    `created_at`        DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`        DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    `deleted_at`        DATETIME                 DEFAULT NULL COMMENT '删除时间（软删除）',
+    `created_by`        BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '创建人ID',
+    `updated_by`        BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '更新人ID',
+    `deleted_by`        BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '删除人ID',
+    `version`           INT UNSIGNED    NOT NULL DEFAULT 1 COMMENT '乐观锁版本号',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_slug` (`slug`),
+    KEY `idx_tenant_id` (`tenant_id`),
+    KEY `idx_status` (`status`),
+    KEY `idx_deleted_at` (`deleted_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='SAML IdP 登记表 - 管理租户 SAML 单点登录配置';
+
 -- ============================================================================
 -- Module 3: Authorization (Authz)
 -- ============================================================================
@@ -323,6 +382,9 @@ CREATE TABLE IF NOT EXISTS `authz_assignments`
     `tenant_id`    VARCHAR(64)     NOT NULL COMMENT '租户ID',
     `granted_by`   VARCHAR(64)              DEFAULT NULL COMMENT '授权操作人',
     `granted_at`   DATETIME        NOT NULL COMMENT '授权时间',
+    `not_before`   DATETIME                 DEFAULT NULL COMMENT '生效时间（NULL 表示立即生效）',
+    `expires_at`   DATETIME                 DEFAULT NULL COMMENT '失效时间（NULL 表示永久有效）',
+    `status`       VARCHAR(16)     NOT NULL DEFAULT 'active' COMMENT '有效期状态: pending/active/expired',
     `created_at`   DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     `updated_at`   DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
     `deleted_at`   DATETIME                 DEFAULT NULL COMMENT '删除时间',
@@ -333,6 +395,8 @@ CREATE TABLE IF NOT EXISTS `authz_assignments`
     KEY `idx_subject` (`subject_type`, `subject_id`),
     KEY `idx_role_id` (`role_id`),
     KEY `idx_tenant_id` (`tenant_id`),
+    KEY `idx_status_not_before` (`status`, `not_before`),
+    KEY `idx_status_expires_at` (`status`, `expires_at`),
     KEY `idx_deleted_at` (`deleted_at`)
 ) ENGINE = InnoDB
   DEFAULT CHARSET = utf8mb4
@@ -346,6 +410,7 @@ CREATE TABLE IF NOT EXISTS `authz_policy_versions`
     `policy_version` BIGINT          NOT NULL COMMENT '策略版本号',
     `changed_by`     VARCHAR(64)              DEFAULT NULL COMMENT '变更操作人',
     `reason`         VARCHAR(512)             DEFAULT NULL COMMENT '变更原因',
+    `changes`        JSON                     DEFAULT NULL COMMENT '产生该版本的规则变更集（NULL 表示未记录）',
     `created_at`     DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     `updated_at`     DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
     `deleted_at`     DATETIME                 DEFAULT NULL COMMENT '删除时间',
@@ -376,6 +441,40 @@ CREATE TABLE IF NOT EXISTS `casbin_rule`
   DEFAULT CHARSET = utf8mb4
   COLLATE = utf8mb4_unicode_ci COMMENT ='Casbin 策略规则表 - 存储 RBAC 策略规则';
 
+-- 3.6 gRPC 服务 ACL 表
+CREATE TABLE IF NOT EXISTS `authz_grpc_service_acls`
+(
+    `id`              BIGINT UNSIGNED NOT NULL COMMENT '主键 ID (Snowflake)',
+    `service_name`    VARCHAR(128)    NOT NULL COMMENT '调用方服务名（客户端证书 CN）',
+    `enabled`         TINYINT(1)      NOT NULL DEFAULT 1 COMMENT '是否启用，禁用后该服务的全部调用被拒绝',
+    `description`     VARCHAR(512)             DEFAULT NULL COMMENT '描述',
+    `allowed_methods` JSON                     DEFAULT NULL COMMENT '允许调用的方法模式列表',
+    `denied_methods`  JSON                     DEFAULT NULL COMMENT '拒绝调用的方法模式列表（优先于允许列表）',
+    `created_at`      DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`      DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    `deleted_at`      DATETIME                 DEFAULT NULL COMMENT '删除时间',
+    `created_by`      BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '创建人ID',
+    `updated_by`      BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '更新人ID',
+    `deleted_by`      BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '删除人ID',
+    `version`         INT UNSIGNED    NOT NULL DEFAULT 1 COMMENT '乐观锁版本号',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_service_name` (`service_name`),
+    KEY `idx_deleted_at` (`deleted_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='gRPC 服务 ACL 表 - 管理内部服务可调用的 gRPC 方法';
+
+-- 3.7 gRPC 服务 ACL 导入标记表
+CREATE TABLE IF NOT EXISTS `authz_grpc_service_acl_seeds`
+(
+    `name`      VARCHAR(64)  NOT NULL COMMENT '种子来源标识',
+    `services`  INT UNSIGNED NOT NULL DEFAULT 0 COMMENT '导入的条目数',
+    `seeded_at` DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '导入时间',
+    PRIMARY KEY (`name`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='gRPC 服务 ACL 导入标记表 - 记录启动配置是否已导入';
+
 -- ============================================================================
 -- Module 4: Identity Provider (IDP)
 -- ============================================================================
@@ -407,6 +506,52 @@ CREATE TABLE IF NOT EXISTS `idp_wechat_apps`
   DEFAULT CHARSET = utf8mb4
   COLLATE = utf8mb4_unicode_ci COMMENT ='微信应用表 - 管理微信小程序/公众号应用配置';
 
+CREATE TABLE IF NOT EXISTS `idp_wecom_apps`
+(
+    `id`                     BIGINT UNSIGNED NOT NULL COMMENT '主键 ID (Snowflake)',
+    `corp_id`                VARCHAR(64)     NOT NULL COMMENT '企业 ID (CorpID)',
+    `agent_id`               VARCHAR(64)     NOT NULL COMMENT '应用 ID (AgentID)',
+    `name`                   VARCHAR(255)    NOT NULL COMMENT '应用名称',
+    `status`                 VARCHAR(32)     NOT NULL DEFAULT 'Enabled' COMMENT '应用状态 (Enabled/Disabled/Archived)',
+    `corp_secret_cipher`     BLOB                     DEFAULT NULL COMMENT 'CorpSecret 密文 (AES-GCM 加密)',
+    `corp_secret_fp`         VARCHAR(128)             DEFAULT NULL COMMENT 'CorpSecret 指纹 (SHA256)',
+    `corp_secret_version`    INT             NOT NULL DEFAULT 0 COMMENT 'CorpSecret 版本号',
+    `corp_secret_rotated_at` DATETIME                 DEFAULT NULL COMMENT 'CorpSecret 最后轮换时间',
+    `created_at`             DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`             DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_corp_agent` (`corp_id`, `agent_id`),
+    KEY `idx_status` (`status`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='企业微信应用表 - 管理企业微信自建应用配置';
+
+CREATE TABLE IF NOT EXISTS `idp_oidc_providers`
+(
+    `id`                       BIGINT UNSIGNED NOT NULL COMMENT '主键 ID (Snowflake)',
+    `slug`                     VARCHAR(64)     NOT NULL COMMENT '提供商标识，写入联合登录凭据 app_id',
+    `name`                     VARCHAR(255)    NOT NULL COMMENT '提供商名称',
+    `issuer`                   VARCHAR(512)    NOT NULL COMMENT '上游 Issuer（discovery 与 id_token iss 校验）',
+    `client_id`                VARCHAR(255)    NOT NULL COMMENT '上游分配的 client_id',
+    `scopes`                   JSON                     DEFAULT NULL COMMENT '授权请求 scope 列表',
+    `claim_mapping`            JSON                     DEFAULT NULL COMMENT '声明映射 (subject/username/email/name/phone)',
+    `role_mappings`            JSON                     DEFAULT NULL COMMENT '声明到本地角色的映射规则',
+    `auto_provision`           TINYINT(1)      NOT NULL DEFAULT 0 COMMENT '首次登录是否即时开通账户',
+    `tenant_id`                BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '映射角色所在租户，0 表示默认租户',
+    `status`                   VARCHAR(32)     NOT NULL DEFAULT 'Enabled' COMMENT '状态 (Enabled/Disabled/Archived)',
+    `client_secret_cipher`     BLOB                     DEFAULT NULL COMMENT 'client_secret 密文 (AES-GCM 加密)',
+    `client_secret_fp`         VARCHAR(128)             DEFAULT NULL COMMENT 'client_secret 指纹 (SHA256)',
+    `client_secret_version`    INT             NOT NULL DEFAULT 0 COMMENT 'client_secret 版本号',
+    `client_secret_rotated_at` DATETIME                 DEFAULT NULL COMMENT 'client_secret 最后轮换时间',
+    `created_at`               DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`               DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_slug` (`slug`),
+    KEY `idx_status` (`status`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='上游 OIDC 身份提供商表 - 管理联合登录配置';
+
 
 -- ============================================================================
 -- Module 5: Platform / System
@@ -512,6 +657,33 @@ CREATE TABLE IF NOT EXISTS `data_dictionary`
   DEFAULT CHARSET = utf8mb4
   COLLATE = utf8mb4_unicode_ci COMMENT ='数据字典表 - 管理系统枚举值';
 
+-- 5.6 事务性发件箱事件表（领域事件与业务数据同事务写入，由中继异步投递）
+CREATE TABLE IF NOT EXISTS `outbox_events`
+(
+    `id`              BIGINT UNSIGNED NOT NULL PRIMARY KEY COMMENT '事件ID',
+    `topic`           VARCHAR(128)    NOT NULL COMMENT '目标主题',
+    `event_type`      VARCHAR(128)    NOT NULL COMMENT '事件类型',
+    `aggregate_type`  VARCHAR(64)     NOT NULL DEFAULT '' COMMENT '聚合类型',
+    `aggregate_id`    VARCHAR(128)    NOT NULL DEFAULT '' COMMENT '聚合标识',
+    `idempotency_key` VARCHAR(191)    NOT NULL COMMENT '幂等键（消息UUID）',
+    `payload`         BLOB            NOT NULL COMMENT '消息体',
+    `metadata`        JSON                     DEFAULT NULL COMMENT '消息元数据',
+    `status`          VARCHAR(16)     NOT NULL DEFAULT 'pending' COMMENT '状态：pending/published/dead',
+    `attempts`        INT UNSIGNED    NOT NULL DEFAULT 0 COMMENT '已投递次数',
+    `next_attempt_at` DATETIME(3)     NOT NULL COMMENT '下次投递时间',
+    `last_error`      VARCHAR(1024)            DEFAULT NULL COMMENT '最近一次投递错误',
+    `locked_by`       VARCHAR(128)             DEFAULT NULL COMMENT '认领该事件的中继实例',
+    `locked_until`    DATETIME(3)              DEFAULT NULL COMMENT '认领过期时间',
+    `published_at`    DATETIME(3)              DEFAULT NULL COMMENT '投递成功时间',
+    `created_at`      DATETIME(3)     NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+    UNIQUE KEY `uk_idempotency_key` (`idempotency_key`),
+    KEY `idx_status_next_attempt` (`status`, `next_attempt_at`),
+    KEY `idx_locked_by` (`locked_by`),
+    KEY `idx_published_at` (`published_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='事务性发件箱事件表';
+
 -- ============================================================================
 -- Schema 版本管理
 -- ============================================================================
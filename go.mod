@@ -4,6 +4,7 @@ go 1.24.0
 
 require (
 	github.com/alicebob/miniredis/v2 v2.30.0
+	github.com/beevik/etree v1.7.0
 	github.com/go-sql-driver/mysql v1.9.3
 	github.com/google/uuid v1.6.0
 	github.com/lestrrat-go/jwx/v2 v2.1.6
@@ -12,6 +13,7 @@ require (
 	github.com/mozillazg/go-pinyin v0.21.0
 	github.com/prometheus/client_golang v1.22.0
 	github.com/robfig/cron/v3 v3.0.1
+	github.com/russellhaering/goxmldsig v1.6.1
 	github.com/silenceper/wechat/v2 v2.1.11
 	github.com/spf13/cobra v1.9.1
 	github.com/spf13/viper v1.20.1
@@ -156,6 +158,7 @@ require (
 	github.com/golang-migrate/migrate/v4 v4.19.1
 	github.com/gosuri/uitable v0.0.4
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+	github.com/jonboulle/clockwork v0.5.0 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/nyaruka/phonenumbers v1.6.7
 	github.com/redis/go-redis/v9 v9.16.0
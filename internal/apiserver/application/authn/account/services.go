@@ -36,6 +36,9 @@ type AccountApplicationService interface {
 	// DisableAccount 禁用账户
 	DisableAccount(ctx context.Context, accountID meta.ID) error
 
+	// UnlockAccount 解除账户凭据的失败锁定并清除登录失败计数（管理员操作）
+	UnlockAccount(ctx context.Context, accountID meta.ID) error
+
 	// ArchiveAccount 归档账户
 	ArchiveAccount(ctx context.Context, accountID meta.ID) error
 
@@ -2,11 +2,14 @@ package account
 
 import (
 	"context"
+	"strings"
 
 	perrors "github.com/FangcunMount/component-base/pkg/errors"
 	"github.com/FangcunMount/component-base/pkg/logger"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/uow"
 	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	credDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/credential"
 	sessiondomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/session"
 	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
 	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
@@ -19,14 +22,16 @@ import (
 type accountApplicationService struct {
 	uow            uow.UnitOfWork
 	sessionManager sessiondomain.Manager
+	throttle       authentication.LoginThrottle
 }
 
 // accountApplicationService 实现 AccountApplicationService 接口
 var _ AccountApplicationService = (*accountApplicationService)(nil)
 
 // NewAccountApplicationService 创建账户应用服务
-func NewAccountApplicationService(uow uow.UnitOfWork, sessionManager sessiondomain.Manager) AccountApplicationService {
-	return &accountApplicationService{uow: uow, sessionManager: sessionManager}
+// throttle 可为 nil（未启用登录节流时解锁只处理凭据锁定）
+func NewAccountApplicationService(uow uow.UnitOfWork, sessionManager sessiondomain.Manager, throttle authentication.LoginThrottle) AccountApplicationService {
+	return &accountApplicationService{uow: uow, sessionManager: sessionManager, throttle: throttle}
 }
 
 // GetAccountByID 根据ID获取账户
@@ -219,6 +224,86 @@ func (s *accountApplicationService) DisableAccount(ctx context.Context, accountI
 	return s.sessionManager.RevokeByAccount(ctx, accountID, "account_disabled", accountID.String())
 }
 
+// UnlockAccount 解除账户凭据锁定
+// 凭据侧复用 credential.Locker 清零失败计数与锁定时间；节流侧清除登录名与手机号的失败计数，来源 IP 计数不受影响
+func (s *accountApplicationService) UnlockAccount(ctx context.Context, accountID meta.ID) error {
+	l := logger.L(ctx)
+	var subjects []authentication.ThrottleSubject
+
+	err := s.uow.WithinTx(ctx, func(tx uow.TxRepositories) error {
+		account, err := tx.Accounts.GetByID(ctx, accountID)
+		if err != nil {
+			return perrors.WrapC(err, code.ErrDatabase, "failed to get account")
+		}
+		if account == nil {
+			return perrors.WithCode(code.ErrNotFoundAccount, "account not found")
+		}
+		if account.Type == domain.TypeOpera && account.ExternalID != "" {
+			subjects = append(subjects, authentication.ThrottleSubject{
+				Kind:  authentication.ThrottleSubjectUsername,
+				Value: strings.ToLower(strings.TrimSpace(string(account.ExternalID))),
+			})
+		}
+
+		creds, err := tx.Credentials.ListByAccountID(ctx, accountID)
+		if err != nil {
+			return perrors.WrapC(err, code.ErrDatabase, "failed to list credentials")
+		}
+		locker := credDomain.NewLocker()
+		for _, cred := range creds {
+			if cred.IsPhoneOTPType() {
+				subjects = append(subjects, authentication.ThrottleSubject{Kind: authentication.ThrottleSubjectPhone, Value: cred.IDPIdentifier})
+			}
+			hadFailures, wasLocked := cred.FailedAttempts > 0, cred.LockedUntil != nil
+			if !hadFailures && !wasLocked {
+				continue
+			}
+			locker.Unlock(cred)
+			if hadFailures {
+				if err := tx.Credentials.UpdateFailedAttempts(ctx, cred.ID, cred.FailedAttempts); err != nil {
+					return perrors.WrapC(err, code.ErrDatabase, "failed to reset failed attempts")
+				}
+			}
+			if wasLocked {
+				if err := tx.Credentials.UpdateLockedUntil(ctx, cred.ID, nil); err != nil {
+					return perrors.WrapC(err, code.ErrDatabase, "failed to clear credential lock")
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		l.Warnw("解锁账户失败",
+			"action", logger.ActionUpdate,
+			"resource", "account",
+			"account_id", accountID.String(),
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return err
+	}
+
+	if s.throttle != nil {
+		for _, subject := range subjects {
+			if err := s.throttle.Reset(ctx, subject); err != nil {
+				l.Warnw("清除登录失败计数失败",
+					"account_id", accountID.String(),
+					"subject", string(subject.Kind),
+					"error", err.Error(),
+				)
+			}
+		}
+	}
+
+	l.Infow("账户已解锁",
+		"action", logger.ActionUpdate,
+		"resource", "account",
+		"account_id", accountID.String(),
+		"result", logger.ResultSuccess,
+	)
+	return nil
+}
+
 func (s *accountApplicationService) ArchiveAccount(ctx context.Context, accountID meta.ID) error {
 	return s.uow.WithinTx(ctx, func(tx uow.TxRepositories) error {
 		// 使用新的 StatusManager 接口
@@ -0,0 +1,70 @@
+package binding
+
+import (
+	"context"
+
+	credDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/credential"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// ============= 应用服务接口（Driving Ports）=============
+
+// BindingApplicationService 账户身份绑定服务
+// 为已登录账户追加或解除外部登录方式（微信小程序、企业微信、手机号），
+// 避免同一个人以不同方式首次登录后产生多个用户
+type BindingApplicationService interface {
+	// ListBindings 列出账户当前的登录方式
+	ListBindings(ctx context.Context, accountID meta.ID) ([]*BindingResult, error)
+
+	// BindWechat 凭 wx.login code 绑定微信小程序身份（优先 UnionID）
+	BindWechat(ctx context.Context, req BindWechatRequest) (*BindingResult, error)
+
+	// BindWecom 凭企业微信授权 code 绑定企业微信身份
+	BindWecom(ctx context.Context, req BindWecomRequest) (*BindingResult, error)
+
+	// SendPhoneBindCode 发送绑定手机号验证码（场景 bind_phone）
+	SendPhoneBindCode(ctx context.Context, phone string) error
+
+	// BindPhone 凭短信验证码绑定手机号
+	BindPhone(ctx context.Context, req BindPhoneRequest) (*BindingResult, error)
+
+	// Unbind 解除外部身份绑定；账户至少保留一种可用的登录方式
+	Unbind(ctx context.Context, accountID, credentialID meta.ID) error
+
+	// RevokeWechatAuthorization 用户在微信侧撤回授权后禁用对应的微信凭据，返回被禁用的数量
+	RevokeWechatAuthorization(ctx context.Context, appID, openID string) (int, error)
+}
+
+// ============= DTOs =============
+
+// BindWechatRequest 绑定微信小程序请求
+type BindWechatRequest struct {
+	AccountID meta.ID // 当前登录账户
+	AppID     string  // 小程序 AppID
+	JsCode    string  // wx.login 返回的 code
+}
+
+// BindWecomRequest 绑定企业微信请求
+type BindWecomRequest struct {
+	AccountID meta.ID // 当前登录账户
+	CorpID    string  // 企业 CorpID
+	AgentID   string  // 应用 AgentID（企业下登记了多个应用时必填）
+	Code      string  // 企业微信授权 code
+}
+
+// BindPhoneRequest 绑定手机号请求
+type BindPhoneRequest struct {
+	AccountID meta.ID // 当前登录账户
+	Phone     string  // 手机号（支持国内号码，服务端规范为 E.164）
+	Code      string  // 短信验证码
+}
+
+// BindingResult 登录方式
+type BindingResult struct {
+	CredentialID meta.ID                   // 凭据ID
+	Type         credDomain.CredentialType // 凭据类型
+	IDP          string                    // 身份提供商（wechat/wecom/phone，密码为空）
+	Identifier   string                    // 外部标识（UnionID/OpenID/UserID/手机号）
+	AppID        string                    // 应用ID（AppID/CorpID）
+	Enabled      bool                      // 是否启用
+}
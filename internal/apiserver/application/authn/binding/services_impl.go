@@ -0,0 +1,420 @@
+package binding
+
+import (
+	"context"
+	"strings"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/logger"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/loginprep"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/uow"
+	accountDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	credDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/credential"
+	idpPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+	wecomPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// bindPhoneScene 绑定手机号验证码场景（与登录、重置密码验证码隔离）
+const bindPhoneScene = "bind_phone"
+
+// bindingApplicationService 账户身份绑定服务实现
+type bindingApplicationService struct {
+	uow         uow.UnitOfWork
+	issuer      credDomain.Issuer
+	lifecycle   credDomain.Lifecycle
+	idp         authentication.IdentityProvider
+	otpVerifier authentication.OTPVerifier
+	phoneOTP    *loginprep.PhoneOTPDeps
+	apps        *loginprep.IDPAppResolver
+}
+
+var _ BindingApplicationService = (*bindingApplicationService)(nil)
+
+// 撤回授权事件由 IDP 模块的消息推送回调驱动
+var _ idpPort.AuthorizationRevoker = (*bindingApplicationService)(nil)
+
+// NewBindingApplicationService 创建账户身份绑定服务
+// phoneOTP 与登录发码共用 Redis 存储、频控与短信通道，按 bind_phone 场景隔离
+func NewBindingApplicationService(
+	uow uow.UnitOfWork,
+	idp authentication.IdentityProvider,
+	otpVerifier authentication.OTPVerifier,
+	phoneOTP *loginprep.PhoneOTPDeps,
+	wechatAppQuerier idpPort.Repository,
+	wecomAppQuerier wecomPort.Repository,
+	secretVault idpPort.SecretVault,
+) BindingApplicationService {
+	return &bindingApplicationService{
+		uow:         uow,
+		issuer:      credDomain.NewIssuer(nil),
+		lifecycle:   credDomain.NewLifecycle(),
+		idp:         idp,
+		otpVerifier: otpVerifier,
+		phoneOTP:    phoneOTP,
+		apps:        loginprep.NewIDPAppResolver(wechatAppQuerier, wecomAppQuerier, secretVault),
+	}
+}
+
+// ListBindings 列出账户当前的登录方式
+func (s *bindingApplicationService) ListBindings(ctx context.Context, accountID meta.ID) ([]*BindingResult, error) {
+	if accountID.IsZero() {
+		return nil, perrors.WithCode(code.ErrUnauthenticated, "account is required")
+	}
+	var results []*BindingResult
+	err := s.uow.WithinTx(ctx, func(tx uow.TxRepositories) error {
+		creds, err := tx.Credentials.ListByAccountID(ctx, accountID)
+		if err != nil {
+			return perrors.WrapC(err, code.ErrDatabase, "failed to list credentials")
+		}
+		results = make([]*BindingResult, 0, len(creds))
+		for _, cred := range creds {
+			results = append(results, toBindingResult(cred))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// BindWechat 绑定微信小程序身份
+// 标识与注册、登录一致：有 UnionID 时使用 UnionID，否则使用 OpenID
+func (s *bindingApplicationService) BindWechat(ctx context.Context, req BindWechatRequest) (*BindingResult, error) {
+	appID := strings.TrimSpace(req.AppID)
+	jsCode := strings.TrimSpace(req.JsCode)
+	if appID == "" || jsCode == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "app_id and js_code are required")
+	}
+	appSecret, err := s.apps.WechatAppSecret(ctx, appID, idpPort.MiniProgram)
+	if err != nil {
+		return nil, err
+	}
+	openID, unionID, err := s.idp.ExchangeWxMinipCode(ctx, appID, appSecret, jsCode)
+	if err != nil {
+		return nil, perrors.WithCode(code.ErrIDPExchangeFailed, "failed to call wechat code2session: %v", err)
+	}
+	identifier := openID
+	if unionID != "" {
+		identifier = unionID
+	}
+	if identifier == "" {
+		return nil, perrors.WithCode(code.ErrIDPExchangeFailed, "wechat returned empty openid")
+	}
+
+	return s.bind(ctx, req.AccountID, credDomain.CredOAuthWxMinip, identifier, func() (*credDomain.Credential, error) {
+		return s.issuer.IssueWechatMinip(ctx, credDomain.IssueOAuthRequest{
+			AccountID:     req.AccountID,
+			IDPIdentifier: identifier,
+			AppID:         appID,
+		})
+	})
+}
+
+// BindWecom 绑定企业微信身份
+// 与登录一致：AgentID 与 CorpSecret 从企业微信应用登记表解析；标识优先 UserID，回退 OpenUserID
+func (s *bindingApplicationService) BindWecom(ctx context.Context, req BindWecomRequest) (*BindingResult, error) {
+	corpID := strings.TrimSpace(req.CorpID)
+	authCode := strings.TrimSpace(req.Code)
+	if corpID == "" || authCode == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "corp_id and code are required")
+	}
+	app, corpSecret, err := s.apps.WecomApp(ctx, corpID, strings.TrimSpace(req.AgentID))
+	if err != nil {
+		return nil, err
+	}
+	openUserID, userID, err := s.idp.ExchangeWecomCode(ctx, corpID, app.AgentID, corpSecret, authCode)
+	if err != nil {
+		return nil, perrors.WithCode(code.ErrIDPExchangeFailed, "failed to exchange wecom code: %v", err)
+	}
+	identifier := userID
+	if identifier == "" {
+		identifier = openUserID
+	}
+	if identifier == "" {
+		return nil, perrors.WithCode(code.ErrIDPExchangeFailed, "wecom returned empty userid")
+	}
+
+	return s.bind(ctx, req.AccountID, credDomain.CredOAuthWecom, identifier, func() (*credDomain.Credential, error) {
+		return s.issuer.IssueWecom(ctx, credDomain.IssueOAuthRequest{
+			AccountID:     req.AccountID,
+			IDPIdentifier: identifier,
+			AppID:         corpID,
+		})
+	})
+}
+
+// SendPhoneBindCode 发送绑定手机号验证码
+// 不校验手机号是否已被绑定，避免借此枚举注册用户；冲突在绑定时返回
+func (s *bindingApplicationService) SendPhoneBindCode(ctx context.Context, rawPhone string) error {
+	phone, err := meta.NewPhone(rawPhone)
+	if err != nil {
+		return perrors.WithCode(code.ErrInvalidArgument, "invalid phone: %v", err)
+	}
+	return s.phoneOTP.Deliver(ctx, phone.String(), bindPhoneScene)
+}
+
+// BindPhone 凭短信验证码绑定手机号
+func (s *bindingApplicationService) BindPhone(ctx context.Context, req BindPhoneRequest) (*BindingResult, error) {
+	if req.Code == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "verification code is required")
+	}
+	phone, err := meta.NewPhone(req.Phone)
+	if err != nil {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "invalid phone: %v", err)
+	}
+	if s.otpVerifier == nil {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "phone OTP is not configured")
+	}
+	if !s.otpVerifier.VerifyAndConsume(ctx, phone.String(), bindPhoneScene, req.Code) {
+		return nil, perrors.WithCode(code.ErrOTPInvalid, "verification code is invalid or expired")
+	}
+
+	return s.bind(ctx, req.AccountID, credDomain.CredPhoneOTP, phone.String(), func() (*credDomain.Credential, error) {
+		return s.issuer.IssuePhoneOTP(ctx, credDomain.IssuePhoneOTPRequest{
+			AccountID: req.AccountID,
+			Phone:     phone,
+		})
+	})
+}
+
+// bind 在事务内完成冲突检测并持久化凭据
+//   - 身份已属于当前账户：幂等返回
+//   - 身份已属于其他账户：ErrIdentityBoundElsewhere
+//   - 当前账户已绑定同类型的其他身份：ErrCredentialExists，需先解绑
+func (s *bindingApplicationService) bind(
+	ctx context.Context,
+	accountID meta.ID,
+	credType credDomain.CredentialType,
+	identifier string,
+	issue func() (*credDomain.Credential, error),
+) (*BindingResult, error) {
+	l := logger.L(ctx)
+	if accountID.IsZero() {
+		return nil, perrors.WithCode(code.ErrUnauthenticated, "account is required")
+	}
+
+	var result *BindingResult
+	err := s.uow.WithinTx(ctx, func(tx uow.TxRepositories) error {
+		account, err := tx.Accounts.GetByID(ctx, accountID)
+		if err != nil {
+			return perrors.WrapC(err, code.ErrDatabase, "failed to find account")
+		}
+		if account == nil {
+			return perrors.WithCode(code.ErrNotFoundAccount, "account not found")
+		}
+
+		existing, err := tx.Credentials.GetByIDPIdentifier(ctx, identifier, credType)
+		if err != nil {
+			return perrors.WrapC(err, code.ErrDatabase, "failed to find identity binding")
+		}
+		if existing != nil {
+			if existing.AccountID != accountID {
+				return perrors.WithCode(code.ErrIdentityBoundElsewhere, "%s identity is already bound to another account", credType)
+			}
+			result = toBindingResult(existing)
+			return nil
+		}
+
+		current, err := tx.Credentials.GetByAccountIDAndType(ctx, accountID, credType)
+		if err != nil {
+			return perrors.WrapC(err, code.ErrDatabase, "failed to find account credential")
+		}
+		if current != nil {
+			return perrors.WithCode(code.ErrCredentialExists, "account already has a %s binding, unbind it first", credType)
+		}
+
+		cred, err := issue()
+		if err != nil {
+			return err
+		}
+		if err := tx.Credentials.Create(ctx, cred); err != nil {
+			return err
+		}
+		result = toBindingResult(cred)
+		return nil
+	})
+	if err != nil {
+		l.Warnw("绑定登录方式失败",
+			"action", logger.ActionCreate,
+			"resource", "credential",
+			"account_id", accountID.String(),
+			"credential_type", string(credType),
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return nil, err
+	}
+
+	l.Infow("登录方式已绑定",
+		"action", logger.ActionCreate,
+		"resource", "credential",
+		"account_id", accountID.String(),
+		"credential_id", result.CredentialID.String(),
+		"credential_type", string(credType),
+		"result", logger.ResultSuccess,
+	)
+	return result, nil
+}
+
+// Unbind 解除外部身份绑定
+// 仅允许解绑外部身份（密码请走修改/重置密码）；解绑后账户须仍有一种启用的登录方式
+func (s *bindingApplicationService) Unbind(ctx context.Context, accountID, credentialID meta.ID) error {
+	l := logger.L(ctx)
+	if accountID.IsZero() {
+		return perrors.WithCode(code.ErrUnauthenticated, "account is required")
+	}
+
+	var credType credDomain.CredentialType
+	err := s.uow.WithinTx(ctx, func(tx uow.TxRepositories) error {
+		creds, err := tx.Credentials.ListByAccountID(ctx, accountID)
+		if err != nil {
+			return perrors.WrapC(err, code.ErrDatabase, "failed to list credentials")
+		}
+
+		var target *credDomain.Credential
+		remaining := 0
+		for _, cred := range creds {
+			if cred.ID == credentialID {
+				target = cred
+				continue
+			}
+			if cred.IsEnabled() {
+				remaining++
+			}
+		}
+		// 凭据不属于当前账户时与不存在同样处理
+		if target == nil {
+			return perrors.WithCode(code.ErrCredentialNotFound, "credential not found")
+		}
+		credType = target.Type()
+		if credType == credDomain.CredPassword {
+			return perrors.WithCode(code.ErrInvalidArgument, "password credential cannot be unbound")
+		}
+		if remaining == 0 {
+			return perrors.WithCode(code.ErrLastLoginMethod, "account must keep at least one login method")
+		}
+		return tx.Credentials.Delete(ctx, target.ID)
+	})
+	if err != nil {
+		l.Warnw("解绑登录方式失败",
+			"action", logger.ActionDelete,
+			"resource", "credential",
+			"account_id", accountID.String(),
+			"credential_id", credentialID.String(),
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return err
+	}
+
+	l.Infow("登录方式已解绑",
+		"action", logger.ActionDelete,
+		"resource", "credential",
+		"account_id", accountID.String(),
+		"credential_id", credentialID.String(),
+		"credential_type", string(credType),
+		"result", logger.ResultSuccess,
+	)
+	return nil
+}
+
+// RevokeWechatAuthorization 禁用微信侧已撤回授权的凭据
+//
+// 凭据标识在有 UnionID 时存的是 UnionID，撤回事件只携带 OpenID，
+// 因此先按 OpenID 直接匹配，再通过账户的 external_id（openid@appid）定位同应用下的微信凭据。
+func (s *bindingApplicationService) RevokeWechatAuthorization(ctx context.Context, appID, openID string) (int, error) {
+	l := logger.L(ctx)
+	appID = strings.TrimSpace(appID)
+	openID = strings.TrimSpace(openID)
+	if appID == "" || openID == "" {
+		return 0, perrors.WithCode(code.ErrInvalidArgument, "app_id and openid are required")
+	}
+
+	revoked := 0
+	err := s.uow.WithinTx(ctx, func(tx uow.TxRepositories) error {
+		targets := make(map[meta.ID]*credDomain.Credential)
+		for _, credType := range wechatCredentialTypes {
+			cred, err := tx.Credentials.GetByIDPIdentifier(ctx, openID, credType)
+			if err != nil {
+				return perrors.WrapC(err, code.ErrDatabase, "failed to find wechat credential")
+			}
+			if cred != nil && credentialBelongsToApp(cred, appID) {
+				targets[cred.ID] = cred
+			}
+		}
+
+		account, err := tx.Accounts.GetByExternalIDAppId(ctx, accountDomain.ExternalID(openID+"@"+appID), accountDomain.AppId(appID))
+		if err != nil {
+			return perrors.WrapC(err, code.ErrDatabase, "failed to find wechat account")
+		}
+		if account != nil {
+			creds, err := tx.Credentials.ListByAccountID(ctx, account.ID)
+			if err != nil {
+				return perrors.WrapC(err, code.ErrDatabase, "failed to list credentials")
+			}
+			for _, cred := range creds {
+				if isWechatCredential(cred) && credentialBelongsToApp(cred, appID) {
+					targets[cred.ID] = cred
+				}
+			}
+		}
+
+		for _, cred := range targets {
+			if !cred.IsEnabled() {
+				continue
+			}
+			s.lifecycle.Disable(cred)
+			if err := tx.Credentials.UpdateStatus(ctx, cred.ID, cred.Status); err != nil {
+				return perrors.WrapC(err, code.ErrDatabase, "failed to disable credential")
+			}
+			revoked++
+		}
+		return nil
+	})
+	if err != nil {
+		l.Warnw("禁用撤回授权的微信凭据失败",
+			"action", logger.ActionUpdate,
+			"resource", "credential",
+			"app_id", appID,
+			"openid", openID,
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return 0, err
+	}
+	return revoked, nil
+}
+
+// wechatCredentialTypes 微信侧（小程序/公众号）的凭据类型
+var wechatCredentialTypes = []credDomain.CredentialType{credDomain.CredOAuthWxMinip, credDomain.CredOAuthWxMP}
+
+func isWechatCredential(cred *credDomain.Credential) bool {
+	t := cred.Type()
+	return t == credDomain.CredOAuthWxMinip || t == credDomain.CredOAuthWxMP
+}
+
+// credentialBelongsToApp 历史凭据可能未记录 AppID，视为属于当前应用
+func credentialBelongsToApp(cred *credDomain.Credential, appID string) bool {
+	return cred.AppID == nil || *cred.AppID == "" || *cred.AppID == appID
+}
+
+// toBindingResult 转换为登录方式 DTO
+func toBindingResult(cred *credDomain.Credential) *BindingResult {
+	result := &BindingResult{
+		CredentialID: cred.ID,
+		Type:         cred.Type(),
+		Identifier:   cred.IDPIdentifier,
+		Enabled:      cred.IsEnabled(),
+	}
+	if cred.IDP != nil {
+		result.IDP = *cred.IDP
+	}
+	if cred.AppID != nil {
+		result.AppID = *cred.AppID
+	}
+	return result
+}
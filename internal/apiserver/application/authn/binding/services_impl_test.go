@@ -0,0 +1,323 @@
+package binding
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/loginprep"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/uow"
+	accountDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	credDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/credential"
+	wechatDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+	wecomDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+type credentialRepoStub struct {
+	credDomain.Repository
+	byID   map[uint64]*credDomain.Credential
+	nextID uint64
+}
+
+func (s *credentialRepoStub) Create(_ context.Context, c *credDomain.Credential) error {
+	s.nextID++
+	c.ID = meta.FromUint64(s.nextID)
+	s.byID[s.nextID] = c
+	return nil
+}
+
+func (s *credentialRepoStub) GetByAccountIDAndType(_ context.Context, accountID meta.ID, t credDomain.CredentialType) (*credDomain.Credential, error) {
+	for _, c := range s.byID {
+		if c.AccountID == accountID && c.Type() == t {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *credentialRepoStub) GetByIDPIdentifier(_ context.Context, identifier string, t credDomain.CredentialType) (*credDomain.Credential, error) {
+	for _, c := range s.byID {
+		if c.IDPIdentifier == identifier && c.Type() == t {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *credentialRepoStub) ListByAccountID(_ context.Context, accountID meta.ID) ([]*credDomain.Credential, error) {
+	var out []*credDomain.Credential
+	for _, c := range s.byID {
+		if c.AccountID == accountID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (s *credentialRepoStub) UpdateStatus(_ context.Context, id meta.ID, status credDomain.CredentialStatus) error {
+	if c, ok := s.byID[id.Uint64()]; ok {
+		c.Status = status
+	}
+	return nil
+}
+
+func (s *credentialRepoStub) Delete(_ context.Context, id meta.ID) error {
+	delete(s.byID, id.Uint64())
+	return nil
+}
+
+type accountRepoStub struct {
+	accountDomain.Repository
+	byExternalID map[string]*accountDomain.Account
+}
+
+func (s accountRepoStub) GetByExternalIDAppId(_ context.Context, externalID accountDomain.ExternalID, appID accountDomain.AppId) (*accountDomain.Account, error) {
+	acc := s.byExternalID[string(externalID)]
+	if acc == nil || acc.AppID != appID {
+		return nil, nil
+	}
+	return acc, nil
+}
+
+func (accountRepoStub) GetByID(_ context.Context, id meta.ID) (*accountDomain.Account, error) {
+	return &accountDomain.Account{ID: id}, nil
+}
+
+type uowStub struct {
+	creds    *credentialRepoStub
+	accounts accountRepoStub
+}
+
+func (u uowStub) WithinTx(_ context.Context, fn func(tx uow.TxRepositories) error) error {
+	return fn(uow.TxRepositories{Accounts: u.accounts, Credentials: u.creds})
+}
+
+type otpStub struct{ code string }
+
+func (o otpStub) VerifyAndConsume(_ context.Context, _, scene, c string) bool {
+	return scene == bindPhoneScene && c == o.code
+}
+
+type idpStub struct {
+	wecomAgentID    string
+	wecomCorpSecret string
+}
+
+func (*idpStub) ExchangeWxMinipCode(context.Context, string, string, string) (string, string, error) {
+	return "openid", "unionid", nil
+}
+
+func (*idpStub) ExchangeWxMPCode(context.Context, string, string, string) (authentication.WxMPIdentity, error) {
+	return authentication.WxMPIdentity{OpenID: "mp-openid", UnionID: "unionid"}, nil
+}
+
+func (i *idpStub) ExchangeWecomCode(_ context.Context, _, agentID, corpSecret, _ string) (string, string, error) {
+	i.wecomAgentID, i.wecomCorpSecret = agentID, corpSecret
+	return "", "wecom-user", nil
+}
+
+// wecomAppRepoStub 企业微信应用登记
+type wecomAppRepoStub struct {
+	wecomDomain.Repository
+	apps []*wecomDomain.WecomApp
+}
+
+func (r wecomAppRepoStub) GetByCorpAgent(_ context.Context, corpID, agentID string) (*wecomDomain.WecomApp, error) {
+	for _, app := range r.apps {
+		if app.CorpID == corpID && app.AgentID == agentID {
+			return app, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r wecomAppRepoStub) List(_ context.Context, filter wecomDomain.ListFilter) ([]*wecomDomain.WecomApp, error) {
+	var out []*wecomDomain.WecomApp
+	for _, app := range r.apps {
+		if (filter.CorpID == nil || app.CorpID == *filter.CorpID) && (filter.Status == nil || app.Status == *filter.Status) {
+			out = append(out, app)
+		}
+	}
+	return out, nil
+}
+
+// vaultStub 以 "enc:" 前缀模拟加密
+type vaultStub struct {
+	wechatDomain.SecretVault
+}
+
+func (vaultStub) Decrypt(_ context.Context, cipher []byte) ([]byte, error) {
+	return []byte(strings.TrimPrefix(string(cipher), "enc:")), nil
+}
+
+func wecomApp(corpID, agentID, secret string) *wecomDomain.WecomApp {
+	return &wecomDomain.WecomApp{
+		CorpID:  corpID,
+		AgentID: agentID,
+		Status:  wecomDomain.StatusEnabled,
+		Cred:    &wecomDomain.Credentials{Auth: &wecomDomain.CorpSecret{SecretCipher: []byte("enc:" + secret)}},
+	}
+}
+
+func newService(creds *credentialRepoStub) *bindingApplicationService {
+	return newServiceWithIDP(creds, &idpStub{})
+}
+
+func newServiceWithIDP(creds *credentialRepoStub, idp *idpStub) *bindingApplicationService {
+	wecomApps := wecomAppRepoStub{apps: []*wecomDomain.WecomApp{wecomApp("corp", "1000002", "corp-secret")}}
+	return NewBindingApplicationService(uowStub{creds: creds}, idp, otpStub{code: "123456"}, nil, nil, wecomApps, vaultStub{}).(*bindingApplicationService)
+}
+
+func passwordCred(accountID uint64) *credDomain.Credential {
+	algo := "argon2id"
+	return &credDomain.Credential{
+		ID:        meta.FromUint64(1),
+		AccountID: meta.FromUint64(accountID),
+		Material:  []byte("hash"),
+		Algo:      &algo,
+		Status:    credDomain.CredStatusEnabled,
+	}
+}
+
+func TestBindPhone(t *testing.T) {
+	ctx := context.Background()
+	creds := &credentialRepoStub{byID: map[uint64]*credDomain.Credential{1: passwordCred(100)}, nextID: 1}
+	svc := newService(creds)
+
+	_, err := svc.BindPhone(ctx, BindPhoneRequest{AccountID: meta.FromUint64(100), Phone: "13800138000", Code: "000000"})
+	assert.True(t, perrors.IsCode(err, code.ErrOTPInvalid))
+
+	result, err := svc.BindPhone(ctx, BindPhoneRequest{AccountID: meta.FromUint64(100), Phone: "13800138000", Code: "123456"})
+	require.NoError(t, err)
+	assert.Equal(t, credDomain.CredPhoneOTP, result.Type)
+	assert.Equal(t, "+8613800138000", result.Identifier)
+
+	// 同一账户重复绑定幂等
+	again, err := svc.BindPhone(ctx, BindPhoneRequest{AccountID: meta.FromUint64(100), Phone: "+8613800138000", Code: "123456"})
+	require.NoError(t, err)
+	assert.Equal(t, result.CredentialID, again.CredentialID)
+
+	// 其他账户绑定同一手机号冲突
+	_, err = svc.BindPhone(ctx, BindPhoneRequest{AccountID: meta.FromUint64(200), Phone: "13800138000", Code: "123456"})
+	assert.True(t, perrors.IsCode(err, code.ErrIdentityBoundElsewhere))
+
+	// 已有手机号时绑定另一个号码需先解绑
+	_, err = svc.BindPhone(ctx, BindPhoneRequest{AccountID: meta.FromUint64(100), Phone: "13900139000", Code: "123456"})
+	assert.True(t, perrors.IsCode(err, code.ErrCredentialExists))
+}
+
+func TestBindOAuthIdentities(t *testing.T) {
+	ctx := context.Background()
+	creds := &credentialRepoStub{byID: map[uint64]*credDomain.Credential{}}
+	svc := newService(creds)
+
+	// 未配置微信应用查询时拒绝
+	_, err := svc.BindWechat(ctx, BindWechatRequest{AccountID: meta.FromUint64(100), AppID: "wx1", JsCode: "code"})
+	assert.True(t, perrors.IsCode(err, code.ErrInvalidArgument))
+
+	result, err := svc.BindWecom(ctx, BindWecomRequest{AccountID: meta.FromUint64(100), CorpID: "corp", Code: "c"})
+	require.NoError(t, err)
+	assert.Equal(t, credDomain.CredOAuthWecom, result.Type)
+	assert.Equal(t, "wecom", result.IDP)
+	assert.Equal(t, "wecom-user", result.Identifier)
+	assert.Equal(t, "corp", result.AppID)
+
+	_, err = svc.BindWecom(ctx, BindWecomRequest{AccountID: meta.FromUint64(200), CorpID: "corp", Code: "c"})
+	assert.True(t, perrors.IsCode(err, code.ErrIdentityBoundElsewhere))
+}
+
+func TestBindWecomResolvesRegisteredApp(t *testing.T) {
+	ctx := context.Background()
+	creds := &credentialRepoStub{byID: map[uint64]*credDomain.Credential{}}
+	idp := &idpStub{}
+	svc := newServiceWithIDP(creds, idp)
+
+	_, err := svc.BindWecom(ctx, BindWecomRequest{AccountID: meta.FromUint64(100), CorpID: "unknown", Code: "c"})
+	assert.True(t, perrors.IsCode(err, code.ErrWecomAppNotFound))
+	assert.Empty(t, idp.wecomAgentID)
+
+	_, err = svc.BindWecom(ctx, BindWecomRequest{AccountID: meta.FromUint64(100), CorpID: "corp", Code: "c"})
+	require.NoError(t, err)
+	assert.Equal(t, "1000002", idp.wecomAgentID)
+	assert.Equal(t, "corp-secret", idp.wecomCorpSecret)
+
+	// 企业下登记多个应用时须指定 AgentID
+	svc.apps = loginprep.NewIDPAppResolver(nil, wecomAppRepoStub{apps: []*wecomDomain.WecomApp{
+		wecomApp("corp", "1000002", "corp-secret"),
+		wecomApp("corp", "1000003", "other-secret"),
+	}}, vaultStub{})
+	_, err = svc.BindWecom(ctx, BindWecomRequest{AccountID: meta.FromUint64(200), CorpID: "corp", Code: "c"})
+	assert.True(t, perrors.IsCode(err, code.ErrWecomAppAmbiguous))
+	_, err = svc.BindWecom(ctx, BindWecomRequest{AccountID: meta.FromUint64(200), CorpID: "corp", AgentID: "1000003", Code: "c"})
+	assert.True(t, perrors.IsCode(err, code.ErrIdentityBoundElsewhere))
+	assert.Equal(t, "1000003", idp.wecomAgentID)
+	assert.Equal(t, "other-secret", idp.wecomCorpSecret)
+}
+
+func TestUnbindKeepsLastLoginMethod(t *testing.T) {
+	ctx := context.Background()
+	idp := "phone"
+	creds := &credentialRepoStub{byID: map[uint64]*credDomain.Credential{
+		1: passwordCred(100),
+		2: {ID: meta.FromUint64(2), AccountID: meta.FromUint64(100), IDP: &idp, IDPIdentifier: "+8613800138000", Status: credDomain.CredStatusEnabled},
+		3: {ID: meta.FromUint64(3), AccountID: meta.FromUint64(300), IDP: &idp, IDPIdentifier: "+8613900139000", Status: credDomain.CredStatusEnabled},
+	}}
+	svc := newService(creds)
+
+	assert.True(t, perrors.IsCode(svc.Unbind(ctx, meta.FromUint64(100), meta.FromUint64(1)), code.ErrInvalidArgument))
+	// 不属于当前账户的凭据按不存在处理
+	assert.True(t, perrors.IsCode(svc.Unbind(ctx, meta.FromUint64(100), meta.FromUint64(3)), code.ErrCredentialNotFound))
+	assert.True(t, perrors.IsCode(svc.Unbind(ctx, meta.FromUint64(300), meta.FromUint64(3)), code.ErrLastLoginMethod))
+
+	require.NoError(t, svc.Unbind(ctx, meta.FromUint64(100), meta.FromUint64(2)))
+	list, err := svc.ListBindings(ctx, meta.FromUint64(100))
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, credDomain.CredPassword, list[0].Type)
+}
+
+func TestRevokeWechatAuthorization(t *testing.T) {
+	ctx := context.Background()
+	wx, mp := "wechat", "wechat_mp"
+	appMP, appOther := "wx-mp", "wx-other"
+	creds := &credentialRepoStub{byID: map[uint64]*credDomain.Credential{
+		1: passwordCred(100),
+		// 有 UnionID 时凭据标识为 UnionID，需要通过账户 external_id 定位
+		2: {ID: meta.FromUint64(2), AccountID: meta.FromUint64(100), IDP: &mp, IDPIdentifier: "unionid", AppID: &appMP, Status: credDomain.CredStatusEnabled},
+		// 同一账户下其他应用的微信凭据不受影响
+		3: {ID: meta.FromUint64(3), AccountID: meta.FromUint64(100), IDP: &wx, IDPIdentifier: "unionid", AppID: &appOther, Status: credDomain.CredStatusEnabled},
+		// 无 UnionID 的账户直接以 OpenID 为标识
+		4: {ID: meta.FromUint64(4), AccountID: meta.FromUint64(200), IDP: &mp, IDPIdentifier: "openid-b", AppID: &appMP, Status: credDomain.CredStatusEnabled},
+	}}
+	accounts := accountRepoStub{byExternalID: map[string]*accountDomain.Account{
+		"openid-a@wx-mp": {ID: meta.FromUint64(100), ExternalID: "openid-a@wx-mp", AppID: accountDomain.AppId(appMP)},
+	}}
+	svc := NewBindingApplicationService(uowStub{creds: creds, accounts: accounts}, &idpStub{}, nil, nil, nil, nil, nil)
+
+	n, err := svc.RevokeWechatAuthorization(ctx, appMP, "openid-a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.False(t, creds.byID[2].IsEnabled())
+	assert.True(t, creds.byID[1].IsEnabled())
+	assert.True(t, creds.byID[3].IsEnabled())
+
+	n, err = svc.RevokeWechatAuthorization(ctx, appMP, "openid-b")
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.False(t, creds.byID[4].IsEnabled())
+
+	// 重复推送幂等
+	n, err = svc.RevokeWechatAuthorization(ctx, appMP, "openid-b")
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	_, err = svc.RevokeWechatAuthorization(ctx, "", "openid-b")
+	assert.True(t, perrors.IsCode(err, code.ErrInvalidArgument))
+}
@@ -30,7 +30,10 @@ const (
 	AuthTypePassword AuthType = "password"  // 密码认证
 	AuthTypePhoneOTP AuthType = "phone_otp" // 手机号OTP认证
 	AuthTypeWechat   AuthType = "wechat"    // 微信小程序认证
+	AuthTypeWechatMP AuthType = "wechat_mp" // 微信公众号网页授权认证
 	AuthTypeWecom    AuthType = "wecom"     // 企业微信认证
+	AuthTypeOIDC     AuthType = "oidc"      // 上游 OIDC 联合登录
+	AuthTypeSAML     AuthType = "saml"      // SAML 单点登录
 	AuthTypeJWTToken AuthType = "jwt_token" // JWT令牌认证
 )
 
@@ -39,6 +42,11 @@ type LoginRequest struct {
 	// ========== 认证类型（必须）==========
 	AuthType AuthType // 认证类型
 
+	// ========== 请求上下文（可选，用于失败节流）==========
+	RemoteIP   string // 客户端 IP
+	UserAgent  string // 客户端 UA
+	DeviceName string // 客户端上报的设备名（记入会话）
+
 	// ========== 密码认证字段 ==========
 	TenantID meta.ID // 租户ID（可选）
 	Username *string // 用户名（当 AuthType=password 时必须）
@@ -52,14 +60,36 @@ type LoginRequest struct {
 	WechatAppID  *string // 微信AppID（当 AuthType=wechat 时必须）
 	WechatJSCode *string // wx.login返回的code（当 AuthType=wechat 时必须）
 
+	// ========== 微信公众号网页授权认证字段 ==========
+	WechatMPAppID *string // 公众号AppID（当 AuthType=wechat_mp 时必须）
+	WechatMPCode  *string // 网页授权回调的code，snsapi_base / snsapi_userinfo 均可（当 AuthType=wechat_mp 时必须）
+
 	// ========== 企业微信认证字段 ==========
-	WecomCorpID *string // 企业CorpID（当 AuthType=wecom 时必须）
-	WecomCode   *string // 企业微信授权code（当 AuthType=wecom 时必须）
+	WecomCorpID  *string // 企业CorpID（当 AuthType=wecom 时必须）
+	WecomAgentID *string // 应用AgentID（可选；企业下登记了多个应用时必须）
+	WecomCode    *string // 企业微信授权code（当 AuthType=wecom 时必须）
+
+	// ========== 上游 OIDC 联合登录字段 ==========
+	OIDCProvider     *string // 提供商 slug（当 AuthType=oidc 时必须）
+	OIDCCode         *string // 上游授权回调的 code（当 AuthType=oidc 时必须）
+	OIDCRedirectURI  *string // 发起授权时使用的回调地址（当 AuthType=oidc 时必须）
+	OIDCState        *string // 构造授权地址时服务端下发的 state（当 AuthType=oidc 时必须）
+	OIDCCodeVerifier *string // PKCE code_verifier（可选）
+
+	// ========== SAML 单点登录字段 ==========
+	SAMLIdP      *string // IdP 登记 slug（当 AuthType=saml 时必须，取自 ACS 路径）
+	SAMLResponse *string // HTTP-POST 绑定回传的 Base64 SAMLResponse（当 AuthType=saml 时必须）
 
 	// ========== JWT令牌认证字段 ==========
 	JWTToken *string // JWT访问令牌（当 AuthType=jwt_token 时必须）
 }
 
+// ExternalRoleSyncer 外部角色同步端口（Driven Port，由授权模块实现）
+// 上游 OIDC 登录成功后按提供商的角色映射同步用户角色
+type ExternalRoleSyncer interface {
+	SyncExternalRoles(ctx context.Context, userID, tenantID meta.ID, source string, roleNames []string) error
+}
+
 // LoginResult 登录结果
 type LoginResult struct {
 	// 认证主体
@@ -2,23 +2,32 @@ package login
 
 import (
 	"context"
+	"time"
 
 	perrors "github.com/FangcunMount/component-base/pkg/errors"
 	"github.com/FangcunMount/component-base/pkg/logger"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/loginprep"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	samlPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/saml"
 	tokenDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/token"
+	oidcPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/oidcprovider"
 	idpPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+	wecomPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
 	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
 	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
 	"github.com/FangcunMount/iam-contracts/pkg/tenant"
 )
 
 type loginApplicationService struct {
-	tokenIssuer      tokenDomain.Issuer
-	tokenRefresher   tokenDomain.Refresher
-	authenticater    *authentication.Authenticater
-	wechatAppQuerier idpPort.Repository
-	secretVault      idpPort.SecretVault
+	tokenIssuer    tokenDomain.Issuer
+	tokenRefresher tokenDomain.Refresher
+	authenticater  *authentication.Authenticater
+	apps           *loginprep.IDPAppResolver
+	oidcQuerier    oidcPort.Repository
+	oidcAuthorizer oidcPort.AuthorizationStore
+	samlQuerier    samlPort.Repository
+	secretVault    idpPort.SecretVault
+	roleSyncer     ExternalRoleSyncer
 }
 
 var _ LoginApplicationService = (*loginApplicationService)(nil)
@@ -28,14 +37,23 @@ func NewLoginApplicationService(
 	tokenRefresher tokenDomain.Refresher,
 	authenticater *authentication.Authenticater,
 	wechatAppQuerier idpPort.Repository,
+	wecomAppQuerier wecomPort.Repository,
+	oidcQuerier oidcPort.Repository,
+	oidcAuthorizer oidcPort.AuthorizationStore,
+	samlQuerier samlPort.Repository,
 	secretVault idpPort.SecretVault,
+	roleSyncer ExternalRoleSyncer,
 ) LoginApplicationService {
 	return &loginApplicationService{
-		tokenIssuer:      tokenIssuer,
-		tokenRefresher:   tokenRefresher,
-		authenticater:    authenticater,
-		wechatAppQuerier: wechatAppQuerier,
-		secretVault:      secretVault,
+		tokenIssuer:    tokenIssuer,
+		tokenRefresher: tokenRefresher,
+		authenticater:  authenticater,
+		apps:           loginprep.NewIDPAppResolver(wechatAppQuerier, wecomAppQuerier, secretVault),
+		oidcQuerier:    oidcQuerier,
+		oidcAuthorizer: oidcAuthorizer,
+		samlQuerier:    samlQuerier,
+		secretVault:    secretVault,
+		roleSyncer:     roleSyncer,
 	}
 }
 
@@ -75,11 +93,21 @@ func (s *loginApplicationService) Login(ctx context.Context, req LoginRequest) (
 			"credential_id", decision.CredentialID.String(),
 			"result", logger.ResultFailed,
 		)
+		if decision.CaptchaRequired && decision.ErrCode != authentication.ErrThrottled {
+			return nil, perrors.WithCode(code.ErrCaptchaRequired, "too many failed attempts, captcha verification required")
+		}
+		if decision.ErrCode == authentication.ErrThrottled {
+			return nil, perrors.WithCode(code.ErrLoginThrottled, "too many failed attempts, retry after %s", decision.RetryAfter.Round(time.Second))
+		}
 		return nil, s.convertAuthError(decision.ErrCode)
 	}
 
 	ensurePrincipalTenantID(decision.Principal)
 
+	if scenario == authentication.AuthOIDC {
+		s.syncOIDCRoles(ctx, *req.OIDCProvider, decision)
+	}
+
 	l.Debugw("认证成功，开始颁发令牌",
 		"action", logger.ActionLogin,
 		"scenario", string(scenario),
@@ -103,6 +131,9 @@ func (s *loginApplicationService) Login(ctx context.Context, req LoginRequest) (
 			"error", err.Error(),
 			"result", logger.ResultFailed,
 		)
+		if perrors.IsCode(err, code.ErrSessionLimitExceeded) {
+			return nil, err
+		}
 		return nil, perrors.WithCode(code.ErrInvalidArgument, "failed to issue token: %v", err)
 	}
 
@@ -200,6 +231,12 @@ func (s *loginApplicationService) convertAuthError(errCode authentication.ErrCod
 		return perrors.WithCode(code.ErrIDPExchangeFailed, "failed to exchange with identity provider")
 	case authentication.ErrStateMismatch:
 		return perrors.WithCode(code.ErrStateMismatch, "state parameter mismatch")
+	case authentication.ErrPasswordExpired:
+		return perrors.WithCode(code.ErrPasswordExpired, "password expired, change it before login")
+	case authentication.ErrAssertionInvalid:
+		return perrors.WithCode(code.ErrSAMLAssertionInvalid, "saml assertion is invalid")
+	case authentication.ErrAssertionReplayed:
+		return perrors.WithCode(code.ErrSAMLAssertionReplayed, "saml assertion has already been used")
 	default:
 		return perrors.WithCode(code.ErrAuthenticationFailed, "authentication failed")
 	}
@@ -210,7 +247,10 @@ func (s *loginApplicationService) prepareAuthentication(ctx context.Context, req
 
 	// 构建统一的 AuthInput，根据请求中有哪些字段就填充哪些字段
 	input := authentication.AuthInput{
-		TenantID: req.TenantID,
+		TenantID:   req.TenantID,
+		RemoteIP:   req.RemoteIP,
+		UserAgent:  req.UserAgent,
+		DeviceName: req.DeviceName,
 	}
 
 	// 根据存在的字段来推断认证场景
@@ -251,61 +291,41 @@ func (s *loginApplicationService) prepareAuthentication(ctx context.Context, req
 			"app_id", input.WxAppID,
 		)
 
-		// 查询微信应用配置获取 AppSecret
-		if s.wechatAppQuerier != nil && s.secretVault != nil {
-			wechatApp, err := s.wechatAppQuerier.GetByAppID(ctx, *req.WechatAppID)
-			if err != nil {
-				l.Errorw("查询微信应用配置失败",
-					"action", logger.ActionLogin,
-					"scenario", string(scenario),
-					"app_id", *req.WechatAppID,
-					"error", err.Error(),
-				)
-				return "", authentication.AuthInput{}, perrors.WithCode(code.ErrInvalidArgument, "failed to query wechat app: %v", err)
-			}
-			if wechatApp == nil {
-				l.Warnw("微信应用不存在",
-					"action", logger.ActionLogin,
-					"scenario", string(scenario),
-					"app_id", *req.WechatAppID,
-				)
-				return "", authentication.AuthInput{}, perrors.WithCode(code.ErrInvalidArgument, "wechat app not found: %s", *req.WechatAppID)
-			}
-			if !wechatApp.IsEnabled() {
-				l.Warnw("微信应用已禁用",
-					"action", logger.ActionLogin,
-					"scenario", string(scenario),
-					"app_id", *req.WechatAppID,
-				)
-				return "", authentication.AuthInput{}, perrors.WithCode(code.ErrInvalidArgument, "wechat app is disabled: %s", *req.WechatAppID)
-			}
-			if wechatApp.Cred == nil || wechatApp.Cred.Auth == nil {
-				l.Errorw("微信应用凭据缺失",
-					"action", logger.ActionLogin,
-					"scenario", string(scenario),
-					"app_id", *req.WechatAppID,
-				)
-				return "", authentication.AuthInput{}, perrors.WithCode(code.ErrInvalidArgument, "wechat app credentials not found")
-			}
-
-			appSecretPlain, err := s.secretVault.Decrypt(ctx, wechatApp.Cred.Auth.AppSecretCipher)
-			if err != nil {
-				l.Errorw("解密应用密钥失败",
-					"action", logger.ActionLogin,
-					"scenario", string(scenario),
-					"app_id", *req.WechatAppID,
-					"error", err.Error(),
-				)
-				return "", authentication.AuthInput{}, perrors.WithCode(code.ErrInvalidArgument, "failed to decrypt app secret: %v", err)
-			}
-			input.WxAppSecret = string(appSecretPlain)
-		} else {
-			l.Errorw("微信应用配置服务不可用",
+		appSecret, err := s.apps.WechatAppSecret(ctx, *req.WechatAppID, idpPort.MiniProgram)
+		if err != nil {
+			l.Warnw("解析微信应用失败",
+				"action", logger.ActionLogin,
+				"scenario", string(scenario),
+				"app_id", input.WxAppID,
+				"error", err.Error(),
+			)
+			return "", authentication.AuthInput{}, err
+		}
+		input.WxAppSecret = appSecret
+	}
+
+	// 微信公众号网页授权认证（H5 在微信内打开）
+	if req.WechatMPAppID != nil && req.WechatMPCode != nil {
+		scenario = authentication.AuthWxMP
+		input.WxMPAppID = *req.WechatMPAppID
+		input.WxMPCode = *req.WechatMPCode
+		l.Debugw("检测到微信公众号网页授权认证",
+			"action", logger.ActionLogin,
+			"scenario", string(scenario),
+			"app_id", input.WxMPAppID,
+		)
+
+		appSecret, err := s.apps.WechatAppSecret(ctx, *req.WechatMPAppID, idpPort.MP)
+		if err != nil {
+			l.Warnw("解析微信应用失败",
 				"action", logger.ActionLogin,
 				"scenario", string(scenario),
+				"app_id", input.WxMPAppID,
+				"error", err.Error(),
 			)
-			return "", authentication.AuthInput{}, perrors.WithCode(code.ErrInvalidArgument, "wechat app configuration service not available")
+			return "", authentication.AuthInput{}, err
 		}
+		input.WxMPAppSecret = appSecret
 	}
 
 	// 企业微信认证
@@ -318,7 +338,91 @@ func (s *loginApplicationService) prepareAuthentication(ctx context.Context, req
 			"scenario", string(scenario),
 			"corp_id", input.WecomCorpID,
 		)
-		// TODO: 查询企业微信应用配置获取 AgentID 和 CorpSecret
+
+		var agentID string
+		if req.WecomAgentID != nil {
+			agentID = *req.WecomAgentID
+		}
+		app, corpSecret, err := s.apps.WecomApp(ctx, input.WecomCorpID, agentID)
+		if err != nil {
+			l.Warnw("解析企业微信应用失败",
+				"action", logger.ActionLogin,
+				"scenario", string(scenario),
+				"corp_id", input.WecomCorpID,
+				"agent_id", agentID,
+				"error", err.Error(),
+			)
+			return "", authentication.AuthInput{}, err
+		}
+		input.WecomAgentID = app.AgentID
+		input.WecomCorpSecret = corpSecret
+	}
+
+	// 上游 OIDC 联合登录
+	if req.OIDCProvider != nil && req.OIDCCode != nil {
+		scenario = authentication.AuthOIDC
+		input.OIDCProvider = *req.OIDCProvider
+		input.OIDCCode = *req.OIDCCode
+		if req.OIDCRedirectURI != nil {
+			input.OIDCRedirectURI = *req.OIDCRedirectURI
+		}
+		if req.OIDCCodeVerifier != nil {
+			input.OIDCCodeVerifier = *req.OIDCCodeVerifier
+		}
+		l.Debugw("检测到上游 OIDC 联合登录",
+			"action", logger.ActionLogin,
+			"scenario", string(scenario),
+			"provider", input.OIDCProvider,
+		)
+
+		nonce, err := s.consumeOIDCAuthorization(ctx, req.OIDCState, input.OIDCProvider, input.OIDCRedirectURI)
+		if err != nil {
+			return "", authentication.AuthInput{}, err
+		}
+		input.OIDCNonce = nonce
+
+		provider, clientSecret, err := s.oidcProvider(ctx, scenario, input.OIDCProvider)
+		if err != nil {
+			return "", authentication.AuthInput{}, err
+		}
+		claims := provider.Claims.WithDefaults()
+		input.OIDCIssuer = provider.Issuer
+		input.OIDCClientID = provider.ClientID
+		input.OIDCClientSecret = clientSecret
+		input.OIDCClaims = authentication.OIDCClaimMapping{
+			Subject:  claims.Subject,
+			Username: claims.Username,
+			Email:    claims.Email,
+			Name:     claims.Name,
+			Phone:    claims.Phone,
+		}
+		input.OIDCAutoProvision = provider.AutoProvision
+		if input.TenantID.IsZero() {
+			input.TenantID = provider.TenantID
+		}
+	}
+
+	// SAML 单点登录
+	if req.SAMLIdP != nil && req.SAMLResponse != nil {
+		scenario = authentication.AuthSAML
+		input.SAMLIdP = *req.SAMLIdP
+		input.SAMLResponse = *req.SAMLResponse
+		l.Debugw("检测到 SAML 单点登录",
+			"action", logger.ActionLogin,
+			"scenario", string(scenario),
+			"idp", input.SAMLIdP,
+		)
+
+		idp, err := s.samlIdP(ctx, scenario, input.SAMLIdP)
+		if err != nil {
+			return "", authentication.AuthInput{}, err
+		}
+		input.SAMLIdPEntityID = idp.EntityID
+		input.SAMLCertificates = idp.Certificates
+		input.SAMLAttributes = idp.AttributeMapping
+		input.SAMLAutoProvision = idp.AutoProvision
+		// IdP 登记归属租户，不允许请求指定其它租户
+		input.TenantID = idp.TenantID
 	}
 
 	// JWT令牌认证
@@ -348,3 +452,136 @@ func (s *loginApplicationService) prepareAuthentication(ctx context.Context, req
 
 	return scenario, input, nil
 }
+
+// consumeOIDCAuthorization 一次性消费构造授权地址时登记的 state，
+// 校验其签发给同一提供商与回调地址，返回应在 id_token 中回传的 nonce
+func (s *loginApplicationService) consumeOIDCAuthorization(ctx context.Context, state *string, slug, redirectURI string) (string, error) {
+	if state == nil || *state == "" {
+		return "", perrors.WithCode(code.ErrOIDCStateInvalid, "oidc state is required")
+	}
+	if s.oidcAuthorizer == nil {
+		return "", perrors.WithCode(code.ErrInvalidArgument, "oidc authorization store not available")
+	}
+
+	authz, err := s.oidcAuthorizer.ConsumeAuthorization(ctx, *state)
+	if perrors.Is(err, oidcPort.ErrAuthorizationNotFound) {
+		return "", perrors.WithCode(code.ErrOIDCStateInvalid, "oidc state is unknown, expired or already used")
+	}
+	if err != nil {
+		return "", perrors.Wrap(err, "failed to consume oidc authorization")
+	}
+	if authz.Slug != slug || authz.RedirectURI != redirectURI {
+		logger.L(ctx).Warnw("上游 OIDC state 与登录请求不匹配",
+			"action", logger.ActionLogin,
+			"provider", slug,
+			"issued_provider", authz.Slug,
+			"result", logger.ResultFailed,
+		)
+		return "", perrors.WithCode(code.ErrOIDCStateInvalid, "oidc state was issued for a different provider or redirect_uri")
+	}
+	return authz.Nonce, nil
+}
+
+// oidcProvider 查询已启用的上游 OIDC 身份提供商并解密 client_secret（公共客户端可不配置）
+func (s *loginApplicationService) oidcProvider(ctx context.Context, scenario authentication.Scenario, slug string) (*oidcPort.OIDCProvider, string, error) {
+	l := logger.L(ctx)
+
+	if s.oidcQuerier == nil {
+		l.Errorw("上游 OIDC 身份提供商配置服务不可用",
+			"action", logger.ActionLogin,
+			"scenario", string(scenario),
+		)
+		return nil, "", perrors.WithCode(code.ErrInvalidArgument, "oidc provider configuration service not available")
+	}
+
+	provider, err := s.oidcQuerier.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, "", perrors.WithCode(code.ErrInvalidArgument, "failed to query oidc provider: %v", err)
+	}
+	if provider == nil {
+		l.Warnw("上游 OIDC 身份提供商未登记",
+			"action", logger.ActionLogin,
+			"scenario", string(scenario),
+			"provider", slug,
+		)
+		return nil, "", perrors.WithCode(code.ErrOIDCProviderNotFound, "oidc provider not found: %s", slug)
+	}
+	if !provider.IsEnabled() {
+		return nil, "", perrors.WithCode(code.ErrOIDCProviderStatusInvalid, "oidc provider is disabled: %s", slug)
+	}
+	if provider.Cred == nil || len(provider.Cred.SecretCipher) == 0 {
+		return provider, "", nil
+	}
+	if s.secretVault == nil {
+		return nil, "", perrors.WithCode(code.ErrInvalidArgument, "secret vault not available")
+	}
+
+	secret, err := s.secretVault.Decrypt(ctx, provider.Cred.SecretCipher)
+	if err != nil {
+		l.Errorw("解密上游 OIDC client_secret 失败",
+			"action", logger.ActionLogin,
+			"scenario", string(scenario),
+			"provider", slug,
+			"error", err.Error(),
+		)
+		return nil, "", perrors.WithCode(code.ErrInvalidArgument, "failed to decrypt client secret: %v", err)
+	}
+	return provider, string(secret), nil
+}
+
+// samlIdP 查询已启用的 SAML IdP 登记
+func (s *loginApplicationService) samlIdP(ctx context.Context, scenario authentication.Scenario, slug string) (*samlPort.IdentityProvider, error) {
+	l := logger.L(ctx)
+
+	if s.samlQuerier == nil {
+		l.Errorw("SAML IdP 登记服务不可用",
+			"action", logger.ActionLogin,
+			"scenario", string(scenario),
+		)
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "saml idp configuration service not available")
+	}
+
+	idp, err := s.samlQuerier.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "failed to query saml idp: %v", err)
+	}
+	if idp == nil {
+		l.Warnw("SAML IdP 未登记",
+			"action", logger.ActionLogin,
+			"scenario", string(scenario),
+			"idp", slug,
+		)
+		return nil, perrors.WithCode(code.ErrSAMLIdPNotFound, "saml idp not found: %s", slug)
+	}
+	if !idp.IsEnabled() {
+		return nil, perrors.WithCode(code.ErrSAMLIdPDisabled, "saml idp is disabled: %s", slug)
+	}
+	return idp, nil
+}
+
+// syncOIDCRoles 按提供商角色映射同步用户角色；同步失败不影响登录，仅记录日志
+func (s *loginApplicationService) syncOIDCRoles(ctx context.Context, slug string, decision authentication.AuthDecision) {
+	if s.roleSyncer == nil || s.oidcQuerier == nil {
+		return
+	}
+	l := logger.L(ctx)
+
+	provider, err := s.oidcQuerier.GetBySlug(ctx, slug)
+	if err != nil || provider == nil || len(provider.RoleMappings) == 0 {
+		return
+	}
+	tenantID := provider.TenantID
+	if tenantID.IsZero() {
+		tenantID = decision.Principal.TenantID
+	}
+
+	roles := provider.MapRoles(decision.ExternalClaims)
+	if err := s.roleSyncer.SyncExternalRoles(ctx, decision.Principal.UserID, tenantID, "oidc:"+slug, roles); err != nil {
+		l.Warnw("上游 OIDC 角色同步失败",
+			"action", logger.ActionLogin,
+			"provider", slug,
+			"user_id", decision.Principal.UserID.String(),
+			"error", err.Error(),
+		)
+	}
+}
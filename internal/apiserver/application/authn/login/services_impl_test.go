@@ -2,11 +2,19 @@ package login
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/loginprep"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	samlPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/saml"
 	domaintoken "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/token"
+	oidcPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/oidcprovider"
+	wecomPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
 	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
 	"github.com/FangcunMount/iam-contracts/pkg/tenant"
 	"github.com/stretchr/testify/require"
@@ -111,7 +119,7 @@ func TestLogin_DefaultsMissingTenantIDBeforeTokenIssue(t *testing.T) {
 			)
 
 			issuer := &loginTokenIssuerStub{}
-			svc := NewLoginApplicationService(issuer, nil, auth, nil, nil)
+			svc := NewLoginApplicationService(issuer, nil, auth, nil, nil, nil, nil, nil, nil, nil)
 
 			jwtToken := "jwt-token-value"
 			result, err := svc.Login(context.Background(), LoginRequest{
@@ -133,3 +141,320 @@ func TestLogin_DefaultsMissingTenantIDBeforeTokenIssue(t *testing.T) {
 		})
 	}
 }
+
+type wecomRepoStub struct {
+	apps []*wecomPort.WecomApp
+}
+
+func (s *wecomRepoStub) Create(context.Context, *wecomPort.WecomApp) error { return nil }
+func (s *wecomRepoStub) GetByID(context.Context, idutil.ID) (*wecomPort.WecomApp, error) {
+	return nil, nil
+}
+func (s *wecomRepoStub) GetByCorpAgent(_ context.Context, corpID, agentID string) (*wecomPort.WecomApp, error) {
+	for _, app := range s.apps {
+		if app.CorpID == corpID && app.AgentID == agentID {
+			return app, nil
+		}
+	}
+	return nil, nil
+}
+func (s *wecomRepoStub) List(_ context.Context, filter wecomPort.ListFilter) ([]*wecomPort.WecomApp, error) {
+	var out []*wecomPort.WecomApp
+	for _, app := range s.apps {
+		if filter.CorpID != nil && app.CorpID != *filter.CorpID {
+			continue
+		}
+		if filter.Status != nil && app.Status != *filter.Status {
+			continue
+		}
+		out = append(out, app)
+	}
+	return out, nil
+}
+func (s *wecomRepoStub) Update(context.Context, *wecomPort.WecomApp) error { return nil }
+
+type plainVaultStub struct{}
+
+func (plainVaultStub) Encrypt(_ context.Context, plaintext []byte) ([]byte, error) {
+	return plaintext, nil
+}
+func (plainVaultStub) Decrypt(_ context.Context, cipher []byte) ([]byte, error) { return cipher, nil }
+func (plainVaultStub) Sign(context.Context, string, []byte) ([]byte, error)     { return nil, nil }
+
+func TestPrepareAuthentication_ResolvesWecomAppFromRegistry(t *testing.T) {
+	newApp := func(agentID, secret string, status wecomPort.Status) *wecomPort.WecomApp {
+		app := wecomPort.NewWecomApp("ww-corp", agentID, wecomPort.WithWecomAppStatus(status))
+		app.Cred = &wecomPort.Credentials{Auth: &wecomPort.CorpSecret{SecretCipher: []byte(secret)}}
+		return app
+	}
+	corpID, authCode := "ww-corp", "code"
+
+	// 企业下仅一个启用应用：无需 AgentID
+	svc := &loginApplicationService{
+		apps: loginprep.NewIDPAppResolver(nil, &wecomRepoStub{apps: []*wecomPort.WecomApp{
+			newApp("1000002", "secret-a", wecomPort.StatusEnabled),
+			newApp("1000003", "secret-b", wecomPort.StatusDisabled),
+		}}, plainVaultStub{}),
+	}
+	scenario, input, err := svc.prepareAuthentication(context.Background(), LoginRequest{WecomCorpID: &corpID, WecomCode: &authCode})
+	require.NoError(t, err)
+	require.Equal(t, authentication.AuthWecom, scenario)
+	require.Equal(t, "1000002", input.WecomAgentID)
+	require.Equal(t, "secret-a", input.WecomCorpSecret)
+
+	// 多个启用应用：必须指定 AgentID
+	svc.apps = loginprep.NewIDPAppResolver(nil, &wecomRepoStub{apps: []*wecomPort.WecomApp{
+		newApp("1000002", "secret-a", wecomPort.StatusEnabled),
+		newApp("1000003", "secret-b", wecomPort.StatusEnabled),
+	}}, plainVaultStub{})
+	_, _, err = svc.prepareAuthentication(context.Background(), LoginRequest{WecomCorpID: &corpID, WecomCode: &authCode})
+	require.True(t, perrors.IsCode(err, code.ErrWecomAppAmbiguous))
+
+	agentID := "1000003"
+	_, input, err = svc.prepareAuthentication(context.Background(), LoginRequest{WecomCorpID: &corpID, WecomAgentID: &agentID, WecomCode: &authCode})
+	require.NoError(t, err)
+	require.Equal(t, "secret-b", input.WecomCorpSecret)
+
+	// 未登记
+	otherCorp := "ww-other"
+	_, _, err = svc.prepareAuthentication(context.Background(), LoginRequest{WecomCorpID: &otherCorp, WecomCode: &authCode})
+	require.True(t, perrors.IsCode(err, code.ErrWecomAppNotFound))
+}
+
+type oidcRepoStub struct {
+	providers map[string]*oidcPort.OIDCProvider
+}
+
+func (s *oidcRepoStub) Create(context.Context, *oidcPort.OIDCProvider) error { return nil }
+func (s *oidcRepoStub) GetByID(context.Context, idutil.ID) (*oidcPort.OIDCProvider, error) {
+	return nil, nil
+}
+func (s *oidcRepoStub) GetBySlug(_ context.Context, slug string) (*oidcPort.OIDCProvider, error) {
+	return s.providers[slug], nil
+}
+func (s *oidcRepoStub) List(context.Context, oidcPort.ListFilter) ([]*oidcPort.OIDCProvider, error) {
+	return nil, nil
+}
+func (s *oidcRepoStub) Update(context.Context, *oidcPort.OIDCProvider) error { return nil }
+
+type oidcBindingRepoStub struct{}
+
+func (oidcBindingRepoStub) FindPasswordCredential(context.Context, meta.ID) (meta.ID, string, error) {
+	return 0, "", nil
+}
+func (oidcBindingRepoStub) FindPhoneOTPCredential(context.Context, string) (meta.ID, meta.ID, meta.ID, error) {
+	return 0, 0, 0, nil
+}
+func (oidcBindingRepoStub) FindOAuthCredential(context.Context, string, string, string) (meta.ID, meta.ID, meta.ID, error) {
+	return meta.FromUint64(2002), meta.FromUint64(1001), meta.FromUint64(3003), nil
+}
+
+type oidcFederationStub struct {
+	got authentication.OIDCCodeExchange
+}
+
+func (s *oidcFederationStub) ExchangeOIDCCode(_ context.Context, req authentication.OIDCCodeExchange) (map[string]any, error) {
+	s.got = req
+	return map[string]any{"sub": "s-1", "groups": []any{"iam-admins", "staff"}}, nil
+}
+
+type roleSyncerStub struct {
+	userID, tenantID meta.ID
+	source           string
+	roles            []string
+}
+
+func (s *roleSyncerStub) SyncExternalRoles(_ context.Context, userID, tenantID meta.ID, source string, roleNames []string) error {
+	s.userID, s.tenantID, s.source, s.roles = userID, tenantID, source, roleNames
+	return nil
+}
+
+func TestLogin_OIDCResolvesProviderAndSyncsMappedRoles(t *testing.T) {
+	provider := oidcPort.NewOIDCProvider("corp-sso", "https://sso.example.com", "iam",
+		oidcPort.WithOIDCProviderStatus(oidcPort.StatusEnabled))
+	provider.Cred = &oidcPort.ClientSecret{SecretCipher: []byte("client-secret")}
+	provider.RoleMappings = []oidcPort.RoleMappingRule{{Claim: "groups", Value: "iam-admins", Role: "admin"}}
+	provider.TenantID = meta.FromUint64(9)
+	repo := &oidcRepoStub{providers: map[string]*oidcPort.OIDCProvider{"corp-sso": provider}}
+
+	fed := &oidcFederationStub{}
+	auth := authentication.NewAuthenticater(oidcBindingRepoStub{}, &loginAccountRepoStub{enabled: true}, nil, nil, nil, nil).
+		WithOIDCFederation(fed, nil)
+	syncer := &roleSyncerStub{}
+	authorizations := &oidcAuthorizationStoreStub{issued: map[string]oidcPort.AuthorizationRequest{}}
+	svc := NewLoginApplicationService(&loginTokenIssuerStub{}, nil, auth, nil, nil, repo, authorizations, nil, plainVaultStub{}, syncer)
+
+	slug, authCode, redirectURI := "corp-sso", "code", "https://app.example.com/cb"
+	state := authorizations.issue(slug, redirectURI, "n-1")
+	result, err := svc.Login(context.Background(), LoginRequest{
+		AuthType:        AuthTypeOIDC,
+		OIDCProvider:    &slug,
+		OIDCCode:        &authCode,
+		OIDCRedirectURI: &redirectURI,
+		OIDCState:       &state,
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(9), result.TenantID.Uint64())
+	require.Equal(t, "https://sso.example.com", fed.got.Issuer)
+	require.Equal(t, "client-secret", fed.got.ClientSecret)
+	require.Equal(t, redirectURI, fed.got.RedirectURI)
+	require.Equal(t, "n-1", fed.got.Nonce)
+
+	require.Equal(t, uint64(1001), syncer.userID.Uint64())
+	require.Equal(t, uint64(9), syncer.tenantID.Uint64())
+	require.Equal(t, "oidc:corp-sso", syncer.source)
+	require.Equal(t, []string{"admin"}, syncer.roles)
+
+	provider.Disable()
+	state = authorizations.issue(slug, redirectURI, "n-2")
+	_, err = svc.Login(context.Background(), LoginRequest{OIDCProvider: &slug, OIDCCode: &authCode, OIDCRedirectURI: &redirectURI, OIDCState: &state})
+	require.True(t, perrors.IsCode(err, code.ErrOIDCProviderStatusInvalid))
+
+	unknown := "unknown"
+	state = authorizations.issue(unknown, redirectURI, "n-3")
+	_, err = svc.Login(context.Background(), LoginRequest{OIDCProvider: &unknown, OIDCCode: &authCode, OIDCRedirectURI: &redirectURI, OIDCState: &state})
+	require.True(t, perrors.IsCode(err, code.ErrOIDCProviderNotFound))
+}
+
+func TestLogin_OIDCRequiresIssuedStateOnce(t *testing.T) {
+	provider := oidcPort.NewOIDCProvider("corp-sso", "https://sso.example.com", "iam",
+		oidcPort.WithOIDCProviderStatus(oidcPort.StatusEnabled))
+	repo := &oidcRepoStub{providers: map[string]*oidcPort.OIDCProvider{"corp-sso": provider}}
+
+	fed := &oidcFederationStub{}
+	auth := authentication.NewAuthenticater(oidcBindingRepoStub{}, &loginAccountRepoStub{enabled: true}, nil, nil, nil, nil).
+		WithOIDCFederation(fed, nil)
+	authorizations := &oidcAuthorizationStoreStub{issued: map[string]oidcPort.AuthorizationRequest{}}
+	svc := NewLoginApplicationService(&loginTokenIssuerStub{}, nil, auth, nil, nil, repo, authorizations, nil, plainVaultStub{}, &roleSyncerStub{})
+
+	slug, authCode, redirectURI := "corp-sso", "code", "https://app.example.com/cb"
+	attempt := func(state *string, redirect string) error {
+		_, err := svc.Login(context.Background(), LoginRequest{
+			AuthType:        AuthTypeOIDC,
+			OIDCProvider:    &slug,
+			OIDCCode:        &authCode,
+			OIDCRedirectURI: &redirect,
+			OIDCState:       state,
+		})
+		return err
+	}
+
+	require.True(t, perrors.IsCode(attempt(nil, redirectURI), code.ErrOIDCStateInvalid))
+
+	forged := "forged"
+	require.True(t, perrors.IsCode(attempt(&forged, redirectURI), code.ErrOIDCStateInvalid))
+
+	// 签发给其它回调地址的 state 被消费后不可再用
+	state := authorizations.issue(slug, "https://evil.example.com/cb", "n-1")
+	require.True(t, perrors.IsCode(attempt(&state, redirectURI), code.ErrOIDCStateInvalid))
+	require.True(t, perrors.IsCode(attempt(&state, "https://evil.example.com/cb"), code.ErrOIDCStateInvalid))
+
+	state = authorizations.issue(slug, redirectURI, "n-2")
+	require.NoError(t, attempt(&state, redirectURI))
+	require.Equal(t, "n-2", fed.got.Nonce)
+	require.True(t, perrors.IsCode(attempt(&state, redirectURI), code.ErrOIDCStateInvalid), "state 只能使用一次")
+}
+
+// oidcAuthorizationStoreStub 内存授权请求登记
+type oidcAuthorizationStoreStub struct {
+	issued map[string]oidcPort.AuthorizationRequest
+	seq    int
+}
+
+func (s *oidcAuthorizationStoreStub) issue(slug, redirectURI, nonce string) string {
+	s.seq++
+	state := fmt.Sprintf("st-%d", s.seq)
+	s.issued[state] = oidcPort.AuthorizationRequest{Slug: slug, RedirectURI: redirectURI, Nonce: nonce}
+	return state
+}
+
+func (s *oidcAuthorizationStoreStub) SaveAuthorization(_ context.Context, state string, req oidcPort.AuthorizationRequest, _ time.Duration) error {
+	s.issued[state] = req
+	return nil
+}
+
+func (s *oidcAuthorizationStoreStub) ConsumeAuthorization(_ context.Context, state string) (*oidcPort.AuthorizationRequest, error) {
+	req, ok := s.issued[state]
+	if !ok {
+		return nil, oidcPort.ErrAuthorizationNotFound
+	}
+	delete(s.issued, state)
+	return &req, nil
+}
+
+type samlRepoStub struct {
+	idps map[string]*samlPort.IdentityProvider
+}
+
+func (s *samlRepoStub) Create(context.Context, *samlPort.IdentityProvider) error { return nil }
+func (s *samlRepoStub) GetBySlug(_ context.Context, slug string) (*samlPort.IdentityProvider, error) {
+	return s.idps[slug], nil
+}
+func (s *samlRepoStub) List(context.Context, samlPort.ListFilter) ([]*samlPort.IdentityProvider, error) {
+	return nil, nil
+}
+func (s *samlRepoStub) Update(context.Context, *samlPort.IdentityProvider) error { return nil }
+
+type samlVerifierStub struct {
+	got  authentication.SAMLResponseVerification
+	used map[string]bool
+}
+
+func (s *samlVerifierStub) VerifySAMLResponse(_ context.Context, req authentication.SAMLResponseVerification) (authentication.SAMLAssertion, error) {
+	s.got = req
+	if s.used[req.Response] {
+		return authentication.SAMLAssertion{}, authentication.ErrSAMLReplay
+	}
+	s.used[req.Response] = true
+	return authentication.SAMLAssertion{
+		ID:           "_a1",
+		NameID:       "u-1001",
+		SessionIndex: "_s1",
+		Attributes:   map[string][]string{"mail": {"doctor@hospital.example"}},
+	}, nil
+}
+
+func TestLogin_SAMLUsesIdPTenantAndMapsAssertionErrors(t *testing.T) {
+	idp := samlPort.NewIdentityProvider(meta.FromUint64(9), "city-hospital", samlPort.IdPMetadata{
+		EntityID:     "https://idp.hospital.example/saml",
+		SSOURL:       "https://idp.hospital.example/sso",
+		Certificates: []string{"Y2VydA=="},
+	},
+		samlPort.WithIdentityProviderStatus(samlPort.StatusEnabled),
+		samlPort.WithAttributeMapping(map[string]string{"mail": "email"}),
+	)
+	repo := &samlRepoStub{idps: map[string]*samlPort.IdentityProvider{"city-hospital": idp}}
+
+	verifier := &samlVerifierStub{used: map[string]bool{}}
+	auth := authentication.NewAuthenticater(oidcBindingRepoStub{}, &loginAccountRepoStub{enabled: true}, nil, nil, nil, nil).
+		WithSAML(verifier, nil)
+	issuer := &loginTokenIssuerStub{}
+	svc := NewLoginApplicationService(issuer, nil, auth, nil, nil, nil, nil, repo, nil, nil)
+
+	// 请求携带的租户被忽略，以 IdP 登记的租户为准
+	slug, response := "city-hospital", "base64-response"
+	result, err := svc.Login(context.Background(), LoginRequest{
+		AuthType:     AuthTypeSAML,
+		TenantID:     meta.FromUint64(1),
+		SAMLIdP:      &slug,
+		SAMLResponse: &response,
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(9), result.TenantID.Uint64())
+	require.Equal(t, "https://idp.hospital.example/saml", verifier.got.IdPEntityID)
+	require.Equal(t, []string{"Y2VydA=="}, verifier.got.Certificates)
+	require.Equal(t, "doctor@hospital.example", issuer.captured.Claims["email"])
+	require.Equal(t, "u-1001", issuer.captured.Claims["saml_name_id"])
+	require.Equal(t, []string{string(authentication.AMRSAML)}, issuer.captured.AMR)
+
+	_, err = svc.Login(context.Background(), LoginRequest{SAMLIdP: &slug, SAMLResponse: &response})
+	require.True(t, perrors.IsCode(err, code.ErrSAMLAssertionReplayed))
+
+	idp.Disable()
+	_, err = svc.Login(context.Background(), LoginRequest{SAMLIdP: &slug, SAMLResponse: &response})
+	require.True(t, perrors.IsCode(err, code.ErrSAMLIdPDisabled))
+
+	unknown := "unknown"
+	_, err = svc.Login(context.Background(), LoginRequest{SAMLIdP: &unknown, SAMLResponse: &response})
+	require.True(t, perrors.IsCode(err, code.ErrSAMLIdPNotFound))
+}
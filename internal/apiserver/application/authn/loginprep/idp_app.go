@@ -0,0 +1,98 @@
+package loginprep
+
+import (
+	"context"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	idpPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+	wecomPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+// IDPAppResolver 按应用登记表解析微信/企业微信应用并解密其密钥
+// 登录、注册与身份绑定共用，保证三处对应用状态与类型的校验一致
+type IDPAppResolver struct {
+	wechatApps idpPort.Repository
+	wecomApps  wecomPort.Repository
+	vault      idpPort.SecretVault
+}
+
+// NewIDPAppResolver 创建应用解析器，未接入的登记表可传 nil
+func NewIDPAppResolver(wechatApps idpPort.Repository, wecomApps wecomPort.Repository, vault idpPort.SecretVault) *IDPAppResolver {
+	return &IDPAppResolver{wechatApps: wechatApps, wecomApps: wecomApps, vault: vault}
+}
+
+// WechatAppSecret 查询已启用的微信应用并解密 AppSecret
+// appType 非空时限定应用类型，避免小程序与公众号 AppID 混用
+func (r *IDPAppResolver) WechatAppSecret(ctx context.Context, appID string, appType idpPort.AppType) (string, error) {
+	if r == nil || r.wechatApps == nil || r.vault == nil {
+		return "", perrors.WithCode(code.ErrInvalidArgument, "wechat app configuration service not available")
+	}
+
+	app, err := r.wechatApps.GetByAppID(ctx, appID)
+	if err != nil {
+		return "", perrors.WithCode(code.ErrInvalidArgument, "failed to query wechat app: %v", err)
+	}
+	if app == nil {
+		return "", perrors.WithCode(code.ErrInvalidArgument, "wechat app not found: %s", appID)
+	}
+	if appType != "" && app.Type != "" && app.Type != appType {
+		return "", perrors.WithCode(code.ErrInvalidArgument, "wechat app %s is not a %s app", appID, appType)
+	}
+	if !app.IsEnabled() {
+		return "", perrors.WithCode(code.ErrInvalidArgument, "wechat app is disabled: %s", appID)
+	}
+	if app.Cred == nil || app.Cred.Auth == nil {
+		return "", perrors.WithCode(code.ErrInvalidArgument, "wechat app credentials not found")
+	}
+
+	plain, err := r.vault.Decrypt(ctx, app.Cred.Auth.AppSecretCipher)
+	if err != nil {
+		return "", perrors.WithCode(code.ErrInvalidArgument, "failed to decrypt app secret: %v", err)
+	}
+	return string(plain), nil
+}
+
+// WecomApp 从企业微信应用登记表解析 AgentID 并解密 CorpSecret
+// 未指定 AgentID 时要求该企业下恰好登记了一个启用的应用
+func (r *IDPAppResolver) WecomApp(ctx context.Context, corpID, agentID string) (*wecomPort.WecomApp, string, error) {
+	if r == nil || r.wecomApps == nil || r.vault == nil {
+		return nil, "", perrors.WithCode(code.ErrInvalidArgument, "wecom app configuration service not available")
+	}
+
+	var app *wecomPort.WecomApp
+	if agentID != "" {
+		found, err := r.wecomApps.GetByCorpAgent(ctx, corpID, agentID)
+		if err != nil {
+			return nil, "", perrors.WithCode(code.ErrInvalidArgument, "failed to query wecom app: %v", err)
+		}
+		app = found
+	} else {
+		enabled := wecomPort.StatusEnabled
+		apps, err := r.wecomApps.List(ctx, wecomPort.ListFilter{CorpID: &corpID, Status: &enabled})
+		if err != nil {
+			return nil, "", perrors.WithCode(code.ErrInvalidArgument, "failed to query wecom app: %v", err)
+		}
+		if len(apps) > 1 {
+			return nil, "", perrors.WithCode(code.ErrWecomAppAmbiguous, "multiple wecom apps registered for corp %s, agent_id is required", corpID)
+		}
+		if len(apps) == 1 {
+			app = apps[0]
+		}
+	}
+	if app == nil {
+		return nil, "", perrors.WithCode(code.ErrWecomAppNotFound, "wecom app not found: %s/%s", corpID, agentID)
+	}
+	if !app.IsEnabled() {
+		return nil, "", perrors.WithCode(code.ErrInvalidArgument, "wecom app is disabled: %s/%s", corpID, app.AgentID)
+	}
+	if app.Cred == nil || app.Cred.Auth == nil {
+		return nil, "", perrors.WithCode(code.ErrInvalidArgument, "wecom app corp secret not configured")
+	}
+
+	secret, err := r.vault.Decrypt(ctx, app.Cred.Auth.SecretCipher)
+	if err != nil {
+		return nil, "", perrors.WithCode(code.ErrInvalidArgument, "failed to decrypt corp secret: %v", err)
+	}
+	return app, string(secret), nil
+}
@@ -1,15 +1,18 @@
 package loginprep
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
 	"math/big"
 	"time"
 
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
 )
 
-// PhoneOTPDeps 手机登录发码依赖（与登录校验共用 Redis OTP 约定）
+// PhoneOTPDeps 手机验证码发码依赖（与校验侧共用 Redis OTP 约定，按 scene 隔离）
 type PhoneOTPDeps struct {
 	Store    authentication.OTPCodeStore
 	Gate     authentication.OTPSendGate
@@ -46,6 +49,42 @@ func (d *PhoneOTPDeps) effectiveCodeLen() int {
 // loginOTPScene 与 domain 层 PhoneOTPAuthStrategy 中 OTP 场景一致
 const loginOTPScene = "login"
 
+// Deliver 为指定场景生成验证码并投递：频控 → 生成 → 写入 → 发送（发送失败回滚写入）
+// phoneE164 须已规范化为 E.164
+func (d *PhoneOTPDeps) Deliver(ctx context.Context, phoneE164, scene string) error {
+	if d == nil || d.Store == nil || d.Gate == nil || d.SMS == nil {
+		return perrors.WithCode(code.ErrInvalidArgument, "phone OTP is not configured")
+	}
+
+	ok, err := d.Gate.TryAcquire(ctx, phoneE164, scene, d.effectiveCooldown())
+	if err != nil {
+		return fmt.Errorf("%s otp send gate: %w", scene, err)
+	}
+	if !ok {
+		return perrors.WithCode(code.ErrOTPSendTooFrequent, "please wait before requesting another code")
+	}
+
+	otp, err := randomNumericOTP(d.effectiveCodeLen())
+	if err != nil {
+		return perrors.WithCode(code.ErrInternalServerError, "failed to generate otp: %v", err)
+	}
+
+	if err := d.Store.Put(ctx, phoneE164, scene, otp, d.effectiveTTL()); err != nil {
+		return fmt.Errorf("store %s otp: %w", scene, err)
+	}
+
+	if scene == loginOTPScene {
+		err = d.SMS.SendLoginOTP(ctx, phoneE164, otp)
+	} else {
+		err = d.SMS.SendOTP(ctx, phoneE164, scene, otp)
+	}
+	if err != nil {
+		_ = d.Store.Delete(ctx, phoneE164, scene, otp)
+		return fmt.Errorf("send %s otp sms: %w", scene, err)
+	}
+	return nil
+}
+
 func randomNumericOTP(length int) (string, error) {
 	if length <= 0 || length > 12 {
 		return "", fmt.Errorf("invalid otp length %d", length)
@@ -0,0 +1,53 @@
+package password
+
+import (
+	"context"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// ============= 应用服务接口（Driving Ports）=============
+
+// PasswordApplicationService 密码自助服务：修改密码与短信验证码重置密码
+type PasswordApplicationService interface {
+	// ChangePassword 已登录用户凭当前密码修改密码，成功后撤销该账户除当前会话外的全部会话
+	ChangePassword(ctx context.Context, req ChangePasswordRequest) error
+
+	// SendResetCode 发送重置密码验证码（场景 reset_password）
+	SendResetCode(ctx context.Context, phone string) error
+
+	// ResetPassword 凭手机验证码重置密码，成功后撤销该账户全部会话并解除失败锁定
+	ResetPassword(ctx context.Context, req ResetPasswordRequest) error
+
+	// ChangeExpiredPassword 密码过期无法登录时，凭用户名与当前密码修改密码（无需令牌）
+	// 与密码登录共用失败节流与凭据锁定，校验未通过时统一返回凭据错误
+	ChangeExpiredPassword(ctx context.Context, req ExpiredPasswordChangeRequest) error
+}
+
+// ============= DTOs =============
+
+// ChangePasswordRequest 修改密码请求
+type ChangePasswordRequest struct {
+	AccountID       meta.ID // 当前登录账户
+	TenantID        meta.ID // 当前令牌租户（用于解析租户密码策略）
+	SessionID       string  // 发起修改的当前会话，修改后保留
+	CurrentPassword string  // 当前密码（明文）
+	NewPassword     string  // 新密码（明文）
+}
+
+// ResetPasswordRequest 重置密码请求
+type ResetPasswordRequest struct {
+	Phone       string // 手机号（支持国内号码，服务端规范为 E.164）
+	Code        string // 短信验证码
+	NewPassword string // 新密码（明文）
+}
+
+// ExpiredPasswordChangeRequest 过期密码修改请求
+type ExpiredPasswordChangeRequest struct {
+	TenantID        meta.ID // 登录租户（与密码登录一致）
+	RemoteIP        string  // 来源 IP（失败节流维度）
+	UserAgent       string  // 客户端 UA
+	Username        string  // 登录名
+	CurrentPassword string  // 当前（已过期）密码
+	NewPassword     string  // 新密码（明文）
+}
@@ -0,0 +1,399 @@
+package password
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/logger"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/loginprep"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/uow"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	credDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/credential"
+	sessiondomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/session"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+const (
+	// resetPasswordScene 重置密码验证码场景（与登录验证码隔离，互不可用）
+	resetPasswordScene = "reset_password"
+
+	// 会话撤销原因
+	revokeReasonPasswordChange = "password_change"
+	revokeReasonPasswordReset  = "password_reset"
+	revokeReasonPasswordExpire = "password_expired"
+
+	passwordAlgo = "argon2id"
+)
+
+// CredentialAuthenticator 登录认证入口（由认证器实现）
+// 过期密码修改借此复用密码登录的失败节流、凭据锁定与过期检查
+type CredentialAuthenticator interface {
+	Authenticate(ctx context.Context, scenario authentication.Scenario, input authentication.AuthInput) (authentication.AuthDecision, error)
+}
+
+// passwordApplicationService 密码自助服务实现
+type passwordApplicationService struct {
+	uow            uow.UnitOfWork
+	hasher         authentication.PasswordHasher
+	otpVerifier    authentication.OTPVerifier
+	phoneOTP       *loginprep.PhoneOTPDeps
+	sessionManager sessiondomain.Manager
+	rotator        credDomain.Rotator
+	validator      *credDomain.PasswordPolicyValidator
+	accounts       authentication.AccountRepository
+	authenticator  CredentialAuthenticator
+}
+
+var _ PasswordApplicationService = (*passwordApplicationService)(nil)
+
+// NewPasswordApplicationService 创建密码自助服务
+// phoneOTP 与登录发码共用 Redis 存储、频控与短信通道，按 reset_password 场景隔离
+// validator 为空时使用默认密码策略（不做历史与泄露库校验）
+// accounts 与 authenticator 任一为空时不开放过期密码修改
+func NewPasswordApplicationService(
+	uow uow.UnitOfWork,
+	hasher authentication.PasswordHasher,
+	otpVerifier authentication.OTPVerifier,
+	phoneOTP *loginprep.PhoneOTPDeps,
+	sessionManager sessiondomain.Manager,
+	validator *credDomain.PasswordPolicyValidator,
+	accounts authentication.AccountRepository,
+	authenticator CredentialAuthenticator,
+) PasswordApplicationService {
+	if validator == nil {
+		validator = credDomain.NewPasswordPolicyValidator(nil, nil, nil)
+	}
+	return &passwordApplicationService{
+		uow:            uow,
+		hasher:         hasher,
+		otpVerifier:    otpVerifier,
+		phoneOTP:       phoneOTP,
+		sessionManager: sessionManager,
+		rotator:        credDomain.NewRotator(),
+		validator:      validator,
+		accounts:       accounts,
+		authenticator:  authenticator,
+	}
+}
+
+// ChangePassword 修改密码
+func (s *passwordApplicationService) ChangePassword(ctx context.Context, req ChangePasswordRequest) error {
+	l := logger.L(ctx)
+	if req.AccountID.IsZero() {
+		return perrors.WithCode(code.ErrUnauthenticated, "account is required")
+	}
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		return perrors.WithCode(code.ErrInvalidArgument, "current and new password are required")
+	}
+	if req.CurrentPassword == req.NewPassword {
+		return perrors.WithCode(code.ErrInvalidArgument, "new password must differ from the current password")
+	}
+
+	err := s.uow.WithinTx(ctx, func(tx uow.TxRepositories) error {
+		cred, err := s.passwordCredential(ctx, tx, req.AccountID)
+		if err != nil {
+			return err
+		}
+		if !s.hasher.Verify(string(cred.Material), req.CurrentPassword+s.hasher.Pepper()) {
+			return perrors.WithCode(code.ErrPasswordIncorrect, "current password is incorrect")
+		}
+		return s.rotate(ctx, tx, cred, req.TenantID, req.NewPassword, false)
+	})
+	if err != nil {
+		l.Warnw("修改密码失败",
+			"action", logger.ActionUpdate,
+			"resource", "password",
+			"account_id", req.AccountID.String(),
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return err
+	}
+
+	l.Infow("密码已修改",
+		"action", logger.ActionUpdate,
+		"resource", "password",
+		"account_id", req.AccountID.String(),
+		"result", logger.ResultSuccess,
+	)
+	return s.revokeSessions(ctx, req.AccountID, req.SessionID, revokeReasonPasswordChange)
+}
+
+// SendResetCode 发送重置密码验证码
+// 不校验手机号是否已绑定账户，避免借此枚举注册用户
+func (s *passwordApplicationService) SendResetCode(ctx context.Context, rawPhone string) error {
+	phone, err := meta.NewPhone(rawPhone)
+	if err != nil {
+		return perrors.WithCode(code.ErrInvalidArgument, "invalid phone: %v", err)
+	}
+	return s.phoneOTP.Deliver(ctx, phone.String(), resetPasswordScene)
+}
+
+// ResetPassword 凭验证码重置密码
+func (s *passwordApplicationService) ResetPassword(ctx context.Context, req ResetPasswordRequest) error {
+	l := logger.L(ctx)
+	if req.Code == "" || req.NewPassword == "" {
+		return perrors.WithCode(code.ErrInvalidArgument, "code and new password are required")
+	}
+	phone, err := meta.NewPhone(req.Phone)
+	if err != nil {
+		return perrors.WithCode(code.ErrInvalidArgument, "invalid phone: %v", err)
+	}
+	e164 := phone.String()
+	if s.otpVerifier == nil {
+		return perrors.WithCode(code.ErrInvalidArgument, "phone OTP is not configured")
+	}
+	if !s.otpVerifier.VerifyAndConsume(ctx, e164, resetPasswordScene, req.Code) {
+		return perrors.WithCode(code.ErrOTPInvalid, "verification code is invalid or expired")
+	}
+
+	var accountID meta.ID
+	err = s.uow.WithinTx(ctx, func(tx uow.TxRepositories) error {
+		binding, err := tx.Credentials.GetByIDPIdentifier(ctx, e164, credDomain.CredPhoneOTP)
+		if err != nil {
+			return perrors.WrapC(err, code.ErrDatabase, "failed to find phone binding")
+		}
+		if binding == nil {
+			return perrors.WithCode(code.ErrNoBinding, "phone is not bound to any account")
+		}
+		accountID = binding.AccountID
+
+		account, err := tx.Accounts.GetByID(ctx, accountID)
+		if err != nil {
+			return perrors.WrapC(err, code.ErrDatabase, "failed to find account")
+		}
+		if account == nil {
+			return perrors.WithCode(code.ErrNoBinding, "phone is not bound to any account")
+		}
+
+		cred, err := s.passwordCredential(ctx, tx, accountID)
+		if err != nil {
+			return err
+		}
+		return s.rotate(ctx, tx, cred, account.ScopedTenantID, req.NewPassword, true)
+	})
+	if err != nil {
+		l.Warnw("重置密码失败",
+			"action", logger.ActionUpdate,
+			"resource", "password",
+			"phone", e164,
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return err
+	}
+
+	l.Infow("密码已重置",
+		"action", logger.ActionUpdate,
+		"resource", "password",
+		"account_id", accountID.String(),
+		"result", logger.ResultSuccess,
+	)
+	return s.revokeSessions(ctx, accountID, "", revokeReasonPasswordReset)
+}
+
+// ChangeExpiredPassword 凭用户名与当前密码修改已过期的密码
+// 当前密码经登录认证器校验（节流 -> 凭据锁定 -> 密码 -> 过期），失败计入登录失败计数；
+// 仅在密码确已过期时放行，未过期时须走登录后的修改密码接口
+func (s *passwordApplicationService) ChangeExpiredPassword(ctx context.Context, req ExpiredPasswordChangeRequest) error {
+	l := logger.L(ctx)
+	if req.Username == "" || req.CurrentPassword == "" || req.NewPassword == "" {
+		return perrors.WithCode(code.ErrInvalidArgument, "username, current and new password are required")
+	}
+	if req.CurrentPassword == req.NewPassword {
+		return perrors.WithCode(code.ErrInvalidArgument, "new password must differ from the current password")
+	}
+	if s.accounts == nil || s.authenticator == nil {
+		return perrors.WithCode(code.ErrInvalidArgument, "password login is not configured")
+	}
+
+	decision, err := s.authenticator.Authenticate(ctx, authentication.AuthPassword, authentication.AuthInput{
+		TenantID:  req.TenantID,
+		RemoteIP:  req.RemoteIP,
+		UserAgent: req.UserAgent,
+		Username:  req.Username,
+		Password:  req.CurrentPassword,
+	})
+	if err != nil {
+		return perrors.WrapC(err, code.ErrInternalServerError, "failed to verify current password")
+	}
+	if decision.OK {
+		// 密码已验证，可安全提示未过期
+		return perrors.WithCode(code.ErrInvalidArgument, "password is not expired, change it after login")
+	}
+	if decision.ErrCode != authentication.ErrPasswordExpired {
+		// 账户不存在、密码错误、锁定、节流等一律同码，防止借此探测账户状态
+		l.Warnw("修改过期密码校验未通过",
+			"action", logger.ActionUpdate,
+			"resource", "password",
+			"username", req.Username,
+			"err_code", string(decision.ErrCode),
+			"result", logger.ResultFailed,
+		)
+		return errExpiredPasswordCredential()
+	}
+
+	lookup, err := s.accounts.FindAccountByUsername(ctx, req.TenantID, req.Username)
+	if err != nil {
+		return perrors.WrapC(err, code.ErrDatabase, "failed to find account")
+	}
+	if lookup == nil || lookup.AccountID.IsZero() {
+		return errExpiredPasswordCredential()
+	}
+	tenantID := req.TenantID
+	if !lookup.ScopedTenantID.IsZero() {
+		tenantID = lookup.ScopedTenantID
+	}
+
+	err = s.uow.WithinTx(ctx, func(tx uow.TxRepositories) error {
+		cred, err := s.passwordCredential(ctx, tx, lookup.AccountID)
+		if err != nil {
+			return err
+		}
+		if cred.ID != decision.CredentialID {
+			// 校验后凭据已被替换
+			return errExpiredPasswordCredential()
+		}
+		return s.rotate(ctx, tx, cred, tenantID, req.NewPassword, false)
+	})
+	if err != nil {
+		l.Warnw("修改过期密码失败",
+			"action", logger.ActionUpdate,
+			"resource", "password",
+			"username", req.Username,
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return err
+	}
+
+	l.Infow("过期密码已修改",
+		"action", logger.ActionUpdate,
+		"resource", "password",
+		"account_id", lookup.AccountID.String(),
+		"result", logger.ResultSuccess,
+	)
+	return s.revokeSessions(ctx, lookup.AccountID, "", revokeReasonPasswordExpire)
+}
+
+// errExpiredPasswordCredential 过期密码修改的统一凭据错误
+func errExpiredPasswordCredential() error {
+	return perrors.WithCode(code.ErrPasswordIncorrect, "username or password is incorrect")
+}
+
+// passwordCredential 查询账户的可用密码凭据
+func (s *passwordApplicationService) passwordCredential(ctx context.Context, tx uow.TxRepositories, accountID meta.ID) (*credDomain.Credential, error) {
+	cred, err := tx.Credentials.GetByAccountIDAndType(ctx, accountID, credDomain.CredPassword)
+	if err != nil {
+		return nil, perrors.WrapC(err, code.ErrDatabase, "failed to find password credential")
+	}
+	if cred == nil {
+		return nil, perrors.WithCode(code.ErrCredentialNotFound, "account has no password credential")
+	}
+	if !cred.IsEnabled() {
+		return nil, perrors.WithCode(code.ErrCredentialDisabled, "password credential is disabled")
+	}
+	return cred, nil
+}
+
+// rotate 按租户密码策略校验新密码，哈希后落库并归档旧哈希；unlock 为 true 时同时清除失败计数与锁定
+func (s *passwordApplicationService) rotate(ctx context.Context, tx uow.TxRepositories, cred *credDomain.Credential, tenantID meta.ID, newPassword string, unlock bool) error {
+	policy := s.validator.Policy(tenantID)
+	previous, err := s.previousHashes(ctx, tx, cred, policy.HistorySize)
+	if err != nil {
+		return err
+	}
+	if err := s.validator.Validate(ctx, tenantID, newPassword, previous); err != nil {
+		return err
+	}
+
+	hashed, err := s.hasher.Hash(newPassword + s.hasher.Pepper())
+	if err != nil {
+		return perrors.WithCode(code.ErrEncrypt, "failed to hash password: %v", err)
+	}
+	old := credDomain.PasswordHistoryEntry{CredentialID: cred.ID, Material: cred.Material, Algo: passwordAlgo, CreatedAt: time.Now()}
+	if cred.Algo != nil {
+		old.Algo = *cred.Algo
+	}
+
+	algo := passwordAlgo
+	s.rotator.Rotate(cred, []byte(hashed), &algo)
+	if err := tx.Credentials.UpdateMaterial(ctx, cred.ID, cred.Material, algo); err != nil {
+		return perrors.WrapC(err, code.ErrDatabase, "failed to update password")
+	}
+	cred.MarkPasswordChanged(old.CreatedAt)
+	if err := tx.Credentials.UpdatePasswordChangedAt(ctx, cred.ID, old.CreatedAt); err != nil {
+		return perrors.WrapC(err, code.ErrDatabase, "failed to update password changed time")
+	}
+
+	// 当前密码占用一个名额，历史表只需保留 HistorySize-1 条
+	if policy.HistorySize > 1 && tx.PasswordHistory != nil && len(old.Material) > 0 {
+		if err := tx.PasswordHistory.Append(ctx, old); err != nil {
+			return perrors.WrapC(err, code.ErrDatabase, "failed to archive password history")
+		}
+		if err := tx.PasswordHistory.Prune(ctx, cred.ID, policy.HistorySize-1); err != nil {
+			return perrors.WrapC(err, code.ErrDatabase, "failed to prune password history")
+		}
+	}
+
+	if !unlock || (cred.FailedAttempts == 0 && cred.LockedUntil == nil) {
+		return nil
+	}
+	if err := tx.Credentials.UpdateFailedAttempts(ctx, cred.ID, 0); err != nil {
+		return perrors.WrapC(err, code.ErrDatabase, "failed to reset failed attempts")
+	}
+	if err := tx.Credentials.UpdateLockedUntil(ctx, cred.ID, nil); err != nil {
+		return perrors.WrapC(err, code.ErrDatabase, "failed to clear credential lock")
+	}
+	return nil
+}
+
+// previousHashes 当前密码及历史密码哈希（新到旧），用于复用检查
+func (s *passwordApplicationService) previousHashes(ctx context.Context, tx uow.TxRepositories, cred *credDomain.Credential, historySize int) ([][]byte, error) {
+	if historySize <= 0 {
+		return nil, nil
+	}
+	previous := [][]byte{cred.Material}
+	if historySize == 1 || tx.PasswordHistory == nil {
+		return previous, nil
+	}
+	entries, err := tx.PasswordHistory.ListRecent(ctx, cred.ID, historySize-1)
+	if err != nil {
+		return nil, perrors.WrapC(err, code.ErrDatabase, "failed to load password history")
+	}
+	for _, e := range entries {
+		previous = append(previous, e.Material)
+	}
+	return previous, nil
+}
+
+// revokeSessions 密码变更后撤销账户会话，keepSessionID 非空时保留该会话（已登录修改密码的当前会话）
+// 重置与过期修改密码不存在可信的当前会话，撤销全部会话，客户端需重新登录
+func (s *passwordApplicationService) revokeSessions(ctx context.Context, accountID meta.ID, keepSessionID, reason string) error {
+	if s.sessionManager == nil {
+		return nil
+	}
+	if keepSessionID == "" {
+		if err := s.sessionManager.RevokeByAccount(ctx, accountID, reason, accountID.String()); err != nil {
+			return fmt.Errorf("password updated but failed to revoke sessions: %w", err)
+		}
+		return nil
+	}
+
+	sessions, err := s.sessionManager.ListByAccount(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("password updated but failed to list sessions: %w", err)
+	}
+	for _, sess := range sessions {
+		if sess == nil || sess.SessionID == keepSessionID {
+			continue
+		}
+		if err := s.sessionManager.Revoke(ctx, sess.SessionID, reason, accountID.String()); err != nil {
+			return fmt.Errorf("password updated but failed to revoke sessions: %w", err)
+		}
+	}
+	return nil
+}
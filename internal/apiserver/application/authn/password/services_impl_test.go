@@ -0,0 +1,412 @@
+package password
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/loginprep"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/uow"
+	accountDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	credDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/credential"
+	sessiondomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/session"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+type credentialRepoStub struct {
+	credDomain.Repository
+	byID map[uint64]*credDomain.Credential
+}
+
+func (s *credentialRepoStub) GetByAccountIDAndType(_ context.Context, accountID meta.ID, t credDomain.CredentialType) (*credDomain.Credential, error) {
+	for _, c := range s.byID {
+		if c.AccountID == accountID && ((t == credDomain.CredPassword && c.IDP == nil) || (t == credDomain.CredPhoneOTP && c.IsPhoneOTPType())) {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *credentialRepoStub) GetByIDPIdentifier(_ context.Context, identifier string, _ credDomain.CredentialType) (*credDomain.Credential, error) {
+	for _, c := range s.byID {
+		if c.IDPIdentifier == identifier {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *credentialRepoStub) UpdateMaterial(_ context.Context, id meta.ID, material []byte, algo string) error {
+	s.byID[id.Uint64()].Material = material
+	s.byID[id.Uint64()].Algo = &algo
+	return nil
+}
+
+func (s *credentialRepoStub) UpdateFailedAttempts(_ context.Context, id meta.ID, attempts int) error {
+	s.byID[id.Uint64()].FailedAttempts = attempts
+	return nil
+}
+
+func (s *credentialRepoStub) UpdateLockedUntil(_ context.Context, id meta.ID, until *time.Time) error {
+	s.byID[id.Uint64()].LockedUntil = until
+	return nil
+}
+
+func (s *credentialRepoStub) UpdatePasswordChangedAt(_ context.Context, id meta.ID, changedAt time.Time) error {
+	s.byID[id.Uint64()].PasswordChangedAt = &changedAt
+	return nil
+}
+
+type historyRepoStub struct {
+	entries []credDomain.PasswordHistoryEntry // 新到旧
+}
+
+func (h *historyRepoStub) Append(_ context.Context, entry credDomain.PasswordHistoryEntry) error {
+	h.entries = append([]credDomain.PasswordHistoryEntry{entry}, h.entries...)
+	return nil
+}
+
+func (h *historyRepoStub) ListRecent(_ context.Context, _ meta.ID, limit int) ([]credDomain.PasswordHistoryEntry, error) {
+	if len(h.entries) > limit {
+		return h.entries[:limit], nil
+	}
+	return h.entries, nil
+}
+
+func (h *historyRepoStub) Prune(_ context.Context, _ meta.ID, keep int) error {
+	if len(h.entries) > keep {
+		h.entries = h.entries[:keep]
+	}
+	return nil
+}
+
+type accountRepoStub struct {
+	accountDomain.Repository
+}
+
+func (accountRepoStub) GetByID(_ context.Context, id meta.ID) (*accountDomain.Account, error) {
+	return &accountDomain.Account{ID: id}, nil
+}
+
+// usernameRepoStub 密码登录账户查询
+type usernameRepoStub struct{}
+
+func (usernameRepoStub) FindAccountByUsername(_ context.Context, _ meta.ID, username string) (*authentication.UsernameLoginLookup, error) {
+	if username != "alice" {
+		return nil, nil
+	}
+	return &authentication.UsernameLoginLookup{AccountID: meta.FromUint64(100)}, nil
+}
+
+func (usernameRepoStub) GetAccountStatus(context.Context, meta.ID) (bool, bool, error) {
+	return true, false, nil
+}
+
+type uowStub struct {
+	creds   *credentialRepoStub
+	history *historyRepoStub
+}
+
+func (u uowStub) WithinTx(_ context.Context, fn func(tx uow.TxRepositories) error) error {
+	return fn(uow.TxRepositories{Accounts: accountRepoStub{}, Credentials: u.creds, PasswordHistory: u.history})
+}
+
+// plainHasher 以 "hash:" 前缀模拟哈希，便于断言
+type plainHasher struct{}
+
+func (plainHasher) Hash(p string) (string, error) { return "hash:" + p, nil }
+func (plainHasher) Verify(h, p string) bool       { return h == "hash:"+p }
+func (plainHasher) NeedRehash(string) bool        { return false }
+func (plainHasher) Pepper() string                { return "" }
+
+type otpStub struct {
+	codes map[string]string // phone|scene -> code
+}
+
+func (o *otpStub) VerifyAndConsume(_ context.Context, phone, scene, c string) bool {
+	key := phone + "|" + scene
+	if o.codes[key] != c {
+		return false
+	}
+	delete(o.codes, key)
+	return true
+}
+
+func (o *otpStub) Put(_ context.Context, phone, scene, c string, _ time.Duration) error {
+	o.codes[phone+"|"+scene] = c
+	return nil
+}
+
+func (o *otpStub) Delete(_ context.Context, phone, scene, _ string) error {
+	delete(o.codes, phone+"|"+scene)
+	return nil
+}
+
+func (o *otpStub) TryAcquire(context.Context, string, string, time.Duration) (bool, error) {
+	return true, nil
+}
+
+type smsStub struct{ scenes []string }
+
+func (s *smsStub) SendLoginOTP(ctx context.Context, phone, c string) error {
+	return s.SendOTP(ctx, phone, "login", c)
+}
+
+func (s *smsStub) SendOTP(_ context.Context, _, scene, _ string) error {
+	s.scenes = append(s.scenes, scene)
+	return nil
+}
+
+type sessionManagerStub struct {
+	sessiondomain.Manager
+	revoked        map[string]string // accountID -> reason
+	active         []*sessiondomain.Session
+	revokedSession map[string]string // sessionID -> reason
+}
+
+func (m *sessionManagerStub) RevokeByAccount(_ context.Context, accountID meta.ID, reason, _ string) error {
+	m.revoked[accountID.String()] = reason
+	return nil
+}
+
+func (m *sessionManagerStub) ListByAccount(context.Context, meta.ID) ([]*sessiondomain.Session, error) {
+	return m.active, nil
+}
+
+func (m *sessionManagerStub) Revoke(_ context.Context, sessionID, reason, _ string) error {
+	m.revokedSession[sessionID] = reason
+	return nil
+}
+
+// passwordCredentialStub 认证器读取的密码凭据与过期状态
+type passwordCredentialStub struct {
+	authentication.CredentialRepository
+	creds  *credentialRepoStub
+	policy credDomain.PasswordPolicy
+}
+
+func (s passwordCredentialStub) FindPasswordCredential(ctx context.Context, accountID meta.ID) (meta.ID, string, error) {
+	cred, _ := s.creds.GetByAccountIDAndType(ctx, accountID, credDomain.CredPassword)
+	if cred == nil {
+		return 0, "", nil
+	}
+	return cred.ID, string(cred.Material), nil
+}
+
+func (s passwordCredentialStub) IsPasswordExpired(_ context.Context, _, credentialID meta.ID) (bool, error) {
+	return s.policy.IsExpired(s.creds.byID[credentialID.Uint64()].PasswordChangedAt, time.Now()), nil
+}
+
+// lockoutStub 按凭据 LockedUntil 判定锁定，失败累加计数
+type lockoutStub struct{ creds *credentialRepoStub }
+
+func (l lockoutStub) IsLocked(_ context.Context, id meta.ID) (bool, error) {
+	return l.creds.byID[id.Uint64()].IsLockedByTime(time.Now()), nil
+}
+
+func (l lockoutStub) RecordFailure(_ context.Context, id meta.ID) (bool, error) {
+	l.creds.byID[id.Uint64()].FailedAttempts++
+	return false, nil
+}
+
+func (l lockoutStub) RecordSuccess(_ context.Context, id meta.ID) error {
+	l.creds.byID[id.Uint64()].FailedAttempts = 0
+	return nil
+}
+
+// throttleStub 按登录名计数，达到 max 后拒绝
+type throttleStub struct {
+	max      int
+	failures map[string]int
+}
+
+func (t *throttleStub) Check(_ context.Context, attempt authentication.LoginAttempt) (authentication.ThrottleVerdict, error) {
+	return authentication.ThrottleVerdict{Blocked: t.failures[attempt.Subject.Value] >= t.max}, nil
+}
+
+func (t *throttleStub) RecordFailure(ctx context.Context, attempt authentication.LoginAttempt) (authentication.ThrottleVerdict, error) {
+	t.failures[attempt.Subject.Value]++
+	return t.Check(ctx, attempt)
+}
+
+func (t *throttleStub) Reset(_ context.Context, subject authentication.ThrottleSubject) error {
+	delete(t.failures, subject.Value)
+	return nil
+}
+
+type fixture struct {
+	svc      PasswordApplicationService
+	creds    *credentialRepoStub
+	history  *historyRepoStub
+	otp      *otpStub
+	sms      *smsStub
+	sessions *sessionManagerStub
+	throttle *throttleStub
+}
+
+func newFixture() *fixture {
+	return newFixtureWithPolicy(credDomain.DefaultPasswordPolicy())
+}
+
+func newFixtureWithPolicy(policy credDomain.PasswordPolicy) *fixture {
+	phone := "phone"
+	lockedUntil := time.Now().Add(time.Hour)
+	creds := &credentialRepoStub{byID: map[uint64]*credDomain.Credential{
+		1: {ID: meta.FromUint64(1), AccountID: meta.FromUint64(100), Material: []byte("hash:old-pass"), Status: credDomain.CredStatusEnabled, FailedAttempts: 3, LockedUntil: &lockedUntil},
+		2: {ID: meta.FromUint64(2), AccountID: meta.FromUint64(100), IDP: &phone, IDPIdentifier: "+8613800138000", Status: credDomain.CredStatusEnabled},
+	}}
+	otp := &otpStub{codes: map[string]string{}}
+	sms := &smsStub{}
+	sessions := &sessionManagerStub{revoked: map[string]string{}, revokedSession: map[string]string{}}
+	history := &historyRepoStub{}
+	validator := credDomain.NewPasswordPolicyValidator(
+		&credDomain.StaticPasswordPolicyProvider{Default: policy}, nil, plainHasher{})
+	throttle := &throttleStub{max: 3, failures: map[string]int{}}
+	passwords := passwordCredentialStub{creds: creds, policy: policy}
+	authenticater := authentication.NewAuthenticater(passwords, usernameRepoStub{}, plainHasher{}, otp, nil, nil).
+		WithPasswordExpiry(passwords).
+		WithLoginThrottle(throttle).
+		WithCredentialLockout(lockoutStub{creds: creds})
+	svc := NewPasswordApplicationService(uowStub{creds, history}, plainHasher{}, otp,
+		&loginprep.PhoneOTPDeps{Store: otp, Gate: otp, SMS: sms}, sessions, validator, usernameRepoStub{}, authenticater)
+	return &fixture{svc: svc, creds: creds, history: history, otp: otp, sms: sms, sessions: sessions, throttle: throttle}
+}
+
+func TestChangePasswordRequiresCurrentPassword(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+
+	err := f.svc.ChangePassword(ctx, ChangePasswordRequest{AccountID: meta.FromUint64(100), CurrentPassword: "wrong", NewPassword: "new-pass"})
+	require.Error(t, err)
+	assert.True(t, perrors.IsCode(err, code.ErrPasswordIncorrect))
+	assert.Empty(t, f.sessions.revoked)
+
+	err = f.svc.ChangePassword(ctx, ChangePasswordRequest{AccountID: meta.FromUint64(100), CurrentPassword: "old-pass", NewPassword: "new-pass"})
+	require.NoError(t, err)
+	assert.Equal(t, "hash:new-pass", string(f.creds.byID[1].Material))
+	assert.Equal(t, "password_change", f.sessions.revoked["100"])
+	// 修改密码不解除失败锁定
+	assert.Equal(t, 3, f.creds.byID[1].FailedAttempts)
+}
+
+func TestChangePasswordKeepsCurrentSession(t *testing.T) {
+	f := newFixture()
+	f.sessions.active = []*sessiondomain.Session{{SessionID: "current"}, {SessionID: "laptop"}, {SessionID: "phone"}}
+
+	err := f.svc.ChangePassword(context.Background(), ChangePasswordRequest{
+		AccountID: meta.FromUint64(100), SessionID: "current", CurrentPassword: "old-pass", NewPassword: "new-pass",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"laptop": "password_change", "phone": "password_change"}, f.sessions.revokedSession)
+	assert.Empty(t, f.sessions.revoked, "携带当前会话时不整体撤销")
+}
+
+func TestResetPasswordWithSceneScopedCode(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+
+	require.NoError(t, f.svc.SendResetCode(ctx, "13800138000"))
+	assert.Equal(t, []string{"reset_password"}, f.sms.scenes)
+	resetCode := f.otp.codes["+8613800138000|reset_password"]
+	require.NotEmpty(t, resetCode)
+
+	// 登录场景的验证码不能用于重置
+	f.otp.codes["+8613800138000|login"] = "000000"
+	err := f.svc.ResetPassword(ctx, ResetPasswordRequest{Phone: "13800138000", Code: "000000", NewPassword: "new-pass"})
+	assert.True(t, perrors.IsCode(err, code.ErrOTPInvalid))
+
+	err = f.svc.ResetPassword(ctx, ResetPasswordRequest{Phone: "13800138000", Code: resetCode, NewPassword: "new-pass"})
+	require.NoError(t, err)
+	cred := f.creds.byID[1]
+	assert.Equal(t, "hash:new-pass", string(cred.Material))
+	assert.Zero(t, cred.FailedAttempts)
+	assert.Nil(t, cred.LockedUntil)
+	assert.Equal(t, "password_reset", f.sessions.revoked["100"])
+
+	// 验证码一次性
+	err = f.svc.ResetPassword(ctx, ResetPasswordRequest{Phone: "13800138000", Code: resetCode, NewPassword: "again"})
+	assert.True(t, perrors.IsCode(err, code.ErrOTPInvalid))
+}
+
+func TestChangePasswordEnforcesPolicy(t *testing.T) {
+	f := newFixtureWithPolicy(credDomain.PasswordPolicy{MinLength: 8, MinCharClasses: 2, HistorySize: 3})
+	ctx := context.Background()
+	change := func(current, next string) error {
+		return f.svc.ChangePassword(ctx, ChangePasswordRequest{AccountID: meta.FromUint64(100), CurrentPassword: current, NewPassword: next})
+	}
+
+	assert.True(t, perrors.IsCode(change("old-pass", "short"), code.ErrPasswordTooShort))
+	assert.True(t, perrors.IsCode(change("old-pass", "alllowercase"), code.ErrPasswordTooWeak))
+
+	require.NoError(t, change("old-pass", "new-pass-1"))
+	require.NotNil(t, f.creds.byID[1].PasswordChangedAt)
+	require.NoError(t, change("new-pass-1", "new-pass-2"))
+	assert.Len(t, f.history.entries, 2)
+
+	// 最近 3 个密码（含当前）均不可复用
+	assert.True(t, perrors.IsCode(change("new-pass-2", "old-pass"), code.ErrPasswordReused))
+	require.NoError(t, change("new-pass-2", "new-pass-3"))
+	// 历史仅保留 HistorySize-1 条，最早的密码重新可用
+	assert.Len(t, f.history.entries, 2)
+	require.NoError(t, change("new-pass-3", "old-pass"))
+}
+
+func TestChangeExpiredPassword(t *testing.T) {
+	f := newFixtureWithPolicy(credDomain.PasswordPolicy{MinLength: 8, MaxAge: 24 * time.Hour})
+	ctx := context.Background()
+	req := ExpiredPasswordChangeRequest{Username: "alice", CurrentPassword: "old-pass", NewPassword: "new-pass"}
+
+	recent := time.Now().Add(-time.Hour)
+	f.creds.byID[1].PasswordChangedAt = &recent
+	f.creds.byID[1].LockedUntil = nil
+	err := f.svc.ChangeExpiredPassword(ctx, req)
+	assert.True(t, perrors.IsCode(err, code.ErrInvalidArgument))
+
+	stale := time.Now().Add(-48 * time.Hour)
+	f.creds.byID[1].PasswordChangedAt = &stale
+	err = f.svc.ChangeExpiredPassword(ctx, ExpiredPasswordChangeRequest{Username: "bob", CurrentPassword: "old-pass", NewPassword: "new-pass"})
+	assert.True(t, perrors.IsCode(err, code.ErrPasswordIncorrect))
+
+	require.NoError(t, f.svc.ChangeExpiredPassword(ctx, req))
+	assert.Equal(t, "hash:new-pass", string(f.creds.byID[1].Material))
+	assert.True(t, f.creds.byID[1].PasswordChangedAt.After(stale))
+	assert.Equal(t, "password_expired", f.sessions.revoked["100"])
+}
+
+func TestChangeExpiredPasswordSharesLoginGuards(t *testing.T) {
+	f := newFixtureWithPolicy(credDomain.PasswordPolicy{MinLength: 8, MaxAge: 24 * time.Hour})
+	ctx := context.Background()
+	stale := time.Now().Add(-48 * time.Hour)
+	f.creds.byID[1].PasswordChangedAt = &stale
+	change := func(current string) error {
+		return f.svc.ChangeExpiredPassword(ctx, ExpiredPasswordChangeRequest{Username: "alice", CurrentPassword: current, NewPassword: "new-pass"})
+	}
+
+	// 凭据锁定期内即使密码正确也拒绝，且不暴露锁定状态
+	assert.True(t, perrors.IsCode(change("old-pass"), code.ErrPasswordIncorrect))
+	assert.Equal(t, "hash:old-pass", string(f.creds.byID[1].Material))
+
+	f.creds.byID[1].LockedUntil = nil
+	f.creds.byID[1].FailedAttempts = 0
+	for i := 0; i < 3; i++ {
+		assert.True(t, perrors.IsCode(change("wrong-pass"), code.ErrPasswordIncorrect))
+	}
+	assert.Equal(t, 3, f.throttle.failures["alice"])
+	assert.Equal(t, 3, f.creds.byID[1].FailedAttempts)
+
+	// 节流生效后先于密码与过期检查拒绝
+	assert.True(t, perrors.IsCode(change("old-pass"), code.ErrPasswordIncorrect))
+	assert.Equal(t, "hash:old-pass", string(f.creds.byID[1].Material))
+	assert.Empty(t, f.sessions.revoked)
+
+	require.NoError(t, f.throttle.Reset(ctx, authentication.ThrottleSubject{Kind: authentication.ThrottleSubjectUsername, Value: "alice"}))
+	require.NoError(t, change("old-pass"))
+	assert.Equal(t, "hash:new-pass", string(f.creds.byID[1].Material))
+	assert.Zero(t, f.creds.byID[1].FailedAttempts)
+}
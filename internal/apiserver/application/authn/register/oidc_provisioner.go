@@ -0,0 +1,72 @@
+package register
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// oidcAccountProvisioner 上游 OIDC 即时开通适配器，复用统一注册流程创建用户、账户与凭据
+type oidcAccountProvisioner struct {
+	registerService RegisterApplicationService
+}
+
+var _ authentication.OIDCAccountProvisioner = (*oidcAccountProvisioner)(nil)
+
+// NewOIDCAccountProvisioner 创建上游 OIDC 即时开通适配器
+func NewOIDCAccountProvisioner(registerService RegisterApplicationService) authentication.OIDCAccountProvisioner {
+	return &oidcAccountProvisioner{registerService: registerService}
+}
+
+// ProvisionOIDCAccount 为首次登录的上游身份开通账户
+func (p *oidcAccountProvisioner) ProvisionOIDCAccount(ctx context.Context, identity authentication.OIDCIdentity) (meta.ID, meta.ID, meta.ID, error) {
+	provider, subject := identity.Provider, identity.Subject
+	req := RegisterRequest{
+		Name:           firstNonEmpty(identity.Name, identity.Username, identity.Email, subject),
+		AccountType:    account.TypeOIDC,
+		CredentialType: CredTypeOIDC,
+		OIDCProvider:   &provider,
+		OIDCSubject:    &subject,
+	}
+
+	// 仅采信上游已验证的邮箱与手机号，避免借未验证的手机号关联到他人的已有用户
+	if verified(identity.Claims, "email_verified") {
+		if email, err := meta.NewEmail(identity.Email); err == nil {
+			req.Email = email
+		}
+	}
+	if verified(identity.Claims, "phone_number_verified") {
+		if phone, err := meta.NewPhone(identity.Phone); err == nil {
+			req.Phone = phone
+		}
+	}
+	if identity.Username != "" {
+		req.Profile = map[string]string{"username": identity.Username}
+	}
+	if raw, err := json.Marshal(identity.Claims); err == nil {
+		req.ParamsJSON = raw
+	}
+
+	result, err := p.registerService.Register(ctx, req)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return result.AccountID, result.UserID, result.CredentialID, nil
+}
+
+func verified(claims map[string]any, name string) bool {
+	v, _ := claims[name].(bool)
+	return v
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
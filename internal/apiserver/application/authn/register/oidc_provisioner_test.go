@@ -0,0 +1,52 @@
+package register
+
+import (
+	"context"
+	"testing"
+
+	accountdomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+	"github.com/stretchr/testify/require"
+)
+
+type registerServiceStub struct {
+	last RegisterRequest
+}
+
+func (s *registerServiceStub) Register(_ context.Context, req RegisterRequest) (*RegisterResult, error) {
+	s.last = req
+	return &RegisterResult{AccountID: meta.FromUint64(1), UserID: meta.FromUint64(2), CredentialID: meta.FromUint64(3)}, nil
+}
+
+func TestOIDCAccountProvisioner_OnlyTrustsVerifiedContacts(t *testing.T) {
+	svc := &registerServiceStub{}
+	provisioner := NewOIDCAccountProvisioner(svc)
+
+	accountID, userID, credentialID, err := provisioner.ProvisionOIDCAccount(context.Background(), authentication.OIDCIdentity{
+		Provider: "corp-sso",
+		Subject:  "s-1",
+		Username: "alice",
+		Email:    "alice@example.com",
+		Phone:    "+8613800000000",
+		Claims: map[string]any{
+			"sub":                   "s-1",
+			"email_verified":        true,
+			"phone_number_verified": false,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), accountID.Uint64())
+	require.Equal(t, uint64(2), userID.Uint64())
+	require.Equal(t, uint64(3), credentialID.Uint64())
+
+	req := svc.last
+	require.Equal(t, accountdomain.TypeOIDC, req.AccountType)
+	require.Equal(t, CredTypeOIDC, req.CredentialType)
+	require.Equal(t, "corp-sso", *req.OIDCProvider)
+	require.Equal(t, "s-1", *req.OIDCSubject)
+	require.Equal(t, "alice", req.Name)
+	require.Equal(t, "alice@example.com", req.Email.String())
+	require.True(t, req.Phone.IsEmpty())
+	require.JSONEq(t, `{"sub":"s-1","email_verified":true,"phone_number_verified":false}`, string(req.ParamsJSON))
+}
@@ -0,0 +1,51 @@
+package register
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// samlAccountProvisioner SAML 即时开通适配器，复用统一注册流程创建用户、账户与凭据
+type samlAccountProvisioner struct {
+	registerService RegisterApplicationService
+}
+
+var _ authentication.SAMLAccountProvisioner = (*samlAccountProvisioner)(nil)
+
+// NewSAMLAccountProvisioner 创建 SAML 即时开通适配器
+func NewSAMLAccountProvisioner(registerService RegisterApplicationService) authentication.SAMLAccountProvisioner {
+	return &samlAccountProvisioner{registerService: registerService}
+}
+
+// ProvisionSAMLAccount 为首次登录的 IdP 用户开通账户
+func (p *samlAccountProvisioner) ProvisionSAMLAccount(ctx context.Context, identity authentication.SAMLIdentity) (meta.ID, meta.ID, meta.ID, error) {
+	idp, nameID := identity.IdP, identity.NameID
+	req := RegisterRequest{
+		Name:           firstNonEmpty(identity.Name, identity.Username, identity.Email, nameID),
+		AccountType:    account.TypeSAML,
+		CredentialType: CredTypeSAML,
+		SAMLIdP:        &idp,
+		SAMLNameID:     &nameID,
+	}
+
+	// SAML 属性没有验证标记，手机号只保留在 Claims 中，不参与按手机号关联已有用户
+	if email, err := meta.NewEmail(identity.Email); err == nil {
+		req.Email = email
+	}
+	if identity.Username != "" {
+		req.Profile = map[string]string{"username": identity.Username}
+	}
+	if raw, err := json.Marshal(identity.Claims); err == nil {
+		req.ParamsJSON = raw
+	}
+
+	result, err := p.registerService.Register(ctx, req)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return result.AccountID, result.UserID, result.CredentialID, nil
+}
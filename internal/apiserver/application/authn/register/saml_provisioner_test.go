@@ -0,0 +1,35 @@
+package register
+
+import (
+	"context"
+	"testing"
+
+	accountdomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSAMLAccountProvisioner_DoesNotLinkByPhone(t *testing.T) {
+	svc := &registerServiceStub{}
+	provisioner := NewSAMLAccountProvisioner(svc)
+
+	_, _, credentialID, err := provisioner.ProvisionSAMLAccount(context.Background(), authentication.SAMLIdentity{
+		IdP:    "city-hospital",
+		NameID: "u-1001",
+		Email:  "doctor@hospital.example",
+		Phone:  "+8613800000000",
+		Claims: map[string]any{"email": "doctor@hospital.example", "phone": "+8613800000000"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), credentialID.Uint64())
+
+	req := svc.last
+	require.Equal(t, accountdomain.TypeSAML, req.AccountType)
+	require.Equal(t, CredTypeSAML, req.CredentialType)
+	require.Equal(t, "city-hospital", *req.SAMLIdP)
+	require.Equal(t, "u-1001", *req.SAMLNameID)
+	require.Equal(t, "doctor@hospital.example", req.Name)
+	require.Equal(t, "doctor@hospital.example", req.Email.String())
+	require.True(t, req.Phone.IsEmpty())
+	require.JSONEq(t, `{"email":"doctor@hospital.example","phone":"+8613800000000"}`, string(req.ParamsJSON))
+}
@@ -50,10 +50,21 @@ type RegisterRequest struct {
 	WechatOpenID  *string // 微信OpenID（可选，如果有就不需要 code2session）
 	WechatUnionID *string // 微信UnionID（可选）
 
+	// ========== 微信公众号账户参数（复用 WechatAppID/OpenID/UnionID）==========
+	WechatOAuthCode *string // 网页授权 code（当 AccountType = TypeWcOffi 且无 OpenID 时必须）
+
 	// ========== 企业微信账户参数 ==========
 	WecomCorpID *string // 企业CorpID（当 AccountType = TypeWcCom 时必须）
 	WecomUserID *string // 企业微信UserID（当 AccountType = TypeWcCom 时必须）
 
+	// ========== 上游 OIDC 账户参数 ==========
+	OIDCProvider *string // 提供商 slug（当 AccountType = TypeOIDC 时必须）
+	OIDCSubject  *string // 上游 sub（当 AccountType = TypeOIDC 时必须，身份已由 id_token 校验）
+
+	// ========== SAML 账户参数 ==========
+	SAMLIdP    *string // IdP 登记 slug（当 AccountType = TypeSAML 时必须）
+	SAMLNameID *string // 断言 NameID（当 AccountType = TypeSAML 时必须，身份已由断言签名校验）
+
 	// ========== 账户元数据（可选）==========
 	Profile    map[string]string // 用户资料（昵称、头像等）
 	Meta       map[string]string // 额外元数据
@@ -64,10 +75,13 @@ type RegisterRequest struct {
 type CredentialType string
 
 const (
-	CredTypePassword CredentialType = "password" // 密码
-	CredTypePhone    CredentialType = "phone"    // 手机号OTP
-	CredTypeWechat   CredentialType = "wechat"   // 微信小程序
-	CredTypeWecom    CredentialType = "wecom"    // 企业微信
+	CredTypePassword CredentialType = "password"  // 密码
+	CredTypePhone    CredentialType = "phone"     // 手机号OTP
+	CredTypeWechat   CredentialType = "wechat"    // 微信小程序
+	CredTypeWechatMP CredentialType = "wechat_mp" // 微信公众号
+	CredTypeWecom    CredentialType = "wecom"     // 企业微信
+	CredTypeOIDC     CredentialType = "oidc"      // 上游 OIDC
+	CredTypeSAML     CredentialType = "saml"      // SAML 单点登录
 )
 
 // RegisterResult 注册结果
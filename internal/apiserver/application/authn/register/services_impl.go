@@ -7,6 +7,7 @@ import (
 
 	perrors "github.com/FangcunMount/component-base/pkg/errors"
 	"github.com/FangcunMount/component-base/pkg/logger"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/loginprep"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/uow"
 	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
@@ -21,12 +22,12 @@ import (
 // ============= RegisterApplicationService 实现 =============
 
 type registerApplicationService struct {
-	uow              uow.UnitOfWork
-	userRepo         userDomain.Repository
-	hasher           authentication.PasswordHasher
-	idp              authentication.IdentityProvider
-	wechatAppQuerier idpPort.Repository
-	secretVault      idpPort.SecretVault
+	uow            uow.UnitOfWork
+	userRepo       userDomain.Repository
+	hasher         authentication.PasswordHasher
+	idp            authentication.IdentityProvider
+	apps           *loginprep.IDPAppResolver
+	passwordPolicy *credDomain.PasswordPolicyValidator
 }
 
 var _ RegisterApplicationService = (*registerApplicationService)(nil)
@@ -38,14 +39,18 @@ func NewRegisterApplicationService(
 	userRepo userDomain.Repository,
 	wechatAppQuerier idpPort.Repository,
 	secretVault idpPort.SecretVault,
+	passwordPolicy *credDomain.PasswordPolicyValidator,
 ) RegisterApplicationService {
+	if passwordPolicy == nil {
+		passwordPolicy = credDomain.NewPasswordPolicyValidator(nil, nil, nil)
+	}
 	return &registerApplicationService{
-		uow:              uow,
-		userRepo:         userRepo,
-		hasher:           hasher,
-		idp:              idp,
-		wechatAppQuerier: wechatAppQuerier,
-		secretVault:      secretVault,
+		uow:            uow,
+		userRepo:       userRepo,
+		hasher:         hasher,
+		idp:            idp,
+		apps:           loginprep.NewIDPAppResolver(wechatAppQuerier, nil, secretVault),
+		passwordPolicy: passwordPolicy,
 	}
 }
 
@@ -102,7 +107,7 @@ func (s *registerApplicationService) Register(ctx context.Context, req RegisterR
 			user = u
 			isNewUser = false
 		} else {
-			openID, unionID, err := s.resolveWechatIDs(ctx, req)
+			openID, unionID, err := s.resolveWechatIDs(ctx, &req)
 			if err != nil {
 				l.Errorw("解析微信身份失败",
 					"action", logger.ActionRegister,
@@ -120,6 +125,10 @@ func (s *registerApplicationService) Register(ctx context.Context, req RegisterR
 			if openID != "" && req.WechatJsCode != nil {
 				req.WechatJsCode = nil
 			}
+			// 网页授权 code 只能使用一次，解析后改用 OpenID 建账户
+			if openID != "" && req.WechatOAuthCode != nil {
+				req.WechatOAuthCode = nil
+			}
 
 			var errGet error
 			user, isNewUser, errGet = s.createOrGetUser(ctx, userRepo, accountRepo, req, openID, unionID)
@@ -308,6 +317,9 @@ func (s *registerApplicationService) issueCredential(
 		if req.Password == nil || *req.Password == "" {
 			return nil, perrors.WithCode(code.ErrInvalidArgument, "password is required")
 		}
+		if err := s.passwordPolicy.Validate(ctx, req.ScopedTenantID, *req.Password, nil); err != nil {
+			return nil, err
+		}
 		return issuer.IssuePassword(ctx, credDomain.IssuePasswordRequest{
 			AccountID:     accountID,
 			PlainPassword: *req.Password,
@@ -341,6 +353,27 @@ func (s *registerApplicationService) issueCredential(
 			ParamsJSON:    req.ParamsJSON,
 		})
 
+	case CredTypeWechatMP:
+		// 颁发微信公众号凭据
+		if creationParams == nil || creationParams.OpenID == "" {
+			return nil, perrors.WithCode(code.ErrInvalidArgument, "openid is required for wechat mp credential")
+		}
+		// 与小程序一致，优先使用 UnionID 作为标识符
+		idpIdentifier := creationParams.OpenID
+		if creationParams.UnionID != "" {
+			idpIdentifier = creationParams.UnionID
+		}
+		appID := ""
+		if req.WechatAppID != nil {
+			appID = *req.WechatAppID
+		}
+		return issuer.IssueWechatMP(ctx, credDomain.IssueOAuthRequest{
+			AccountID:     accountID,
+			IDPIdentifier: idpIdentifier,
+			AppID:         appID,
+			ParamsJSON:    req.ParamsJSON,
+		})
+
 	case CredTypeWecom:
 		// 颁发企业微信凭据
 		if req.WecomUserID == nil || *req.WecomUserID == "" {
@@ -357,6 +390,30 @@ func (s *registerApplicationService) issueCredential(
 			ParamsJSON:    req.ParamsJSON,
 		})
 
+	case CredTypeOIDC:
+		// 颁发上游 OIDC 凭据
+		if req.OIDCProvider == nil || *req.OIDCProvider == "" || req.OIDCSubject == nil || *req.OIDCSubject == "" {
+			return nil, perrors.WithCode(code.ErrInvalidArgument, "oidc provider and subject are required")
+		}
+		return issuer.IssueOIDC(ctx, credDomain.IssueOAuthRequest{
+			AccountID:     accountID,
+			IDPIdentifier: *req.OIDCSubject,
+			AppID:         *req.OIDCProvider,
+			ParamsJSON:    req.ParamsJSON,
+		})
+
+	case CredTypeSAML:
+		// 颁发 SAML 单点登录凭据
+		if req.SAMLIdP == nil || *req.SAMLIdP == "" || req.SAMLNameID == nil || *req.SAMLNameID == "" {
+			return nil, perrors.WithCode(code.ErrInvalidArgument, "saml idp and name id are required")
+		}
+		return issuer.IssueSAML(ctx, credDomain.IssueOAuthRequest{
+			AccountID:     accountID,
+			IDPIdentifier: *req.SAMLNameID,
+			AppID:         *req.SAMLIdP,
+			ParamsJSON:    req.ParamsJSON,
+		})
+
 	default:
 		return nil, perrors.WithCode(code.ErrInvalidArgument, "unsupported credential type: %s", req.CredentialType)
 	}
@@ -365,49 +422,36 @@ func (s *registerApplicationService) issueCredential(
 // toDomainInput 将应用层DTO转换为领域层输入，必要时查询 AppSecret
 func (s *registerApplicationService) toDomainInput(ctx context.Context, req RegisterRequest, userID meta.ID) (domain.CreationInput, error) {
 	input := domain.CreationInput{
-		UserID:         userID,
-		Phone:          req.Phone,
-		Email:          req.Email,
-		OperaLoginID:   strings.TrimSpace(req.OperaLoginID),
-		ScopedTenantID: req.ScopedTenantID,
-		AccountType:    req.AccountType,
-		WechatAppID:    req.WechatAppID,
-		WechatJsCode:   req.WechatJsCode,
-		WechatOpenID:   req.WechatOpenID,
-		WechatUnionID:  req.WechatUnionID,
-		WecomCorpID:    req.WecomCorpID,
-		WecomUserID:    req.WecomUserID,
-		Profile:        req.Profile,
-		Meta:           req.Meta,
-		ParamsJSON:     req.ParamsJSON,
-	}
-
-	// 如果是微信小程序注册且提供了 JsCode，需要查询 AppSecret
-	if req.AccountType == domain.TypeWcMinip && req.WechatAppID != nil && req.WechatJsCode != nil {
-		if s.wechatAppQuerier == nil || s.secretVault == nil {
-			return domain.CreationInput{}, perrors.WithCode(code.ErrInvalidArgument, "wechat app configuration service not available")
-		}
-
-		wechatApp, err := s.wechatAppQuerier.GetByAppID(ctx, *req.WechatAppID)
-		if err != nil {
-			return domain.CreationInput{}, perrors.WithCode(code.ErrInvalidArgument, "failed to query wechat app: %v", err)
-		}
-		if wechatApp == nil {
-			return domain.CreationInput{}, perrors.WithCode(code.ErrInvalidArgument, "wechat app not found: %s", *req.WechatAppID)
-		}
-		if !wechatApp.IsEnabled() {
-			return domain.CreationInput{}, perrors.WithCode(code.ErrInvalidArgument, "wechat app is disabled: %s", *req.WechatAppID)
-		}
-		if wechatApp.Cred == nil || wechatApp.Cred.Auth == nil {
-			return domain.CreationInput{}, perrors.WithCode(code.ErrInvalidArgument, "wechat app credentials not found")
-		}
+		UserID:          userID,
+		Phone:           req.Phone,
+		Email:           req.Email,
+		OperaLoginID:    strings.TrimSpace(req.OperaLoginID),
+		ScopedTenantID:  req.ScopedTenantID,
+		AccountType:     req.AccountType,
+		WechatAppID:     req.WechatAppID,
+		WechatJsCode:    req.WechatJsCode,
+		WechatOpenID:    req.WechatOpenID,
+		WechatUnionID:   req.WechatUnionID,
+		WechatOAuthCode: req.WechatOAuthCode,
+		WecomCorpID:     req.WecomCorpID,
+		WecomUserID:     req.WecomUserID,
+		OIDCProvider:    req.OIDCProvider,
+		OIDCSubject:     req.OIDCSubject,
+		SAMLIdP:         req.SAMLIdP,
+		SAMLNameID:      req.SAMLNameID,
+		Profile:         req.Profile,
+		Meta:            req.Meta,
+		ParamsJSON:      req.ParamsJSON,
+	}
 
-		appSecretPlain, err := s.secretVault.Decrypt(ctx, wechatApp.Cred.Auth.AppSecretCipher)
+	// 微信小程序 JsCode / 公众号网页授权 code 换身份前需要查询 AppSecret
+	needSecret := (req.AccountType == domain.TypeWcMinip && req.WechatJsCode != nil) ||
+		(req.AccountType == domain.TypeWcOffi && req.WechatOAuthCode != nil)
+	if needSecret && req.WechatAppID != nil {
+		appSecret, err := s.apps.WechatAppSecret(ctx, *req.WechatAppID, "")
 		if err != nil {
-			return domain.CreationInput{}, perrors.WithCode(code.ErrInvalidArgument, "failed to decrypt app secret: %v", err)
+			return domain.CreationInput{}, err
 		}
-
-		appSecret := string(appSecretPlain)
 		input.WechatAppSecret = &appSecret
 	}
 
@@ -429,9 +473,9 @@ func (s *registerApplicationService) createOrGetUser(
 		return nil, false, perrors.WithCode(code.ErrInternalServerError, "user repository is not initialized")
 	}
 
-	// 如果是微信小程序注册且提供了 UnionID，则通过 UnionID 查找现有用户
-	if req.AccountType == domain.TypeWcMinip && accountRepo != nil {
-		// 如果是微信小程序注册且提供了 UnionID，则通过 UnionID 查找现有用户
+	// 微信小程序 / 公众号注册：同一开放平台下 UnionID 一致，据此关联已有用户
+	if (req.AccountType == domain.TypeWcMinip || req.AccountType == domain.TypeWcOffi) && accountRepo != nil {
+		// 提供了 UnionID，则通过 UnionID 查找现有用户（公众号可借此关联小程序账户）
 		if wechatUnionID != "" {
 			account, err := accountRepo.GetByUniqueID(ctx, domain.UnionID(wechatUnionID))
 			if err != nil && !perrors.Is(err, gorm.ErrRecordNotFound) {
@@ -442,7 +486,7 @@ func (s *registerApplicationService) createOrGetUser(
 			}
 		}
 
-		// 如果是微信小程序注册且提供了 OpenID，则通过 OpenID 查找现有用户
+		// 提供了 OpenID，则通过 OpenID 查找现有用户
 		if wechatOpenID != "" && req.WechatAppID != nil && *req.WechatAppID != "" {
 			externalID := domain.ExternalID(fmt.Sprintf("%s@%s", wechatOpenID, *req.WechatAppID))
 			appID := domain.AppId(*req.WechatAppID)
@@ -456,6 +500,30 @@ func (s *registerApplicationService) createOrGetUser(
 		}
 	}
 
+	// 上游 OIDC：同一提供商下 sub 唯一，重复开通时复用已有账户的用户
+	if req.AccountType == domain.TypeOIDC && accountRepo != nil &&
+		req.OIDCProvider != nil && req.OIDCSubject != nil {
+		account, err := accountRepo.GetByExternalIDAppId(ctx, domain.ExternalID(*req.OIDCSubject), domain.AppId(*req.OIDCProvider))
+		if err != nil && !perrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, err
+		}
+		if account != nil {
+			return s.loadOrRepairUserForAccount(ctx, repo, account.UserID, req)
+		}
+	}
+
+	// SAML：同一 IdP 下 NameID 唯一，重复开通时复用已有账户的用户
+	if req.AccountType == domain.TypeSAML && accountRepo != nil &&
+		req.SAMLIdP != nil && req.SAMLNameID != nil {
+		account, err := accountRepo.GetByExternalIDAppId(ctx, domain.ExternalID(*req.SAMLNameID), domain.AppId(*req.SAMLIdP))
+		if err != nil && !perrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, err
+		}
+		if account != nil {
+			return s.loadOrRepairUserForAccount(ctx, repo, account.UserID, req)
+		}
+	}
+
 	// 通过手机号查找现有用户
 	if !req.Phone.IsEmpty() {
 		existingUser, err := repo.FindByPhone(ctx, req.Phone)
@@ -526,9 +594,10 @@ func (s *registerApplicationService) loadOrRepairUserForAccount(
 	return recovered, false, nil
 }
 
-// resolveWechatIDs 解析微信小程序的 OpenID 和 UnionID
-func (s *registerApplicationService) resolveWechatIDs(ctx context.Context, req RegisterRequest) (string, string, error) {
-	if req.AccountType != domain.TypeWcMinip {
+// resolveWechatIDs 解析微信小程序 / 公众号的 OpenID 和 UnionID
+// 公众号 snsapi_userinfo 授权返回的昵称头像补入 req.Profile
+func (s *registerApplicationService) resolveWechatIDs(ctx context.Context, req *RegisterRequest) (string, string, error) {
+	if req.AccountType != domain.TypeWcMinip && req.AccountType != domain.TypeWcOffi {
 		return "", "", nil
 	}
 	if req.WechatOpenID != nil && *req.WechatOpenID != "" {
@@ -539,39 +608,57 @@ func (s *registerApplicationService) resolveWechatIDs(ctx context.Context, req R
 		}
 		return openID, unionID, nil
 	}
-	if req.WechatAppID == nil || *req.WechatAppID == "" || req.WechatJsCode == nil || *req.WechatJsCode == "" {
+	if req.WechatAppID == nil || *req.WechatAppID == "" {
 		return "", "", nil
 	}
-	if s.wechatAppQuerier == nil || s.secretVault == nil {
-		return "", "", perrors.WithCode(code.ErrInvalidArgument, "wechat app configuration service not available")
-	}
 
-	wechatApp, err := s.wechatAppQuerier.GetByAppID(ctx, *req.WechatAppID)
-	if err != nil {
-		return "", "", perrors.WithCode(code.ErrInvalidArgument, "failed to query wechat app: %v", err)
-	}
-	if wechatApp == nil {
-		return "", "", perrors.WithCode(code.ErrInvalidArgument, "wechat app not found: %s", *req.WechatAppID)
-	}
-	if !wechatApp.IsEnabled() {
-		return "", "", perrors.WithCode(code.ErrInvalidArgument, "wechat app is disabled: %s", *req.WechatAppID)
-	}
-	if wechatApp.Cred == nil || wechatApp.Cred.Auth == nil {
-		return "", "", perrors.WithCode(code.ErrInvalidArgument, "wechat app credentials not found")
+	if req.AccountType == domain.TypeWcOffi {
+		if req.WechatOAuthCode == nil || *req.WechatOAuthCode == "" {
+			return "", "", nil
+		}
+		appSecret, err := s.apps.WechatAppSecret(ctx, *req.WechatAppID, "")
+		if err != nil {
+			return "", "", err
+		}
+		identity, err := s.idp.ExchangeWxMPCode(ctx, *req.WechatAppID, appSecret, *req.WechatOAuthCode)
+		if err != nil {
+			return "", "", perrors.WithCode(code.ErrInvalidCredential, "failed to exchange wechat oauth code: %v", err)
+		}
+		req.Profile = withDefaultProfile(req.Profile, "nickname", identity.Nickname)
+		req.Profile = withDefaultProfile(req.Profile, "avatar", identity.AvatarURL)
+		return identity.OpenID, identity.UnionID, nil
 	}
 
-	appSecretPlain, err := s.secretVault.Decrypt(ctx, wechatApp.Cred.Auth.AppSecretCipher)
+	if req.WechatJsCode == nil || *req.WechatJsCode == "" {
+		return "", "", nil
+	}
+	appSecret, err := s.apps.WechatAppSecret(ctx, *req.WechatAppID, "")
 	if err != nil {
-		return "", "", perrors.WithCode(code.ErrInvalidArgument, "failed to decrypt app secret: %v", err)
+		return "", "", err
 	}
-
-	openID, unionID, err := s.idp.ExchangeWxMinipCode(ctx, *req.WechatAppID, string(appSecretPlain), *req.WechatJsCode)
+	openID, unionID, err := s.idp.ExchangeWxMinipCode(ctx, *req.WechatAppID, appSecret, *req.WechatJsCode)
 	if err != nil {
 		return "", "", perrors.WithCode(code.ErrInvalidCredential, "failed to call wechat code2session: %v", err)
 	}
 	return openID, unionID, nil
 }
 
+// withDefaultProfile 资料项缺省时补入 value
+func withDefaultProfile(profile map[string]string, key, value string) map[string]string {
+	if value == "" {
+		return profile
+	}
+	if _, ok := profile[key]; ok {
+		return profile
+	}
+	merged := make(map[string]string, len(profile)+1)
+	for k, v := range profile {
+		merged[k] = v
+	}
+	merged[key] = value
+	return merged
+}
+
 // mapCredentialType 将应用层凭据类型映射为领域层类型
 func mapCredentialType(t CredentialType) credDomain.CredentialType {
 	switch t {
@@ -579,8 +666,14 @@ func mapCredentialType(t CredentialType) credDomain.CredentialType {
 		return credDomain.CredPhoneOTP
 	case CredTypeWechat:
 		return credDomain.CredOAuthWxMinip
+	case CredTypeWechatMP:
+		return credDomain.CredOAuthWxMP
 	case CredTypeWecom:
 		return credDomain.CredOAuthWecom
+	case CredTypeOIDC:
+		return credDomain.CredOAuthOIDC
+	case CredTypeSAML:
+		return credDomain.CredSAML
 	default:
 		return credDomain.CredPassword
 	}
@@ -72,6 +72,7 @@ func (s *accountRepoStub) UpdateProfile(context.Context, meta.ID, map[string]str
 	return nil
 }
 func (s *accountRepoStub) UpdateMeta(context.Context, meta.ID, map[string]string) error { return nil }
+func (s *accountRepoStub) UpdateUserID(context.Context, meta.ID, meta.ID) error         { return nil }
 func (s *accountRepoStub) GetByID(context.Context, meta.ID) (*accountdomain.Account, error) {
 	return nil, gorm.ErrRecordNotFound
 }
@@ -87,6 +88,9 @@ func (s *accountRepoStub) GetByExternalIDAppId(_ context.Context, externalID acc
 	}
 	return nil, gorm.ErrRecordNotFound
 }
+func (s *accountRepoStub) ListByUserID(context.Context, meta.ID) ([]*accountdomain.Account, error) {
+	return nil, nil
+}
 
 func TestCreateOrGetUser_RepairsDanglingWechatAccountUser(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,74 @@
+package saml
+
+import (
+	"context"
+
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/saml"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// ============= 应用服务接口（Driving Ports）=============
+
+// SAMLIdPApplicationService SAML IdP 登记管理应用服务
+type SAMLIdPApplicationService interface {
+	// RegisterIdP 以 IdP 元数据登记 SAML 身份提供商
+	RegisterIdP(ctx context.Context, dto RegisterIdPDTO) (*IdPResult, error)
+	// GetIdP 查询 SAML IdP 登记
+	GetIdP(ctx context.Context, slug string) (*IdPResult, error)
+	// ListIdPs 列出 SAML IdP 登记
+	ListIdPs(ctx context.Context, filter ListIdPsFilter) ([]*IdPResult, error)
+	// UpdateIdP 更新 SAML IdP 登记；提交新元数据即完成证书轮换
+	UpdateIdP(ctx context.Context, slug string, dto UpdateIdPDTO) (*IdPResult, error)
+	// EnableIdP 启用 SAML IdP 登记
+	EnableIdP(ctx context.Context, slug string) (*IdPResult, error)
+	// DisableIdP 禁用 SAML IdP 登记
+	DisableIdP(ctx context.Context, slug string) (*IdPResult, error)
+}
+
+// SAMLServiceProviderApplicationService SAML SP 协议端点应用服务
+type SAMLServiceProviderApplicationService interface {
+	// Metadata 返回供 IdP 导入的 SP 元数据
+	Metadata(ctx context.Context, slug string) ([]byte, error)
+	// BeginLogin 生成跳转到 IdP 的签名 AuthnRequest 地址
+	BeginLogin(ctx context.Context, slug, relayState string) (string, error)
+}
+
+// ============= DTOs =============
+
+// RegisterIdPDTO 登记 SAML IdP DTO
+type RegisterIdPDTO struct {
+	TenantID         meta.ID           // 所属租户（必填）
+	Slug             string            // 唯一标识（必填）
+	Name             string            // 显示名称（必填）
+	MetadataXML      string            // IdP 元数据 XML（必填）
+	AttributeMapping map[string]string // SAML 属性名 -> Claims 键
+	AutoProvision    bool              // 是否即时开通账户
+}
+
+// ListIdPsFilter SAML IdP 登记列表过滤条件
+type ListIdPsFilter struct {
+	TenantID *meta.ID
+	Status   *domain.Status
+}
+
+// UpdateIdPDTO 更新 SAML IdP 登记 DTO，nil 字段保持不变
+type UpdateIdPDTO struct {
+	Name             *string
+	MetadataXML      *string
+	AttributeMapping *map[string]string
+	AutoProvision    *bool
+}
+
+// IdPResult SAML IdP 登记结果 DTO
+type IdPResult struct {
+	ID               string
+	TenantID         string
+	Slug             string
+	Name             string
+	EntityID         string
+	SSOURL           string
+	CertificateCount int
+	AttributeMapping map[string]string
+	AutoProvision    bool
+	Status           domain.Status
+}
@@ -0,0 +1,257 @@
+package saml
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/logger"
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/saml"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// ============= 应用服务实现 =============
+
+// ===========================================
+// ==== SAMLIdPApplicationService 实现 =====
+// ===========================================
+
+type samlIdPApplicationService struct {
+	repo domain.Repository
+	sp   domain.ServiceProvider
+}
+
+// NewSAMLIdPApplicationService 创建 SAML IdP 登记管理应用服务
+func NewSAMLIdPApplicationService(repo domain.Repository, sp domain.ServiceProvider) SAMLIdPApplicationService {
+	return &samlIdPApplicationService{repo: repo, sp: sp}
+}
+
+// RegisterIdP 以 IdP 元数据登记 SAML 身份提供商
+func (s *samlIdPApplicationService) RegisterIdP(ctx context.Context, dto RegisterIdPDTO) (*IdPResult, error) {
+	l := logger.L(ctx)
+	l.Debugw("登记 SAML IdP",
+		"action", logger.ActionCreate,
+		"resource", "saml_idp",
+		"slug", dto.Slug,
+		"tenant_id", dto.TenantID.String(),
+	)
+
+	name := strings.TrimSpace(dto.Name)
+	if name == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "name cannot be empty")
+	}
+
+	existing, err := s.repo.GetBySlug(ctx, dto.Slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query saml idp: %w", err)
+	}
+	if existing != nil {
+		return nil, perrors.WithCode(code.ErrSAMLIdPAlreadyExists, "saml idp already exists: %s", dto.Slug)
+	}
+
+	md, err := s.sp.ParseIdPMetadata([]byte(dto.MetadataXML))
+	if err != nil {
+		l.Warnw("SAML IdP 元数据无效",
+			"action", logger.ActionCreate,
+			"resource", "saml_idp",
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return nil, perrors.WithCode(code.ErrSAMLMetadataInvalid, "%s", err.Error())
+	}
+
+	idp := domain.NewIdentityProvider(dto.TenantID, dto.Slug, md,
+		domain.WithIdentityProviderID(meta.FromUint64(idutil.GetIntID())),
+		domain.WithIdentityProviderName(name),
+		domain.WithIdentityProviderStatus(domain.StatusEnabled), // 默认启用
+		domain.WithAttributeMapping(dto.AttributeMapping),
+		domain.WithAutoProvision(dto.AutoProvision),
+		domain.WithMetadataXML(dto.MetadataXML),
+	)
+	if err := idp.Validate(); err != nil {
+		return nil, perrors.WithCode(code.ErrSAMLMetadataInvalid, "%s", err.Error())
+	}
+
+	if err := s.repo.Create(ctx, idp); err != nil {
+		l.Errorw("持久化 SAML IdP 登记失败",
+			"action", logger.ActionCreate,
+			"resource", "saml_idp",
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return nil, err
+	}
+
+	return toIdPResult(idp), nil
+}
+
+// GetIdP 查询 SAML IdP 登记
+func (s *samlIdPApplicationService) GetIdP(ctx context.Context, slug string) (*IdPResult, error) {
+	idp, err := getIdP(ctx, s.repo, slug)
+	if err != nil {
+		return nil, err
+	}
+	return toIdPResult(idp), nil
+}
+
+// ListIdPs 列出 SAML IdP 登记
+func (s *samlIdPApplicationService) ListIdPs(ctx context.Context, filter ListIdPsFilter) ([]*IdPResult, error) {
+	idps, err := s.repo.List(ctx, domain.ListFilter{TenantID: filter.TenantID, Status: filter.Status})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saml idps: %w", err)
+	}
+
+	results := make([]*IdPResult, 0, len(idps))
+	for _, idp := range idps {
+		results = append(results, toIdPResult(idp))
+	}
+	return results, nil
+}
+
+// UpdateIdP 更新 SAML IdP 登记
+func (s *samlIdPApplicationService) UpdateIdP(ctx context.Context, slug string, dto UpdateIdPDTO) (*IdPResult, error) {
+	current, err := getIdP(ctx, s.repo, slug)
+	if err != nil {
+		return nil, err
+	}
+	// 在副本上修改，校验失败时不影响已加载的登记
+	updated := *current
+	idp := &updated
+
+	if dto.Name != nil {
+		name := strings.TrimSpace(*dto.Name)
+		if name == "" {
+			return nil, perrors.WithCode(code.ErrInvalidArgument, "name cannot be empty")
+		}
+		idp.Name = name
+	}
+	if dto.MetadataXML != nil {
+		md, err := s.sp.ParseIdPMetadata([]byte(*dto.MetadataXML))
+		if err != nil {
+			return nil, perrors.WithCode(code.ErrSAMLMetadataInvalid, "%s", err.Error())
+		}
+		idp.ApplyMetadata(md, *dto.MetadataXML)
+	}
+	if dto.AttributeMapping != nil {
+		idp.AttributeMapping = *dto.AttributeMapping
+	}
+	if dto.AutoProvision != nil {
+		idp.AutoProvision = *dto.AutoProvision
+	}
+	if err := idp.Validate(); err != nil {
+		return nil, perrors.WithCode(code.ErrSAMLMetadataInvalid, "%s", err.Error())
+	}
+
+	if err := s.repo.Update(ctx, idp); err != nil {
+		return nil, fmt.Errorf("failed to update saml idp: %w", err)
+	}
+	return toIdPResult(idp), nil
+}
+
+// EnableIdP 启用 SAML IdP 登记
+func (s *samlIdPApplicationService) EnableIdP(ctx context.Context, slug string) (*IdPResult, error) {
+	return s.changeIdPStatus(ctx, slug, domain.StatusEnabled)
+}
+
+// DisableIdP 禁用 SAML IdP 登记
+func (s *samlIdPApplicationService) DisableIdP(ctx context.Context, slug string) (*IdPResult, error) {
+	return s.changeIdPStatus(ctx, slug, domain.StatusDisabled)
+}
+
+func (s *samlIdPApplicationService) changeIdPStatus(ctx context.Context, slug string, status domain.Status) (*IdPResult, error) {
+	logger.L(ctx).Debugw("切换 SAML IdP 登记状态",
+		"action", logger.ActionUpdate,
+		"resource", "saml_idp",
+		"slug", slug,
+		"status", status,
+	)
+
+	idp, err := getIdP(ctx, s.repo, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == domain.StatusEnabled {
+		idp.Enable()
+	} else {
+		idp.Disable()
+	}
+
+	if err := s.repo.Update(ctx, idp); err != nil {
+		return nil, fmt.Errorf("failed to update saml idp status: %w", err)
+	}
+	return toIdPResult(idp), nil
+}
+
+// =======================================================
+// ==== SAMLServiceProviderApplicationService 实现 =====
+// =======================================================
+
+type samlServiceProviderApplicationService struct {
+	repo domain.Repository
+	sp   domain.ServiceProvider
+}
+
+// NewSAMLServiceProviderApplicationService 创建 SAML SP 协议端点应用服务
+func NewSAMLServiceProviderApplicationService(repo domain.Repository, sp domain.ServiceProvider) SAMLServiceProviderApplicationService {
+	return &samlServiceProviderApplicationService{repo: repo, sp: sp}
+}
+
+// Metadata 返回供 IdP 导入的 SP 元数据
+func (s *samlServiceProviderApplicationService) Metadata(ctx context.Context, slug string) ([]byte, error) {
+	if _, err := getIdP(ctx, s.repo, slug); err != nil {
+		return nil, err
+	}
+	return s.sp.Metadata(slug)
+}
+
+// BeginLogin 生成跳转到 IdP 的签名 AuthnRequest 地址
+func (s *samlServiceProviderApplicationService) BeginLogin(ctx context.Context, slug, relayState string) (string, error) {
+	idp, err := getIdP(ctx, s.repo, slug)
+	if err != nil {
+		return "", err
+	}
+	if !idp.IsEnabled() {
+		return "", perrors.WithCode(code.ErrSAMLIdPDisabled, "saml idp is disabled: %s", slug)
+	}
+
+	redirectURL, err := s.sp.AuthnRequestURL(ctx, idp, relayState)
+	if err != nil {
+		return "", perrors.WithCode(code.ErrInvalidArgument, "failed to build authn request: %v", err)
+	}
+	return redirectURL, nil
+}
+
+// ============= 辅助函数 =============
+
+func getIdP(ctx context.Context, repo domain.Repository, slug string) (*domain.IdentityProvider, error) {
+	if slug == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "slug cannot be empty")
+	}
+	idp, err := repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query saml idp: %w", err)
+	}
+	if idp == nil {
+		return nil, perrors.WithCode(code.ErrSAMLIdPNotFound, "saml idp not found: %s", slug)
+	}
+	return idp, nil
+}
+
+func toIdPResult(idp *domain.IdentityProvider) *IdPResult {
+	return &IdPResult{
+		ID:               idp.ID.String(),
+		TenantID:         idp.TenantID.String(),
+		Slug:             idp.Slug,
+		Name:             idp.Name,
+		EntityID:         idp.EntityID,
+		SSOURL:           idp.SSOURL,
+		CertificateCount: len(idp.Certificates),
+		AttributeMapping: idp.AttributeMapping,
+		AutoProvision:    idp.AutoProvision,
+		Status:           idp.Status,
+	}
+}
@@ -0,0 +1,107 @@
+package saml
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/saml"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+	"github.com/stretchr/testify/require"
+)
+
+type repoStub struct {
+	idps map[string]*domain.IdentityProvider
+}
+
+func (r *repoStub) Create(_ context.Context, idp *domain.IdentityProvider) error {
+	r.idps[idp.Slug] = idp
+	return nil
+}
+
+func (r *repoStub) GetBySlug(_ context.Context, slug string) (*domain.IdentityProvider, error) {
+	return r.idps[slug], nil
+}
+
+func (r *repoStub) List(context.Context, domain.ListFilter) ([]*domain.IdentityProvider, error) {
+	return nil, nil
+}
+
+func (r *repoStub) Update(_ context.Context, idp *domain.IdentityProvider) error {
+	r.idps[idp.Slug] = idp
+	return nil
+}
+
+// spStub 以元数据原文作为 EntityID 的简化解析
+type spStub struct{}
+
+func (spStub) Metadata(slug string) ([]byte, error) { return []byte("<md " + slug + "/>"), nil }
+
+func (spStub) ParseIdPMetadata(raw []byte) (domain.IdPMetadata, error) {
+	if len(raw) == 0 {
+		return domain.IdPMetadata{}, errors.New("empty metadata")
+	}
+	return domain.IdPMetadata{
+		EntityID:     string(raw),
+		SSOURL:       "https://idp.hospital.example/sso",
+		Certificates: []string{"Y2VydA=="},
+	}, nil
+}
+
+func (spStub) AuthnRequestURL(_ context.Context, idp *domain.IdentityProvider, relayState string) (string, error) {
+	return idp.SSOURL + "?RelayState=" + relayState, nil
+}
+
+func TestSAMLServices_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := &repoStub{idps: map[string]*domain.IdentityProvider{}}
+	idpSvc := NewSAMLIdPApplicationService(repo, spStub{})
+	spSvc := NewSAMLServiceProviderApplicationService(repo, spStub{})
+
+	registered, err := idpSvc.RegisterIdP(ctx, RegisterIdPDTO{
+		TenantID:         meta.FromUint64(7),
+		Slug:             "city-hospital",
+		Name:             "City Hospital",
+		MetadataXML:      "https://idp.hospital.example/saml",
+		AttributeMapping: map[string]string{"mail": "email"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusEnabled, registered.Status)
+	require.Equal(t, "https://idp.hospital.example/saml", registered.EntityID)
+	require.Equal(t, "7", registered.TenantID)
+
+	_, err = idpSvc.RegisterIdP(ctx, RegisterIdPDTO{TenantID: meta.FromUint64(7), Slug: "city-hospital", Name: "Dup", MetadataXML: "x"})
+	require.True(t, perrors.IsCode(err, code.ErrSAMLIdPAlreadyExists))
+
+	_, err = idpSvc.RegisterIdP(ctx, RegisterIdPDTO{TenantID: meta.FromUint64(7), Slug: "bad-md", Name: "Bad"})
+	require.True(t, perrors.IsCode(err, code.ErrSAMLMetadataInvalid))
+
+	reserved := map[string]string{"uid": "saml_name_id"}
+	_, err = idpSvc.UpdateIdP(ctx, "city-hospital", UpdateIdPDTO{AttributeMapping: &reserved})
+	require.True(t, perrors.IsCode(err, code.ErrSAMLMetadataInvalid))
+
+	rotated := "https://idp2.hospital.example/saml"
+	updated, err := idpSvc.UpdateIdP(ctx, "city-hospital", UpdateIdPDTO{MetadataXML: &rotated})
+	require.NoError(t, err)
+	require.Equal(t, rotated, updated.EntityID)
+
+	redirect, err := spSvc.BeginLogin(ctx, "city-hospital", "st-1")
+	require.NoError(t, err)
+	require.Equal(t, "https://idp.hospital.example/sso?RelayState=st-1", redirect)
+
+	md, err := spSvc.Metadata(ctx, "city-hospital")
+	require.NoError(t, err)
+	require.Equal(t, "<md city-hospital/>", string(md))
+
+	disabled, err := idpSvc.DisableIdP(ctx, "city-hospital")
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusDisabled, disabled.Status)
+
+	_, err = spSvc.BeginLogin(ctx, "city-hospital", "st-2")
+	require.True(t, perrors.IsCode(err, code.ErrSAMLIdPDisabled))
+
+	_, err = spSvc.Metadata(ctx, "unknown")
+	require.True(t, perrors.IsCode(err, code.ErrSAMLIdPNotFound))
+}
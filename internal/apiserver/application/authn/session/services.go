@@ -1,10 +1,37 @@
 package session
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // SessionApplicationService 提供管理员会话控制动作。
 type SessionApplicationService interface {
 	RevokeSession(ctx context.Context, sessionID string, reason string, revokedBy string) error
 	RevokeAllSessionsByAccount(ctx context.Context, accountID string, reason string, revokedBy string) error
 	RevokeAllSessionsByUser(ctx context.Context, userID string, reason string, revokedBy string) error
+	ListSessionsByUser(ctx context.Context, userID string) ([]*SessionResult, error)
+	ListSessionsByAccount(ctx context.Context, accountID string) ([]*SessionResult, error)
+}
+
+// SelfSessionApplicationService 提供用户自助的会话（设备）管理动作。
+type SelfSessionApplicationService interface {
+	// ListMySessions 列出当前用户的活跃会话，currentSessionID 用于标记本机会话
+	ListMySessions(ctx context.Context, userID string, currentSessionID string) ([]*SessionResult, error)
+	// RevokeMySession 注销当前用户名下的单个会话；非本人会话按不存在处理
+	RevokeMySession(ctx context.Context, userID string, sessionID string) error
+}
+
+// SessionResult 会话（设备）视图
+type SessionResult struct {
+	SessionID  string
+	UserID     string
+	AccountID  string
+	AMR        []string
+	ClientIP   string
+	UserAgent  string
+	DeviceName string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	Current    bool
 }
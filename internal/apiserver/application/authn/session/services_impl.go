@@ -3,6 +3,7 @@ package session
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	perrors "github.com/FangcunMount/component-base/pkg/errors"
 	"github.com/FangcunMount/component-base/pkg/logger"
@@ -20,6 +21,11 @@ func NewSessionApplicationService(manager sessiondomain.Manager) SessionApplicat
 	return &sessionApplicationService{manager: manager}
 }
 
+// NewSelfSessionApplicationService 创建用户自助会话应用服务。
+func NewSelfSessionApplicationService(manager sessiondomain.Manager) SelfSessionApplicationService {
+	return &sessionApplicationService{manager: manager}
+}
+
 func (s *sessionApplicationService) RevokeSession(ctx context.Context, sessionID string, reason string, revokedBy string) error {
 	l := logger.L(ctx)
 	if sessionID == "" {
@@ -61,6 +67,94 @@ func (s *sessionApplicationService) RevokeAllSessionsByUser(ctx context.Context,
 	return s.manager.RevokeByUser(ctx, id, normalizeReason(reason, "admin_revoked_user_sessions"), revokedBy)
 }
 
+func (s *sessionApplicationService) ListSessionsByUser(ctx context.Context, userID string) ([]*SessionResult, error) {
+	id, err := parseMetaID(userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.manager.ListByUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toSessionResults(sessions, ""), nil
+}
+
+func (s *sessionApplicationService) ListSessionsByAccount(ctx context.Context, accountID string) ([]*SessionResult, error) {
+	id, err := parseMetaID(accountID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.manager.ListByAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toSessionResults(sessions, ""), nil
+}
+
+func (s *sessionApplicationService) ListMySessions(ctx context.Context, userID string, currentSessionID string) ([]*SessionResult, error) {
+	id, err := parseMetaID(userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.manager.ListByUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toSessionResults(sessions, currentSessionID), nil
+}
+
+func (s *sessionApplicationService) RevokeMySession(ctx context.Context, userID string, sessionID string) error {
+	l := logger.L(ctx)
+	id, err := parseMetaID(userID)
+	if err != nil {
+		return err
+	}
+	if sessionID == "" {
+		return perrors.WithCode(code.ErrInvalidArgument, "session_id is required")
+	}
+	sess, err := s.manager.Get(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	// 不区分"不存在"与"不属于本人"，避免枚举他人会话
+	if sess == nil || !sess.IsActive() || sess.UserID != id {
+		return perrors.WithCode(code.ErrSessionNotFound, "session not found")
+	}
+	l.Infow("用户注销单个设备会话",
+		"action", logger.ActionRevoke,
+		"resource", "session",
+		"user_id", userID,
+		"session_id", sessionID,
+	)
+	return s.manager.Revoke(ctx, sessionID, "user_signed_out_device", userID)
+}
+
+func toSessionResults(sessions []*sessiondomain.Session, currentSessionID string) []*SessionResult {
+	results := make([]*SessionResult, 0, len(sessions))
+	for _, sess := range sessions {
+		if sess == nil {
+			continue
+		}
+		results = append(results, &SessionResult{
+			SessionID:  sess.SessionID,
+			UserID:     sess.UserID.String(),
+			AccountID:  sess.AccountID.String(),
+			AMR:        append([]string(nil), sess.AMR...),
+			ClientIP:   sess.ClientIP,
+			UserAgent:  sess.UserAgent,
+			DeviceName: sess.DeviceName,
+			CreatedAt:  sess.CreatedAt,
+			ExpiresAt:  sess.ExpiresAt,
+			Current:    currentSessionID != "" && sess.SessionID == currentSessionID,
+		})
+	}
+	// 最近登录的设备排在前面
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].CreatedAt.After(results[j].CreatedAt)
+	})
+	return results
+}
+
 func parseMetaID(raw string) (meta.ID, error) {
 	var id uint64
 	if _, err := fmt.Sscanf(raw, "%d", &id); err != nil {
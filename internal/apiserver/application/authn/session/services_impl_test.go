@@ -0,0 +1,82 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sessiondomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/session"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+type managerStub struct {
+	sessiondomain.Manager
+	sessions map[string]*sessiondomain.Session
+	revoked  []string
+}
+
+func (m *managerStub) Get(_ context.Context, sessionID string) (*sessiondomain.Session, error) {
+	return m.sessions[sessionID], nil
+}
+
+func (m *managerStub) Revoke(_ context.Context, sessionID string, _ string, _ string) error {
+	m.revoked = append(m.revoked, sessionID)
+	return nil
+}
+
+func (m *managerStub) ListByUser(_ context.Context, userID meta.ID) ([]*sessiondomain.Session, error) {
+	var out []*sessiondomain.Session
+	for _, sess := range m.sessions {
+		if sess.UserID == userID {
+			out = append(out, sess)
+		}
+	}
+	return out, nil
+}
+
+func newManagerStub() *managerStub {
+	expiresAt := time.Now().Add(time.Hour)
+	older := sessiondomain.New("sess-1", meta.FromUint64(1), meta.FromUint64(11), meta.FromUint64(0), []string{"pwd"}, nil, expiresAt)
+	older.CreatedAt = time.Now().Add(-time.Hour)
+	newer := sessiondomain.New("sess-2", meta.FromUint64(1), meta.FromUint64(12), meta.FromUint64(0), []string{"otp"}, nil, expiresAt)
+	newer.DeviceName = "iPhone · 微信"
+	foreign := sessiondomain.New("sess-3", meta.FromUint64(2), meta.FromUint64(21), meta.FromUint64(0), []string{"pwd"}, nil, expiresAt)
+	return &managerStub{sessions: map[string]*sessiondomain.Session{
+		older.SessionID:   older,
+		newer.SessionID:   newer,
+		foreign.SessionID: foreign,
+	}}
+}
+
+func TestListMySessions_MarksCurrentAndSortsByRecency(t *testing.T) {
+	svc := NewSelfSessionApplicationService(newManagerStub())
+
+	results, err := svc.ListMySessions(context.Background(), "1", "sess-1")
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "sess-2", results[0].SessionID)
+	assert.Equal(t, "iPhone · 微信", results[0].DeviceName)
+	assert.False(t, results[0].Current)
+	assert.Equal(t, "sess-1", results[1].SessionID)
+	assert.True(t, results[1].Current)
+}
+
+func TestRevokeMySession_OnlyOwnSessions(t *testing.T) {
+	manager := newManagerStub()
+	svc := NewSelfSessionApplicationService(manager)
+	ctx := context.Background()
+
+	err := svc.RevokeMySession(ctx, "1", "sess-3")
+	assert.True(t, perrors.IsCode(err, code.ErrSessionNotFound))
+	err = svc.RevokeMySession(ctx, "1", "missing")
+	assert.True(t, perrors.IsCode(err, code.ErrSessionNotFound))
+	assert.Empty(t, manager.revoked)
+
+	require.NoError(t, svc.RevokeMySession(ctx, "1", "sess-2"))
+	assert.Equal(t, []string{"sess-2"}, manager.revoked)
+}
@@ -54,8 +54,17 @@ type VerifyTokenRequest struct {
 	ExpectedAudience []string
 }
 
+// 令牌无效原因
+const (
+	InvalidReasonInvalid         = "invalid"              // 签名、撤销、issuer/audience 不匹配等
+	InvalidReasonExpired         = "expired"              // 访问令牌过期
+	InvalidReasonSessionIdle     = "session_idle_timeout" // 会话空闲超时
+	InvalidReasonSessionLifetime = "session_max_lifetime" // 会话超过绝对时长
+)
+
 // TokenVerifyResult 令牌验证结果DTO
 type TokenVerifyResult struct {
-	Valid  bool                // 是否有效
-	Claims *domain.TokenClaims // 令牌声明（如果有效）
+	Valid         bool                // 是否有效
+	Claims        *domain.TokenClaims // 令牌声明（如果有效）
+	InvalidReason string              // 无效原因（Valid=false 时）
 }
@@ -187,8 +187,9 @@ func (s *tokenApplicationService) VerifyToken(ctx context.Context, req VerifyTok
 		)
 		// 令牌无效
 		return &TokenVerifyResult{
-			Valid:  false,
-			Claims: nil,
+			Valid:         false,
+			Claims:        nil,
+			InvalidReason: invalidReason(err),
 		}, nil
 	}
 
@@ -200,7 +201,7 @@ func (s *tokenApplicationService) VerifyToken(ctx context.Context, req VerifyTok
 			"actual_issuer", claims.Issuer,
 			"result", logger.ResultFailed,
 		)
-		return &TokenVerifyResult{Valid: false, Claims: nil}, nil
+		return &TokenVerifyResult{Valid: false, Claims: nil, InvalidReason: InvalidReasonInvalid}, nil
 	}
 
 	if len(req.ExpectedAudience) > 0 && !containsAnyAudience(claims.Audience, req.ExpectedAudience) {
@@ -211,7 +212,7 @@ func (s *tokenApplicationService) VerifyToken(ctx context.Context, req VerifyTok
 			"actual_audience", claims.Audience,
 			"result", logger.ResultFailed,
 		)
-		return &TokenVerifyResult{Valid: false, Claims: nil}, nil
+		return &TokenVerifyResult{Valid: false, Claims: nil, InvalidReason: InvalidReasonInvalid}, nil
 	}
 
 	l.Debugw("访问令牌验证成功",
@@ -228,6 +229,20 @@ func (s *tokenApplicationService) VerifyToken(ctx context.Context, req VerifyTok
 	}, nil
 }
 
+// invalidReason 将验证错误码归类为对外的无效原因
+func invalidReason(err error) string {
+	switch {
+	case perrors.IsCode(err, code.ErrSessionIdleTimeout):
+		return InvalidReasonSessionIdle
+	case perrors.IsCode(err, code.ErrSessionLifetimeExceeded):
+		return InvalidReasonSessionLifetime
+	case perrors.IsCode(err, code.ErrExpired):
+		return InvalidReasonExpired
+	default:
+		return InvalidReasonInvalid
+	}
+}
+
 func containsAnyAudience(actual []string, expected []string) bool {
 	if len(actual) == 0 || len(expected) == 0 {
 		return false
@@ -17,9 +17,10 @@ import (
 
 // TxRepositories 聚合事务中可使用的仓储集合。
 type TxRepositories struct {
-	Accounts    accountDomain.Repository
-	Credentials credentialDomain.Repository
-	Users       userDomain.Repository
+	Accounts        accountDomain.Repository
+	Credentials     credentialDomain.Repository
+	PasswordHistory credentialDomain.PasswordHistoryRepository
+	Users           userDomain.Repository
 }
 
 // UnitOfWork 提供业务事务边界。
@@ -47,9 +48,10 @@ func (u *gormUnitOfWork) WithinTx(ctx context.Context, fn func(tx TxRepositories
 
 	return u.base.WithinTransaction(ctx, func(tx *gorm.DB) error {
 		repos := TxRepositories{
-			Accounts:    acctrepo.NewAccountRepository(tx),
-			Credentials: credentialrepo.NewRepository(tx),
-			Users:       userrepo.NewRepository(tx),
+			Accounts:        acctrepo.NewAccountRepository(tx),
+			Credentials:     credentialrepo.NewRepository(tx),
+			PasswordHistory: credentialrepo.NewHistoryRepository(tx),
+			Users:           userrepo.NewRepository(tx),
 		}
 		return fn(repos)
 	})
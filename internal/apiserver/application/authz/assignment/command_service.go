@@ -3,6 +3,7 @@ package assignment
 
 import (
 	"context"
+	"time"
 
 	"github.com/FangcunMount/component-base/pkg/errors"
 	"github.com/FangcunMount/component-base/pkg/log"
@@ -10,7 +11,9 @@ import (
 	authzuow "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/uow"
 	assignmentDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
 	policyDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
 	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+	"gorm.io/gorm"
 )
 
 // AssignmentCommandService 赋权命令服务（实现 AssignmentCommander 接口）
@@ -21,6 +24,7 @@ type AssignmentCommandService struct {
 	uow                 authzuow.UnitOfWork
 	casbinAdapter       policyDomain.CasbinAdapter
 	versionNotifier     policyDomain.VersionNotifier
+	now                 func() time.Time
 }
 
 // NewAssignmentCommandService 创建赋权命令服务
@@ -35,6 +39,7 @@ func NewAssignmentCommandService(
 		uow:                 uow,
 		casbinAdapter:       casbinAdapter,
 		versionNotifier:     versionNotifier,
+		now:                 time.Now,
 	}
 }
 
@@ -45,9 +50,24 @@ func (s *AssignmentCommandService) Grant(ctx context.Context, cmd assignmentDoma
 		return nil, err
 	}
 
+	// 2. 按有效期确定初始状态：未到生效时间的赋权暂不写入 g 规则，由有效期调度器到点授予
+	created := assignmentDomain.NewAssignment(
+		cmd.SubjectType,
+		cmd.SubjectID,
+		cmd.RoleID,
+		cmd.TenantID,
+		assignmentDomain.WithGrantedBy(cmd.GrantedBy),
+		assignmentDomain.WithValidity(cmd.NotBefore, cmd.ExpiresAt),
+	)
+	created.Status = created.StatusAt(s.now())
+	if created.Status == assignmentDomain.StatusExpired {
+		return nil, errors.WithCode(code.ErrInvalidArgument, "失效时间已过")
+	}
+
 	var (
 		newAssignment *assignmentDomain.Assignment
 		version       *policyDomain.PolicyVersion
+		queued        bool
 	)
 
 	err := s.uow.WithinTx(ctx, func(tx authzuow.TxRepositories) error {
@@ -67,16 +87,13 @@ func (s *AssignmentCommandService) Grant(ctx context.Context, cmd assignmentDoma
 			return errors.New("角色不属于当前租户")
 		}
 
-		created := assignmentDomain.NewAssignment(
-			cmd.SubjectType,
-			cmd.SubjectID,
-			cmd.RoleID,
-			cmd.TenantID,
-			assignmentDomain.WithGrantedBy(cmd.GrantedBy),
-		)
 		if err := tx.Assignments.Create(ctx, &created); err != nil {
 			return errors.Wrap(err, "创建赋权失败")
 		}
+		newAssignment = &created
+		if !created.Effective() {
+			return nil
+		}
 
 		groupingRule := policyDomain.GroupingRule{
 			Sub:  created.SubjectKey(),
@@ -87,19 +104,22 @@ func (s *AssignmentCommandService) Grant(ctx context.Context, cmd assignmentDoma
 			return errors.Wrap(err, "添加 Casbin 分组规则失败")
 		}
 
-		version, err = tx.PolicyVersions.Increment(ctx, cmd.TenantID, cmd.GrantedBy, "assignment grant")
+		version, queued, err = authzshared.BumpVersion(ctx, tx, cmd.TenantID, cmd.GrantedBy, "assignment grant")
 		if err != nil {
 			return errors.Wrap(err, "更新授权版本失败")
 		}
-
-		newAssignment = &created
 		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
+	if !newAssignment.Effective() {
+		return newAssignment, nil
+	}
 
-	s.publishVersion(ctx, cmd.TenantID, version)
+	if !queued {
+		s.publishVersion(ctx, cmd.TenantID, version)
+	}
 	authzshared.ReloadRuntimePolicy(ctx, s.casbinAdapter, "assignment grant")
 	return newAssignment, nil
 }
@@ -111,7 +131,10 @@ func (s *AssignmentCommandService) Revoke(ctx context.Context, cmd assignmentDom
 		return err
 	}
 
-	var version *policyDomain.PolicyVersion
+	var (
+		version *policyDomain.PolicyVersion
+		queued  bool
+	)
 	err := s.uow.WithinTx(ctx, func(tx authzuow.TxRepositories) error {
 		role, err := tx.Roles.FindByID(ctx, meta.FromUint64(cmd.RoleID))
 		if err != nil {
@@ -121,8 +144,18 @@ func (s *AssignmentCommandService) Revoke(ctx context.Context, cmd assignmentDom
 			return errors.New("角色不属于当前租户")
 		}
 
-		if err := tx.Assignments.DeleteBySubjectAndRole(ctx, cmd.SubjectType, cmd.SubjectID, cmd.RoleID, cmd.TenantID); err != nil {
-			return errors.Wrap(err, "删除赋权记录失败")
+		// 已过期的赋权仅作历史保留，只删除待生效与生效中的记录
+		held, err := tx.Assignments.ListBySubject(ctx, cmd.SubjectType, cmd.SubjectID, cmd.TenantID)
+		if err != nil {
+			return errors.Wrap(err, "获取主体赋权失败")
+		}
+		for _, a := range held {
+			if a.RoleID != cmd.RoleID || a.Status == assignmentDomain.StatusExpired {
+				continue
+			}
+			if err := tx.Assignments.Delete(ctx, a.ID); err != nil {
+				return errors.Wrap(err, "删除赋权记录失败")
+			}
 		}
 
 		groupingRule := policyDomain.GroupingRule{
@@ -134,7 +167,7 @@ func (s *AssignmentCommandService) Revoke(ctx context.Context, cmd assignmentDom
 			return errors.Wrap(err, "删除 Casbin 分组规则失败")
 		}
 
-		version, err = tx.PolicyVersions.Increment(ctx, cmd.TenantID, "system", "assignment revoke")
+		version, queued, err = authzshared.BumpVersion(ctx, tx, cmd.TenantID, "system", "assignment revoke")
 		if err != nil {
 			return errors.Wrap(err, "更新授权版本失败")
 		}
@@ -144,7 +177,9 @@ func (s *AssignmentCommandService) Revoke(ctx context.Context, cmd assignmentDom
 		return err
 	}
 
-	s.publishVersion(ctx, cmd.TenantID, version)
+	if !queued {
+		s.publishVersion(ctx, cmd.TenantID, version)
+	}
 	authzshared.ReloadRuntimePolicy(ctx, s.casbinAdapter, "assignment revoke")
 	return nil
 }
@@ -153,6 +188,7 @@ func (s *AssignmentCommandService) Revoke(ctx context.Context, cmd assignmentDom
 func (s *AssignmentCommandService) RevokeByID(ctx context.Context, cmd assignmentDomain.RevokeByIDCommand) error {
 	var (
 		version          *policyDomain.PolicyVersion
+		queued           bool
 		targetAssignment *assignmentDomain.Assignment
 	)
 
@@ -171,19 +207,28 @@ func (s *AssignmentCommandService) RevokeByID(ctx context.Context, cmd assignmen
 			return errors.Wrap(err, "获取角色失败")
 		}
 
-		groupingRule := policyDomain.GroupingRule{
-			Sub:  targetAssignment.SubjectKey(),
-			Role: role.Key(),
-			Dom:  targetAssignment.TenantID,
+		// 仅生效中的赋权写入过 g 规则；主体仍通过其它生效中的赋权持有该角色时保留
+		keepRule := !targetAssignment.Effective()
+		if !keepRule {
+			if keepRule, err = roleHeldByOther(ctx, tx, targetAssignment); err != nil {
+				return err
+			}
 		}
-		if err := tx.RuleStore.RemoveGroupingPolicy(ctx, groupingRule); err != nil {
-			return errors.Wrap(err, "删除 Casbin 分组规则失败")
+		if !keepRule {
+			groupingRule := policyDomain.GroupingRule{
+				Sub:  targetAssignment.SubjectKey(),
+				Role: role.Key(),
+				Dom:  targetAssignment.TenantID,
+			}
+			if err := tx.RuleStore.RemoveGroupingPolicy(ctx, groupingRule); err != nil {
+				return errors.Wrap(err, "删除 Casbin 分组规则失败")
+			}
 		}
 		if err := tx.Assignments.Delete(ctx, targetAssignment.ID); err != nil {
 			return errors.Wrap(err, "删除赋权记录失败")
 		}
 
-		version, err = tx.PolicyVersions.Increment(ctx, targetAssignment.TenantID, "system", "assignment revoke")
+		version, queued, err = authzshared.BumpVersion(ctx, tx, targetAssignment.TenantID, "system", "assignment revoke")
 		if err != nil {
 			return errors.Wrap(err, "更新授权版本失败")
 		}
@@ -193,11 +238,129 @@ func (s *AssignmentCommandService) RevokeByID(ctx context.Context, cmd assignmen
 		return err
 	}
 
-	s.publishVersion(ctx, cmd.TenantID, version)
+	if !queued {
+		s.publishVersion(ctx, cmd.TenantID, version)
+	}
 	authzshared.ReloadRuntimePolicy(ctx, s.casbinAdapter, "assignment revoke by id")
 	return nil
 }
 
+// SyncExternalRoles 按外部身份源同步主体角色
+// 授予映射出但尚未持有的角色，撤销该来源此前授予但已不再映射的角色；
+// 管理员手工授予的赋权（GrantedBy 不等于 Source）不受影响，租户内不存在的角色名跳过
+func (s *AssignmentCommandService) SyncExternalRoles(ctx context.Context, cmd assignmentDomain.SyncExternalRolesCommand) error {
+	if cmd.SubjectType == "" || cmd.SubjectID == "" || cmd.TenantID == "" {
+		return errors.WithCode(code.ErrInvalidArgument, "主体与租户不能为空")
+	}
+	if cmd.Source == "" {
+		return errors.WithCode(code.ErrInvalidArgument, "外部身份源不能为空")
+	}
+
+	var (
+		version *policyDomain.PolicyVersion
+		queued  bool
+		changed bool
+	)
+	err := s.uow.WithinTx(ctx, func(tx authzuow.TxRepositories) error {
+		desired := make(map[uint64]string, len(cmd.RoleNames))
+		var order []uint64
+		for _, name := range cmd.RoleNames {
+			role, err := tx.Roles.FindByName(ctx, cmd.TenantID, name)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					log.Warnw("external role mapping refers to unknown role", "tenant_id", cmd.TenantID, "role", name, "source", cmd.Source)
+					continue
+				}
+				return errors.Wrap(err, "获取角色失败")
+			}
+			if _, dup := desired[role.ID.Uint64()]; !dup {
+				order = append(order, role.ID.Uint64())
+			}
+			desired[role.ID.Uint64()] = role.Key()
+		}
+
+		existing, err := tx.Assignments.ListBySubject(ctx, cmd.SubjectType, cmd.SubjectID, cmd.TenantID)
+		if err != nil {
+			return errors.Wrap(err, "获取主体赋权失败")
+		}
+		subjectKey := string(cmd.SubjectType) + ":" + cmd.SubjectID
+		held := make(map[uint64]struct{}, len(existing))
+		for _, a := range existing {
+			if a.Status == assignmentDomain.StatusExpired {
+				// 已过期的赋权仅作历史保留，映射仍包含该角色时重新授予
+				continue
+			}
+			// 待生效的赋权尚未持有角色，映射包含该角色时立即授予
+			if a.Effective() {
+				held[a.RoleID] = struct{}{}
+			}
+			if _, keep := desired[a.RoleID]; keep || a.GrantedBy != cmd.Source {
+				continue
+			}
+			// 只删除该来源授予的这条赋权，同角色的手工赋权保留
+			if err := tx.Assignments.Delete(ctx, a.ID); err != nil {
+				return errors.Wrap(err, "删除赋权记录失败")
+			}
+			changed = true
+			keepRule := !a.Effective()
+			if !keepRule {
+				if keepRule, err = roleHeldByOther(ctx, tx, a); err != nil {
+					return err
+				}
+			}
+			if keepRule {
+				continue
+			}
+			role, err := tx.Roles.FindByID(ctx, meta.FromUint64(a.RoleID))
+			if err != nil {
+				return errors.Wrap(err, "获取角色失败")
+			}
+			if err := tx.RuleStore.RemoveGroupingPolicy(ctx, policyDomain.GroupingRule{Sub: subjectKey, Role: role.Key(), Dom: cmd.TenantID}); err != nil {
+				return errors.Wrap(err, "删除 Casbin 分组规则失败")
+			}
+		}
+
+		for _, roleID := range order {
+			if _, ok := held[roleID]; ok {
+				continue
+			}
+			roleKey := desired[roleID]
+			created := assignmentDomain.NewAssignment(
+				cmd.SubjectType,
+				cmd.SubjectID,
+				roleID,
+				cmd.TenantID,
+				assignmentDomain.WithGrantedBy(cmd.Source),
+			)
+			if err := tx.Assignments.Create(ctx, &created); err != nil {
+				return errors.Wrap(err, "创建赋权失败")
+			}
+			if err := tx.RuleStore.AddGroupingPolicy(ctx, policyDomain.GroupingRule{Sub: subjectKey, Role: roleKey, Dom: cmd.TenantID}); err != nil {
+				return errors.Wrap(err, "添加 Casbin 分组规则失败")
+			}
+			changed = true
+		}
+
+		if !changed {
+			return nil
+		}
+		version, queued, err = authzshared.BumpVersion(ctx, tx, cmd.TenantID, cmd.Source, "external role sync")
+		if err != nil {
+			return errors.Wrap(err, "更新授权版本失败")
+		}
+		return nil
+	})
+	if err != nil || !changed {
+		return err
+	}
+
+	if !queued {
+		s.publishVersion(ctx, cmd.TenantID, version)
+	}
+	authzshared.ReloadRuntimePolicy(ctx, s.casbinAdapter, "external role sync")
+	return nil
+}
+
 func (s *AssignmentCommandService) publishVersion(ctx context.Context, tenantID string, version *policyDomain.PolicyVersion) {
 	if s.versionNotifier == nil || version == nil {
 		return
@@ -16,6 +16,7 @@ import (
 	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 func TestAssignmentCommandServiceGrant_RejectsMissingUserWithoutWrites(t *testing.T) {
@@ -110,6 +111,134 @@ func TestAssignmentCommandServiceGrant_CommitsFactsWhenRuntimeReloadFails(t *tes
 	assert.Equal(t, 3, runtime.loadCalls)
 }
 
+func TestAssignmentCommandServiceGrant_EnqueuesVersionWhenOutboxEnabled(t *testing.T) {
+	roleRepo := &assignmentRoleRepoStub{
+		role: &roleDomain.Role{
+			ID:       meta.FromUint64(10),
+			Name:     "iam:admin",
+			TenantID: "tenant-a",
+		},
+	}
+	assignmentRepo := &assignmentRepoStub{}
+	userRepo := testhelpers.NewUserRepoStub()
+	userRepo.UsersByID[123] = &userDomain.User{ID: meta.FromUint64(123)}
+	versionRepo := &policyVersionRepoStub{currentVersion: 6}
+	outbox := &versionOutboxStub{}
+	notifier := &versionNotifierStub{}
+
+	validator := assignmentDomain.NewValidator(assignmentRepo, roleRepo, userRepo)
+	service := NewAssignmentCommandService(
+		validator,
+		&uowStub{tx: authzuow.TxRepositories{
+			Assignments:    assignmentRepo,
+			Roles:          roleRepo,
+			Users:          userRepo,
+			PolicyVersions: versionRepo,
+			RuleStore:      &ruleStoreStub{},
+			VersionOutbox:  outbox,
+		}},
+		&casbinAdapterStub{},
+		notifier,
+	)
+
+	_, err := service.Grant(context.Background(), assignmentDomain.GrantCommand{
+		SubjectType: assignmentDomain.SubjectTypeUser,
+		SubjectID:   "123",
+		RoleID:      10,
+		TenantID:    "tenant-a",
+		GrantedBy:   "1",
+	})
+	require.NoError(t, err)
+	require.Len(t, outbox.enqueued, 1)
+	assert.Equal(t, "tenant-a", outbox.enqueued[0].TenantID)
+	assert.Equal(t, int64(7), outbox.enqueued[0].Version)
+	// 事件已随事务落库，提交后不再直接发布
+	assert.Equal(t, 0, notifier.publishCalls)
+}
+
+func TestAssignmentCommandServiceSyncExternalRoles_OnlyTouchesSourceGrants(t *testing.T) {
+	roles := &namedRoleRepoStub{roles: []*roleDomain.Role{
+		{ID: meta.FromUint64(10), Name: "admin", TenantID: "tenant-a"},
+		{ID: meta.FromUint64(11), Name: "auditor", TenantID: "tenant-a"},
+		{ID: meta.FromUint64(12), Name: "operator", TenantID: "tenant-a"},
+		{ID: meta.FromUint64(13), Name: "viewer", TenantID: "tenant-a"},
+	}}
+	source := "oidc:corp-sso"
+	assignmentRepo := &assignmentRepoStub{nextID: 100, bySubject: []*assignmentDomain.Assignment{
+		// 上游此前授予、仍映射：保留
+		{ID: assignmentDomain.NewAssignmentID(1), SubjectType: assignmentDomain.SubjectTypeUser, SubjectID: "123", RoleID: 10, TenantID: "tenant-a", GrantedBy: source, Status: assignmentDomain.StatusActive},
+		// 上游此前授予、已不再映射：撤销
+		{ID: assignmentDomain.NewAssignmentID(2), SubjectType: assignmentDomain.SubjectTypeUser, SubjectID: "123", RoleID: 12, TenantID: "tenant-a", GrantedBy: source, Status: assignmentDomain.StatusActive},
+		// 管理员手工授予：不受影响
+		{ID: assignmentDomain.NewAssignmentID(3), SubjectType: assignmentDomain.SubjectTypeUser, SubjectID: "123", RoleID: 13, TenantID: "tenant-a", GrantedBy: "1", Status: assignmentDomain.StatusActive},
+	}}
+	versionRepo := &policyVersionRepoStub{}
+	ruleStore := &ruleStoreStub{}
+	runtime := &casbinAdapterStub{}
+	service := NewAssignmentCommandService(
+		assignmentDomain.NewValidator(assignmentRepo, roles, testhelpers.NewUserRepoStub()),
+		&uowStub{tx: authzuow.TxRepositories{
+			Assignments:    assignmentRepo,
+			Roles:          roles,
+			PolicyVersions: versionRepo,
+			RuleStore:      ruleStore,
+		}},
+		runtime,
+		nil,
+	)
+
+	cmd := assignmentDomain.SyncExternalRolesCommand{
+		SubjectType: assignmentDomain.SubjectTypeUser,
+		SubjectID:   "123",
+		TenantID:    "tenant-a",
+		Source:      source,
+		RoleNames:   []string{"admin", "auditor", "unknown"},
+	}
+	require.NoError(t, service.SyncExternalRoles(context.Background(), cmd))
+
+	require.Len(t, assignmentRepo.created, 1)
+	assert.Equal(t, uint64(11), assignmentRepo.created[0].RoleID)
+	assert.Equal(t, source, assignmentRepo.created[0].GrantedBy)
+	require.Len(t, ruleStore.groupingAdds, 1)
+	assert.Equal(t, "role:auditor", ruleStore.groupingAdds[0].Role)
+	assert.Equal(t, []uint64{2}, assignmentRepo.deleted)
+	require.Len(t, ruleStore.groupingRemoves, 1)
+	assert.Equal(t, "role:operator", ruleStore.groupingRemoves[0].Role)
+	assert.Equal(t, 1, versionRepo.incrementCalls)
+	assert.Equal(t, 1, runtime.loadCalls)
+
+	// 无变化时不递增版本
+	assignmentRepo.bySubject = []*assignmentDomain.Assignment{
+		{ID: assignmentDomain.NewAssignmentID(1), SubjectType: assignmentDomain.SubjectTypeUser, SubjectID: "123", RoleID: 10, TenantID: "tenant-a", GrantedBy: source, Status: assignmentDomain.StatusActive},
+	}
+	cmd.RoleNames = []string{"admin"}
+	require.NoError(t, service.SyncExternalRoles(context.Background(), cmd))
+	assert.Equal(t, 1, versionRepo.incrementCalls)
+}
+
+type namedRoleRepoStub struct {
+	assignmentRoleRepoStub
+	roles []*roleDomain.Role
+}
+
+func (r *namedRoleRepoStub) FindByID(_ context.Context, id meta.ID) (*roleDomain.Role, error) {
+	for _, role := range r.roles {
+		if role.ID == id {
+			return role, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *namedRoleRepoStub) FindByName(_ context.Context, tenantID, name string) (*roleDomain.Role, error) {
+	for _, role := range r.roles {
+		if role.TenantID == tenantID && role.Name == name {
+			return role, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
 type uowStub struct {
 	tx authzuow.TxRepositories
 }
@@ -123,6 +252,8 @@ type assignmentRepoStub struct {
 	nextID      uint64
 	created     []*assignmentDomain.Assignment
 	findByID    map[uint64]*assignmentDomain.Assignment
+	bySubject   []*assignmentDomain.Assignment
+	deleted     []uint64
 }
 
 func (r *assignmentRepoStub) Create(_ context.Context, a *assignmentDomain.Assignment) error {
@@ -142,10 +273,11 @@ func (r *assignmentRepoStub) Create(_ context.Context, a *assignmentDomain.Assig
 
 func (r *assignmentRepoStub) Delete(_ context.Context, id assignmentDomain.AssignmentID) error {
 	delete(r.findByID, id.Uint64())
+	r.deleted = append(r.deleted, id.Uint64())
 	return nil
 }
 
-func (r *assignmentRepoStub) DeleteBySubjectAndRole(_ context.Context, _ assignmentDomain.SubjectType, _ string, _ uint64, _ string) error {
+func (r *assignmentRepoStub) DeleteBySubjectAndRole(context.Context, assignmentDomain.SubjectType, string, uint64, string) error {
 	return nil
 }
 
@@ -154,6 +286,10 @@ func (r *assignmentRepoStub) FindByID(_ context.Context, id assignmentDomain.Ass
 }
 
 func (r *assignmentRepoStub) ListBySubject(_ context.Context, _ assignmentDomain.SubjectType, _ string, _ string) ([]*assignmentDomain.Assignment, error) {
+	return r.bySubject, nil
+}
+
+func (r *assignmentRepoStub) ListBySubjectAcrossTenants(_ context.Context, _ assignmentDomain.SubjectType, _ string) ([]*assignmentDomain.Assignment, error) {
 	return nil, nil
 }
 
@@ -188,7 +324,7 @@ func (r *policyVersionRepoStub) GetOrCreate(_ context.Context, tenantID string)
 	return &version, nil
 }
 
-func (r *policyVersionRepoStub) Increment(_ context.Context, tenantID, changedBy, reason string) (*policyDomain.PolicyVersion, error) {
+func (r *policyVersionRepoStub) Increment(_ context.Context, tenantID, changedBy, reason string, _ ...policyDomain.PolicyVersionOption) (*policyDomain.PolicyVersion, error) {
 	r.incrementCalls++
 	r.currentVersion++
 	version := policyDomain.NewPolicyVersion(
@@ -206,7 +342,8 @@ func (r *policyVersionRepoStub) GetCurrent(_ context.Context, tenantID string) (
 }
 
 type ruleStoreStub struct {
-	groupingAdds []policyDomain.GroupingRule
+	groupingAdds    []policyDomain.GroupingRule
+	groupingRemoves []policyDomain.GroupingRule
 }
 
 func (r *ruleStoreStub) AddPolicy(context.Context, ...policyDomain.PolicyRule) error { return nil }
@@ -217,7 +354,8 @@ func (r *ruleStoreStub) AddGroupingPolicy(_ context.Context, rules ...policyDoma
 	r.groupingAdds = append(r.groupingAdds, rules...)
 	return nil
 }
-func (r *ruleStoreStub) RemoveGroupingPolicy(context.Context, ...policyDomain.GroupingRule) error {
+func (r *ruleStoreStub) RemoveGroupingPolicy(_ context.Context, rules ...policyDomain.GroupingRule) error {
+	r.groupingRemoves = append(r.groupingRemoves, rules...)
 	return nil
 }
 
@@ -272,3 +410,12 @@ func (n *versionNotifierStub) Subscribe(context.Context, policyDomain.VersionCha
 	return nil
 }
 func (n *versionNotifierStub) Close() error { return nil }
+
+type versionOutboxStub struct {
+	enqueued []*policyDomain.PolicyVersion
+}
+
+func (o *versionOutboxStub) Enqueue(_ context.Context, version *policyDomain.PolicyVersion) error {
+	o.enqueued = append(o.enqueued, version)
+	return nil
+}
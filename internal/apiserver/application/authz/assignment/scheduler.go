@@ -0,0 +1,271 @@
+package assignment
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/log"
+	authzshared "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/shared"
+	authzuow "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/uow"
+	assignmentDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
+	policyDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultScheduleInterval 默认的赋权有效期检查间隔
+	DefaultScheduleInterval = time.Minute
+	// scheduleBatchSize 单轮最多处理的到期赋权数，剩余的留待下一轮
+	scheduleBatchSize = 500
+)
+
+// ValidityScheduler 赋权有效期调度器
+// 在生效/失效边界处增删赋权对应的 Casbin g 规则并递增策略版本；
+// 状态切换以当前状态为条件，多副本同时运行时每个边界只会被处理一次
+type ValidityScheduler struct {
+	uow             authzuow.UnitOfWork
+	casbinAdapter   policyDomain.CasbinAdapter
+	versionNotifier policyDomain.VersionNotifier
+	interval        time.Duration
+	now             func() time.Time
+
+	mu      sync.RWMutex
+	running bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// SchedulerOption 调度器选项
+type SchedulerOption func(*ValidityScheduler)
+
+// WithScheduleInterval 设置检查间隔，非正数时使用 DefaultScheduleInterval
+func WithScheduleInterval(interval time.Duration) SchedulerOption {
+	return func(s *ValidityScheduler) {
+		if interval > 0 {
+			s.interval = interval
+		}
+	}
+}
+
+// WithClock 替换时钟，供测试驱动有效期边界
+func WithClock(now func() time.Time) SchedulerOption {
+	return func(s *ValidityScheduler) { s.now = now }
+}
+
+// NewValidityScheduler 创建赋权有效期调度器
+func NewValidityScheduler(
+	uow authzuow.UnitOfWork,
+	casbinAdapter policyDomain.CasbinAdapter,
+	versionNotifier policyDomain.VersionNotifier,
+	opts ...SchedulerOption,
+) *ValidityScheduler {
+	s := &ValidityScheduler{
+		uow:             uow,
+		casbinAdapter:   casbinAdapter,
+		versionNotifier: versionNotifier,
+		interval:        DefaultScheduleInterval,
+		now:             time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Start 启动调度循环，启动时立即补处理停机期间越过边界的赋权
+func (s *ValidityScheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return nil
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.running = true
+
+	s.wg.Add(1)
+	go s.run(ctx)
+
+	log.Infow("authz assignment validity scheduler started", "interval", s.interval)
+	return nil
+}
+
+// Stop 停止调度循环
+func (s *ValidityScheduler) Stop() error {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return nil
+	}
+	s.cancel()
+	s.mu.Unlock()
+
+	s.wg.Wait()
+
+	s.mu.Lock()
+	s.running = false
+	s.mu.Unlock()
+
+	log.Info("authz assignment validity scheduler stopped")
+	return nil
+}
+
+// IsRunning 返回调度器是否正在运行
+func (s *ValidityScheduler) IsRunning() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.running
+}
+
+func (s *ValidityScheduler) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		// 失败时保留当前状态，等待下一轮重试
+		if _, err := s.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Warnw("failed to apply authz assignment validity", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce 处理当前时刻已越过有效期边界的赋权，返回切换了状态的赋权数
+func (s *ValidityScheduler) RunOnce(ctx context.Context) (int, error) {
+	now := s.now()
+
+	type tenantVersion struct {
+		version *policyDomain.PolicyVersion
+		queued  bool
+	}
+	var (
+		transitioned int
+		tenants      []string
+		versions     = make(map[string]tenantVersion)
+	)
+	err := s.uow.WithinTx(ctx, func(tx authzuow.TxRepositories) error {
+		due, err := tx.AssignmentSchedule.ListDue(ctx, now, scheduleBatchSize)
+		if err != nil {
+			return errors.Wrap(err, "获取到期赋权失败")
+		}
+
+		touched := make(map[string]struct{})
+		for _, a := range due {
+			to := a.StatusAt(now)
+			if to == a.Status {
+				continue
+			}
+			ok, err := tx.AssignmentSchedule.TransitionStatus(ctx, a.ID, a.Status, to)
+			if err != nil {
+				return errors.Wrap(err, "更新赋权状态失败")
+			}
+			if !ok {
+				// 已被其它副本处理
+				continue
+			}
+			transitioned++
+
+			changed, err := s.applyTransition(ctx, tx, a, to)
+			if err != nil {
+				return err
+			}
+			if _, seen := touched[a.TenantID]; changed && !seen {
+				touched[a.TenantID] = struct{}{}
+				tenants = append(tenants, a.TenantID)
+			}
+		}
+
+		for _, tenantID := range tenants {
+			version, queued, err := authzshared.BumpVersion(ctx, tx, tenantID, "system", "assignment schedule")
+			if err != nil {
+				return errors.Wrap(err, "更新授权版本失败")
+			}
+			versions[tenantID] = tenantVersion{version: version, queued: queued}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(tenants) == 0 {
+		return transitioned, nil
+	}
+
+	for _, tenantID := range tenants {
+		if v := versions[tenantID]; !v.queued {
+			s.publishVersion(ctx, tenantID, v.version)
+		}
+	}
+	authzshared.ReloadRuntimePolicy(ctx, s.casbinAdapter, "assignment schedule")
+	return transitioned, nil
+}
+
+// applyTransition 按状态切换增删 g 规则，返回规则是否可能发生变化
+func (s *ValidityScheduler) applyTransition(ctx context.Context, tx authzuow.TxRepositories, a *assignmentDomain.Assignment, to assignmentDomain.Status) (bool, error) {
+	granting := to == assignmentDomain.StatusActive
+	if !granting && !a.Effective() {
+		// 未生效即过期，从未写入 g 规则
+		return false, nil
+	}
+
+	role, err := tx.Roles.FindByID(ctx, meta.FromUint64(a.RoleID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warnw("scheduled assignment refers to deleted role", "assignment_id", a.ID.String(), "role_id", a.RoleID)
+			return false, nil
+		}
+		return false, errors.Wrap(err, "获取角色失败")
+	}
+	rule := policyDomain.GroupingRule{Sub: a.SubjectKey(), Role: role.Key(), Dom: a.TenantID}
+
+	if granting {
+		if err := tx.RuleStore.AddGroupingPolicy(ctx, rule); err != nil {
+			return false, errors.Wrap(err, "添加 Casbin 分组规则失败")
+		}
+		return true, nil
+	}
+
+	// 主体仍通过其它生效中的赋权持有该角色时保留 g 规则
+	held, err := roleHeldByOther(ctx, tx, a)
+	if err != nil || held {
+		return false, err
+	}
+	if err := tx.RuleStore.RemoveGroupingPolicy(ctx, rule); err != nil {
+		return false, errors.Wrap(err, "删除 Casbin 分组规则失败")
+	}
+	return true, nil
+}
+
+// roleHeldByOther 判断主体是否仍通过 a 以外生效中的赋权持有同一角色
+func roleHeldByOther(ctx context.Context, tx authzuow.TxRepositories, a *assignmentDomain.Assignment) (bool, error) {
+	held, err := tx.Assignments.ListBySubject(ctx, a.SubjectType, a.SubjectID, a.TenantID)
+	if err != nil {
+		return false, errors.Wrap(err, "获取主体赋权失败")
+	}
+	for _, other := range held {
+		if other.ID != a.ID && other.RoleID == a.RoleID && other.Effective() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *ValidityScheduler) publishVersion(ctx context.Context, tenantID string, version *policyDomain.PolicyVersion) {
+	if s.versionNotifier == nil || version == nil {
+		return
+	}
+	if err := s.versionNotifier.Publish(ctx, tenantID, version.Version); err != nil {
+		log.Errorw("failed to publish authz assignment schedule version", "tenant_id", tenantID, "version", version.Version, "error", err)
+	}
+}
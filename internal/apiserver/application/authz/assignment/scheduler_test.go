@@ -0,0 +1,375 @@
+package assignment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authzuow "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/uow"
+	assignmentDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
+	roleDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/role"
+	userDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/uc/user"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/testhelpers"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+type scheduleFixture struct {
+	clock       *fakeClock
+	store       *scheduledAssignmentStore
+	rules       *ruleStoreStub
+	versions    *policyVersionRepoStub
+	notifier    *versionNotifierStub
+	commander   *AssignmentCommandService
+	scheduler   *ValidityScheduler
+	counselorID uint64
+}
+
+func newScheduleFixture(t *testing.T) *scheduleFixture {
+	t.Helper()
+
+	f := &scheduleFixture{
+		clock:       &fakeClock{now: time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)},
+		store:       &scheduledAssignmentStore{},
+		rules:       &ruleStoreStub{},
+		versions:    &policyVersionRepoStub{},
+		notifier:    &versionNotifierStub{},
+		counselorID: 10,
+	}
+	roleRepo := &assignmentRoleRepoStub{
+		role: &roleDomain.Role{
+			ID:       meta.FromUint64(f.counselorID),
+			Name:     "counselor",
+			TenantID: "school-a",
+		},
+	}
+	userRepo := testhelpers.NewUserRepoStub()
+	userRepo.UsersByID[123] = &userDomain.User{ID: meta.FromUint64(123)}
+
+	uow := &uowStub{tx: authzuow.TxRepositories{
+		Assignments:        f.store,
+		AssignmentSchedule: f.store,
+		Roles:              roleRepo,
+		Users:              userRepo,
+		PolicyVersions:     f.versions,
+		RuleStore:          f.rules,
+	}}
+	f.commander = NewAssignmentCommandService(assignmentDomain.NewValidator(f.store, roleRepo, userRepo), uow, &casbinAdapterStub{}, f.notifier)
+	f.commander.now = f.clock.Now
+	f.scheduler = NewValidityScheduler(uow, &casbinAdapterStub{}, f.notifier, WithClock(f.clock.Now))
+	return f
+}
+
+func (f *scheduleFixture) grant(t *testing.T, notBefore, expiresAt *time.Time) *assignmentDomain.Assignment {
+	t.Helper()
+	a, err := f.commander.Grant(context.Background(), assignmentDomain.GrantCommand{
+		SubjectType: assignmentDomain.SubjectTypeUser,
+		SubjectID:   "123",
+		RoleID:      f.counselorID,
+		TenantID:    "school-a",
+		GrantedBy:   "1",
+		NotBefore:   notBefore,
+		ExpiresAt:   expiresAt,
+	})
+	require.NoError(t, err)
+	return a
+}
+
+func TestValidityScheduler_GrantsAndRevokesAtBoundaries(t *testing.T) {
+	f := newScheduleFixture(t)
+	ctx := context.Background()
+
+	notBefore := f.clock.Now().Add(24 * time.Hour)
+	expiresAt := notBefore.Add(14 * 24 * time.Hour)
+	substitute := f.grant(t, &notBefore, &expiresAt)
+	assert.Equal(t, assignmentDomain.StatusPending, substitute.Status)
+	assert.Empty(t, f.rules.groupingAdds, "未到生效时间不写入 g 规则")
+	assert.Equal(t, 0, f.versions.incrementCalls)
+
+	n, err := f.scheduler.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	f.clock.Advance(24 * time.Hour)
+	n, err = f.scheduler.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, f.rules.groupingAdds, 1)
+	assert.Equal(t, "user:123", f.rules.groupingAdds[0].Sub)
+	assert.Equal(t, "role:counselor", f.rules.groupingAdds[0].Role)
+	assert.Equal(t, 1, f.versions.incrementCalls)
+	assert.Equal(t, 1, f.notifier.publishCalls)
+	assert.Equal(t, assignmentDomain.StatusActive, f.store.byID(substitute.ID).Status)
+
+	f.clock.Advance(14*24*time.Hour - time.Second)
+	n, err = f.scheduler.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	f.clock.Advance(time.Second)
+	n, err = f.scheduler.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, f.rules.groupingRemoves, 1)
+	assert.Equal(t, f.rules.groupingAdds[0], f.rules.groupingRemoves[0])
+	assert.Equal(t, 2, f.versions.incrementCalls)
+	assert.Equal(t, 2, f.notifier.publishCalls)
+
+	// 过期赋权保留在历史中
+	history, err := f.store.ListBySubject(ctx, assignmentDomain.SubjectTypeUser, "123", "school-a")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, assignmentDomain.StatusExpired, history[0].Status)
+
+	n, err = f.scheduler.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, 2, f.versions.incrementCalls)
+}
+
+func TestValidityScheduler_KeepsRuleHeldByAnotherAssignment(t *testing.T) {
+	f := newScheduleFixture(t)
+	ctx := context.Background()
+
+	expiresAt := f.clock.Now().Add(time.Hour)
+	temporary := f.grant(t, nil, &expiresAt)
+	assert.Equal(t, assignmentDomain.StatusActive, temporary.Status)
+	f.grant(t, nil, nil)
+	require.Len(t, f.rules.groupingAdds, 2)
+	assert.Equal(t, 2, f.versions.incrementCalls)
+
+	f.clock.Advance(time.Hour)
+	n, err := f.scheduler.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, assignmentDomain.StatusExpired, f.store.byID(temporary.ID).Status)
+	assert.Empty(t, f.rules.groupingRemoves, "永久赋权仍持有角色")
+	assert.Equal(t, 2, f.versions.incrementCalls)
+}
+
+func TestValidityScheduler_SkipsBoundaryAlreadyPassed(t *testing.T) {
+	f := newScheduleFixture(t)
+	ctx := context.Background()
+
+	notBefore := f.clock.Now().Add(time.Hour)
+	expiresAt := notBefore.Add(time.Hour)
+	a := f.grant(t, &notBefore, &expiresAt)
+
+	// 停机期间越过两个边界：直接过期，从未写入 g 规则
+	f.clock.Advance(3 * time.Hour)
+	n, err := f.scheduler.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, assignmentDomain.StatusExpired, f.store.byID(a.ID).Status)
+	assert.Empty(t, f.rules.groupingAdds)
+	assert.Empty(t, f.rules.groupingRemoves)
+	assert.Equal(t, 0, f.versions.incrementCalls)
+}
+
+func TestAssignmentCommandServiceRevoke_KeepsExpiredHistory(t *testing.T) {
+	f := newScheduleFixture(t)
+	ctx := context.Background()
+
+	expiresAt := f.clock.Now().Add(time.Hour)
+	expired := f.grant(t, nil, &expiresAt)
+	f.clock.Advance(time.Hour)
+	_, err := f.scheduler.RunOnce(ctx)
+	require.NoError(t, err)
+	f.grant(t, nil, nil)
+
+	require.NoError(t, f.commander.Revoke(ctx, assignmentDomain.RevokeCommand{
+		SubjectType: assignmentDomain.SubjectTypeUser,
+		SubjectID:   "123",
+		RoleID:      f.counselorID,
+		TenantID:    "school-a",
+	}))
+	require.Len(t, f.store.items, 1, "仅删除未过期的赋权")
+	assert.Equal(t, expired.ID, f.store.items[0].ID)
+	assert.Equal(t, assignmentDomain.StatusExpired, f.store.items[0].Status)
+	assert.Len(t, f.rules.groupingRemoves, 2)
+}
+
+func TestAssignmentCommandServiceRevokeByID_KeepsRuleHeldByAnotherAssignment(t *testing.T) {
+	f := newScheduleFixture(t)
+	ctx := context.Background()
+
+	temporary := f.grant(t, nil, nil)
+	permanent := f.grant(t, nil, nil)
+
+	require.NoError(t, f.commander.RevokeByID(ctx, assignmentDomain.RevokeByIDCommand{AssignmentID: temporary.ID, TenantID: "school-a"}))
+	assert.Nil(t, f.store.byID(temporary.ID))
+	assert.Empty(t, f.rules.groupingRemoves, "另一条生效中的赋权仍持有角色")
+
+	require.NoError(t, f.commander.RevokeByID(ctx, assignmentDomain.RevokeByIDCommand{AssignmentID: permanent.ID, TenantID: "school-a"}))
+	require.Len(t, f.rules.groupingRemoves, 1)
+	assert.Equal(t, "user:123", f.rules.groupingRemoves[0].Sub)
+}
+
+func TestAssignmentCommandServiceRevokeByID_PendingAssignmentKeepsRule(t *testing.T) {
+	f := newScheduleFixture(t)
+	ctx := context.Background()
+
+	f.grant(t, nil, nil)
+	notBefore := f.clock.Now().Add(time.Hour)
+	pending := f.grant(t, &notBefore, nil)
+
+	require.NoError(t, f.commander.RevokeByID(ctx, assignmentDomain.RevokeByIDCommand{AssignmentID: pending.ID, TenantID: "school-a"}))
+	assert.Empty(t, f.rules.groupingRemoves, "待生效赋权从未写入 g 规则")
+}
+
+func TestAssignmentCommandServiceSyncExternalRoles_KeepsManualGrantOfSameRole(t *testing.T) {
+	f := newScheduleFixture(t)
+	ctx := context.Background()
+
+	sync := assignmentDomain.SyncExternalRolesCommand{
+		SubjectType: assignmentDomain.SubjectTypeUser,
+		SubjectID:   "123",
+		TenantID:    "school-a",
+		Source:      "oidc:corp-sso",
+		RoleNames:   []string{"counselor"},
+	}
+	require.NoError(t, f.commander.SyncExternalRoles(ctx, sync))
+	manual := f.grant(t, nil, nil)
+	require.Len(t, f.store.items, 2)
+
+	// 映射不再包含该角色：只撤销来源授予的赋权，管理员手工赋权与 g 规则保留
+	sync.RoleNames = nil
+	require.NoError(t, f.commander.SyncExternalRoles(ctx, sync))
+	require.Len(t, f.store.items, 1)
+	assert.Equal(t, manual.ID, f.store.items[0].ID)
+	assert.Empty(t, f.rules.groupingRemoves)
+}
+
+func TestAssignmentCommandServiceSyncExternalRoles_GrantsDespitePendingGrant(t *testing.T) {
+	f := newScheduleFixture(t)
+
+	notBefore := f.clock.Now().Add(24 * time.Hour)
+	f.grant(t, &notBefore, nil)
+	require.NoError(t, f.commander.SyncExternalRoles(context.Background(), assignmentDomain.SyncExternalRolesCommand{
+		SubjectType: assignmentDomain.SubjectTypeUser,
+		SubjectID:   "123",
+		TenantID:    "school-a",
+		Source:      "oidc:corp-sso",
+		RoleNames:   []string{"counselor"},
+	}))
+	require.Len(t, f.store.items, 2, "待生效的手工赋权不视为已持有")
+	assert.Equal(t, "oidc:corp-sso", f.store.items[1].GrantedBy)
+	assert.Equal(t, assignmentDomain.StatusActive, f.store.items[1].Status)
+	require.Len(t, f.rules.groupingAdds, 1)
+}
+
+func TestAssignmentCommandServiceGrant_RejectsExpiredValidity(t *testing.T) {
+	f := newScheduleFixture(t)
+
+	expiresAt := f.clock.Now()
+	_, err := f.commander.Grant(context.Background(), assignmentDomain.GrantCommand{
+		SubjectType: assignmentDomain.SubjectTypeUser,
+		SubjectID:   "123",
+		RoleID:      f.counselorID,
+		TenantID:    "school-a",
+		GrantedBy:   "1",
+		ExpiresAt:   &expiresAt,
+	})
+	require.Error(t, err)
+	assert.Empty(t, f.store.items)
+}
+
+// scheduledAssignmentStore 内存赋权仓储，同时实现有效期调度仓储
+type scheduledAssignmentStore struct {
+	nextID uint64
+	items  []*assignmentDomain.Assignment
+}
+
+func (s *scheduledAssignmentStore) byID(id assignmentDomain.AssignmentID) *assignmentDomain.Assignment {
+	for _, a := range s.items {
+		if a.ID == id {
+			return a
+		}
+	}
+	return nil
+}
+
+func (s *scheduledAssignmentStore) Create(_ context.Context, a *assignmentDomain.Assignment) error {
+	s.nextID++
+	a.ID = assignmentDomain.NewAssignmentID(s.nextID)
+	stored := *a
+	s.items = append(s.items, &stored)
+	return nil
+}
+
+func (s *scheduledAssignmentStore) Delete(_ context.Context, id assignmentDomain.AssignmentID) error {
+	for i, a := range s.items {
+		if a.ID == id {
+			s.items = append(s.items[:i], s.items[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *scheduledAssignmentStore) DeleteBySubjectAndRole(_ context.Context, subjectType assignmentDomain.SubjectType, subjectID string, roleID uint64, tenantID string) error {
+	kept := s.items[:0]
+	for _, a := range s.items {
+		if a.SubjectType != subjectType || a.SubjectID != subjectID || a.RoleID != roleID || a.TenantID != tenantID {
+			kept = append(kept, a)
+		}
+	}
+	s.items = kept
+	return nil
+}
+
+func (s *scheduledAssignmentStore) FindByID(_ context.Context, id assignmentDomain.AssignmentID) (*assignmentDomain.Assignment, error) {
+	return s.byID(id), nil
+}
+
+func (s *scheduledAssignmentStore) ListBySubject(_ context.Context, subjectType assignmentDomain.SubjectType, subjectID, tenantID string) ([]*assignmentDomain.Assignment, error) {
+	var out []*assignmentDomain.Assignment
+	for _, a := range s.items {
+		if a.SubjectType == subjectType && a.SubjectID == subjectID && a.TenantID == tenantID {
+			copied := *a
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (s *scheduledAssignmentStore) ListBySubjectAcrossTenants(context.Context, assignmentDomain.SubjectType, string) ([]*assignmentDomain.Assignment, error) {
+	return nil, nil
+}
+
+func (s *scheduledAssignmentStore) ListByRole(context.Context, uint64, string) ([]*assignmentDomain.Assignment, error) {
+	return nil, nil
+}
+
+func (s *scheduledAssignmentStore) ListDue(_ context.Context, now time.Time, limit int) ([]*assignmentDomain.Assignment, error) {
+	var out []*assignmentDomain.Assignment
+	for _, a := range s.items {
+		if len(out) == limit {
+			break
+		}
+		due := (a.Status == assignmentDomain.StatusPending && a.NotBefore != nil && !a.NotBefore.After(now)) ||
+			(a.Status != assignmentDomain.StatusExpired && a.ExpiresAt != nil && !a.ExpiresAt.After(now))
+		if due {
+			copied := *a
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (s *scheduledAssignmentStore) TransitionStatus(_ context.Context, id assignmentDomain.AssignmentID, from, to assignmentDomain.Status) (bool, error) {
+	a := s.byID(id)
+	if a == nil || a.Status != from {
+		return false, nil
+	}
+	a.Status = to
+	return true, nil
+}
@@ -0,0 +1,368 @@
+// Package history 策略变更历史应用服务
+//
+// 每次授权写事务都会把实际生效的 Casbin 规则变更随新版本落库（见 authzshared.BumpVersion），
+// 本服务基于这些变更集提供版本列表、任意两个版本间的差异，以及按版本回滚。
+// 回滚通过重放逆向变更集实现，本身也产生一个新版本，因此可以再次回滚。
+package history
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/log"
+	"gorm.io/gorm"
+
+	authzshared "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/shared"
+	authzuow "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/uow"
+	assignmentDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
+	policyDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
+	roleDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/role"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+// 版本列表分页默认值与上限
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// Service 策略变更历史应用服务，同时实现 HistoryCommander 与 HistoryQueryer
+type Service struct {
+	uow             authzuow.UnitOfWork
+	casbinAdapter   policyDomain.CasbinAdapter
+	versionNotifier policyDomain.VersionNotifier
+}
+
+var (
+	_ policyDomain.HistoryCommander = (*Service)(nil)
+	_ policyDomain.HistoryQueryer   = (*Service)(nil)
+)
+
+// NewService 创建策略变更历史应用服务
+func NewService(
+	uow authzuow.UnitOfWork,
+	casbinAdapter policyDomain.CasbinAdapter,
+	versionNotifier policyDomain.VersionNotifier,
+) *Service {
+	return &Service{
+		uow:             uow,
+		casbinAdapter:   casbinAdapter,
+		versionNotifier: versionNotifier,
+	}
+}
+
+// ListVersions 分页列出版本及其变更集
+func (s *Service) ListVersions(ctx context.Context, query policyDomain.ListVersionsQuery) ([]*policyDomain.PolicyVersion, int64, error) {
+	if query.TenantID == "" {
+		return nil, 0, errors.WithCode(code.ErrInvalidArgument, "租户ID不能为空")
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultLimit
+	}
+	if query.Limit > maxLimit {
+		query.Limit = maxLimit
+	}
+
+	var (
+		versions []*policyDomain.PolicyVersion
+		total    int64
+	)
+	err := s.uow.WithinTx(ctx, func(tx authzuow.TxRepositories) error {
+		var err error
+		versions, total, err = tx.VersionHistory.ListVersions(ctx, query.TenantID, query.Offset, query.Limit)
+		return err
+	})
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "获取策略版本列表失败")
+	}
+	return versions, total, nil
+}
+
+// GetVersion 获取指定版本及其变更集
+func (s *Service) GetVersion(ctx context.Context, tenantID string, version int64) (*policyDomain.PolicyVersion, error) {
+	if tenantID == "" {
+		return nil, errors.WithCode(code.ErrInvalidArgument, "租户ID不能为空")
+	}
+
+	var found *policyDomain.PolicyVersion
+	err := s.uow.WithinTx(ctx, func(tx authzuow.TxRepositories) error {
+		var err error
+		found, err = findVersion(ctx, tx, tenantID, version)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// Diff 计算两个版本之间的净变更，From 大于 To 时返回从 From 回到 To 所需的变更
+func (s *Service) Diff(ctx context.Context, query policyDomain.DiffVersionsQuery) ([]policyDomain.RuleChange, error) {
+	if query.TenantID == "" {
+		return nil, errors.WithCode(code.ErrInvalidArgument, "租户ID不能为空")
+	}
+
+	var changes []policyDomain.RuleChange
+	err := s.uow.WithinTx(ctx, func(tx authzuow.TxRepositories) error {
+		for _, v := range []int64{query.From, query.To} {
+			if _, err := findVersion(ctx, tx, query.TenantID, v); err != nil {
+				return err
+			}
+		}
+		var err error
+		changes, err = diff(ctx, tx, query.TenantID, query.From, query.To)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// Rollback 把租户授权规则恢复到指定版本
+//
+// 回滚只作用于版本之后实际发生的规则变更：重新授予的赋权会补回赋权记录，
+// 撤销的赋权会同时删除赋权记录。变更涉及的角色或资源已被删除时拒绝回滚。
+// 目标版本即当前版本或期间规则无净变化时不产生新版本，返回当前版本。
+func (s *Service) Rollback(ctx context.Context, cmd policyDomain.RollbackCommand) (*policyDomain.PolicyVersion, error) {
+	if cmd.TenantID == "" {
+		return nil, errors.WithCode(code.ErrInvalidArgument, "租户ID不能为空")
+	}
+	if cmd.ChangedBy == "" {
+		return nil, errors.WithCode(code.ErrInvalidArgument, "变更人不能为空")
+	}
+	reason := cmd.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("rollback to version %d", cmd.TargetVersion)
+	}
+
+	var (
+		version *policyDomain.PolicyVersion
+		queued  bool
+		changed bool
+	)
+	err := s.uow.WithinTx(ctx, func(tx authzuow.TxRepositories) error {
+		if _, err := findVersion(ctx, tx, cmd.TenantID, cmd.TargetVersion); err != nil {
+			return err
+		}
+		current, err := tx.PolicyVersions.GetCurrent(ctx, cmd.TenantID)
+		if err != nil {
+			return errors.Wrap(err, "获取当前策略版本失败")
+		}
+		version = current
+		if current.Version == cmd.TargetVersion {
+			return nil
+		}
+		if current.Version < cmd.TargetVersion {
+			return errors.WithCode(code.ErrInvalidArgument, "目标版本 %d 晚于当前版本 %d", cmd.TargetVersion, current.Version)
+		}
+
+		changes, err := diff(ctx, tx, cmd.TenantID, current.Version, cmd.TargetVersion)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		if err := applyChanges(ctx, tx, cmd.TenantID, changes, cmd.ChangedBy); err != nil {
+			return err
+		}
+
+		version, queued, err = authzshared.BumpVersion(ctx, tx, cmd.TenantID, cmd.ChangedBy, reason)
+		if err != nil {
+			return errors.Wrap(err, "更新授权版本失败")
+		}
+		changed = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if changed {
+		if !queued {
+			s.publishVersion(ctx, cmd.TenantID, version)
+		}
+		authzshared.ReloadRuntimePolicy(ctx, s.casbinAdapter, "policy rollback")
+	}
+	return version, nil
+}
+
+// applyChanges 在事务内逐条应用规则变更，g 规则同时维护赋权记录
+func applyChanges(ctx context.Context, tx authzuow.TxRepositories, tenantID string, changes []policyDomain.RuleChange, changedBy string) error {
+	validator := assignmentDomain.NewValidator(tx.Assignments, tx.Roles, tx.Users)
+	for _, c := range changes {
+		role, err := findRole(ctx, tx, tenantID, c.RoleKey())
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case c.PType == policyDomain.PTypePolicy && c.Op == policyDomain.ChangeOpAdd:
+			if role == nil {
+				return errors.WithCode(code.ErrPolicyRollbackConflict, "角色 %s 已删除，无法恢复其权限", c.Sub)
+			}
+			res, err := tx.Resources.FindByKey(ctx, c.Obj)
+			if err != nil {
+				return errors.Wrapf(err, "获取资源 %s 失败", c.Obj)
+			}
+			if res == nil {
+				return errors.WithCode(code.ErrPolicyRollbackConflict, "资源 %s 已删除，无法恢复权限", c.Obj)
+			}
+			if err := tx.RuleStore.AddPolicy(ctx, c.PolicyRule()); err != nil {
+				return errors.Wrap(err, "添加 Casbin 策略规则失败")
+			}
+
+		case c.PType == policyDomain.PTypePolicy:
+			if err := tx.RuleStore.RemovePolicy(ctx, c.PolicyRule()); err != nil {
+				return errors.Wrap(err, "删除 Casbin 策略规则失败")
+			}
+
+		case c.Op == policyDomain.ChangeOpAdd:
+			if role == nil {
+				return errors.WithCode(code.ErrPolicyRollbackConflict, "角色 %s 已删除，无法恢复赋权", c.Role)
+			}
+			subjectType, subjectID, ok := parseSubject(c.Sub)
+			if !ok {
+				return errors.WithCode(code.ErrPolicyRollbackConflict, "无法识别的主体 %s", c.Sub)
+			}
+			if err := validator.CheckSubjectExists(ctx, subjectType, subjectID, tenantID); err != nil {
+				return err
+			}
+			if err := restoreAssignment(ctx, tx, subjectType, subjectID, role.ID.Uint64(), tenantID, changedBy); err != nil {
+				return err
+			}
+			if err := tx.RuleStore.AddGroupingPolicy(ctx, c.GroupingRule()); err != nil {
+				return errors.Wrap(err, "添加 Casbin 分组规则失败")
+			}
+
+		default:
+			// 只删除生效中的赋权；待生效与已过期的记录由有效期调度器维护
+			if subjectType, subjectID, ok := parseSubject(c.Sub); ok && role != nil {
+				held, err := listRoleAssignments(ctx, tx, subjectType, subjectID, role.ID.Uint64(), tenantID)
+				if err != nil {
+					return err
+				}
+				for _, a := range held {
+					if !a.Effective() {
+						continue
+					}
+					if err := tx.Assignments.Delete(ctx, a.ID); err != nil {
+						return errors.Wrap(err, "删除赋权记录失败")
+					}
+				}
+			}
+			if err := tx.RuleStore.RemoveGroupingPolicy(ctx, c.GroupingRule()); err != nil {
+				return errors.Wrap(err, "删除 Casbin 分组规则失败")
+			}
+		}
+	}
+	return nil
+}
+
+// restoreAssignment 恢复 g 规则对应的赋权记录
+// 仍在生效中的原赋权保留其有效期直接沿用；原赋权已过期时拒绝回滚，避免越过有效期重新授予；
+// 原赋权已被撤销时重新创建永久赋权
+func restoreAssignment(ctx context.Context, tx authzuow.TxRepositories, subjectType assignmentDomain.SubjectType, subjectID string, roleID uint64, tenantID, changedBy string) error {
+	held, err := listRoleAssignments(ctx, tx, subjectType, subjectID, roleID, tenantID)
+	if err != nil {
+		return err
+	}
+	expired := false
+	for _, a := range held {
+		if a.Effective() {
+			return nil
+		}
+		if a.Status == assignmentDomain.StatusExpired {
+			expired = true
+		}
+	}
+	if expired {
+		return errors.WithCode(code.ErrPolicyRollbackConflict, "主体 %s:%s 的赋权已过期，无法恢复", subjectType, subjectID)
+	}
+
+	created := assignmentDomain.NewAssignment(subjectType, subjectID, roleID, tenantID,
+		assignmentDomain.WithGrantedBy(changedBy))
+	if err := tx.Assignments.Create(ctx, &created); err != nil {
+		return errors.Wrap(err, "创建赋权失败")
+	}
+	return nil
+}
+
+// listRoleAssignments 获取主体在租户内持有指定角色的全部赋权，含待生效与已过期记录
+func listRoleAssignments(ctx context.Context, tx authzuow.TxRepositories, subjectType assignmentDomain.SubjectType, subjectID string, roleID uint64, tenantID string) ([]*assignmentDomain.Assignment, error) {
+	all, err := tx.Assignments.ListBySubject(ctx, subjectType, subjectID, tenantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "获取主体赋权失败")
+	}
+	held := all[:0]
+	for _, a := range all {
+		if a.RoleID == roleID {
+			held = append(held, a)
+		}
+	}
+	return held, nil
+}
+
+// diff 读取 (min, max] 区间的版本并计算净变更
+func diff(ctx context.Context, tx authzuow.TxRepositories, tenantID string, from, to int64) ([]policyDomain.RuleChange, error) {
+	low, high := from, to
+	if low > high {
+		low, high = high, low
+	}
+	versions, err := tx.VersionHistory.ListRange(ctx, tenantID, low, high)
+	if err != nil {
+		return nil, errors.Wrap(err, "获取策略版本历史失败")
+	}
+	return policyDomain.DiffVersions(versions, from, to)
+}
+
+func findVersion(ctx context.Context, tx authzuow.TxRepositories, tenantID string, version int64) (*policyDomain.PolicyVersion, error) {
+	found, err := tx.VersionHistory.FindVersion(ctx, tenantID, version)
+	if err != nil {
+		return nil, errors.Wrap(err, "获取策略版本失败")
+	}
+	if found == nil {
+		return nil, errors.WithCode(code.ErrPolicyVersionNotFound, "策略版本 %d 不存在", version)
+	}
+	return found, nil
+}
+
+// findRole 按 Casbin 角色键查找租户角色，不存在时返回 nil
+func findRole(ctx context.Context, tx authzuow.TxRepositories, tenantID, roleKey string) (*roleDomain.Role, error) {
+	name, ok := strings.CutPrefix(roleKey, "role:")
+	if !ok {
+		return nil, nil
+	}
+	role, err := tx.Roles.FindByName(ctx, tenantID, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "获取角色 %s 失败", name)
+	}
+	return role, nil
+}
+
+// parseSubject 把 Casbin 主体键（如 user:123）拆分为主体类型与 ID
+func parseSubject(key string) (assignmentDomain.SubjectType, string, bool) {
+	subjectType, subjectID, ok := strings.Cut(key, ":")
+	if !ok || subjectID == "" {
+		return "", "", false
+	}
+	return assignmentDomain.SubjectType(subjectType), subjectID, true
+}
+
+func (s *Service) publishVersion(ctx context.Context, tenantID string, version *policyDomain.PolicyVersion) {
+	if s.versionNotifier == nil || version == nil {
+		return
+	}
+	if err := s.versionNotifier.Publish(ctx, tenantID, version.Version); err != nil {
+		log.Errorw("failed to publish policy rollback version", "tenant_id", tenantID, "version", version.Version, "error", err)
+	}
+}
@@ -0,0 +1,411 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	authzshared "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/shared"
+	authzuow "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/uow"
+	assignmentDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
+	policyDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
+	resourceDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/resource"
+	roleDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/role"
+	userDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/uc/user"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/testhelpers"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+var (
+	readRule  = policyDomain.NewPolicyRule("role:counselor", "t1", "scale:form:*", "read_all")
+	aliceRule = policyDomain.NewGroupingRule("user:123", "t1", "role:counselor")
+	bobRule   = policyDomain.NewGroupingRule("user:124", "t1", "role:counselor")
+)
+
+type fixture struct {
+	service     *Service
+	uow         *uowStub
+	roles       *roleStore
+	assignments *assignmentStore
+	rules       *ruleStore
+	versions    *versionStore
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	counselor := roleDomain.NewRole("counselor", "咨询师", "t1", roleDomain.WithID(meta.FromUint64(10)))
+	form := resourceDomain.NewResource("scale:form:*", []string{"read_all"})
+	users := testhelpers.NewUserRepoStub()
+	users.UsersByID[123] = &userDomain.User{ID: meta.FromUint64(123)}
+	users.UsersByID[124] = &userDomain.User{ID: meta.FromUint64(124)}
+
+	f := &fixture{
+		roles:       &roleStore{byName: map[string]*roleDomain.Role{"counselor": &counselor}},
+		assignments: &assignmentStore{},
+		rules:       &ruleStore{present: map[string]bool{}},
+		versions:    &versionStore{},
+	}
+	f.uow = &uowStub{newTx: func() authzuow.TxRepositories {
+		recorder := policyDomain.NewChangeRecorder()
+		f.rules.recorder = recorder
+		return authzuow.TxRepositories{
+			Assignments:    f.assignments,
+			Roles:          f.roles,
+			Resources:      &resourceStore{byKey: map[string]*resourceDomain.Resource{form.Key: &form}},
+			PolicyVersions: f.versions,
+			VersionHistory: f.versions,
+			Users:          users,
+			RuleStore:      f.rules,
+			RuleChanges:    recorder,
+		}
+	}}
+	f.service = NewService(f.uow, nil, nil)
+
+	// v1：初始化版本，记录空变更集
+	f.mutate(t, func(tx authzuow.TxRepositories) error { return nil })
+	// v2：授予权限并给 alice、bob 赋权
+	f.mutate(t, func(tx authzuow.TxRepositories) error {
+		f.assignments.add("123")
+		f.assignments.add("124")
+		if err := tx.RuleStore.AddPolicy(context.Background(), readRule); err != nil {
+			return err
+		}
+		return tx.RuleStore.AddGroupingPolicy(context.Background(), aliceRule, bobRule)
+	})
+	// v3：误操作批量撤销赋权
+	f.mutate(t, func(tx authzuow.TxRepositories) error {
+		f.assignments.items = nil
+		return tx.RuleStore.RemoveGroupingPolicy(context.Background(), aliceRule, bobRule)
+	})
+	return f
+}
+
+// mutate 模拟一次授权写事务：执行变更后递增版本
+func (f *fixture) mutate(t *testing.T, fn func(tx authzuow.TxRepositories) error) {
+	t.Helper()
+	err := f.uow.WithinTx(context.Background(), func(tx authzuow.TxRepositories) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+		_, _, err := authzshared.BumpVersion(context.Background(), tx, "t1", "9", "test")
+		return err
+	})
+	require.NoError(t, err)
+}
+
+func TestDiff_BetweenAnyTwoVersions(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	forward, err := f.service.Diff(ctx, policyDomain.DiffVersionsQuery{TenantID: "t1", From: 1, To: 3})
+	require.NoError(t, err)
+	assert.Equal(t, []policyDomain.RuleChange{
+		policyDomain.PolicyChange(policyDomain.ChangeOpAdd, readRule),
+	}, forward)
+
+	backward, err := f.service.Diff(ctx, policyDomain.DiffVersionsQuery{TenantID: "t1", From: 3, To: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []policyDomain.RuleChange{
+		policyDomain.GroupingChange(policyDomain.ChangeOpAdd, bobRule),
+		policyDomain.GroupingChange(policyDomain.ChangeOpAdd, aliceRule),
+	}, backward)
+
+	_, err = f.service.Diff(ctx, policyDomain.DiffVersionsQuery{TenantID: "t1", From: 1, To: 9})
+	assert.True(t, errors.IsCode(err, code.ErrPolicyVersionNotFound))
+}
+
+func TestRollback_RestoresRevokedAssignmentsAsNewVersion(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	version, err := f.service.Rollback(ctx, policyDomain.RollbackCommand{TenantID: "t1", TargetVersion: 2, ChangedBy: "9"})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(4), version.Version)
+	assert.Equal(t, "rollback to version 2", version.Reason)
+	assert.Len(t, version.Changes, 2)
+	assert.True(t, f.rules.present[ruleKey(policyDomain.GroupingChange(policyDomain.ChangeOpAdd, aliceRule))])
+	assert.True(t, f.rules.present[ruleKey(policyDomain.GroupingChange(policyDomain.ChangeOpAdd, bobRule))])
+	require.Len(t, f.assignments.items, 2)
+	assert.Equal(t, "9", f.assignments.items[0].GrantedBy)
+
+	// 回滚本身也可以被回滚
+	version, err = f.service.Rollback(ctx, policyDomain.RollbackCommand{TenantID: "t1", TargetVersion: 3, ChangedBy: "9"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), version.Version)
+	assert.Empty(t, f.assignments.items)
+
+	// 恢复到初始版本会撤销权限
+	_, err = f.service.Rollback(ctx, policyDomain.RollbackCommand{TenantID: "t1", TargetVersion: 1, ChangedBy: "9"})
+	require.NoError(t, err)
+	assert.Empty(t, f.rules.present)
+}
+
+func TestRollback_RespectsAssignmentValidity(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	hourAgo, inHour := now.Add(-time.Hour), now.Add(time.Hour)
+
+	t.Run("expired assignment", func(t *testing.T) {
+		f := newFixture(t)
+		f.assignments.add("123", assignmentDomain.WithValidity(nil, &hourAgo), assignmentDomain.WithStatus(assignmentDomain.StatusExpired))
+		_, err := f.service.Rollback(ctx, policyDomain.RollbackCommand{TenantID: "t1", TargetVersion: 2, ChangedBy: "9"})
+		assert.True(t, errors.IsCode(err, code.ErrPolicyRollbackConflict))
+		assert.Len(t, f.versions.items, 3)
+	})
+
+	t.Run("active assignment keeps validity", func(t *testing.T) {
+		f := newFixture(t)
+		alice := f.assignments.add("123", assignmentDomain.WithValidity(nil, &inHour))
+		_, err := f.service.Rollback(ctx, policyDomain.RollbackCommand{TenantID: "t1", TargetVersion: 2, ChangedBy: "9"})
+		require.NoError(t, err)
+		require.Len(t, f.assignments.items, 2, "生效中的原赋权直接沿用，只为 bob 重新创建")
+		assert.Equal(t, alice.ID, f.assignments.items[0].ID)
+		assert.Equal(t, &inHour, f.assignments.items[0].ExpiresAt)
+		assert.True(t, f.rules.present[ruleKey(policyDomain.GroupingChange(policyDomain.ChangeOpAdd, aliceRule))])
+
+		// 回滚撤销规则时只删除生效中的赋权，待生效与已过期的记录保留
+		pending := f.assignments.add("123", assignmentDomain.WithValidity(&inHour, nil), assignmentDomain.WithStatus(assignmentDomain.StatusPending))
+		expired := f.assignments.add("124", assignmentDomain.WithValidity(nil, &hourAgo), assignmentDomain.WithStatus(assignmentDomain.StatusExpired))
+		_, err = f.service.Rollback(ctx, policyDomain.RollbackCommand{TenantID: "t1", TargetVersion: 3, ChangedBy: "9"})
+		require.NoError(t, err)
+		require.Len(t, f.assignments.items, 2)
+		assert.Equal(t, pending.ID, f.assignments.items[0].ID)
+		assert.Equal(t, expired.ID, f.assignments.items[1].ID)
+		assert.False(t, f.rules.present[ruleKey(policyDomain.GroupingChange(policyDomain.ChangeOpAdd, aliceRule))])
+	})
+}
+
+func TestRollback_NoNetChangeDoesNotBumpVersion(t *testing.T) {
+	f := newFixture(t)
+	f.mutate(t, func(tx authzuow.TxRepositories) error {
+		return tx.RuleStore.AddGroupingPolicy(context.Background(), aliceRule)
+	})
+	f.mutate(t, func(tx authzuow.TxRepositories) error {
+		return tx.RuleStore.RemoveGroupingPolicy(context.Background(), aliceRule)
+	})
+
+	version, err := f.service.Rollback(context.Background(), policyDomain.RollbackCommand{TenantID: "t1", TargetVersion: 3, ChangedBy: "9"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), version.Version)
+	assert.Len(t, f.versions.items, 5)
+}
+
+func TestRollback_Rejections(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deleted role", func(t *testing.T) {
+		f := newFixture(t)
+		delete(f.roles.byName, "counselor")
+		_, err := f.service.Rollback(ctx, policyDomain.RollbackCommand{TenantID: "t1", TargetVersion: 2, ChangedBy: "9"})
+		assert.True(t, errors.IsCode(err, code.ErrPolicyRollbackConflict))
+		assert.Len(t, f.versions.items, 3)
+	})
+
+	t.Run("unrecorded history", func(t *testing.T) {
+		f := newFixture(t)
+		f.versions.items[1].Changes = nil
+		_, err := f.service.Rollback(ctx, policyDomain.RollbackCommand{TenantID: "t1", TargetVersion: 1, ChangedBy: "9"})
+		assert.True(t, errors.IsCode(err, code.ErrPolicyHistoryIncomplete))
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		f := newFixture(t)
+		_, err := f.service.Rollback(ctx, policyDomain.RollbackCommand{TenantID: "t1", TargetVersion: 7, ChangedBy: "9"})
+		assert.True(t, errors.IsCode(err, code.ErrPolicyVersionNotFound))
+	})
+
+	t.Run("missing changed by", func(t *testing.T) {
+		f := newFixture(t)
+		_, err := f.service.Rollback(ctx, policyDomain.RollbackCommand{TenantID: "t1", TargetVersion: 2})
+		assert.True(t, errors.IsCode(err, code.ErrInvalidArgument))
+	})
+}
+
+func TestListVersions_ReturnsNewestFirst(t *testing.T) {
+	f := newFixture(t)
+
+	versions, total, err := f.service.ListVersions(context.Background(), policyDomain.ListVersionsQuery{TenantID: "t1", Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, versions, 2)
+	assert.Equal(t, int64(3), versions[0].Version)
+	assert.Len(t, versions[0].Changes, 2)
+}
+
+// uowStub 每个事务创建新的变更记录器，与真实工作单元一致
+type uowStub struct {
+	newTx func() authzuow.TxRepositories
+}
+
+func (u *uowStub) WithinTx(_ context.Context, fn func(tx authzuow.TxRepositories) error) error {
+	return fn(u.newTx())
+}
+
+type roleStore struct {
+	roleDomain.Repository
+	byName map[string]*roleDomain.Role
+}
+
+func (s *roleStore) FindByName(_ context.Context, _ string, name string) (*roleDomain.Role, error) {
+	if r, ok := s.byName[name]; ok {
+		return r, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type resourceStore struct {
+	resourceDomain.Repository
+	byKey map[string]*resourceDomain.Resource
+}
+
+func (s *resourceStore) FindByKey(_ context.Context, key string) (*resourceDomain.Resource, error) {
+	return s.byKey[key], nil
+}
+
+type assignmentStore struct {
+	assignmentDomain.Repository
+	nextID uint64
+	items  []*assignmentDomain.Assignment
+}
+
+func (s *assignmentStore) add(subjectID string, opts ...assignmentDomain.AssignmentOption) *assignmentDomain.Assignment {
+	a := assignmentDomain.NewAssignment(assignmentDomain.SubjectTypeUser, subjectID, 10, "t1", opts...)
+	_ = s.Create(context.Background(), &a)
+	return s.items[len(s.items)-1]
+}
+
+func (s *assignmentStore) Create(_ context.Context, a *assignmentDomain.Assignment) error {
+	s.nextID++
+	a.ID = assignmentDomain.NewAssignmentID(s.nextID)
+	copied := *a
+	s.items = append(s.items, &copied)
+	return nil
+}
+
+func (s *assignmentStore) Delete(_ context.Context, id assignmentDomain.AssignmentID) error {
+	for i, a := range s.items {
+		if a.ID == id {
+			s.items = append(s.items[:i], s.items[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *assignmentStore) ListBySubject(_ context.Context, subjectType assignmentDomain.SubjectType, subjectID, tenantID string) ([]*assignmentDomain.Assignment, error) {
+	var out []*assignmentDomain.Assignment
+	for _, a := range s.items {
+		if a.SubjectType == subjectType && a.SubjectID == subjectID && a.TenantID == tenantID {
+			copied := *a
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+// ruleStore 与 casbinrule 记录仓储一致：只登记实际生效的变更
+type ruleStore struct {
+	present  map[string]bool
+	recorder *policyDomain.ChangeRecorder
+}
+
+func ruleKey(c policyDomain.RuleChange) string { return c.RuleKey() }
+
+func (s *ruleStore) apply(changes ...policyDomain.RuleChange) {
+	for _, c := range changes {
+		key := ruleKey(c)
+		if (c.Op == policyDomain.ChangeOpAdd) == s.present[key] {
+			continue
+		}
+		if c.Op == policyDomain.ChangeOpAdd {
+			s.present[key] = true
+		} else {
+			delete(s.present, key)
+		}
+		s.recorder.Record(c)
+	}
+}
+
+func (s *ruleStore) AddPolicy(_ context.Context, rules ...policyDomain.PolicyRule) error {
+	for _, r := range rules {
+		s.apply(policyDomain.PolicyChange(policyDomain.ChangeOpAdd, r))
+	}
+	return nil
+}
+
+func (s *ruleStore) RemovePolicy(_ context.Context, rules ...policyDomain.PolicyRule) error {
+	for _, r := range rules {
+		s.apply(policyDomain.PolicyChange(policyDomain.ChangeOpRemove, r))
+	}
+	return nil
+}
+
+func (s *ruleStore) AddGroupingPolicy(_ context.Context, rules ...policyDomain.GroupingRule) error {
+	for _, r := range rules {
+		s.apply(policyDomain.GroupingChange(policyDomain.ChangeOpAdd, r))
+	}
+	return nil
+}
+
+func (s *ruleStore) RemoveGroupingPolicy(_ context.Context, rules ...policyDomain.GroupingRule) error {
+	for _, r := range rules {
+		s.apply(policyDomain.GroupingChange(policyDomain.ChangeOpRemove, r))
+	}
+	return nil
+}
+
+type versionStore struct {
+	items []*policyDomain.PolicyVersion
+}
+
+func (s *versionStore) GetOrCreate(ctx context.Context, tenantID string) (*policyDomain.PolicyVersion, error) {
+	return s.GetCurrent(ctx, tenantID)
+}
+
+func (s *versionStore) Increment(_ context.Context, tenantID, changedBy, reason string, opts ...policyDomain.PolicyVersionOption) (*policyDomain.PolicyVersion, error) {
+	opts = append([]policyDomain.PolicyVersionOption{policyDomain.WithChangedBy(changedBy), policyDomain.WithReason(reason)}, opts...)
+	v := policyDomain.NewPolicyVersion(tenantID, int64(len(s.items)+1), opts...)
+	s.items = append(s.items, &v)
+	return &v, nil
+}
+
+func (s *versionStore) GetCurrent(context.Context, string) (*policyDomain.PolicyVersion, error) {
+	if len(s.items) == 0 {
+		return nil, nil
+	}
+	return s.items[len(s.items)-1], nil
+}
+
+func (s *versionStore) ListVersions(_ context.Context, _ string, offset, limit int) ([]*policyDomain.PolicyVersion, int64, error) {
+	var out []*policyDomain.PolicyVersion
+	for i := len(s.items) - 1 - offset; i >= 0 && len(out) < limit; i-- {
+		out = append(out, s.items[i])
+	}
+	return out, int64(len(s.items)), nil
+}
+
+func (s *versionStore) FindVersion(_ context.Context, _ string, version int64) (*policyDomain.PolicyVersion, error) {
+	for _, v := range s.items {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *versionStore) ListRange(_ context.Context, _ string, from, to int64) ([]*policyDomain.PolicyVersion, error) {
+	var out []*policyDomain.PolicyVersion
+	for _, v := range s.items {
+		if v.Version > from && v.Version <= to {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
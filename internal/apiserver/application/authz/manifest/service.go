@@ -0,0 +1,286 @@
+// Package manifest 声明式授权配置应用服务
+//
+// 导出、计划与导入都在授权工作单元内读取现状，导入时全部变更与一次版本递增处于同一事务，
+// 任一步失败整体回滚，避免出现“角色已建、权限未落”的半成品配置。
+package manifest
+
+import (
+	"context"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/log"
+	authzshared "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/shared"
+	authzuow "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/uow"
+	assignmentDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/manifest"
+	policyDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
+	resourceDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/resource"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+// pageSize 读取现状时的分页大小
+const pageSize = 500
+
+// defaultReason 未给出变更原因时记录到策略版本的原因
+const defaultReason = "declarative config apply"
+
+// Service 声明式授权配置应用服务，同时实现 Commander 与 Queryer
+type Service struct {
+	uow             authzuow.UnitOfWork
+	casbinAdapter   policyDomain.CasbinAdapter
+	versionNotifier policyDomain.VersionNotifier
+}
+
+var (
+	_ domain.Commander = (*Service)(nil)
+	_ domain.Queryer   = (*Service)(nil)
+)
+
+// NewService 创建声明式授权配置应用服务
+func NewService(
+	uow authzuow.UnitOfWork,
+	casbinAdapter policyDomain.CasbinAdapter,
+	versionNotifier policyDomain.VersionNotifier,
+) *Service {
+	return &Service{
+		uow:             uow,
+		casbinAdapter:   casbinAdapter,
+		versionNotifier: versionNotifier,
+	}
+}
+
+// Export 导出租户当前授权配置
+func (s *Service) Export(ctx context.Context, query domain.ExportQuery) (*domain.Manifest, error) {
+	if query.TenantID == "" {
+		return nil, errors.WithCode(code.ErrInvalidArgument, "租户ID不能为空")
+	}
+
+	var snapshot *domain.Snapshot
+	err := s.uow.WithinTx(ctx, func(tx authzuow.TxRepositories) error {
+		var err error
+		snapshot, err = loadSnapshot(ctx, tx, query.TenantID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Export(query.IncludeAssignments)
+}
+
+// Plan 计算导入文档将产生的变更，不产生副作用
+func (s *Service) Plan(ctx context.Context, cmd domain.ApplyCommand) (*domain.Plan, error) {
+	if err := validateApplyCommand(cmd); err != nil {
+		return nil, err
+	}
+
+	var plan *domain.Plan
+	err := s.uow.WithinTx(ctx, func(tx authzuow.TxRepositories) error {
+		snapshot, err := loadSnapshot(ctx, tx, cmd.TenantID)
+		if err != nil {
+			return err
+		}
+		plan, err = domain.BuildPlan(snapshot, cmd.Manifest, domain.PlanOptions{Prune: cmd.Prune})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Apply 在单个事务内把租户授权配置变更为文档描述的状态
+func (s *Service) Apply(ctx context.Context, cmd domain.ApplyCommand) (*domain.ApplyResult, error) {
+	if err := validateApplyCommand(cmd); err != nil {
+		return nil, err
+	}
+	if cmd.ChangedBy == "" {
+		return nil, errors.WithCode(code.ErrInvalidArgument, "变更人不能为空")
+	}
+	reason := cmd.Reason
+	if reason == "" {
+		reason = defaultReason
+	}
+
+	var (
+		plan    *domain.Plan
+		version *policyDomain.PolicyVersion
+		queued  bool
+	)
+	err := s.uow.WithinTx(ctx, func(tx authzuow.TxRepositories) error {
+		snapshot, err := loadSnapshot(ctx, tx, cmd.TenantID)
+		if err != nil {
+			return err
+		}
+		plan, err = domain.BuildPlan(snapshot, cmd.Manifest, domain.PlanOptions{Prune: cmd.Prune})
+		if err != nil {
+			return err
+		}
+		if plan.Empty() {
+			return nil
+		}
+		if err := executePlan(ctx, tx, plan, cmd.ChangedBy); err != nil {
+			return err
+		}
+		version, queued, err = authzshared.BumpVersion(ctx, tx, cmd.TenantID, cmd.ChangedBy, reason)
+		if err != nil {
+			return errors.Wrap(err, "更新授权版本失败")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.ApplyResult{Plan: plan}
+	if plan.Empty() {
+		return result, nil
+	}
+	result.Version = version.Version
+	if !queued {
+		s.publishVersion(ctx, cmd.TenantID, version)
+	}
+	authzshared.ReloadRuntimePolicy(ctx, s.casbinAdapter, "declarative config apply")
+	return result, nil
+}
+
+// executePlan 按依赖顺序执行计划：先建资源与角色，再撤销旧规则与赋权、删除角色，最后写入新规则与赋权
+func executePlan(ctx context.Context, tx authzuow.TxRepositories, plan *domain.Plan, changedBy string) error {
+	for i := range plan.ResourceCreates {
+		if err := tx.Resources.Create(ctx, &plan.ResourceCreates[i]); err != nil {
+			return errors.Wrapf(err, "创建资源 %s 失败", plan.ResourceCreates[i].Key)
+		}
+	}
+	for i := range plan.ResourceUpdates {
+		if err := tx.Resources.Update(ctx, &plan.ResourceUpdates[i]); err != nil {
+			return errors.Wrapf(err, "更新资源 %s 失败", plan.ResourceUpdates[i].Key)
+		}
+	}
+	for i := range plan.RoleCreates {
+		if err := tx.Roles.Create(ctx, &plan.RoleCreates[i]); err != nil {
+			return errors.Wrapf(err, "创建角色 %s 失败", plan.RoleCreates[i].Name)
+		}
+	}
+	for i := range plan.RoleUpdates {
+		if err := tx.Roles.Update(ctx, &plan.RoleUpdates[i]); err != nil {
+			return errors.Wrapf(err, "更新角色 %s 失败", plan.RoleUpdates[i].Name)
+		}
+	}
+
+	if err := tx.RuleStore.RemovePolicy(ctx, plan.PolicyRemoves...); err != nil {
+		return errors.Wrap(err, "删除 Casbin 策略规则失败")
+	}
+	for _, c := range plan.AssignmentRevokes {
+		// 已过期的赋权仅作历史保留，只删除待生效与生效中的记录
+		held, err := tx.Assignments.ListBySubject(ctx, c.SubjectType, c.SubjectID, plan.TenantID)
+		if err != nil {
+			return errors.Wrap(err, "获取主体赋权失败")
+		}
+		for _, a := range held {
+			if a.RoleID != c.RoleID || a.Status == assignmentDomain.StatusExpired {
+				continue
+			}
+			if err := tx.Assignments.Delete(ctx, a.ID); err != nil {
+				return errors.Wrap(err, "删除赋权记录失败")
+			}
+		}
+		rule := policyDomain.NewGroupingRule(c.SubjectKey(), plan.TenantID, "role:"+c.RoleName)
+		if err := tx.RuleStore.RemoveGroupingPolicy(ctx, rule); err != nil {
+			return errors.Wrap(err, "删除 Casbin 分组规则失败")
+		}
+	}
+	for _, r := range plan.RoleDeletes {
+		if err := tx.Roles.Delete(ctx, r.ID); err != nil {
+			return errors.Wrapf(err, "删除角色 %s 失败", r.Name)
+		}
+	}
+
+	if err := tx.RuleStore.AddPolicy(ctx, plan.PolicyAdds...); err != nil {
+		return errors.Wrap(err, "添加 Casbin 策略规则失败")
+	}
+	validator := assignmentDomain.NewValidator(tx.Assignments, tx.Roles, tx.Users)
+	for _, c := range plan.AssignmentGrants {
+		if err := validator.CheckSubjectExists(ctx, c.SubjectType, c.SubjectID, plan.TenantID); err != nil {
+			return err
+		}
+		role, err := tx.Roles.FindByName(ctx, plan.TenantID, c.RoleName)
+		if err != nil {
+			return errors.Wrapf(err, "获取角色 %s 失败", c.RoleName)
+		}
+		created := assignmentDomain.NewAssignment(c.SubjectType, c.SubjectID, role.ID.Uint64(), plan.TenantID,
+			assignmentDomain.WithGrantedBy(changedBy))
+		if err := tx.Assignments.Create(ctx, &created); err != nil {
+			return errors.Wrap(err, "创建赋权失败")
+		}
+		rule := policyDomain.NewGroupingRule(created.SubjectKey(), plan.TenantID, role.Key())
+		if err := tx.RuleStore.AddGroupingPolicy(ctx, rule); err != nil {
+			return errors.Wrap(err, "添加 Casbin 分组规则失败")
+		}
+	}
+	return nil
+}
+
+// loadSnapshot 在事务内读取租户授权配置现状
+func loadSnapshot(ctx context.Context, tx authzuow.TxRepositories, tenantID string) (*domain.Snapshot, error) {
+	snapshot := &domain.Snapshot{TenantID: tenantID}
+
+	for offset := 0; ; offset += pageSize {
+		items, total, err := tx.Resources.List(ctx, resourceDomain.ListResourcesQuery{Offset: offset, Limit: pageSize})
+		if err != nil {
+			return nil, errors.Wrap(err, "获取资源列表失败")
+		}
+		snapshot.Resources = append(snapshot.Resources, items...)
+		if len(items) < pageSize || int64(len(snapshot.Resources)) >= total {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += pageSize {
+		items, total, err := tx.Roles.List(ctx, tenantID, offset, pageSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "获取角色列表失败")
+		}
+		snapshot.Roles = append(snapshot.Roles, items...)
+		if len(items) < pageSize || int64(len(snapshot.Roles)) >= total {
+			break
+		}
+	}
+
+	policies, err := tx.RuleReader.ListPolicies(ctx, tenantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "获取策略规则失败")
+	}
+	snapshot.Policies = policies
+
+	for _, r := range snapshot.Roles {
+		assignments, err := tx.Assignments.ListByRole(ctx, r.ID.Uint64(), tenantID)
+		if err != nil {
+			return nil, errors.Wrap(err, "获取角色赋权失败")
+		}
+		for _, a := range assignments {
+			// 已过期的赋权不再持有角色，仅在赋权历史中可查
+			if a.Status != assignmentDomain.StatusExpired {
+				snapshot.Assignments = append(snapshot.Assignments, a)
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+func validateApplyCommand(cmd domain.ApplyCommand) error {
+	if cmd.TenantID == "" {
+		return errors.WithCode(code.ErrInvalidArgument, "租户ID不能为空")
+	}
+	if cmd.Manifest == nil {
+		return errors.WithCode(code.ErrAuthzConfigInvalid, "文档不能为空")
+	}
+	return nil
+}
+
+func (s *Service) publishVersion(ctx context.Context, tenantID string, version *policyDomain.PolicyVersion) {
+	if s.versionNotifier == nil || version == nil {
+		return
+	}
+	if err := s.versionNotifier.Publish(ctx, tenantID, version.Version); err != nil {
+		log.Errorw("failed to publish authz config version", "tenant_id", tenantID, "version", version.Version, "error", err)
+	}
+}
@@ -0,0 +1,430 @@
+package manifest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	authzuow "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/uow"
+	assignmentDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/manifest"
+	policyDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
+	resourceDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/resource"
+	roleDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/role"
+	userDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/uc/user"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/testhelpers"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+const document = `
+apiVersion: iam.fangcunmount.cn/v1
+kind: AuthzConfig
+resources:
+  - key: scale:form:*
+    display_name: 量表
+    app_name: scale
+    domain: form
+    type: form
+    actions: [read_all, update_all]
+roles:
+  - name: scale-admin
+    display_name: 量表管理员
+    permissions:
+      - resource: scale:form:*
+        actions: [read_all, update_all]
+assignments:
+  - subject_type: user
+    subject_id: "123"
+    roles: [scale-admin]
+`
+
+func newFixture(t *testing.T) (*Service, *memStore, *versionRepoStub, *notifierStub) {
+	t.Helper()
+	store := newMemStore()
+	versions := &versionRepoStub{}
+	notifier := &notifierStub{}
+	users := testhelpers.NewUserRepoStub()
+	users.UsersByID[123] = &userDomain.User{ID: meta.FromUint64(123)}
+
+	service := NewService(&uowStub{tx: authzuow.TxRepositories{
+		Assignments:    store.assignments,
+		Roles:          store.roles,
+		Resources:      store.resources,
+		PolicyVersions: versions,
+		Users:          users,
+		RuleStore:      store.rules,
+		RuleReader:     store.rules,
+	}}, nil, notifier)
+	return service, store, versions, notifier
+}
+
+func parse(t *testing.T, data string) *domain.Manifest {
+	t.Helper()
+	m, err := domain.Parse([]byte(data))
+	require.NoError(t, err)
+	return m
+}
+
+func TestServiceApply_WritesAllChangesAndBumpsVersionOnce(t *testing.T) {
+	service, store, versions, notifier := newFixture(t)
+
+	result, err := service.Apply(context.Background(), domain.ApplyCommand{
+		TenantID:  "t1",
+		Manifest:  parse(t, document),
+		ChangedBy: "9",
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, result.Plan.Changes, 5)
+	assert.Equal(t, int64(1), result.Version)
+	assert.Equal(t, 1, versions.incrementCalls)
+	assert.Equal(t, defaultReason, versions.lastReason)
+	assert.Equal(t, 1, notifier.publishCalls)
+
+	require.Len(t, store.resources.items, 1)
+	role, err := store.roles.FindByName(context.Background(), "t1", "scale-admin")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []policyDomain.PolicyRule{
+		policyDomain.NewPolicyRule("role:scale-admin", "t1", "scale:form:*", "read_all"),
+		policyDomain.NewPolicyRule("role:scale-admin", "t1", "scale:form:*", "update_all"),
+	}, store.rules.policies)
+	assert.Equal(t, []policyDomain.GroupingRule{
+		policyDomain.NewGroupingRule("user:123", "t1", "role:scale-admin"),
+	}, store.rules.groupings)
+	require.Len(t, store.assignments.items, 1)
+	assert.Equal(t, role.ID.Uint64(), store.assignments.items[0].RoleID)
+	assert.Equal(t, "9", store.assignments.items[0].GrantedBy)
+
+	// 再次导入同一文档不产生变更，也不递增版本
+	again, err := service.Apply(context.Background(), domain.ApplyCommand{
+		TenantID:  "t1",
+		Manifest:  parse(t, document),
+		ChangedBy: "9",
+	})
+	require.NoError(t, err)
+	assert.True(t, again.Plan.Empty())
+	assert.Equal(t, int64(0), again.Version)
+	assert.Equal(t, 1, versions.incrementCalls)
+
+	// 导出结果与文档一致
+	exported, err := service.Export(context.Background(), domain.ExportQuery{TenantID: "t1", IncludeAssignments: true})
+	require.NoError(t, err)
+	plan, err := service.Plan(context.Background(), domain.ApplyCommand{TenantID: "t1", Manifest: exported, Prune: true})
+	require.NoError(t, err)
+	assert.True(t, plan.Empty(), "%+v", plan.Changes)
+}
+
+func TestServiceApply_RespectsAssignmentStatus(t *testing.T) {
+	service, store, _, _ := newFixture(t)
+	ctx := context.Background()
+	apply := func(doc string) {
+		t.Helper()
+		_, err := service.Apply(ctx, domain.ApplyCommand{TenantID: "t1", Manifest: parse(t, doc), ChangedBy: "9"})
+		require.NoError(t, err)
+	}
+	apply(document)
+	roleID := store.assignments.items[0].RoleID
+	add := func(status assignmentDomain.Status) *assignmentDomain.Assignment {
+		a := assignmentDomain.NewAssignment(assignmentDomain.SubjectTypeUser, "123", roleID, "t1", assignmentDomain.WithStatus(status))
+		require.NoError(t, store.assignments.Create(ctx, &a))
+		return &a
+	}
+
+	// 撤销时已过期的赋权保留在历史中
+	expired := add(assignmentDomain.StatusExpired)
+	apply(strings.Replace(document, "assignments:\n  - subject_type: user\n    subject_id: \"123\"\n    roles: [scale-admin]\n", "assignments: []\n", 1))
+	require.Len(t, store.assignments.items, 1)
+	assert.Equal(t, expired.ID, store.assignments.items[0].ID)
+	assert.Empty(t, store.rules.groupings)
+
+	// 待生效的赋权尚未持有角色，文档声明时立即授予
+	pending := add(assignmentDomain.StatusPending)
+	apply(document)
+	require.Len(t, store.assignments.items, 3)
+	assert.Equal(t, pending.ID, store.assignments.items[1].ID)
+	assert.Equal(t, assignmentDomain.StatusActive, store.assignments.items[2].Status)
+	assert.Len(t, store.rules.groupings, 1)
+}
+
+func TestServicePlan_HasNoSideEffects(t *testing.T) {
+	service, store, versions, _ := newFixture(t)
+
+	plan, err := service.Plan(context.Background(), domain.ApplyCommand{TenantID: "t1", Manifest: parse(t, document)})
+	require.NoError(t, err)
+	assert.Len(t, plan.Changes, 5)
+	assert.Empty(t, store.roles.items)
+	assert.Empty(t, store.rules.policies)
+	assert.Zero(t, versions.incrementCalls)
+}
+
+func TestServiceApply_FailureDoesNotBumpVersion(t *testing.T) {
+	service, _, versions, notifier := newFixture(t)
+	doc := parse(t, document)
+	doc.Assignments[0].SubjectID = "404"
+
+	_, err := service.Apply(context.Background(), domain.ApplyCommand{TenantID: "t1", Manifest: doc, ChangedBy: "9"})
+	require.Error(t, err)
+	assert.Zero(t, versions.incrementCalls)
+	assert.Zero(t, notifier.publishCalls)
+}
+
+func TestServiceApply_RequiresChangedBy(t *testing.T) {
+	service, _, _, _ := newFixture(t)
+	_, err := service.Apply(context.Background(), domain.ApplyCommand{TenantID: "t1", Manifest: parse(t, document)})
+	require.Error(t, err)
+}
+
+type uowStub struct {
+	tx authzuow.TxRepositories
+}
+
+func (u *uowStub) WithinTx(_ context.Context, fn func(tx authzuow.TxRepositories) error) error {
+	return fn(u.tx)
+}
+
+type memStore struct {
+	roles       *memRoles
+	resources   *memResources
+	assignments *memAssignments
+	rules       *memRules
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		roles:       &memRoles{},
+		resources:   &memResources{},
+		assignments: &memAssignments{},
+		rules:       &memRules{},
+	}
+}
+
+type memRoles struct {
+	items []*roleDomain.Role
+}
+
+func (r *memRoles) Create(_ context.Context, role *roleDomain.Role) error {
+	role.ID = meta.FromUint64(uint64(len(r.items) + 100))
+	copied := *role
+	r.items = append(r.items, &copied)
+	return nil
+}
+func (r *memRoles) Update(_ context.Context, role *roleDomain.Role) error {
+	for i, item := range r.items {
+		if item.ID == role.ID {
+			copied := *role
+			r.items[i] = &copied
+		}
+	}
+	return nil
+}
+func (r *memRoles) Delete(_ context.Context, id meta.ID) error {
+	for i, item := range r.items {
+		if item.ID == id {
+			r.items = append(r.items[:i], r.items[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+func (r *memRoles) FindByID(_ context.Context, id meta.ID) (*roleDomain.Role, error) {
+	for _, item := range r.items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (r *memRoles) FindByName(_ context.Context, tenantID, name string) (*roleDomain.Role, error) {
+	for _, item := range r.items {
+		if item.TenantID == tenantID && item.Name == name {
+			return item, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (r *memRoles) List(_ context.Context, tenantID string, offset, limit int) ([]*roleDomain.Role, int64, error) {
+	var out []*roleDomain.Role
+	for _, item := range r.items {
+		if item.TenantID == tenantID {
+			out = append(out, item)
+		}
+	}
+	total := int64(len(out))
+	if offset >= len(out) {
+		return nil, total, nil
+	}
+	out = out[offset:]
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, total, nil
+}
+
+type memResources struct {
+	items []*resourceDomain.Resource
+}
+
+func (r *memResources) Create(_ context.Context, res *resourceDomain.Resource) error {
+	res.ID = resourceDomain.NewResourceID(uint64(len(r.items) + 1))
+	copied := *res
+	r.items = append(r.items, &copied)
+	return nil
+}
+func (r *memResources) Update(_ context.Context, res *resourceDomain.Resource) error {
+	for i, item := range r.items {
+		if item.ID == res.ID {
+			copied := *res
+			r.items[i] = &copied
+		}
+	}
+	return nil
+}
+func (r *memResources) Delete(context.Context, resourceDomain.ResourceID) error { return nil }
+func (r *memResources) FindByID(context.Context, resourceDomain.ResourceID) (*resourceDomain.Resource, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+func (r *memResources) FindByKey(context.Context, string) (*resourceDomain.Resource, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+func (r *memResources) List(_ context.Context, query resourceDomain.ListResourcesQuery) ([]*resourceDomain.Resource, int64, error) {
+	if query.Offset >= len(r.items) {
+		return nil, int64(len(r.items)), nil
+	}
+	return r.items[query.Offset:], int64(len(r.items)), nil
+}
+func (r *memResources) ValidateAction(context.Context, string, string) (bool, error) {
+	return true, nil
+}
+
+type memAssignments struct {
+	nextID uint64
+	items  []*assignmentDomain.Assignment
+}
+
+func (r *memAssignments) Create(_ context.Context, a *assignmentDomain.Assignment) error {
+	r.nextID++
+	a.ID = assignmentDomain.NewAssignmentID(r.nextID)
+	copied := *a
+	r.items = append(r.items, &copied)
+	return nil
+}
+func (r *memAssignments) Delete(_ context.Context, id assignmentDomain.AssignmentID) error {
+	for i, a := range r.items {
+		if a.ID == id {
+			r.items = append(r.items[:i], r.items[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+func (r *memAssignments) DeleteBySubjectAndRole(context.Context, assignmentDomain.SubjectType, string, uint64, string) error {
+	return nil
+}
+func (r *memAssignments) FindByID(context.Context, assignmentDomain.AssignmentID) (*assignmentDomain.Assignment, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+func (r *memAssignments) ListBySubject(_ context.Context, subjectType assignmentDomain.SubjectType, subjectID, tenantID string) ([]*assignmentDomain.Assignment, error) {
+	var out []*assignmentDomain.Assignment
+	for _, a := range r.items {
+		if a.SubjectType == subjectType && a.SubjectID == subjectID && a.TenantID == tenantID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+func (r *memAssignments) ListBySubjectAcrossTenants(context.Context, assignmentDomain.SubjectType, string) ([]*assignmentDomain.Assignment, error) {
+	return nil, nil
+}
+func (r *memAssignments) ListByRole(_ context.Context, roleID uint64, tenantID string) ([]*assignmentDomain.Assignment, error) {
+	var out []*assignmentDomain.Assignment
+	for _, a := range r.items {
+		if a.RoleID == roleID && a.TenantID == tenantID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+type memRules struct {
+	policies  []policyDomain.PolicyRule
+	groupings []policyDomain.GroupingRule
+}
+
+func (r *memRules) AddPolicy(_ context.Context, rules ...policyDomain.PolicyRule) error {
+	r.policies = append(r.policies, rules...)
+	return nil
+}
+func (r *memRules) RemovePolicy(_ context.Context, rules ...policyDomain.PolicyRule) error {
+	for _, rule := range rules {
+		for i, p := range r.policies {
+			if p == rule {
+				r.policies = append(r.policies[:i], r.policies[i+1:]...)
+				break
+			}
+		}
+	}
+	return nil
+}
+func (r *memRules) AddGroupingPolicy(_ context.Context, rules ...policyDomain.GroupingRule) error {
+	r.groupings = append(r.groupings, rules...)
+	return nil
+}
+func (r *memRules) RemoveGroupingPolicy(_ context.Context, rules ...policyDomain.GroupingRule) error {
+	for _, rule := range rules {
+		for i, g := range r.groupings {
+			if g == rule {
+				r.groupings = append(r.groupings[:i], r.groupings[i+1:]...)
+				break
+			}
+		}
+	}
+	return nil
+}
+func (r *memRules) ListPolicies(_ context.Context, domain string) ([]policyDomain.PolicyRule, error) {
+	var out []policyDomain.PolicyRule
+	for _, p := range r.policies {
+		if p.Dom == domain {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+type versionRepoStub struct {
+	current        int64
+	incrementCalls int
+	lastReason     string
+}
+
+func (r *versionRepoStub) GetOrCreate(_ context.Context, tenantID string) (*policyDomain.PolicyVersion, error) {
+	v := policyDomain.NewPolicyVersion(tenantID, r.current)
+	return &v, nil
+}
+func (r *versionRepoStub) Increment(_ context.Context, tenantID, changedBy, reason string, _ ...policyDomain.PolicyVersionOption) (*policyDomain.PolicyVersion, error) {
+	r.incrementCalls++
+	r.current++
+	r.lastReason = reason
+	v := policyDomain.NewPolicyVersion(tenantID, r.current, policyDomain.WithChangedBy(changedBy), policyDomain.WithReason(reason))
+	return &v, nil
+}
+func (r *versionRepoStub) GetCurrent(_ context.Context, tenantID string) (*policyDomain.PolicyVersion, error) {
+	v := policyDomain.NewPolicyVersion(tenantID, r.current)
+	return &v, nil
+}
+
+type notifierStub struct {
+	publishCalls int
+}
+
+func (n *notifierStub) Publish(context.Context, string, int64) error {
+	n.publishCalls++
+	return nil
+}
+func (n *notifierStub) Subscribe(context.Context, policyDomain.VersionChangeHandler) error {
+	return nil
+}
+func (n *notifierStub) Close() error { return nil }
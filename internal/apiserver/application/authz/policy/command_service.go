@@ -38,7 +38,10 @@ func (s *PolicyCommandService) AddPolicyRule(
 		return err
 	}
 
-	var version *policyDomain.PolicyVersion
+	var (
+		version *policyDomain.PolicyVersion
+		queued  bool
+	)
 	err := s.uow.WithinTx(ctx, func(tx authzuow.TxRepositories) error {
 		txValidator := policyDomain.NewValidator(tx.Roles, tx.Resources)
 		roleKey, err := txValidator.CheckRoleExistsAndTenant(ctx, cmd.RoleID, cmd.TenantID)
@@ -53,14 +56,16 @@ func (s *PolicyCommandService) AddPolicyRule(
 		if err := tx.RuleStore.AddPolicy(ctx, rule); err != nil {
 			return err
 		}
-		version, err = tx.PolicyVersions.Increment(ctx, cmd.TenantID, cmd.ChangedBy, cmd.Reason)
+		version, queued, err = authzshared.BumpVersion(ctx, tx, cmd.TenantID, cmd.ChangedBy, cmd.Reason)
 		return err
 	})
 	if err != nil {
 		return err
 	}
 
-	s.publishVersion(ctx, cmd.TenantID, version)
+	if !queued {
+		s.publishVersion(ctx, cmd.TenantID, version)
+	}
 	authzshared.ReloadRuntimePolicy(ctx, s.casbinAdapter, "policy add")
 	return nil
 }
@@ -73,7 +78,10 @@ func (s *PolicyCommandService) RemovePolicyRule(
 		return err
 	}
 
-	var version *policyDomain.PolicyVersion
+	var (
+		version *policyDomain.PolicyVersion
+		queued  bool
+	)
 	err := s.uow.WithinTx(ctx, func(tx authzuow.TxRepositories) error {
 		txValidator := policyDomain.NewValidator(tx.Roles, tx.Resources)
 		roleKey, err := txValidator.CheckRoleExistsAndTenant(ctx, cmd.RoleID, cmd.TenantID)
@@ -88,14 +96,16 @@ func (s *PolicyCommandService) RemovePolicyRule(
 		if err := tx.RuleStore.RemovePolicy(ctx, rule); err != nil {
 			return err
 		}
-		version, err = tx.PolicyVersions.Increment(ctx, cmd.TenantID, cmd.ChangedBy, cmd.Reason)
+		version, queued, err = authzshared.BumpVersion(ctx, tx, cmd.TenantID, cmd.ChangedBy, cmd.Reason)
 		return err
 	})
 	if err != nil {
 		return err
 	}
 
-	s.publishVersion(ctx, cmd.TenantID, version)
+	if !queued {
+		s.publishVersion(ctx, cmd.TenantID, version)
+	}
 	authzshared.ReloadRuntimePolicy(ctx, s.casbinAdapter, "policy remove")
 	return nil
 }
@@ -119,7 +119,7 @@ func (r *policyVersionRepoForCommandStub) GetOrCreate(_ context.Context, tenantI
 	version := policyDomain.NewPolicyVersion(tenantID, r.currentVersion)
 	return &version, nil
 }
-func (r *policyVersionRepoForCommandStub) Increment(_ context.Context, tenantID, changedBy, reason string) (*policyDomain.PolicyVersion, error) {
+func (r *policyVersionRepoForCommandStub) Increment(_ context.Context, tenantID, changedBy, reason string, _ ...policyDomain.PolicyVersionOption) (*policyDomain.PolicyVersion, error) {
 	r.incrementCalls++
 	r.currentVersion++
 	version := policyDomain.NewPolicyVersion(
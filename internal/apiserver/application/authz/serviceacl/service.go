@@ -0,0 +1,213 @@
+// Package serviceacl gRPC 服务 ACL 应用服务
+//
+// 条目持久化在数据库中；写入后立即应用到本实例的 ACL 拦截器，
+// 其他副本由 Syncer 定期从数据库拉取全量条目并原子替换，实现跨副本热更新。
+package serviceacl
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/FangcunMount/component-base/pkg/log"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/serviceacl"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/grpc"
+)
+
+// Runtime 运行中的 ACL 拦截器（由 *grpc.DynamicACL 实现）
+type Runtime interface {
+	ReplaceServices(services []grpc.ACLRule)
+	DefaultPolicy() string
+}
+
+// Service 服务 ACL 应用服务，同时实现 Commander 与 Queryer
+type Service struct {
+	repo domain.Repository
+
+	mu sync.Mutex
+	// runtime 未启用 gRPC ACL 时为 nil，此时仅维护数据库条目
+	runtime Runtime
+	// applied 最近一次应用到运行时的条目指纹，用于跳过无变化的同步
+	applied string
+}
+
+var (
+	_ domain.Commander = (*Service)(nil)
+	_ domain.Queryer   = (*Service)(nil)
+)
+
+// NewService 创建服务 ACL 应用服务
+func NewService(repo domain.Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// BindRuntime 绑定运行中的 ACL 拦截器；gRPC 服务启动前调用
+func (s *Service) BindRuntime(runtime Runtime) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runtime = runtime
+	s.applied = ""
+}
+
+// CreateEntry 登记调用方服务
+func (s *Service) CreateEntry(ctx context.Context, cmd domain.CreateEntryCommand) (*domain.Entry, error) {
+	entry := domain.NewEntry(cmd.ServiceName, cmd.AllowedMethods, cmd.DeniedMethods,
+		domain.WithEnabled(cmd.Enabled),
+		domain.WithDescription(cmd.Description),
+	)
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+	s.syncAfterWrite(ctx)
+	return entry, nil
+}
+
+// UpdateEntry 更新调用方服务的方法授权
+func (s *Service) UpdateEntry(ctx context.Context, cmd domain.UpdateEntryCommand) (*domain.Entry, error) {
+	entry, err := s.repo.FindByService(ctx, cmd.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.ReplaceMethods(cmd.AllowedMethods, cmd.DeniedMethods)
+	if cmd.Enabled != nil {
+		entry.Enabled = *cmd.Enabled
+	}
+	if cmd.Description != nil {
+		entry.Description = *cmd.Description
+	}
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, entry); err != nil {
+		return nil, err
+	}
+	s.syncAfterWrite(ctx)
+	return entry, nil
+}
+
+// DeleteEntry 删除调用方服务
+func (s *Service) DeleteEntry(ctx context.Context, serviceName string) error {
+	if err := s.repo.Delete(ctx, serviceName); err != nil {
+		return err
+	}
+	s.syncAfterWrite(ctx)
+	return nil
+}
+
+// GetEntry 查询单个服务
+func (s *Service) GetEntry(ctx context.Context, serviceName string) (*domain.Entry, error) {
+	return s.repo.FindByService(ctx, serviceName)
+}
+
+// ListEntries 列出全部服务
+func (s *Service) ListEntries(ctx context.Context) ([]*domain.Entry, error) {
+	return s.repo.List(ctx)
+}
+
+// DryRun 以与拦截器相同的匹配规则判定已保存的条目
+func (s *Service) DryRun(ctx context.Context, serviceName, method string) (*domain.DryRunResult, error) {
+	entries, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	runtime := s.boundRuntime()
+	policy := &grpc.ACLPolicy{DefaultPolicy: grpc.ACLPolicyDeny, Services: toRules(entries)}
+	if runtime != nil {
+		policy.DefaultPolicy = runtime.DefaultPolicy()
+	}
+	decision := policy.Evaluate(serviceName, method)
+
+	return &domain.DryRunResult{
+		ServiceName:    serviceName,
+		Method:         method,
+		Allowed:        decision.Allowed,
+		Reason:         decision.Reason,
+		MatchedPattern: decision.MatchedPattern,
+		DefaultPolicy:  policy.DefaultPolicy,
+		Enforced:       runtime != nil,
+	}, nil
+}
+
+// SeedOnce 以启动配置（grpc_acl.yaml）中的规则初始化数据库，仅在首次启动时导入一次；
+// 之后即使条目被全部删除也不再导入。返回写入的条目数；不合法的规则跳过并记录告警
+func (s *Service) SeedOnce(ctx context.Context, rules []grpc.ACLRule) (int, error) {
+	entries := make([]*domain.Entry, 0, len(rules))
+	for _, rule := range rules {
+		entry := domain.NewEntry(rule.ServiceName, rule.AllowedMethods, rule.DeniedMethods,
+			domain.WithEnabled(rule.Enabled),
+			domain.WithDescription(rule.Description),
+		)
+		if err := entry.Validate(); err != nil {
+			log.Warnw("skip invalid grpc acl seed entry", "service", rule.ServiceName, "error", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	seeded, err := s.repo.Seed(ctx, entries)
+	if err != nil || !seeded {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// Sync 从数据库加载全量条目并应用到运行时；条目无变化时不替换
+// 返回本次是否替换了运行时规则
+func (s *Service) Sync(ctx context.Context) (bool, error) {
+	if s.boundRuntime() == nil {
+		return false, nil
+	}
+
+	entries, err := s.repo.List(ctx)
+	if err != nil {
+		return false, err
+	}
+	rules := toRules(entries)
+	fingerprint, err := json.Marshal(rules)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.runtime == nil || s.applied == string(fingerprint) {
+		return false, nil
+	}
+	s.runtime.ReplaceServices(rules)
+	s.applied = string(fingerprint)
+	log.Infow("grpc service acl reloaded", "services", len(rules))
+	return true, nil
+}
+
+// syncAfterWrite 写入后立即应用到本实例；失败时由 Syncer 下一轮补齐
+func (s *Service) syncAfterWrite(ctx context.Context) {
+	if _, err := s.Sync(ctx); err != nil {
+		log.Warnw("failed to apply grpc service acl after write", "error", err)
+	}
+}
+
+func (s *Service) boundRuntime() Runtime {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.runtime
+}
+
+// toRules 转换为拦截器规则
+func toRules(entries []*domain.Entry) []grpc.ACLRule {
+	rules := make([]grpc.ACLRule, 0, len(entries))
+	for _, e := range entries {
+		rules = append(rules, grpc.ACLRule{
+			ServiceName:    e.ServiceName,
+			Enabled:        e.Enabled,
+			Description:    e.Description,
+			AllowedMethods: e.AllowedMethods,
+			DeniedMethods:  e.DeniedMethods,
+		})
+	}
+	return rules
+}
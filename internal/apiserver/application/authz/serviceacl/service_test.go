@@ -0,0 +1,221 @@
+package serviceacl
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/serviceacl"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/grpc"
+	"github.com/stretchr/testify/require"
+)
+
+// repoStub 多个副本共享的内存仓储
+type repoStub struct {
+	mu      sync.Mutex
+	entries map[string]*domain.Entry
+	seeded  bool
+}
+
+func newRepoStub() *repoStub { return &repoStub{entries: map[string]*domain.Entry{}} }
+
+func (r *repoStub) Create(_ context.Context, e *domain.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[e.ServiceName]; ok {
+		return perrors.WithCode(code.ErrServiceACLAlreadyExists, "exists")
+	}
+	cp := *e
+	r.entries[e.ServiceName] = &cp
+	return nil
+}
+
+func (r *repoStub) Update(_ context.Context, e *domain.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *e
+	r.entries[e.ServiceName] = &cp
+	return nil
+}
+
+func (r *repoStub) Delete(_ context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[name]; !ok {
+		return perrors.WithCode(code.ErrServiceACLNotFound, "not found")
+	}
+	delete(r.entries, name)
+	return nil
+}
+
+func (r *repoStub) FindByService(_ context.Context, name string) (*domain.Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[name]
+	if !ok {
+		return nil, perrors.WithCode(code.ErrServiceACLNotFound, "not found")
+	}
+	cp := *e
+	return &cp, nil
+}
+
+func (r *repoStub) List(context.Context) ([]*domain.Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]*domain.Entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		cp := *e
+		out = append(out, &cp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ServiceName < out[j].ServiceName })
+	return out, nil
+}
+
+func (r *repoStub) Count(context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return int64(len(r.entries)), nil
+}
+
+func (r *repoStub) Seed(_ context.Context, entries []*domain.Entry) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.seeded {
+		return false, nil
+	}
+	r.seeded = true
+	for _, e := range entries {
+		cp := *e
+		r.entries[e.ServiceName] = &cp
+	}
+	return true, nil
+}
+
+const getUser = "/iam.identity.v1.IdentityRead/GetUser"
+
+func TestService_SeedWriteAndCrossReplicaSync(t *testing.T) {
+	ctx := context.Background()
+	repo := newRepoStub()
+
+	bootstrap := &grpc.ACLPolicy{
+		DefaultPolicy: grpc.ACLPolicyDeny,
+		Services: []grpc.ACLRule{
+			{ServiceName: "qs-apiserver.svc", Enabled: true, AllowedMethods: []string{"/iam.authn.v1.AuthService/*"}},
+			{ServiceName: "bad name", Enabled: true},
+		},
+	}
+	aclA, aclB := grpc.NewDynamicACL(bootstrap), grpc.NewDynamicACL(bootstrap)
+	replicaA, replicaB := NewService(repo), NewService(repo)
+	replicaA.BindRuntime(aclA)
+	replicaB.BindRuntime(aclB)
+
+	// 仅首次启动时导入 YAML，非法条目跳过
+	seeded, err := replicaA.SeedOnce(ctx, bootstrap.Services)
+	require.NoError(t, err)
+	require.Equal(t, 1, seeded)
+	seeded, err = replicaB.SeedOnce(ctx, bootstrap.Services)
+	require.NoError(t, err)
+	require.Zero(t, seeded)
+
+	require.False(t, aclA.Evaluate("qs-apiserver.svc", getUser).Allowed)
+
+	// 写入立即在本副本生效
+	_, err = replicaA.UpdateEntry(ctx, domain.UpdateEntryCommand{
+		ServiceName:    "qs-apiserver.svc",
+		AllowedMethods: []string{"/iam.authn.v1.AuthService/*", getUser},
+	})
+	require.NoError(t, err)
+	require.True(t, aclA.Evaluate("qs-apiserver.svc", getUser).Allowed)
+	require.False(t, aclB.Evaluate("qs-apiserver.svc", getUser).Allowed)
+
+	// 其他副本在下一次同步后生效，无变化时不重复替换
+	changed, err := replicaB.Sync(ctx)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.True(t, aclB.Evaluate("qs-apiserver.svc", getUser).Allowed)
+	changed, err = replicaB.Sync(ctx)
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	// 删除后回落到默认策略
+	require.NoError(t, replicaA.DeleteEntry(ctx, "qs-apiserver.svc"))
+	decision := aclA.Evaluate("qs-apiserver.svc", getUser)
+	require.False(t, decision.Allowed)
+	require.Equal(t, grpc.ACLReasonDefaultPolicy, decision.Reason)
+
+	// 条目被全部删除后重启也不重新导入
+	seeded, err = NewService(repo).SeedOnce(ctx, bootstrap.Services)
+	require.NoError(t, err)
+	require.Zero(t, seeded)
+	require.Empty(t, repo.entries)
+}
+
+func TestService_CreateValidatesAndDryRun(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newRepoStub())
+
+	_, err := svc.CreateEntry(ctx, domain.CreateEntryCommand{ServiceName: "qs", AllowedMethods: []string{"GetUser"}})
+	require.True(t, perrors.IsCode(err, code.ErrServiceACLInvalid))
+
+	_, err = svc.CreateEntry(ctx, domain.CreateEntryCommand{
+		ServiceName:    "qs",
+		Enabled:        true,
+		AllowedMethods: []string{"/iam.identity.v1.IdentityRead/*"},
+		DeniedMethods:  []string{"/iam.identity.v1.IdentityRead/SearchUsers"},
+	})
+	require.NoError(t, err)
+
+	// 未绑定运行时：仍可试运行，按 deny 默认策略且标记未执行
+	result, err := svc.DryRun(ctx, "qs", getUser)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.False(t, result.Enforced)
+	require.Equal(t, "/iam.identity.v1.IdentityRead/*", result.MatchedPattern)
+
+	result, err = svc.DryRun(ctx, "qs", "/iam.identity.v1.IdentityRead/SearchUsers")
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, grpc.ACLReasonDeniedMethod, result.Reason)
+
+	// 已绑定运行时：试运行结论与拦截器一致
+	acl := grpc.NewDynamicACL(&grpc.ACLPolicy{DefaultPolicy: grpc.ACLPolicyAllow})
+	svc.BindRuntime(acl)
+	_, err = svc.Sync(ctx)
+	require.NoError(t, err)
+	for _, tc := range []struct{ service, method string }{
+		{"qs", getUser},
+		{"qs", "/iam.identity.v1.IdentityRead/SearchUsers"},
+		{"qs", "/iam.authz.v1.AuthzService/Check"},
+		{"unknown", getUser},
+	} {
+		result, err := svc.DryRun(ctx, tc.service, tc.method)
+		require.NoError(t, err)
+		require.True(t, result.Enforced)
+		require.Equal(t, acl.Evaluate(tc.service, tc.method).Allowed, result.Allowed, "%s %s", tc.service, tc.method)
+	}
+}
+
+func TestSyncer_PicksUpRemoteChanges(t *testing.T) {
+	ctx := context.Background()
+	repo := newRepoStub()
+	acl := grpc.NewDynamicACL(nil)
+	svc := NewService(repo)
+	svc.BindRuntime(acl)
+
+	syncer := NewSyncer(svc, 10*time.Millisecond)
+	require.NoError(t, syncer.Start(ctx))
+	require.True(t, syncer.IsRunning())
+
+	// 模拟其他副本直接写库
+	require.NoError(t, repo.Create(ctx, domain.NewEntry("qs", []string{getUser}, nil)))
+	require.Eventually(t, func() bool {
+		return acl.Evaluate("qs", getUser).Allowed
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, syncer.Stop())
+	require.False(t, syncer.IsRunning())
+}
@@ -0,0 +1,96 @@
+package serviceacl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/log"
+)
+
+// DefaultReloadInterval 默认的跨副本同步间隔
+const DefaultReloadInterval = 10 * time.Second
+
+// Syncer 定期从数据库同步服务 ACL 到本实例拦截器
+type Syncer struct {
+	service  *Service
+	interval time.Duration
+
+	mu      sync.RWMutex
+	running bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewSyncer 创建同步器；interval 非正数时使用 DefaultReloadInterval
+func NewSyncer(service *Service, interval time.Duration) *Syncer {
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+	return &Syncer{service: service, interval: interval}
+}
+
+// Start 启动同步循环
+func (s *Syncer) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return nil
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.running = true
+
+	s.wg.Add(1)
+	go s.run(ctx)
+
+	log.Infow("grpc service acl syncer started", "interval", s.interval)
+	return nil
+}
+
+// Stop 停止同步循环
+func (s *Syncer) Stop() error {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return nil
+	}
+	s.cancel()
+	s.mu.Unlock()
+
+	s.wg.Wait()
+
+	s.mu.Lock()
+	s.running = false
+	s.mu.Unlock()
+
+	log.Info("grpc service acl syncer stopped")
+	return nil
+}
+
+// IsRunning 返回同步器是否正在运行
+func (s *Syncer) IsRunning() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.running
+}
+
+func (s *Syncer) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// 同步失败时保留当前规则，等待下一轮
+			if _, err := s.service.Sync(ctx); err != nil && ctx.Err() == nil {
+				log.Warnw("failed to sync grpc service acl", "error", err)
+			}
+		}
+	}
+}
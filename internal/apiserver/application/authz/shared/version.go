@@ -0,0 +1,29 @@
+package shared
+
+import (
+	"context"
+
+	authzuow "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/uow"
+	policyDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
+)
+
+// BumpVersion 在事务内递增租户授权版本，并把本事务内该租户的规则变更集记录到新版本；
+// 启用发件箱时同时登记版本变更事件。
+// queued 为 true 表示事件已随事务落库，由中继投递，调用方提交后无需再直接发布。
+func BumpVersion(ctx context.Context, tx authzuow.TxRepositories, tenantID, changedBy, reason string) (version *policyDomain.PolicyVersion, queued bool, err error) {
+	var opts []policyDomain.PolicyVersionOption
+	if tx.RuleChanges != nil {
+		opts = append(opts, policyDomain.WithChanges(tx.RuleChanges.Drain(tenantID)))
+	}
+	version, err = tx.PolicyVersions.Increment(ctx, tenantID, changedBy, reason, opts...)
+	if err != nil {
+		return nil, false, err
+	}
+	if tx.VersionOutbox == nil {
+		return version, false, nil
+	}
+	if err := tx.VersionOutbox.Enqueue(ctx, version); err != nil {
+		return nil, false, err
+	}
+	return version, true, nil
+}
@@ -10,8 +10,10 @@ import (
 	resourceDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/resource"
 	roleDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/role"
 	userDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/uc/user"
+	messaginginfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/messaging"
 	assignmentrepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/assignment"
 	casbinrulerepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/casbinrule"
+	outboxrepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/outbox"
 	policyrepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/policy"
 	resourcerepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/resource"
 	rolerepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/role"
@@ -25,8 +27,17 @@ type TxRepositories struct {
 	Roles          roleDomain.Repository
 	Resources      resourceDomain.Repository
 	PolicyVersions policyDomain.Repository
+	VersionHistory policyDomain.VersionHistory
 	Users          userDomain.Repository
 	RuleStore      policyDomain.RuleStore
+	RuleReader     policyDomain.RuleReader
+	// RuleChanges 收集 RuleStore 在本事务内实际生效的规则变更，由 BumpVersion 随版本落库；
+	// 为 nil 时版本不记录变更集
+	RuleChanges *policyDomain.ChangeRecorder
+	// VersionOutbox 未启用发件箱时为 nil，调用方应在提交后直接发布版本通知
+	VersionOutbox policyDomain.VersionOutbox
+	// AssignmentSchedule 按有效期切换赋权状态，供赋权有效期调度器使用
+	AssignmentSchedule assignmentDomain.ScheduleRepository
 }
 
 type UnitOfWork interface {
@@ -35,12 +46,25 @@ type UnitOfWork interface {
 
 var _ txpkg.UnitOfWork[TxRepositories] = (*gormUnitOfWork)(nil)
 
-func NewUnitOfWork(db *gorm.DB) UnitOfWork {
-	return &gormUnitOfWork{base: dbmysql.NewUnitOfWork(db)}
+// Option 工作单元选项
+type Option func(*gormUnitOfWork)
+
+// WithVersionOutbox 在事务内提供版本变更发件箱
+func WithVersionOutbox() Option {
+	return func(u *gormUnitOfWork) { u.versionOutbox = true }
+}
+
+func NewUnitOfWork(db *gorm.DB, opts ...Option) UnitOfWork {
+	u := &gormUnitOfWork{base: dbmysql.NewUnitOfWork(db)}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
 }
 
 type gormUnitOfWork struct {
-	base *dbmysql.UnitOfWork
+	base          *dbmysql.UnitOfWork
+	versionOutbox bool
 }
 
 func (u *gormUnitOfWork) WithinTx(ctx context.Context, fn func(tx TxRepositories) error) error {
@@ -48,14 +72,37 @@ func (u *gormUnitOfWork) WithinTx(ctx context.Context, fn func(tx TxRepositories
 		return fn(TxRepositories{})
 	}
 	return u.base.WithinTransaction(ctx, func(tx *gorm.DB) error {
-		repos := TxRepositories{
-			Assignments:    assignmentrepo.NewAssignmentRepository(tx),
-			Roles:          rolerepo.NewRoleRepository(tx),
-			Resources:      resourcerepo.NewResourceRepository(tx),
-			PolicyVersions: policyrepo.NewPolicyVersionRepository(tx),
-			Users:          userrepo.NewRepository(tx),
-			RuleStore:      casbinrulerepo.NewRepository(tx),
-		}
-		return fn(repos)
+		return fn(u.repositories(tx))
 	})
 }
+
+// NewTxRepositories 基于已开启的事务构建授权仓储集合，
+// 供需要与其它上下文共享同一事务的调用方（如用户合并）复用。
+func NewTxRepositories(tx *gorm.DB, opts ...Option) TxRepositories {
+	u := &gormUnitOfWork{}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u.repositories(tx)
+}
+
+func (u *gormUnitOfWork) repositories(tx *gorm.DB) TxRepositories {
+	recorder := policyDomain.NewChangeRecorder()
+	repos := TxRepositories{
+		Assignments:    assignmentrepo.NewAssignmentRepository(tx),
+		Roles:          rolerepo.NewRoleRepository(tx),
+		Resources:      resourcerepo.NewResourceRepository(tx),
+		PolicyVersions: policyrepo.NewPolicyVersionRepository(tx),
+		VersionHistory: policyrepo.NewVersionHistory(tx),
+		Users:          userrepo.NewRepository(tx),
+		RuleStore:      casbinrulerepo.NewRecordingRepository(tx, recorder),
+		RuleReader:     casbinrulerepo.NewRuleReader(tx),
+		RuleChanges:    recorder,
+
+		AssignmentSchedule: assignmentrepo.NewScheduleRepository(tx),
+	}
+	if u.versionOutbox {
+		repos.VersionOutbox = messaginginfra.NewVersionOutbox(outboxrepo.NewRepository(tx))
+	}
+	return repos
+}
@@ -0,0 +1,206 @@
+package cachegovernance
+
+import (
+	"context"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/log"
+
+	cacheinfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/cache"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+const (
+	defaultSampleLimit = 20
+	maxSampleLimit     = 200
+)
+
+// Action 表示缓存治理写动作类型。
+type Action string
+
+const (
+	ActionPurge  Action = "purge"
+	ActionWarm   Action = "warm"
+	ActionSample Action = "sample"
+)
+
+// AuditEntry 记录一次缓存治理动作。
+type AuditEntry struct {
+	Action   Action
+	Family   cacheinfra.Family
+	Key      string
+	Actor    string
+	Affected int64
+	Err      error
+	At       time.Time
+	Duration time.Duration
+}
+
+// Auditor 负责持久化或输出缓存治理审计记录。
+type Auditor interface {
+	Record(ctx context.Context, entry AuditEntry)
+}
+
+// logAuditor 默认审计实现：写入结构化日志。
+type logAuditor struct{}
+
+func (logAuditor) Record(ctx context.Context, entry AuditEntry) {
+	fields := []interface{}{
+		"action", entry.Action,
+		"family", entry.Family,
+		"key", entry.Key,
+		"actor", entry.Actor,
+		"affected", entry.Affected,
+		"duration", entry.Duration,
+	}
+	if entry.Err != nil {
+		log.Warnw("cache governance action failed", append(fields, "error", entry.Err)...)
+		return
+	}
+	log.Infow("cache governance action", fields...)
+}
+
+// CommandOption 用于扩展缓存治理命令服务。
+type CommandOption func(*CommandService)
+
+// WithWarmers 配置缓存族预热器。
+func WithWarmers(warmers ...cacheinfra.FamilyWarmer) CommandOption {
+	return func(s *CommandService) {
+		for _, warmer := range warmers {
+			if warmer == nil {
+				continue
+			}
+			s.warmers[warmer.Family()] = warmer
+		}
+	}
+}
+
+// WithAuditor 替换默认的日志审计实现。
+func WithAuditor(auditor Auditor) CommandOption {
+	return func(s *CommandService) {
+		if auditor != nil {
+			s.auditor = auditor
+		}
+	}
+}
+
+// CommandService 负责执行需要管理员授权的缓存治理动作：清理、预热与 key 抽样。
+type CommandService struct {
+	operators map[cacheinfra.Family]cacheinfra.FamilyOperator
+	warmers   map[cacheinfra.Family]cacheinfra.FamilyWarmer
+	auditor   Auditor
+	now       func() time.Time
+}
+
+// NewCommandService 创建缓存治理命令服务。
+func NewCommandService(operators []cacheinfra.FamilyOperator, opts ...CommandOption) *CommandService {
+	service := &CommandService{
+		operators: make(map[cacheinfra.Family]cacheinfra.FamilyOperator, len(operators)),
+		warmers:   map[cacheinfra.Family]cacheinfra.FamilyWarmer{},
+		auditor:   logAuditor{},
+		now:       time.Now,
+	}
+	for _, operator := range operators {
+		if operator == nil {
+			continue
+		}
+		service.operators[operator.Family()] = operator
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	return service
+}
+
+// Purge 清理单个 key，key 为空时清理整个缓存族，返回删除数量。
+func (s *CommandService) Purge(ctx context.Context, actor string, family cacheinfra.Family, key string) (deleted int64, err error) {
+	start := s.now()
+	defer func() { s.audit(ctx, ActionPurge, family, key, actor, deleted, err, start) }()
+
+	if err = requireCapability(family, cacheinfra.GovernanceCapabilityPurge); err != nil {
+		return 0, err
+	}
+	operator := s.operators[family]
+	if operator == nil {
+		return 0, errors.WithCode(code.ErrModuleNotFound, "cache family %s has no operator configured", family)
+	}
+	deleted, err = operator.Purge(ctx, key)
+	if err != nil {
+		if errors.IsCode(err, code.ErrInvalidArgument) {
+			return deleted, err
+		}
+		return deleted, errors.WrapC(err, code.ErrInternalServerError, "purge cache family %s failed", family)
+	}
+	return deleted, nil
+}
+
+// Warm 触发缓存族预热，target 的含义由缓存族决定（如微信 appID）。
+func (s *CommandService) Warm(ctx context.Context, actor string, family cacheinfra.Family, target string) (err error) {
+	start := s.now()
+	defer func() { s.audit(ctx, ActionWarm, family, target, actor, 0, err, start) }()
+
+	if err = requireCapability(family, cacheinfra.GovernanceCapabilityWarm); err != nil {
+		return err
+	}
+	warmer := s.warmers[family]
+	if warmer == nil {
+		return errors.WithCode(code.ErrModuleNotFound, "cache family %s has no warmer configured", family)
+	}
+	if err = warmer.Warm(ctx, target); err != nil {
+		if errors.IsCode(err, code.ErrInvalidArgument) {
+			return err
+		}
+		return errors.WrapC(err, code.ErrInternalServerError, "warm cache family %s failed", family)
+	}
+	return nil
+}
+
+// SampleKeys 抽样缓存族下的 key 及其剩余 TTL。
+func (s *CommandService) SampleKeys(ctx context.Context, actor string, family cacheinfra.Family, limit int) (samples []cacheinfra.KeySample, err error) {
+	start := s.now()
+	defer func() { s.audit(ctx, ActionSample, family, "", actor, int64(len(samples)), err, start) }()
+
+	if err = requireCapability(family, cacheinfra.GovernanceCapabilitySample); err != nil {
+		return nil, err
+	}
+	operator := s.operators[family]
+	if operator == nil {
+		return nil, errors.WithCode(code.ErrModuleNotFound, "cache family %s has no operator configured", family)
+	}
+	if limit <= 0 {
+		limit = defaultSampleLimit
+	}
+	if limit > maxSampleLimit {
+		limit = maxSampleLimit
+	}
+	samples, err = operator.Sample(ctx, limit)
+	if err != nil {
+		return nil, errors.WrapC(err, code.ErrInternalServerError, "sample cache family %s failed", family)
+	}
+	return samples, nil
+}
+
+func (s *CommandService) audit(ctx context.Context, action Action, family cacheinfra.Family, key, actor string, affected int64, err error, start time.Time) {
+	s.auditor.Record(ctx, AuditEntry{
+		Action:   action,
+		Family:   family,
+		Key:      key,
+		Actor:    actor,
+		Affected: affected,
+		Err:      err,
+		At:       start,
+		Duration: s.now().Sub(start),
+	})
+}
+
+func requireCapability(family cacheinfra.Family, capability cacheinfra.GovernanceCapability) error {
+	descriptor, ok := cacheinfra.GetFamily(family)
+	if !ok {
+		return errors.WithCode(code.ErrInvalidArgument, "unknown cache family %q", family)
+	}
+	if !descriptor.Supports(capability) {
+		return errors.WithCode(code.ErrInvalidArgument, "cache family %s does not support %s", family, capability)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package cachegovernance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+
+	cacheinfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/cache"
+	redisinfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/redis"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+type recordingAuditor struct {
+	entries []AuditEntry
+}
+
+func (a *recordingAuditor) Record(_ context.Context, entry AuditEntry) {
+	a.entries = append(a.entries, entry)
+}
+
+type refresherStub struct {
+	appIDs []string
+}
+
+func (r *refresherStub) RefreshAccessToken(_ context.Context, appID string) (string, error) {
+	r.appIDs = append(r.appIDs, appID)
+	return "token", nil
+}
+
+func TestCommandServicePurgeSampleAndWarm(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	mr.Set("session:s1", "{}")
+	mr.Set("session:s2", "{}")
+
+	auditor := &recordingAuditor{}
+	refresher := &refresherStub{}
+	service := NewCommandService(
+		redisinfra.SessionStoreOperators(redisinfra.NewSessionStore(client)),
+		WithWarmers(NewWechatAccessTokenWarmer(refresher)),
+		WithAuditor(auditor),
+	)
+	ctx := context.Background()
+
+	samples, err := service.SampleKeys(ctx, "admin", cacheinfra.FamilyAuthnSession, 0)
+	if err != nil || len(samples) != 2 {
+		t.Fatalf("SampleKeys() = %v, %v; want 2 samples", samples, err)
+	}
+
+	deleted, err := service.Purge(ctx, "admin", cacheinfra.FamilyAuthnSession, "session:s1")
+	if err != nil || deleted != 1 {
+		t.Fatalf("Purge(key) = %d, %v; want 1", deleted, err)
+	}
+	if _, err := service.Purge(ctx, "admin", cacheinfra.FamilyAuthnSession, "otp:login:x"); !errors.IsCode(err, code.ErrInvalidArgument) {
+		t.Fatalf("Purge(foreign key) error = %v, want invalid argument", err)
+	}
+	deleted, err = service.Purge(ctx, "admin", cacheinfra.FamilyAuthnSession, "")
+	if err != nil || deleted != 1 {
+		t.Fatalf("Purge(family) = %d, %v; want 1", deleted, err)
+	}
+
+	if err := service.Warm(ctx, "admin", cacheinfra.FamilyIDPWechatAccessToken, "wx-app"); err != nil {
+		t.Fatalf("Warm() error = %v", err)
+	}
+	if len(refresher.appIDs) != 1 || refresher.appIDs[0] != "wx-app" {
+		t.Fatalf("refresher calls = %v", refresher.appIDs)
+	}
+	if err := service.Warm(ctx, "admin", cacheinfra.FamilyIDPWechatAccessToken, ""); !errors.IsCode(err, code.ErrInvalidArgument) {
+		t.Fatalf("Warm() without app id error = %v, want invalid argument", err)
+	}
+
+	if len(auditor.entries) != 6 {
+		t.Fatalf("audit entries = %d, want 6", len(auditor.entries))
+	}
+	if got := auditor.entries[1]; got.Action != ActionPurge || got.Key != "session:s1" || got.Actor != "admin" || got.Affected != 1 {
+		t.Fatalf("unexpected audit entry %+v", got)
+	}
+	if auditor.entries[2].Err == nil || auditor.entries[5].Err == nil {
+		t.Fatal("failed action should be audited with its error")
+	}
+}
+
+func TestCommandServiceRejectsUnsupportedCapability(t *testing.T) {
+	auditor := &recordingAuditor{}
+	service := NewCommandService(nil, WithAuditor(auditor))
+	ctx := context.Background()
+
+	// 撤销令牌 marker 只开放抽样，删除会让令牌重新生效
+	if _, err := service.Purge(ctx, "admin", cacheinfra.FamilyAuthnRevokedAccessToken, ""); !errors.IsCode(err, code.ErrInvalidArgument) {
+		t.Fatalf("Purge(revoked) error = %v, want invalid argument", err)
+	}
+	if _, err := service.Purge(ctx, "admin", cacheinfra.Family("unknown"), ""); !errors.IsCode(err, code.ErrInvalidArgument) {
+		t.Fatalf("Purge(unknown) error = %v, want invalid argument", err)
+	}
+	if _, err := service.SampleKeys(ctx, "admin", cacheinfra.FamilyAuthnSession, 10); !errors.IsCode(err, code.ErrModuleNotFound) {
+		t.Fatalf("SampleKeys() without operator error = %v, want module not found", err)
+	}
+	if len(auditor.entries) != 3 {
+		t.Fatalf("audit entries = %d, want 3", len(auditor.entries))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/alicebob/miniredis/v2"
 	goredis "github.com/redis/go-redis/v9"
 
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
 	jwksdomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/jwks"
 	cacheinfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/cache"
 	redisinfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/redis"
@@ -49,6 +50,7 @@ func TestReadServiceOverview(t *testing.T) {
 	tokenStore := redisinfra.NewRedisStore(client)
 	sessionStore := redisinfra.NewSessionStore(client)
 	otpVerifier := redisinfra.NewOTPVerifier(client)
+	loginThrottle := redisinfra.NewLoginThrottle(client, authentication.ThrottlePolicy{})
 	accessTokenCache := redisinfra.NewAccessTokenCache(client)
 	wechatSDKCache := redisinfra.NewWechatSDKCache(client)
 
@@ -71,6 +73,7 @@ func TestReadServiceOverview(t *testing.T) {
 
 	inspectors := append(redisinfra.RedisStoreInspectors(tokenStore), redisinfra.OTPVerifierInspectors(otpVerifier)...)
 	inspectors = append(inspectors, redisinfra.SessionStoreInspectors(sessionStore)...)
+	inspectors = append(inspectors, redisinfra.LoginThrottleInspectors(loginThrottle)...)
 	inspectors = append(inspectors, redisinfra.AccessTokenCacheInspectors(accessTokenCache)...)
 	inspectors = append(inspectors, redisinfra.WechatSDKCacheInspectors(wechatSDKCache)...)
 	inspectors = append(inspectors, NewJWKSPublishSnapshotInspector(keySetBuilder))
@@ -81,8 +84,8 @@ func TestReadServiceOverview(t *testing.T) {
 		t.Fatalf("Overview() error = %v", err)
 	}
 
-	if len(overview.Families) != 10 {
-		t.Fatalf("family count = %d, want 10", len(overview.Families))
+	if len(overview.Families) != 11 {
+		t.Fatalf("family count = %d, want 11", len(overview.Families))
 	}
 	if len(overview.RuntimeStatuses) != 2 {
 		t.Fatalf("runtime status count = %d, want 2", len(overview.RuntimeStatuses))
@@ -114,8 +117,8 @@ func TestReadServiceDegradesWithoutInspectors(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Overview() error = %v", err)
 	}
-	if len(overview.Families) != 10 {
-		t.Fatalf("family count = %d, want 10", len(overview.Families))
+	if len(overview.Families) != 11 {
+		t.Fatalf("family count = %d, want 11", len(overview.Families))
 	}
 
 	for _, view := range overview.Families {
@@ -0,0 +1,62 @@
+package cachegovernance
+
+import (
+	"context"
+	"strings"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+
+	jwksdomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/jwks"
+	cacheinfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/cache"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+type jwksPublishSnapshotWarmer struct {
+	builder *jwksdomain.KeySetBuilder
+}
+
+// NewJWKSPublishSnapshotWarmer 创建 JWKS 发布快照预热器：按可发布密钥重建进程内快照。
+func NewJWKSPublishSnapshotWarmer(builder *jwksdomain.KeySetBuilder) cacheinfra.FamilyWarmer {
+	if builder == nil {
+		return nil
+	}
+	return &jwksPublishSnapshotWarmer{builder: builder}
+}
+
+func (w *jwksPublishSnapshotWarmer) Family() cacheinfra.Family {
+	return cacheinfra.FamilyAuthnJWKSPublishSnapshot
+}
+
+func (w *jwksPublishSnapshotWarmer) Warm(ctx context.Context, _ string) error {
+	return w.builder.RefreshCache(ctx)
+}
+
+// AccessTokenRefresher 强制刷新微信应用访问令牌。
+type AccessTokenRefresher interface {
+	RefreshAccessToken(ctx context.Context, appID string) (string, error)
+}
+
+type wechatAccessTokenWarmer struct {
+	refresher AccessTokenRefresher
+}
+
+// NewWechatAccessTokenWarmer 创建微信 access token 预热器，target 为 appID。
+func NewWechatAccessTokenWarmer(refresher AccessTokenRefresher) cacheinfra.FamilyWarmer {
+	if refresher == nil {
+		return nil
+	}
+	return &wechatAccessTokenWarmer{refresher: refresher}
+}
+
+func (w *wechatAccessTokenWarmer) Family() cacheinfra.Family {
+	return cacheinfra.FamilyIDPWechatAccessToken
+}
+
+func (w *wechatAccessTokenWarmer) Warm(ctx context.Context, appID string) error {
+	appID = strings.TrimSpace(appID)
+	if appID == "" {
+		return errors.WithCode(code.ErrInvalidArgument, "wechat access token warm-up requires app id")
+	}
+	_, err := w.refresher.RefreshAccessToken(ctx, appID)
+	return err
+}
@@ -0,0 +1,99 @@
+package oidcprovider
+
+import (
+	"context"
+
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/oidcprovider"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// ============= 应用服务接口（Driving Ports）=============
+
+// OIDCProviderApplicationService 上游 OIDC 身份提供商管理应用服务
+type OIDCProviderApplicationService interface {
+	// CreateProvider 登记上游 OIDC 身份提供商
+	CreateProvider(ctx context.Context, dto CreateOIDCProviderDTO) (*OIDCProviderResult, error)
+	// GetProvider 查询上游 OIDC 身份提供商
+	GetProvider(ctx context.Context, slug string) (*OIDCProviderResult, error)
+	// ListProviders 列出上游 OIDC 身份提供商
+	ListProviders(ctx context.Context, filter ListOIDCProvidersFilter) ([]*OIDCProviderResult, error)
+	// UpdateProvider 更新上游 OIDC 身份提供商配置
+	UpdateProvider(ctx context.Context, slug string, dto UpdateOIDCProviderDTO) (*OIDCProviderResult, error)
+	// EnableProvider 启用上游 OIDC 身份提供商
+	EnableProvider(ctx context.Context, slug string) (*OIDCProviderResult, error)
+	// DisableProvider 禁用上游 OIDC 身份提供商
+	DisableProvider(ctx context.Context, slug string) (*OIDCProviderResult, error)
+}
+
+// OIDCProviderCredentialApplicationService 上游 OIDC 身份提供商凭据应用服务
+type OIDCProviderCredentialApplicationService interface {
+	// RotateClientSecret 轮换 client_secret
+	RotateClientSecret(ctx context.Context, slug, newSecret string) error
+}
+
+// OIDCAuthorizeApplicationService 上游 OIDC 授权地址应用服务（供前端发起联合登录）
+type OIDCAuthorizeApplicationService interface {
+	// BuildAuthorizeURL 构造上游授权端点跳转地址
+	BuildAuthorizeURL(ctx context.Context, slug string, dto AuthorizeURLDTO) (*AuthorizeURLResult, error)
+}
+
+// ============= DTOs =============
+
+// CreateOIDCProviderDTO 登记上游 OIDC 身份提供商 DTO
+type CreateOIDCProviderDTO struct {
+	Slug          string                   // 唯一标识（必填）
+	Name          string                   // 显示名称（必填）
+	Issuer        string                   // 上游 Issuer（必填）
+	ClientID      string                   // client_id（必填）
+	ClientSecret  string                   // client_secret（可选，公共客户端可不设置）
+	Scopes        []string                 // 授权 scope，空时使用 openid profile email
+	Claims        domain.ClaimMapping      // 声明映射
+	RoleMappings  []domain.RoleMappingRule // 角色映射规则
+	AutoProvision bool                     // 是否即时开通账户
+	TenantID      meta.ID                  // 映射角色所在租户
+}
+
+// ListOIDCProvidersFilter 上游 OIDC 身份提供商列表过滤条件
+type ListOIDCProvidersFilter struct {
+	Status *domain.Status
+}
+
+// UpdateOIDCProviderDTO 更新上游 OIDC 身份提供商配置 DTO，nil 字段保持不变
+type UpdateOIDCProviderDTO struct {
+	Name          *string
+	Issuer        *string
+	ClientID      *string
+	Scopes        *[]string
+	Claims        *domain.ClaimMapping
+	RoleMappings  *[]domain.RoleMappingRule
+	AutoProvision *bool
+	TenantID      *meta.ID
+}
+
+// AuthorizeURLDTO 构造授权地址 DTO
+type AuthorizeURLDTO struct {
+	RedirectURI   string // 回调地址（必填）
+	CodeChallenge string // PKCE S256 challenge（可选）
+}
+
+// AuthorizeURLResult 授权地址结果
+type AuthorizeURLResult struct {
+	AuthorizeURL string // 上游授权地址
+	State        string // 服务端生成的 state，回调后随 code 提交登录
+}
+
+// OIDCProviderResult 上游 OIDC 身份提供商结果 DTO
+type OIDCProviderResult struct {
+	ID              string
+	Slug            string
+	Name            string
+	Issuer          string
+	ClientID        string
+	Scopes          []string
+	Claims          domain.ClaimMapping
+	RoleMappings    []domain.RoleMappingRule
+	AutoProvision   bool
+	TenantID        string
+	Status          domain.Status
+	HasClientSecret bool
+}
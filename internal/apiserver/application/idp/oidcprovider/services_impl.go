@@ -0,0 +1,381 @@
+package oidcprovider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/logger"
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/oidcprovider"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// ============= 应用服务实现 =============
+
+// ================================================
+// ==== OIDCProviderApplicationService 实现 =====
+// ================================================
+
+type oidcProviderApplicationService struct {
+	repo    domain.Repository
+	creator domain.Creator
+	rotater domain.CredentialRotater
+}
+
+// NewOIDCProviderApplicationService 创建上游 OIDC 身份提供商管理应用服务
+func NewOIDCProviderApplicationService(
+	repo domain.Repository,
+	creator domain.Creator,
+	rotater domain.CredentialRotater,
+) OIDCProviderApplicationService {
+	return &oidcProviderApplicationService{
+		repo:    repo,
+		creator: creator,
+		rotater: rotater,
+	}
+}
+
+// CreateProvider 登记上游 OIDC 身份提供商
+func (s *oidcProviderApplicationService) CreateProvider(ctx context.Context, dto CreateOIDCProviderDTO) (*OIDCProviderResult, error) {
+	l := logger.L(ctx)
+	l.Debugw("登记上游 OIDC 身份提供商",
+		"action", logger.ActionCreate,
+		"resource", "oidc_provider",
+		"slug", dto.Slug,
+		"issuer", dto.Issuer,
+	)
+
+	existing, err := s.repo.GetBySlug(ctx, dto.Slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query oidc provider: %w", err)
+	}
+	if existing != nil {
+		return nil, perrors.WithCode(code.ErrOIDCProviderAlreadyExists, "oidc provider already exists: %s", dto.Slug)
+	}
+
+	provider, err := s.creator.Create(ctx, dto.Slug, strings.TrimSpace(dto.Name), dto.Issuer, strings.TrimSpace(dto.ClientID))
+	if err != nil {
+		l.Warnw("上游 OIDC 身份提供商配置无效",
+			"action", logger.ActionCreate,
+			"resource", "oidc_provider",
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return nil, perrors.WithCode(code.ErrOIDCProviderInvalid, "%s", err.Error())
+	}
+
+	provider.ID = meta.FromUint64(idutil.GetIntID())
+	provider.Scopes = dto.Scopes
+	provider.Claims = dto.Claims
+	provider.RoleMappings = dto.RoleMappings
+	provider.AutoProvision = dto.AutoProvision
+	provider.TenantID = dto.TenantID
+	if err := provider.Validate(); err != nil {
+		return nil, perrors.WithCode(code.ErrOIDCProviderInvalid, "%s", err.Error())
+	}
+
+	if dto.ClientSecret != "" {
+		if err := s.rotater.RotateClientSecret(ctx, provider, dto.ClientSecret); err != nil {
+			return nil, fmt.Errorf("failed to set client secret: %w", err)
+		}
+	}
+
+	if err := s.repo.Create(ctx, provider); err != nil {
+		l.Errorw("持久化上游 OIDC 身份提供商失败",
+			"action", logger.ActionCreate,
+			"resource", "oidc_provider",
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return nil, err
+	}
+
+	return toOIDCProviderResult(provider), nil
+}
+
+// GetProvider 查询上游 OIDC 身份提供商
+func (s *oidcProviderApplicationService) GetProvider(ctx context.Context, slug string) (*OIDCProviderResult, error) {
+	provider, err := getProvider(ctx, s.repo, slug)
+	if err != nil {
+		return nil, err
+	}
+	return toOIDCProviderResult(provider), nil
+}
+
+// ListProviders 列出上游 OIDC 身份提供商
+func (s *oidcProviderApplicationService) ListProviders(ctx context.Context, filter ListOIDCProvidersFilter) ([]*OIDCProviderResult, error) {
+	providers, err := s.repo.List(ctx, domain.ListFilter{Status: filter.Status})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oidc providers: %w", err)
+	}
+
+	results := make([]*OIDCProviderResult, 0, len(providers))
+	for _, provider := range providers {
+		results = append(results, toOIDCProviderResult(provider))
+	}
+	return results, nil
+}
+
+// UpdateProvider 更新上游 OIDC 身份提供商配置
+func (s *oidcProviderApplicationService) UpdateProvider(ctx context.Context, slug string, dto UpdateOIDCProviderDTO) (*OIDCProviderResult, error) {
+	provider, err := getProvider(ctx, s.repo, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if dto.Name != nil {
+		name := strings.TrimSpace(*dto.Name)
+		if name == "" {
+			return nil, perrors.WithCode(code.ErrInvalidArgument, "name cannot be empty")
+		}
+		provider.Name = name
+	}
+	if dto.Issuer != nil {
+		provider.Issuer = strings.TrimRight(strings.TrimSpace(*dto.Issuer), "/")
+	}
+	if dto.ClientID != nil {
+		provider.ClientID = strings.TrimSpace(*dto.ClientID)
+	}
+	if dto.Scopes != nil {
+		provider.Scopes = *dto.Scopes
+	}
+	if dto.Claims != nil {
+		provider.Claims = *dto.Claims
+	}
+	if dto.RoleMappings != nil {
+		provider.RoleMappings = *dto.RoleMappings
+	}
+	if dto.AutoProvision != nil {
+		provider.AutoProvision = *dto.AutoProvision
+	}
+	if dto.TenantID != nil {
+		provider.TenantID = *dto.TenantID
+	}
+	if err := provider.Validate(); err != nil {
+		return nil, perrors.WithCode(code.ErrOIDCProviderInvalid, "%s", err.Error())
+	}
+
+	if err := s.repo.Update(ctx, provider); err != nil {
+		return nil, fmt.Errorf("failed to update oidc provider: %w", err)
+	}
+	return toOIDCProviderResult(provider), nil
+}
+
+// EnableProvider 启用上游 OIDC 身份提供商
+func (s *oidcProviderApplicationService) EnableProvider(ctx context.Context, slug string) (*OIDCProviderResult, error) {
+	return s.changeProviderStatus(ctx, slug, domain.StatusEnabled)
+}
+
+// DisableProvider 禁用上游 OIDC 身份提供商
+func (s *oidcProviderApplicationService) DisableProvider(ctx context.Context, slug string) (*OIDCProviderResult, error) {
+	return s.changeProviderStatus(ctx, slug, domain.StatusDisabled)
+}
+
+func (s *oidcProviderApplicationService) changeProviderStatus(ctx context.Context, slug string, status domain.Status) (*OIDCProviderResult, error) {
+	logger.L(ctx).Debugw("切换上游 OIDC 身份提供商状态",
+		"action", logger.ActionUpdate,
+		"resource", "oidc_provider",
+		"slug", slug,
+		"status", status,
+	)
+
+	provider, err := getProvider(ctx, s.repo, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case domain.StatusEnabled:
+		provider.Enable()
+	case domain.StatusDisabled:
+		provider.Disable()
+	default:
+		return nil, perrors.WithCode(code.ErrOIDCProviderStatusInvalid, "unsupported provider status: %s", status)
+	}
+
+	if err := s.repo.Update(ctx, provider); err != nil {
+		return nil, fmt.Errorf("failed to update oidc provider status: %w", err)
+	}
+	return toOIDCProviderResult(provider), nil
+}
+
+// ===========================================================
+// ==== OIDCProviderCredentialApplicationService 实现 =====
+// ===========================================================
+
+type oidcProviderCredentialApplicationService struct {
+	repo    domain.Repository
+	rotater domain.CredentialRotater
+}
+
+// NewOIDCProviderCredentialApplicationService 创建上游 OIDC 身份提供商凭据应用服务
+func NewOIDCProviderCredentialApplicationService(
+	repo domain.Repository,
+	rotater domain.CredentialRotater,
+) OIDCProviderCredentialApplicationService {
+	return &oidcProviderCredentialApplicationService{
+		repo:    repo,
+		rotater: rotater,
+	}
+}
+
+// RotateClientSecret 轮换 client_secret
+func (s *oidcProviderCredentialApplicationService) RotateClientSecret(ctx context.Context, slug, newSecret string) error {
+	provider, err := getProvider(ctx, s.repo, slug)
+	if err != nil {
+		return err
+	}
+
+	if err := s.rotater.RotateClientSecret(ctx, provider, newSecret); err != nil {
+		logger.L(ctx).Errorw("轮换上游 OIDC client_secret 失败",
+			"action", logger.ActionUpdate,
+			"resource", "oidc_provider_credential",
+			"slug", slug,
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return fmt.Errorf("failed to rotate client secret: %w", err)
+	}
+
+	if err := s.repo.Update(ctx, provider); err != nil {
+		return fmt.Errorf("failed to update oidc provider: %w", err)
+	}
+	return nil
+}
+
+// ==================================================
+// ==== OIDCAuthorizeApplicationService 实现 =====
+// ==================================================
+
+// authorizationTTL 授权请求登记的有效期，需覆盖用户在上游完成登录的时间
+const authorizationTTL = 10 * time.Minute
+
+type oidcAuthorizeApplicationService struct {
+	repo      domain.Repository
+	discovery domain.Discovery
+	store     domain.AuthorizationStore
+}
+
+// NewOIDCAuthorizeApplicationService 创建上游 OIDC 授权地址应用服务
+func NewOIDCAuthorizeApplicationService(repo domain.Repository, discovery domain.Discovery, store domain.AuthorizationStore) OIDCAuthorizeApplicationService {
+	return &oidcAuthorizeApplicationService{
+		repo:      repo,
+		discovery: discovery,
+		store:     store,
+	}
+}
+
+// BuildAuthorizeURL 构造上游授权端点跳转地址（授权码模式，可选 PKCE S256）
+//
+// state 与 nonce 由服务端生成并与 slug、redirect_uri 一同登记，登录时一次性消费
+func (s *oidcAuthorizeApplicationService) BuildAuthorizeURL(ctx context.Context, slug string, dto AuthorizeURLDTO) (*AuthorizeURLResult, error) {
+	if dto.RedirectURI == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "redirect_uri is required")
+	}
+
+	provider, err := getProvider(ctx, s.repo, slug)
+	if err != nil {
+		return nil, err
+	}
+	if !provider.IsEnabled() {
+		return nil, perrors.WithCode(code.ErrOIDCProviderStatusInvalid, "oidc provider is not enabled: %s", slug)
+	}
+
+	endpoint, err := s.discovery.AuthorizationEndpoint(ctx, provider.Issuer)
+	if err != nil {
+		return nil, perrors.WithCode(code.ErrIDPExchangeFailed, "oidc discovery failed: %v", err)
+	}
+	if endpoint == "" {
+		return nil, perrors.WithCode(code.ErrOIDCProviderInvalid, "upstream does not advertise an authorization endpoint")
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, perrors.WithCode(code.ErrOIDCProviderInvalid, "upstream authorization endpoint is malformed")
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate oidc state: %w", err)
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate oidc nonce: %w", err)
+	}
+	if err := s.store.SaveAuthorization(ctx, state, domain.AuthorizationRequest{
+		Slug:        provider.Slug,
+		RedirectURI: dto.RedirectURI,
+		Nonce:       nonce,
+	}, authorizationTTL); err != nil {
+		return nil, fmt.Errorf("failed to save oidc authorization: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", provider.ClientID)
+	q.Set("redirect_uri", dto.RedirectURI)
+	q.Set("scope", strings.Join(provider.ScopesOrDefault(), " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	if dto.CodeChallenge != "" {
+		q.Set("code_challenge", dto.CodeChallenge)
+		q.Set("code_challenge_method", "S256")
+	}
+	u.RawQuery = q.Encode()
+	return &AuthorizeURLResult{AuthorizeURL: u.String(), State: state}, nil
+}
+
+// ============= 辅助函数 =============
+
+// getProvider 按 slug 查询上游 OIDC 身份提供商，不存在时返回 ErrOIDCProviderNotFound
+func getProvider(ctx context.Context, repo domain.Repository, slug string) (*domain.OIDCProvider, error) {
+	provider, err := repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query oidc provider: %w", err)
+	}
+	if provider == nil {
+		return nil, perrors.WithCode(code.ErrOIDCProviderNotFound, "oidc provider not found: %s", slug)
+	}
+	return provider, nil
+}
+
+// randomToken 生成 256 位随机值，用作 state / nonce
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// toOIDCProviderResult 转换领域对象为结果 DTO（不回显 client_secret）
+func toOIDCProviderResult(p *domain.OIDCProvider) *OIDCProviderResult {
+	if p == nil {
+		return nil
+	}
+
+	result := &OIDCProviderResult{
+		ID:              p.ID.String(),
+		Slug:            p.Slug,
+		Name:            p.Name,
+		Issuer:          p.Issuer,
+		ClientID:        p.ClientID,
+		Scopes:          p.Scopes,
+		Claims:          p.Claims,
+		RoleMappings:    p.RoleMappings,
+		AutoProvision:   p.AutoProvision,
+		Status:          p.Status,
+		HasClientSecret: p.Cred != nil && len(p.Cred.SecretCipher) > 0,
+	}
+	if !p.TenantID.IsZero() {
+		result.TenantID = p.TenantID.String()
+	}
+	return result
+}
@@ -0,0 +1,133 @@
+package oidcprovider
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/oidcprovider"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/testhelpers"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/stretchr/testify/require"
+)
+
+type repoStub struct {
+	providers map[string]*domain.OIDCProvider
+}
+
+func (r *repoStub) Create(_ context.Context, p *domain.OIDCProvider) error {
+	r.providers[p.Slug] = p
+	return nil
+}
+
+func (r *repoStub) GetByID(context.Context, idutil.ID) (*domain.OIDCProvider, error) { return nil, nil }
+
+func (r *repoStub) GetBySlug(_ context.Context, slug string) (*domain.OIDCProvider, error) {
+	return r.providers[slug], nil
+}
+
+func (r *repoStub) List(context.Context, domain.ListFilter) ([]*domain.OIDCProvider, error) {
+	return nil, nil
+}
+
+func (r *repoStub) Update(_ context.Context, p *domain.OIDCProvider) error {
+	r.providers[p.Slug] = p
+	return nil
+}
+
+type discoveryStub struct{}
+
+func (discoveryStub) AuthorizationEndpoint(context.Context, string) (string, error) {
+	return "https://sso.example.com/authorize?prompt=login", nil
+}
+
+type authorizationStoreStub struct {
+	saved map[string]domain.AuthorizationRequest
+	ttl   time.Duration
+}
+
+func (s *authorizationStoreStub) SaveAuthorization(_ context.Context, state string, req domain.AuthorizationRequest, ttl time.Duration) error {
+	s.saved[state] = req
+	s.ttl = ttl
+	return nil
+}
+
+func (s *authorizationStoreStub) ConsumeAuthorization(context.Context, string) (*domain.AuthorizationRequest, error) {
+	return nil, domain.ErrAuthorizationNotFound
+}
+
+func TestOIDCProviderServices_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := &repoStub{providers: map[string]*domain.OIDCProvider{}}
+	rotater := domain.NewCredentialRotater(&testhelpers.VaultStub{}, time.Now)
+	appSvc := NewOIDCProviderApplicationService(repo, domain.NewCreator(repo), rotater)
+	credSvc := NewOIDCProviderCredentialApplicationService(repo, rotater)
+	store := &authorizationStoreStub{saved: map[string]domain.AuthorizationRequest{}}
+	authorizeSvc := NewOIDCAuthorizeApplicationService(repo, discoveryStub{}, store)
+
+	created, err := appSvc.CreateProvider(ctx, CreateOIDCProviderDTO{
+		Slug:         "corp-sso",
+		Name:         "Corp SSO",
+		Issuer:       "https://sso.example.com/",
+		ClientID:     "iam",
+		ClientSecret: "client-secret",
+		Scopes:       []string{"groups"},
+		RoleMappings: []domain.RoleMappingRule{{Claim: "groups", Value: "iam-admins", Role: "admin"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusEnabled, created.Status)
+	require.Equal(t, "https://sso.example.com", created.Issuer)
+	require.True(t, created.HasClientSecret)
+	require.Equal(t, []byte("cipher:client-secret"), repo.providers["corp-sso"].Cred.SecretCipher)
+
+	_, err = appSvc.CreateProvider(ctx, CreateOIDCProviderDTO{Slug: "corp-sso", Name: "Dup", Issuer: "https://sso.example.com", ClientID: "iam"})
+	require.True(t, perrors.IsCode(err, code.ErrOIDCProviderAlreadyExists))
+
+	_, err = appSvc.CreateProvider(ctx, CreateOIDCProviderDTO{Slug: "bad", Name: "Bad", Issuer: "not-a-url", ClientID: "iam"})
+	require.True(t, perrors.IsCode(err, code.ErrOIDCProviderInvalid))
+
+	incomplete := []domain.RoleMappingRule{{Claim: "groups"}}
+	_, err = appSvc.UpdateProvider(ctx, "corp-sso", UpdateOIDCProviderDTO{RoleMappings: &incomplete})
+	require.True(t, perrors.IsCode(err, code.ErrOIDCProviderInvalid))
+
+	require.NoError(t, credSvc.RotateClientSecret(ctx, "corp-sso", "client-secret-2"))
+	require.Equal(t, 2, repo.providers["corp-sso"].Cred.Version)
+
+	authorize, err := authorizeSvc.BuildAuthorizeURL(ctx, "corp-sso", AuthorizeURLDTO{
+		RedirectURI:   "https://app.example.com/cb",
+		CodeChallenge: "challenge",
+	})
+	require.NoError(t, err)
+	u, err := url.Parse(authorize.AuthorizeURL)
+	require.NoError(t, err)
+	q := u.Query()
+	require.Equal(t, "login", q.Get("prompt"))
+	require.Equal(t, "code", q.Get("response_type"))
+	require.Equal(t, "iam", q.Get("client_id"))
+	require.Equal(t, "openid groups", q.Get("scope"))
+	require.Equal(t, "S256", q.Get("code_challenge_method"))
+
+	// state 与 nonce 由服务端生成并登记
+	require.NotEmpty(t, authorize.State)
+	require.Equal(t, authorize.State, q.Get("state"))
+	saved, ok := store.saved[authorize.State]
+	require.True(t, ok)
+	require.Equal(t, "corp-sso", saved.Slug)
+	require.Equal(t, "https://app.example.com/cb", saved.RedirectURI)
+	require.NotEmpty(t, saved.Nonce)
+	require.Equal(t, saved.Nonce, q.Get("nonce"))
+	require.Equal(t, authorizationTTL, store.ttl)
+
+	disabled, err := appSvc.DisableProvider(ctx, "corp-sso")
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusDisabled, disabled.Status)
+
+	_, err = authorizeSvc.BuildAuthorizeURL(ctx, "corp-sso", AuthorizeURLDTO{RedirectURI: "https://app.example.com/cb"})
+	require.True(t, perrors.IsCode(err, code.ErrOIDCProviderStatusInvalid))
+
+	_, err = appSvc.GetProvider(ctx, "unknown")
+	require.True(t, perrors.IsCode(err, code.ErrOIDCProviderNotFound))
+}
@@ -0,0 +1,257 @@
+package wechatapp
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/logger"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+const (
+	// callbackTimestampSkew 回调时间戳允许的最大偏差
+	callbackTimestampSkew = 5 * time.Minute
+	// callbackNonceTTL nonce 防重放记录的保留时长，覆盖整个时间戳窗口
+	callbackNonceTTL = 2 * callbackTimestampSkew
+)
+
+// ================================================
+// ==== WechatAppCallbackApplicationService 实现 =====
+// ================================================
+
+type wechatAppCallbackApplicationService struct {
+	repo      domain.Repository
+	vault     domain.SecretVault
+	codec     domain.CallbackCodec
+	nonces    domain.CallbackNonceCache
+	revoker   domain.AuthorizationRevoker
+	publisher domain.CallbackEventPublisher // 可为 nil，不转发事件
+	now       func() time.Time
+}
+
+// NewWechatAppCallbackApplicationService 创建微信消息推送应用服务
+func NewWechatAppCallbackApplicationService(
+	repo domain.Repository,
+	vault domain.SecretVault,
+	codec domain.CallbackCodec,
+	nonces domain.CallbackNonceCache,
+	revoker domain.AuthorizationRevoker,
+	publisher domain.CallbackEventPublisher,
+) WechatAppCallbackApplicationService {
+	return &wechatAppCallbackApplicationService{
+		repo:      repo,
+		vault:     vault,
+		codec:     codec,
+		nonces:    nonces,
+		revoker:   revoker,
+		publisher: publisher,
+		now:       time.Now,
+	}
+}
+
+// VerifyURL 服务器配置校验
+func (s *wechatAppCallbackApplicationService) VerifyURL(ctx context.Context, dto VerifyCallbackURLDTO) (string, error) {
+	app, err := s.getApp(ctx, dto.AppID)
+	if err != nil {
+		return "", err
+	}
+	if !s.verify(dto.Signature, s.codec.Sign(app.Cred.Msg.CallbackToken, dto.Timestamp, dto.Nonce)) {
+		return "", perrors.WithCode(code.ErrWechatCallbackSignatureInvalid, "wechat callback signature mismatch: %s", dto.AppID)
+	}
+	return dto.EchoStr, nil
+}
+
+// HandleMessage 处理推送消息
+//
+// 安全模式（encrypt_type=aes）校验 msg_signature 并解密 Encrypt 字段；
+// 明文模式仅校验 signature，配置了 EncodingAESKey 的应用只接受安全模式。
+// 时间戳超出窗口或 nonce 重复的请求视为重放。
+// 撤回授权事件会禁用关联凭据，其余消息转发到消息总线。
+func (s *wechatAppCallbackApplicationService) HandleMessage(ctx context.Context, dto CallbackMessageDTO) error {
+	l := logger.L(ctx)
+
+	app, err := s.getApp(ctx, dto.AppID)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := s.open(ctx, app, dto)
+	if err != nil {
+		l.Warnw("微信回调消息校验失败",
+			"action", logger.ActionRead,
+			"resource", "wechat_callback",
+			"app_id", dto.AppID,
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return err
+	}
+
+	// 处理失败时撤回 nonce：返回错误会触发微信重试，重试请求不能被当作重放拒绝
+	if err := s.dispatch(ctx, dto.AppID, plaintext); err != nil {
+		if releaseErr := s.nonces.ReleaseNonce(ctx, dto.AppID, dto.Nonce); releaseErr != nil {
+			l.Warnw("撤回微信回调 nonce 失败",
+				"action", logger.ActionDelete,
+				"resource", "wechat_callback",
+				"app_id", dto.AppID,
+				"error", releaseErr.Error(),
+				"result", logger.ResultFailed,
+			)
+		}
+		return err
+	}
+	return nil
+}
+
+// dispatch 解析明文消息并执行对应处理
+func (s *wechatAppCallbackApplicationService) dispatch(ctx context.Context, appID string, plaintext []byte) error {
+	l := logger.L(ctx)
+
+	msg, err := domain.ParseCallbackMessage(plaintext)
+	if err != nil {
+		return perrors.WithCode(code.ErrWechatCallbackMalformed, "invalid wechat callback xml: %v", err)
+	}
+
+	switch {
+	case msg.IsEvent(domain.EventUserAuthorizationRevoke):
+		openID := msg.SubjectOpenID()
+		revoked, err := s.revoker.RevokeWechatAuthorization(ctx, appID, openID)
+		if err != nil {
+			return fmt.Errorf("failed to revoke wechat authorization: %w", err)
+		}
+		l.Infow("微信用户撤回授权，已禁用关联凭据",
+			"action", logger.ActionUpdate,
+			"resource", "wechat_callback",
+			"app_id", appID,
+			"openid", openID,
+			"revoke_info", msg.RevokeInfo,
+			"revoked", revoked,
+			"result", logger.ResultSuccess,
+		)
+	case msg.IsEvent(domain.EventSubscribe), msg.IsEvent(domain.EventUnsubscribe):
+		l.Infow("微信用户关注状态变更",
+			"action", logger.ActionRead,
+			"resource", "wechat_callback",
+			"app_id", appID,
+			"openid", msg.SubjectOpenID(),
+			"event", msg.Event,
+		)
+	}
+
+	// 转发失败仅记录日志：返回错误会触发微信重试，导致已处理的事件被重复执行
+	if s.publisher != nil {
+		if err := s.publisher.PublishCallbackEvent(ctx, appID, msg); err != nil {
+			l.Warnw("转发微信回调消息失败",
+				"action", logger.ActionCreate,
+				"resource", "wechat_callback",
+				"app_id", appID,
+				"msg_type", msg.MsgType,
+				"event", msg.Event,
+				"error", err.Error(),
+				"result", logger.ResultFailed,
+			)
+		}
+	}
+	return nil
+}
+
+// open 验签、防重放并返回明文 XML
+func (s *wechatAppCallbackApplicationService) open(ctx context.Context, app *domain.WechatApp, dto CallbackMessageDTO) ([]byte, error) {
+	token := app.Cred.Msg.CallbackToken
+	aesConfigured := len(app.Cred.Msg.EncodingAESKeyCipher) > 0
+	if err := s.checkTimestamp(app.AppID, dto.Timestamp); err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(dto.EncryptType, "aes") {
+		// 已配置 EncodingAESKey 时拒绝明文请求，防止绕过安全模式
+		if aesConfigured {
+			return nil, perrors.WithCode(code.ErrWechatCallbackSignatureInvalid, "wechat callback must use aes mode: %s", app.AppID)
+		}
+		if !s.verify(dto.Signature, s.codec.Sign(token, dto.Timestamp, dto.Nonce)) {
+			return nil, perrors.WithCode(code.ErrWechatCallbackSignatureInvalid, "wechat callback signature mismatch: %s", app.AppID)
+		}
+		if err := s.markNonce(ctx, app.AppID, dto.Nonce); err != nil {
+			return nil, err
+		}
+		return dto.Body, nil
+	}
+
+	var envelope domain.CallbackEnvelope
+	if err := xml.Unmarshal(dto.Body, &envelope); err != nil || envelope.Encrypt == "" {
+		return nil, perrors.WithCode(code.ErrWechatCallbackMalformed, "wechat callback envelope has no Encrypt field")
+	}
+	if !s.verify(dto.MsgSignature, s.codec.Sign(token, dto.Timestamp, dto.Nonce, envelope.Encrypt)) {
+		return nil, perrors.WithCode(code.ErrWechatCallbackSignatureInvalid, "wechat callback msg_signature mismatch: %s", app.AppID)
+	}
+
+	if !aesConfigured {
+		return nil, perrors.WithCode(code.ErrWechatMsgSecretNotConfigured, "encoding aes key is not configured: %s", app.AppID)
+	}
+	aesKey, err := s.vault.Decrypt(ctx, app.Cred.Msg.EncodingAESKeyCipher)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt encoding aes key: %w", err)
+	}
+	plaintext, err := s.codec.Decrypt(app.AppID, string(aesKey), envelope.Encrypt)
+	if err != nil {
+		return nil, perrors.WithCode(code.ErrWechatCallbackMalformed, "failed to decrypt wechat callback: %v", err)
+	}
+	if err := s.markNonce(ctx, app.AppID, dto.Nonce); err != nil {
+		return nil, err
+	}
+	return plaintext, nil
+}
+
+// checkTimestamp 拒绝超出 ±callbackTimestampSkew 窗口的请求
+func (s *wechatAppCallbackApplicationService) checkTimestamp(appID, timestamp string) error {
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return perrors.WithCode(code.ErrWechatCallbackSignatureInvalid, "invalid wechat callback timestamp: %s", appID)
+	}
+	skew := s.now().Sub(time.Unix(sec, 0))
+	if skew > callbackTimestampSkew || skew < -callbackTimestampSkew {
+		return perrors.WithCode(code.ErrWechatCallbackSignatureInvalid, "wechat callback timestamp out of window: %s", appID)
+	}
+	return nil
+}
+
+// markNonce 记录已验签请求的 nonce，窗口期内重复出现视为重放
+func (s *wechatAppCallbackApplicationService) markNonce(ctx context.Context, appID, nonce string) error {
+	if nonce == "" {
+		return perrors.WithCode(code.ErrWechatCallbackSignatureInvalid, "wechat callback nonce is empty: %s", appID)
+	}
+	first, err := s.nonces.MarkNonce(ctx, appID, nonce, callbackNonceTTL)
+	if err != nil {
+		return fmt.Errorf("failed to check wechat callback nonce: %w", err)
+	}
+	if !first {
+		return perrors.WithCode(code.ErrWechatCallbackSignatureInvalid, "wechat callback nonce replayed: %s", appID)
+	}
+	return nil
+}
+
+func (s *wechatAppCallbackApplicationService) verify(got, want string) bool {
+	return got != "" && subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+// getApp 查询应用并确认已配置消息推送 Token
+func (s *wechatAppCallbackApplicationService) getApp(ctx context.Context, appID string) (*domain.WechatApp, error) {
+	app, err := s.repo.GetByAppID(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wechat app: %w", err)
+	}
+	if app == nil {
+		return nil, perrors.WithCode(code.ErrWechatAppNotFound, "wechat app not found: %s", appID)
+	}
+	if app.Cred == nil || app.Cred.Msg == nil || app.Cred.Msg.CallbackToken == "" {
+		return nil, perrors.WithCode(code.ErrWechatMsgSecretNotConfigured, "callback token is not configured: %s", appID)
+	}
+	return app, nil
+}
@@ -0,0 +1,266 @@
+package wechatapp
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"testing"
+	"time"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/silenceper/wechat/v2/util"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+const (
+	callbackToken     = "callback-token"
+	callbackAESKey    = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+	callbackTimestamp = "1700000000"
+	callbackNonce     = "nonce"
+)
+
+// plainVault 测试用保险库：密文即明文
+type plainVault struct{}
+
+func (plainVault) Encrypt(_ context.Context, p []byte) ([]byte, error) { return p, nil }
+func (plainVault) Decrypt(_ context.Context, c []byte) ([]byte, error) { return c, nil }
+func (plainVault) Sign(context.Context, string, []byte) ([]byte, error) {
+	return nil, nil
+}
+
+// sdkCodec 直接使用 SDK 算法，与基础设施层实现一致
+type sdkCodec struct{}
+
+func (sdkCodec) Sign(token, timestamp, nonce string, extra ...string) string {
+	return util.Signature(append([]string{token, timestamp, nonce}, extra...)...)
+}
+
+func (sdkCodec) Decrypt(appID, key, ciphertext string) ([]byte, error) {
+	_, plain, err := util.DecryptMsg(appID, ciphertext, key)
+	return plain, err
+}
+
+// nonceCacheStub 内存 nonce 缓存
+type nonceCacheStub struct {
+	seen map[string]bool
+}
+
+func (c *nonceCacheStub) MarkNonce(_ context.Context, appID, nonce string, _ time.Duration) (bool, error) {
+	key := appID + "/" + nonce
+	if c.seen[key] {
+		return false, nil
+	}
+	c.seen[key] = true
+	return true, nil
+}
+
+func (c *nonceCacheStub) ReleaseNonce(_ context.Context, appID, nonce string) error {
+	delete(c.seen, appID+"/"+nonce)
+	return nil
+}
+
+type revokerStub struct {
+	calls []string
+	err   error
+}
+
+func (r *revokerStub) RevokeWechatAuthorization(_ context.Context, appID, openID string) (int, error) {
+	r.calls = append(r.calls, appID+"/"+openID)
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 1, nil
+}
+
+type publisherStub struct {
+	events []string
+}
+
+func (p *publisherStub) PublishCallbackEvent(_ context.Context, appID string, msg *domain.CallbackMessage) error {
+	p.events = append(p.events, appID+"/"+msg.MsgType+"/"+msg.Event)
+	return nil
+}
+
+func newCallbackService(t *testing.T) (WechatAppCallbackApplicationService, *revokerStub, *publisherStub) {
+	t.Helper()
+	repo := &repoStub{apps: map[string]*domain.WechatApp{
+		"wx-mp": {
+			AppID:  "wx-mp",
+			Type:   domain.MP,
+			Status: domain.StatusEnabled,
+			Cred: &domain.Credentials{Msg: &domain.MsgSecret{
+				CallbackToken:        callbackToken,
+				EncodingAESKeyCipher: []byte(callbackAESKey),
+			}},
+		},
+		"wx-plain": {
+			AppID:  "wx-plain",
+			Type:   domain.MP,
+			Status: domain.StatusEnabled,
+			Cred:   &domain.Credentials{Msg: &domain.MsgSecret{CallbackToken: callbackToken}},
+		},
+		"wx-no-msg": {AppID: "wx-no-msg", Type: domain.MP, Status: domain.StatusEnabled},
+	}}
+	revoker := &revokerStub{}
+	publisher := &publisherStub{}
+	svc := NewWechatAppCallbackApplicationService(repo, plainVault{}, sdkCodec{}, &nonceCacheStub{seen: map[string]bool{}}, revoker, publisher)
+	svc.(*wechatAppCallbackApplicationService).now = func() time.Time { return time.Unix(1700000000, 0) }
+	return svc, revoker, publisher
+}
+
+// encryptedMessage 构造安全模式回调请求
+func encryptedMessage(t *testing.T, appID, rawXML string) CallbackMessageDTO {
+	t.Helper()
+	encrypted, err := util.EncryptMsg([]byte("0123456789abcdef"), []byte(rawXML), appID, callbackAESKey)
+	require.NoError(t, err)
+	return CallbackMessageDTO{
+		AppID:        "wx-mp",
+		MsgSignature: util.Signature(callbackToken, callbackTimestamp, callbackNonce, string(encrypted)),
+		Timestamp:    callbackTimestamp,
+		Nonce:        callbackNonce,
+		EncryptType:  "aes",
+		Body:         []byte(fmt.Sprintf("<xml><ToUserName><![CDATA[gh_1]]></ToUserName><Encrypt><![CDATA[%s]]></Encrypt></xml>", encrypted)),
+	}
+}
+
+func eventXML(event, extra string) string {
+	return fmt.Sprintf("<xml><ToUserName><![CDATA[gh_1]]></ToUserName><FromUserName><![CDATA[openid-1]]></FromUserName>"+
+		"<CreateTime>1700000000</CreateTime><MsgType><![CDATA[event]]></MsgType><Event><![CDATA[%s]]></Event>%s</xml>", event, extra)
+}
+
+func TestCallbackService_VerifyURL(t *testing.T) {
+	svc, _, _ := newCallbackService(t)
+	ctx := context.Background()
+
+	echo, err := svc.VerifyURL(ctx, VerifyCallbackURLDTO{
+		AppID:     "wx-mp",
+		Signature: util.Signature(callbackToken, callbackTimestamp, callbackNonce),
+		Timestamp: callbackTimestamp,
+		Nonce:     callbackNonce,
+		EchoStr:   "echo",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "echo", echo)
+
+	_, err = svc.VerifyURL(ctx, VerifyCallbackURLDTO{AppID: "wx-mp", Signature: "forged", Timestamp: callbackTimestamp, Nonce: callbackNonce})
+	require.True(t, perrors.IsCode(err, code.ErrWechatCallbackSignatureInvalid))
+
+	_, err = svc.VerifyURL(ctx, VerifyCallbackURLDTO{AppID: "wx-no-msg"})
+	require.True(t, perrors.IsCode(err, code.ErrWechatMsgSecretNotConfigured))
+
+	_, err = svc.VerifyURL(ctx, VerifyCallbackURLDTO{AppID: "wx-missing"})
+	require.True(t, perrors.IsCode(err, code.ErrWechatAppNotFound))
+}
+
+func TestCallbackService_RevokeEventDisablesCredential(t *testing.T) {
+	svc, revoker, publisher := newCallbackService(t)
+
+	dto := encryptedMessage(t, "wx-mp", eventXML(domain.EventUserAuthorizationRevoke,
+		"<OpenID><![CDATA[openid-revoked]]></OpenID><AppID><![CDATA[wx-mp]]></AppID><RevokeInfo><![CDATA[301]]></RevokeInfo>"))
+	require.NoError(t, svc.HandleMessage(context.Background(), dto))
+
+	require.Equal(t, []string{"wx-mp/openid-revoked"}, revoker.calls)
+	require.Equal(t, []string{"wx-mp/event/user_authorization_revoke"}, publisher.events)
+}
+
+func TestCallbackService_ForwardsOtherMessages(t *testing.T) {
+	svc, revoker, publisher := newCallbackService(t)
+	ctx := context.Background()
+
+	require.NoError(t, svc.HandleMessage(ctx, encryptedMessage(t, "wx-mp", eventXML(domain.EventSubscribe, ""))))
+
+	// 明文模式（未配置 EncodingAESKey）
+	require.NoError(t, svc.HandleMessage(ctx, CallbackMessageDTO{
+		AppID:     "wx-plain",
+		Signature: util.Signature(callbackToken, callbackTimestamp, callbackNonce),
+		Timestamp: callbackTimestamp,
+		Nonce:     callbackNonce,
+		Body:      []byte(eventXML("CLICK", "<EventKey><![CDATA[menu]]></EventKey>")),
+	}))
+
+	require.Empty(t, revoker.calls)
+	require.Equal(t, []string{"wx-mp/event/subscribe", "wx-plain/event/CLICK"}, publisher.events)
+}
+
+func TestCallbackService_RejectsTamperedMessages(t *testing.T) {
+	svc, revoker, publisher := newCallbackService(t)
+	ctx := context.Background()
+
+	forged := encryptedMessage(t, "wx-mp", eventXML(domain.EventUserAuthorizationRevoke, ""))
+	forged.MsgSignature = "forged"
+	require.True(t, perrors.IsCode(svc.HandleMessage(ctx, forged), code.ErrWechatCallbackSignatureInvalid))
+
+	// 使用其他应用的密文（AppID 校验失败）
+	other := encryptedMessage(t, "wx-other", eventXML(domain.EventUserAuthorizationRevoke, ""))
+	require.True(t, perrors.IsCode(svc.HandleMessage(ctx, other), code.ErrWechatCallbackMalformed))
+
+	empty := encryptedMessage(t, "wx-mp", "")
+	empty.Body = []byte("<xml></xml>")
+	require.True(t, perrors.IsCode(svc.HandleMessage(ctx, empty), code.ErrWechatCallbackMalformed))
+
+	require.Empty(t, revoker.calls)
+	require.Empty(t, publisher.events)
+}
+
+func TestCallbackService_RejectsPlaintextWhenAESConfigured(t *testing.T) {
+	svc, revoker, publisher := newCallbackService(t)
+
+	err := svc.HandleMessage(context.Background(), CallbackMessageDTO{
+		AppID:     "wx-mp",
+		Signature: util.Signature(callbackToken, callbackTimestamp, callbackNonce),
+		Timestamp: callbackTimestamp,
+		Nonce:     callbackNonce,
+		Body: []byte(eventXML(domain.EventUserAuthorizationRevoke,
+			"<OpenID><![CDATA[openid-revoked]]></OpenID><AppID><![CDATA[wx-mp]]></AppID>")),
+	})
+	require.True(t, perrors.IsCode(err, code.ErrWechatCallbackSignatureInvalid))
+
+	require.Empty(t, revoker.calls)
+	require.Empty(t, publisher.events)
+}
+
+func TestCallbackService_RejectsReplayedMessages(t *testing.T) {
+	svc, revoker, publisher := newCallbackService(t)
+	ctx := context.Background()
+
+	dto := encryptedMessage(t, "wx-mp", eventXML(domain.EventUserAuthorizationRevoke,
+		"<OpenID><![CDATA[openid-revoked]]></OpenID><AppID><![CDATA[wx-mp]]></AppID>"))
+	require.NoError(t, svc.HandleMessage(ctx, dto))
+	require.True(t, perrors.IsCode(svc.HandleMessage(ctx, dto), code.ErrWechatCallbackSignatureInvalid))
+
+	// 时间戳超出窗口
+	stale := encryptedMessage(t, "wx-mp", eventXML(domain.EventSubscribe, ""))
+	stale.Nonce = "nonce-stale"
+	stale.Timestamp = "1699999000"
+	var envelope domain.CallbackEnvelope
+	require.NoError(t, xml.Unmarshal(stale.Body, &envelope))
+	stale.MsgSignature = util.Signature(callbackToken, stale.Timestamp, stale.Nonce, envelope.Encrypt)
+	require.True(t, perrors.IsCode(svc.HandleMessage(ctx, stale), code.ErrWechatCallbackSignatureInvalid))
+
+	require.Equal(t, []string{"wx-mp/openid-revoked"}, revoker.calls)
+	require.Equal(t, []string{"wx-mp/event/user_authorization_revoke"}, publisher.events)
+}
+
+func TestCallbackService_RetriesAfterRevokeFailure(t *testing.T) {
+	svc, revoker, publisher := newCallbackService(t)
+	ctx := context.Background()
+
+	dto := encryptedMessage(t, "wx-mp", eventXML(domain.EventUserAuthorizationRevoke,
+		"<OpenID><![CDATA[openid-revoked]]></OpenID><AppID><![CDATA[wx-mp]]></AppID>"))
+
+	// 撤回授权失败时返回错误，微信重试同一条消息（相同 nonce）必须能再次处理
+	revoker.err = fmt.Errorf("db down")
+	require.Error(t, svc.HandleMessage(ctx, dto))
+	require.Empty(t, publisher.events)
+
+	revoker.err = nil
+	require.NoError(t, svc.HandleMessage(ctx, dto))
+	require.Equal(t, []string{"wx-mp/openid-revoked", "wx-mp/openid-revoked"}, revoker.calls)
+	require.Equal(t, []string{"wx-mp/event/user_authorization_revoke"}, publisher.events)
+
+	// 成功处理后的重复请求仍视为重放
+	require.True(t, perrors.IsCode(svc.HandleMessage(ctx, dto), code.ErrWechatCallbackSignatureInvalid))
+}
@@ -40,6 +40,14 @@ type WechatAppTokenApplicationService interface {
 	RefreshAccessToken(ctx context.Context, appID string) (string, error)
 }
 
+// WechatAppCallbackApplicationService 微信服务器消息推送应用服务
+type WechatAppCallbackApplicationService interface {
+	// VerifyURL 服务器配置校验：验签通过后原样返回 echostr
+	VerifyURL(ctx context.Context, dto VerifyCallbackURLDTO) (string, error)
+	// HandleMessage 处理推送消息：验签、安全模式解密并分发事件
+	HandleMessage(ctx context.Context, dto CallbackMessageDTO) error
+}
+
 // ============= DTOs =============
 
 // CreateWechatAppDTO 创建微信应用 DTO
@@ -70,3 +78,23 @@ type WechatAppResult struct {
 	Type   domain.AppType // 应用类型
 	Status domain.Status  // 应用状态
 }
+
+// VerifyCallbackURLDTO 服务器配置校验 DTO（GET 回调）
+type VerifyCallbackURLDTO struct {
+	AppID     string
+	Signature string
+	Timestamp string
+	Nonce     string
+	EchoStr   string
+}
+
+// CallbackMessageDTO 推送消息 DTO（POST 回调）
+type CallbackMessageDTO struct {
+	AppID        string
+	Signature    string // 明文模式签名
+	MsgSignature string // 安全模式签名（包含密文）
+	Timestamp    string
+	Nonce        string
+	EncryptType  string // aes 表示安全模式
+	Body         []byte // 原始 XML 请求体
+}
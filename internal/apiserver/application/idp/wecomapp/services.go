@@ -0,0 +1,69 @@
+package wecomapp
+
+import (
+	"context"
+
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
+)
+
+// ============= 应用服务接口（Driving Ports）=============
+
+// WecomAppApplicationService 企业微信应用管理应用服务
+type WecomAppApplicationService interface {
+	// CreateApp 登记企业微信应用
+	CreateApp(ctx context.Context, dto CreateWecomAppDTO) (*WecomAppResult, error)
+	// GetApp 查询企业微信应用
+	GetApp(ctx context.Context, corpID, agentID string) (*WecomAppResult, error)
+	// ListApps 列出企业微信应用
+	ListApps(ctx context.Context, filter ListWecomAppsFilter) ([]*WecomAppResult, error)
+	// UpdateApp 更新企业微信应用基础信息
+	UpdateApp(ctx context.Context, corpID, agentID string, dto UpdateWecomAppDTO) (*WecomAppResult, error)
+	// EnableApp 启用企业微信应用
+	EnableApp(ctx context.Context, corpID, agentID string) (*WecomAppResult, error)
+	// DisableApp 禁用企业微信应用
+	DisableApp(ctx context.Context, corpID, agentID string) (*WecomAppResult, error)
+}
+
+// WecomAppCredentialApplicationService 企业微信应用凭据应用服务
+type WecomAppCredentialApplicationService interface {
+	// RotateCorpSecret 轮换应用 Secret
+	RotateCorpSecret(ctx context.Context, corpID, agentID, newSecret string) error
+}
+
+// WecomAppTokenApplicationService 企业微信应用访问令牌应用服务
+type WecomAppTokenApplicationService interface {
+	// GetAccessToken 获取访问令牌（带缓存和自动刷新）
+	GetAccessToken(ctx context.Context, corpID, agentID string) (string, error)
+	// RefreshAccessToken 强制刷新访问令牌
+	RefreshAccessToken(ctx context.Context, corpID, agentID string) (string, error)
+}
+
+// ============= DTOs =============
+
+// CreateWecomAppDTO 登记企业微信应用 DTO
+type CreateWecomAppDTO struct {
+	CorpID     string // 企业 ID（必填）
+	AgentID    string // 应用 AgentID（必填）
+	Name       string // 应用名称（必填）
+	CorpSecret string // 应用 Secret（可选，创建时设置）
+}
+
+// ListWecomAppsFilter 企业微信应用列表过滤条件。
+type ListWecomAppsFilter struct {
+	CorpID *string
+	Status *domain.Status
+}
+
+// UpdateWecomAppDTO 更新企业微信应用基础信息 DTO。
+type UpdateWecomAppDTO struct {
+	Name *string
+}
+
+// WecomAppResult 企业微信应用结果 DTO
+type WecomAppResult struct {
+	ID      string        // 内部 ID
+	CorpID  string        // 企业 ID
+	AgentID string        // 应用 AgentID
+	Name    string        // 应用名称
+	Status  domain.Status // 应用状态
+}
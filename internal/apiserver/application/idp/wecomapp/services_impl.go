@@ -0,0 +1,309 @@
+package wecomapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/logger"
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// ============= 应用服务实现 =============
+
+// ================================================
+// ==== WecomAppApplicationService 实现 =====
+// ================================================
+
+type wecomAppApplicationService struct {
+	repo    domain.Repository
+	creator domain.Creator
+	rotater domain.CredentialRotater
+}
+
+// NewWecomAppApplicationService 创建企业微信应用管理应用服务
+func NewWecomAppApplicationService(
+	repo domain.Repository,
+	creator domain.Creator,
+	rotater domain.CredentialRotater,
+) WecomAppApplicationService {
+	return &wecomAppApplicationService{
+		repo:    repo,
+		creator: creator,
+		rotater: rotater,
+	}
+}
+
+// CreateApp 登记企业微信应用
+func (s *wecomAppApplicationService) CreateApp(ctx context.Context, dto CreateWecomAppDTO) (*WecomAppResult, error) {
+	l := logger.L(ctx)
+	l.Debugw("登记企业微信应用",
+		"action", logger.ActionCreate,
+		"resource", "wecom_app",
+		"corp_id", dto.CorpID,
+		"agent_id", dto.AgentID,
+	)
+
+	app, err := s.creator.Create(ctx, dto.CorpID, dto.AgentID, dto.Name)
+	if err != nil {
+		l.Errorw("创建企业微信应用实体失败",
+			"action", logger.ActionCreate,
+			"resource", "wecom_app",
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return nil, fmt.Errorf("failed to create wecom app: %w", err)
+	}
+
+	app.ID = meta.FromUint64(idutil.GetIntID())
+	app.Cred = &domain.Credentials{}
+
+	if dto.CorpSecret != "" {
+		if err := s.rotater.RotateCorpSecret(ctx, app, dto.CorpSecret); err != nil {
+			return nil, fmt.Errorf("failed to set corp secret: %w", err)
+		}
+	}
+
+	if err := s.repo.Create(ctx, app); err != nil {
+		l.Errorw("持久化企业微信应用失败",
+			"action", logger.ActionCreate,
+			"resource", "wecom_app",
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return nil, fmt.Errorf("failed to persist wecom app: %w", err)
+	}
+
+	return toWecomAppResult(app), nil
+}
+
+// GetApp 查询企业微信应用
+func (s *wecomAppApplicationService) GetApp(ctx context.Context, corpID, agentID string) (*WecomAppResult, error) {
+	app, err := getApp(ctx, s.repo, corpID, agentID)
+	if err != nil {
+		return nil, err
+	}
+	return toWecomAppResult(app), nil
+}
+
+// ListApps 列出企业微信应用。
+func (s *wecomAppApplicationService) ListApps(ctx context.Context, filter ListWecomAppsFilter) ([]*WecomAppResult, error) {
+	apps, err := s.repo.List(ctx, domain.ListFilter{
+		CorpID: filter.CorpID,
+		Status: filter.Status,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wecom apps: %w", err)
+	}
+
+	results := make([]*WecomAppResult, 0, len(apps))
+	for _, app := range apps {
+		results = append(results, toWecomAppResult(app))
+	}
+	return results, nil
+}
+
+// UpdateApp 更新企业微信应用基础信息。
+func (s *wecomAppApplicationService) UpdateApp(ctx context.Context, corpID, agentID string, dto UpdateWecomAppDTO) (*WecomAppResult, error) {
+	if dto.Name == nil {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "at least one field must be updated")
+	}
+	name := strings.TrimSpace(*dto.Name)
+	if name == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "name cannot be empty")
+	}
+
+	app, err := getApp(ctx, s.repo, corpID, agentID)
+	if err != nil {
+		return nil, err
+	}
+	app.Name = name
+
+	if err := s.repo.Update(ctx, app); err != nil {
+		return nil, fmt.Errorf("failed to update wecom app: %w", err)
+	}
+	return toWecomAppResult(app), nil
+}
+
+// EnableApp 启用企业微信应用。
+func (s *wecomAppApplicationService) EnableApp(ctx context.Context, corpID, agentID string) (*WecomAppResult, error) {
+	return s.changeAppStatus(ctx, corpID, agentID, domain.StatusEnabled)
+}
+
+// DisableApp 禁用企业微信应用。
+func (s *wecomAppApplicationService) DisableApp(ctx context.Context, corpID, agentID string) (*WecomAppResult, error) {
+	return s.changeAppStatus(ctx, corpID, agentID, domain.StatusDisabled)
+}
+
+func (s *wecomAppApplicationService) changeAppStatus(ctx context.Context, corpID, agentID string, status domain.Status) (*WecomAppResult, error) {
+	l := logger.L(ctx)
+	l.Debugw("切换企业微信应用状态",
+		"action", logger.ActionUpdate,
+		"resource", "wecom_app",
+		"corp_id", corpID,
+		"agent_id", agentID,
+		"status", status,
+	)
+
+	app, err := getApp(ctx, s.repo, corpID, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case domain.StatusEnabled:
+		app.Enable()
+	case domain.StatusDisabled:
+		app.Disable()
+	default:
+		return nil, perrors.WithCode(code.ErrWecomAppStatusInvalid, "unsupported app status: %s", status)
+	}
+
+	if err := s.repo.Update(ctx, app); err != nil {
+		return nil, fmt.Errorf("failed to update wecom app status: %w", err)
+	}
+	return toWecomAppResult(app), nil
+}
+
+// =========================================================
+// ==== WecomAppCredentialApplicationService 实现 =====
+// =========================================================
+
+type wecomAppCredentialApplicationService struct {
+	repo    domain.Repository
+	rotater domain.CredentialRotater
+}
+
+// NewWecomAppCredentialApplicationService 创建企业微信应用凭据应用服务
+func NewWecomAppCredentialApplicationService(
+	repo domain.Repository,
+	rotater domain.CredentialRotater,
+) WecomAppCredentialApplicationService {
+	return &wecomAppCredentialApplicationService{
+		repo:    repo,
+		rotater: rotater,
+	}
+}
+
+// RotateCorpSecret 轮换应用 Secret
+func (s *wecomAppCredentialApplicationService) RotateCorpSecret(ctx context.Context, corpID, agentID, newSecret string) error {
+	l := logger.L(ctx)
+
+	app, err := getApp(ctx, s.repo, corpID, agentID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.rotater.RotateCorpSecret(ctx, app, newSecret); err != nil {
+		l.Errorw("轮换企业微信应用 Secret 失败",
+			"action", logger.ActionUpdate,
+			"resource", "wecom_app_credential",
+			"corp_id", corpID,
+			"agent_id", agentID,
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return fmt.Errorf("failed to rotate corp secret: %w", err)
+	}
+
+	if err := s.repo.Update(ctx, app); err != nil {
+		return fmt.Errorf("failed to update wecom app: %w", err)
+	}
+	return nil
+}
+
+// ======================================================
+// ==== WecomAppTokenApplicationService 实现 =====
+// ======================================================
+
+type wecomAppTokenApplicationService struct {
+	repo          domain.Repository
+	tokenCacher   domain.AccessTokenCacher
+	tokenProvider domain.AppTokenProvider
+	cache         domain.AccessTokenCache
+}
+
+// NewWecomAppTokenApplicationService 创建企业微信应用访问令牌应用服务
+func NewWecomAppTokenApplicationService(
+	repo domain.Repository,
+	tokenCacher domain.AccessTokenCacher,
+	tokenProvider domain.AppTokenProvider,
+	cache domain.AccessTokenCache,
+) WecomAppTokenApplicationService {
+	return &wecomAppTokenApplicationService{
+		repo:          repo,
+		tokenCacher:   tokenCacher,
+		tokenProvider: tokenProvider,
+		cache:         cache,
+	}
+}
+
+// GetAccessToken 获取访问令牌（带缓存和自动刷新）
+func (s *wecomAppTokenApplicationService) GetAccessToken(ctx context.Context, corpID, agentID string) (string, error) {
+	app, err := getApp(ctx, s.repo, corpID, agentID)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := s.tokenCacher.EnsureToken(ctx, app, 120*time.Second)
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token: %w", err)
+	}
+	return token, nil
+}
+
+// RefreshAccessToken 强制刷新访问令牌
+func (s *wecomAppTokenApplicationService) RefreshAccessToken(ctx context.Context, corpID, agentID string) (string, error) {
+	app, err := getApp(ctx, s.repo, corpID, agentID)
+	if err != nil {
+		return "", err
+	}
+
+	aat, err := s.tokenProvider.Fetch(ctx, app)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch access token: %w", err)
+	}
+
+	ttl := time.Until(aat.ExpiresAt)
+	if ttl < 60*time.Second {
+		ttl = 60 * time.Second
+	}
+	if err := s.cache.Set(ctx, app.TokenCacheKey(), aat, ttl); err != nil {
+		return "", fmt.Errorf("failed to cache access token: %w", err)
+	}
+	return aat.Token, nil
+}
+
+// ============= 辅助函数 =============
+
+// getApp 按 CorpID + AgentID 查询企业微信应用，不存在时返回 ErrWecomAppNotFound
+func getApp(ctx context.Context, repo domain.Repository, corpID, agentID string) (*domain.WecomApp, error) {
+	app, err := repo.GetByCorpAgent(ctx, corpID, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wecom app: %w", err)
+	}
+	if app == nil {
+		return nil, perrors.WithCode(code.ErrWecomAppNotFound, "wecom app not found: %s/%s", corpID, agentID)
+	}
+	return app, nil
+}
+
+// toWecomAppResult 转换领域对象为结果 DTO
+func toWecomAppResult(app *domain.WecomApp) *WecomAppResult {
+	if app == nil {
+		return nil
+	}
+
+	return &WecomAppResult{
+		ID:      app.ID.String(),
+		CorpID:  app.CorpID,
+		AgentID: app.AgentID,
+		Name:    app.Name,
+		Status:  app.Status,
+	}
+}
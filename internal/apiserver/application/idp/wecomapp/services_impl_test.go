@@ -0,0 +1,73 @@
+package wecomapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/testhelpers"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/stretchr/testify/require"
+)
+
+type repoStub struct {
+	apps map[string]*domain.WecomApp
+}
+
+func (r *repoStub) Create(_ context.Context, app *domain.WecomApp) error {
+	r.apps[app.CorpID+"/"+app.AgentID] = app
+	return nil
+}
+
+func (r *repoStub) GetByID(context.Context, idutil.ID) (*domain.WecomApp, error) { return nil, nil }
+
+func (r *repoStub) GetByCorpAgent(_ context.Context, corpID, agentID string) (*domain.WecomApp, error) {
+	return r.apps[corpID+"/"+agentID], nil
+}
+
+func (r *repoStub) List(context.Context, domain.ListFilter) ([]*domain.WecomApp, error) {
+	return nil, nil
+}
+
+func (r *repoStub) Update(_ context.Context, app *domain.WecomApp) error {
+	r.apps[app.CorpID+"/"+app.AgentID] = app
+	return nil
+}
+
+func TestWecomAppServices_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := &repoStub{apps: map[string]*domain.WecomApp{}}
+	rotater := domain.NewCredentialRotater(&testhelpers.VaultStub{}, time.Now)
+	appSvc := NewWecomAppApplicationService(repo, domain.NewCreator(repo), rotater)
+	credSvc := NewWecomAppCredentialApplicationService(repo, rotater)
+
+	created, err := appSvc.CreateApp(ctx, CreateWecomAppDTO{
+		CorpID:     "ww-corp",
+		AgentID:    "1000002",
+		Name:       "OA",
+		CorpSecret: "corp-secret-value-0001",
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusEnabled, created.Status)
+
+	stored := repo.apps["ww-corp/1000002"]
+	require.NotNil(t, stored.Cred.Auth)
+	require.Equal(t, []byte("cipher:corp-secret-value-0001"), stored.Cred.Auth.SecretCipher)
+
+	require.NoError(t, credSvc.RotateCorpSecret(ctx, "ww-corp", "1000002", "corp-secret-value-0002"))
+	require.Equal(t, 2, repo.apps["ww-corp/1000002"].Cred.Auth.Version)
+
+	disabled, err := appSvc.DisableApp(ctx, "ww-corp", "1000002")
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusDisabled, disabled.Status)
+
+	_, err = appSvc.GetApp(ctx, "ww-corp", "9999")
+	require.Error(t, err)
+	require.True(t, perrors.IsCode(err, code.ErrWecomAppNotFound))
+
+	err = credSvc.RotateCorpSecret(ctx, "ww-corp", "9999", "corp-secret-value-0003")
+	require.True(t, perrors.IsCode(err, code.ErrWecomAppNotFound))
+}
@@ -17,6 +17,7 @@ type Config struct {
 	FullSQL       string
 	DeltaSQL      string
 	Snapshot      bool
+	SnapshotKeep  int
 }
 
 // LoadConfig 从 viper 读取配置
@@ -48,6 +49,7 @@ func LoadConfig() Config {
 	cfg.FullSQL = sub.GetString("full_sql")
 	cfg.DeltaSQL = sub.GetString("delta_sql")
 	cfg.Snapshot = sub.GetBool("snapshot")
+	cfg.SnapshotKeep = sub.GetInt("snapshot_keep")
 	if cfg.DataDir != "" && !sub.IsSet("snapshot") {
 		cfg.Snapshot = true
 	}
@@ -58,9 +60,10 @@ func LoadConfig() Config {
 // ToUpdaterConfig 转换为 Updater 配置
 func (c Config) ToUpdaterConfig() UpdaterConfig {
 	return UpdaterConfig{
-		FullCron:  c.FullSyncCron,
-		DeltaCron: c.DeltaSyncCron,
-		DataDir:   c.DataDir,
-		Snapshot:  c.Snapshot,
+		FullCron:     c.FullSyncCron,
+		DeltaCron:    c.DeltaSyncCron,
+		DataDir:      c.DataDir,
+		Snapshot:     c.Snapshot,
+		SnapshotKeep: c.SnapshotKeep,
 	}
 }
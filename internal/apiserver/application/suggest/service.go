@@ -2,32 +2,93 @@ package suggest
 
 import (
 	"context"
+	"strconv"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
 
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/suggest"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/infra/suggest/search"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+const (
+	// ChildResource 儿童档案资源键
+	ChildResource = "iam:children"
+	// ChildSearchAction 租户范围内检索儿童档案的动作
+	ChildSearchAction = "search"
 )
 
+// Authorizer 权限判定端口（由 Casbin 适配器实现）
+type Authorizer interface {
+	Enforce(ctx context.Context, sub, dom, obj, act string) (bool, error)
+}
+
+// Caller 查询发起人
+type Caller struct {
+	UserID   string
+	TenantID string
+}
+
 // Service 提供 suggest 查询
 type Service struct {
-	cfg Config
+	cfg        Config
+	authorizer Authorizer
+}
+
+// ServiceOption Service 选项
+type ServiceOption func(*Service)
+
+// WithAuthorizer 设置权限判定器；未设置时所有调用方只能检索自己监护的儿童
+func WithAuthorizer(a Authorizer) ServiceOption {
+	return func(s *Service) { s.authorizer = a }
 }
 
 // NewService 创建 Service
-func NewService(cfg Config) *Service {
+func NewService(cfg Config, opts ...ServiceOption) *Service {
 	if cfg.MaxResults == 0 {
 		cfg.MaxResults = 20
 	}
 	if cfg.KeyPadLen == 0 {
 		cfg.KeyPadLen = 25
 	}
-	return &Service{cfg: cfg}
+	s := &Service{cfg: cfg}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// Suggest 查询
-func (s *Service) Suggest(_ context.Context, keyword string) []suggest.Term {
+// Suggest 在调用方租户内查询
+//
+// 拥有 iam:children#search 权限的调用方可检索整个租户，其余调用方仅能检索自己监护的儿童。
+func (s *Service) Suggest(ctx context.Context, caller Caller, keyword string) ([]suggest.Term, error) {
+	scope, err := s.resolveScope(ctx, caller)
+	if err != nil {
+		return nil, err
+	}
 	store := search.Current()
 	if store == nil {
-		return nil
+		return nil, nil
+	}
+	return store.Suggest(scope, keyword, s.cfg.MaxResults, s.cfg.KeyPadLen), nil
+}
+
+// resolveScope 根据调用方身份与授权确定查询范围
+func (s *Service) resolveScope(ctx context.Context, caller Caller) (suggest.Scope, error) {
+	userID, err := strconv.ParseInt(caller.UserID, 10, 64)
+	if err != nil || userID <= 0 {
+		return suggest.Scope{}, errors.WithCode(code.ErrUnauthorized, "invalid caller")
+	}
+	scope := suggest.Scope{TenantID: caller.TenantID, GuardianID: userID}
+	if s.authorizer == nil {
+		return scope, nil
+	}
+	allowed, err := s.authorizer.Enforce(ctx, "user:"+caller.UserID, caller.TenantID, ChildResource, ChildSearchAction)
+	if err != nil {
+		return suggest.Scope{}, errors.WrapC(err, code.ErrInternalServerError, "authorization check failed")
+	}
+	if allowed {
+		scope.GuardianID = 0
 	}
-	return store.Suggest(keyword, s.cfg.MaxResults, s.cfg.KeyPadLen)
+	return scope, nil
 }
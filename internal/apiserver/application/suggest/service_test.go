@@ -0,0 +1,59 @@
+package suggest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/infra/suggest/search"
+)
+
+type authorizerStub struct {
+	allowed bool
+	err     error
+	calls   []string
+}
+
+func (a *authorizerStub) Enforce(_ context.Context, sub, dom, obj, act string) (bool, error) {
+	a.calls = append(a.calls, sub+"|"+dom+"|"+obj+"|"+act)
+	return a.allowed, a.err
+}
+
+func TestServiceSuggestScopesByTenantAndGuardianship(t *testing.T) {
+	search.Swap(search.Load([]string{
+		"张三|1|13800138000|tenant-a|5|100",
+		"张四|2|13800138001|tenant-a|5|200",
+		"张五|3|13800138002|tenant-b|5|100",
+	}))
+	t.Cleanup(func() { search.Swap(search.Load(nil)) })
+
+	// 无检索权限：仅返回自己监护的儿童
+	denied := &authorizerStub{}
+	svc := NewService(Config{}, WithAuthorizer(denied))
+	out, err := svc.Suggest(context.Background(), Caller{UserID: "100", TenantID: "tenant-a"}, "zhang")
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	assert.Equal(t, int64(1), out[0].ID)
+	assert.Equal(t, []string{"user:100|tenant-a|iam:children|search"}, denied.calls)
+
+	// 有检索权限：整个租户可见，但不跨租户
+	svc = NewService(Config{}, WithAuthorizer(&authorizerStub{allowed: true}))
+	out, err = svc.Suggest(context.Background(), Caller{UserID: "100", TenantID: "tenant-a"}, "zhang")
+	require.NoError(t, err)
+	assert.Len(t, out, 2)
+	for _, term := range out {
+		assert.Equal(t, "tenant-a", term.TenantID)
+	}
+}
+
+func TestServiceSuggestFailsClosed(t *testing.T) {
+	svc := NewService(Config{}, WithAuthorizer(&authorizerStub{err: errors.New("casbin down")}))
+	_, err := svc.Suggest(context.Background(), Caller{UserID: "100", TenantID: "tenant-a"}, "zhang")
+	assert.Error(t, err)
+
+	_, err = NewService(Config{}).Suggest(context.Background(), Caller{TenantID: "tenant-a"}, "zhang")
+	assert.Error(t, err)
+}
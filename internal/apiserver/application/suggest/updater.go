@@ -1,11 +1,15 @@
 package suggest
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/robfig/cron/v3"
@@ -20,6 +24,13 @@ type Loader interface {
 	Delta(ctx context.Context, since time.Time) ([]string, error)
 }
 
+// 快照文件命名：snapshot-<水位线 UnixNano>.txt，版本号即数据截止时间
+const (
+	snapshotPrefix      = "snapshot-"
+	snapshotSuffix      = ".txt"
+	defaultSnapshotKeep = 3
+)
+
 // Updater 定期刷新内存搜索引擎
 type Updater struct {
 	loader        Loader
@@ -29,14 +40,17 @@ type Updater struct {
 	lastFetch     time.Time
 	cron          *cron.Cron
 	writeSnapshot bool
+	snapshotKeep  int
+	mu            sync.Mutex // 串行化全量与增量刷新，避免增量写入即将被替换的旧 Store
 }
 
 // UpdaterConfig 配置刷新策略
 type UpdaterConfig struct {
-	FullCron  string
-	DeltaCron string
-	DataDir   string
-	Snapshot  bool
+	FullCron     string
+	DeltaCron    string
+	DataDir      string
+	Snapshot     bool
+	SnapshotKeep int
 }
 
 // NewUpdater 创建 Updater
@@ -45,12 +59,17 @@ func NewUpdater(loader Loader, cfg UpdaterConfig) *Updater {
 	if full == "" {
 		full = "@every 1h"
 	}
+	keep := cfg.SnapshotKeep
+	if keep <= 0 {
+		keep = defaultSnapshotKeep
+	}
 	return &Updater{
 		loader:        loader,
 		fullSpec:      full,
 		deltaSpec:     cfg.DeltaCron,
 		dataDir:       cfg.DataDir,
 		writeSnapshot: cfg.Snapshot,
+		snapshotKeep:  keep,
 	}
 }
 
@@ -60,7 +79,7 @@ func (u *Updater) Start(ctx context.Context) error {
 		return fmt.Errorf("suggest updater missing loader")
 	}
 
-	if err := u.runFull(ctx); err != nil {
+	if err := u.bootstrap(ctx); err != nil {
 		return err
 	}
 
@@ -103,52 +122,158 @@ func (u *Updater) Stop() {
 	<-ctx.Done()
 }
 
+// bootstrap 启动时优先从最新快照热启动并用增量追平，失败或无快照时回退为全量
+func (u *Updater) bootstrap(ctx context.Context) error {
+	if u.deltaSpec != "" && u.warmStart() {
+		err := u.runDelta(ctx)
+		if err == nil {
+			return nil
+		}
+		log.Warnw("suggest catch-up after warm start failed, fallback to full sync", "error", err)
+	}
+	return u.runFull(ctx)
+}
+
+// warmStart 加载最新版本快照并设置水位线
+func (u *Updater) warmStart() bool {
+	if u.dataDir == "" {
+		return false
+	}
+	versions := listSnapshots(u.dataDir)
+	for i := len(versions) - 1; i >= 0; i-- {
+		file := snapshotFile(u.dataDir, versions[i])
+		lines, err := readLines(file)
+		if err != nil {
+			log.Warnw("suggest read snapshot failed", "error", err, "file", file)
+			continue
+		}
+		store := search.Load(lines)
+		search.Swap(store)
+		u.mu.Lock()
+		u.lastFetch = time.Unix(0, versions[i])
+		u.mu.Unlock()
+		log.Infow("suggest warm started from snapshot", "file", file, "count", len(lines), "watermark", u.lastFetch)
+		return true
+	}
+	return false
+}
+
 func (u *Updater) runFull(ctx context.Context) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	// 水位线取查询开始时间，查询期间的变更留给下一次增量（增量按 ID 幂等覆盖）
+	fetchStart := time.Now()
 	lines, err := u.loader.Full(ctx)
 	if err != nil {
 		return err
 	}
-	search.Swap(search.Load(lines))
-	u.lastFetch = time.Now()
-	u.persist(lines)
+	store := search.Load(lines)
+	search.Swap(store)
+	u.lastFetch = fetchStart
+	u.persist(store)
 	log.Infow("suggest full sync completed", "count", len(lines))
 	return nil
 }
 
 func (u *Updater) runDelta(ctx context.Context) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
 	if u.lastFetch.IsZero() {
 		return nil
 	}
+	fetchStart := time.Now()
 	lines, err := u.loader.Delta(ctx, u.lastFetch)
 	if err != nil {
 		return err
 	}
-	if len(lines) == 0 {
-		return nil
-	}
 	store := search.Current()
 	if store == nil {
 		return fmt.Errorf("suggest store not initialized")
 	}
+	u.lastFetch = fetchStart
+	if len(lines) == 0 {
+		return nil
+	}
 	store.ImportLines(lines)
-	u.lastFetch = time.Now()
-	u.persist(lines)
+	u.persist(store)
 	log.Infow("suggest delta sync completed", "count", len(lines))
 	return nil
 }
 
-func (u *Updater) persist(lines []string) {
-	if !u.writeSnapshot || len(lines) == 0 || u.dataDir == "" {
+// persist 以当前水位线为版本写入完整快照，先写临时文件再重命名，并清理过旧版本
+func (u *Updater) persist(store *search.Store) {
+	if !u.writeSnapshot || store == nil || u.dataDir == "" {
 		return
 	}
 	if err := os.MkdirAll(u.dataDir, 0o755); err != nil {
 		log.Warnw("suggest persist mkdir failed", "error", err, "dir", u.dataDir)
 		return
 	}
-	file := filepath.Join(u.dataDir, "snapshot.txt")
-	if err := os.WriteFile(file, []byte(joinLines(lines)), 0o644); err != nil {
+	version := u.lastFetch.UnixNano()
+	file := snapshotFile(u.dataDir, version)
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, []byte(joinLines(store.Lines())), 0o644); err != nil {
+		log.Warnw("suggest persist snapshot failed", "error", err, "file", tmp)
+		return
+	}
+	if err := os.Rename(tmp, file); err != nil {
 		log.Warnw("suggest persist snapshot failed", "error", err, "file", file)
+		_ = os.Remove(tmp)
+		return
+	}
+
+	versions := listSnapshots(u.dataDir)
+	for len(versions) > u.snapshotKeep {
+		if err := os.Remove(snapshotFile(u.dataDir, versions[0])); err != nil {
+			log.Warnw("suggest prune snapshot failed", "error", err, "version", versions[0])
+		}
+		versions = versions[1:]
+	}
+}
+
+// listSnapshots 返回目录内快照版本，按升序排列
+func listSnapshots(dir string) []int64 {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var versions []int64
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, snapshotPrefix) || !strings.HasSuffix(name, snapshotSuffix) {
+			continue
+		}
+		v, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(name, snapshotPrefix), snapshotSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions
+}
+
+func snapshotFile(dir string, version int64) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%d%s", snapshotPrefix, version, snapshotSuffix))
+}
+
+func readLines(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
 	}
+	return lines, scanner.Err()
 }
 
 func joinLines(lines []string) string {
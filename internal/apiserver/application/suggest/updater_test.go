@@ -0,0 +1,82 @@
+package suggest
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/infra/suggest/search"
+)
+
+type fakeLoader struct {
+	full      []string
+	delta     []string
+	fullCalls int
+	since     []time.Time
+}
+
+func (f *fakeLoader) Full(context.Context) ([]string, error) {
+	f.fullCalls++
+	return f.full, nil
+}
+
+func (f *fakeLoader) Delta(_ context.Context, since time.Time) ([]string, error) {
+	f.since = append(f.since, since)
+	return f.delta, nil
+}
+
+func TestUpdaterWarmStartsFromSnapshotAndCatchesUp(t *testing.T) {
+	dir := t.TempDir()
+	cfg := UpdaterConfig{DeltaCron: "@every 5m", DataDir: dir, Snapshot: true}
+	t.Cleanup(func() { search.Swap(search.Load(nil)) })
+
+	first := &fakeLoader{full: []string{"张三|1|13800138000|-|5", "李四|2|13900139000|-|5"}}
+	u := NewUpdater(first, cfg)
+	if err := u.bootstrap(context.Background()); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+	if first.fullCalls != 1 {
+		t.Fatalf("expected full sync without snapshot, got %d", first.fullCalls)
+	}
+	versions := listSnapshots(dir)
+	if len(versions) != 1 {
+		t.Fatalf("expected one snapshot, got %v", versions)
+	}
+
+	// 重启：从快照热启动，只以快照版本为水位线拉增量，墓碑行移除已删除儿童
+	restarted := &fakeLoader{delta: []string{"张三|1||-|0||1"}}
+	u2 := NewUpdater(restarted, cfg)
+	if err := u2.bootstrap(context.Background()); err != nil {
+		t.Fatalf("bootstrap after restart: %v", err)
+	}
+	if restarted.fullCalls != 0 {
+		t.Fatalf("expected no full sync on warm start, got %d", restarted.fullCalls)
+	}
+	if len(restarted.since) != 1 || restarted.since[0].UnixNano() != versions[0] {
+		t.Fatalf("expected catch-up from snapshot watermark, got %v", restarted.since)
+	}
+	lines := search.Current().Lines()
+	if len(lines) != 1 {
+		t.Fatalf("expected tombstone applied, got %v", lines)
+	}
+}
+
+func TestUpdaterPrunesOldSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	loader := &fakeLoader{full: []string{"张三|1|13800138000|-|5"}}
+	u := NewUpdater(loader, UpdaterConfig{DataDir: dir, Snapshot: true, SnapshotKeep: 2})
+	for i := 0; i < 4; i++ {
+		if err := u.runFull(context.Background()); err != nil {
+			t.Fatalf("runFull: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if versions := listSnapshots(dir); len(versions) != 2 {
+		t.Fatalf("expected 2 snapshots kept, got %v", versions)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Fatalf("expected no leftover temp files, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,89 @@
+// Package merge 重复用户合并应用服务
+//
+// 手机号注册、微信注册、运营导入各自以不同键调用 createOrGetUser，
+// 会为同一位家长产生多条 users 记录。合并操作将源用户名下的账号（连同凭据）、
+// 监护关系与授权赋权整体迁移到目标用户，并停用源用户。
+package merge
+
+import (
+	"context"
+	"time"
+)
+
+// UserMergeApplicationService 用户合并应用服务（管理员操作）
+type UserMergeApplicationService interface {
+	// Merge 将源用户合并到目标用户；DryRun 时仅返回迁移计划，不写库
+	Merge(ctx context.Context, cmd MergeCommand) (*MergeReport, error)
+}
+
+// ============= DTOs =============
+
+// MergeCommand 合并命令
+type MergeCommand struct {
+	SourceUserID string // 被合并（将停用）的用户
+	TargetUserID string // 保留的用户
+	DryRun       bool   // 仅预览
+	Reason       string // 合并原因，写入审计
+	Operator     string // 操作人
+}
+
+// 迁移动作
+const (
+	ActionMove   = "move"   // 改挂到目标用户
+	ActionRevoke = "revoke" // 目标用户已有等价记录，撤销源记录
+	ActionDrop   = "drop"   // 目标用户已有等价记录，源记录为历史数据，保持不动
+)
+
+// MergeReport 合并结果（DryRun 时为计划）
+type MergeReport struct {
+	SourceUserID  string
+	TargetUserID  string
+	DryRun        bool
+	Accounts      []AccountMove
+	Guardianships []GuardianshipMove
+	Assignments   []AssignmentMove
+}
+
+// AccountMove 账号迁移项
+type AccountMove struct {
+	AccountID   string
+	Type        string
+	ExternalID  string
+	Credentials int // 随账号一并迁移的凭据数
+}
+
+// GuardianshipMove 监护关系迁移项
+type GuardianshipMove struct {
+	GuardianshipID string
+	ChildID        string
+	Relation       string
+	Active         bool
+	Action         string
+}
+
+// AssignmentMove 授权赋权迁移项
+type AssignmentMove struct {
+	AssignmentID string
+	TenantID     string
+	RoleID       uint64
+	Action       string
+}
+
+// ============= 审计 =============
+
+// AuditEntry 记录一次用户合并。
+type AuditEntry struct {
+	SourceUserID string
+	TargetUserID string
+	Operator     string
+	Reason       string
+	DryRun       bool
+	Report       *MergeReport
+	Err          error
+	At           time.Time
+}
+
+// Auditor 负责持久化或输出用户合并审计记录。
+type Auditor interface {
+	Record(ctx context.Context, entry AuditEntry)
+}
@@ -0,0 +1,449 @@
+package merge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/log"
+	"github.com/FangcunMount/component-base/pkg/logger"
+	authzshared "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/shared"
+	sessiondomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/session"
+	assignmentDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
+	policyDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/uc/guardianship"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/uc/user"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// 合并后撤销源用户会话时使用的原因
+const sessionRevokeReason = "user_merged"
+
+// logAuditor 默认审计实现：写入结构化日志。
+type logAuditor struct{}
+
+func (logAuditor) Record(ctx context.Context, entry AuditEntry) {
+	fields := []interface{}{
+		"action", "user_merge",
+		"source_user_id", entry.SourceUserID,
+		"target_user_id", entry.TargetUserID,
+		"operator", entry.Operator,
+		"reason", entry.Reason,
+		"dry_run", entry.DryRun,
+	}
+	if entry.Report != nil {
+		fields = append(fields,
+			"accounts", len(entry.Report.Accounts),
+			"guardianships", len(entry.Report.Guardianships),
+			"assignments", len(entry.Report.Assignments),
+		)
+	}
+	if entry.Err != nil {
+		log.Warnw("user merge failed", append(fields, "error", entry.Err)...)
+		return
+	}
+	log.Infow("user merge", fields...)
+}
+
+// Option 用户合并服务选项
+type Option func(*userMergeApplicationService)
+
+// WithAuditor 替换默认的日志审计实现。
+func WithAuditor(auditor Auditor) Option {
+	return func(s *userMergeApplicationService) {
+		if auditor != nil {
+			s.auditor = auditor
+		}
+	}
+}
+
+type userMergeApplicationService struct {
+	uow             UnitOfWork
+	sessionManager  sessiondomain.Manager
+	casbinAdapter   policyDomain.CasbinAdapter
+	versionNotifier policyDomain.VersionNotifier
+	auditor         Auditor
+	now             func() time.Time
+}
+
+var _ UserMergeApplicationService = (*userMergeApplicationService)(nil)
+
+// NewUserMergeApplicationService 创建用户合并应用服务。
+// sessionManager、casbinAdapter、versionNotifier 均可为 nil，此时跳过对应的提交后动作。
+func NewUserMergeApplicationService(
+	uow UnitOfWork,
+	sessionManager sessiondomain.Manager,
+	casbinAdapter policyDomain.CasbinAdapter,
+	versionNotifier policyDomain.VersionNotifier,
+	opts ...Option,
+) UserMergeApplicationService {
+	s := &userMergeApplicationService{
+		uow:             uow,
+		sessionManager:  sessionManager,
+		casbinAdapter:   casbinAdapter,
+		versionNotifier: versionNotifier,
+		auditor:         logAuditor{},
+		now:             time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Merge 将源用户合并到目标用户
+func (s *userMergeApplicationService) Merge(ctx context.Context, cmd MergeCommand) (report *MergeReport, err error) {
+	l := logger.L(ctx)
+	start := s.now()
+	defer func() {
+		s.auditor.Record(ctx, AuditEntry{
+			SourceUserID: cmd.SourceUserID,
+			TargetUserID: cmd.TargetUserID,
+			Operator:     cmd.Operator,
+			Reason:       cmd.Reason,
+			DryRun:       cmd.DryRun,
+			Report:       report,
+			Err:          err,
+			At:           start,
+		})
+	}()
+
+	sourceID, err := parseUserID(cmd.SourceUserID)
+	if err != nil {
+		return nil, err
+	}
+	targetID, err := parseUserID(cmd.TargetUserID)
+	if err != nil {
+		return nil, err
+	}
+	if sourceID == targetID {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "source and target user must differ")
+	}
+
+	l.Debugw("合并用户",
+		"action", logger.ActionUpdate,
+		"resource", logger.ResourceUser,
+		"source_user_id", cmd.SourceUserID,
+		"target_user_id", cmd.TargetUserID,
+		"dry_run", cmd.DryRun,
+	)
+
+	var versions map[string]tenantVersion
+	err = s.uow.WithinTx(ctx, func(tx TxRepositories) error {
+		source, err := findUser(ctx, tx.Users, sourceID)
+		if err != nil {
+			return err
+		}
+		target, err := findUser(ctx, tx.Users, targetID)
+		if err != nil {
+			return err
+		}
+		if target.IsBlocked() {
+			return perrors.WithCode(code.ErrUserBlocked, "target user(%s) is blocked", cmd.TargetUserID)
+		}
+
+		p, err := s.plan(ctx, tx, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		report = p.report(cmd)
+		if cmd.DryRun {
+			return nil
+		}
+
+		if versions, err = s.apply(ctx, tx, p, targetID, cmd.Operator); err != nil {
+			return err
+		}
+
+		source.Deactivate()
+		return tx.Users.Update(ctx, source)
+	})
+	if err != nil {
+		l.Warnw("合并用户失败",
+			"action", logger.ActionUpdate,
+			"resource", logger.ResourceUser,
+			"source_user_id", cmd.SourceUserID,
+			"target_user_id", cmd.TargetUserID,
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+		return nil, err
+	}
+	if cmd.DryRun {
+		return report, nil
+	}
+
+	s.afterCommit(ctx, sourceID, cmd.Operator, versions)
+
+	l.Infow("合并用户成功",
+		"action", logger.ActionUpdate,
+		"resource", logger.ResourceUser,
+		"source_user_id", cmd.SourceUserID,
+		"target_user_id", cmd.TargetUserID,
+		"result", logger.ResultSuccess,
+	)
+	return report, nil
+}
+
+// mergePlan 事务内计算出的迁移计划
+type mergePlan struct {
+	accounts      []AccountMove
+	accountIDs    []meta.ID
+	guardianships []guardianshipStep
+	assignments   []assignmentStep
+}
+
+type guardianshipStep struct {
+	g      *guardianship.Guardianship
+	action string
+}
+
+type assignmentStep struct {
+	a      *assignmentDomain.Assignment
+	action string
+}
+
+type tenantVersion struct {
+	version *policyDomain.PolicyVersion
+	queued  bool
+}
+
+func (s *userMergeApplicationService) plan(ctx context.Context, tx TxRepositories, sourceID, targetID meta.ID) (*mergePlan, error) {
+	p := &mergePlan{}
+
+	// 账号：凭据挂在账号下，随账号一起迁移
+	accounts, err := tx.Accounts.ListByUserID(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, acc := range accounts {
+		creds, err := tx.Credentials.ListByAccountID(ctx, acc.ID)
+		if err != nil {
+			return nil, err
+		}
+		p.accountIDs = append(p.accountIDs, acc.ID)
+		p.accounts = append(p.accounts, AccountMove{
+			AccountID:   acc.ID.String(),
+			Type:        string(acc.Type),
+			ExternalID:  string(acc.ExternalID),
+			Credentials: len(creds),
+		})
+	}
+
+	// 监护关系：(user_id, child_id) 唯一，目标用户已有同一儿童的记录时不能直接改挂
+	guardianships, err := tx.Guardianships.FindByUserIDIncludingRevoked(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range guardianships {
+		existing, err := tx.Guardianships.FindByUserIDAndChildIDIncludingRevoked(ctx, targetID, g.Child)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		switch {
+		case existing == nil:
+			p.guardianships = append(p.guardianships, guardianshipStep{g: g, action: ActionMove})
+		case !g.IsActive():
+			p.guardianships = append(p.guardianships, guardianshipStep{g: g, action: ActionDrop})
+		case existing.IsActive():
+			p.guardianships = append(p.guardianships, guardianshipStep{g: g, action: ActionRevoke})
+		default:
+			// 目标用户对该儿童的监护已被撤销而源用户仍有效，需人工确认后再合并
+			return nil, perrors.WithCode(code.ErrUserMergeConflict,
+				"target user has revoked guardianship(%s) for child(%s) that source still holds", existing.ID.String(), g.Child.String())
+		}
+	}
+
+	// 授权赋权：按租户比对，目标用户生效中的同角色赋权覆盖源赋权有效期时仅撤销源赋权
+	assignments, err := tx.Authz.Assignments.ListBySubjectAcrossTenants(ctx, assignmentDomain.SubjectTypeUser, sourceID.String())
+	if err != nil {
+		return nil, err
+	}
+	targetRoles := make(map[string]map[uint64][]*assignmentDomain.Assignment)
+	for _, a := range assignments {
+		roles, ok := targetRoles[a.TenantID]
+		if !ok {
+			held, err := tx.Authz.Assignments.ListBySubject(ctx, assignmentDomain.SubjectTypeUser, targetID.String(), a.TenantID)
+			if err != nil {
+				return nil, err
+			}
+			roles = make(map[uint64][]*assignmentDomain.Assignment, len(held))
+			for _, h := range held {
+				if h.Effective() {
+					roles[h.RoleID] = append(roles[h.RoleID], h)
+				}
+			}
+			targetRoles[a.TenantID] = roles
+		}
+		action := ActionMove
+		if coveredByAny(a, roles[a.RoleID]) {
+			action = ActionRevoke
+		}
+		p.assignments = append(p.assignments, assignmentStep{a: a, action: action})
+	}
+
+	return p, nil
+}
+
+// coveredByAny 目标用户生效中的赋权是否覆盖源赋权剩余的有效期
+// 已过期的源赋权作为历史记录迁移；生效中的赋权起点已过，只需比较失效时间
+func coveredByAny(a *assignmentDomain.Assignment, held []*assignmentDomain.Assignment) bool {
+	if a.Status == assignmentDomain.StatusExpired {
+		return false
+	}
+	for _, h := range held {
+		if h.ExpiresAt == nil || (a.ExpiresAt != nil && !a.ExpiresAt.After(*h.ExpiresAt)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *userMergeApplicationService) apply(ctx context.Context, tx TxRepositories, p *mergePlan, targetID meta.ID, operator string) (map[string]tenantVersion, error) {
+	for _, id := range p.accountIDs {
+		if err := tx.Accounts.UpdateUserID(ctx, id, targetID); err != nil {
+			return nil, fmt.Errorf("move account(%s): %w", id.String(), err)
+		}
+	}
+
+	now := s.now()
+	for _, step := range p.guardianships {
+		switch step.action {
+		case ActionMove:
+			step.g.User = targetID
+		case ActionRevoke:
+			step.g.Revoke(now)
+		default:
+			continue
+		}
+		if err := tx.Guardianships.Update(ctx, step.g); err != nil {
+			return nil, fmt.Errorf("update guardianship(%s): %w", step.g.ID.String(), err)
+		}
+	}
+
+	touched := make(map[string]struct{})
+	for _, step := range p.assignments {
+		a := step.a
+		if err := tx.Authz.Assignments.Delete(ctx, a.ID); err != nil {
+			return nil, fmt.Errorf("delete assignment(%s): %w", a.ID.String(), err)
+		}
+		if err := tx.Authz.RuleStore.RemoveGroupingPolicy(ctx, policyDomain.GroupingRule{
+			Sub: a.SubjectKey(), Role: a.RoleKey(), Dom: a.TenantID,
+		}); err != nil {
+			return nil, fmt.Errorf("remove grouping policy: %w", err)
+		}
+		if step.action == ActionMove {
+			moved := assignmentDomain.NewAssignment(
+				assignmentDomain.SubjectTypeUser,
+				targetID.String(),
+				a.RoleID,
+				a.TenantID,
+				assignmentDomain.WithGrantedBy(a.GrantedBy),
+				assignmentDomain.WithValidity(a.NotBefore, a.ExpiresAt),
+				assignmentDomain.WithStatus(a.Status),
+			)
+			if err := tx.Authz.Assignments.Create(ctx, &moved); err != nil {
+				return nil, fmt.Errorf("create assignment: %w", err)
+			}
+			if moved.Effective() {
+				if err := tx.Authz.RuleStore.AddGroupingPolicy(ctx, policyDomain.GroupingRule{
+					Sub: moved.SubjectKey(), Role: moved.RoleKey(), Dom: moved.TenantID,
+				}); err != nil {
+					return nil, fmt.Errorf("add grouping policy: %w", err)
+				}
+			}
+		}
+		touched[a.TenantID] = struct{}{}
+	}
+
+	versions := make(map[string]tenantVersion, len(touched))
+	for tenantID := range touched {
+		version, queued, err := authzshared.BumpVersion(ctx, tx.Authz, tenantID, operator, "user merge")
+		if err != nil {
+			return nil, fmt.Errorf("bump authz version: %w", err)
+		}
+		versions[tenantID] = tenantVersion{version: version, queued: queued}
+	}
+	return versions, nil
+}
+
+// afterCommit 事务提交后的尽力而为动作：通知授权版本、刷新运行时策略、撤销源用户会话
+func (s *userMergeApplicationService) afterCommit(ctx context.Context, sourceID meta.ID, operator string, versions map[string]tenantVersion) {
+	for tenantID, v := range versions {
+		if v.queued || s.versionNotifier == nil || v.version == nil {
+			continue
+		}
+		if err := s.versionNotifier.Publish(ctx, tenantID, v.version.Version); err != nil {
+			log.Errorw("failed to publish authz version after user merge", "tenant_id", tenantID, "version", v.version.Version, "error", err)
+		}
+	}
+	if len(versions) > 0 {
+		authzshared.ReloadRuntimePolicy(ctx, s.casbinAdapter, "user merge")
+	}
+
+	if s.sessionManager == nil {
+		return
+	}
+	if err := s.sessionManager.RevokeByUser(ctx, sourceID, sessionRevokeReason, operator); err != nil {
+		logger.L(ctx).Warnw("撤销源用户会话失败",
+			"action", logger.ActionRevoke,
+			"resource", "session",
+			"user_id", sourceID.String(),
+			"error", err.Error(),
+			"result", logger.ResultFailed,
+		)
+	}
+}
+
+func (p *mergePlan) report(cmd MergeCommand) *MergeReport {
+	r := &MergeReport{
+		SourceUserID:  cmd.SourceUserID,
+		TargetUserID:  cmd.TargetUserID,
+		DryRun:        cmd.DryRun,
+		Accounts:      p.accounts,
+		Guardianships: make([]GuardianshipMove, 0, len(p.guardianships)),
+		Assignments:   make([]AssignmentMove, 0, len(p.assignments)),
+	}
+	for _, step := range p.guardianships {
+		r.Guardianships = append(r.Guardianships, GuardianshipMove{
+			GuardianshipID: step.g.ID.String(),
+			ChildID:        step.g.Child.String(),
+			Relation:       string(step.g.Rel),
+			Active:         step.g.IsActive(),
+			Action:         step.action,
+		})
+	}
+	for _, step := range p.assignments {
+		r.Assignments = append(r.Assignments, AssignmentMove{
+			AssignmentID: step.a.ID.String(),
+			TenantID:     step.a.TenantID,
+			RoleID:       step.a.RoleID,
+			Action:       step.action,
+		})
+	}
+	return r
+}
+
+func findUser(ctx context.Context, repo user.Repository, id meta.ID) (*user.User, error) {
+	u, err := repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, perrors.WithCode(code.ErrUserNotFound, "user(%s) not found", id.String())
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+func parseUserID(raw string) (meta.ID, error) {
+	var id uint64
+	if _, err := fmt.Sscanf(raw, "%d", &id); err != nil || id == 0 {
+		return meta.FromUint64(0), perrors.WithCode(code.ErrInvalidArgument, "invalid user id: %s", raw)
+	}
+	return meta.FromUint64(id), nil
+}
@@ -0,0 +1,293 @@
+package merge_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/uc/merge"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/uc/testutil"
+	accountdomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
+	sessiondomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/session"
+	assignmentdomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/uc/guardianship"
+	userdomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/uc/user"
+	acctrepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/account"
+	assignmentrepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/assignment"
+	credentialrepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/credential"
+	guardrepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/guardianship"
+	policyrepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/policy"
+	userrepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/user"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+type sessionStub struct {
+	sessiondomain.Manager
+	revokedUsers []meta.ID
+}
+
+func (s *sessionStub) RevokeByUser(_ context.Context, userID meta.ID, _ string, _ string) error {
+	s.revokedUsers = append(s.revokedUsers, userID)
+	return nil
+}
+
+var _ sessiondomain.Manager = (*sessionStub)(nil)
+
+type auditorStub struct {
+	entries []merge.AuditEntry
+}
+
+func (a *auditorStub) Record(_ context.Context, entry merge.AuditEntry) {
+	a.entries = append(a.entries, entry)
+}
+
+type fixture struct {
+	db       *gorm.DB
+	source   *userdomain.User
+	target   *userdomain.User
+	account  *accountdomain.Account
+	moveG    *guardianship.Guardianship
+	dupG     *guardianship.Guardianship
+	sessions *sessionStub
+	auditor  *auditorStub
+	service  merge.UserMergeApplicationService
+}
+
+func setup(t *testing.T) *fixture {
+	t.Helper()
+	ctx := context.Background()
+	db := testutil.SetupTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&acctrepo.AccountPO{},
+		&credentialrepo.PO{},
+		&assignmentrepo.AssignmentPO{},
+		&policyrepo.PolicyVersionPO{},
+	))
+	require.NoError(t, db.Exec(`CREATE TABLE casbin_rule (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		ptype TEXT, v0 TEXT, v1 TEXT, v2 TEXT, v3 TEXT, v4 TEXT, v5 TEXT)`).Error)
+
+	users := userrepo.NewRepository(db)
+	newUser := func(phone string) *userdomain.User {
+		p, err := meta.NewPhone(phone)
+		require.NoError(t, err)
+		u, err := userdomain.NewUser("家长", p)
+		require.NoError(t, err)
+		require.NoError(t, users.Create(ctx, u))
+		return u
+	}
+	source := newUser("+8613800138000")
+	target := newUser("+8613800138001")
+
+	account := accountdomain.NewAccount(source.ID, accountdomain.TypeWcMinip, "openid-1",
+		accountdomain.WithAppID("wx-app"))
+	require.NoError(t, acctrepo.NewAccountRepository(db).Create(ctx, account))
+
+	guards := guardrepo.NewRepository(db)
+	now := time.Now()
+	moveG := &guardianship.Guardianship{User: source.ID, Child: meta.FromUint64(101), Rel: guardianship.RelParent, EstablishedAt: now}
+	dupG := &guardianship.Guardianship{User: source.ID, Child: meta.FromUint64(102), Rel: guardianship.RelParent, EstablishedAt: now}
+	targetG := &guardianship.Guardianship{User: target.ID, Child: meta.FromUint64(102), Rel: guardianship.RelParent, EstablishedAt: now}
+	for _, g := range []*guardianship.Guardianship{moveG, dupG, targetG} {
+		require.NoError(t, guards.Create(ctx, g))
+	}
+
+	assignments := assignmentrepo.NewAssignmentRepository(db)
+	for _, a := range []assignmentdomain.Assignment{
+		assignmentdomain.NewAssignment(assignmentdomain.SubjectTypeUser, source.ID.String(), 7, "t1"),
+		assignmentdomain.NewAssignment(assignmentdomain.SubjectTypeUser, source.ID.String(), 8, "t1"),
+		assignmentdomain.NewAssignment(assignmentdomain.SubjectTypeUser, target.ID.String(), 8, "t1"),
+	} {
+		a := a
+		require.NoError(t, assignments.Create(ctx, &a))
+	}
+
+	sessions := &sessionStub{}
+	auditor := &auditorStub{}
+	return &fixture{
+		db:       db,
+		source:   source,
+		target:   target,
+		account:  account,
+		moveG:    moveG,
+		dupG:     dupG,
+		sessions: sessions,
+		auditor:  auditor,
+		service: merge.NewUserMergeApplicationService(
+			merge.NewUnitOfWork(db), sessions, nil, nil, merge.WithAuditor(auditor)),
+	}
+}
+
+func actions[T any](items []T, action func(T) string) map[string]int {
+	counts := map[string]int{}
+	for _, item := range items {
+		counts[action(item)]++
+	}
+	return counts
+}
+
+func TestMerge_DryRunReportsPlanWithoutWriting(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	report, err := f.service.Merge(ctx, merge.MergeCommand{
+		SourceUserID: f.source.ID.String(),
+		TargetUserID: f.target.ID.String(),
+		DryRun:       true,
+		Operator:     "admin",
+	})
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	require.Len(t, report.Accounts, 1)
+	assert.Equal(t, f.account.ID.String(), report.Accounts[0].AccountID)
+	assert.Equal(t, map[string]int{merge.ActionMove: 1, merge.ActionRevoke: 1},
+		actions(report.Guardianships, func(g merge.GuardianshipMove) string { return g.Action }))
+	assert.Equal(t, map[string]int{merge.ActionMove: 1, merge.ActionRevoke: 1},
+		actions(report.Assignments, func(a merge.AssignmentMove) string { return a.Action }))
+
+	acc, err := acctrepo.NewAccountRepository(f.db).GetByID(ctx, f.account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, f.source.ID, acc.UserID)
+	src, err := userrepo.NewRepository(f.db).FindByID(ctx, f.source.ID)
+	require.NoError(t, err)
+	assert.True(t, src.IsUsable())
+	assert.Empty(t, f.sessions.revokedUsers)
+	require.Len(t, f.auditor.entries, 1)
+	assert.True(t, f.auditor.entries[0].DryRun)
+}
+
+func TestMerge_MovesEverythingToTarget(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	_, err := f.service.Merge(ctx, merge.MergeCommand{
+		SourceUserID: f.source.ID.String(),
+		TargetUserID: f.target.ID.String(),
+		Reason:       "duplicate parent",
+		Operator:     "admin",
+	})
+	require.NoError(t, err)
+
+	acc, err := acctrepo.NewAccountRepository(f.db).GetByID(ctx, f.account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, f.target.ID, acc.UserID)
+
+	guards := guardrepo.NewRepository(f.db)
+	moved, err := guards.FindByID(ctx, f.moveG.ID)
+	require.NoError(t, err)
+	assert.Equal(t, f.target.ID, moved.User)
+	assert.True(t, moved.IsActive())
+	dup, err := guards.FindByID(ctx, f.dupG.ID)
+	require.NoError(t, err)
+	assert.Equal(t, f.source.ID, dup.User)
+	assert.False(t, dup.IsActive())
+
+	assignments := assignmentrepo.NewAssignmentRepository(f.db)
+	left, err := assignments.ListBySubjectAcrossTenants(ctx, assignmentdomain.SubjectTypeUser, f.source.ID.String())
+	require.NoError(t, err)
+	assert.Empty(t, left)
+	held, err := assignments.ListBySubject(ctx, assignmentdomain.SubjectTypeUser, f.target.ID.String(), "t1")
+	require.NoError(t, err)
+	assert.Len(t, held, 2)
+
+	var rules int64
+	require.NoError(t, f.db.Table("casbin_rule").Where("ptype = ? AND v0 = ?", "g", "user:"+f.target.ID.String()).Count(&rules).Error)
+	assert.EqualValues(t, 1, rules)
+	version, err := policyrepo.NewPolicyVersionRepository(f.db).GetCurrent(ctx, "t1")
+	require.NoError(t, err)
+	require.NotNil(t, version)
+	assert.EqualValues(t, 1, version.Version)
+
+	src, err := userrepo.NewRepository(f.db).FindByID(ctx, f.source.ID)
+	require.NoError(t, err)
+	assert.True(t, src.IsInactive())
+	assert.Equal(t, []meta.ID{f.source.ID}, f.sessions.revokedUsers)
+
+	require.Len(t, f.auditor.entries, 1)
+	assert.Equal(t, "duplicate parent", f.auditor.entries[0].Reason)
+	assert.NoError(t, f.auditor.entries[0].Err)
+}
+
+func TestMerge_RejectsInvalidInput(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	_, err := f.service.Merge(ctx, merge.MergeCommand{
+		SourceUserID: f.source.ID.String(),
+		TargetUserID: f.source.ID.String(),
+	})
+	assert.True(t, perrors.IsCode(err, code.ErrInvalidArgument))
+
+	_, err = f.service.Merge(ctx, merge.MergeCommand{
+		SourceUserID: f.source.ID.String(),
+		TargetUserID: "999999",
+	})
+	assert.True(t, perrors.IsCode(err, code.ErrUserNotFound))
+	assert.Len(t, f.auditor.entries, 2)
+}
+
+func TestMerge_ConflictOnRevokedTargetGuardianship(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	guards := guardrepo.NewRepository(f.db)
+	revoked := &guardianship.Guardianship{User: f.target.ID, Child: f.moveG.Child, Rel: guardianship.RelParent, EstablishedAt: time.Now()}
+	require.NoError(t, guards.Create(ctx, revoked))
+	revoked.Revoke(time.Now())
+	require.NoError(t, guards.Update(ctx, revoked))
+
+	_, err := f.service.Merge(ctx, merge.MergeCommand{
+		SourceUserID: f.source.ID.String(),
+		TargetUserID: f.target.ID.String(),
+	})
+	assert.True(t, perrors.IsCode(err, code.ErrUserMergeConflict))
+
+	acc, err := acctrepo.NewAccountRepository(f.db).GetByID(ctx, f.account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, f.source.ID, acc.UserID)
+}
+
+func TestMerge_MovesGrantsNotCoveredByTarget(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	tomorrow, nextMonth := now.Add(24*time.Hour), now.Add(30*24*time.Hour)
+	grant := func(userID meta.ID, roleID uint64, opts ...assignmentdomain.AssignmentOption) {
+		a := assignmentdomain.NewAssignment(assignmentdomain.SubjectTypeUser, userID.String(), roleID, "t1", opts...)
+		require.NoError(t, assignmentrepo.NewAssignmentRepository(f.db).Create(ctx, &a))
+	}
+	// 目标仅有待生效赋权：源赋权迁移
+	grant(f.source.ID, 9)
+	grant(f.target.ID, 9, assignmentdomain.WithValidity(&tomorrow, nil), assignmentdomain.WithStatus(assignmentdomain.StatusPending))
+	// 目标赋权更早失效：源赋权迁移
+	grant(f.source.ID, 10, assignmentdomain.WithValidity(nil, &nextMonth))
+	grant(f.target.ID, 10, assignmentdomain.WithValidity(nil, &tomorrow))
+	// 目标永久赋权覆盖源限时赋权：撤销源赋权
+	grant(f.source.ID, 11, assignmentdomain.WithValidity(nil, &tomorrow))
+	grant(f.target.ID, 11)
+
+	report, err := f.service.Merge(ctx, merge.MergeCommand{
+		SourceUserID: f.source.ID.String(),
+		TargetUserID: f.target.ID.String(),
+		DryRun:       true,
+	})
+	require.NoError(t, err)
+	byRole := make(map[uint64]string, len(report.Assignments))
+	for _, a := range report.Assignments {
+		byRole[a.RoleID] = a.Action
+	}
+	assert.Equal(t, map[uint64]string{
+		7:  merge.ActionMove,
+		8:  merge.ActionRevoke,
+		9:  merge.ActionMove,
+		10: merge.ActionMove,
+		11: merge.ActionRevoke,
+	}, byRole)
+}
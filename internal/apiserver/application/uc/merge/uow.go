@@ -0,0 +1,66 @@
+package merge
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	authzuow "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/uow"
+	accountDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
+	credentialDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/credential"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/uc/guardianship"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/uc/user"
+	acctrepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/account"
+	credentialrepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/credential"
+	guardrepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/guardianship"
+	userrepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/user"
+	dbmysql "github.com/FangcunMount/iam-contracts/internal/pkg/database/mysql"
+	txpkg "github.com/FangcunMount/iam-contracts/internal/pkg/database/tx"
+)
+
+// TxRepositories 用户合并跨越用户中心、认证与授权三个上下文，共享同一事务。
+type TxRepositories struct {
+	Users         user.Repository
+	Guardianships guardianship.Repository
+	Accounts      accountDomain.Repository
+	Credentials   credentialDomain.Repository
+	Authz         authzuow.TxRepositories
+}
+
+// UnitOfWork 提供用户合并的事务边界。
+type UnitOfWork interface {
+	WithinTx(ctx context.Context, fn func(tx TxRepositories) error) error
+}
+
+var _ txpkg.UnitOfWork[TxRepositories] = (*gormUnitOfWork)(nil)
+
+// NewUnitOfWork 创建基于 GORM 的 UnitOfWork。
+// authzOpts 应与授权模块保持一致（如启用版本变更发件箱）。
+func NewUnitOfWork(db *gorm.DB, authzOpts ...authzuow.Option) UnitOfWork {
+	return &gormUnitOfWork{
+		base:      dbmysql.NewUnitOfWork(db),
+		authzOpts: authzOpts,
+	}
+}
+
+type gormUnitOfWork struct {
+	base      *dbmysql.UnitOfWork
+	authzOpts []authzuow.Option
+}
+
+func (u *gormUnitOfWork) WithinTx(ctx context.Context, fn func(tx TxRepositories) error) error {
+	if u == nil || u.base == nil {
+		return fn(TxRepositories{})
+	}
+
+	return u.base.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repos := TxRepositories{
+			Users:         userrepo.NewRepository(tx),
+			Guardianships: guardrepo.NewRepository(tx),
+			Accounts:      acctrepo.NewAccountRepository(tx),
+			Credentials:   credentialrepo.NewRepository(tx),
+			Authz:         authzuow.NewTxRepositories(tx, u.authzOpts...),
+		}
+		return fn(repos)
+	})
+}
@@ -2,7 +2,10 @@ package assembler
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,19 +16,27 @@ import (
 	"github.com/FangcunMount/component-base/pkg/log"
 	"github.com/FangcunMount/component-base/pkg/messaging"
 	accountApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/account"
+	bindingApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/binding"
 	jwksApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/jwks"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/login"
 	loginprep "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/loginprep"
+	passwordApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/password"
 	registerApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/register"
+	samlApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/saml"
 	sessionApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/session"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/token"
 	authnUow "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/uow"
 	cachegovernance "github.com/FangcunMount/iam-contracts/internal/apiserver/application/cachegovernance"
+	accountDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	credentialDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/credential"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/jwks"
+	samlPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/saml"
 	sessionDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/session"
 	tokenDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/token"
+	oidcPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/oidcprovider"
 	idpPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+	wecomPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
 	userDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/uc/user"
 	authenticationInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/authentication"
 	cacheinfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/cache"
@@ -34,13 +45,16 @@ import (
 	acctrepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/account"
 	credentialrepo "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/credential"
 	jwksMysql "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/jwks"
+	samlMysql "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/samlidp"
 	mysqluser "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/user"
 	redisInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/redis"
+	samlInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/saml"
 	schedulerInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/scheduler"
 	smsInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/sms"
 	wechatInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/wechat"
 	authngrpc "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/authn/grpc"
 	authhandler "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/authn/restful/handler"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
 )
 
 // AuthnModule 认证模块
@@ -52,6 +66,11 @@ type AuthnModule struct {
 	LoginPreparationService loginprep.LoginPreparationService
 	TokenService            token.TokenApplicationService
 	SessionService          sessionApp.SessionApplicationService
+	SelfSessionService      sessionApp.SelfSessionApplicationService
+	PasswordService         passwordApp.PasswordApplicationService
+	BindingService          bindingApp.BindingApplicationService
+	SAMLIdPService          samlApp.SAMLIdPApplicationService             // 未启用 SAML 时为 nil
+	SAMLSPService           samlApp.SAMLServiceProviderApplicationService // 未启用 SAML 时为 nil
 
 	// JWKS 应用服务
 	KeyManagementApp *jwksApp.KeyManagementAppService
@@ -63,6 +82,10 @@ type AuthnModule struct {
 	AuthHandler         *authhandler.AuthHandler
 	JWKSHandler         *authhandler.JWKSHandler
 	SessionAdminHandler *authhandler.SessionAdminHandler
+	PasswordHandler     *authhandler.PasswordHandler
+	BindingHandler      *authhandler.BindingHandler
+	SessionHandler      *authhandler.SessionHandler
+	SAMLHandler         *authhandler.SAMLHandler
 
 	// gRPC 服务
 	GRPCService *authngrpc.Service
@@ -73,13 +96,16 @@ type AuthnModule struct {
 		Stop() error
 		IsRunning() bool
 		TriggerNow(ctx context.Context) error
+		Leadership() schedulerInfra.LeadershipStatus
 	}
 
 	tokenStoreInspectorSource *redisInfra.RedisStore
 	sessionStoreInspector     *redisInfra.SessionStore
 	otpInspectorSource        *redisInfra.OTPVerifierImpl
+	loginThrottle             *redisInfra.LoginThrottle
 	keySetBuilder             *jwks.KeySetBuilder
 	sessionManager            sessionDomain.Manager
+	roleSyncer                *deferredExternalRoleSyncer
 }
 
 // NewAuthnModule 创建认证模块
@@ -133,7 +159,10 @@ func (m *AuthnModule) Initialize(params ...interface{}) error {
 	}
 
 	// 初始化基础设施层
-	infra := m.initializeInfrastructure(db, redisClient, idpDeps, eventBus)
+	infra, err := m.initializeInfrastructure(db, redisClient, idpDeps, eventBus)
+	if err != nil {
+		return err
+	}
 
 	// 初始化领域层
 	domain := m.initializeDomain(infra)
@@ -147,7 +176,7 @@ func (m *AuthnModule) Initialize(params ...interface{}) error {
 	m.initializeInterface()
 
 	// 初始化调度器
-	m.initializeSchedulers()
+	m.initializeSchedulers(redisClient)
 
 	return nil
 }
@@ -162,6 +191,7 @@ type infrastructureComponents struct {
 	credentialRepo authentication.CredentialRepository
 	otpVerifier    authentication.OTPVerifier
 	otpRedis       *redisInfra.OTPVerifierImpl
+	loginThrottle  authentication.LoginThrottle
 	idp            authentication.IdentityProvider
 	tokenVerifier  authentication.TokenVerifier
 	accessChecker  sessionDomain.SubjectAccessEvaluator
@@ -182,14 +212,22 @@ type infrastructureComponents struct {
 
 	// IDP 基础设施
 	wechatAppQuerier idpPort.Repository
+	wecomAppQuerier  wecomPort.Repository
+	oidcQuerier      oidcPort.Repository
+	oidcAuthorizer   oidcPort.AuthorizationStore
+	oidcFederation   authentication.OIDCFederation
 	secretVault      idpPort.SecretVault
 
+	// SAML SP（saml.enabled=true 时装配）
+	samlRepo samlPort.Repository
+	samlSP   *samlInfra.ServiceProvider
+
 	// 消息总线（可选，登录 OTP 走 MQ 时需要）
 	eventBus messaging.EventBus
 }
 
 // initializeInfrastructure 初始化基础设施层
-func (m *AuthnModule) initializeInfrastructure(db *gorm.DB, redisClient *redis.Client, idpDeps *IDPModule, eventBus messaging.EventBus) *infrastructureComponents {
+func (m *AuthnModule) initializeInfrastructure(db *gorm.DB, redisClient *redis.Client, idpDeps *IDPModule, eventBus messaging.EventBus) (*infrastructureComponents, error) {
 	infra := &infrastructureComponents{
 		db:       db,
 		redis:    redisClient,
@@ -207,10 +245,24 @@ func (m *AuthnModule) initializeInfrastructure(db *gorm.DB, redisClient *redis.C
 	infra.otpRedis = otpRedis
 	m.otpInspectorSource = otpRedis
 
+	// 登录节流：密码登录按 IP/登录名、OTP 校验按手机号累计失败
+	if viper.GetBool("login_throttle.enabled") {
+		throttle := redisInfra.NewLoginThrottle(redisClient, loadThrottlePolicy())
+		m.loginThrottle = throttle
+		infra.loginThrottle = throttle
+		infra.otpVerifier = authentication.NewThrottledOTPVerifier(otpRedis, throttle)
+	}
+
 	// 身份提供商 (微信)
 	// 优先使用 IDP 模块提供的基础设施能力
 	if idpDeps != nil {
 		infra.wechatAppQuerier = idpDeps.Repository()
+		infra.wecomAppQuerier = idpDeps.WecomRepository()
+		infra.oidcQuerier = idpDeps.OIDCRepository()
+		infra.oidcAuthorizer = idpDeps.OIDCAuthorizationStore()
+		if client := idpDeps.OIDCClient(); client != nil {
+			infra.oidcFederation = client
+		}
 		infra.secretVault = idpDeps.SecretVault()
 		if provider := idpDeps.WechatAuthProvider(); provider != nil {
 			infra.idp = wechatInfra.NewIdentityProvider(provider, nil)
@@ -220,20 +272,19 @@ func (m *AuthnModule) initializeInfrastructure(db *gorm.DB, redisClient *redis.C
 		infra.idp = wechatInfra.NewIdentityProvider(nil, nil)
 	}
 
+	// SAML SP
+	if err := m.initializeSAML(infra); err != nil {
+		return nil, err
+	}
+
 	// JWKS 仓储
 	infra.keyRepo = jwksMysql.NewKeyRepository(db)
 
-	// JWKS 基础设施
-	keysDir := viper.GetString("jwks.keys_dir")
-	// 打印 keys_dir 以便启动时诊断（如果为空，会提示警告）
-	if strings.TrimSpace(keysDir) == "" {
-		log.Warnw("jwks.keys_dir is empty; private keys will be looked up in current working directory", "jwks.keys_dir", keysDir)
-	} else {
-		log.Infow("JWKS keys directory", "jwks.keys_dir", keysDir)
+	// JWKS 基础设施：私钥存储后端可切换（pem 本地文件 / mysql 加密存库）
+	if err := m.initializeKeyStorage(infra); err != nil {
+		return nil, err
 	}
-	infra.privateKeyStorage = crypto.NewPEMPrivateKeyStorage(keysDir)
 	infra.keyGenerator = crypto.NewRSAKeyGeneratorWithStorage(infra.privateKeyStorage)
-	infra.privKeyResolver = crypto.NewPEMPrivateKeyResolver(keysDir)
 
 	// Token Store
 	infra.tokenStore = redisInfra.NewRedisStore(redisClient)
@@ -245,7 +296,85 @@ func (m *AuthnModule) initializeInfrastructure(db *gorm.DB, redisClient *redis.C
 	infra.userRepo = mysqluser.NewRepository(db)
 	infra.accessChecker = sessionDomain.NewSubjectAccessEvaluator(infra.userRepo, infra.accountRepo)
 
-	return infra
+	return infra, nil
+}
+
+// initializeSAML 按 saml.* 配置装配 SAML SP；未启用时跳过，SAML 登录与端点均不可用
+func (m *AuthnModule) initializeSAML(infra *infrastructureComponents) error {
+	if !viper.GetBool("saml.enabled") {
+		return nil
+	}
+	cert, key, err := samlInfra.LoadKeyPair(viper.GetString("saml.sp.cert_file"), viper.GetString("saml.sp.key_file"))
+	if err != nil {
+		return err
+	}
+	store := redisInfra.NewSAMLStore(infra.redis)
+	sp, err := samlInfra.NewServiceProvider(samlInfra.Config{
+		EntityID:    viper.GetString("saml.sp.entity_id"),
+		BaseURL:     viper.GetString("saml.sp.base_url"),
+		Certificate: cert,
+		PrivateKey:  key,
+		ClockSkew:   viper.GetDuration("saml.clock_skew"),
+		RequestTTL:  viper.GetDuration("saml.request_ttl"),
+	}, store, store)
+	if err != nil {
+		return fmt.Errorf("failed to create saml service provider: %w", err)
+	}
+	infra.samlSP = sp
+	infra.samlRepo = samlMysql.NewSAMLIdPRepository(infra.db)
+	log.Infow("SAML service provider enabled", "saml.sp.entity_id", viper.GetString("saml.sp.entity_id"))
+	return nil
+}
+
+// initializeKeyStorage 按 jwks.storage.backend 装配私钥存储与签名解析器
+func (m *AuthnModule) initializeKeyStorage(infra *infrastructureComponents) error {
+	backend := strings.ToLower(strings.TrimSpace(viper.GetString("jwks.storage.backend")))
+	switch backend {
+	case "", "pem":
+		keysDir := viper.GetString("jwks.keys_dir")
+		// 打印 keys_dir 以便启动时诊断（如果为空，会提示警告）
+		if strings.TrimSpace(keysDir) == "" {
+			log.Warnw("jwks.keys_dir is empty; private keys will be looked up in current working directory", "jwks.keys_dir", keysDir)
+		} else {
+			log.Infow("JWKS keys directory", "jwks.keys_dir", keysDir)
+		}
+		infra.privateKeyStorage = crypto.NewPEMPrivateKeyStorage(keysDir)
+		infra.privKeyResolver = crypto.NewPEMPrivateKeyResolver(keysDir)
+	case "mysql":
+		masterKey, err := decodeMasterKey(viper.GetString("jwks.storage.master_key"))
+		if err != nil {
+			return fmt.Errorf("invalid jwks.storage.master_key: %w", err)
+		}
+		vault, err := crypto.NewAEADVault(masterKey)
+		if err != nil {
+			return fmt.Errorf("failed to create jwks key cipher: %w", err)
+		}
+		store := jwksMysql.NewPrivateKeyStore(infra.db, vault)
+		infra.privateKeyStorage = store
+		infra.privKeyResolver = store
+		log.Infow("JWKS private keys stored encrypted in database", "jwks.storage.backend", backend)
+	default:
+		return fmt.Errorf("unsupported jwks.storage.backend: %s", backend)
+	}
+	return nil
+}
+
+// decodeMasterKey 解析 32 字节主密钥，支持 base64 与 hex
+func decodeMasterKey(raw string) ([]byte, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("master key is empty")
+	}
+	for _, decode := range []func(string) ([]byte, error){
+		base64.StdEncoding.DecodeString,
+		base64.RawURLEncoding.DecodeString,
+		hex.DecodeString,
+	} {
+		if key, err := decode(raw); err == nil && len(key) == 32 {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("master key must decode to 32 bytes")
 }
 
 // domainComponents 领域层组件
@@ -315,7 +444,11 @@ func (m *AuthnModule) initializeDomain(infra *infrastructureComponents) *domainC
 		refreshTTL = 7 * 24 * 60 * 60 * 1000000000 // 7天（纳秒）
 	}
 
-	domain.sessionManager = sessionDomain.NewManager(infra.sessionStore)
+	domain.sessionManager = sessionDomain.NewManager(
+		infra.sessionStore,
+		sessionDomain.WithConcurrencyPolicy(infra.accountRepo, loadSessionConcurrencyPolicy()),
+		sessionDomain.WithTimeoutPolicy(loadSessionTimeoutPolicies()),
+	)
 	m.sessionManager = domain.sessionManager
 	domain.tokenIssuer = tokenDomain.NewTokenIssuer(infra.jwtGenerator, infra.tokenStore, domain.sessionManager, accessTTL, refreshTTL)
 	domain.tokenRefresher = tokenDomain.NewTokenRefresher(infra.jwtGenerator, infra.tokenStore, domain.sessionManager, infra.accessChecker, accessTTL, refreshTTL)
@@ -339,7 +472,15 @@ func (m *AuthnModule) initializeApplication(
 	hasher authentication.PasswordHasher,
 ) error {
 	// 账户应用服务
-	m.AccountService = accountApp.NewAccountApplicationService(infra.unitOfWork, domain.sessionManager)
+	m.AccountService = accountApp.NewAccountApplicationService(infra.unitOfWork, domain.sessionManager, infra.loginThrottle)
+
+	// 密码策略（注册、修改与重置密码共用）
+	passwordPolicies := loadPasswordPolicies()
+	passwordValidator := credentialDomain.NewPasswordPolicyValidator(
+		passwordPolicies,
+		newBreachedPasswordChecker(),
+		hasher,
+	)
 
 	// 注册服务
 	m.RegisterService = registerApp.NewRegisterApplicationService(
@@ -349,6 +490,7 @@ func (m *AuthnModule) initializeApplication(
 		infra.userRepo,
 		infra.wechatAppQuerier,
 		infra.secretVault,
+		passwordValidator,
 	)
 
 	smsProvider := strings.ToLower(strings.TrimSpace(viper.GetString("sms.provider")))
@@ -380,20 +522,76 @@ func (m *AuthnModule) initializeApplication(
 	}
 
 	m.LoginPreparationService = loginprep.NewLoginPreparationService(phoneOTP)
+	authenticater := authentication.NewAuthenticater(
+		infra.credentialRepo,
+		infra.accountRepo,
+		hasher,
+		infra.otpVerifier,
+		infra.idp,
+		infra.tokenVerifier,
+	).WithPasswordExpiry(authenticationInfra.NewPasswordExpiryAdapter(
+		credentialrepo.NewRepository(infra.db),
+		passwordPolicies,
+	)).WithLoginThrottle(infra.loginThrottle)
+	if lockout := loadLockoutPolicy(); lockout.Enabled {
+		authenticater = authenticater.WithCredentialLockout(authenticationInfra.NewCredentialLockoutAdapter(
+			credentialrepo.NewRepository(infra.db),
+			lockout,
+		))
+	}
+
+	// 上游 OIDC 联合登录：首次登录经注册流程即时开通账户
+	if infra.oidcFederation != nil {
+		authenticater = authenticater.WithOIDCFederation(
+			infra.oidcFederation,
+			registerApp.NewOIDCAccountProvisioner(m.RegisterService),
+		)
+	}
 
+	// SAML 单点登录：断言由 SP 校验，首次登录经注册流程即时开通账户
+	if infra.samlSP != nil {
+		authenticater = authenticater.WithSAML(
+			infra.samlSP,
+			registerApp.NewSAMLAccountProvisioner(m.RegisterService),
+		)
+		m.SAMLIdPService = samlApp.NewSAMLIdPApplicationService(infra.samlRepo, infra.samlSP)
+		m.SAMLSPService = samlApp.NewSAMLServiceProviderApplicationService(infra.samlRepo, infra.samlSP)
+	}
+
+	// 过期密码修改复用登录认证器的失败节流与凭据锁定
+	m.PasswordService = passwordApp.NewPasswordApplicationService(
+		infra.unitOfWork,
+		hasher,
+		infra.otpVerifier,
+		phoneOTP,
+		domain.sessionManager,
+		passwordValidator,
+		infra.accountRepo,
+		authenticater,
+	)
+
+	m.BindingService = bindingApp.NewBindingApplicationService(
+		infra.unitOfWork,
+		infra.idp,
+		infra.otpVerifier,
+		phoneOTP,
+		infra.wechatAppQuerier,
+		infra.wecomAppQuerier,
+		infra.secretVault,
+	)
+
+	m.roleSyncer = &deferredExternalRoleSyncer{}
 	m.LoginService = login.NewLoginApplicationService(
 		domain.tokenIssuer,
 		domain.tokenRefresher,
-		authentication.NewAuthenticater(
-			infra.credentialRepo,
-			infra.accountRepo,
-			hasher,
-			infra.otpVerifier,
-			infra.idp,
-			infra.tokenVerifier,
-		),
+		authenticater,
 		infra.wechatAppQuerier,
+		infra.wecomAppQuerier,
+		infra.oidcQuerier,
+		infra.oidcAuthorizer,
+		infra.samlRepo,
 		infra.secretVault,
+		m.roleSyncer,
 	)
 
 	// Token 服务
@@ -403,6 +601,7 @@ func (m *AuthnModule) initializeApplication(
 		domain.tokenVerifyer,
 	)
 	m.SessionService = sessionApp.NewSessionApplicationService(domain.sessionManager)
+	m.SelfSessionService = sessionApp.NewSelfSessionApplicationService(domain.sessionManager)
 
 	// JWKS 应用服务
 	logger := log.New(log.NewOptions())
@@ -413,6 +612,165 @@ func (m *AuthnModule) initializeApplication(
 	return nil
 }
 
+// passwordPolicyConfig 密码策略配置（password_policy）
+type passwordPolicyConfig struct {
+	MinLength      int           `mapstructure:"min_length"`
+	MinCharClasses int           `mapstructure:"min_char_classes"`
+	HistorySize    int           `mapstructure:"history_size"`
+	MaxAge         time.Duration `mapstructure:"max_age"`
+	CheckBreached  *bool         `mapstructure:"check_breached"`
+}
+
+// toPolicy 转换为领域策略，未配置的字段沿用 base
+func (c passwordPolicyConfig) toPolicy(base credentialDomain.PasswordPolicy) credentialDomain.PasswordPolicy {
+	if c.MinLength > 0 {
+		base.MinLength = c.MinLength
+	}
+	if c.MinCharClasses > 0 {
+		base.MinCharClasses = c.MinCharClasses
+	}
+	if c.HistorySize > 0 {
+		base.HistorySize = c.HistorySize
+	}
+	if c.MaxAge > 0 {
+		base.MaxAge = c.MaxAge
+	}
+	if c.CheckBreached != nil {
+		base.CheckBreached = *c.CheckBreached
+	}
+	return base
+}
+
+// loadPasswordPolicies 从配置加载默认及租户覆盖的密码策略
+func loadPasswordPolicies() *credentialDomain.StaticPasswordPolicyProvider {
+	var (
+		defaults passwordPolicyConfig
+		tenants  map[string]passwordPolicyConfig
+	)
+	if err := viper.UnmarshalKey("password_policy", &defaults); err != nil {
+		log.Warnw("invalid password_policy config, fallback to defaults", "error", err.Error())
+	}
+	if err := viper.UnmarshalKey("password_policy.tenants", &tenants); err != nil {
+		log.Warnw("invalid password_policy.tenants config, ignored", "error", err.Error())
+	}
+
+	provider := &credentialDomain.StaticPasswordPolicyProvider{
+		Default: defaults.toPolicy(credentialDomain.DefaultPasswordPolicy()),
+		Tenants: make(map[uint64]credentialDomain.PasswordPolicy, len(tenants)),
+	}
+	for raw, tenantCfg := range tenants {
+		tenantID, err := strconv.ParseUint(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			log.Warnw("skip password policy with invalid tenant id", "tenant", raw)
+			continue
+		}
+		provider.Tenants[tenantID] = tenantCfg.toPolicy(provider.Default)
+	}
+	return provider
+}
+
+// newBreachedPasswordChecker 按配置创建本地泄露密码库检查器，未配置路径时返回 nil
+func newBreachedPasswordChecker() credentialDomain.BreachedPasswordChecker {
+	path := strings.TrimSpace(viper.GetString("password_policy.breached_corpus_path"))
+	if path == "" {
+		return nil
+	}
+	return crypto.NewPrefixFileBreachedChecker(path, viper.GetInt("password_policy.breached_min_count"))
+}
+
+// loadSessionConcurrencyPolicy 从配置加载按账号类型的并发会话上限（session_limits）
+func loadSessionConcurrencyPolicy() sessionDomain.ConcurrencyPolicy {
+	var raw map[string]struct {
+		MaxSessions int    `mapstructure:"max_sessions"`
+		OnOverflow  string `mapstructure:"on_overflow"`
+	}
+	if err := viper.UnmarshalKey("session_limits", &raw); err != nil {
+		log.Warnw("invalid session_limits config, concurrent sessions unlimited", "error", err.Error())
+		return nil
+	}
+	policy := make(sessionDomain.ConcurrencyPolicy, len(raw))
+	for accountType, cfg := range raw {
+		action := sessionDomain.OverflowAction(strings.ToLower(strings.TrimSpace(cfg.OnOverflow)))
+		if action != sessionDomain.OverflowReject && action != sessionDomain.OverflowEvictOldest {
+			log.Warnw("unknown session_limits on_overflow, fallback to reject", "account_type", accountType, "on_overflow", cfg.OnOverflow)
+			action = sessionDomain.OverflowReject
+		}
+		policy[accountDomain.AccountType(accountType)] = sessionDomain.ConcurrencyLimit{
+			MaxSessions: cfg.MaxSessions,
+			OnOverflow:  action,
+		}
+	}
+	return policy
+}
+
+// sessionTimeoutConfig 会话超时配置（session_timeouts）
+type sessionTimeoutConfig struct {
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
+	MaxLifetime time.Duration `mapstructure:"max_lifetime"`
+}
+
+func (c sessionTimeoutConfig) toPolicy() sessionDomain.TimeoutPolicy {
+	return sessionDomain.TimeoutPolicy{IdleTimeout: c.IdleTimeout, MaxLifetime: c.MaxLifetime}
+}
+
+// loadSessionTimeoutPolicies 从配置加载默认、按账号类型及按租户的会话超时策略
+func loadSessionTimeoutPolicies() *sessionDomain.StaticTimeoutPolicyProvider {
+	var (
+		defaults     sessionTimeoutConfig
+		accountTypes map[string]sessionTimeoutConfig
+		tenants      map[string]sessionTimeoutConfig
+	)
+	if err := viper.UnmarshalKey("session_timeouts", &defaults); err != nil {
+		log.Warnw("invalid session_timeouts config, timeouts disabled", "error", err.Error())
+	}
+	if err := viper.UnmarshalKey("session_timeouts.account_types", &accountTypes); err != nil {
+		log.Warnw("invalid session_timeouts.account_types config, ignored", "error", err.Error())
+	}
+	if err := viper.UnmarshalKey("session_timeouts.tenants", &tenants); err != nil {
+		log.Warnw("invalid session_timeouts.tenants config, ignored", "error", err.Error())
+	}
+
+	provider := &sessionDomain.StaticTimeoutPolicyProvider{
+		Default:      defaults.toPolicy(),
+		AccountTypes: make(map[accountDomain.AccountType]sessionDomain.TimeoutPolicy, len(accountTypes)),
+		Tenants:      make(map[uint64]sessionDomain.TimeoutPolicy, len(tenants)),
+	}
+	for accountType, cfg := range accountTypes {
+		provider.AccountTypes[accountDomain.AccountType(accountType)] = cfg.toPolicy()
+	}
+	for raw, cfg := range tenants {
+		tenantID, err := strconv.ParseUint(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			log.Warnw("skip session timeout policy with invalid tenant id", "tenant", raw)
+			continue
+		}
+		provider.Tenants[tenantID] = cfg.toPolicy()
+	}
+	return provider
+}
+
+// loadLockoutPolicy 从配置加载凭据锁定策略（lockout）
+func loadLockoutPolicy() credentialDomain.LockoutPolicy {
+	return credentialDomain.LockoutPolicy{
+		Enabled:         viper.GetBool("lockout.enabled"),
+		Threshold:       viper.GetInt("lockout.threshold"),
+		LockDuration:    viper.GetDuration("lockout.lock_duration"),
+		BackoffFactor:   viper.GetFloat64("lockout.backoff_factor"),
+		MaxLockDuration: viper.GetDuration("lockout.max_lock_duration"),
+	}
+}
+
+// loadThrottlePolicy 从配置加载登录节流策略（login_throttle）
+func loadThrottlePolicy() authentication.ThrottlePolicy {
+	return authentication.ThrottlePolicy{
+		Window:              viper.GetDuration("login_throttle.window"),
+		MaxFailuresPerIP:    viper.GetInt("login_throttle.max_failures_per_ip"),
+		MaxFailuresPerUser:  viper.GetInt("login_throttle.max_failures_per_user"),
+		MaxFailuresPerPhone: viper.GetInt("login_throttle.max_failures_per_phone"),
+		CaptchaAfter:        viper.GetInt("login_throttle.captcha_after"),
+	}
+}
+
 // initializeInterface 初始化接口层
 func (m *AuthnModule) initializeInterface() {
 	m.AccountHandler = authhandler.NewAccountHandler(
@@ -431,6 +789,12 @@ func (m *AuthnModule) initializeInterface() {
 		m.KeyPublishApp,
 	)
 	m.SessionAdminHandler = authhandler.NewSessionAdminHandler(m.SessionService)
+	m.PasswordHandler = authhandler.NewPasswordHandler(m.PasswordService)
+	m.BindingHandler = authhandler.NewBindingHandler(m.BindingService)
+	m.SessionHandler = authhandler.NewSessionHandler(m.SelfSessionService)
+	if m.SAMLIdPService != nil && m.SAMLSPService != nil {
+		m.SAMLHandler = authhandler.NewSAMLHandler(m.SAMLIdPService, m.SAMLSPService, m.LoginService)
+	}
 
 	m.GRPCService = authngrpc.NewService(
 		m.TokenService,
@@ -440,14 +804,26 @@ func (m *AuthnModule) initializeInterface() {
 }
 
 // initializeSchedulers 初始化调度器
-func (m *AuthnModule) initializeSchedulers() {
+func (m *AuthnModule) initializeSchedulers(redisClient *redis.Client) {
 	logger := log.New(log.NewOptions())
 	cronSpec := "0 2 * * *" // 每天凌晨2点
 
+	var opts []schedulerInfra.Option
+	if viper.GetBool("jwks.rotation.leader_election.enabled") {
+		// 多副本部署时仅租约持有者执行轮换
+		ttl := viper.GetDuration("jwks.rotation.leader_election.lease_ttl")
+		if ttl <= 0 {
+			ttl = 30 * time.Second
+		}
+		lease := redisInfra.NewSchedulerLease(redisClient, "jwks_rotation", ttl)
+		opts = append(opts, schedulerInfra.WithLeaderLease(lease, ttl/3))
+	}
+
 	m.RotationScheduler = schedulerInfra.NewKeyRotationCronScheduler(
 		m.KeyRotationApp,
 		cronSpec,
 		logger,
+		opts...,
 	)
 }
 
@@ -467,13 +843,54 @@ func (m *AuthnModule) CacheFamilyInspectors() []cacheinfra.FamilyInspector {
 	inspectors = append(inspectors, redisInfra.RedisStoreInspectors(m.tokenStoreInspectorSource)...)
 	inspectors = append(inspectors, redisInfra.SessionStoreInspectors(m.sessionStoreInspector)...)
 	inspectors = append(inspectors, redisInfra.OTPVerifierInspectors(m.otpInspectorSource)...)
+	inspectors = append(inspectors, redisInfra.LoginThrottleInspectors(m.loginThrottle)...)
 	if m.keySetBuilder != nil {
 		inspectors = append(inspectors, cachegovernance.NewJWKSPublishSnapshotInspector(m.keySetBuilder))
 	}
 	return inspectors
 }
 
+// CacheFamilyOperators 返回认证模块暴露的缓存族治理操作器。
+func (m *AuthnModule) CacheFamilyOperators() []cacheinfra.FamilyOperator {
+	operators := make([]cacheinfra.FamilyOperator, 0, 8)
+	operators = append(operators, redisInfra.RedisStoreOperators(m.tokenStoreInspectorSource)...)
+	operators = append(operators, redisInfra.SessionStoreOperators(m.sessionStoreInspector)...)
+	operators = append(operators, redisInfra.OTPVerifierOperators(m.otpInspectorSource)...)
+	operators = append(operators, redisInfra.LoginThrottleOperators(m.loginThrottle)...)
+	return operators
+}
+
+// CacheFamilyWarmers 返回认证模块暴露的缓存族预热器。
+func (m *AuthnModule) CacheFamilyWarmers() []cacheinfra.FamilyWarmer {
+	if m.keySetBuilder == nil {
+		return nil
+	}
+	return []cacheinfra.FamilyWarmer{cachegovernance.NewJWKSPublishSnapshotWarmer(m.keySetBuilder)}
+}
+
 // SessionManager 返回认证模块创建的会话管理器。
 func (m *AuthnModule) SessionManager() sessionDomain.Manager {
 	return m.sessionManager
 }
+
+// BindExternalRoleSyncer 绑定上游身份角色同步（由 authz 模块初始化后注入）
+func (m *AuthnModule) BindExternalRoleSyncer(syncer login.ExternalRoleSyncer) {
+	if m == nil || m.roleSyncer == nil {
+		return
+	}
+	m.roleSyncer.target = syncer
+}
+
+// deferredExternalRoleSyncer 延迟绑定的外部角色同步器
+// 未绑定时跳过同步，登录不受影响
+type deferredExternalRoleSyncer struct {
+	target login.ExternalRoleSyncer
+}
+
+func (s *deferredExternalRoleSyncer) SyncExternalRoles(ctx context.Context, userID, tenantID meta.ID, source string, roleNames []string) error {
+	if s.target == nil {
+		log.Warnf("external roles not synced, no syncer is bound: source=%s", source)
+		return nil
+	}
+	return s.target.SyncExternalRoles(ctx, userID, tenantID, source, roleNames)
+}
@@ -1,14 +1,18 @@
 package assembler
 
 import (
+	"context"
 	"fmt"
 
 	"gorm.io/gorm"
 
 	assignmentApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/assignment"
+	historyApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/history"
+	manifestApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/manifest"
 	policyApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/policy"
 	resourceApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/resource"
 	roleApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/role"
+	serviceACLApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/serviceacl"
 	authzUow "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/uow"
 	assignmentDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
 	policyDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
@@ -19,9 +23,11 @@ import (
 	policyInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/policy"
 	resourceInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/resource"
 	roleInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/role"
+	serviceACLInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/serviceacl"
 	userInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/user"
 	authzgrpc "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/authz/grpc"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/interface/authz/restful/handler"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
 )
 
 // AuthzModule 授权模块
@@ -32,10 +38,23 @@ type AuthzModule struct {
 	PolicyHandler     *handler.PolicyHandler
 	ResourceHandler   *handler.ResourceHandler
 	CheckHandler      *handler.CheckHandler
+	ConfigHandler     *handler.ConfigHandler
+	HistoryHandler    *handler.PolicyHistoryHandler
+	ServiceACLHandler *handler.ServiceACLHandler
 	GRPCService       *authzgrpc.Service
 
+	// ServiceACLService gRPC 服务 ACL 的持久化与热更新（由服务启动流程绑定到 gRPC 拦截器）
+	ServiceACLService *serviceACLApp.Service
+	// AssignmentScheduler 限时赋权的有效期调度器（由服务启动流程启动与停止）
+	AssignmentScheduler *assignmentApp.ValidityScheduler
+
 	// CasbinAdapter 运行时策略引擎（供 HTTP/gRPC/中间件复用）
 	CasbinAdapter policyDomain.CasbinAdapter
+	// VersionNotifier 与 UoWOptions 供跨上下文写授权数据的模块（如用户合并）复用
+	VersionNotifier policyDomain.VersionNotifier
+	UoWOptions      []authzUow.Option
+
+	assignmentCommander assignmentDomain.Commander
 }
 
 // NewAuthzModule 创建授权模块
@@ -45,7 +64,8 @@ func NewAuthzModule() *AuthzModule {
 
 // Initialize 初始化授权模块
 // versionNotifier: 策略版本通知器（可选，传 nil 则不发送通知）
-func (m *AuthzModule) Initialize(db *gorm.DB, versionNotifier policyDomain.VersionNotifier) error {
+// uowOpts: 授权工作单元选项（如启用版本变更发件箱）
+func (m *AuthzModule) Initialize(db *gorm.DB, versionNotifier policyDomain.VersionNotifier, uowOpts ...authzUow.Option) error {
 	if db == nil {
 		return fmt.Errorf("mysql db is required")
 	}
@@ -57,6 +77,8 @@ func (m *AuthzModule) Initialize(db *gorm.DB, versionNotifier policyDomain.Versi
 		return fmt.Errorf("failed to create casbin adapter: %w", err)
 	}
 	m.CasbinAdapter = casbinAdapter
+	m.VersionNotifier = versionNotifier
+	m.UoWOptions = uowOpts
 
 	// 2. 初始化仓储层
 	roleRepository := roleInfra.NewRoleRepository(db)
@@ -64,7 +86,8 @@ func (m *AuthzModule) Initialize(db *gorm.DB, versionNotifier policyDomain.Versi
 	resourceRepository := resourceInfra.NewResourceRepository(db)
 	policyVersionRepository := policyInfra.NewPolicyVersionRepository(db)
 	userRepository := userInfra.NewRepository(db)
-	unitOfWork := authzUow.NewUnitOfWork(db)
+	serviceACLRepository := serviceACLInfra.NewServiceACLRepository(db)
+	unitOfWork := authzUow.NewUnitOfWork(db, uowOpts...)
 
 	// 3. 初始化领域服务
 	// Resource 模块
@@ -94,6 +117,14 @@ func (m *AuthzModule) Initialize(db *gorm.DB, versionNotifier policyDomain.Versi
 		versionNotifier,
 	)
 	assignmentQueryer := assignmentApp.NewAssignmentQueryService(assignmentManager, assignmentRepository)
+	m.assignmentCommander = assignmentCommander
+	m.AssignmentScheduler = assignmentApp.NewValidityScheduler(unitOfWork, casbinAdapter, versionNotifier)
+	// 声明式配置
+	configService := manifestApp.NewService(unitOfWork, casbinAdapter, versionNotifier)
+	// 策略变更历史
+	historyService := historyApp.NewService(unitOfWork, casbinAdapter, versionNotifier)
+	// gRPC 服务 ACL
+	m.ServiceACLService = serviceACLApp.NewService(serviceACLRepository)
 
 	// 5. 初始化 HTTP 处理器 - 依赖 driving 接口（CQRS）
 	// Resource Handler
@@ -106,6 +137,36 @@ func (m *AuthzModule) Initialize(db *gorm.DB, versionNotifier policyDomain.Versi
 	m.AssignmentHandler = handler.NewAssignmentHandler(assignmentCommander, assignmentQueryer)
 	// PDP
 	m.CheckHandler = handler.NewCheckHandler(casbinAdapter)
+	// 声明式配置 Handler
+	m.ConfigHandler = handler.NewConfigHandler(configService, configService)
+	// 策略变更历史 Handler
+	m.HistoryHandler = handler.NewPolicyHistoryHandler(historyService, historyService)
+	// gRPC 服务 ACL Handler
+	m.ServiceACLHandler = handler.NewServiceACLHandler(m.ServiceACLService, m.ServiceACLService)
 	m.GRPCService = authzgrpc.NewService(casbinAdapter, roleRepository, policyVersionRepository, assignmentCommander)
 	return nil
 }
+
+// ExternalRoleSyncer 返回供认证模块同步上游身份角色的适配器
+func (m *AuthzModule) ExternalRoleSyncer() *ExternalRoleSyncer {
+	if m == nil || m.assignmentCommander == nil {
+		return nil
+	}
+	return &ExternalRoleSyncer{commander: m.assignmentCommander}
+}
+
+// ExternalRoleSyncer 将用户角色同步请求转换为赋权命令
+type ExternalRoleSyncer struct {
+	commander assignmentDomain.Commander
+}
+
+// SyncExternalRoles 同步外部身份源授予用户的角色
+func (s *ExternalRoleSyncer) SyncExternalRoles(ctx context.Context, userID, tenantID meta.ID, source string, roleNames []string) error {
+	return s.commander.SyncExternalRoles(ctx, assignmentDomain.SyncExternalRolesCommand{
+		SubjectType: assignmentDomain.SubjectTypeUser,
+		SubjectID:   userID.String(),
+		TenantID:    tenantID.String(),
+		Source:      source,
+		RoleNames:   roleNames,
+	})
+}
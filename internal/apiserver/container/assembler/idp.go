@@ -11,11 +11,21 @@ import (
 
 	"github.com/FangcunMount/component-base/pkg/errors"
 	"github.com/FangcunMount/component-base/pkg/log"
+	"github.com/FangcunMount/component-base/pkg/messaging"
+	cachegovernance "github.com/FangcunMount/iam-contracts/internal/apiserver/application/cachegovernance"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/idp/oidcprovider"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/idp/wechatapp"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/idp/wecomapp"
+	oidcproviderDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/oidcprovider"
 	wechatappDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+	wecomappDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
 	cacheinfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/cache"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/infra/crypto"
+	messagingInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/messaging"
+	infraOIDCMysql "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/oidcprovider"
 	infraMysql "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/wechatapp"
+	infraWecomMysql "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/wecomapp"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/infra/oidc"
 	infraRedis "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/redis"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/infra/wechatapi"
 	wechatapiPort "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/wechatapi/port"
@@ -33,6 +43,8 @@ import (
 //
 // 职责：
 // - 微信应用管理（HTTP 接口）
+// - 企业微信应用管理（HTTP 接口）
+// - 上游 OIDC 身份提供商管理（HTTP 接口）
 // - 提供基础设施服务（供 authn 模块使用）
 // - 认证功能由 authn 模块统一提供
 type IDPModule struct {
@@ -40,9 +52,19 @@ type IDPModule struct {
 	WechatAppService           wechatapp.WechatAppApplicationService
 	WechatAppCredentialService wechatapp.WechatAppCredentialApplicationService
 	WechatAppTokenService      wechatapp.WechatAppTokenApplicationService
+	WecomAppService            wecomapp.WecomAppApplicationService
+	WecomAppCredentialService  wecomapp.WecomAppCredentialApplicationService
+	WecomAppTokenService       wecomapp.WecomAppTokenApplicationService
+	WechatCallbackService      wechatapp.WechatAppCallbackApplicationService
+	OIDCProviderService        oidcprovider.OIDCProviderApplicationService
+	OIDCCredentialService      oidcprovider.OIDCProviderCredentialApplicationService
+	OIDCAuthorizeService       oidcprovider.OIDCAuthorizeApplicationService
 
 	// HTTP 处理器（对外暴露）
-	WechatAppHandler *handler.WechatAppHandler
+	WechatAppHandler      *handler.WechatAppHandler
+	WecomAppHandler       *handler.WecomAppHandler
+	WechatCallbackHandler *handler.WechatCallbackHandler
+	OIDCProviderHandler   *handler.OIDCProviderHandler
 	// WechatAuthHandler 已移除 - 认证由 authn 模块统一提供
 
 	// gRPC 服务（对外暴露）
@@ -50,11 +72,18 @@ type IDPModule struct {
 
 	// 基础设施组件（内部管理，供其他模块使用）
 	wechatAppRepo       wechatappDomain.Repository
+	wecomAppRepo        wecomappDomain.Repository
+	oidcProviderRepo    oidcproviderDomain.Repository
+	oidcClient          *oidc.Client
+	oidcAuthorizations  oidcproviderDomain.AuthorizationStore
 	accessTokenCache    wechatappDomain.AccessTokenCache
 	secretVault         wechatappDomain.SecretVault
 	wechatAuthProvider  wechatapiPort.AuthProvider
 	wechatTokenProvider *wechatapi.TokenProvider
 	wechatSDKCache      wechatCache.Cache
+	callbackPublisher   wechatappDomain.CallbackEventPublisher
+	callbackNonces      wechatappDomain.CallbackNonceCache
+	revoker             *deferredAuthorizationRevoker
 }
 
 // NewIDPModule 创建 IDP 模块
@@ -66,12 +95,18 @@ func NewIDPModule() *IDPModule {
 // params[0]: *gorm.DB - 数据库连接
 // params[1]: *redis.Client - Redis 客户端
 // params[2]: []byte - 加密密钥（32 字节 AES-256）
+// params[3]: messaging.EventBus - 可选，用于转发微信推送消息
 func (m *IDPModule) Initialize(params ...interface{}) error {
 	// 验证参数
 	db, redisClient, encryptionKey, err := m.validateParameters(params)
 	if err != nil {
 		return err
 	}
+	if len(params) > 3 {
+		if bus, ok := params[3].(messaging.EventBus); ok && bus != nil {
+			m.callbackPublisher = messagingInfra.NewWechatCallbackPublisher(bus, "")
+		}
+	}
 
 	// 初始化基础设施层组件（直接创建）
 	if err := m.initializeInfrastructure(db, redisClient, encryptionKey); err != nil {
@@ -138,9 +173,16 @@ func (m *IDPModule) initializeInfrastructure(
 ) error {
 	// 创建 MySQL 仓储
 	m.wechatAppRepo = infraMysql.NewWechatAppRepository(db)
+	m.wecomAppRepo = infraWecomMysql.NewWecomAppRepository(db)
+	m.oidcProviderRepo = infraOIDCMysql.NewOIDCProviderRepository(db)
+
+	// 上游 OIDC 客户端（discovery 与 JWKS 进程内缓存）
+	m.oidcClient = oidc.NewClient(nil)
+	m.oidcAuthorizations = infraRedis.NewOIDCAuthorizationStore(redisClient)
 
 	// 创建 Redis 缓存
 	m.accessTokenCache = infraRedis.NewAccessTokenCache(redisClient)
+	m.callbackNonces = infraRedis.NewWechatCallbackNonceCache(redisClient)
 
 	// 创建加密服务
 	secretVault, err := crypto.NewSecretVault(encryptionKey)
@@ -165,6 +207,16 @@ type domainServices struct {
 	credentialRotater wechatappDomain.CredentialRotater
 	accessTokenCacher wechatappDomain.AccessTokenCacher
 	appTokenProvider  wechatappDomain.AppTokenProvider
+
+	// 企业微信应用领域服务
+	wecomAppCreator        wecomappDomain.Creator
+	wecomRotater           wecomappDomain.CredentialRotater
+	wecomAccessTokenCacher wecomappDomain.AccessTokenCacher
+	wecomTokenProvider     wecomappDomain.AppTokenProvider
+
+	// 上游 OIDC 身份提供商领域服务
+	oidcProviderCreator oidcproviderDomain.Creator
+	oidcRotater         oidcproviderDomain.CredentialRotater
 }
 
 // initializeDomain 初始化领域层
@@ -190,11 +242,23 @@ func (m *IDPModule) initializeDomain() (*domainServices, error) {
 		appTokenProvider,
 	)
 
+	// 企业微信应用领域服务（与微信应用共用 SecretVault 与访问令牌缓存）
+	wecomTokenProvider := &wecomTokenProviderAdapter{
+		tokenProvider: m.wechatTokenProvider,
+		secretVault:   m.secretVault,
+	}
+
 	return &domainServices{
-		wechatAppCreator:  wechatAppCreator,
-		credentialRotater: credentialRotater,
-		accessTokenCacher: accessTokenCacher,
-		appTokenProvider:  appTokenProvider,
+		wechatAppCreator:       wechatAppCreator,
+		credentialRotater:      credentialRotater,
+		accessTokenCacher:      accessTokenCacher,
+		appTokenProvider:       appTokenProvider,
+		wecomAppCreator:        wecomappDomain.NewCreator(m.wecomAppRepo),
+		wecomRotater:           wecomappDomain.NewCredentialRotater(m.secretVault, time.Now),
+		wecomAccessTokenCacher: wecomappDomain.NewAccessTokenCacher(m.accessTokenCache, wecomTokenProvider),
+		wecomTokenProvider:     wecomTokenProvider,
+		oidcProviderCreator:    oidcproviderDomain.NewCreator(m.oidcProviderRepo),
+		oidcRotater:            oidcproviderDomain.NewCredentialRotater(m.secretVault, time.Now),
 	}, nil
 }
 
@@ -221,6 +285,48 @@ func (m *IDPModule) initializeApplication(
 		m.accessTokenCache,
 	)
 
+	m.WecomAppService = wecomapp.NewWecomAppApplicationService(
+		m.wecomAppRepo,
+		domainServices.wecomAppCreator,
+		domainServices.wecomRotater,
+	)
+
+	m.WecomAppCredentialService = wecomapp.NewWecomAppCredentialApplicationService(
+		m.wecomAppRepo,
+		domainServices.wecomRotater,
+	)
+
+	m.WecomAppTokenService = wecomapp.NewWecomAppTokenApplicationService(
+		m.wecomAppRepo,
+		domainServices.wecomAccessTokenCacher,
+		domainServices.wecomTokenProvider,
+		m.accessTokenCache,
+	)
+
+	m.OIDCProviderService = oidcprovider.NewOIDCProviderApplicationService(
+		m.oidcProviderRepo,
+		domainServices.oidcProviderCreator,
+		domainServices.oidcRotater,
+	)
+
+	m.OIDCCredentialService = oidcprovider.NewOIDCProviderCredentialApplicationService(
+		m.oidcProviderRepo,
+		domainServices.oidcRotater,
+	)
+
+	m.OIDCAuthorizeService = oidcprovider.NewOIDCAuthorizeApplicationService(m.oidcProviderRepo, m.oidcClient, m.oidcAuthorizations)
+
+	// 撤回授权的凭据处理由 authn 模块提供，其初始化晚于 IDP 模块，先占位后绑定
+	m.revoker = &deferredAuthorizationRevoker{}
+	m.WechatCallbackService = wechatapp.NewWechatAppCallbackApplicationService(
+		m.wechatAppRepo,
+		m.secretVault,
+		wechatapi.NewCallbackCodec(),
+		m.callbackNonces,
+		m.revoker,
+		m.callbackPublisher,
+	)
+
 	return nil
 }
 
@@ -233,6 +339,20 @@ func (m *IDPModule) initializeInterface() error {
 		m.WechatAppTokenService,
 	)
 
+	m.WecomAppHandler = handler.NewWecomAppHandler(
+		m.WecomAppService,
+		m.WecomAppCredentialService,
+		m.WecomAppTokenService,
+	)
+
+	m.WechatCallbackHandler = handler.NewWechatCallbackHandler(m.WechatCallbackService)
+
+	m.OIDCProviderHandler = handler.NewOIDCProviderHandler(
+		m.OIDCProviderService,
+		m.OIDCCredentialService,
+		m.OIDCAuthorizeService,
+	)
+
 	// 创建 gRPC 服务
 	m.GRPCService = idpGrpc.NewService(
 		m.WechatAppService,
@@ -253,6 +373,26 @@ func (m *IDPModule) Repository() wechatappDomain.Repository {
 	return m.wechatAppRepo
 }
 
+// WecomRepository 返回企业微信应用查询能力（供 authn 模块解析 CorpSecret）
+func (m *IDPModule) WecomRepository() wecomappDomain.Repository {
+	return m.wecomAppRepo
+}
+
+// OIDCRepository 返回上游 OIDC 身份提供商查询能力（供 authn 模块解析 client_secret 与映射）
+func (m *IDPModule) OIDCRepository() oidcproviderDomain.Repository {
+	return m.oidcProviderRepo
+}
+
+// OIDCClient 返回上游 OIDC 客户端（供 authn 模块换取令牌并校验 id_token）
+func (m *IDPModule) OIDCClient() *oidc.Client {
+	return m.oidcClient
+}
+
+// OIDCAuthorizationStore 返回上游授权请求登记（供 authn 模块登录时消费 state）
+func (m *IDPModule) OIDCAuthorizationStore() oidcproviderDomain.AuthorizationStore {
+	return m.oidcAuthorizations
+}
+
 // SecretVault 返回密钥托管能力（供 authn 模块解密 AppSecret）
 func (m *IDPModule) SecretVault() wechatappDomain.SecretVault {
 	return m.secretVault
@@ -263,6 +403,14 @@ func (m *IDPModule) WechatAuthProvider() wechatapiPort.AuthProvider {
 	return m.wechatAuthProvider
 }
 
+// BindAuthorizationRevoker 绑定微信撤回授权的凭据处理（由 authn 模块初始化后注入）
+func (m *IDPModule) BindAuthorizationRevoker(revoker wechatappDomain.AuthorizationRevoker) {
+	if m == nil || m.revoker == nil {
+		return
+	}
+	m.revoker.target = revoker
+}
+
 // CacheFamilyInspectors 返回 IDP 模块暴露的缓存族状态读取器。
 func (m *IDPModule) CacheFamilyInspectors() []cacheinfra.FamilyInspector {
 	inspectors := make([]cacheinfra.FamilyInspector, 0, 2)
@@ -271,6 +419,19 @@ func (m *IDPModule) CacheFamilyInspectors() []cacheinfra.FamilyInspector {
 	return inspectors
 }
 
+// CacheFamilyOperators 返回 IDP 模块暴露的缓存族治理操作器。
+func (m *IDPModule) CacheFamilyOperators() []cacheinfra.FamilyOperator {
+	return infraRedis.AccessTokenCacheOperators(m.accessTokenCache)
+}
+
+// CacheFamilyWarmers 返回 IDP 模块暴露的缓存族预热器。
+func (m *IDPModule) CacheFamilyWarmers() []cacheinfra.FamilyWarmer {
+	if m.WechatAppTokenService == nil {
+		return nil
+	}
+	return []cacheinfra.FamilyWarmer{cachegovernance.NewWechatAccessTokenWarmer(m.WechatAppTokenService)}
+}
+
 // ==================== 适配器 ====================
 
 // appTokenProviderAdapter 应用令牌提供器适配器
@@ -301,3 +462,45 @@ func (a *appTokenProviderAdapter) Fetch(
 	// 这里暂时返回错误，表示需要调整架构
 	return nil, fmt.Errorf("not implemented: AppTokenProvider should be called from application layer with decrypted credentials")
 }
+
+// deferredAuthorizationRevoker 延迟绑定的撤回授权处理器
+// 未绑定时仅记录日志，回调仍正常应答，避免微信持续重试
+type deferredAuthorizationRevoker struct {
+	target wechatappDomain.AuthorizationRevoker
+}
+
+func (r *deferredAuthorizationRevoker) RevokeWechatAuthorization(ctx context.Context, appID, openID string) (int, error) {
+	if r.target == nil {
+		log.Warnf("wechat authorization revoked but no revoker is bound: app_id=%s", appID)
+		return 0, nil
+	}
+	return r.target.RevokeWechatAuthorization(ctx, appID, openID)
+}
+
+// wecomTokenProviderAdapter 企业微信应用令牌提供器适配器
+// 解密 CorpSecret 后调用企业微信 gettoken 接口
+type wecomTokenProviderAdapter struct {
+	tokenProvider *wechatapi.TokenProvider
+	secretVault   wecomappDomain.SecretVault
+}
+
+// Fetch 实现 wecomapp.AppTokenProvider 接口
+func (a *wecomTokenProviderAdapter) Fetch(
+	ctx context.Context,
+	app *wecomappDomain.WecomApp,
+) (*wecomappDomain.AppAccessToken, error) {
+	if app.Cred == nil || app.Cred.Auth == nil {
+		return nil, fmt.Errorf("wecom app corp secret not configured")
+	}
+
+	secret, err := a.secretVault.Decrypt(ctx, app.Cred.Auth.SecretCipher)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt corp secret: %w", err)
+	}
+
+	result, err := a.tokenProvider.FetchWorkToken(ctx, app.CorpID, app.AgentID, string(secret))
+	if err != nil {
+		return nil, err
+	}
+	return &wecomappDomain.AppAccessToken{Token: result.Token, ExpiresAt: result.ExpiresAt}, nil
+}
@@ -26,20 +26,24 @@ func NewSuggestModule() *SuggestModule {
 }
 
 // Initialize 初始化模块
-// params[0]: *gorm.DB
-// params[1]: config.Config (可选，默认从 viper 读取)
+// params 按类型识别：
+//   - *gorm.DB：业务库连接（必需）
+//   - appsuggest.Config：模块配置（可选，默认从 viper 读取）
+//   - appsuggest.Authorizer：权限判定器（可选，用于租户范围检索）
 func (m *SuggestModule) Initialize(params ...interface{}) error {
-	var db *gorm.DB
-	if len(params) > 0 {
-		if v, ok := params[0].(*gorm.DB); ok {
-			db = v
-		}
-	}
-
+	var (
+		db         *gorm.DB
+		authorizer appsuggest.Authorizer
+	)
 	cfg := appsuggest.LoadConfig()
-	if len(params) > 1 {
-		if v, ok := params[1].(appsuggest.Config); ok {
+	for _, param := range params {
+		switch v := param.(type) {
+		case *gorm.DB:
+			db = v
+		case appsuggest.Config:
 			cfg = v
+		case appsuggest.Authorizer:
+			authorizer = v
 		}
 	}
 
@@ -54,10 +58,16 @@ func (m *SuggestModule) Initialize(params ...interface{}) error {
 		return fmt.Errorf("suggest module requires mysql connection")
 	}
 
+	var opts []appsuggest.ServiceOption
+	if authorizer != nil {
+		opts = append(opts, appsuggest.WithAuthorizer(authorizer))
+	} else {
+		log.Warn("Suggest module has no authorizer; callers can only search their own children")
+	}
 	m.Service = appsuggest.NewService(appsuggest.Config{
 		MaxResults: cfg.MaxResults,
 		KeyPadLen:  cfg.KeyPadLen,
-	})
+	}, opts...)
 
 	loader := suggest.NewLoader(db, suggest.LoaderConfig{
 		FullSQL:  cfg.FullSQL,
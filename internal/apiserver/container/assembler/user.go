@@ -6,10 +6,12 @@ import (
 	"github.com/FangcunMount/component-base/pkg/errors"
 	appchild "github.com/FangcunMount/iam-contracts/internal/apiserver/application/uc/child"
 	appguard "github.com/FangcunMount/iam-contracts/internal/apiserver/application/uc/guardianship"
+	appmerge "github.com/FangcunMount/iam-contracts/internal/apiserver/application/uc/merge"
 	appregistration "github.com/FangcunMount/iam-contracts/internal/apiserver/application/uc/registration"
 	appuow "github.com/FangcunMount/iam-contracts/internal/apiserver/application/uc/uow"
 	appuser "github.com/FangcunMount/iam-contracts/internal/apiserver/application/uc/user"
 	sessiondomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/session"
+	policyDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
 	childInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/child"
 	guardianshipInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/guardianship"
 	userInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/user"
@@ -27,6 +29,7 @@ type UserModule struct {
 	UserHandler         *handler.UserHandler
 	ChildHandler        *handler.ChildHandler
 	GuardianshipHandler *handler.GuardianshipHandler
+	UserMergeHandler    *handler.UserMergeHandler
 	// gRPC 服务
 	GRPCService *ucGrpc.Service
 }
@@ -44,6 +47,7 @@ func (m *UserModule) Initialize(params ...interface{}) error {
 	}
 	var casbin authn.CasbinEnforcer
 	var sessionManager sessiondomain.Manager
+	var authzModule *AuthzModule
 	if len(params) > 1 {
 		for _, param := range params[1:] {
 			switch v := param.(type) {
@@ -51,6 +55,8 @@ func (m *UserModule) Initialize(params ...interface{}) error {
 				casbin = v
 			case sessiondomain.Manager:
 				sessionManager = v
+			case *AuthzModule:
+				authzModule = v
 			}
 		}
 	}
@@ -87,6 +93,19 @@ func (m *UserModule) Initialize(params ...interface{}) error {
 	// 组合注册服务（单事务创建 child + guardianship）
 	registrationAppSrv := appregistration.NewChildRegistrationService(uow)
 
+	// 用户合并服务（跨用户中心/认证/授权单事务）
+	var (
+		mergeUow        = appmerge.NewUnitOfWork(db)
+		casbinAdapter   policyDomain.CasbinAdapter
+		versionNotifier policyDomain.VersionNotifier
+	)
+	if authzModule != nil {
+		mergeUow = appmerge.NewUnitOfWork(db, authzModule.UoWOptions...)
+		casbinAdapter = authzModule.CasbinAdapter
+		versionNotifier = authzModule.VersionNotifier
+	}
+	mergeAppSrv := appmerge.NewUserMergeApplicationService(mergeUow, sessionManager, casbinAdapter, versionNotifier)
+
 	// 初始化 handler 层
 	m.UserHandler = handler.NewUserHandler(
 		userAppSrv,
@@ -108,6 +127,8 @@ func (m *UserModule) Initialize(params ...interface{}) error {
 		guardQuerySrv,
 	)
 
+	m.UserMergeHandler = handler.NewUserMergeHandler(mergeAppSrv)
+
 	// 初始化 gRPC 服务
 	identitySvc := identityGrpc.NewService(
 		userRepo,
@@ -9,11 +9,13 @@ import (
 	redis "github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 
+	authzUow "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/uow"
 	cachegovernance "github.com/FangcunMount/iam-contracts/internal/apiserver/application/cachegovernance"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/container/assembler"
 	policyDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
 	cacheinfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/cache"
 	messagingInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/messaging"
+	outboxInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/outbox"
 	"github.com/FangcunMount/iam-contracts/internal/pkg/middleware/authn"
 )
 
@@ -34,6 +36,10 @@ type Container struct {
 	IDPModule              *assembler.IDPModule
 	SuggestModule          *assembler.SuggestModule
 	CacheGovernanceService *cachegovernance.ReadService
+	CacheGovernanceCommand *cachegovernance.CommandService
+
+	// 发件箱中继（启用 EventBus 且 outbox.enabled 时创建，由服务启动/关闭流程驱动）
+	OutboxRelay *messagingInfra.OutboxRelay
 
 	// IDP 模块加密密钥（32 字节 AES-256）
 	idpEncryptionKey []byte
@@ -143,24 +149,24 @@ func (c *Container) initAuthModule() error {
 		return fmt.Errorf("failed to initialize auth module: %w", err)
 	}
 	c.AuthnModule = authModule
+	// 微信推送的撤回授权事件需要禁用 authn 侧的凭据
+	if c.IDPModule != nil && authModule.BindingService != nil {
+		c.IDPModule.BindAuthorizationRevoker(authModule.BindingService)
+	}
 	return nil
 }
 
 // initUserModule 初始化用户模块
 func (c *Container) initUserModule() error {
 	userModule := assembler.NewUserModule()
-	var casbin authn.CasbinEnforcer
+	params := []interface{}{c.mysqlDB}
 	if c.AuthzModule != nil {
-		casbin = c.AuthzModule.CasbinAdapter
+		params = append(params, authn.CasbinEnforcer(c.AuthzModule.CasbinAdapter), c.AuthzModule)
 	}
 	if c.AuthnModule != nil {
-		if err := userModule.Initialize(c.mysqlDB, casbin, c.AuthnModule.SessionManager()); err != nil {
-			return fmt.Errorf("failed to initialize user module: %w", err)
-		}
-		c.UserModule = userModule
-		return nil
+		params = append(params, c.AuthnModule.SessionManager())
 	}
-	if err := userModule.Initialize(c.mysqlDB, casbin); err != nil {
+	if err := userModule.Initialize(params...); err != nil {
 		return fmt.Errorf("failed to initialize user module: %w", err)
 	}
 	c.UserModule = userModule
@@ -173,27 +179,47 @@ func (c *Container) initAuthzModule() error {
 	authzModule := assembler.NewAuthzModule()
 
 	// 创建策略版本通知器
-	var versionNotifier policyDomain.VersionNotifier
+	var (
+		versionNotifier policyDomain.VersionNotifier
+		uowOpts         []authzUow.Option
+	)
 	if c.eventBus != nil {
 		// 使用 NSQ EventBus
 		versionNotifier = messagingInfra.NewVersionNotifier(c.eventBus)
 		log.Info("   📨 Policy version notifier: NSQ EventBus")
+
+		// 版本变更随授权事务写入发件箱，由中继异步投递
+		if cfg := messagingInfra.LoadOutboxRelayConfig(); cfg.Enabled && c.mysqlDB != nil {
+			c.OutboxRelay = messagingInfra.NewOutboxRelay(outboxInfra.NewRepository(c.mysqlDB), c.eventBus.Publisher(), cfg)
+			uowOpts = append(uowOpts, authzUow.WithVersionOutbox())
+			log.Info("   📤 Policy version outbox: enabled")
+		}
 	} else {
 		// 没有消息队列时，不发送通知
 		log.Warn("   ⚠️  Policy version notifier: disabled (no EventBus)")
 	}
 
-	if err := authzModule.Initialize(c.mysqlDB, versionNotifier); err != nil {
+	if err := authzModule.Initialize(c.mysqlDB, versionNotifier, uowOpts...); err != nil {
 		return fmt.Errorf("failed to initialize authz module: %w", err)
 	}
 	c.AuthzModule = authzModule
+	// 上游 OIDC 登录按提供商角色映射同步赋权
+	if c.AuthnModule != nil {
+		if syncer := authzModule.ExternalRoleSyncer(); syncer != nil {
+			c.AuthnModule.BindExternalRoleSyncer(syncer)
+		}
+	}
 	return nil
 }
 
 // initSuggestModule 初始化联想模块
 func (c *Container) initSuggestModule() error {
 	suggestModule := assembler.NewSuggestModule()
-	if err := suggestModule.Initialize(c.mysqlDB); err != nil {
+	params := []interface{}{c.mysqlDB}
+	if c.AuthzModule != nil && c.AuthzModule.CasbinAdapter != nil {
+		params = append(params, c.AuthzModule.CasbinAdapter)
+	}
+	if err := suggestModule.Initialize(params...); err != nil {
 		return fmt.Errorf("failed to initialize suggest module: %w", err)
 	}
 	// 可能因配置关闭而 Service 为空
@@ -208,7 +234,7 @@ func (c *Container) initSuggestModule() error {
 func (c *Container) initIDPModule() error {
 	idpModule := assembler.NewIDPModule()
 	// 传递 Redis（用于 Access Token 缓存）
-	if err := idpModule.Initialize(c.mysqlDB, c.redisClient, c.idpEncryptionKey); err != nil {
+	if err := idpModule.Initialize(c.mysqlDB, c.redisClient, c.idpEncryptionKey, c.eventBus); err != nil {
 		return fmt.Errorf("failed to initialize idp module: %w", err)
 	}
 	c.IDPModule = idpModule
@@ -217,17 +243,24 @@ func (c *Container) initIDPModule() error {
 
 type cacheInspectorProvider interface {
 	CacheFamilyInspectors() []cacheinfra.FamilyInspector
+	CacheFamilyOperators() []cacheinfra.FamilyOperator
+	CacheFamilyWarmers() []cacheinfra.FamilyWarmer
 }
 
 func (c *Container) initCacheGovernance() {
 	inspectors := make([]cacheinfra.FamilyInspector, 0, 8)
+	operators := make([]cacheinfra.FamilyOperator, 0, 8)
+	warmers := make([]cacheinfra.FamilyWarmer, 0, 2)
 	for _, provider := range []cacheInspectorProvider{c.AuthnModule, c.IDPModule} {
 		if provider == nil {
 			continue
 		}
 		inspectors = append(inspectors, provider.CacheFamilyInspectors()...)
+		operators = append(operators, provider.CacheFamilyOperators()...)
+		warmers = append(warmers, provider.CacheFamilyWarmers()...)
 	}
 	c.CacheGovernanceService = cachegovernance.NewReadService(inspectors)
+	c.CacheGovernanceCommand = cachegovernance.NewCommandService(operators, cachegovernance.WithWarmers(warmers...))
 }
 
 // HealthCheck 健康检查
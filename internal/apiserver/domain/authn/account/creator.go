@@ -26,8 +26,11 @@ func NewAccountCreator(repo Repository, idp authentication.IdentityProvider) Acc
 	strategies := map[AccountType]CreatorStrategy{
 		TypeOpera:        NewOperaCreatorStrategy(),
 		TypeWcMinip:      NewWechatMinipCreatorStrategy(idp),
+		TypeWcOffi:       NewWechatMPCreatorStrategy(idp),
 		TypeWcCom:        NewWecomCreatorStrategy(idp),
 		TypeMockConsumer: NewMockConsumerCreatorStrategy(),
+		TypeOIDC:         NewOIDCCreatorStrategy(),
+		TypeSAML:         NewSAMLCreatorStrategy(),
 	}
 
 	return &accountCreator{
@@ -0,0 +1,66 @@
+package account
+
+import (
+	"context"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+// ==================== 上游 OIDC 账户创建策略 ====================
+
+// OIDCCreatorStrategy 上游 OIDC 联合登录账户创建策略（TypeOIDC）
+// 身份已由认证流程校验 id_token 得出，此处无需再与上游交互
+type OIDCCreatorStrategy struct{}
+
+var _ CreatorStrategy = (*OIDCCreatorStrategy)(nil)
+
+// NewOIDCCreatorStrategy 创建上游 OIDC 创建策略
+func NewOIDCCreatorStrategy() *OIDCCreatorStrategy {
+	return &OIDCCreatorStrategy{}
+}
+
+// Kind 返回策略支持的账户类型
+func (s *OIDCCreatorStrategy) Kind() AccountType {
+	return TypeOIDC
+}
+
+// PrepareData 准备上游 OIDC 账户创建参数
+func (s *OIDCCreatorStrategy) PrepareData(ctx context.Context, input CreationInput) (*CreationParams, error) {
+	if input.OIDCProvider == nil || *input.OIDCProvider == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "oidc provider is required for oidc account")
+	}
+	if input.OIDCSubject == nil || *input.OIDCSubject == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "oidc subject is required for oidc account")
+	}
+
+	// 以提供商 slug 作为 AppID、上游 sub 作为 ExternalID，sub 仅在同一 Issuer 内唯一
+	return &CreationParams{
+		UserID:      input.UserID,
+		AccountType: TypeOIDC,
+		AppID:       AppId(*input.OIDCProvider),
+		ExternalID:  ExternalID(*input.OIDCSubject),
+		Profile:     input.Profile,
+		Meta:        input.Meta,
+		ParamsJSON:  input.ParamsJSON,
+	}, nil
+}
+
+// Create 创建上游 OIDC 账户实体
+func (s *OIDCCreatorStrategy) Create(ctx context.Context, params *CreationParams) (*Account, error) {
+	account := NewAccount(
+		params.UserID,
+		TypeOIDC,
+		params.ExternalID,
+		WithAppID(params.AppID),
+	)
+
+	if len(params.Profile) > 0 {
+		account.Profile = params.Profile
+	}
+	if len(params.Meta) > 0 {
+		account.Meta = params.Meta
+	}
+
+	return account, nil
+}
@@ -0,0 +1,32 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOIDCCreatorStrategyUsesProviderAndSubject(t *testing.T) {
+	provider, subject := "corp-sso", "3f2a-91"
+
+	strategy := NewOIDCCreatorStrategy()
+	params, err := strategy.PrepareData(context.Background(), CreationInput{
+		UserID:       meta.FromUint64(101),
+		AccountType:  TypeOIDC,
+		OIDCProvider: &provider,
+		OIDCSubject:  &subject,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AppId("corp-sso"), params.AppID)
+	require.Equal(t, ExternalID("3f2a-91"), params.ExternalID)
+
+	acc, err := strategy.Create(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, TypeOIDC, acc.Type)
+	require.Equal(t, AppId("corp-sso"), acc.AppID)
+
+	_, err = strategy.PrepareData(context.Background(), CreationInput{UserID: meta.FromUint64(101), AccountType: TypeOIDC, OIDCProvider: &provider})
+	require.Error(t, err)
+}
@@ -0,0 +1,66 @@
+package account
+
+import (
+	"context"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+// ==================== SAML 账户创建策略 ====================
+
+// SAMLCreatorStrategy SAML 单点登录账户创建策略（TypeSAML）
+// 身份已由认证流程校验断言签名得出，此处无需再与 IdP 交互
+type SAMLCreatorStrategy struct{}
+
+var _ CreatorStrategy = (*SAMLCreatorStrategy)(nil)
+
+// NewSAMLCreatorStrategy 创建 SAML 创建策略
+func NewSAMLCreatorStrategy() *SAMLCreatorStrategy {
+	return &SAMLCreatorStrategy{}
+}
+
+// Kind 返回策略支持的账户类型
+func (s *SAMLCreatorStrategy) Kind() AccountType {
+	return TypeSAML
+}
+
+// PrepareData 准备 SAML 账户创建参数
+func (s *SAMLCreatorStrategy) PrepareData(ctx context.Context, input CreationInput) (*CreationParams, error) {
+	if input.SAMLIdP == nil || *input.SAMLIdP == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "saml idp is required for saml account")
+	}
+	if input.SAMLNameID == nil || *input.SAMLNameID == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "saml name id is required for saml account")
+	}
+
+	// 以 IdP slug 作为 AppID、NameID 作为 ExternalID，NameID 仅在同一 IdP 内唯一
+	return &CreationParams{
+		UserID:      input.UserID,
+		AccountType: TypeSAML,
+		AppID:       AppId(*input.SAMLIdP),
+		ExternalID:  ExternalID(*input.SAMLNameID),
+		Profile:     input.Profile,
+		Meta:        input.Meta,
+		ParamsJSON:  input.ParamsJSON,
+	}, nil
+}
+
+// Create 创建 SAML 账户实体
+func (s *SAMLCreatorStrategy) Create(ctx context.Context, params *CreationParams) (*Account, error) {
+	account := NewAccount(
+		params.UserID,
+		TypeSAML,
+		params.ExternalID,
+		WithAppID(params.AppID),
+	)
+
+	if len(params.Profile) > 0 {
+		account.Profile = params.Profile
+	}
+	if len(params.Meta) > 0 {
+		account.Meta = params.Meta
+	}
+
+	return account, nil
+}
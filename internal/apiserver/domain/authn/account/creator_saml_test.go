@@ -0,0 +1,31 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSAMLCreatorStrategyUsesIdPAndNameID(t *testing.T) {
+	idp, nameID := "city-hospital", "alice@hospital.example"
+
+	strategy := NewSAMLCreatorStrategy()
+	params, err := strategy.PrepareData(context.Background(), CreationInput{
+		UserID:      meta.FromUint64(101),
+		AccountType: TypeSAML,
+		SAMLIdP:     &idp,
+		SAMLNameID:  &nameID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AppId("city-hospital"), params.AppID)
+	require.Equal(t, ExternalID("alice@hospital.example"), params.ExternalID)
+
+	acc, err := strategy.Create(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, TypeSAML, acc.Type)
+
+	_, err = strategy.PrepareData(context.Background(), CreationInput{UserID: meta.FromUint64(101), AccountType: TypeSAML, SAMLIdP: &idp})
+	require.Error(t, err)
+}
@@ -0,0 +1,120 @@
+package account
+
+import (
+	"context"
+	"fmt"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+// ==================== 微信公众号账户创建策略 ====================
+
+// WechatMPCreatorStrategy 微信公众号账户创建策略（TypeWcOffi）
+type WechatMPCreatorStrategy struct {
+	idp authentication.IdentityProvider // 用于网页授权 code 换身份
+}
+
+var _ CreatorStrategy = (*WechatMPCreatorStrategy)(nil)
+
+// NewWechatMPCreatorStrategy 创建微信公众号创建策略
+func NewWechatMPCreatorStrategy(idp authentication.IdentityProvider) *WechatMPCreatorStrategy {
+	return &WechatMPCreatorStrategy{
+		idp: idp,
+	}
+}
+
+// Kind 返回策略支持的账户类型
+func (s *WechatMPCreatorStrategy) Kind() AccountType {
+	return TypeWcOffi
+}
+
+// PrepareData 准备微信公众号账户创建参数
+// 如果提供了网页授权 code，则调用微信接口换取 OpenID 和 UnionID
+func (s *WechatMPCreatorStrategy) PrepareData(ctx context.Context, input CreationInput) (*CreationParams, error) {
+	if input.WechatAppID == nil || *input.WechatAppID == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "wechat appid is required for wechat mp account")
+	}
+
+	var openID, unionID string
+	profile := input.Profile
+
+	if input.WechatOAuthCode != nil && *input.WechatOAuthCode != "" {
+		if input.WechatAppSecret == nil || *input.WechatAppSecret == "" {
+			return nil, perrors.WithCode(code.ErrInvalidArgument, "wechat appsecret is required for oauth code exchange")
+		}
+
+		identity, err := s.idp.ExchangeWxMPCode(ctx, *input.WechatAppID, *input.WechatAppSecret, *input.WechatOAuthCode)
+		if err != nil {
+			return nil, perrors.WithCode(code.ErrInvalidCredential, "failed to exchange wechat oauth code: %v", err)
+		}
+		openID = identity.OpenID
+		unionID = identity.UnionID
+		profile = mergeWxMPProfile(profile, identity)
+	} else if input.WechatOpenID != nil && *input.WechatOpenID != "" {
+		openID = *input.WechatOpenID
+		if input.WechatUnionID != nil {
+			unionID = *input.WechatUnionID
+		}
+	} else {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "wechat oauth code or openid is required")
+	}
+
+	// 构造 ExternalID：OpenID@AppID
+	externalID := ExternalID(fmt.Sprintf("%s@%s", openID, *input.WechatAppID))
+
+	return &CreationParams{
+		UserID:      input.UserID,
+		AccountType: TypeWcOffi,
+		AppID:       AppId(*input.WechatAppID),
+		ExternalID:  externalID,
+		OpenID:      openID,
+		UnionID:     unionID,
+		Profile:     profile,
+		Meta:        input.Meta,
+		ParamsJSON:  input.ParamsJSON,
+	}, nil
+}
+
+// Create 创建微信公众号账户实体
+// UnionID 写入 UniqueID，便于与同一开放平台下的小程序账户关联
+func (s *WechatMPCreatorStrategy) Create(ctx context.Context, params *CreationParams) (*Account, error) {
+	opts := []AccountOption{WithAppID(params.AppID)}
+	if params.UnionID != "" {
+		opts = append(opts, WithUnionID(UnionID(params.UnionID)))
+	}
+	account := NewAccount(
+		params.UserID,
+		TypeWcOffi,
+		params.ExternalID,
+		opts...,
+	)
+
+	if len(params.Profile) > 0 {
+		account.Profile = params.Profile
+	}
+	if len(params.Meta) > 0 {
+		account.Meta = params.Meta
+	}
+
+	return account, nil
+}
+
+// mergeWxMPProfile snsapi_userinfo 返回的昵称头像作为缺省资料
+func mergeWxMPProfile(profile map[string]string, identity authentication.WxMPIdentity) map[string]string {
+	if identity.Nickname == "" && identity.AvatarURL == "" {
+		return profile
+	}
+	merged := make(map[string]string, len(profile)+2)
+	for k, v := range profile {
+		merged[k] = v
+	}
+	if _, ok := merged["nickname"]; !ok && identity.Nickname != "" {
+		merged["nickname"] = identity.Nickname
+	}
+	if _, ok := merged["avatar"]; !ok && identity.AvatarURL != "" {
+		merged["avatar"] = identity.AvatarURL
+	}
+	return merged
+}
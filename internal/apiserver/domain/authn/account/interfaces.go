@@ -86,10 +86,21 @@ type CreationInput struct {
 	WechatOpenID    *string // 微信OpenID（可选，如果有就不需要 code2session）
 	WechatUnionID   *string // 微信UnionID（可选）
 
+	// ========== 微信公众号专用（复用 WechatAppID/AppSecret/OpenID/UnionID）==========
+	WechatOAuthCode *string // 网页授权 code（TypeWcOffi 时如果没有 OpenID 则必须）
+
 	// ========== 企业微信专用 ==========
 	WecomCorpID *string // 企业CorpID（TypeWcCom 必须）
 	WecomUserID *string // 企业微信UserID（TypeWcCom 必须）
 
+	// ========== 上游 OIDC 专用 ==========
+	OIDCProvider *string // 提供商 slug（TypeOIDC 必须）
+	OIDCSubject  *string // 上游 sub（TypeOIDC 必须）
+
+	// ========== SAML 专用 ==========
+	SAMLIdP    *string // IdP 登记 slug（TypeSAML 必须）
+	SAMLNameID *string // 断言 NameID（TypeSAML 必须）
+
 	// ========== 账户元数据（可选）==========
 	Profile    map[string]string // 用户资料（昵称、头像等）
 	Meta       map[string]string // 额外元数据
@@ -20,9 +20,14 @@ type Repository interface {
 	UpdateStatus(ctx context.Context, id meta.ID, status AccountStatus) error
 	UpdateProfile(ctx context.Context, id meta.ID, profile map[string]string) error
 	UpdateMeta(ctx context.Context, id meta.ID, meta map[string]string) error
+	// UpdateUserID 将账号改挂到另一用户（用户合并）
+	UpdateUserID(ctx context.Context, id meta.ID, userID meta.ID) error
 
 	// GetBy*** 查询账号
 	GetByID(ctx context.Context, id meta.ID) (*Account, error)
 	GetByUniqueID(ctx context.Context, uniqueID UnionID) (*Account, error)
 	GetByExternalIDAppId(ctx context.Context, externalID ExternalID, appID AppId) (*Account, error)
+
+	// ListByUserID 列出用户名下全部账号
+	ListByUserID(ctx context.Context, userID meta.ID) ([]*Account, error)
 }
@@ -41,6 +41,8 @@ const (
 	TypeWcCom        AccountType = "wc-com"        // 企业微信
 	TypeOpera        AccountType = "opera"         // 运营后台
 	TypeMockConsumer AccountType = "mock-consumer" // 内部 mock C 端
+	TypeOIDC         AccountType = "oidc"          // 上游 OIDC 联合登录
+	TypeSAML         AccountType = "saml"          // SAML 单点登录
 )
 
 const (
@@ -57,7 +59,7 @@ func (a AccountType) String() string {
 
 // Validate 校验账号类型是否合法
 func (a AccountType) Validate() bool {
-	tList := []AccountType{TypeWcMinip, TypeWcOffi, TypeWcCom, TypeOpera, TypeMockConsumer}
+	tList := []AccountType{TypeWcMinip, TypeWcOffi, TypeWcCom, TypeOpera, TypeMockConsumer, TypeOIDC, TypeSAML}
 	for _, t := range tList {
 		if a == t {
 			return true
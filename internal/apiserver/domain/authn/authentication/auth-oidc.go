@@ -0,0 +1,224 @@
+package authentication
+
+import (
+	"context"
+	"fmt"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/logger"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// Register the OIDC credential builder
+func init() {
+	RegisterCredentialBuilder(AuthOIDC, newOIDCCredential)
+}
+
+// OIDCClaimMapping 上游声明名映射（顶层声明），由应用层按提供商配置补齐默认值
+type OIDCClaimMapping struct {
+	Subject  string
+	Username string
+	Email    string
+	Name     string
+	Phone    string
+}
+
+// ====================== 认证凭据（认证所需的数据） ========================
+
+// OIDCCredential 认证凭据（上游 OIDC 授权码联合登录所需的数据）
+type OIDCCredential struct {
+	TenantID      meta.ID
+	RemoteIP      string
+	UserAgent     string
+	Provider      string
+	Exchange      OIDCCodeExchange
+	Claims        OIDCClaimMapping
+	AutoProvision bool
+}
+
+// Scenario 返回认证场景
+func (c *OIDCCredential) Scenario() Scenario {
+	return AuthOIDC
+}
+
+// newOIDCCredential 构造上游 OIDC 认证凭据
+func newOIDCCredential(input AuthInput) (AuthCredential, error) {
+	if input.OIDCProvider == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "oidc provider is required for oidc authentication")
+	}
+	if input.OIDCIssuer == "" || input.OIDCClientID == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "oidc issuer and client_id are required for oidc authentication")
+	}
+	if input.OIDCCode == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "oidc code is required for oidc authentication")
+	}
+	if input.OIDCRedirectURI == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "oidc redirect_uri is required for oidc authentication")
+	}
+	claims := input.OIDCClaims
+	if claims.Subject == "" {
+		claims.Subject = "sub"
+	}
+	return &OIDCCredential{
+		TenantID:  input.TenantID,
+		RemoteIP:  input.RemoteIP,
+		UserAgent: input.UserAgent,
+		Provider:  input.OIDCProvider,
+		Exchange: OIDCCodeExchange{
+			Issuer:       input.OIDCIssuer,
+			ClientID:     input.OIDCClientID,
+			ClientSecret: input.OIDCClientSecret,
+			Code:         input.OIDCCode,
+			RedirectURI:  input.OIDCRedirectURI,
+			CodeVerifier: input.OIDCCodeVerifier,
+			Nonce:        input.OIDCNonce,
+		},
+		Claims:        claims,
+		AutoProvision: input.OIDCAutoProvision,
+	}, nil
+}
+
+// ================= 认证策略（执行认证的认证器） ========================
+
+// OAuthOIDCAuthStrategy 上游 OIDC 联合登录认证策略
+type OAuthOIDCAuthStrategy struct {
+	scenario    Scenario
+	credRepo    CredentialRepository
+	accountRepo AccountRepository
+	federation  OIDCFederation
+	provisioner OIDCAccountProvisioner
+}
+
+// 实现认证策略接口
+var _ AuthStrategy = (*OAuthOIDCAuthStrategy)(nil)
+
+// NewOAuthOIDCAuthStrategy 构造函数（注入依赖）；provisioner 为空时不做即时开通
+func NewOAuthOIDCAuthStrategy(
+	credRepo CredentialRepository,
+	accountRepo AccountRepository,
+	federation OIDCFederation,
+	provisioner OIDCAccountProvisioner,
+) *OAuthOIDCAuthStrategy {
+	return &OAuthOIDCAuthStrategy{
+		scenario:    AuthOIDC,
+		credRepo:    credRepo,
+		accountRepo: accountRepo,
+		federation:  federation,
+		provisioner: provisioner,
+	}
+}
+
+// Kind 返回认证策略类型
+func (o *OAuthOIDCAuthStrategy) Kind() Scenario {
+	return o.scenario
+}
+
+// Authenticate 执行上游 OIDC 联合登录认证
+// 认证流程：
+// 1. 授权码换取令牌并校验 id_token
+// 2. 按声明映射取 subject，查找凭据绑定
+// 3. 未绑定且提供商允许时即时开通账户
+// 4. 检查账户状态
+// 5. 返回认证判决（附带上游声明供角色映射）
+func (o *OAuthOIDCAuthStrategy) Authenticate(ctx context.Context, credential AuthCredential) (AuthDecision, error) {
+	oidcCred, ok := credential.(*OIDCCredential)
+	if !ok {
+		return AuthDecision{}, fmt.Errorf("oidc strategy expects *OIDCCredential, got %T", credential)
+	}
+	if o.federation == nil {
+		return AuthDecision{}, perrors.WithCode(code.ErrInvalidArgument, "oidc federation is not configured")
+	}
+
+	// Step 1: 与上游 IdP 交互，code 无效、id_token 校验失败均视为业务失败
+	claims, err := o.federation.ExchangeOIDCCode(ctx, oidcCred.Exchange)
+	if err != nil {
+		logger.L(ctx).Warnw("上游 OIDC 授权码换取失败",
+			"action", logger.ActionLogin,
+			"provider", oidcCred.Provider,
+			"error", err.Error(),
+		)
+		return AuthDecision{OK: false, ErrCode: ErrIDPExchangeFailed}, nil
+	}
+
+	// Step 2: 根据 subject 查找凭据绑定
+	identity := oidcCred.Claims.identity(oidcCred.Provider, claims)
+	if identity.Subject == "" {
+		return AuthDecision{OK: false, ErrCode: ErrIDPExchangeFailed}, nil
+	}
+
+	accountID, userID, credentialID, err := o.credRepo.FindOAuthCredential(ctx, string(AuthOIDC), oidcCred.Provider, identity.Subject)
+	if err != nil {
+		return AuthDecision{}, fmt.Errorf("failed to find oidc credential: %w", err)
+	}
+
+	// Step 3: 即时开通
+	if credentialID.IsZero() {
+		if !oidcCred.AutoProvision || o.provisioner == nil {
+			return AuthDecision{OK: false, ErrCode: ErrNoBinding}, nil
+		}
+		accountID, userID, credentialID, err = o.provisioner.ProvisionOIDCAccount(ctx, identity)
+		if err != nil {
+			return AuthDecision{}, fmt.Errorf("failed to provision oidc account: %w", err)
+		}
+	}
+
+	// Step 4: 检查账户状态
+	enabled, locked, err := o.accountRepo.GetAccountStatus(ctx, accountID)
+	if err != nil {
+		return AuthDecision{}, fmt.Errorf("failed to get account status: %w", err)
+	}
+	if !enabled {
+		return AuthDecision{OK: false, ErrCode: ErrDisabled}, nil
+	}
+	if locked {
+		return AuthDecision{OK: false, ErrCode: ErrLocked}, nil
+	}
+
+	// Step 5: 认证成功，构造Principal
+	principal := &Principal{
+		AccountID: accountID,
+		UserID:    userID,
+		TenantID:  oidcCred.TenantID,
+		AMR:       []string{string(AMROIDC)},
+		Claims: map[string]any{
+			"oidc_provider": oidcCred.Provider,
+			"oidc_subject":  identity.Subject,
+			"auth_time":     ctx.Value("request_time"),
+		},
+	}
+
+	return AuthDecision{
+		OK:             true,
+		Principal:      principal,
+		CredentialID:   credentialID,
+		ExternalClaims: claims,
+	}, nil
+}
+
+// identity 按映射从声明中提取上游身份
+func (m OIDCClaimMapping) identity(provider string, claims map[string]any) OIDCIdentity {
+	return OIDCIdentity{
+		Provider: provider,
+		Subject:  claimString(claims, m.Subject),
+		Username: claimString(claims, m.Username),
+		Email:    claimString(claims, m.Email),
+		Name:     claimString(claims, m.Name),
+		Phone:    claimString(claims, m.Phone),
+		Claims:   claims,
+	}
+}
+
+func claimString(claims map[string]any, name string) string {
+	if name == "" {
+		return ""
+	}
+	switch v := claims[name].(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -57,6 +57,7 @@ type PasswordAuthStrategy struct {
 	credRepo    CredentialRepository
 	accountRepo AccountRepository
 	hasher      PasswordHasher
+	expiry      PasswordExpiryChecker // 可选：密码最长有效期
 }
 
 // 实现认证策略接口
@@ -250,6 +251,25 @@ func (p *PasswordAuthStrategy) Authenticate(ctx context.Context, credential Auth
 		}, nil
 	}
 
+	// Step 4.1: 密码已过期时拒绝登录，需先修改密码（密码已验证，可安全提示）
+	if p.expiry != nil {
+		expired, err := p.expiry.IsPasswordExpired(ctx, principalTenant, credentialID)
+		if err != nil {
+			return AuthDecision{}, fmt.Errorf("failed to check password expiry: %w", err)
+		}
+		if expired {
+			l.Warnw("密码已过期",
+				"scenario", string(AuthPassword),
+				"credential_id", credentialID.String(),
+			)
+			return AuthDecision{
+				OK:           false,
+				ErrCode:      ErrPasswordExpired,
+				CredentialID: credentialID,
+			}, nil
+		}
+	}
+
 	l.Debugw("密码认证：步骤5 - 检查是否需要密码rehash",
 		"scenario", string(AuthPassword),
 		"credential_id", credentialID.String(),
@@ -0,0 +1,221 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/logger"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// Register the SAML credential builder
+func init() {
+	RegisterCredentialBuilder(AuthSAML, newSAMLCredential)
+}
+
+// 属性映射后用于即时开通的约定 Claims 键
+const (
+	SAMLClaimUsername = "username"
+	SAMLClaimEmail    = "email"
+	SAMLClaimName     = "name"
+	SAMLClaimPhone    = "phone"
+)
+
+// ====================== 认证凭据（认证所需的数据） ========================
+
+// SAMLCredential 认证凭据（SAML HTTP-POST 绑定回传的断言）
+type SAMLCredential struct {
+	TenantID      meta.ID
+	RemoteIP      string
+	UserAgent     string
+	IdP           string
+	Verification  SAMLResponseVerification
+	Attributes    map[string]string
+	AutoProvision bool
+}
+
+// Scenario 返回认证场景
+func (c *SAMLCredential) Scenario() Scenario {
+	return AuthSAML
+}
+
+// newSAMLCredential 构造 SAML 认证凭据
+func newSAMLCredential(input AuthInput) (AuthCredential, error) {
+	if input.SAMLIdP == "" || input.SAMLIdPEntityID == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "saml idp is required for saml authentication")
+	}
+	if len(input.SAMLCertificates) == 0 {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "saml idp signing certificate is required for saml authentication")
+	}
+	if input.SAMLResponse == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "saml response is required for saml authentication")
+	}
+	return &SAMLCredential{
+		TenantID:  input.TenantID,
+		RemoteIP:  input.RemoteIP,
+		UserAgent: input.UserAgent,
+		IdP:       input.SAMLIdP,
+		Verification: SAMLResponseVerification{
+			IdP:          input.SAMLIdP,
+			IdPEntityID:  input.SAMLIdPEntityID,
+			Certificates: input.SAMLCertificates,
+			Response:     input.SAMLResponse,
+		},
+		Attributes:    input.SAMLAttributes,
+		AutoProvision: input.SAMLAutoProvision,
+	}, nil
+}
+
+// ================= 认证策略（执行认证的认证器） ========================
+
+// SAMLAuthStrategy SAML 单点登录认证策略
+type SAMLAuthStrategy struct {
+	scenario    Scenario
+	credRepo    CredentialRepository
+	accountRepo AccountRepository
+	verifier    SAMLAssertionVerifier
+	provisioner SAMLAccountProvisioner
+}
+
+// 实现认证策略接口
+var _ AuthStrategy = (*SAMLAuthStrategy)(nil)
+
+// NewSAMLAuthStrategy 构造函数（注入依赖）；provisioner 为空时不做即时开通
+func NewSAMLAuthStrategy(
+	credRepo CredentialRepository,
+	accountRepo AccountRepository,
+	verifier SAMLAssertionVerifier,
+	provisioner SAMLAccountProvisioner,
+) *SAMLAuthStrategy {
+	return &SAMLAuthStrategy{
+		scenario:    AuthSAML,
+		credRepo:    credRepo,
+		accountRepo: accountRepo,
+		verifier:    verifier,
+		provisioner: provisioner,
+	}
+}
+
+// Kind 返回认证策略类型
+func (s *SAMLAuthStrategy) Kind() Scenario {
+	return s.scenario
+}
+
+// Authenticate 执行 SAML 单点登录认证
+// 认证流程：
+// 1. 校验 SAMLResponse 并消费断言
+// 2. 以 NameID 查找凭据绑定
+// 3. 未绑定且 IdP 允许时即时开通账户
+// 4. 检查账户状态
+// 5. 返回认证判决（属性按映射写入 Principal.Claims）
+func (s *SAMLAuthStrategy) Authenticate(ctx context.Context, credential AuthCredential) (AuthDecision, error) {
+	samlCred, ok := credential.(*SAMLCredential)
+	if !ok {
+		return AuthDecision{}, fmt.Errorf("saml strategy expects *SAMLCredential, got %T", credential)
+	}
+	if s.verifier == nil {
+		return AuthDecision{}, perrors.WithCode(code.ErrInvalidArgument, "saml service provider is not configured")
+	}
+
+	// Step 1: 签名、条件或重放校验失败均视为业务失败
+	assertion, err := s.verifier.VerifySAMLResponse(ctx, samlCred.Verification)
+	if err != nil {
+		logger.L(ctx).Warnw("SAML 断言校验失败",
+			"action", logger.ActionLogin,
+			"idp", samlCred.IdP,
+			"error", err.Error(),
+		)
+		if errors.Is(err, ErrSAMLReplay) {
+			return AuthDecision{OK: false, ErrCode: ErrAssertionReplayed}, nil
+		}
+		return AuthDecision{OK: false, ErrCode: ErrAssertionInvalid}, nil
+	}
+	if assertion.NameID == "" {
+		return AuthDecision{OK: false, ErrCode: ErrAssertionInvalid}, nil
+	}
+
+	// Step 2: 根据 NameID 查找凭据绑定
+	claims := mapSAMLAttributes(samlCred.Attributes, assertion.Attributes)
+	accountID, userID, credentialID, err := s.credRepo.FindOAuthCredential(ctx, string(AuthSAML), samlCred.IdP, assertion.NameID)
+	if err != nil {
+		return AuthDecision{}, fmt.Errorf("failed to find saml credential: %w", err)
+	}
+
+	// Step 3: 即时开通
+	if credentialID.IsZero() {
+		if !samlCred.AutoProvision || s.provisioner == nil {
+			return AuthDecision{OK: false, ErrCode: ErrNoBinding}, nil
+		}
+		accountID, userID, credentialID, err = s.provisioner.ProvisionSAMLAccount(ctx, SAMLIdentity{
+			IdP:      samlCred.IdP,
+			TenantID: samlCred.TenantID,
+			NameID:   assertion.NameID,
+			Username: claimString(claims, SAMLClaimUsername),
+			Email:    claimString(claims, SAMLClaimEmail),
+			Name:     claimString(claims, SAMLClaimName),
+			Phone:    claimString(claims, SAMLClaimPhone),
+			Claims:   claims,
+		})
+		if err != nil {
+			return AuthDecision{}, fmt.Errorf("failed to provision saml account: %w", err)
+		}
+	}
+
+	// Step 4: 检查账户状态
+	enabled, locked, err := s.accountRepo.GetAccountStatus(ctx, accountID)
+	if err != nil {
+		return AuthDecision{}, fmt.Errorf("failed to get account status: %w", err)
+	}
+	if !enabled {
+		return AuthDecision{OK: false, ErrCode: ErrDisabled}, nil
+	}
+	if locked {
+		return AuthDecision{OK: false, ErrCode: ErrLocked}, nil
+	}
+
+	// Step 5: 认证成功，构造Principal；映射属性在前，保留键不被覆盖
+	principalClaims := make(map[string]any, len(claims)+4)
+	for k, v := range claims {
+		principalClaims[k] = v
+	}
+	principalClaims["saml_idp"] = samlCred.IdP
+	principalClaims["saml_name_id"] = assertion.NameID
+	if assertion.SessionIndex != "" {
+		principalClaims["saml_session_index"] = assertion.SessionIndex
+	}
+	principalClaims["auth_time"] = ctx.Value("request_time")
+
+	principal := &Principal{
+		AccountID: accountID,
+		UserID:    userID,
+		TenantID:  samlCred.TenantID,
+		AMR:       []string{string(AMRSAML)},
+		Claims:    principalClaims,
+	}
+
+	return AuthDecision{
+		OK:           true,
+		Principal:    principal,
+		CredentialID: credentialID,
+	}, nil
+}
+
+// mapSAMLAttributes 按映射把 SAML 属性转为 Claims；单值取字符串，多值保留列表
+func mapSAMLAttributes(mapping map[string]string, attributes map[string][]string) map[string]any {
+	claims := make(map[string]any, len(mapping))
+	for attr, key := range mapping {
+		values := attributes[attr]
+		switch len(values) {
+		case 0:
+			continue
+		case 1:
+			claims[key] = values[0]
+		default:
+			claims[key] = append([]string(nil), values...)
+		}
+	}
+	return claims
+}
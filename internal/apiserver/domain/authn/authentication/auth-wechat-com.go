@@ -119,7 +119,7 @@ func (o *OAuthWeChatComAuthStrategy) Authenticate(ctx context.Context, credentia
 		idpIdentifier = openUserID
 	}
 
-	accountID, uid, credentialID, err := o.credRepo.FindOAuthCredential(ctx, "wecom", wecomCred.CorpID, idpIdentifier)
+	accountID, uid, credentialID, err := o.credRepo.FindOAuthCredential(ctx, string(AuthWecom), wecomCred.CorpID, idpIdentifier)
 	if err != nil {
 		return AuthDecision{}, fmt.Errorf("failed to find wecom credential: %w", err)
 	}
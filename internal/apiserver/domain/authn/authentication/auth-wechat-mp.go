@@ -0,0 +1,174 @@
+package authentication
+
+import (
+	"context"
+	"fmt"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// Register the Wechat Official Account credential builder
+func init() {
+	RegisterCredentialBuilder(AuthWxMP, newWechatMPCredential)
+}
+
+// ====================== 认证凭据（认证所需的数据） ========================
+
+// WechatMPCredential 认证凭据（微信公众号网页授权登录所需的数据）
+type WechatMPCredential struct {
+	TenantID  meta.ID
+	RemoteIP  string
+	UserAgent string
+	AppID     string
+	AppSecret string
+	Code      string
+}
+
+// Scenario 返回认证场景
+func (c *WechatMPCredential) Scenario() Scenario {
+	return AuthWxMP
+}
+
+// newWechatMPCredential 构造微信公众号认证凭据
+func newWechatMPCredential(input AuthInput) (AuthCredential, error) {
+	if input.WxMPAppID == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "wechat mp appid is required for wechat mp authentication")
+	}
+	if input.WxMPAppSecret == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "wechat mp appsecret is required for wechat mp authentication")
+	}
+	if input.WxMPCode == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "wechat oauth code is required for wechat mp authentication")
+	}
+	return &WechatMPCredential{
+		TenantID:  input.TenantID,
+		RemoteIP:  input.RemoteIP,
+		UserAgent: input.UserAgent,
+		AppID:     input.WxMPAppID,
+		AppSecret: input.WxMPAppSecret,
+		Code:      input.WxMPCode,
+	}, nil
+}
+
+// ================= 认证策略（执行认证的认证器） ========================
+
+// OAuthWechatMPAuthStrategy 微信公众号网页授权认证策略
+type OAuthWechatMPAuthStrategy struct {
+	scenario    Scenario
+	credRepo    CredentialRepository
+	accountRepo AccountRepository
+	idp         IdentityProvider
+}
+
+// 实现认证策略接口
+var _ AuthStrategy = (*OAuthWechatMPAuthStrategy)(nil)
+
+// NewOAuthWechatMPAuthStrategy 构造函数（注入依赖）
+func NewOAuthWechatMPAuthStrategy(
+	credRepo CredentialRepository,
+	accountRepo AccountRepository,
+	idp IdentityProvider,
+) *OAuthWechatMPAuthStrategy {
+	return &OAuthWechatMPAuthStrategy{
+		scenario:    AuthWxMP,
+		credRepo:    credRepo,
+		accountRepo: accountRepo,
+		idp:         idp,
+	}
+}
+
+// Kind 返回认证策略类型
+func (o *OAuthWechatMPAuthStrategy) Kind() Scenario {
+	return o.scenario
+}
+
+// Authenticate 执行微信公众号网页授权认证
+// 认证流程：
+// 1. 调用微信API用网页授权code换取openID/unionID
+// 2. 查找公众号凭据绑定（优先unionID，回退openID）
+// 3. 未绑定公众号但有unionID时，按unionID关联已有的小程序账户
+// 4. 检查账户状态并返回认证判决
+func (o *OAuthWechatMPAuthStrategy) Authenticate(ctx context.Context, credential AuthCredential) (AuthDecision, error) {
+	mpCred, ok := credential.(*WechatMPCredential)
+	if !ok {
+		return AuthDecision{}, fmt.Errorf("wechat mp strategy expects *WechatMPCredential, got %T", credential)
+	}
+
+	// Step 1: 与微信IdP交互，用网页授权code换取身份
+	identity, err := o.idp.ExchangeWxMPCode(ctx, mpCred.AppID, mpCred.AppSecret, mpCred.Code)
+	if err != nil {
+		return AuthDecision{
+			OK:      false,
+			ErrCode: ErrIDPExchangeFailed,
+		}, fmt.Errorf("failed to exchange wx mp code: %w", err)
+	}
+
+	// Step 2: 查找公众号凭据绑定
+	idpIdentifier := identity.OpenID
+	if identity.UnionID != "" {
+		idpIdentifier = identity.UnionID
+	}
+	accountID, userID, credentialID, err := o.credRepo.FindOAuthCredential(ctx, string(AuthWxMP), mpCred.AppID, idpIdentifier)
+	if err != nil {
+		return AuthDecision{}, fmt.Errorf("failed to find wx mp credential: %w", err)
+	}
+
+	// Step 3: 同一开放平台下的小程序账户通过 unionID 关联
+	linkedVia := ""
+	if credentialID.IsZero() && identity.UnionID != "" {
+		accountID, userID, credentialID, err = o.credRepo.FindOAuthCredential(ctx, string(AuthWxMinip), "", identity.UnionID)
+		if err != nil {
+			return AuthDecision{}, fmt.Errorf("failed to find wx minip credential by unionid: %w", err)
+		}
+		linkedVia = "unionid"
+	}
+	if credentialID.IsZero() {
+		return AuthDecision{
+			OK:      false,
+			ErrCode: ErrNoBinding,
+		}, nil
+	}
+
+	// Step 4: 检查账户状态
+	enabled, locked, err := o.accountRepo.GetAccountStatus(ctx, accountID)
+	if err != nil {
+		return AuthDecision{}, fmt.Errorf("failed to get account status: %w", err)
+	}
+	if !enabled {
+		return AuthDecision{
+			OK:      false,
+			ErrCode: ErrDisabled,
+		}, nil
+	}
+	if locked {
+		return AuthDecision{
+			OK:      false,
+			ErrCode: ErrLocked,
+		}, nil
+	}
+
+	claims := map[string]any{
+		"wx_openid":  identity.OpenID,
+		"wx_unionid": identity.UnionID,
+		"wx_scope":   identity.Scope,
+		"auth_time":  ctx.Value("request_time"),
+	}
+	if linkedVia != "" {
+		claims["wx_linked_via"] = linkedVia
+	}
+	principal := &Principal{
+		AccountID: accountID,
+		UserID:    userID,
+		TenantID:  mpCred.TenantID,
+		AMR:       []string{string(AMRWx)},
+		Claims:    claims,
+	}
+
+	return AuthDecision{
+		OK:           true,
+		Principal:    principal,
+		CredentialID: credentialID,
+	}, nil
+}
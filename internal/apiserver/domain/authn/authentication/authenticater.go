@@ -2,6 +2,7 @@ package authentication
 
 import (
 	"context"
+	"strings"
 
 	perrors "github.com/FangcunMount/component-base/pkg/errors"
 	"github.com/FangcunMount/component-base/pkg/logger"
@@ -22,6 +23,16 @@ type Authenticater struct {
 	otpVerifier   OTPVerifier
 	idp           IdentityProvider
 	tokenVerifier TokenVerifier
+
+	passwordExpiry PasswordExpiryChecker
+	throttle       LoginThrottle
+	lockout        CredentialLockout
+
+	oidcFederation  OIDCFederation
+	oidcProvisioner OIDCAccountProvisioner
+
+	samlVerifier    SAMLAssertionVerifier
+	samlProvisioner SAMLAccountProvisioner
 }
 
 // NewAuthenticater 创建认证器
@@ -43,11 +54,45 @@ func NewAuthenticater(
 	}
 }
 
+// WithPasswordExpiry 启用密码最长有效期检查（密码登录）
+func (a *Authenticater) WithPasswordExpiry(checker PasswordExpiryChecker) *Authenticater {
+	a.passwordExpiry = checker
+	return a
+}
+
+// WithLoginThrottle 启用按来源 IP 与登录名的失败节流（密码登录）
+func (a *Authenticater) WithLoginThrottle(throttle LoginThrottle) *Authenticater {
+	a.throttle = throttle
+	return a
+}
+
+// WithCredentialLockout 启用凭据失败计数与锁定（密码登录）
+func (a *Authenticater) WithCredentialLockout(lockout CredentialLockout) *Authenticater {
+	a.lockout = lockout
+	return a
+}
+
+// WithOIDCFederation 启用上游 OIDC 联合登录；provisioner 为空时不做即时开通
+func (a *Authenticater) WithOIDCFederation(federation OIDCFederation, provisioner OIDCAccountProvisioner) *Authenticater {
+	a.oidcFederation = federation
+	a.oidcProvisioner = provisioner
+	return a
+}
+
+// WithSAML 启用 SAML 单点登录；provisioner 为空时不做即时开通
+func (a *Authenticater) WithSAML(verifier SAMLAssertionVerifier, provisioner SAMLAccountProvisioner) *Authenticater {
+	a.samlVerifier = verifier
+	a.samlProvisioner = provisioner
+	return a
+}
+
 // Authenticate 认证
 // 统一流程：
 // 1. 根据场景构建领域凭据
-// 2. 获取并创建认证策略
-// 3. 执行认证
+// 2. 节流检查（来源 IP / 登录名）
+// 3. 获取并创建认证策略
+// 4. 执行认证
+// 5. 记录失败计数与凭据锁定
 func (a *Authenticater) Authenticate(ctx context.Context, scenario Scenario, input AuthInput) (AuthDecision, error) {
 	l := logger.L(ctx)
 
@@ -78,6 +123,11 @@ func (a *Authenticater) Authenticate(ctx context.Context, scenario Scenario, inp
 		"claims", make(map[string]any),
 	)
 
+	attempt := loginAttemptOf(credential)
+	if blocked, ok := a.checkThrottle(ctx, attempt); ok {
+		return blocked, nil
+	}
+
 	// 创建认证策略
 	strategy := a.createStrategy(scenario)
 	if strategy == nil {
@@ -109,6 +159,7 @@ func (a *Authenticater) Authenticate(ctx context.Context, scenario Scenario, inp
 		)
 		return AuthDecision{}, err
 	}
+	decision = a.applyGuards(ctx, attempt, decision)
 
 	if !decision.OK {
 		l.Warnw("认证不通过（域层）",
@@ -122,6 +173,9 @@ func (a *Authenticater) Authenticate(ctx context.Context, scenario Scenario, inp
 	if decision.Principal != nil && decision.Principal.TenantID.IsZero() && !input.TenantID.IsZero() {
 		decision.Principal.TenantID = input.TenantID
 	}
+	if decision.Principal != nil {
+		decision.Principal.Device = DeviceInfo{IP: input.RemoteIP, UserAgent: input.UserAgent, Name: input.DeviceName}
+	}
 
 	l.Debugw("认证成功（域层）",
 		"action", logger.ActionLogin,
@@ -134,6 +188,94 @@ func (a *Authenticater) Authenticate(ctx context.Context, scenario Scenario, inp
 	return decision, nil
 }
 
+// loginAttemptOf 提取节流维度；目前仅密码登录按登录名节流，验证码由 ThrottledOTPVerifier 按手机号节流
+func loginAttemptOf(credential AuthCredential) LoginAttempt {
+	pc, ok := credential.(*PasswordCredential)
+	if !ok {
+		return LoginAttempt{}
+	}
+	return LoginAttempt{
+		IP:      pc.RemoteIP,
+		Subject: ThrottleSubject{Kind: ThrottleSubjectUsername, Value: strings.ToLower(strings.TrimSpace(pc.Username))},
+	}
+}
+
+// checkThrottle 节流检查，被拒绝时返回判决；节流存储异常时放行
+func (a *Authenticater) checkThrottle(ctx context.Context, attempt LoginAttempt) (AuthDecision, bool) {
+	if a.throttle == nil || attempt.Subject.IsZero() {
+		return AuthDecision{}, false
+	}
+	verdict, err := a.throttle.Check(ctx, attempt)
+	if err != nil {
+		logger.L(ctx).Warnw("登录节流检查失败，放行",
+			"action", logger.ActionLogin,
+			"error", err.Error(),
+		)
+		return AuthDecision{}, false
+	}
+	if !verdict.Blocked {
+		return AuthDecision{}, false
+	}
+	logger.L(ctx).Warnw("登录失败次数过多，暂时拒绝",
+		"action", logger.ActionLogin,
+		"ip", attempt.IP,
+		"subject", attempt.Subject.Value,
+		"retry_after", verdict.RetryAfter.String(),
+	)
+	return AuthDecision{
+		OK:              false,
+		ErrCode:         ErrThrottled,
+		RetryAfter:      verdict.RetryAfter,
+		CaptchaRequired: verdict.CaptchaRequired,
+	}, true
+}
+
+// applyGuards 根据认证结果更新凭据锁定与节流计数
+// 密码已验证（含密码过期）视为成功：清除失败计数；密码错误时累加计数，凭据锁定期内一律拒绝
+func (a *Authenticater) applyGuards(ctx context.Context, attempt LoginAttempt, decision AuthDecision) AuthDecision {
+	if attempt.Subject.IsZero() {
+		return decision
+	}
+	l := logger.L(ctx)
+	verified := decision.OK || decision.ErrCode == ErrPasswordExpired
+	failed := !decision.OK && decision.ErrCode == ErrInvalidCredential
+
+	if a.lockout != nil && !decision.CredentialID.IsZero() {
+		locked, err := a.lockout.IsLocked(ctx, decision.CredentialID)
+		switch {
+		case err != nil:
+			l.Warnw("查询凭据锁定状态失败", "credential_id", decision.CredentialID.String(), "error", err.Error())
+		case locked:
+			return AuthDecision{OK: false, ErrCode: ErrLocked, CredentialID: decision.CredentialID}
+		case verified:
+			if err := a.lockout.RecordSuccess(ctx, decision.CredentialID); err != nil {
+				l.Warnw("清除凭据失败计数失败", "credential_id", decision.CredentialID.String(), "error", err.Error())
+			}
+		case failed:
+			if _, err := a.lockout.RecordFailure(ctx, decision.CredentialID); err != nil {
+				l.Warnw("记录凭据失败计数失败", "credential_id", decision.CredentialID.String(), "error", err.Error())
+			}
+		}
+	}
+
+	if a.throttle != nil {
+		switch {
+		case verified:
+			if err := a.throttle.Reset(ctx, attempt.Subject); err != nil {
+				l.Warnw("清除登录失败计数失败", "error", err.Error())
+			}
+		case failed:
+			verdict, err := a.throttle.RecordFailure(ctx, attempt)
+			if err != nil {
+				l.Warnw("记录登录失败计数失败", "error", err.Error())
+				break
+			}
+			decision.CaptchaRequired = verdict.CaptchaRequired
+		}
+	}
+	return decision
+}
+
 // BuildCredential 根据认证场景构建领域凭据
 func (a *Authenticater) buildCredential(kind Scenario, input AuthInput) (AuthCredential, error) {
 	builder, err := getCredentialBuilder(kind)
@@ -155,13 +297,21 @@ func (a *Authenticater) buildCredential(kind Scenario, input AuthInput) (AuthCre
 func (f *Authenticater) createStrategy(scenario Scenario) AuthStrategy {
 	switch scenario {
 	case AuthPassword:
-		return NewPasswordAuthStrategy(f.credRepo, f.accountRepo, f.hasher)
+		strategy := NewPasswordAuthStrategy(f.credRepo, f.accountRepo, f.hasher)
+		strategy.expiry = f.passwordExpiry
+		return strategy
 	case AuthPhoneOTP:
 		return NewPhoneOTPAuthStrategy(f.credRepo, f.accountRepo, f.otpVerifier)
 	case AuthWxMinip:
 		return NewOAuthWechatMinipAuthStrategy(f.credRepo, f.accountRepo, f.idp)
+	case AuthWxMP:
+		return NewOAuthWechatMPAuthStrategy(f.credRepo, f.accountRepo, f.idp)
 	case AuthWecom:
 		return NewOAuthWeChatComAuthStrategy(f.credRepo, f.accountRepo, f.idp)
+	case AuthOIDC:
+		return NewOAuthOIDCAuthStrategy(f.credRepo, f.accountRepo, f.oidcFederation, f.oidcProvisioner)
+	case AuthSAML:
+		return NewSAMLAuthStrategy(f.credRepo, f.accountRepo, f.samlVerifier, f.samlProvisioner)
 	case AuthJWTToken:
 		return NewJWTTokenAuthStrategy(f.tokenVerifier)
 	default:
@@ -2,6 +2,7 @@ package authentication_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
@@ -158,3 +159,247 @@ func TestPasswordAuthStrategy_AllCases(t *testing.T) {
 	require.Equal(t, meta.ID(13), d8.Principal.AccountID)
 	require.Equal(t, meta.ID(23), d8.Principal.UserID)
 }
+
+// oauthCredRepoStub 按 idpType 返回 OAuth 绑定
+type oauthCredRepoStub struct {
+	credRepoStub
+	bindings map[string][3]meta.ID
+	lookups  []string
+}
+
+func (s *oauthCredRepoStub) FindOAuthCredential(ctx context.Context, idpType, appID, idpIdentifier string) (meta.ID, meta.ID, meta.ID, error) {
+	s.lookups = append(s.lookups, idpType+"|"+appID+"|"+idpIdentifier)
+	b := s.bindings[idpType]
+	return b[0], b[1], b[2], nil
+}
+
+type wxMPIDPStub struct {
+	identity authentication.WxMPIdentity
+}
+
+func (s *wxMPIDPStub) ExchangeWxMinipCode(ctx context.Context, appID, appSecret, jsCode string) (string, string, error) {
+	return "", "", nil
+}
+func (s *wxMPIDPStub) ExchangeWxMPCode(ctx context.Context, appID, appSecret, code string) (authentication.WxMPIdentity, error) {
+	return s.identity, nil
+}
+func (s *wxMPIDPStub) ExchangeWecomCode(ctx context.Context, corpID, agentID, corpSecret, code string) (string, string, error) {
+	return "", "", nil
+}
+
+func TestOAuthWechatMPAuthStrategy(t *testing.T) {
+	ctx := context.Background()
+	input := authentication.AuthInput{TenantID: meta.ID(1), WxMPAppID: "wx-mp", WxMPAppSecret: "secret", WxMPCode: "code"}
+	acc := &accRepoStub{enabled: true}
+
+	// 1. 已绑定公众号：按 unionID 命中
+	cred1 := &oauthCredRepoStub{bindings: map[string][3]meta.ID{
+		"oauth_wx_mp": {meta.ID(10), meta.ID(20), meta.ID(30)},
+	}}
+	idp := &wxMPIDPStub{identity: authentication.WxMPIdentity{OpenID: "o1", UnionID: "u1", Scope: "snsapi_base"}}
+	d1, err := authentication.NewAuthenticater(cred1, acc, nil, nil, idp, nil).Authenticate(ctx, authentication.AuthWxMP, input)
+	require.NoError(t, err)
+	require.True(t, d1.OK)
+	require.Equal(t, meta.ID(30), d1.CredentialID)
+	require.Equal(t, []string{"oauth_wx_mp|wx-mp|u1"}, cred1.lookups)
+	require.NotContains(t, d1.Principal.Claims, "wx_linked_via")
+
+	// 2. 未绑定公众号：按 unionID 关联小程序账户
+	cred2 := &oauthCredRepoStub{bindings: map[string][3]meta.ID{
+		"oauth_wx_minip": {meta.ID(11), meta.ID(21), meta.ID(31)},
+	}}
+	d2, err := authentication.NewAuthenticater(cred2, acc, nil, nil, idp, nil).Authenticate(ctx, authentication.AuthWxMP, input)
+	require.NoError(t, err)
+	require.True(t, d2.OK)
+	require.Equal(t, meta.ID(11), d2.Principal.AccountID)
+	require.Equal(t, "unionid", d2.Principal.Claims["wx_linked_via"])
+	require.Equal(t, []string{"oauth_wx_mp|wx-mp|u1", "oauth_wx_minip||u1"}, cred2.lookups)
+
+	// 3. 无 unionID 且未绑定 -> no binding，不做跨应用关联
+	cred3 := &oauthCredRepoStub{bindings: map[string][3]meta.ID{
+		"oauth_wx_minip": {meta.ID(11), meta.ID(21), meta.ID(31)},
+	}}
+	idp3 := &wxMPIDPStub{identity: authentication.WxMPIdentity{OpenID: "o3", Scope: "snsapi_base"}}
+	d3, err := authentication.NewAuthenticater(cred3, acc, nil, nil, idp3, nil).Authenticate(ctx, authentication.AuthWxMP, input)
+	require.NoError(t, err)
+	require.False(t, d3.OK)
+	require.Equal(t, authentication.ErrNoBinding, d3.ErrCode)
+	require.Equal(t, []string{"oauth_wx_mp|wx-mp|o3"}, cred3.lookups)
+}
+
+type oidcFederationStub struct {
+	claims map[string]any
+	err    error
+	got    authentication.OIDCCodeExchange
+}
+
+func (s *oidcFederationStub) ExchangeOIDCCode(ctx context.Context, req authentication.OIDCCodeExchange) (map[string]any, error) {
+	s.got = req
+	return s.claims, s.err
+}
+
+type oidcProvisionerStub struct {
+	identities []authentication.OIDCIdentity
+}
+
+func (s *oidcProvisionerStub) ProvisionOIDCAccount(ctx context.Context, identity authentication.OIDCIdentity) (meta.ID, meta.ID, meta.ID, error) {
+	s.identities = append(s.identities, identity)
+	return meta.ID(12), meta.ID(22), meta.ID(32), nil
+}
+
+func TestOAuthOIDCAuthStrategy(t *testing.T) {
+	ctx := context.Background()
+	input := authentication.AuthInput{
+		TenantID:         meta.ID(1),
+		OIDCProvider:     "corp-sso",
+		OIDCIssuer:       "https://sso.example.com",
+		OIDCClientID:     "iam",
+		OIDCCode:         "code",
+		OIDCRedirectURI:  "https://app.example.com/cb",
+		OIDCCodeVerifier: "verifier",
+		OIDCNonce:        "n-1",
+		OIDCClaims:       authentication.OIDCClaimMapping{Username: "preferred_username", Email: "email"},
+	}
+	acc := &accRepoStub{enabled: true}
+	fed := &oidcFederationStub{claims: map[string]any{"sub": "s-1", "preferred_username": "alice", "email": "alice@example.com", "groups": []any{"staff"}}}
+
+	// 1. 已绑定：按 provider slug + sub 命中，返回上游声明
+	cred1 := &oauthCredRepoStub{bindings: map[string][3]meta.ID{
+		"oauth_oidc": {meta.ID(10), meta.ID(20), meta.ID(30)},
+	}}
+	d1, err := authentication.NewAuthenticater(cred1, acc, nil, nil, nil, nil).
+		WithOIDCFederation(fed, nil).
+		Authenticate(ctx, authentication.AuthOIDC, input)
+	require.NoError(t, err)
+	require.True(t, d1.OK)
+	require.Equal(t, meta.ID(30), d1.CredentialID)
+	require.Equal(t, []string{"oauth_oidc|corp-sso|s-1"}, cred1.lookups)
+	require.Equal(t, []string{"oidc"}, d1.Principal.AMR)
+	require.Equal(t, "s-1", d1.Principal.Claims["oidc_subject"])
+	require.Equal(t, fed.claims, d1.ExternalClaims)
+	require.Equal(t, "verifier", fed.got.CodeVerifier)
+	require.Equal(t, "n-1", fed.got.Nonce)
+
+	// 2. 未绑定且未开启即时开通 -> no binding
+	cred2 := &oauthCredRepoStub{bindings: map[string][3]meta.ID{}}
+	prov := &oidcProvisionerStub{}
+	d2, err := authentication.NewAuthenticater(cred2, acc, nil, nil, nil, nil).
+		WithOIDCFederation(fed, prov).
+		Authenticate(ctx, authentication.AuthOIDC, input)
+	require.NoError(t, err)
+	require.False(t, d2.OK)
+	require.Equal(t, authentication.ErrNoBinding, d2.ErrCode)
+	require.Empty(t, prov.identities)
+
+	// 3. 未绑定且开启即时开通 -> 按声明映射开通账户
+	input.OIDCAutoProvision = true
+	d3, err := authentication.NewAuthenticater(cred2, acc, nil, nil, nil, nil).
+		WithOIDCFederation(fed, prov).
+		Authenticate(ctx, authentication.AuthOIDC, input)
+	require.NoError(t, err)
+	require.True(t, d3.OK)
+	require.Equal(t, meta.ID(12), d3.Principal.AccountID)
+	require.Len(t, prov.identities, 1)
+	require.Equal(t, "alice", prov.identities[0].Username)
+	require.Equal(t, "alice@example.com", prov.identities[0].Email)
+
+	// 4. 上游校验失败 -> idp_exchange_failed（业务失败）
+	bad := &oidcFederationStub{err: errors.New("nonce mismatch")}
+	d4, err := authentication.NewAuthenticater(cred1, acc, nil, nil, nil, nil).
+		WithOIDCFederation(bad, nil).
+		Authenticate(ctx, authentication.AuthOIDC, input)
+	require.NoError(t, err)
+	require.False(t, d4.OK)
+	require.Equal(t, authentication.ErrIDPExchangeFailed, d4.ErrCode)
+}
+
+type samlVerifierStub struct {
+	assertion authentication.SAMLAssertion
+	err       error
+	got       authentication.SAMLResponseVerification
+}
+
+func (s *samlVerifierStub) VerifySAMLResponse(ctx context.Context, req authentication.SAMLResponseVerification) (authentication.SAMLAssertion, error) {
+	s.got = req
+	return s.assertion, s.err
+}
+
+type samlProvisionerStub struct {
+	identities []authentication.SAMLIdentity
+}
+
+func (s *samlProvisionerStub) ProvisionSAMLAccount(ctx context.Context, identity authentication.SAMLIdentity) (meta.ID, meta.ID, meta.ID, error) {
+	s.identities = append(s.identities, identity)
+	return meta.ID(13), meta.ID(23), meta.ID(33), nil
+}
+
+func TestSAMLAuthStrategy(t *testing.T) {
+	ctx := context.Background()
+	input := authentication.AuthInput{
+		TenantID:         meta.ID(5),
+		SAMLIdP:          "city-hospital",
+		SAMLIdPEntityID:  "https://idp.hospital.example/saml",
+		SAMLCertificates: []string{"MIIC"},
+		SAMLResponse:     "PHNhbWxwOlJlc3BvbnNlLz4=",
+		SAMLAttributes:   map[string]string{"mail": "email", "memberOf": "groups", "saml_idp": "saml_idp"},
+	}
+	acc := &accRepoStub{enabled: true}
+	verifier := &samlVerifierStub{assertion: authentication.SAMLAssertion{
+		ID:           "_a1",
+		NameID:       "alice@hospital.example",
+		SessionIndex: "_s1",
+		Attributes: map[string][]string{
+			"mail":     {"alice@hospital.example"},
+			"memberOf": {"doctors", "staff"},
+			"saml_idp": {"spoofed"},
+		},
+	}}
+
+	// 1. 已绑定：按 IdP slug + NameID 命中，属性映射写入 Claims，保留键不被覆盖
+	cred1 := &oauthCredRepoStub{bindings: map[string][3]meta.ID{
+		"saml": {meta.ID(10), meta.ID(20), meta.ID(30)},
+	}}
+	d1, err := authentication.NewAuthenticater(cred1, acc, nil, nil, nil, nil).
+		WithSAML(verifier, nil).
+		Authenticate(ctx, authentication.AuthSAML, input)
+	require.NoError(t, err)
+	require.True(t, d1.OK)
+	require.Equal(t, []string{"saml|city-hospital|alice@hospital.example"}, cred1.lookups)
+	require.Equal(t, "https://idp.hospital.example/saml", verifier.got.IdPEntityID)
+	require.Equal(t, []string{"saml"}, d1.Principal.AMR)
+	require.Equal(t, meta.ID(5), d1.Principal.TenantID)
+	require.Equal(t, "alice@hospital.example", d1.Principal.Claims["email"])
+	require.Equal(t, []string{"doctors", "staff"}, d1.Principal.Claims["groups"])
+	require.Equal(t, "city-hospital", d1.Principal.Claims["saml_idp"])
+	require.Equal(t, "_s1", d1.Principal.Claims["saml_session_index"])
+
+	// 2. 未绑定且开启即时开通 -> 按映射后的 Claims 开通
+	input.SAMLAutoProvision = true
+	prov := &samlProvisionerStub{}
+	cred2 := &oauthCredRepoStub{bindings: map[string][3]meta.ID{}}
+	d2, err := authentication.NewAuthenticater(cred2, acc, nil, nil, nil, nil).
+		WithSAML(verifier, prov).
+		Authenticate(ctx, authentication.AuthSAML, input)
+	require.NoError(t, err)
+	require.True(t, d2.OK)
+	require.Equal(t, meta.ID(13), d2.Principal.AccountID)
+	require.Len(t, prov.identities, 1)
+	require.Equal(t, "alice@hospital.example", prov.identities[0].Email)
+	require.Equal(t, meta.ID(5), prov.identities[0].TenantID)
+
+	// 3. 重放与校验失败区分错误码
+	replayed := &samlVerifierStub{err: authentication.ErrSAMLReplay}
+	d3, err := authentication.NewAuthenticater(cred1, acc, nil, nil, nil, nil).
+		WithSAML(replayed, nil).
+		Authenticate(ctx, authentication.AuthSAML, input)
+	require.NoError(t, err)
+	require.Equal(t, authentication.ErrAssertionReplayed, d3.ErrCode)
+
+	invalid := &samlVerifierStub{err: errors.New("audience mismatch")}
+	d4, err := authentication.NewAuthenticater(cred1, acc, nil, nil, nil, nil).
+		WithSAML(invalid, nil).
+		Authenticate(ctx, authentication.AuthSAML, input)
+	require.NoError(t, err)
+	require.False(t, d4.OK)
+	require.Equal(t, authentication.ErrAssertionInvalid, d4.ErrCode)
+}
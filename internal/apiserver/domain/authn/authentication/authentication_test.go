@@ -52,6 +52,7 @@ func TestAuthenticater_CreateStrategyMapping(t *testing.T) {
 	assert.NotNil(t, a.createStrategy(AuthPassword))
 	assert.NotNil(t, a.createStrategy(AuthPhoneOTP))
 	assert.NotNil(t, a.createStrategy(AuthWxMinip))
+	assert.NotNil(t, a.createStrategy(AuthWxMP))
 	assert.NotNil(t, a.createStrategy(AuthWecom))
 	assert.NotNil(t, a.createStrategy(AuthJWTToken))
 
@@ -2,6 +2,7 @@ package authentication
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
@@ -28,6 +29,20 @@ type PasswordHasher interface {
 	Pepper() string
 }
 
+// PasswordExpiryChecker 密码有效期检查（可选）
+// 职责：判断密码是否已超过租户策略规定的最长有效期，过期时需先修改密码再登录
+type PasswordExpiryChecker interface {
+	IsPasswordExpired(ctx context.Context, tenantID, credentialID meta.ID) (bool, error)
+}
+
+// CredentialLockout 凭据失败计数与锁定（可选）
+// 职责：认证失败时累加凭据失败次数并按锁定策略锁定，认证成功时清零；锁定期内无论密码对错均拒绝
+type CredentialLockout interface {
+	IsLocked(ctx context.Context, credentialID meta.ID) (bool, error)
+	RecordFailure(ctx context.Context, credentialID meta.ID) (locked bool, err error)
+	RecordSuccess(ctx context.Context, credentialID meta.ID) error
+}
+
 // OTPVerifier OTP验证服务（一次性密码验证）
 // 职责：验证OTP并消费（防止重放）
 type OTPVerifier interface {
@@ -51,9 +66,11 @@ type OTPSendGate interface {
 	TryAcquire(ctx context.Context, phoneE164, scene string, cooldown time.Duration) (bool, error)
 }
 
-// SMSSender 登录 OTP 触达通道：实现通常为「投递 MQ / 事件」，由下游真正发短信，IAM 不直连运营商
+// SMSSender 验证码触达通道：实现通常为「投递 MQ / 事件」，由下游真正发短信，IAM 不直连运营商
 type SMSSender interface {
 	SendLoginOTP(ctx context.Context, phoneE164, code string) error
+	// SendOTP 按场景投递验证码（如 reset_password），下游可据 scene 选择短信模板
+	SendOTP(ctx context.Context, phoneE164, scene, code string) error
 }
 
 // IdentityProvider 身份提供商服务（OAuth/OIDC）
@@ -64,12 +81,103 @@ type IdentityProvider interface {
 	// 返回：OpenID、UnionID（可选）
 	ExchangeWxMinipCode(ctx context.Context, appID, appSecret, jsCode string) (openID, unionID string, err error)
 
+	// ExchangeWxMPCode 微信公众号网页授权 code 换 用户身份
+	// snsapi_base 仅返回 OpenID（公众号已绑定开放平台时含 UnionID）；snsapi_userinfo 额外返回昵称、头像
+	ExchangeWxMPCode(ctx context.Context, appID, appSecret, code string) (WxMPIdentity, error)
+
 	// ExchangeWecomCode 企业微信 code 换 用户信息
 	// 参数：corpID 企业ID, agentID 应用ID, corpSecret 应用密钥, code 登录凭证
 	// 返回：OpenUserID、UserID
 	ExchangeWecomCode(ctx context.Context, corpID, agentID, corpSecret, code string) (openUserID, userID string, err error)
 }
 
+// WxMPIdentity 公众号网页授权换取的用户身份
+type WxMPIdentity struct {
+	OpenID    string
+	UnionID   string
+	Scope     string // snsapi_base | snsapi_userinfo
+	Nickname  string // 仅 snsapi_userinfo
+	AvatarURL string // 仅 snsapi_userinfo
+}
+
+// OIDCFederation 上游 OIDC 身份联合服务
+// 职责：授权码换取令牌，校验 id_token（签名、iss、aud、nonce）并返回声明
+type OIDCFederation interface {
+	ExchangeOIDCCode(ctx context.Context, req OIDCCodeExchange) (claims map[string]any, err error)
+}
+
+// OIDCCodeExchange 授权码换取参数
+type OIDCCodeExchange struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string // PKCE，可选
+	Nonce        string // 授权请求下发的 nonce，id_token 必须回传
+}
+
+// OIDCAccountProvisioner 联合登录即时开通（JIT）
+// 职责：上游身份首次登录且未绑定时创建用户、账户与凭据
+type OIDCAccountProvisioner interface {
+	ProvisionOIDCAccount(ctx context.Context, identity OIDCIdentity) (accountID, userID, credentialID meta.ID, err error)
+}
+
+// OIDCIdentity 按声明映射解析出的上游身份
+type OIDCIdentity struct {
+	Provider string // 提供商 slug
+	Subject  string
+	Username string
+	Email    string
+	Name     string
+	Phone    string
+	Claims   map[string]any
+}
+
+// SAMLAssertionVerifier SAML 断言校验服务
+// 职责：校验 SAMLResponse（签名、Issuer、Audience、有效期、InResponseTo）并消费断言 ID 防重放
+type SAMLAssertionVerifier interface {
+	VerifySAMLResponse(ctx context.Context, req SAMLResponseVerification) (SAMLAssertion, error)
+}
+
+// ErrSAMLReplay 断言 ID 已被消费（重放）
+var ErrSAMLReplay = errors.New("saml assertion replayed")
+
+// SAMLResponseVerification SAMLResponse 校验参数
+type SAMLResponseVerification struct {
+	IdP          string   // IdP 登记 slug
+	IdPEntityID  string   // 期望的 Issuer
+	Certificates []string // IdP 签名证书（Base64 DER）
+	Response     string   // Base64 SAMLResponse
+}
+
+// SAMLAssertion 校验通过的断言内容
+type SAMLAssertion struct {
+	ID           string
+	NameID       string
+	NameIDFormat string
+	SessionIndex string
+	Attributes   map[string][]string
+}
+
+// SAMLAccountProvisioner SAML 单点登录即时开通（JIT）
+// 职责：IdP 用户首次登录且未绑定时创建用户、账户与凭据
+type SAMLAccountProvisioner interface {
+	ProvisionSAMLAccount(ctx context.Context, identity SAMLIdentity) (accountID, userID, credentialID meta.ID, err error)
+}
+
+// SAMLIdentity 按属性映射解析出的 IdP 身份
+type SAMLIdentity struct {
+	IdP      string // IdP 登记 slug
+	TenantID meta.ID
+	NameID   string
+	Username string
+	Email    string
+	Name     string
+	Phone    string
+	Claims   map[string]any
+}
+
 // TokenVerifier JWT令牌验证服务
 // 职责：验证JWT访问令牌的有效性
 type TokenVerifier interface {
@@ -1,12 +1,17 @@
 package authentication
 
-import "github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+import (
+	"time"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
 
 // AuthInput 统一认证输入参数（应用层 -> 领域层）
 type AuthInput struct {
-	TenantID  meta.ID
-	RemoteIP  string
-	UserAgent string
+	TenantID   meta.ID
+	RemoteIP   string
+	UserAgent  string
+	DeviceName string
 
 	// password
 	Username string
@@ -21,6 +26,11 @@ type AuthInput struct {
 	WxAppSecret string
 	WxJsCode    string
 
+	// wx_mp（公众号网页授权，snsapi_base / snsapi_userinfo）
+	WxMPAppID     string
+	WxMPAppSecret string
+	WxMPCode      string
+
 	// wecom
 	WecomCorpID     string
 	WecomAgentID    string
@@ -28,6 +38,26 @@ type AuthInput struct {
 	WecomCode       string
 	WecomState      string
 
+	// oauth_oidc（提供商配置由应用层按 slug 解析后填入）
+	OIDCProvider      string
+	OIDCIssuer        string
+	OIDCClientID      string
+	OIDCClientSecret  string
+	OIDCCode          string
+	OIDCRedirectURI   string
+	OIDCCodeVerifier  string
+	OIDCNonce         string
+	OIDCClaims        OIDCClaimMapping
+	OIDCAutoProvision bool
+
+	// saml（IdP 登记由应用层按 slug 解析后填入）
+	SAMLIdP           string
+	SAMLIdPEntityID   string
+	SAMLCertificates  []string          // IdP 签名证书（Base64 DER）
+	SAMLResponse      string            // HTTP-POST 绑定回传的 Base64 SAMLResponse
+	SAMLAttributes    map[string]string // SAML 属性名 -> Principal.Claims 键
+	SAMLAutoProvision bool
+
 	// jwt_token
 	AccessToken string
 }
@@ -42,6 +72,10 @@ const (
 	ErrNoBinding          ErrCode = "no_binding"
 	ErrLocked             ErrCode = "locked"
 	ErrDisabled           ErrCode = "disabled"
+	ErrPasswordExpired    ErrCode = "password_expired"
+	ErrThrottled          ErrCode = "throttled"
+	ErrAssertionInvalid   ErrCode = "assertion_invalid"
+	ErrAssertionReplayed  ErrCode = "assertion_replayed"
 )
 
 // 策略的判决单（业务失败走 ErrCode，系统异常用 error）
@@ -55,4 +89,11 @@ type AuthDecision struct {
 	ShouldRotate bool
 	NewMaterial  []byte
 	NewAlgo      *string
+
+	// 节流：失败次数达到阈值时提示客户端先完成人机验证
+	CaptchaRequired bool
+	RetryAfter      time.Duration // ErrThrottled 时建议的重试等待时间
+
+	// ExternalClaims 上游 IdP 返回的声明（仅联合登录），供应用层做角色映射，不进入令牌
+	ExternalClaims map[string]any
 }
@@ -20,7 +20,7 @@ type CredentialRepository interface {
 	// 返回：账户ID、用户ID、凭据ID
 	FindPhoneOTPCredential(ctx context.Context, phoneE164 string) (accountID, userID, credentialID meta.ID, err error)
 	// FindOAuthCredential 根据身份提供商标识查找OAuth凭据绑定
-	// idpType: "wx_minip" | "wecom" | ...
+	// idpType: "oauth_wx_minip" | "oauth_wx_mp" | "oauth_wecom" | ...
 	// idpIdentifier: OpenID/UnionID/UserID
 	// 返回：账户ID、用户ID、凭据ID
 	FindOAuthCredential(ctx context.Context, idpType, appID, idpIdentifier string) (accountID, userID, credentialID meta.ID, err error)
@@ -0,0 +1,142 @@
+package authentication
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/logger"
+)
+
+// ==================== 登录失败节流 ====================
+
+// ThrottleSubjectKind 节流主体类型
+type ThrottleSubjectKind string
+
+const (
+	ThrottleSubjectUsername ThrottleSubjectKind = "username" // 密码登录名
+	ThrottleSubjectPhone    ThrottleSubjectKind = "phone"    // 手机号（OTP 校验）
+)
+
+// ThrottleSubject 节流主体：同一登录名/手机号的失败在窗口内累计，跨 IP 生效
+type ThrottleSubject struct {
+	Kind  ThrottleSubjectKind
+	Value string
+}
+
+// IsZero 主体是否为空
+func (s ThrottleSubject) IsZero() bool {
+	return s.Kind == "" || strings.TrimSpace(s.Value) == ""
+}
+
+// LoginAttempt 一次登录（或验证码校验）尝试的节流维度
+type LoginAttempt struct {
+	IP      string // 来源 IP，可为空
+	Subject ThrottleSubject
+}
+
+// ThrottleCounts 窗口内的失败计数
+type ThrottleCounts struct {
+	IPFailures      int
+	SubjectFailures int
+	RetryAfter      time.Duration // 各维度计数窗口剩余时间的最大值
+}
+
+// ThrottleVerdict 节流判定
+type ThrottleVerdict struct {
+	Blocked         bool          // 是否拒绝本次尝试
+	CaptchaRequired bool          // 是否需要客户端完成人机验证
+	RetryAfter      time.Duration // Blocked 时建议的重试等待时间
+}
+
+// ThrottlePolicy 登录节流策略（值对象）
+// 与凭据锁定（auth_credentials.locked_until）互补：锁定针对单个凭据，节流针对来源 IP 与登录名，
+// 用于抵御跨大量账户的撞库以及验证码爆破
+type ThrottlePolicy struct {
+	Window              time.Duration // 计数窗口
+	MaxFailuresPerIP    int           // 同一 IP 窗口内最大失败次数，0 不限制
+	MaxFailuresPerUser  int           // 同一登录名窗口内最大失败次数，0 不限制
+	MaxFailuresPerPhone int           // 同一手机号窗口内最大验证码校验失败次数，0 不限制
+	CaptchaAfter        int           // 登录名/手机号失败达到该次数后要求人机验证，0 不启用
+}
+
+// Evaluate 根据计数给出判定
+func (p ThrottlePolicy) Evaluate(kind ThrottleSubjectKind, counts ThrottleCounts) ThrottleVerdict {
+	subjectMax := p.MaxFailuresPerUser
+	if kind == ThrottleSubjectPhone {
+		subjectMax = p.MaxFailuresPerPhone
+	}
+
+	verdict := ThrottleVerdict{
+		CaptchaRequired: p.CaptchaAfter > 0 && counts.SubjectFailures >= p.CaptchaAfter,
+	}
+	if (p.MaxFailuresPerIP > 0 && counts.IPFailures >= p.MaxFailuresPerIP) ||
+		(subjectMax > 0 && counts.SubjectFailures >= subjectMax) {
+		verdict.Blocked = true
+		verdict.RetryAfter = counts.RetryAfter
+	}
+	return verdict
+}
+
+// LoginThrottle 登录失败计数（Driven Port）
+// 实现通常基于 Redis 固定窗口计数，多实例共享
+type LoginThrottle interface {
+	// Check 返回当前判定（不计数）
+	Check(ctx context.Context, attempt LoginAttempt) (ThrottleVerdict, error)
+	// RecordFailure 记录一次失败，返回记录后的判定
+	RecordFailure(ctx context.Context, attempt LoginAttempt) (ThrottleVerdict, error)
+	// Reset 清除主体计数（认证成功或管理员解锁），IP 计数保留至窗口结束
+	Reset(ctx context.Context, subject ThrottleSubject) error
+}
+
+// ==================== 验证码校验节流 ====================
+
+// ThrottledOTPVerifier 为 OTPVerifier 增加按手机号的失败计数
+// 超过阈值后窗口内直接判定失败（不再触达存储），成功后清零；节流存储异常时放行
+type ThrottledOTPVerifier struct {
+	inner    OTPVerifier
+	throttle LoginThrottle
+}
+
+var _ OTPVerifier = (*ThrottledOTPVerifier)(nil)
+
+// NewThrottledOTPVerifier 包装 OTP 校验器；throttle 为空时返回原校验器
+func NewThrottledOTPVerifier(inner OTPVerifier, throttle LoginThrottle) OTPVerifier {
+	if throttle == nil {
+		return inner
+	}
+	return &ThrottledOTPVerifier{inner: inner, throttle: throttle}
+}
+
+// VerifyAndConsume 校验并消费 OTP
+func (v *ThrottledOTPVerifier) VerifyAndConsume(ctx context.Context, phoneE164, scene, code string) bool {
+	l := logger.L(ctx)
+	attempt := LoginAttempt{Subject: ThrottleSubject{Kind: ThrottleSubjectPhone, Value: phoneE164}}
+
+	verdict, err := v.throttle.Check(ctx, attempt)
+	if err != nil {
+		l.Warnw("验证码节流检查失败，放行",
+			"scene", scene,
+			"error", err.Error(),
+		)
+	} else if verdict.Blocked {
+		l.Warnw("验证码校验失败次数过多，拒绝校验",
+			"scene", scene,
+			"phone", phoneE164,
+			"retry_after", verdict.RetryAfter.String(),
+		)
+		return false
+	}
+
+	if v.inner.VerifyAndConsume(ctx, phoneE164, scene, code) {
+		if err := v.throttle.Reset(ctx, attempt.Subject); err != nil {
+			l.Warnw("清除验证码失败计数失败", "error", err.Error())
+		}
+		return true
+	}
+
+	if _, err := v.throttle.RecordFailure(ctx, attempt); err != nil {
+		l.Warnw("记录验证码失败计数失败", "error", err.Error())
+	}
+	return false
+}
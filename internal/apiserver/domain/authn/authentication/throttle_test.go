@@ -0,0 +1,165 @@
+package authentication_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// throttleStub 内存节流计数，按 ThrottlePolicy 判定
+type throttleStub struct {
+	policy   authentication.ThrottlePolicy
+	subjects map[authentication.ThrottleSubject]int
+	ips      map[string]int
+}
+
+func newThrottleStub(policy authentication.ThrottlePolicy) *throttleStub {
+	return &throttleStub{
+		policy:   policy,
+		subjects: map[authentication.ThrottleSubject]int{},
+		ips:      map[string]int{},
+	}
+}
+
+func (s *throttleStub) verdict(attempt authentication.LoginAttempt) authentication.ThrottleVerdict {
+	return s.policy.Evaluate(attempt.Subject.Kind, authentication.ThrottleCounts{
+		IPFailures:      s.ips[attempt.IP],
+		SubjectFailures: s.subjects[attempt.Subject],
+		RetryAfter:      time.Minute,
+	})
+}
+
+func (s *throttleStub) Check(_ context.Context, attempt authentication.LoginAttempt) (authentication.ThrottleVerdict, error) {
+	return s.verdict(attempt), nil
+}
+
+func (s *throttleStub) RecordFailure(_ context.Context, attempt authentication.LoginAttempt) (authentication.ThrottleVerdict, error) {
+	s.subjects[attempt.Subject]++
+	if attempt.IP != "" {
+		s.ips[attempt.IP]++
+	}
+	return s.verdict(attempt), nil
+}
+
+func (s *throttleStub) Reset(_ context.Context, subject authentication.ThrottleSubject) error {
+	delete(s.subjects, subject)
+	return nil
+}
+
+type lockoutStub struct {
+	failures  int
+	threshold int
+	successes int
+}
+
+func (s *lockoutStub) IsLocked(context.Context, meta.ID) (bool, error) {
+	return s.threshold > 0 && s.failures >= s.threshold, nil
+}
+
+func (s *lockoutStub) RecordFailure(context.Context, meta.ID) (bool, error) {
+	s.failures++
+	return s.failures >= s.threshold, nil
+}
+
+func (s *lockoutStub) RecordSuccess(context.Context, meta.ID) error {
+	s.failures = 0
+	s.successes++
+	return nil
+}
+
+type otpStub struct{ code string }
+
+func (s otpStub) VerifyAndConsume(_ context.Context, _, _, code string) bool { return code == s.code }
+
+func TestThrottlePolicy_Evaluate(t *testing.T) {
+	p := authentication.ThrottlePolicy{MaxFailuresPerIP: 10, MaxFailuresPerUser: 5, MaxFailuresPerPhone: 3, CaptchaAfter: 2}
+
+	v := p.Evaluate(authentication.ThrottleSubjectUsername, authentication.ThrottleCounts{SubjectFailures: 1})
+	assert.False(t, v.Blocked)
+	assert.False(t, v.CaptchaRequired)
+
+	v = p.Evaluate(authentication.ThrottleSubjectUsername, authentication.ThrottleCounts{SubjectFailures: 3})
+	assert.False(t, v.Blocked)
+	assert.True(t, v.CaptchaRequired)
+
+	// 手机号使用独立阈值
+	v = p.Evaluate(authentication.ThrottleSubjectPhone, authentication.ThrottleCounts{SubjectFailures: 3, RetryAfter: time.Minute})
+	assert.True(t, v.Blocked)
+	assert.Equal(t, time.Minute, v.RetryAfter)
+
+	v = p.Evaluate(authentication.ThrottleSubjectUsername, authentication.ThrottleCounts{IPFailures: 10})
+	assert.True(t, v.Blocked)
+}
+
+func TestAuthenticater_ThrottleAndLockout(t *testing.T) {
+	ctx := context.Background()
+	acc := &accRepoStub{accountID: meta.ID(12), userID: meta.ID(22), enabled: true}
+	cred := &credRepoStub{credID: meta.ID(100), stored: "secret"}
+	throttle := newThrottleStub(authentication.ThrottlePolicy{MaxFailuresPerUser: 4, CaptchaAfter: 2})
+	lockout := &lockoutStub{threshold: 3}
+	a := authentication.NewAuthenticater(cred, acc, &hasherStub{}, nil, nil, nil).
+		WithLoginThrottle(throttle).
+		WithCredentialLockout(lockout)
+
+	login := func(password string) authentication.AuthDecision {
+		d, err := a.Authenticate(ctx, authentication.AuthPassword, authentication.AuthInput{
+			TenantID: meta.ID(1), Username: " Alice ", Password: password, RemoteIP: "10.0.0.1",
+		})
+		require.NoError(t, err)
+		return d
+	}
+
+	d := login("wrong")
+	require.Equal(t, authentication.ErrInvalidCredential, d.ErrCode)
+	assert.False(t, d.CaptchaRequired)
+
+	d = login("wrong")
+	require.Equal(t, authentication.ErrInvalidCredential, d.ErrCode)
+	assert.True(t, d.CaptchaRequired)
+
+	// 第三次失败触发凭据锁定，锁定期内正确密码也被拒绝
+	d = login("wrong")
+	require.Equal(t, authentication.ErrInvalidCredential, d.ErrCode)
+	d = login("secret")
+	require.Equal(t, authentication.ErrLocked, d.ErrCode)
+	assert.Zero(t, lockout.successes)
+
+	// 登录名失败次数（按小写归一）达到上限后直接拒绝，不再触达策略
+	throttle.subjects[authentication.ThrottleSubject{Kind: authentication.ThrottleSubjectUsername, Value: "alice"}] = 4
+	d = login("secret")
+	require.Equal(t, authentication.ErrThrottled, d.ErrCode)
+	assert.Equal(t, time.Minute, d.RetryAfter)
+
+	// 解锁后成功登录清除计数
+	lockout.failures = 0
+	throttle.subjects = map[authentication.ThrottleSubject]int{}
+	d = login("secret")
+	require.True(t, d.OK)
+	assert.Equal(t, 1, lockout.successes)
+}
+
+func TestThrottledOTPVerifier(t *testing.T) {
+	ctx := context.Background()
+	throttle := newThrottleStub(authentication.ThrottlePolicy{MaxFailuresPerPhone: 2})
+	v := authentication.NewThrottledOTPVerifier(otpStub{code: "123456"}, throttle)
+	phone := authentication.ThrottleSubject{Kind: authentication.ThrottleSubjectPhone, Value: "+8613800138000"}
+
+	assert.False(t, v.VerifyAndConsume(ctx, phone.Value, "login", "000000"))
+	assert.True(t, v.VerifyAndConsume(ctx, phone.Value, "login", "123456"))
+	assert.Zero(t, throttle.subjects[phone])
+
+	assert.False(t, v.VerifyAndConsume(ctx, phone.Value, "login", "000000"))
+	assert.False(t, v.VerifyAndConsume(ctx, phone.Value, "login", "000001"))
+	// 达到上限后正确验证码也被拒绝
+	assert.False(t, v.VerifyAndConsume(ctx, phone.Value, "login", "123456"))
+
+	// 未配置节流时返回原校验器
+	inner := otpStub{code: "1"}
+	assert.Equal(t, authentication.OTPVerifier(inner), authentication.NewThrottledOTPVerifier(inner, nil))
+}
@@ -9,8 +9,11 @@ const (
 	AuthPassword Scenario = "password"
 	AuthPhoneOTP Scenario = "phone_otp"
 	AuthWxMinip  Scenario = "oauth_wx_minip"
+	AuthWxMP     Scenario = "oauth_wx_mp" // 微信公众号网页授权
 	AuthWecom    Scenario = "oauth_wecom"
-	AuthJWTToken Scenario = "jwt_token" // JWT Token 认证
+	AuthOIDC     Scenario = "oauth_oidc" // 上游 OIDC 授权码联合登录
+	AuthSAML     Scenario = "saml"       // SAML 2.0 SP 单点登录
+	AuthJWTToken Scenario = "jwt_token"  // JWT Token 认证
 )
 
 // AMR（认证方法引用），用于审计与 Step-Up
@@ -21,6 +24,8 @@ const (
 	AMROTP      AMR = "otp"
 	AMRWx       AMR = "wechat"
 	AMRWecom    AMR = "wecom"
+	AMROIDC     AMR = "oidc"
+	AMRSAML     AMR = "saml"
 	AMRJWTToken AMR = "jwt" // JWT Token 认证方法
 )
 
@@ -32,4 +37,12 @@ type Principal struct {
 	SessionID string
 	AMR       []string
 	Claims    map[string]any
+	Device    DeviceInfo // 登录时采集的设备信息，仅记入会话，不进入令牌
+}
+
+// DeviceInfo 登录设备信息，用于"我的设备"展示与远程登出
+type DeviceInfo struct {
+	IP        string
+	UserAgent string
+	Name      string // 客户端上报的设备名，缺省时由 UA 推断
 }
@@ -1,6 +1,8 @@
 package credential
 
 import (
+	"time"
+
 	"github.com/FangcunMount/component-base/pkg/errors"
 	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
 )
@@ -53,6 +55,7 @@ func (b *binder) Bind(spec BindSpec) (*Credential, error) {
 		// password 类型不需要 IDP
 		cred.IDP = nil
 		cred.AppID = nil
+		cred.MarkPasswordChanged(time.Now())
 
 	case CredPhoneOTP:
 		// phone_otp 需要 IDPIdentifier（手机号）
@@ -63,7 +66,7 @@ func (b *binder) Bind(spec BindSpec) (*Credential, error) {
 		cred.Material = nil
 		cred.Algo = nil
 
-	case CredOAuthWxMinip, CredOAuthWecom:
+	case CredOAuthWxMinip, CredOAuthWxMP, CredOAuthWecom, CredOAuthOIDC, CredSAML:
 		// OAuth 类型需要 IDPIdentifier 和 AppID
 		if spec.IDPIdentifier == "" {
 			return nil, errors.WithCode(code.ErrInvalidCredential, "OAuth credential requires IDP identifier")
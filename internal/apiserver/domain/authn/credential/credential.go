@@ -12,9 +12,9 @@ type Credential struct {
 	AccountID meta.ID
 
 	// —— 外部身份三元组：仅 OAuth/Phone 有值；password 留空 —— //
-	IDP           *string // "wechat"|"wecom"|"phone" | nil(本地)
-	IDPIdentifier string  // unionid | openid@appid | open_userid | +E164 | ""(password)
-	AppID         *string // wechat=appid | wecom=corp_id | nil(本地)
+	IDP           *string // "wechat"|"wechat_mp"|"wecom"|"oidc"|"saml"|"phone" | nil(本地)
+	IDPIdentifier string  // unionid | openid@appid | open_userid | sub | NameID | +E164 | ""(password)
+	AppID         *string // wechat=appid | wecom=corp_id | oidc=provider slug | saml=idp slug | nil(本地)
 
 	// —— 三件套（仅 password 会使用；其余类型为空） —— //
 	Material   []byte  // PHC 哈希（password）；其余类型 NULL
@@ -27,6 +27,9 @@ type Credential struct {
 	LockedUntil    *time.Time // 锁定截止时间
 	LastSuccessAt  *time.Time // 最近成功时间
 	LastFailureAt  *time.Time // 最近失败时间
+
+	// PasswordChangedAt 最近一次设置密码的时间（仅 password，用于密码最长有效期）
+	PasswordChangedAt *time.Time
 }
 
 // ==================== 状态查询方法 ====================
@@ -68,6 +71,29 @@ func (c *Credential) IsPhoneOTPType() bool {
 	return c.IDP != nil && *c.IDP == "phone" && c.IDPIdentifier != ""
 }
 
+// Type 按外部身份三元组推断凭据类型（auth_credentials.type）
+func (c *Credential) Type() CredentialType {
+	if c.IDP == nil {
+		return CredPassword
+	}
+	switch *c.IDP {
+	case "phone":
+		return CredPhoneOTP
+	case "wechat":
+		return CredOAuthWxMinip
+	case "wechat_mp":
+		return CredOAuthWxMP
+	case "wecom":
+		return CredOAuthWecom
+	case "oidc":
+		return CredOAuthOIDC
+	case "saml":
+		return CredSAML
+	default:
+		return CredPassword
+	}
+}
+
 // ==================== 行为方法 ====================
 
 // RecordSuccess 记录认证成功
@@ -106,8 +132,10 @@ func (c *Credential) ApplyLockPolicy(now time.Time, policy LockoutPolicy) bool {
 		return false
 	}
 
-	if c.ShouldLock(policy.Threshold) {
-		until := now.Add(policy.LockDuration)
+	if policy.Threshold > 0 && c.ShouldLock(policy.Threshold) {
+		// 失败计数在锁定到期后不清零，据此得出第几次锁定
+		level := c.FailedAttempts / policy.Threshold
+		until := now.Add(policy.DurationFor(level))
 		c.LockUntil(until)
 		return true
 	}
@@ -135,6 +163,12 @@ func (c *Credential) RotateMaterial(newMaterial []byte, newAlgo *string) {
 	}
 }
 
+// MarkPasswordChanged 记录密码设置时间
+// 仅在用户设置新密码时调用；登录时的条件再哈希不改变密码年龄
+func (c *Credential) MarkPasswordChanged(now time.Time) {
+	c.PasswordChangedAt = &now
+}
+
 // UpdateIDPIdentifier 更新 IDP 标识符（用于 OAuth 场景的 unionid 更新等）
 func (c *Credential) UpdateIDPIdentifier(identifier string) {
 	c.IDPIdentifier = identifier
@@ -149,12 +183,14 @@ func (c *Credential) UpdateParams(params []byte) {
 
 // NewPasswordCredential 创建密码类型凭据
 func NewPasswordCredential(accountID meta.ID, material []byte, algo string) *Credential {
+	now := time.Now()
 	return &Credential{
-		AccountID:      accountID,
-		Material:       material,
-		Algo:           &algo,
-		Status:         CredStatusEnabled,
-		FailedAttempts: 0,
+		AccountID:         accountID,
+		Material:          material,
+		Algo:              &algo,
+		Status:            CredStatusEnabled,
+		FailedAttempts:    0,
+		PasswordChangedAt: &now,
 	}
 }
 
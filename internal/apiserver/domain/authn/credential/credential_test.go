@@ -78,3 +78,25 @@ func TestCredential_UpdateAndRotate(t *testing.T) {
 	c.UpdateParams(params)
 	assert.Equal(t, params, c.ParamsJSON)
 }
+
+func TestLockoutPolicy_ExponentialBackoff(t *testing.T) {
+	policy := LockoutPolicy{Enabled: true, Threshold: 3, LockDuration: time.Minute, BackoffFactor: 2, MaxLockDuration: 10 * time.Minute}
+	assert.Equal(t, time.Minute, policy.DurationFor(1))
+	assert.Equal(t, 4*time.Minute, policy.DurationFor(3))
+	assert.Equal(t, 10*time.Minute, policy.DurationFor(8))
+	assert.Equal(t, time.Minute, LockoutPolicy{LockDuration: time.Minute}.DurationFor(5))
+
+	now := time.Now()
+	c := NewPasswordCredential(meta.FromUint64(1), []byte("hash"), "argon2id")
+	c.FailedAttempts = 2
+	assert.False(t, c.ApplyLockPolicy(now, policy))
+
+	c.FailedAttempts = 3
+	require.True(t, c.ApplyLockPolicy(now, policy))
+	assert.Equal(t, now.Add(time.Minute), *c.LockedUntil)
+
+	// 锁定到期后继续失败，累计达到 2 倍阈值时锁定时长翻倍
+	c.FailedAttempts = 6
+	require.True(t, c.ApplyLockPolicy(now, policy))
+	assert.Equal(t, now.Add(2*time.Minute), *c.LockedUntil)
+}
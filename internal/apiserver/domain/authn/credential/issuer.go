@@ -23,8 +23,17 @@ type Issuer interface {
 	// IssueWechatMinip 颁发微信小程序凭据
 	IssueWechatMinip(ctx context.Context, req IssueOAuthRequest) (*Credential, error)
 
+	// IssueWechatMP 颁发微信公众号凭据
+	IssueWechatMP(ctx context.Context, req IssueOAuthRequest) (*Credential, error)
+
 	// IssueWecom 颁发企业微信凭据
 	IssueWecom(ctx context.Context, req IssueOAuthRequest) (*Credential, error)
+
+	// IssueOIDC 颁发上游 OIDC 联合登录凭据
+	IssueOIDC(ctx context.Context, req IssueOAuthRequest) (*Credential, error)
+
+	// IssueSAML 颁发 SAML 单点登录凭据
+	IssueSAML(ctx context.Context, req IssueOAuthRequest) (*Credential, error)
 }
 
 // ==================== 颁发请求 DTOs ====================
@@ -178,6 +187,40 @@ func (i *issuer) IssueWechatMinip(ctx context.Context, req IssueOAuthRequest) (*
 	return credential, nil
 }
 
+// IssueWechatMP 颁发微信公众号凭据（创建凭据实体，不包含持久化）
+func (i *issuer) IssueWechatMP(ctx context.Context, req IssueOAuthRequest) (*Credential, error) {
+	// 参数验证
+	if req.AccountID.IsZero() {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "account_id is required")
+	}
+	if req.IDPIdentifier == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "idp_identifier is required")
+	}
+	if req.AppID == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "app_id is required")
+	}
+
+	// 设置默认 IDP
+	if req.IDP == "" {
+		req.IDP = "wechat_mp"
+	}
+
+	credential, err := i.binder.Bind(BindSpec{
+		AccountID:     req.AccountID,
+		Type:          CredOAuthWxMP,
+		IDP:           &req.IDP,
+		IDPIdentifier: req.IDPIdentifier,
+		AppID:         &req.AppID,
+		ParamsJSON:    req.ParamsJSON,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 注意：凭据持久化由应用层负责
+	return credential, nil
+}
+
 // IssueWecom 颁发企业微信凭据（创建凭据实体，不包含持久化）
 func (i *issuer) IssueWecom(ctx context.Context, req IssueOAuthRequest) (*Credential, error) {
 	// 参数验证
@@ -213,6 +256,72 @@ func (i *issuer) IssueWecom(ctx context.Context, req IssueOAuthRequest) (*Creden
 	return credential, nil
 }
 
+// IssueOIDC 颁发上游 OIDC 联合登录凭据（创建凭据实体，不包含持久化）
+// IDPIdentifier 为上游 sub，AppID 为提供商 slug
+func (i *issuer) IssueOIDC(ctx context.Context, req IssueOAuthRequest) (*Credential, error) {
+	if req.AccountID.IsZero() {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "account_id is required")
+	}
+	if req.IDPIdentifier == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "idp_identifier is required")
+	}
+	if req.AppID == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "app_id is required")
+	}
+
+	if req.IDP == "" {
+		req.IDP = "oidc"
+	}
+
+	credential, err := i.binder.Bind(BindSpec{
+		AccountID:     req.AccountID,
+		Type:          CredOAuthOIDC,
+		IDP:           &req.IDP,
+		IDPIdentifier: req.IDPIdentifier,
+		AppID:         &req.AppID,
+		ParamsJSON:    req.ParamsJSON,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 注意：凭据持久化由应用层负责
+	return credential, nil
+}
+
+// IssueSAML 颁发 SAML 单点登录凭据（创建凭据实体，不包含持久化）
+// IDPIdentifier 为断言 NameID，AppID 为 IdP 登记 slug
+func (i *issuer) IssueSAML(ctx context.Context, req IssueOAuthRequest) (*Credential, error) {
+	if req.AccountID.IsZero() {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "account_id is required")
+	}
+	if req.IDPIdentifier == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "idp_identifier is required")
+	}
+	if req.AppID == "" {
+		return nil, perrors.WithCode(code.ErrInvalidArgument, "app_id is required")
+	}
+
+	if req.IDP == "" {
+		req.IDP = "saml"
+	}
+
+	credential, err := i.binder.Bind(BindSpec{
+		AccountID:     req.AccountID,
+		Type:          CredSAML,
+		IDP:           &req.IDP,
+		IDPIdentifier: req.IDPIdentifier,
+		AppID:         &req.AppID,
+		ParamsJSON:    req.ParamsJSON,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 注意：凭据持久化由应用层负责
+	return credential, nil
+}
+
 // hashPassword 使用 PHC 格式哈希密码
 func (i *issuer) hashPassword(plainPassword string) (string, error) {
 	plaintextWithPepper := plainPassword + i.hasher.Pepper()
@@ -0,0 +1,170 @@
+package credential
+
+import (
+	"context"
+	"time"
+	"unicode"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// ==================== 密码策略 ====================
+
+// PasswordPolicy 密码策略（值对象），可按租户配置
+type PasswordPolicy struct {
+	MinLength      int           // 最小长度（按字符计）
+	MinCharClasses int           // 至少包含的字符类别数（大写/小写/数字/符号，0~4）
+	HistorySize    int           // 禁止复用最近 N 个密码（含当前密码），0 表示不限制
+	MaxAge         time.Duration // 密码最长有效期，超过后下次登录强制修改，0 表示不过期
+	CheckBreached  bool          // 是否校验泄露密码库
+}
+
+// DefaultPasswordPolicy 默认密码策略
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:      8,
+		MinCharClasses: 2,
+		CheckBreached:  true,
+	}
+}
+
+// ValidateStrength 校验长度与字符类别
+func (p PasswordPolicy) ValidateStrength(plain string) error {
+	if n := len([]rune(plain)); n < p.MinLength {
+		return errors.WithCode(code.ErrPasswordTooShort, "password must be at least %d characters", p.MinLength)
+	}
+	if classes := countCharClasses(plain); classes < p.MinCharClasses {
+		return errors.WithCode(code.ErrPasswordTooWeak,
+			"password must contain at least %d of: uppercase, lowercase, digits, symbols", p.MinCharClasses)
+	}
+	return nil
+}
+
+// IsExpired 密码是否已超过最长有效期
+// changedAt 为空（历史数据未记录）时不视为过期
+func (p PasswordPolicy) IsExpired(changedAt *time.Time, now time.Time) bool {
+	if p.MaxAge <= 0 || changedAt == nil {
+		return false
+	}
+	return now.Sub(*changedAt) > p.MaxAge
+}
+
+func countCharClasses(s string) int {
+	var upper, lower, digit, symbol bool
+	for _, r := range s {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	n := 0
+	for _, ok := range []bool{upper, lower, digit, symbol} {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
+
+// PasswordHistoryEntry 密码历史记录
+type PasswordHistoryEntry struct {
+	CredentialID meta.ID
+	Material     []byte // PHC 哈希
+	Algo         string
+	CreatedAt    time.Time
+}
+
+// ==================== 驱动端口 ====================
+
+// PasswordPolicyProvider 按租户解析密码策略（Driven Port）
+type PasswordPolicyProvider interface {
+	PolicyFor(tenantID meta.ID) PasswordPolicy
+}
+
+// BreachedPasswordChecker 泄露密码检查（Driven Port）
+// 实现须离线完成（如本地 k-匿名哈希前缀库），不得把明文或完整哈希发往外部
+type BreachedPasswordChecker interface {
+	IsBreached(ctx context.Context, plain string) (bool, error)
+}
+
+// StaticPasswordPolicyProvider 基于配置的策略提供者：租户覆盖优先，否则使用默认策略
+type StaticPasswordPolicyProvider struct {
+	Default PasswordPolicy
+	Tenants map[uint64]PasswordPolicy
+}
+
+var _ PasswordPolicyProvider = (*StaticPasswordPolicyProvider)(nil)
+
+// PolicyFor 返回租户的密码策略
+func (p *StaticPasswordPolicyProvider) PolicyFor(tenantID meta.ID) PasswordPolicy {
+	if p == nil {
+		return DefaultPasswordPolicy()
+	}
+	if policy, ok := p.Tenants[tenantID.Uint64()]; ok {
+		return policy
+	}
+	return p.Default
+}
+
+// ==================== 策略校验器 ====================
+
+// PasswordPolicyValidator 密码策略校验器（领域服务）
+// 职责：在设置新密码前依次校验强度、历史复用与泄露库
+type PasswordPolicyValidator struct {
+	policies PasswordPolicyProvider
+	breached BreachedPasswordChecker
+	hasher   PasswordHasher
+}
+
+// NewPasswordPolicyValidator 创建密码策略校验器；breached 可为 nil（跳过泄露库校验）
+func NewPasswordPolicyValidator(policies PasswordPolicyProvider, breached BreachedPasswordChecker, hasher PasswordHasher) *PasswordPolicyValidator {
+	return &PasswordPolicyValidator{policies: policies, breached: breached, hasher: hasher}
+}
+
+// Policy 返回租户的密码策略
+func (v *PasswordPolicyValidator) Policy(tenantID meta.ID) PasswordPolicy {
+	if v == nil || v.policies == nil {
+		return DefaultPasswordPolicy()
+	}
+	return v.policies.PolicyFor(tenantID)
+}
+
+// Validate 校验新密码
+// previous 为当前及历史密码哈希（新到旧），仅前 HistorySize 个参与复用检查
+func (v *PasswordPolicyValidator) Validate(ctx context.Context, tenantID meta.ID, plain string, previous [][]byte) error {
+	policy := v.Policy(tenantID)
+	if err := policy.ValidateStrength(plain); err != nil {
+		return err
+	}
+
+	if policy.HistorySize > 0 && v.hasher != nil {
+		if len(previous) > policy.HistorySize {
+			previous = previous[:policy.HistorySize]
+		}
+		peppered := plain + v.hasher.Pepper()
+		for _, hash := range previous {
+			if len(hash) > 0 && v.hasher.Verify(string(hash), peppered) {
+				return errors.WithCode(code.ErrPasswordReused, "password must differ from the last %d passwords", policy.HistorySize)
+			}
+		}
+	}
+
+	if policy.CheckBreached && v.breached != nil {
+		breached, err := v.breached.IsBreached(ctx, plain)
+		if err != nil {
+			return errors.WrapC(err, code.ErrInternalServerError, "breached password check failed")
+		}
+		if breached {
+			return errors.WithCode(code.ErrPasswordBreached, "password has appeared in a data breach, choose another one")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package credential
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+type prefixHasher struct{}
+
+func (prefixHasher) Hash(p string) (string, error) { return "h:" + p, nil }
+func (prefixHasher) Verify(h, p string) bool       { return h == "h:"+p }
+func (prefixHasher) Pepper() string                { return "" }
+func (prefixHasher) NeedRehash(string) bool        { return false }
+
+type breachedStub struct {
+	list map[string]bool
+	err  error
+}
+
+func (b breachedStub) IsBreached(_ context.Context, plain string) (bool, error) {
+	return b.list[plain], b.err
+}
+
+func TestPasswordPolicy_ValidateStrength(t *testing.T) {
+	p := PasswordPolicy{MinLength: 8, MinCharClasses: 3}
+
+	assert.True(t, perrors.IsCode(p.ValidateStrength("Ab1!"), code.ErrPasswordTooShort))
+	assert.True(t, perrors.IsCode(p.ValidateStrength("abcdefgh1"), code.ErrPasswordTooWeak))
+	assert.NoError(t, p.ValidateStrength("Abcdefg1"))
+	// 按字符而非字节计长度
+	assert.NoError(t, PasswordPolicy{MinLength: 4}.ValidateStrength("密码口令"))
+}
+
+func TestPasswordPolicy_IsExpired(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+
+	assert.False(t, PasswordPolicy{}.IsExpired(&old, now))
+	assert.False(t, PasswordPolicy{MaxAge: time.Hour}.IsExpired(nil, now))
+	assert.True(t, PasswordPolicy{MaxAge: 24 * time.Hour}.IsExpired(&old, now))
+}
+
+func TestPasswordPolicyValidator_TenantOverrideAndHistory(t *testing.T) {
+	provider := &StaticPasswordPolicyProvider{
+		Default: PasswordPolicy{MinLength: 6},
+		Tenants: map[uint64]PasswordPolicy{7: {MinLength: 12, HistorySize: 2}},
+	}
+	v := NewPasswordPolicyValidator(provider, nil, prefixHasher{})
+	ctx := context.Background()
+	previous := [][]byte{[]byte("h:current-password"), []byte("h:older-password"), []byte("h:oldest-password")}
+
+	require.NoError(t, v.Validate(ctx, meta.FromUint64(1), "short1", previous))
+	assert.True(t, perrors.IsCode(v.Validate(ctx, meta.FromUint64(7), "short1", nil), code.ErrPasswordTooShort))
+
+	assert.True(t, perrors.IsCode(v.Validate(ctx, meta.FromUint64(7), "older-password", previous), code.ErrPasswordReused))
+	// 超出 HistorySize 的旧密码不参与复用检查
+	assert.NoError(t, v.Validate(ctx, meta.FromUint64(7), "oldest-password", previous))
+}
+
+func TestPasswordPolicyValidator_Breached(t *testing.T) {
+	provider := &StaticPasswordPolicyProvider{Default: PasswordPolicy{MinLength: 6, CheckBreached: true}}
+	ctx := context.Background()
+	tenant := meta.FromUint64(1)
+
+	v := NewPasswordPolicyValidator(provider, breachedStub{list: map[string]bool{"password1": true}}, prefixHasher{})
+	assert.True(t, perrors.IsCode(v.Validate(ctx, tenant, "password1", nil), code.ErrPasswordBreached))
+	assert.NoError(t, v.Validate(ctx, tenant, "unique-pass", nil))
+
+	failing := NewPasswordPolicyValidator(provider, breachedStub{err: errors.New("io")}, prefixHasher{})
+	assert.True(t, perrors.IsCode(failing.Validate(ctx, tenant, "unique-pass", nil), code.ErrInternalServerError))
+
+	// 未配置检查器时跳过
+	assert.NoError(t, NewPasswordPolicyValidator(provider, nil, nil).Validate(ctx, tenant, "password1", nil))
+}
@@ -24,6 +24,7 @@ type Repository interface {
 	UpdateLastSuccessAt(ctx context.Context, id meta.ID, lastSuccessAt time.Time) error
 	UpdateLastFailureAt(ctx context.Context, id meta.ID, lastFailureAt time.Time) error
 	UpdateExpiresAt(ctx context.Context, id meta.ID, expiresAt *time.Time) error
+	UpdatePasswordChangedAt(ctx context.Context, id meta.ID, changedAt time.Time) error
 
 	// GetBy*** 查询凭据
 	GetByID(ctx context.Context, id meta.ID) (*Credential, error)
@@ -34,3 +35,14 @@ type Repository interface {
 	// Delete 删除凭据
 	Delete(ctx context.Context, id meta.ID) error
 }
+
+// PasswordHistoryRepository 密码历史仓储（Driven Port）
+// 职责：保存密码凭据的历史哈希，用于禁止复用最近 N 个密码
+type PasswordHistoryRepository interface {
+	// Append 追加一条历史哈希
+	Append(ctx context.Context, entry PasswordHistoryEntry) error
+	// ListRecent 按时间倒序返回最近 limit 条历史
+	ListRecent(ctx context.Context, credentialID meta.ID, limit int) ([]PasswordHistoryEntry, error)
+	// Prune 仅保留最近 keep 条历史
+	Prune(ctx context.Context, credentialID meta.ID, keep int) error
+}
@@ -10,7 +10,7 @@ import "github.com/FangcunMount/iam-contracts/internal/pkg/meta"
 type BindSpec struct {
 	AccountID     meta.ID        // 账号ID
 	Type          CredentialType // 凭据类型
-	IDP           *string        // IDP类型："wechat"|"wecom"|"phone" | nil(本地)
+	IDP           *string        // IDP类型："wechat"|"wechat_mp"|"wecom"|"phone" | nil(本地)
 	IDPIdentifier string         // IDP标识符：unionid | openid@appid | userid | +E164 | ""(password)
 	AppID         *string        // 应用ID
 	Material      []byte         // 凭据材料（仅 password）
@@ -9,7 +9,10 @@ const (
 	CredPassword     CredentialType = "password"       // 用户名+密码
 	CredPhoneOTP     CredentialType = "phone_otp"      // 手机号+短信码（OTP 不落库）
 	CredOAuthWxMinip CredentialType = "oauth_wx_minip" // wx.login
+	CredOAuthWxMP    CredentialType = "oauth_wx_mp"    // 公众号网页授权
 	CredOAuthWecom   CredentialType = "oauth_wecom"    // qwx.login / 扫码
+	CredOAuthOIDC    CredentialType = "oauth_oidc"     // 上游 OIDC 授权码联合登录
+	CredSAML         CredentialType = "saml"           // SAML 2.0 单点登录
 )
 
 // CredentialStatus 凭据状态
@@ -37,8 +40,28 @@ func (s CredentialStatus) Validate() bool {
 }
 
 // LockoutPolicy 锁定策略
+// 失败次数达到 Threshold 后每次失败都会锁定；累计失败每多 Threshold 次，锁定时长乘以 BackoffFactor，上限 MaxLockDuration
 type LockoutPolicy struct {
-	Enabled      bool
-	Threshold    int
-	LockDuration time.Duration
+	Enabled         bool
+	Threshold       int
+	LockDuration    time.Duration
+	BackoffFactor   float64       // 指数退避倍数，<=1 时为固定时长
+	MaxLockDuration time.Duration // 锁定时长上限，0 不限制
+}
+
+// DurationFor 第 level 次（从 1 开始）锁定的时长
+func (p LockoutPolicy) DurationFor(level int) time.Duration {
+	d := p.LockDuration
+	if p.BackoffFactor > 1 {
+		for i := 1; i < level; i++ {
+			d = time.Duration(float64(d) * p.BackoffFactor)
+			if p.MaxLockDuration > 0 && d >= p.MaxLockDuration {
+				break
+			}
+		}
+	}
+	if p.MaxLockDuration > 0 && d > p.MaxLockDuration {
+		d = p.MaxLockDuration
+	}
+	return d
 }
@@ -2,6 +2,7 @@ package jwks
 
 import (
 	"context"
+	"crypto"
 )
 
 // ================== External Service Interfaces (Driven Ports) ==================
@@ -63,6 +64,14 @@ type PrivateKeyResolver interface {
 	ResolveSigningKey(ctx context.Context, kid, alg string) (any, error)
 }
 
+// SignerResolver 签名器解析器
+// 返回 crypto.Signer 句柄，私钥材料可以留在外部签名设备（HSM/KMS）内，不暴露给 JWT 生成器
+// PrivateKeyResolver 的实现可以同时实现该接口；生成器优先使用签名器
+type SignerResolver interface {
+	// ResolveSigner 解析 kid 对应的签名器
+	ResolveSigner(ctx context.Context, kid, alg string) (crypto.Signer, error)
+}
+
 // KeySetReader 密钥集读取器
 // 对外发布用：供应用层生成 /.well-known/jwks.json
 type KeySetReader interface {
@@ -0,0 +1,43 @@
+package saml
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ================== External Service Interfaces (Driven Ports) ==================
+// 定义领域模型所依赖的外部服务接口，由基础设施层提供实现
+
+// IdPMetadata 从 IdP 元数据解析出的登录所需信息
+type IdPMetadata struct {
+	EntityID     string
+	SSOURL       string
+	Certificates []string // 签名证书（Base64 DER）
+}
+
+// ServiceProvider 本系统作为 SAML SP 的协议能力
+type ServiceProvider interface {
+	// Metadata 返回指定 IdP 登记对应的 SP 元数据 XML（ACS 按 slug 区分）
+	Metadata(slug string) ([]byte, error)
+	// ParseIdPMetadata 解析 IdP 元数据 XML
+	ParseIdPMetadata(raw []byte) (IdPMetadata, error)
+	// AuthnRequestURL 生成签名的 HTTP-Redirect AuthnRequest 地址，并登记请求 ID 供回传校验
+	AuthnRequestURL(ctx context.Context, idp *IdentityProvider, relayState string) (string, error)
+}
+
+// ErrRequestNotFound AuthnRequest 不存在、已过期或已被消费
+var ErrRequestNotFound = errors.New("saml authn request not found")
+
+// RequestStore 已发出 AuthnRequest 的登记，回传时按 InResponseTo 一次性消费
+type RequestStore interface {
+	SaveRequest(ctx context.Context, requestID, slug string, ttl time.Duration) error
+	// ConsumeRequest 取出并删除请求登记，不存在时返回 ErrRequestNotFound
+	ConsumeRequest(ctx context.Context, requestID string) (slug string, err error)
+}
+
+// ReplayCache 已消费断言 ID 的缓存，用于防重放
+type ReplayCache interface {
+	// MarkAssertion 记录断言 ID 直到 until；首次记录返回 true
+	MarkAssertion(ctx context.Context, assertionID string, until time.Time) (bool, error)
+}
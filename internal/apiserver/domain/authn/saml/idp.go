@@ -0,0 +1,138 @@
+package saml
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// IdentityProvider 租户登记的 SAML 2.0 身份提供商
+// 以 Slug 唯一标识，Slug 同时写入联合登录凭据的 app_id 与 ACS 路径
+type IdentityProvider struct {
+	ID       meta.ID
+	TenantID meta.ID
+
+	Slug     string
+	Name     string
+	EntityID string // IdP EntityID，用于断言 Issuer 校验
+	SSOURL   string // HTTP-Redirect 绑定的 SingleSignOnService 地址
+	Status   Status
+
+	// Certificates IdP 签名证书（Base64 DER），轮换期间可同时存在多张
+	Certificates []string
+	// AttributeMapping SAML 属性名到 Principal.Claims 键的映射
+	AttributeMapping map[string]string
+	// AutoProvision 首次登录且未绑定时是否即时开通账户
+	AutoProvision bool
+	// MetadataXML 登记时提交的原始 IdP 元数据，便于排查
+	MetadataXML string
+}
+
+// NewIdentityProvider 创建新的 SAML IdP 登记
+func NewIdentityProvider(tenantID meta.ID, slug string, md IdPMetadata, opts ...IdentityProviderOption) *IdentityProvider {
+	p := &IdentityProvider{
+		TenantID:     tenantID,
+		Slug:         slug,
+		EntityID:     md.EntityID,
+		SSOURL:       md.SSOURL,
+		Certificates: md.Certificates,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// IdentityProviderOption SAML IdP 登记选项
+type IdentityProviderOption func(*IdentityProvider)
+
+func WithIdentityProviderID(id meta.ID) IdentityProviderOption {
+	return func(p *IdentityProvider) { p.ID = id }
+}
+func WithIdentityProviderName(name string) IdentityProviderOption {
+	return func(p *IdentityProvider) { p.Name = name }
+}
+func WithIdentityProviderStatus(status Status) IdentityProviderOption {
+	return func(p *IdentityProvider) { p.Status = status }
+}
+func WithAttributeMapping(mapping map[string]string) IdentityProviderOption {
+	return func(p *IdentityProvider) { p.AttributeMapping = mapping }
+}
+func WithAutoProvision(enabled bool) IdentityProviderOption {
+	return func(p *IdentityProvider) { p.AutoProvision = enabled }
+}
+func WithMetadataXML(raw string) IdentityProviderOption {
+	return func(p *IdentityProvider) { p.MetadataXML = raw }
+}
+
+// 状态检查方法
+func (p *IdentityProvider) IsEnabled() bool  { return p.Status == StatusEnabled }
+func (p *IdentityProvider) IsDisabled() bool { return p.Status == StatusDisabled }
+
+// 状态变更方法
+func (p *IdentityProvider) Enable()  { p.Status = StatusEnabled }
+func (p *IdentityProvider) Disable() { p.Status = StatusDisabled }
+
+// ApplyMetadata 以重新解析的 IdP 元数据替换端点与证书（证书轮换）
+func (p *IdentityProvider) ApplyMetadata(md IdPMetadata, raw string) {
+	p.EntityID = md.EntityID
+	p.SSOURL = md.SSOURL
+	p.Certificates = md.Certificates
+	p.MetadataXML = raw
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,63}$`)
+
+// Validate 校验登记完整性
+func (p *IdentityProvider) Validate() error {
+	if !slugPattern.MatchString(p.Slug) {
+		return errors.New("slug must be 2-64 lowercase letters, digits or hyphens")
+	}
+	if p.TenantID.IsZero() {
+		return errors.New("tenant_id cannot be empty")
+	}
+	if strings.TrimSpace(p.EntityID) == "" {
+		return errors.New("idp entity_id cannot be empty")
+	}
+	u, err := url.Parse(p.SSOURL)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return errors.New("idp sso url must be an absolute http(s) URL")
+	}
+	if len(p.Certificates) == 0 {
+		return errors.New("idp signing certificate cannot be empty")
+	}
+	for i, cert := range p.Certificates {
+		if _, err := base64.StdEncoding.DecodeString(cert); err != nil {
+			return fmt.Errorf("idp certificate #%d is not valid base64", i+1)
+		}
+	}
+	seen := make(map[string]struct{}, len(p.AttributeMapping))
+	for attr, key := range p.AttributeMapping {
+		if strings.TrimSpace(attr) == "" || strings.TrimSpace(key) == "" {
+			return errors.New("attribute mapping requires both attribute name and claim key")
+		}
+		if _, ok := reservedClaims[key]; ok {
+			return fmt.Errorf("claim key %q is reserved", key)
+		}
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("claim key %q is mapped more than once", key)
+		}
+		seen[key] = struct{}{}
+	}
+	return nil
+}
+
+// reservedClaims 由 SAML 认证策略写入、不允许被属性映射占用的 Claims 键
+var reservedClaims = map[string]struct{}{
+	"saml_idp":           {},
+	"saml_name_id":       {},
+	"saml_session_index": {},
+	"auth_time":          {},
+}
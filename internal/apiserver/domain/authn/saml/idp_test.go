@@ -0,0 +1,64 @@
+package saml_test
+
+import (
+	"testing"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/saml"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validMetadata() saml.IdPMetadata {
+	return saml.IdPMetadata{
+		EntityID:     "https://idp.hospital.example/saml",
+		SSOURL:       "https://idp.hospital.example/sso",
+		Certificates: []string{"TUlJQw=="},
+	}
+}
+
+func TestIdentityProvider_Validate(t *testing.T) {
+	p := saml.NewIdentityProvider(meta.FromUint64(7), "city-hospital", validMetadata(),
+		saml.WithIdentityProviderName("City Hospital"),
+		saml.WithAttributeMapping(map[string]string{"urn:oid:0.9.2342.19200300.100.1.3": "email"}),
+	)
+	require.NoError(t, p.Validate())
+
+	cases := map[string]func(*saml.IdentityProvider){
+		"bad slug":        func(p *saml.IdentityProvider) { p.Slug = "City Hospital" },
+		"no tenant":       func(p *saml.IdentityProvider) { p.TenantID = 0 },
+		"no entity id":    func(p *saml.IdentityProvider) { p.EntityID = " " },
+		"relative sso":    func(p *saml.IdentityProvider) { p.SSOURL = "/sso" },
+		"no certificates": func(p *saml.IdentityProvider) { p.Certificates = nil },
+		"bad certificate": func(p *saml.IdentityProvider) { p.Certificates = []string{"%%%"} },
+		"reserved claim":  func(p *saml.IdentityProvider) { p.AttributeMapping = map[string]string{"uid": "saml_name_id"} },
+		"duplicate claim": func(p *saml.IdentityProvider) {
+			p.AttributeMapping = map[string]string{"mail": "email", "email": "email"}
+		},
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := saml.NewIdentityProvider(meta.FromUint64(7), "city-hospital", validMetadata())
+			mutate(p)
+			assert.Error(t, p.Validate())
+		})
+	}
+}
+
+func TestIdentityProvider_ApplyMetadataAndStatus(t *testing.T) {
+	p := saml.NewIdentityProvider(meta.FromUint64(7), "city-hospital", validMetadata(),
+		saml.WithIdentityProviderStatus(saml.StatusEnabled))
+	require.True(t, p.IsEnabled())
+
+	p.Disable()
+	assert.True(t, p.IsDisabled())
+
+	p.ApplyMetadata(saml.IdPMetadata{
+		EntityID:     "https://idp2.hospital.example/saml",
+		SSOURL:       "https://idp2.hospital.example/sso",
+		Certificates: []string{"TkVX", "T0xE"},
+	}, "<md/>")
+	assert.Equal(t, "https://idp2.hospital.example/saml", p.EntityID)
+	assert.Len(t, p.Certificates, 2)
+	assert.Equal(t, "<md/>", p.MetadataXML)
+}
@@ -0,0 +1,29 @@
+package saml
+
+import (
+	"context"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// ================== Repository Interface (Driven Port) ==================
+// 定义领域模型所依赖的仓储接口，由基础设施层提供实现
+
+// Repository SAML IdP 登记存储库接口
+type Repository interface {
+	// 创建接口
+	Create(ctx context.Context, idp *IdentityProvider) error
+
+	// 查询接口
+	GetBySlug(ctx context.Context, slug string) (*IdentityProvider, error)
+	List(ctx context.Context, filter ListFilter) ([]*IdentityProvider, error)
+
+	// 更新接口
+	Update(ctx context.Context, idp *IdentityProvider) error
+}
+
+// ListFilter SAML IdP 登记列表过滤条件
+type ListFilter struct {
+	TenantID *meta.ID
+	Status   *Status
+}
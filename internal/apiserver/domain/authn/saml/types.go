@@ -0,0 +1,19 @@
+package saml
+
+import "time"
+
+// Status SAML IdP 登记状态
+type Status string
+
+const (
+	StatusEnabled  Status = "Enabled"  // 已启用
+	StatusDisabled Status = "Disabled" // 已禁用
+)
+
+// 默认值
+const (
+	// DefaultRequestTTL AuthnRequest 等待 IdP 回传的有效期
+	DefaultRequestTTL = 10 * time.Minute
+	// DefaultClockSkew 断言时间条件允许的时钟偏差
+	DefaultClockSkew = 2 * time.Minute
+)
@@ -0,0 +1,57 @@
+package session
+
+import "strings"
+
+// DescribeUserAgent 从 User-Agent 粗略推断设备名，客户端未上报设备名时使用。
+// 只区分常见平台与微信环境，无法识别时返回空串。
+func DescribeUserAgent(ua string) string {
+	if ua == "" {
+		return ""
+	}
+	lower := strings.ToLower(ua)
+
+	var platform string
+	switch {
+	case strings.Contains(lower, "ipad"):
+		platform = "iPad"
+	case strings.Contains(lower, "iphone"):
+		platform = "iPhone"
+	case strings.Contains(lower, "android"):
+		platform = "Android"
+	case strings.Contains(lower, "harmonyos"), strings.Contains(lower, "openharmony"):
+		platform = "HarmonyOS"
+	case strings.Contains(lower, "windows"):
+		platform = "Windows"
+	case strings.Contains(lower, "mac os x"), strings.Contains(lower, "macintosh"):
+		platform = "macOS"
+	case strings.Contains(lower, "linux"):
+		platform = "Linux"
+	}
+
+	var client string
+	switch {
+	case strings.Contains(lower, "miniprogram"):
+		client = "微信小程序"
+	case strings.Contains(lower, "wxwork"):
+		client = "企业微信"
+	case strings.Contains(lower, "micromessenger"):
+		client = "微信"
+	case strings.Contains(lower, "edg/"):
+		client = "Edge"
+	case strings.Contains(lower, "chrome/"):
+		client = "Chrome"
+	case strings.Contains(lower, "firefox/"):
+		client = "Firefox"
+	case strings.Contains(lower, "safari/"):
+		client = "Safari"
+	}
+
+	switch {
+	case platform != "" && client != "":
+		return platform + " · " + client
+	case platform != "":
+		return platform
+	default:
+		return client
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 
+	accountdomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
 	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
 )
@@ -19,22 +20,50 @@ type Manager interface {
 	RevokeByUser(ctx context.Context, userID meta.ID, reason string, revokedBy string) error
 	RevokeByAccount(ctx context.Context, accountID meta.ID, reason string, revokedBy string) error
 	Extend(ctx context.Context, sessionID string, expiresAt time.Time) error
+	ListByUser(ctx context.Context, userID meta.ID) ([]*Session, error)
+	ListByAccount(ctx context.Context, accountID meta.ID) ([]*Session, error)
+	// Evaluate 判定会话是否可用，返回不可用原因；空闲超时或超过绝对时长的会话会被撤销
+	Evaluate(ctx context.Context, session *Session) (EndReason, error)
 }
 
 type manager struct {
-	store Store
+	store       Store
+	accountRepo accountdomain.Repository
+	policy      ConcurrencyPolicy
+	timeouts    TimeoutPolicyProvider
 }
 
 // NewManager 创建会话管理器。
-func NewManager(store Store) Manager {
-	return &manager{store: store}
+func NewManager(store Store, opts ...ManagerOption) Manager {
+	m := &manager{store: store}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
 func (m *manager) Create(ctx context.Context, principal *authentication.Principal, expiresAt time.Time) (*Session, error) {
 	if principal == nil {
 		return nil, fmt.Errorf("principal is nil")
 	}
+	accountType, err := m.resolveAccountType(ctx, principal.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.enforceConcurrencyLimit(ctx, principal.AccountID, accountType); err != nil {
+		return nil, err
+	}
 	session := New(uuid.NewString(), principal.UserID, principal.AccountID, principal.TenantID, principal.AMR, toStringClaims(principal.Claims), expiresAt)
+	session.AccountType = string(accountType)
+	if deadline := m.timeoutPolicy(session).Deadline(session); !deadline.IsZero() && deadline.Before(session.ExpiresAt) {
+		session.ExpiresAt = deadline
+	}
+	session.ClientIP = principal.Device.IP
+	session.UserAgent = principal.Device.UserAgent
+	session.DeviceName = principal.Device.Name
+	if session.DeviceName == "" {
+		session.DeviceName = DescribeUserAgent(principal.Device.UserAgent)
+	}
 	if err := m.store.Save(ctx, session); err != nil {
 		return nil, err
 	}
@@ -57,10 +86,43 @@ func (m *manager) RevokeByAccount(ctx context.Context, accountID meta.ID, reason
 	return m.store.RevokeByAccount(ctx, accountID, reason, revokedBy)
 }
 
+// Extend 延长会话，但不超过绝对时长截止时间。
 func (m *manager) Extend(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	if m.timeouts != nil {
+		sess, err := m.store.Get(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+		if deadline := m.timeoutPolicy(sess).Deadline(sess); !deadline.IsZero() && deadline.Before(expiresAt) {
+			expiresAt = deadline
+		}
+	}
 	return m.store.Extend(ctx, sessionID, expiresAt)
 }
 
+// resolveAccountType 查询账号类型；未配置账号仓储或账号不存在时返回空。
+func (m *manager) resolveAccountType(ctx context.Context, accountID meta.ID) (accountdomain.AccountType, error) {
+	if m.accountRepo == nil || accountID.IsZero() {
+		return "", nil
+	}
+	account, err := m.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return "", fmt.Errorf("load account for session: %w", err)
+	}
+	if account == nil {
+		return "", nil
+	}
+	return account.Type, nil
+}
+
+func (m *manager) ListByUser(ctx context.Context, userID meta.ID) ([]*Session, error) {
+	return m.store.ListByUser(ctx, userID)
+}
+
+func (m *manager) ListByAccount(ctx context.Context, accountID meta.ID) ([]*Session, error) {
+	return m.store.ListByAccount(ctx, accountID)
+}
+
 func toStringClaims(claims map[string]any) map[string]string {
 	if len(claims) == 0 {
 		return nil
@@ -15,4 +15,7 @@ type Store interface {
 	Extend(ctx context.Context, sessionID string, expiresAt time.Time) error
 	RevokeByUser(ctx context.Context, userID meta.ID, reason string, revokedBy string) error
 	RevokeByAccount(ctx context.Context, accountID meta.ID, reason string, revokedBy string) error
+	// ListByUser / ListByAccount 返回索引中仍然活跃的会话，按过期时间升序
+	ListByUser(ctx context.Context, userID meta.ID) ([]*Session, error)
+	ListByAccount(ctx context.Context, accountID meta.ID) ([]*Session, error)
 }
@@ -32,6 +32,13 @@ type Session struct {
 	RevokedAt     *time.Time
 	RevokeReason  string
 	RevokedBy     string
+	AccountType   string    // 创建时的账号类型，用于匹配超时策略
+	LastActiveAt  time.Time // 最近一次刷新时间，用于空闲超时
+
+	// 登录设备信息
+	ClientIP   string
+	UserAgent  string
+	DeviceName string
 }
 
 // New 创建一个新的活跃会话。
@@ -47,7 +54,19 @@ func New(sessionID string, userID, accountID, tenantID meta.ID, amr []string, se
 		SessionClaims: cloneStringMap(sessionClaims),
 		CreatedAt:     now,
 		ExpiresAt:     expiresAt,
+		LastActiveAt:  now,
+	}
+}
+
+// LastActive 返回最近活跃时间；旧会话未记录时以创建时间为准。
+func (s *Session) LastActive() time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	if s.LastActiveAt.IsZero() {
+		return s.CreatedAt
 	}
+	return s.LastActiveAt
 }
 
 // IsActive 返回会话是否仍处于可用状态。
@@ -89,12 +108,13 @@ func (s *Session) Revoke(reason, revokedBy string) {
 	s.RevokedBy = revokedBy
 }
 
-// Extend 延长会话过期时间，并在必要时把已自然过期的会话重新拉回 active。
+// Extend 延长会话过期时间并刷新活跃时间，必要时把已自然过期的会话重新拉回 active。
 func (s *Session) Extend(expiresAt time.Time) {
 	if s == nil {
 		return
 	}
 	s.ExpiresAt = expiresAt
+	s.LastActiveAt = time.Now()
 	if s.Status == StatusExpired {
 		s.Status = StatusActive
 	}
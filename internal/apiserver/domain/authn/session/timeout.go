@@ -0,0 +1,122 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/log"
+	accountdomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// EndReason 会话不可用的原因。
+type EndReason string
+
+const (
+	// EndReasonNone 会话可用。
+	EndReasonNone EndReason = ""
+	// EndReasonRevoked 会话已被撤销或不存在。
+	EndReasonRevoked EndReason = "revoked"
+	// EndReasonExpired 会话已自然过期（刷新令牌到期）。
+	EndReasonExpired EndReason = "expired"
+	// EndReasonIdleTimeout 超过空闲超时未刷新。
+	EndReasonIdleTimeout EndReason = "idle_timeout"
+	// EndReasonMaxLifetime 超过绝对时长，必须重新认证。
+	EndReasonMaxLifetime EndReason = "max_lifetime_exceeded"
+)
+
+// TimeoutPolicy 会话超时策略，零值表示不限制。
+type TimeoutPolicy struct {
+	IdleTimeout time.Duration // 自最近一次刷新起的空闲超时
+	MaxLifetime time.Duration // 自 CreatedAt 起的绝对时长
+}
+
+// Check 返回会话在 now 时刻触发的超时原因，未超时返回 EndReasonNone。
+func (p TimeoutPolicy) Check(sess *Session, now time.Time) EndReason {
+	if sess == nil {
+		return EndReasonNone
+	}
+	if p.MaxLifetime > 0 && now.After(sess.CreatedAt.Add(p.MaxLifetime)) {
+		return EndReasonMaxLifetime
+	}
+	if p.IdleTimeout > 0 && now.After(sess.LastActive().Add(p.IdleTimeout)) {
+		return EndReasonIdleTimeout
+	}
+	return EndReasonNone
+}
+
+// Deadline 返回绝对时长截止时间，未限制时返回零值。
+func (p TimeoutPolicy) Deadline(sess *Session) time.Time {
+	if sess == nil || p.MaxLifetime <= 0 {
+		return time.Time{}
+	}
+	return sess.CreatedAt.Add(p.MaxLifetime)
+}
+
+// TimeoutPolicyProvider 按租户与账号类型提供会话超时策略。
+type TimeoutPolicyProvider interface {
+	PolicyFor(tenantID meta.ID, accountType accountdomain.AccountType) TimeoutPolicy
+}
+
+// StaticTimeoutPolicyProvider 基于配置的超时策略：租户覆盖优先，其次账号类型，否则使用默认策略
+type StaticTimeoutPolicyProvider struct {
+	Default      TimeoutPolicy
+	AccountTypes map[accountdomain.AccountType]TimeoutPolicy
+	Tenants      map[uint64]TimeoutPolicy
+}
+
+var _ TimeoutPolicyProvider = (*StaticTimeoutPolicyProvider)(nil)
+
+// PolicyFor 返回会话适用的超时策略
+func (p *StaticTimeoutPolicyProvider) PolicyFor(tenantID meta.ID, accountType accountdomain.AccountType) TimeoutPolicy {
+	if p == nil {
+		return TimeoutPolicy{}
+	}
+	if policy, ok := p.Tenants[tenantID.Uint64()]; ok {
+		return policy
+	}
+	if policy, ok := p.AccountTypes[accountType]; ok {
+		return policy
+	}
+	return p.Default
+}
+
+// WithTimeoutPolicy 启用空闲超时与绝对时长限制。
+func WithTimeoutPolicy(provider TimeoutPolicyProvider) ManagerOption {
+	return func(m *manager) {
+		m.timeouts = provider
+	}
+}
+
+func (m *manager) timeoutPolicy(sess *Session) TimeoutPolicy {
+	if m.timeouts == nil || sess == nil {
+		return TimeoutPolicy{}
+	}
+	return m.timeouts.PolicyFor(sess.TenantID, accountdomain.AccountType(sess.AccountType))
+}
+
+// Evaluate 判定会话当前是否可用。
+// 触发空闲超时或绝对时长的会话会被撤销，使其从设备列表中消失，已签发的令牌随之失效。
+func (m *manager) Evaluate(ctx context.Context, sess *Session) (EndReason, error) {
+	if sess == nil {
+		return EndReasonRevoked, nil
+	}
+	if sess.Status == StatusRevoked {
+		return EndReasonRevoked, nil
+	}
+	if !sess.IsActive() {
+		return EndReasonExpired, nil
+	}
+	reason := m.timeoutPolicy(sess).Check(sess, time.Now())
+	if reason == EndReasonNone {
+		return EndReasonNone, nil
+	}
+	if err := m.store.Revoke(ctx, sess.SessionID, string(reason), "system"); err != nil {
+		log.Warnw("failed to revoke timed out session",
+			"session_id", sess.SessionID,
+			"reason", string(reason),
+			"error", err,
+		)
+	}
+	return reason, nil
+}
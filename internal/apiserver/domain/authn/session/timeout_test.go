@@ -0,0 +1,113 @@
+package session_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	accountdomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	sessiondomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/session"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	sessiondomain.Store
+	sessions map[string]*sessiondomain.Session
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{sessions: make(map[string]*sessiondomain.Session)}
+}
+
+func (s *memoryStore) Save(_ context.Context, sess *sessiondomain.Session) error {
+	s.sessions[sess.SessionID] = sess
+	return nil
+}
+
+func (s *memoryStore) Get(_ context.Context, sessionID string) (*sessiondomain.Session, error) {
+	return s.sessions[sessionID], nil
+}
+
+func (s *memoryStore) Revoke(_ context.Context, sessionID string, reason string, revokedBy string) error {
+	if sess, ok := s.sessions[sessionID]; ok {
+		sess.Revoke(reason, revokedBy)
+	}
+	return nil
+}
+
+func (s *memoryStore) Extend(_ context.Context, sessionID string, expiresAt time.Time) error {
+	if sess, ok := s.sessions[sessionID]; ok {
+		sess.Extend(expiresAt)
+	}
+	return nil
+}
+
+func (s *memoryStore) ListByAccount(_ context.Context, accountID meta.ID) ([]*sessiondomain.Session, error) {
+	var out []*sessiondomain.Session
+	for _, sess := range s.sessions {
+		if sess.AccountID == accountID && sess.IsActive() {
+			out = append(out, sess)
+		}
+	}
+	return out, nil
+}
+
+func TestSessionTimeoutPolicy(t *testing.T) {
+	now := time.Now()
+	sess := sessiondomain.New("sid", meta.FromUint64(1), meta.FromUint64(2), meta.FromUint64(0), nil, nil, now.Add(time.Hour))
+	sess.CreatedAt = now.Add(-3 * time.Hour)
+	sess.LastActiveAt = now.Add(-10 * time.Minute)
+
+	require.Equal(t, sessiondomain.EndReasonNone, sessiondomain.TimeoutPolicy{}.Check(sess, now))
+	require.Equal(t, sessiondomain.EndReasonNone, sessiondomain.TimeoutPolicy{IdleTimeout: 30 * time.Minute, MaxLifetime: 4 * time.Hour}.Check(sess, now))
+	require.Equal(t, sessiondomain.EndReasonIdleTimeout, sessiondomain.TimeoutPolicy{IdleTimeout: 5 * time.Minute}.Check(sess, now))
+	require.Equal(t, sessiondomain.EndReasonMaxLifetime, sessiondomain.TimeoutPolicy{IdleTimeout: time.Hour, MaxLifetime: 2 * time.Hour}.Check(sess, now))
+
+	provider := &sessiondomain.StaticTimeoutPolicyProvider{
+		Default:      sessiondomain.TimeoutPolicy{IdleTimeout: time.Hour},
+		AccountTypes: map[accountdomain.AccountType]sessiondomain.TimeoutPolicy{accountdomain.TypeOpera: {MaxLifetime: 12 * time.Hour}},
+		Tenants:      map[uint64]sessiondomain.TimeoutPolicy{9: {IdleTimeout: time.Minute}},
+	}
+	require.Equal(t, time.Minute, provider.PolicyFor(meta.FromUint64(9), accountdomain.TypeOpera).IdleTimeout)
+	require.Equal(t, 12*time.Hour, provider.PolicyFor(meta.FromUint64(1), accountdomain.TypeOpera).MaxLifetime)
+	require.Equal(t, time.Hour, provider.PolicyFor(meta.FromUint64(1), accountdomain.TypeWcMinip).IdleTimeout)
+}
+
+func TestManagerEvaluate_RevokesIdleSession(t *testing.T) {
+	store := newMemoryStore()
+	manager := sessiondomain.NewManager(store, sessiondomain.WithTimeoutPolicy(&sessiondomain.StaticTimeoutPolicyProvider{
+		Default: sessiondomain.TimeoutPolicy{IdleTimeout: 5 * time.Minute},
+	}))
+	sess, err := manager.Create(context.Background(), &authentication.Principal{UserID: meta.FromUint64(1), AccountID: meta.FromUint64(2)}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	reason, err := manager.Evaluate(context.Background(), sess)
+	require.NoError(t, err)
+	require.Equal(t, sessiondomain.EndReasonNone, reason)
+
+	sess.LastActiveAt = time.Now().Add(-10 * time.Minute)
+	reason, err = manager.Evaluate(context.Background(), sess)
+	require.NoError(t, err)
+	require.Equal(t, sessiondomain.EndReasonIdleTimeout, reason)
+	require.Equal(t, sessiondomain.StatusRevoked, store.sessions[sess.SessionID].Status)
+
+	reason, err = manager.Evaluate(context.Background(), sess)
+	require.NoError(t, err)
+	require.Equal(t, sessiondomain.EndReasonRevoked, reason)
+}
+
+func TestManagerExtend_CappedByMaxLifetime(t *testing.T) {
+	store := newMemoryStore()
+	manager := sessiondomain.NewManager(store, sessiondomain.WithTimeoutPolicy(&sessiondomain.StaticTimeoutPolicyProvider{
+		Default: sessiondomain.TimeoutPolicy{MaxLifetime: 2 * time.Hour},
+	}))
+	sess, err := manager.Create(context.Background(), &authentication.Principal{UserID: meta.FromUint64(1), AccountID: meta.FromUint64(2)}, time.Now().Add(24*time.Hour))
+	require.NoError(t, err)
+	deadline := sess.CreatedAt.Add(2 * time.Hour)
+	require.Equal(t, deadline, sess.ExpiresAt)
+
+	require.NoError(t, manager.Extend(context.Background(), sess.SessionID, time.Now().Add(48*time.Hour)))
+	require.Equal(t, deadline, store.sessions[sess.SessionID].ExpiresAt)
+}
@@ -70,6 +70,9 @@ func (s *TokenIssuer) IssueToken(ctx context.Context, principal *authentication.
 			"resource", "session",
 			"error", err.Error(),
 		)
+		if perrors.IsCode(err, code.ErrSessionLimitExceeded) {
+			return nil, err
+		}
 		return nil, perrors.WrapC(err, code.ErrInternalServerError, "failed to create session")
 	}
 
@@ -6,9 +6,12 @@ import (
 	"testing"
 	"time"
 
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	accountdomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/account"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
 	sessiondomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/session"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/token"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
 	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
 	"github.com/stretchr/testify/require"
 )
@@ -89,6 +92,29 @@ func (s *sessionStoreStub) RevokeByAccount(_ context.Context, accountID meta.ID,
 	return nil
 }
 
+func (s *sessionStoreStub) ListByUser(_ context.Context, userID meta.ID) ([]*sessiondomain.Session, error) {
+	return nil, nil
+}
+
+func (s *sessionStoreStub) ListByAccount(_ context.Context, accountID meta.ID) ([]*sessiondomain.Session, error) {
+	var out []*sessiondomain.Session
+	for _, sess := range s.sessions {
+		if sess.AccountID == accountID && sess.IsActive() {
+			out = append(out, sess)
+		}
+	}
+	return out, nil
+}
+
+type accountRepoStub struct {
+	accountdomain.Repository
+	accounts map[meta.ID]*accountdomain.Account
+}
+
+func (r *accountRepoStub) GetByID(_ context.Context, id meta.ID) (*accountdomain.Account, error) {
+	return r.accounts[id], nil
+}
+
 type storeStub struct {
 	saved                    *token.Token
 	revokedAccessTokenID     string
@@ -140,6 +166,54 @@ func TestIssueToken_HappyPathAndGeneratorError(t *testing.T) {
 	require.Error(t, err2)
 }
 
+func TestIssueToken_ConcurrentSessionLimit(t *testing.T) {
+	opera := accountdomain.NewAccount(meta.FromUint64(1), accountdomain.TypeOpera, "ops")
+	opera.ID = meta.FromUint64(2)
+	parent := accountdomain.NewAccount(meta.FromUint64(1), accountdomain.TypeWcMinip, "openid")
+	parent.ID = meta.FromUint64(3)
+	accounts := &accountRepoStub{accounts: map[meta.ID]*accountdomain.Account{opera.ID: opera, parent.ID: parent}}
+
+	seed := func() *sessionStoreStub {
+		store := &sessionStoreStub{sessions: map[string]*sessiondomain.Session{}}
+		for i, id := range []string{"old", "new"} {
+			sess := sessiondomain.New(id, opera.UserID, opera.ID, meta.FromUint64(0), []string{"pwd"}, nil, time.Now().Add(time.Hour))
+			sess.CreatedAt = time.Now().Add(time.Duration(i-2) * time.Minute)
+			store.sessions[id] = sess
+		}
+		return store
+	}
+	issue := func(store *sessionStoreStub, action sessiondomain.OverflowAction, accountID meta.ID) error {
+		manager := sessiondomain.NewManager(store, sessiondomain.WithConcurrencyPolicy(accounts, sessiondomain.ConcurrencyPolicy{
+			accountdomain.TypeOpera: {MaxSessions: 2, OnOverflow: action},
+		}))
+		access := token.NewAccessToken("aid", "aval", "sid", opera.UserID, accountID, meta.FromUint64(0), time.Minute)
+		issuer := token.NewTokenIssuer(&genStub{tok: access}, &storeStub{}, manager, time.Minute, time.Hour)
+		_, err := issuer.IssueToken(context.Background(), &authentication.Principal{UserID: opera.UserID, AccountID: accountID})
+		return err
+	}
+
+	// reject：保留既有会话，拒绝新登录
+	rejectStore := seed()
+	err := issue(rejectStore, sessiondomain.OverflowReject, opera.ID)
+	require.True(t, perrors.IsCode(err, code.ErrSessionLimitExceeded))
+	require.Len(t, rejectStore.sessions, 2)
+
+	// evict_oldest：挤掉最早的会话
+	evictStore := seed()
+	require.NoError(t, issue(evictStore, sessiondomain.OverflowEvictOldest, opera.ID))
+	require.Len(t, evictStore.sessions, 3)
+	require.False(t, evictStore.sessions["old"].IsActive())
+	require.Equal(t, sessiondomain.RevokeReasonLimitEvicted, evictStore.sessions["old"].RevokeReason)
+	require.True(t, evictStore.sessions["new"].IsActive())
+
+	// 未配置上限的账号类型不受限制
+	parentStore := &sessionStoreStub{}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, issue(parentStore, sessiondomain.OverflowReject, parent.ID))
+	}
+	require.Len(t, parentStore.sessions, 3)
+}
+
 func TestIssueServiceToken_HappyPathAndValidation(t *testing.T) {
 	serviceTok := token.NewServiceToken("sid", "sval", "service:qs-server", []string{"iam-service"}, map[string]string{"scope": "internal"}, time.Minute)
 	gen := &genStub{serviceTok: serviceTok}
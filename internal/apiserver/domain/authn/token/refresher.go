@@ -84,8 +84,8 @@ func (s *TokenRefresher) RefreshToken(ctx context.Context, refreshTokenValue str
 	if err != nil {
 		return nil, perrors.WrapC(err, code.ErrInternalServerError, "failed to load session")
 	}
-	if sess == nil || !sess.IsActive() {
-		return nil, perrors.WithCode(code.ErrTokenInvalid, "session has been revoked or expired")
+	if err := s.checkSession(ctx, sess); err != nil {
+		return nil, err
 	}
 
 	decision, err := s.accessChecker.Evaluate(ctx, refreshToken.UserID, refreshToken.AccountID)
@@ -194,6 +194,10 @@ func (s *TokenRefresher) RevokeRefreshToken(ctx context.Context, refreshTokenVal
 	return nil
 }
 
+func (s *TokenRefresher) checkSession(ctx context.Context, sess *sessiondomain.Session) error {
+	return checkSession(ctx, s.sessionManager, sess)
+}
+
 func subjectAccessError(status sessiondomain.SubjectAccessStatus) error {
 	switch status {
 	case sessiondomain.SubjectAccessBlocked:
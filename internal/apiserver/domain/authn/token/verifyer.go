@@ -84,8 +84,8 @@ func (s *TokenVerifyer) VerifyAccessToken(ctx context.Context, tokenValue string
 	if err != nil {
 		return nil, perrors.WrapC(err, code.ErrInternalServerError, "failed to load session")
 	}
-	if sess == nil || !sess.IsActive() {
-		return nil, perrors.WithCode(code.ErrTokenInvalid, "session has been revoked or expired")
+	if err := s.checkSession(ctx, sess); err != nil {
+		return nil, err
 	}
 
 	decision, err := s.accessChecker.Evaluate(ctx, claims.UserID, claims.AccountID)
@@ -99,6 +99,28 @@ func (s *TokenVerifyer) VerifyAccessToken(ctx context.Context, tokenValue string
 	return claims, nil
 }
 
+func (s *TokenVerifyer) checkSession(ctx context.Context, sess *sessiondomain.Session) error {
+	return checkSession(ctx, s.sessionManager, sess)
+}
+
+// checkSession 经会话管理器判定会话可用性，超时类原因映射为独立错误码
+func checkSession(ctx context.Context, manager SessionManager, sess *sessiondomain.Session) error {
+	reason, err := manager.Evaluate(ctx, sess)
+	if err != nil {
+		return perrors.WrapC(err, code.ErrInternalServerError, "failed to evaluate session")
+	}
+	switch reason {
+	case sessiondomain.EndReasonNone:
+		return nil
+	case sessiondomain.EndReasonIdleTimeout:
+		return perrors.WithCode(code.ErrSessionIdleTimeout, "session idle timeout, please sign in again")
+	case sessiondomain.EndReasonMaxLifetime:
+		return perrors.WithCode(code.ErrSessionLifetimeExceeded, "session lifetime exceeded, please sign in again")
+	default:
+		return perrors.WithCode(code.ErrTokenInvalid, "session has been revoked or expired")
+	}
+}
+
 func subjectAccessVerifyError(status sessiondomain.SubjectAccessStatus) error {
 	switch status {
 	case sessiondomain.SubjectAccessBlocked:
@@ -1,6 +1,8 @@
 package assignment
 
 import (
+	"time"
+
 	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
 )
 
@@ -12,6 +14,9 @@ type Assignment struct {
 	RoleID      uint64      // 角色ID
 	TenantID    string      // 租户ID（域）
 	GrantedBy   string      // 授权人
+	NotBefore   *time.Time  // 生效时间，nil 表示立即生效
+	ExpiresAt   *time.Time  // 失效时间，nil 表示永久有效
+	Status      Status      // 有效期状态，决定 Casbin 中是否存在对应的 g 规则
 }
 
 // NewAssignment 创建新赋权
@@ -21,6 +26,7 @@ func NewAssignment(subjectType SubjectType, subjectID string, roleID uint64, ten
 		SubjectID:   subjectID,
 		RoleID:      roleID,
 		TenantID:    tenantID,
+		Status:      StatusActive,
 	}
 	for _, opt := range opts {
 		opt(&a)
@@ -31,8 +37,33 @@ func NewAssignment(subjectType SubjectType, subjectID string, roleID uint64, ten
 // AssignmentOption 赋权选项
 type AssignmentOption func(*Assignment)
 
-func WithID(id AssignmentID) AssignmentOption  { return func(a *Assignment) { a.ID = id } }
-func WithGrantedBy(by string) AssignmentOption { return func(a *Assignment) { a.GrantedBy = by } }
+func WithID(id AssignmentID) AssignmentOption   { return func(a *Assignment) { a.ID = id } }
+func WithGrantedBy(by string) AssignmentOption  { return func(a *Assignment) { a.GrantedBy = by } }
+func WithStatus(status Status) AssignmentOption { return func(a *Assignment) { a.Status = status } }
+
+// WithValidity 设置有效期，nil 表示对应边界不限
+func WithValidity(notBefore, expiresAt *time.Time) AssignmentOption {
+	return func(a *Assignment) {
+		a.NotBefore = notBefore
+		a.ExpiresAt = expiresAt
+	}
+}
+
+// StatusAt 返回赋权在 now 时刻应处的状态
+func (a *Assignment) StatusAt(now time.Time) Status {
+	if a.ExpiresAt != nil && !now.Before(*a.ExpiresAt) {
+		return StatusExpired
+	}
+	if a.NotBefore != nil && now.Before(*a.NotBefore) {
+		return StatusPending
+	}
+	return StatusActive
+}
+
+// Effective 赋权当前是否持有角色（待生效与已过期的赋权不对应 g 规则）
+func (a *Assignment) Effective() bool {
+	return a.Status == StatusActive
+}
 
 // SubjectKey 返回 Casbin 中的主体标识
 func (a *Assignment) SubjectKey() string {
@@ -61,6 +92,19 @@ func (id AssignmentID) String() string {
 	return meta.ID(id).String()
 }
 
+// Status 赋权有效期状态
+type Status string
+
+const (
+	StatusPending Status = "pending" // 未到生效时间
+	StatusActive  Status = "active"  // 生效中
+	StatusExpired Status = "expired" // 已过失效时间，仅保留历史记录
+)
+
+func (s Status) String() string {
+	return string(s)
+}
+
 // SubjectType 主体类型
 type SubjectType string
 
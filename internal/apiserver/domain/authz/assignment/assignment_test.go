@@ -2,6 +2,7 @@ package assignment_test
 
 import (
 	"testing"
+	"time"
 
 	assignment "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
 	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
@@ -21,3 +22,18 @@ func TestAssignment_CreateAndKeys(t *testing.T) {
 	id := meta.FromUint64(42)
 	assert.Equal(t, id.String(), rk[len("role:"):])
 }
+
+func TestAssignment_StatusAt(t *testing.T) {
+	notBefore := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := notBefore.Add(14 * 24 * time.Hour)
+	a := assignment.NewAssignment(assignment.SubjectTypeUser, "u1", 42, "tenant", assignment.WithValidity(&notBefore, &expiresAt))
+
+	assert.Equal(t, assignment.StatusActive, a.Status, "新建赋权默认生效，由调用方按 StatusAt 修正")
+	assert.Equal(t, assignment.StatusPending, a.StatusAt(notBefore.Add(-time.Second)))
+	assert.Equal(t, assignment.StatusActive, a.StatusAt(notBefore))
+	assert.Equal(t, assignment.StatusActive, a.StatusAt(expiresAt.Add(-time.Second)))
+	assert.Equal(t, assignment.StatusExpired, a.StatusAt(expiresAt))
+
+	permanent := assignment.NewAssignment(assignment.SubjectTypeUser, "u1", 42, "tenant")
+	assert.Equal(t, assignment.StatusActive, permanent.StatusAt(expiresAt.Add(365*24*time.Hour)))
+}
@@ -2,6 +2,7 @@ package assignment
 
 import (
 	"context"
+	"time"
 )
 
 // Commander 赋权命令接口（Driving Port - 写操作）
@@ -15,6 +16,9 @@ type Commander interface {
 
 	// RevokeByID 根据ID撤销授权
 	RevokeByID(ctx context.Context, cmd RevokeByIDCommand) error
+
+	// SyncExternalRoles 按外部身份源同步主体角色
+	SyncExternalRoles(ctx context.Context, cmd SyncExternalRolesCommand) error
 }
 
 // GrantCommand 授权命令
@@ -24,6 +28,8 @@ type GrantCommand struct {
 	RoleID      uint64      // 角色ID
 	TenantID    string      // 租户ID
 	GrantedBy   string      // 授权人
+	NotBefore   *time.Time  // 生效时间（可选）
+	ExpiresAt   *time.Time  // 失效时间（可选）
 }
 
 // RevokeCommand 撤销授权命令
@@ -40,6 +46,16 @@ type RevokeByIDCommand struct {
 	TenantID     string       // 租户ID
 }
 
+// SyncExternalRolesCommand 外部角色同步命令
+// Source 标识外部身份源（如 oidc:corp-sso），同步只增删由该来源授予的赋权
+type SyncExternalRolesCommand struct {
+	SubjectType SubjectType // 主体类型
+	SubjectID   string      // 主体ID
+	TenantID    string      // 租户ID
+	Source      string      // 外部身份源，写入 GrantedBy
+	RoleNames   []string    // 外部声明映射出的角色名
+}
+
 // Queryer 赋权查询接口（Driving Port - 读操作）
 // 定义赋权查询的用例接口，遵循 CQRS 原则
 type Queryer interface {
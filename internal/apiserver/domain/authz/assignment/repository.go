@@ -2,6 +2,7 @@ package assignment
 
 import (
 	"context"
+	"time"
 )
 
 // Repository 赋权仓储接口（Driven Port）
@@ -16,6 +17,17 @@ type Repository interface {
 	FindByID(ctx context.Context, id AssignmentID) (*Assignment, error)
 	// ListBySubject 根据主体列出赋权
 	ListBySubject(ctx context.Context, subjectType SubjectType, subjectID, tenantID string) ([]*Assignment, error)
+	// ListBySubjectAcrossTenants 列出主体在所有租户下的赋权
+	ListBySubjectAcrossTenants(ctx context.Context, subjectType SubjectType, subjectID string) ([]*Assignment, error)
 	// ListByRole 根据角色列出赋权
 	ListByRole(ctx context.Context, roleID uint64, tenantID string) ([]*Assignment, error)
 }
+
+// ScheduleRepository 赋权有效期调度仓储接口（Driven Port）
+type ScheduleRepository interface {
+	// ListDue 列出 now 时刻已越过有效期边界、状态需要切换的赋权
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*Assignment, error)
+	// TransitionStatus 仅当当前状态为 from 时切换为 to，返回是否切换成功；
+	// 多副本同时调度时只有一个副本切换成功
+	TransitionStatus(ctx context.Context, id AssignmentID, from, to Status) (bool, error)
+}
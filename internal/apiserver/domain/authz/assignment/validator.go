@@ -56,6 +56,9 @@ func (v *validator) ValidateGrantCommand(cmd GrantCommand) error {
 	if cmd.GrantedBy == "" {
 		return errors.WithCode(code.ErrInvalidArgument, "授权人不能为空")
 	}
+	if cmd.NotBefore != nil && cmd.ExpiresAt != nil && !cmd.ExpiresAt.After(*cmd.NotBefore) {
+		return errors.WithCode(code.ErrInvalidArgument, "失效时间必须晚于生效时间")
+	}
 	return nil
 }
 
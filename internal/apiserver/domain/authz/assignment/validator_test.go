@@ -3,6 +3,7 @@ package assignment_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	perrors "github.com/FangcunMount/component-base/pkg/errors"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
@@ -49,6 +50,19 @@ func TestValidateGrantAndRevokeCommands_Invalids(t *testing.T) {
 	require.Error(t, err)
 	assert.True(t, perrors.IsCode(err, code.ErrInvalidArgument))
 
+	notBefore := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	err = v.ValidateGrantCommand(assignment.GrantCommand{
+		SubjectType: assignment.SubjectTypeUser,
+		SubjectID:   "100",
+		RoleID:      1,
+		TenantID:    "t1",
+		GrantedBy:   "1",
+		NotBefore:   &notBefore,
+		ExpiresAt:   &notBefore,
+	})
+	require.Error(t, err)
+	assert.True(t, perrors.IsCode(err, code.ErrInvalidArgument))
+
 	// validate list queries
 	err = v.ValidateListBySubjectQuery("", "")
 	require.Error(t, err)
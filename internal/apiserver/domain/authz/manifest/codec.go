@@ -0,0 +1,37 @@
+package manifest
+
+import (
+	"bytes"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Parse 解析 YAML 或 JSON 文档（JSON 是 YAML 的子集），拒绝未知字段以尽早发现拼写错误
+func Parse(data []byte) (*Manifest, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var m Manifest
+	if err := decoder.Decode(&m); err != nil {
+		if err == io.EOF {
+			return nil, invalid("文档不能为空")
+		}
+		return nil, invalid("文档解析失败: %v", err)
+	}
+	return &m, nil
+}
+
+// EncodeYAML 将文档编码为 YAML
+func EncodeYAML(m *Manifest) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(m); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,39 @@
+package manifest
+
+import "context"
+
+// Commander 声明式配置命令接口（Driving Port - 写操作）
+type Commander interface {
+	// Apply 在单个事务内把租户授权配置变更为文档描述的状态，有变更时递增一次策略版本
+	Apply(ctx context.Context, cmd ApplyCommand) (*ApplyResult, error)
+}
+
+// Queryer 声明式配置查询接口（Driving Port - 读操作）
+type Queryer interface {
+	// Export 导出租户当前授权配置
+	Export(ctx context.Context, query ExportQuery) (*Manifest, error)
+	// Plan 计算导入文档将产生的变更，不产生副作用
+	Plan(ctx context.Context, cmd ApplyCommand) (*Plan, error)
+}
+
+// ExportQuery 导出查询
+type ExportQuery struct {
+	TenantID           string
+	IncludeAssignments bool
+}
+
+// ApplyCommand 导入命令
+type ApplyCommand struct {
+	TenantID  string
+	Manifest  *Manifest
+	Prune     bool
+	ChangedBy string
+	Reason    string
+}
+
+// ApplyResult 导入结果
+type ApplyResult struct {
+	Plan *Plan
+	// Version 变更后的策略版本；计划为空时为 0（未递增）
+	Version int64
+}
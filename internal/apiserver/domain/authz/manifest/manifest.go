@@ -0,0 +1,198 @@
+// Package manifest 声明式授权配置（policy-as-code）领域包
+//
+// 以 YAML/JSON 文档描述一个租户的资源、角色、角色权限与（可选的）赋权，
+// 通过 Export 导出、Plan 计算差异、Apply 在单个事务内整体落地，
+// 使授权配置可以纳入 git 管理并在环境之间推广。
+package manifest
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+const (
+	// APIVersion 文档版本
+	APIVersion = "iam.fangcunmount.cn/v1"
+	// Kind 文档类型
+	Kind = "AuthzConfig"
+)
+
+// Manifest 声明式授权配置文档
+type Manifest struct {
+	APIVersion string `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string `json:"kind" yaml:"kind"`
+	// Tenant 文档所属租户；非空时须与执行导入的租户一致，防止误导入其他环境
+	Tenant    string         `json:"tenant,omitempty" yaml:"tenant,omitempty"`
+	Resources []ResourceSpec `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Roles     []RoleSpec     `json:"roles,omitempty" yaml:"roles,omitempty"`
+	// Assignments 为 nil 时不管理赋权；显式给出（包括空列表）时以文档为准同步文档内角色的赋权
+	Assignments []AssignmentSpec `json:"assignments,omitempty" yaml:"assignments,omitempty"`
+}
+
+// ResourceSpec 资源声明（资源目录全局共享，导入时只创建或更新，不删除）
+type ResourceSpec struct {
+	Key         string   `json:"key" yaml:"key"`
+	DisplayName string   `json:"display_name" yaml:"display_name"`
+	AppName     string   `json:"app_name" yaml:"app_name"`
+	Domain      string   `json:"domain" yaml:"domain"`
+	Type        string   `json:"type" yaml:"type"`
+	Actions     []string `json:"actions" yaml:"actions"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// RoleSpec 角色声明
+type RoleSpec struct {
+	Name        string           `json:"name" yaml:"name"`
+	DisplayName string           `json:"display_name" yaml:"display_name"`
+	Description string           `json:"description,omitempty" yaml:"description,omitempty"`
+	Permissions []PermissionSpec `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+}
+
+// PermissionSpec 角色在资源上允许的动作
+type PermissionSpec struct {
+	Resource string   `json:"resource" yaml:"resource"`
+	Actions  []string `json:"actions" yaml:"actions"`
+}
+
+// AssignmentSpec 主体持有的角色
+type AssignmentSpec struct {
+	SubjectType string   `json:"subject_type" yaml:"subject_type"`
+	SubjectID   string   `json:"subject_id" yaml:"subject_id"`
+	Roles       []string `json:"roles" yaml:"roles"`
+}
+
+// New 创建空文档
+func New(tenantID string) *Manifest {
+	return &Manifest{APIVersion: APIVersion, Kind: Kind, Tenant: tenantID}
+}
+
+// Validate 校验文档结构与内部引用：名称唯一、必填字段、动作在资源声明范围内
+//
+// 权限引用的资源可以不在文档中声明（引用已有资源目录），这部分在生成计划时结合现状校验。
+func (m *Manifest) Validate() error {
+	if m == nil {
+		return invalid("文档不能为空")
+	}
+	if m.APIVersion != APIVersion {
+		return invalid("apiVersion 必须为 %s", APIVersion)
+	}
+	if m.Kind != Kind {
+		return invalid("kind 必须为 %s", Kind)
+	}
+
+	resources := make(map[string]struct{}, len(m.Resources))
+	for i, res := range m.Resources {
+		if res.Key == "" {
+			return invalid("resources[%d].key 不能为空", i)
+		}
+		if _, dup := resources[res.Key]; dup {
+			return invalid("资源 %s 重复声明", res.Key)
+		}
+		resources[res.Key] = struct{}{}
+		if res.DisplayName == "" || res.AppName == "" || res.Domain == "" || res.Type == "" {
+			return invalid("资源 %s 的 display_name、app_name、domain、type 不能为空", res.Key)
+		}
+		if len(res.Actions) == 0 {
+			return invalid("资源 %s 的动作列表不能为空", res.Key)
+		}
+	}
+
+	roles := make(map[string]struct{}, len(m.Roles))
+	for i, role := range m.Roles {
+		if role.Name == "" {
+			return invalid("roles[%d].name 不能为空", i)
+		}
+		if _, dup := roles[role.Name]; dup {
+			return invalid("角色 %s 重复声明", role.Name)
+		}
+		roles[role.Name] = struct{}{}
+		if role.DisplayName == "" {
+			return invalid("角色 %s 的 display_name 不能为空", role.Name)
+		}
+		for _, perm := range role.Permissions {
+			if perm.Resource == "" || len(perm.Actions) == 0 {
+				return invalid("角色 %s 的权限必须给出 resource 与 actions", role.Name)
+			}
+		}
+	}
+
+	subjects := make(map[string]struct{}, len(m.Assignments))
+	for i, a := range m.Assignments {
+		if a.SubjectType == "" || a.SubjectID == "" {
+			return invalid("assignments[%d] 的 subject_type 与 subject_id 不能为空", i)
+		}
+		switch a.SubjectType {
+		case "user", "group", "service":
+		default:
+			return invalid("assignments[%d].subject_type 不支持 %s", i, a.SubjectType)
+		}
+		key := a.SubjectType + ":" + a.SubjectID
+		if _, dup := subjects[key]; dup {
+			return invalid("主体 %s 重复声明", key)
+		}
+		subjects[key] = struct{}{}
+		for _, name := range a.Roles {
+			if _, ok := roles[name]; !ok {
+				return invalid("主体 %s 引用了文档中未声明的角色 %s", key, name)
+			}
+		}
+	}
+	return nil
+}
+
+// Normalize 对文档内的列表排序去重，使导出结果稳定、便于 diff
+func (m *Manifest) Normalize() {
+	sort.Slice(m.Resources, func(i, j int) bool { return m.Resources[i].Key < m.Resources[j].Key })
+	for i := range m.Resources {
+		m.Resources[i].Actions = sortedUnique(m.Resources[i].Actions)
+	}
+
+	sort.Slice(m.Roles, func(i, j int) bool { return m.Roles[i].Name < m.Roles[j].Name })
+	for i := range m.Roles {
+		perms := make(map[string][]string)
+		for _, perm := range m.Roles[i].Permissions {
+			perms[perm.Resource] = append(perms[perm.Resource], perm.Actions...)
+		}
+		merged := make([]PermissionSpec, 0, len(perms))
+		for res, actions := range perms {
+			merged = append(merged, PermissionSpec{Resource: res, Actions: sortedUnique(actions)})
+		}
+		sort.Slice(merged, func(a, b int) bool { return merged[a].Resource < merged[b].Resource })
+		m.Roles[i].Permissions = merged
+	}
+
+	sort.Slice(m.Assignments, func(i, j int) bool {
+		if m.Assignments[i].SubjectType != m.Assignments[j].SubjectType {
+			return m.Assignments[i].SubjectType < m.Assignments[j].SubjectType
+		}
+		return m.Assignments[i].SubjectID < m.Assignments[j].SubjectID
+	})
+	for i := range m.Assignments {
+		m.Assignments[i].Roles = sortedUnique(m.Assignments[i].Roles)
+	}
+}
+
+func sortedUnique(values []string) []string {
+	out := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func invalid(format string, args ...interface{}) error {
+	return errors.WithCode(code.ErrAuthzConfigInvalid, format, args...)
+}
@@ -0,0 +1,124 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+const sampleYAML = `
+apiVersion: iam.fangcunmount.cn/v1
+kind: AuthzConfig
+tenant: "1"
+resources:
+  - key: scale:form:*
+    display_name: 量表
+    app_name: scale
+    domain: form
+    type: form
+    actions: [read_all, update_all, create]
+roles:
+  - name: scale-admin
+    display_name: 量表管理员
+    permissions:
+      - resource: scale:form:*
+        actions: [update_all, read_all]
+assignments: []
+`
+
+func TestParse_YAMLAndJSON(t *testing.T) {
+	m, err := Parse([]byte(sampleYAML))
+	require.NoError(t, err)
+	require.NoError(t, m.Validate())
+	assert.Equal(t, "1", m.Tenant)
+	require.Len(t, m.Roles, 1)
+	assert.Equal(t, []string{"update_all", "read_all"}, m.Roles[0].Permissions[0].Actions)
+	assert.NotNil(t, m.Assignments, "显式给出的空赋权列表表示管理赋权")
+
+	j, err := Parse([]byte(`{"apiVersion":"iam.fangcunmount.cn/v1","kind":"AuthzConfig","roles":[{"name":"a","display_name":"A"}]}`))
+	require.NoError(t, err)
+	require.NoError(t, j.Validate())
+	assert.Nil(t, j.Assignments, "未给出赋权时不管理赋权")
+}
+
+func TestParse_RejectsUnknownFieldsAndEmpty(t *testing.T) {
+	_, err := Parse([]byte("apiVersion: iam.fangcunmount.cn/v1\nkind: AuthzConfig\nrole: []\n"))
+	require.Error(t, err)
+	assert.True(t, errors.IsCode(err, code.ErrAuthzConfigInvalid))
+
+	_, err = Parse(nil)
+	assert.True(t, errors.IsCode(err, code.ErrAuthzConfigInvalid))
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() *Manifest {
+		m, err := Parse([]byte(sampleYAML))
+		require.NoError(t, err)
+		return m
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(m *Manifest)
+	}{
+		{"wrong kind", func(m *Manifest) { m.Kind = "Other" }},
+		{"duplicate resource", func(m *Manifest) { m.Resources = append(m.Resources, m.Resources[0]) }},
+		{"resource without actions", func(m *Manifest) { m.Resources[0].Actions = nil }},
+		{"duplicate role", func(m *Manifest) { m.Roles = append(m.Roles, m.Roles[0]) }},
+		{"role without display name", func(m *Manifest) { m.Roles[0].DisplayName = "" }},
+		{"permission without actions", func(m *Manifest) { m.Roles[0].Permissions[0].Actions = nil }},
+		{"unsupported subject type", func(m *Manifest) {
+			m.Assignments = []AssignmentSpec{{SubjectType: "robot", SubjectID: "1"}}
+		}},
+		{"undeclared role", func(m *Manifest) {
+			m.Assignments = []AssignmentSpec{{SubjectType: "user", SubjectID: "1", Roles: []string{"ghost"}}}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := valid()
+			tt.mutate(m)
+			err := m.Validate()
+			require.Error(t, err)
+			assert.True(t, errors.IsCode(err, code.ErrAuthzConfigInvalid))
+		})
+	}
+}
+
+func TestNormalize_MergesPermissionsAndSorts(t *testing.T) {
+	m := New("1")
+	m.Roles = []RoleSpec{
+		{Name: "b", DisplayName: "B", Permissions: []PermissionSpec{
+			{Resource: "x", Actions: []string{"update", "read"}},
+			{Resource: "a", Actions: []string{"read"}},
+			{Resource: "x", Actions: []string{"read", "delete"}},
+		}},
+		{Name: "a", DisplayName: "A"},
+	}
+	m.Normalize()
+
+	assert.Equal(t, "a", m.Roles[0].Name)
+	assert.Equal(t, []PermissionSpec{
+		{Resource: "a", Actions: []string{"read"}},
+		{Resource: "x", Actions: []string{"delete", "read", "update"}},
+	}, m.Roles[1].Permissions)
+	assert.Nil(t, m.Assignments)
+}
+
+func TestEncodeYAML_RoundTrip(t *testing.T) {
+	m, err := Parse([]byte(sampleYAML))
+	require.NoError(t, err)
+	m.Normalize()
+
+	data, err := EncodeYAML(m)
+	require.NoError(t, err)
+	again, err := Parse(data)
+	require.NoError(t, err)
+	again.Normalize()
+	assert.Equal(t, m.Resources, again.Resources)
+	assert.Equal(t, m.Roles, again.Roles)
+}
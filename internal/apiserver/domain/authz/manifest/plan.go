@@ -0,0 +1,462 @@
+package manifest
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/resource"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/role"
+)
+
+// Snapshot 租户授权配置现状
+type Snapshot struct {
+	TenantID    string
+	Resources   []*resource.Resource
+	Roles       []*role.Role
+	Policies    []policy.PolicyRule      // 租户域下的 p 规则
+	Assignments []*assignment.Assignment // 未过期的赋权，含待生效记录
+}
+
+// ChangeKind 变更对象类型
+type ChangeKind string
+
+const (
+	ChangeKindResource   ChangeKind = "resource"
+	ChangeKindRole       ChangeKind = "role"
+	ChangeKindPolicy     ChangeKind = "policy"
+	ChangeKindAssignment ChangeKind = "assignment"
+)
+
+// ChangeOp 变更操作
+type ChangeOp string
+
+const (
+	ChangeOpCreate ChangeOp = "create"
+	ChangeOpUpdate ChangeOp = "update"
+	ChangeOpDelete ChangeOp = "delete"
+)
+
+// Change 计划中的单项变更（面向人阅读的摘要）
+type Change struct {
+	Kind   ChangeKind
+	Op     ChangeOp
+	Key    string   // resource: 资源键；role: 角色名；policy: "角色 资源 动作"；assignment: "主体 -> 角色"
+	Fields []string // update 时发生变化的字段
+}
+
+// AssignmentChange 赋权变更
+type AssignmentChange struct {
+	SubjectType assignment.SubjectType
+	SubjectID   string
+	RoleName    string
+	RoleID      uint64 // 撤销时为现有角色ID；授予时角色可能尚未创建，由执行方按名称解析
+}
+
+// SubjectKey 返回 Casbin 中的主体标识
+func (c AssignmentChange) SubjectKey() string {
+	return string(c.SubjectType) + ":" + c.SubjectID
+}
+
+// PlanOptions 计划选项
+type PlanOptions struct {
+	// Prune 删除文档中未声明的租户角色（连同其策略与赋权）；资源目录全局共享，从不删除
+	Prune bool
+}
+
+// Plan 将现状变更为文档描述状态所需的全部操作
+type Plan struct {
+	TenantID string
+	Changes  []Change
+
+	ResourceCreates   []resource.Resource
+	ResourceUpdates   []resource.Resource
+	RoleCreates       []role.Role
+	RoleUpdates       []role.Role
+	RoleDeletes       []role.Role
+	PolicyAdds        []policy.PolicyRule
+	PolicyRemoves     []policy.PolicyRule
+	AssignmentGrants  []AssignmentChange
+	AssignmentRevokes []AssignmentChange
+}
+
+// Empty 计划是否无任何变更
+func (p *Plan) Empty() bool {
+	return p == nil || len(p.Changes) == 0
+}
+
+// Export 将现状导出为文档；includeAssignments 为 false 时不包含赋权
+//
+// 文档不表达赋权有效期，存在带有效期的赋权时拒绝导出赋权，避免重新导入后变为永久赋权。
+func (s *Snapshot) Export(includeAssignments bool) (*Manifest, error) {
+	m := New(s.TenantID)
+
+	for _, res := range s.Resources {
+		m.Resources = append(m.Resources, ResourceSpec{
+			Key:         res.Key,
+			DisplayName: res.DisplayName,
+			AppName:     res.AppName,
+			Domain:      res.Domain,
+			Type:        res.Type,
+			Actions:     append([]string(nil), res.Actions...),
+			Description: res.Description,
+		})
+	}
+
+	rules := s.rulesByRoleKey()
+	for _, r := range s.Roles {
+		spec := RoleSpec{Name: r.Name, DisplayName: r.DisplayName, Description: r.Description}
+		for _, rule := range rules[r.Key()] {
+			spec.Permissions = append(spec.Permissions, PermissionSpec{Resource: rule.Obj, Actions: []string{rule.Act}})
+		}
+		m.Roles = append(m.Roles, spec)
+	}
+
+	if includeAssignments {
+		m.Assignments = []AssignmentSpec{}
+		names := s.roleNamesByID()
+		index := make(map[string]int)
+		for _, a := range s.Assignments {
+			name, ok := names[a.RoleID]
+			if !ok {
+				continue
+			}
+			if timeBound(a) {
+				return nil, invalid("赋权 %s -> %s 带有效期，声明式配置无法表达，请导出时不包含赋权", a.SubjectKey(), name)
+			}
+			key := a.SubjectKey()
+			i, ok := index[key]
+			if !ok {
+				i = len(m.Assignments)
+				index[key] = i
+				m.Assignments = append(m.Assignments, AssignmentSpec{SubjectType: string(a.SubjectType), SubjectID: a.SubjectID})
+			}
+			m.Assignments[i].Roles = append(m.Assignments[i].Roles, name)
+		}
+	}
+
+	m.Normalize()
+	return m, nil
+}
+
+// BuildPlan 比较现状与文档，生成变更计划
+//
+// 只管理文档中声明的对象：文档内角色的权限与赋权以文档为准（多删少补），
+// 未声明的角色在 Prune 时整体删除，否则保持不变。
+func BuildPlan(current *Snapshot, desired *Manifest, opts PlanOptions) (*Plan, error) {
+	if err := desired.Validate(); err != nil {
+		return nil, err
+	}
+	if desired.Tenant != "" && desired.Tenant != current.TenantID {
+		return nil, invalid("文档属于租户 %s，不能导入到租户 %s", desired.Tenant, current.TenantID)
+	}
+	desired.Normalize()
+
+	plan := &Plan{TenantID: current.TenantID}
+	actions := plan.diffResources(current, desired)
+	pruned := plan.diffRoles(current, desired, opts.Prune)
+	if err := plan.diffPolicies(current, desired, actions, pruned); err != nil {
+		return nil, err
+	}
+	if err := plan.diffAssignments(current, desired, pruned); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// diffResources 比较资源，返回变更后各资源允许的动作
+func (p *Plan) diffResources(current *Snapshot, desired *Manifest) map[string][]string {
+	actions := make(map[string][]string, len(current.Resources)+len(desired.Resources))
+	existing := make(map[string]*resource.Resource, len(current.Resources))
+	for _, res := range current.Resources {
+		existing[res.Key] = res
+		actions[res.Key] = res.Actions
+	}
+
+	for _, spec := range desired.Resources {
+		actions[spec.Key] = spec.Actions
+		target := resource.NewResource(spec.Key, spec.Actions,
+			resource.WithDisplayName(spec.DisplayName),
+			resource.WithAppName(spec.AppName),
+			resource.WithDomain(spec.Domain),
+			resource.WithType(spec.Type),
+			resource.WithDescription(spec.Description),
+		)
+		old, ok := existing[spec.Key]
+		if !ok {
+			p.ResourceCreates = append(p.ResourceCreates, target)
+			p.add(ChangeKindResource, ChangeOpCreate, spec.Key)
+			continue
+		}
+
+		var fields []string
+		fields = changed(fields, "display_name", old.DisplayName != spec.DisplayName)
+		fields = changed(fields, "app_name", old.AppName != spec.AppName)
+		fields = changed(fields, "domain", old.Domain != spec.Domain)
+		fields = changed(fields, "type", old.Type != spec.Type)
+		fields = changed(fields, "actions", !sameSet(old.Actions, spec.Actions))
+		fields = changed(fields, "description", old.Description != spec.Description)
+		if len(fields) == 0 {
+			continue
+		}
+		target.ID = old.ID
+		p.ResourceUpdates = append(p.ResourceUpdates, target)
+		p.Changes = append(p.Changes, Change{Kind: ChangeKindResource, Op: ChangeOpUpdate, Key: spec.Key, Fields: fields})
+	}
+	return actions
+}
+
+// diffRoles 比较角色，返回被清理的角色键集合
+func (p *Plan) diffRoles(current *Snapshot, desired *Manifest, prune bool) map[string]struct{} {
+	declared := make(map[string]struct{}, len(desired.Roles))
+	existing := make(map[string]*role.Role, len(current.Roles))
+	for _, r := range current.Roles {
+		existing[r.Name] = r
+	}
+
+	for _, spec := range desired.Roles {
+		declared[spec.Name] = struct{}{}
+		old, ok := existing[spec.Name]
+		if !ok {
+			p.RoleCreates = append(p.RoleCreates, role.NewRole(spec.Name, spec.DisplayName, current.TenantID,
+				role.WithDescription(spec.Description)))
+			p.add(ChangeKindRole, ChangeOpCreate, spec.Name)
+			continue
+		}
+
+		var fields []string
+		fields = changed(fields, "display_name", old.DisplayName != spec.DisplayName)
+		fields = changed(fields, "description", old.Description != spec.Description)
+		if len(fields) == 0 {
+			continue
+		}
+		updated := *old
+		updated.DisplayName = spec.DisplayName
+		updated.Description = spec.Description
+		p.RoleUpdates = append(p.RoleUpdates, updated)
+		p.Changes = append(p.Changes, Change{Kind: ChangeKindRole, Op: ChangeOpUpdate, Key: spec.Name, Fields: fields})
+	}
+
+	pruned := make(map[string]struct{})
+	if !prune {
+		return pruned
+	}
+	for _, r := range sortedRoles(current.Roles) {
+		if _, ok := declared[r.Name]; ok {
+			continue
+		}
+		pruned[r.Key()] = struct{}{}
+		p.RoleDeletes = append(p.RoleDeletes, *r)
+		p.add(ChangeKindRole, ChangeOpDelete, r.Name)
+	}
+	return pruned
+}
+
+func (p *Plan) diffPolicies(current *Snapshot, desired *Manifest, actions map[string][]string, pruned map[string]struct{}) error {
+	want := make(map[policy.PolicyRule]struct{})
+	managed := make(map[string]struct{}, len(desired.Roles))
+	for _, spec := range desired.Roles {
+		r := role.NewRole(spec.Name, spec.DisplayName, current.TenantID)
+		managed[r.Key()] = struct{}{}
+		for _, perm := range spec.Permissions {
+			allowed, ok := actions[perm.Resource]
+			if !ok {
+				return invalid("角色 %s 引用了不存在的资源 %s", spec.Name, perm.Resource)
+			}
+			for _, act := range perm.Actions {
+				if !contains(allowed, act) {
+					return invalid("资源 %s 不支持动作 %s（角色 %s）", perm.Resource, act, spec.Name)
+				}
+				want[policy.BuildPolicyRule(r.Key(), current.TenantID, perm.Resource, act)] = struct{}{}
+			}
+		}
+	}
+
+	have := make(map[policy.PolicyRule]struct{}, len(current.Policies))
+	var removes []policy.PolicyRule
+	for _, rule := range current.Policies {
+		if rule.Dom != current.TenantID {
+			continue
+		}
+		have[rule] = struct{}{}
+		_, isManaged := managed[rule.Sub]
+		_, isPruned := pruned[rule.Sub]
+		if _, keep := want[rule]; (isManaged && !keep) || isPruned {
+			removes = append(removes, rule)
+		}
+	}
+	var adds []policy.PolicyRule
+	for rule := range want {
+		if _, ok := have[rule]; !ok {
+			adds = append(adds, rule)
+		}
+	}
+
+	sortRules(removes)
+	sortRules(adds)
+	for _, rule := range removes {
+		p.PolicyRemoves = append(p.PolicyRemoves, rule)
+		p.add(ChangeKindPolicy, ChangeOpDelete, ruleKey(rule))
+	}
+	for _, rule := range adds {
+		p.PolicyAdds = append(p.PolicyAdds, rule)
+		p.add(ChangeKindPolicy, ChangeOpCreate, ruleKey(rule))
+	}
+	return nil
+}
+
+// diffAssignments 计算赋权变更
+// 文档不表达有效期，变更会触及带有效期的赋权时拒绝，此类赋权须通过赋权接口维护
+func (p *Plan) diffAssignments(current *Snapshot, desired *Manifest, pruned map[string]struct{}) error {
+	names := current.roleNamesByID()
+	managed := desired.Assignments != nil
+	declared := make(map[string]struct{}, len(desired.Roles))
+	for _, spec := range desired.Roles {
+		declared[spec.Name] = struct{}{}
+	}
+
+	want := make(map[AssignmentChange]struct{})
+	for _, spec := range desired.Assignments {
+		for _, name := range spec.Roles {
+			want[AssignmentChange{SubjectType: assignment.SubjectType(spec.SubjectType), SubjectID: spec.SubjectID, RoleName: name}] = struct{}{}
+		}
+	}
+
+	have := make(map[AssignmentChange]struct{}, len(current.Assignments))
+	revoked := make(map[AssignmentChange]struct{})
+	bounded := make(map[AssignmentChange]struct{})
+	var revokes []AssignmentChange
+	for _, a := range current.Assignments {
+		name, ok := names[a.RoleID]
+		if !ok {
+			continue
+		}
+		key := AssignmentChange{SubjectType: a.SubjectType, SubjectID: a.SubjectID, RoleName: name}
+		if timeBound(a) {
+			bounded[key] = struct{}{}
+		}
+		if a.Effective() {
+			// 待生效的赋权尚未持有角色，文档声明该赋权时立即授予
+			have[key] = struct{}{}
+		}
+		_, isDeclared := declared[name]
+		_, isPruned := pruned["role:"+name]
+		_, keep := want[key]
+		if _, dup := revoked[key]; dup {
+			continue
+		}
+		if (managed && isDeclared && !keep) || isPruned {
+			revoked[key] = struct{}{}
+			key.RoleID = a.RoleID
+			revokes = append(revokes, key)
+		}
+	}
+	var grants []AssignmentChange
+	for key := range want {
+		if _, ok := have[key]; !ok {
+			grants = append(grants, key)
+		}
+	}
+
+	sortAssignments(revokes)
+	sortAssignments(grants)
+	for _, changes := range [][]AssignmentChange{revokes, grants} {
+		for _, c := range changes {
+			key := c
+			key.RoleID = 0
+			if _, ok := bounded[key]; ok {
+				return invalid("赋权 %s -> %s 带有效期，声明式配置不能变更，请通过赋权接口维护", c.SubjectKey(), c.RoleName)
+			}
+		}
+	}
+	for _, c := range revokes {
+		p.AssignmentRevokes = append(p.AssignmentRevokes, c)
+		p.add(ChangeKindAssignment, ChangeOpDelete, c.SubjectKey()+" -> "+c.RoleName)
+	}
+	for _, c := range grants {
+		p.AssignmentGrants = append(p.AssignmentGrants, c)
+		p.add(ChangeKindAssignment, ChangeOpCreate, c.SubjectKey()+" -> "+c.RoleName)
+	}
+	return nil
+}
+
+// timeBound 赋权是否设置了有效期
+func timeBound(a *assignment.Assignment) bool {
+	return a.NotBefore != nil || a.ExpiresAt != nil
+}
+
+func (p *Plan) add(kind ChangeKind, op ChangeOp, key string) {
+	p.Changes = append(p.Changes, Change{Kind: kind, Op: op, Key: key})
+}
+
+func (s *Snapshot) rulesByRoleKey() map[string][]policy.PolicyRule {
+	rules := make(map[string][]policy.PolicyRule)
+	for _, rule := range s.Policies {
+		if rule.Dom == s.TenantID {
+			rules[rule.Sub] = append(rules[rule.Sub], rule)
+		}
+	}
+	return rules
+}
+
+func (s *Snapshot) roleNamesByID() map[uint64]string {
+	names := make(map[uint64]string, len(s.Roles))
+	for _, r := range s.Roles {
+		names[r.ID.Uint64()] = r.Name
+	}
+	return names
+}
+
+// ruleKey 策略规则的可读标识：角色名 资源 动作
+func ruleKey(rule policy.PolicyRule) string {
+	return strings.TrimPrefix(rule.Sub, "role:") + " " + rule.Obj + " " + rule.Act
+}
+
+func changed(fields []string, name string, diff bool) []string {
+	if diff {
+		return append(fields, name)
+	}
+	return fields
+}
+
+func sameSet(a, b []string) bool {
+	x, y := sortedUnique(a), sortedUnique(b)
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, v string) bool {
+	for _, item := range values {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedRoles(roles []*role.Role) []*role.Role {
+	out := append([]*role.Role(nil), roles...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func sortRules(rules []policy.PolicyRule) {
+	sort.Slice(rules, func(i, j int) bool { return ruleKey(rules[i]) < ruleKey(rules[j]) })
+}
+
+func sortAssignments(changes []AssignmentChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].SubjectKey() != changes[j].SubjectKey() {
+			return changes[i].SubjectKey() < changes[j].SubjectKey()
+		}
+		return changes[i].RoleName < changes[j].RoleName
+	})
+}
@@ -0,0 +1,184 @@
+package manifest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/resource"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/role"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+func snapshot() *Snapshot {
+	form := resource.NewResource("scale:form:*", []string{"read_all", "update_all"},
+		resource.WithID(resource.NewResourceID(1)),
+		resource.WithDisplayName("量表"),
+		resource.WithAppName("scale"),
+		resource.WithDomain("form"),
+		resource.WithType("form"),
+	)
+	admin := role.NewRole("scale-admin", "量表管理员", "t1", role.WithID(meta.FromUint64(10)))
+	viewer := role.NewRole("viewer", "只读", "t1", role.WithID(meta.FromUint64(11)))
+	grant := assignment.NewAssignment(assignment.SubjectTypeUser, "100", 10, "t1")
+	viewerGrant := assignment.NewAssignment(assignment.SubjectTypeUser, "101", 11, "t1")
+
+	return &Snapshot{
+		TenantID:  "t1",
+		Resources: []*resource.Resource{&form},
+		Roles:     []*role.Role{&admin, &viewer},
+		Policies: []policy.PolicyRule{
+			policy.NewPolicyRule("role:scale-admin", "t1", "scale:form:*", "read_all"),
+			policy.NewPolicyRule("role:scale-admin", "t1", "scale:form:*", "update_all"),
+			policy.NewPolicyRule("role:viewer", "t1", "scale:form:*", "read_all"),
+		},
+		Assignments: []*assignment.Assignment{&grant, &viewerGrant},
+	}
+}
+
+func mustExport(t *testing.T, s *Snapshot, includeAssignments bool) *Manifest {
+	t.Helper()
+	m, err := s.Export(includeAssignments)
+	require.NoError(t, err)
+	return m
+}
+
+func TestSnapshotExport_RoundTripIsEmptyPlan(t *testing.T) {
+	current := snapshot()
+	doc := mustExport(t, current, true)
+
+	assert.Equal(t, "t1", doc.Tenant)
+	require.Len(t, doc.Roles, 2)
+	assert.Equal(t, []PermissionSpec{{Resource: "scale:form:*", Actions: []string{"read_all", "update_all"}}}, doc.Roles[0].Permissions)
+	assert.Equal(t, []AssignmentSpec{
+		{SubjectType: "user", SubjectID: "100", Roles: []string{"scale-admin"}},
+		{SubjectType: "user", SubjectID: "101", Roles: []string{"viewer"}},
+	}, doc.Assignments)
+
+	plan, err := BuildPlan(current, doc, PlanOptions{Prune: true})
+	require.NoError(t, err)
+	assert.True(t, plan.Empty(), "%+v", plan.Changes)
+
+	assert.Nil(t, mustExport(t, current, false).Assignments)
+}
+
+func TestBuildPlan_DiffsDeclaredObjects(t *testing.T) {
+	doc := mustExport(t, snapshot(), true)
+	doc.Resources[0].Actions = append(doc.Resources[0].Actions, "export")
+	doc.Resources = append(doc.Resources, ResourceSpec{
+		Key: "scale:report:*", DisplayName: "报告", AppName: "scale", Domain: "report", Type: "report",
+		Actions: []string{"read_all"},
+	})
+	doc.Roles[0].DisplayName = "量表超级管理员"
+	doc.Roles[0].Permissions = []PermissionSpec{
+		{Resource: "scale:form:*", Actions: []string{"read_all", "export"}},
+		{Resource: "scale:report:*", Actions: []string{"read_all"}},
+	}
+	doc.Roles = append(doc.Roles, RoleSpec{Name: "auditor", DisplayName: "审计员"})
+	doc.Assignments[0].Roles = []string{"auditor"}
+
+	plan, err := BuildPlan(snapshot(), doc, PlanOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Change{
+		{Kind: ChangeKindResource, Op: ChangeOpUpdate, Key: "scale:form:*", Fields: []string{"actions"}},
+		{Kind: ChangeKindResource, Op: ChangeOpCreate, Key: "scale:report:*"},
+		{Kind: ChangeKindRole, Op: ChangeOpCreate, Key: "auditor"},
+		{Kind: ChangeKindRole, Op: ChangeOpUpdate, Key: "scale-admin", Fields: []string{"display_name"}},
+		{Kind: ChangeKindPolicy, Op: ChangeOpDelete, Key: "scale-admin scale:form:* update_all"},
+		{Kind: ChangeKindPolicy, Op: ChangeOpCreate, Key: "scale-admin scale:form:* export"},
+		{Kind: ChangeKindPolicy, Op: ChangeOpCreate, Key: "scale-admin scale:report:* read_all"},
+		{Kind: ChangeKindAssignment, Op: ChangeOpDelete, Key: "user:100 -> scale-admin"},
+		{Kind: ChangeKindAssignment, Op: ChangeOpCreate, Key: "user:100 -> auditor"},
+	}, plan.Changes)
+
+	require.Len(t, plan.ResourceUpdates, 1)
+	assert.Equal(t, resource.NewResourceID(1), plan.ResourceUpdates[0].ID)
+	require.Len(t, plan.RoleUpdates, 1)
+	assert.Equal(t, meta.FromUint64(10), plan.RoleUpdates[0].ID)
+	require.Len(t, plan.AssignmentRevokes, 1)
+	assert.Equal(t, uint64(10), plan.AssignmentRevokes[0].RoleID)
+	assert.Empty(t, plan.RoleDeletes)
+}
+
+func TestBuildPlan_UnmanagedAssignmentsAndRolesAreKept(t *testing.T) {
+	doc := mustExport(t, snapshot(), false)
+	doc.Roles = doc.Roles[:1] // 只声明 scale-admin
+
+	plan, err := BuildPlan(snapshot(), doc, PlanOptions{})
+	require.NoError(t, err)
+	assert.True(t, plan.Empty(), "%+v", plan.Changes)
+}
+
+func TestBuildPlan_PruneDeletesUndeclaredRolesWithRulesAndAssignments(t *testing.T) {
+	doc := mustExport(t, snapshot(), false)
+	doc.Roles = doc.Roles[:1]
+
+	plan, err := BuildPlan(snapshot(), doc, PlanOptions{Prune: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Change{
+		{Kind: ChangeKindRole, Op: ChangeOpDelete, Key: "viewer"},
+		{Kind: ChangeKindPolicy, Op: ChangeOpDelete, Key: "viewer scale:form:* read_all"},
+		{Kind: ChangeKindAssignment, Op: ChangeOpDelete, Key: "user:101 -> viewer"},
+	}, plan.Changes)
+	require.Len(t, plan.RoleDeletes, 1)
+	assert.Equal(t, meta.FromUint64(11), plan.RoleDeletes[0].ID)
+}
+
+func TestBuildPlan_RejectsInvalidReferences(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(m *Manifest)
+	}{
+		{"tenant mismatch", func(m *Manifest) { m.Tenant = "t2" }},
+		{"unknown resource", func(m *Manifest) {
+			m.Roles[0].Permissions = []PermissionSpec{{Resource: "ghost:*", Actions: []string{"read_all"}}}
+		}},
+		{"action not allowed", func(m *Manifest) {
+			m.Roles[0].Permissions = []PermissionSpec{{Resource: "scale:form:*", Actions: []string{"approve"}}}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := mustExport(t, snapshot(), false)
+			tt.mutate(doc)
+			_, err := BuildPlan(snapshot(), doc, PlanOptions{})
+			require.Error(t, err)
+			assert.True(t, errors.IsCode(err, code.ErrAuthzConfigInvalid))
+		})
+	}
+}
+
+func TestTimeBoundAssignmentsAreNotManaged(t *testing.T) {
+	expiresAt := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	current := snapshot()
+	temporary := assignment.NewAssignment(assignment.SubjectTypeUser, "102", 11, "t1", assignment.WithValidity(nil, &expiresAt))
+	current.Assignments = append(current.Assignments, &temporary)
+
+	_, err := current.Export(true)
+	assert.True(t, errors.IsCode(err, code.ErrAuthzConfigInvalid))
+	assert.Nil(t, mustExport(t, current, false).Assignments)
+
+	// 不触及带有效期的赋权时照常生成计划
+	doc := mustExport(t, current, false)
+	doc.Assignments = []AssignmentSpec{
+		{SubjectType: "user", SubjectID: "100", Roles: []string{"scale-admin"}},
+		{SubjectType: "user", SubjectID: "101", Roles: []string{"viewer"}},
+		{SubjectType: "user", SubjectID: "102", Roles: []string{"viewer"}},
+	}
+	plan, err := BuildPlan(current, doc, PlanOptions{})
+	require.NoError(t, err)
+	assert.True(t, plan.Empty(), "%+v", plan.Changes)
+
+	// 撤销带有效期的赋权须通过赋权接口
+	doc.Assignments = doc.Assignments[:2]
+	_, err = BuildPlan(current, doc, PlanOptions{})
+	assert.True(t, errors.IsCode(err, code.ErrAuthzConfigInvalid))
+}
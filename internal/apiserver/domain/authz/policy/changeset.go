@@ -0,0 +1,194 @@
+// Package policy 策略领域包
+package policy
+
+import (
+	"strings"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+// 规则类型，与 casbin_rule.ptype 一致
+const (
+	PTypePolicy   = "p"
+	PTypeGrouping = "g"
+)
+
+// ChangeOp 规则变更操作
+type ChangeOp string
+
+const (
+	ChangeOpAdd    ChangeOp = "add"
+	ChangeOpRemove ChangeOp = "remove"
+)
+
+// RuleChange 单条 Casbin 规则的实际变更（值对象）
+//
+// p 规则使用 Sub/Dom/Obj/Act，g 规则使用 Sub/Dom/Role。
+type RuleChange struct {
+	Op    ChangeOp `json:"op"`
+	PType string   `json:"ptype"`
+	Sub   string   `json:"sub"`
+	Dom   string   `json:"dom"`
+	Obj   string   `json:"obj,omitempty"`
+	Act   string   `json:"act,omitempty"`
+	Role  string   `json:"role,omitempty"`
+}
+
+// PolicyChange 构建 p 规则变更
+func PolicyChange(op ChangeOp, rule PolicyRule) RuleChange {
+	return RuleChange{Op: op, PType: PTypePolicy, Sub: rule.Sub, Dom: rule.Dom, Obj: rule.Obj, Act: rule.Act}
+}
+
+// GroupingChange 构建 g 规则变更
+func GroupingChange(op ChangeOp, rule GroupingRule) RuleChange {
+	return RuleChange{Op: op, PType: PTypeGrouping, Sub: rule.Sub, Dom: rule.Dom, Role: rule.Role}
+}
+
+// PolicyRule 返回 p 规则
+func (c RuleChange) PolicyRule() PolicyRule {
+	return NewPolicyRule(c.Sub, c.Dom, c.Obj, c.Act)
+}
+
+// GroupingRule 返回 g 规则
+func (c RuleChange) GroupingRule() GroupingRule {
+	return NewGroupingRule(c.Sub, c.Dom, c.Role)
+}
+
+// Inverse 返回抵消本次变更的变更
+func (c RuleChange) Inverse() RuleChange {
+	inverse := c
+	if c.Op == ChangeOpAdd {
+		inverse.Op = ChangeOpRemove
+	} else {
+		inverse.Op = ChangeOpAdd
+	}
+	return inverse
+}
+
+// RoleKey 返回变更涉及的角色键：p 规则为主体，g 规则为被授予的角色
+func (c RuleChange) RoleKey() string {
+	if c.PType == PTypeGrouping {
+		return c.Role
+	}
+	return c.Sub
+}
+
+// RuleKey 返回规则本身的标识（不含操作）
+func (c RuleChange) RuleKey() string {
+	if c.PType == PTypeGrouping {
+		return strings.Join([]string{c.PType, c.Sub, c.Dom, c.Role}, "|")
+	}
+	return strings.Join([]string{c.PType, c.Sub, c.Dom, c.Obj, c.Act}, "|")
+}
+
+// NetChanges 合并按时间顺序排列的变更序列，返回其净效果
+//
+// 记录的都是实际生效的变更，同一规则的 add/remove 必然交替出现：
+// 出现偶数次相互抵消，奇数次以最后一次为准。结果按规则首次出现的顺序排列。
+func NetChanges(changes []RuleChange) []RuleChange {
+	type state struct {
+		count int
+		last  RuleChange
+	}
+	order := make([]string, 0, len(changes))
+	states := make(map[string]*state, len(changes))
+	for _, c := range changes {
+		key := c.RuleKey()
+		s, ok := states[key]
+		if !ok {
+			s = &state{}
+			states[key] = s
+			order = append(order, key)
+		}
+		s.count++
+		s.last = c
+	}
+
+	net := make([]RuleChange, 0, len(order))
+	for _, key := range order {
+		if s := states[key]; s.count%2 == 1 {
+			net = append(net, s.last)
+		}
+	}
+	return net
+}
+
+// InverseChanges 返回撤销整个变更序列所需的变更（逆序并逐条取反）
+func InverseChanges(changes []RuleChange) []RuleChange {
+	inverse := make([]RuleChange, 0, len(changes))
+	for i := len(changes) - 1; i >= 0; i-- {
+		inverse = append(inverse, changes[i].Inverse())
+	}
+	return inverse
+}
+
+// DiffVersions 计算租户从 from 版本变为 to 版本的净变更
+//
+// versions 须为 (min(from,to), max(from,to)] 区间内按版本号升序排列的全部版本；
+// from 大于 to 时返回撤销这些版本所需的变更。区间内任一版本缺失或未记录变更集时无法计算。
+func DiffVersions(versions []*PolicyVersion, from, to int64) ([]RuleChange, error) {
+	low, high := from, to
+	if low > high {
+		low, high = high, low
+	}
+	if int64(len(versions)) != high-low {
+		return nil, errors.WithCode(code.ErrPolicyHistoryIncomplete, "版本 %d 至 %d 之间的历史不完整", low, high)
+	}
+
+	var changes []RuleChange
+	for i, v := range versions {
+		if v.Version != low+int64(i)+1 {
+			return nil, errors.WithCode(code.ErrPolicyHistoryIncomplete, "缺少版本 %d", low+int64(i)+1)
+		}
+		if !v.Recorded() {
+			return nil, errors.WithCode(code.ErrPolicyHistoryIncomplete, "版本 %d 未记录变更集", v.Version)
+		}
+		changes = append(changes, v.Changes...)
+	}
+
+	net := NetChanges(changes)
+	if from > to {
+		return InverseChanges(net), nil
+	}
+	return net, nil
+}
+
+// ChangeRecorder 收集单个授权事务内实际生效的规则变更
+//
+// 由规则存储在写入成功后登记，BumpVersion 按租户取出并随版本落库。
+type ChangeRecorder struct {
+	changes []RuleChange
+}
+
+// NewChangeRecorder 创建变更记录器
+func NewChangeRecorder() *ChangeRecorder {
+	return &ChangeRecorder{}
+}
+
+// Record 登记变更
+func (r *ChangeRecorder) Record(changes ...RuleChange) {
+	if r == nil {
+		return
+	}
+	r.changes = append(r.changes, changes...)
+}
+
+// Drain 取出并移除指定租户域下的变更；记录器为 nil 时返回 nil，表示未记录
+func (r *ChangeRecorder) Drain(domain string) []RuleChange {
+	if r == nil {
+		return nil
+	}
+	drained := make([]RuleChange, 0)
+	kept := r.changes[:0]
+	for _, c := range r.changes {
+		if c.Dom == domain {
+			drained = append(drained, c)
+		} else {
+			kept = append(kept, c)
+		}
+	}
+	r.changes = kept
+	return drained
+}
@@ -0,0 +1,103 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+var (
+	readRule  = NewPolicyRule("role:admin", "t1", "scale:form:*", "read")
+	writeRule = NewPolicyRule("role:admin", "t1", "scale:form:*", "write")
+	aliceRole = NewGroupingRule("user:1", "t1", "role:admin")
+)
+
+func recorded(version int64, changes ...RuleChange) *PolicyVersion {
+	v := NewPolicyVersion("t1", version, WithChanges(append([]RuleChange{}, changes...)))
+	return &v
+}
+
+func TestNetChanges_CancelsAlternatingChanges(t *testing.T) {
+	changes := []RuleChange{
+		PolicyChange(ChangeOpAdd, readRule),
+		GroupingChange(ChangeOpAdd, aliceRole),
+		PolicyChange(ChangeOpRemove, readRule),
+		PolicyChange(ChangeOpAdd, writeRule),
+		GroupingChange(ChangeOpRemove, aliceRole),
+		GroupingChange(ChangeOpAdd, aliceRole),
+	}
+
+	assert.Equal(t, []RuleChange{
+		GroupingChange(ChangeOpAdd, aliceRole),
+		PolicyChange(ChangeOpAdd, writeRule),
+	}, NetChanges(changes))
+}
+
+func TestInverseChanges_ReversesOrderAndOps(t *testing.T) {
+	inverse := InverseChanges([]RuleChange{
+		PolicyChange(ChangeOpAdd, readRule),
+		GroupingChange(ChangeOpRemove, aliceRole),
+	})
+
+	assert.Equal(t, []RuleChange{
+		GroupingChange(ChangeOpAdd, aliceRole),
+		PolicyChange(ChangeOpRemove, readRule),
+	}, inverse)
+}
+
+func TestDiffVersions(t *testing.T) {
+	versions := []*PolicyVersion{
+		recorded(2, PolicyChange(ChangeOpAdd, readRule), GroupingChange(ChangeOpAdd, aliceRole)),
+		recorded(3, GroupingChange(ChangeOpRemove, aliceRole)),
+	}
+
+	forward, err := DiffVersions(versions, 1, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []RuleChange{PolicyChange(ChangeOpAdd, readRule)}, forward)
+
+	backward, err := DiffVersions(versions[1:], 3, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []RuleChange{GroupingChange(ChangeOpAdd, aliceRole)}, backward)
+
+	same, err := DiffVersions(nil, 2, 2)
+	require.NoError(t, err)
+	assert.Empty(t, same)
+}
+
+func TestDiffVersions_RejectsIncompleteHistory(t *testing.T) {
+	legacy := NewPolicyVersion("t1", 3)
+
+	tests := []struct {
+		name     string
+		versions []*PolicyVersion
+	}{
+		{"missing version", []*PolicyVersion{recorded(2), recorded(4)}},
+		{"unrecorded version", []*PolicyVersion{recorded(2), &legacy}},
+		{"short range", []*PolicyVersion{recorded(2)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DiffVersions(tt.versions, 1, 3)
+			require.Error(t, err)
+			assert.True(t, errors.IsCode(err, code.ErrPolicyHistoryIncomplete))
+		})
+	}
+}
+
+func TestChangeRecorder_DrainByDomain(t *testing.T) {
+	recorder := NewChangeRecorder()
+	other := NewPolicyRule("role:admin", "t2", "scale:form:*", "read")
+	recorder.Record(PolicyChange(ChangeOpAdd, readRule), PolicyChange(ChangeOpAdd, other))
+
+	assert.Equal(t, []RuleChange{PolicyChange(ChangeOpAdd, readRule)}, recorder.Drain("t1"))
+	assert.NotNil(t, recorder.Drain("t1"), "已启用记录但无变更时返回空集而非 nil")
+	assert.Equal(t, []RuleChange{PolicyChange(ChangeOpAdd, other)}, recorder.Drain("t2"))
+
+	var disabled *ChangeRecorder
+	disabled.Record(PolicyChange(ChangeOpAdd, readRule))
+	assert.Nil(t, disabled.Drain("t1"))
+}
@@ -13,6 +13,12 @@ type RuleStore interface {
 	RemoveGroupingPolicy(ctx context.Context, rules ...GroupingRule) error
 }
 
+// RuleReader 从数据库读取 Casbin 规则事实，可参与授权写事务。
+type RuleReader interface {
+	// ListPolicies 列出租户域下的全部 p 规则
+	ListPolicies(ctx context.Context, domain string) ([]PolicyRule, error)
+}
+
 // CasbinAdapter Casbin 策略操作接口（Driven Port - 外部服务）
 type CasbinAdapter interface {
 	RuleStore
@@ -48,3 +54,11 @@ type VersionNotifier interface {
 
 // VersionChangeHandler 版本变更处理函数
 type VersionChangeHandler func(tenantID string, version int64)
+
+// VersionOutbox 策略版本事件发件箱（Driven Port）
+//
+// 在授权写事务内登记版本变更，事务提交后由中继异步投递，避免“已提交未通知”。
+type VersionOutbox interface {
+	// Enqueue 登记版本变更事件，须与版本号递增处于同一事务
+	Enqueue(ctx context.Context, version *PolicyVersion) error
+}
@@ -58,6 +58,44 @@ type GetCurrentVersionQuery struct {
 	TenantID string // 租户ID
 }
 
+// HistoryCommander 策略版本回滚接口（Driving Port）
+type HistoryCommander interface {
+	// Rollback 把租户授权规则恢复到指定版本，产生一个新版本
+	Rollback(ctx context.Context, cmd RollbackCommand) (*PolicyVersion, error)
+}
+
+// RollbackCommand 回滚命令
+type RollbackCommand struct {
+	TenantID      string // 租户ID
+	TargetVersion int64  // 回滚到的版本
+	ChangedBy     string // 变更人
+	Reason        string // 变更原因
+}
+
+// HistoryQueryer 策略版本历史查询接口（Driving Port）
+type HistoryQueryer interface {
+	// ListVersions 分页列出版本及其变更集
+	ListVersions(ctx context.Context, query ListVersionsQuery) ([]*PolicyVersion, int64, error)
+	// GetVersion 获取指定版本及其变更集
+	GetVersion(ctx context.Context, tenantID string, version int64) (*PolicyVersion, error)
+	// Diff 计算两个版本之间的净变更
+	Diff(ctx context.Context, query DiffVersionsQuery) ([]RuleChange, error)
+}
+
+// ListVersionsQuery 版本列表查询
+type ListVersionsQuery struct {
+	TenantID string
+	Offset   int
+	Limit    int
+}
+
+// DiffVersionsQuery 版本差异查询
+type DiffVersionsQuery struct {
+	TenantID string
+	From     int64
+	To       int64
+}
+
 // BuildPolicyRule 构建策略规则（辅助方法）
 func BuildPolicyRule(roleKey, tenantID, resourceKey, action string) PolicyRule {
 	return PolicyRule{
@@ -1,6 +1,8 @@
 package policy
 
 import (
+	"time"
+
 	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
 )
 
@@ -11,6 +13,9 @@ type PolicyVersion struct {
 	Version   int64  // 版本号
 	ChangedBy string // 变更人
 	Reason    string // 变更原因
+	// Changes 产生该版本的规则变更集；nil 表示该版本未记录变更（如历史数据或初始化版本）
+	Changes   []RuleChange
+	CreatedAt time.Time
 }
 
 // NewPolicyVersion 创建新版本
@@ -35,6 +40,14 @@ func WithChangedBy(by string) PolicyVersionOption {
 func WithReason(reason string) PolicyVersionOption {
 	return func(pv *PolicyVersion) { pv.Reason = reason }
 }
+func WithChanges(changes []RuleChange) PolicyVersionOption {
+	return func(pv *PolicyVersion) { pv.Changes = changes }
+}
+
+// Recorded 判断该版本是否记录了变更集
+func (pv *PolicyVersion) Recorded() bool {
+	return pv.Changes != nil
+}
 
 // RedisKey 返回 Redis 中的版本键
 func (pv *PolicyVersion) RedisKey() string {
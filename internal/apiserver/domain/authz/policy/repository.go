@@ -9,8 +9,18 @@ import (
 type Repository interface {
 	// GetOrCreate 获取或创建租户的策略版本
 	GetOrCreate(ctx context.Context, tenantID string) (*PolicyVersion, error)
-	// Increment 递增版本号并记录变更
-	Increment(ctx context.Context, tenantID, changedBy, reason string) (*PolicyVersion, error)
+	// Increment 递增版本号并记录变更，opts 可附带产生该版本的变更集
+	Increment(ctx context.Context, tenantID, changedBy, reason string, opts ...PolicyVersionOption) (*PolicyVersion, error)
 	// GetCurrent 获取当前版本
 	GetCurrent(ctx context.Context, tenantID string) (*PolicyVersion, error)
 }
+
+// VersionHistory 策略版本历史读取接口（Driven Port）
+type VersionHistory interface {
+	// ListVersions 按版本号倒序分页列出租户版本
+	ListVersions(ctx context.Context, tenantID string, offset, limit int) ([]*PolicyVersion, int64, error)
+	// FindVersion 获取租户的指定版本，不存在时返回 nil
+	FindVersion(ctx context.Context, tenantID string, version int64) (*PolicyVersion, error)
+	// ListRange 按版本号升序列出 (from, to] 区间内的版本
+	ListRange(ctx context.Context, tenantID string, from, to int64) ([]*PolicyVersion, error)
+}
@@ -0,0 +1,93 @@
+// Package serviceacl gRPC 服务访问控制领域包
+//
+// 每条 Entry 对应一个调用方服务（mTLS 证书 CN）允许/拒绝调用的 gRPC 方法，
+// 由 gRPC 服务端 ACL 拦截器在运行时执行；默认策略仍由启动配置决定。
+package serviceacl
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// Entry 服务 ACL 条目（聚合根），以 ServiceName 唯一标识
+type Entry struct {
+	ID          meta.ID
+	ServiceName string // 调用方服务名，匹配客户端证书 CN
+	Enabled     bool   // 禁用后该服务的全部调用被拒绝
+	Description string
+
+	// AllowedMethods 允许调用的方法模式
+	AllowedMethods []string
+	// DeniedMethods 拒绝调用的方法模式，优先于 AllowedMethods
+	DeniedMethods []string
+}
+
+// NewEntry 创建服务 ACL 条目
+func NewEntry(serviceName string, allowed, denied []string, opts ...EntryOption) *Entry {
+	e := &Entry{
+		ServiceName:    strings.TrimSpace(serviceName),
+		Enabled:        true,
+		AllowedMethods: normalizeMethods(allowed),
+		DeniedMethods:  normalizeMethods(denied),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// EntryOption 服务 ACL 条目选项
+type EntryOption func(*Entry)
+
+func WithID(id meta.ID) EntryOption           { return func(e *Entry) { e.ID = id } }
+func WithEnabled(enabled bool) EntryOption    { return func(e *Entry) { e.Enabled = enabled } }
+func WithDescription(desc string) EntryOption { return func(e *Entry) { e.Description = desc } }
+
+// ReplaceMethods 替换方法授权
+func (e *Entry) ReplaceMethods(allowed, denied []string) {
+	e.AllowedMethods = normalizeMethods(allowed)
+	e.DeniedMethods = normalizeMethods(denied)
+}
+
+var (
+	serviceNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+	// methodPattern 完整方法名 /pkg.Service/Method 或服务通配 /pkg.Service/*
+	methodPattern = regexp.MustCompile(`^/[A-Za-z0-9_.]+/([A-Za-z0-9_]+|\*)$`)
+)
+
+// Validate 校验条目完整性
+func (e *Entry) Validate() error {
+	if !serviceNamePattern.MatchString(e.ServiceName) {
+		return errors.WithCode(code.ErrServiceACLInvalid, "service name must be 1-128 letters, digits, '.', '_' or '-'")
+	}
+	for _, group := range [][]string{e.AllowedMethods, e.DeniedMethods} {
+		for _, m := range group {
+			if m != "*" && !methodPattern.MatchString(m) {
+				return errors.WithCode(code.ErrServiceACLInvalid, "invalid method pattern %q, expected /pkg.Service/Method, /pkg.Service/* or *", m)
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeMethods 去除空白与重复项，保持原有顺序
+func normalizeMethods(methods []string) []string {
+	out := make([]string, 0, len(methods))
+	seen := make(map[string]struct{}, len(methods))
+	for _, m := range methods {
+		m = strings.TrimSpace(m)
+		if m == "" {
+			continue
+		}
+		if _, ok := seen[m]; ok {
+			continue
+		}
+		seen[m] = struct{}{}
+		out = append(out, m)
+	}
+	return out
+}
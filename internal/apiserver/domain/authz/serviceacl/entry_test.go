@@ -0,0 +1,40 @@
+package serviceacl
+
+import (
+	"testing"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntryValidate(t *testing.T) {
+	valid := NewEntry(" qs-apiserver.svc ", []string{
+		"/iam.identity.v1.IdentityRead/GetUser",
+		" /iam.identity.v1.IdentityRead/GetUser ",
+		"/iam.authn.v1.AuthService/*",
+		"",
+	}, []string{"*"})
+	assert.NoError(t, valid.Validate())
+	assert.Equal(t, "qs-apiserver.svc", valid.ServiceName)
+	assert.True(t, valid.Enabled)
+	assert.Equal(t, []string{"/iam.identity.v1.IdentityRead/GetUser", "/iam.authn.v1.AuthService/*"}, valid.AllowedMethods)
+
+	cases := []struct {
+		name  string
+		entry *Entry
+	}{
+		{"empty service", NewEntry("", nil, nil)},
+		{"service with space", NewEntry("qs api", nil, nil)},
+		{"method without leading slash", NewEntry("qs", []string{"iam.identity.v1.IdentityRead/GetUser"}, nil)},
+		{"partial wildcard", NewEntry("qs", []string{"/iam.identity.v1.IdentityRead/Get*"}, nil)},
+		{"package wildcard", NewEntry("qs", nil, []string{"/iam.identity.v1.*"})},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.entry.Validate()
+			assert.Error(t, err)
+			assert.True(t, perrors.IsCode(err, code.ErrServiceACLInvalid))
+		})
+	}
+}
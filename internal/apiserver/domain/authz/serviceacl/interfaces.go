@@ -0,0 +1,54 @@
+package serviceacl
+
+import "context"
+
+// Commander 服务 ACL 命令服务接口（Driving Port - 写操作）
+// 写入成功后立即应用到本实例的 ACL 拦截器，其余副本通过定期同步生效
+type Commander interface {
+	// CreateEntry 登记调用方服务
+	CreateEntry(ctx context.Context, cmd CreateEntryCommand) (*Entry, error)
+	// UpdateEntry 更新调用方服务的方法授权
+	UpdateEntry(ctx context.Context, cmd UpdateEntryCommand) (*Entry, error)
+	// DeleteEntry 删除调用方服务（删除后按默认策略判定）
+	DeleteEntry(ctx context.Context, serviceName string) error
+}
+
+// CreateEntryCommand 登记服务命令
+type CreateEntryCommand struct {
+	ServiceName    string
+	Enabled        bool
+	Description    string
+	AllowedMethods []string
+	DeniedMethods  []string
+}
+
+// UpdateEntryCommand 更新服务命令（全量替换方法列表）
+type UpdateEntryCommand struct {
+	ServiceName    string
+	Enabled        *bool
+	Description    *string
+	AllowedMethods []string
+	DeniedMethods  []string
+}
+
+// Queryer 服务 ACL 查询服务接口（Driving Port - 读操作）
+type Queryer interface {
+	// GetEntry 查询单个服务
+	GetEntry(ctx context.Context, serviceName string) (*Entry, error)
+	// ListEntries 列出全部服务
+	ListEntries(ctx context.Context) ([]*Entry, error)
+	// DryRun 按已保存的条目判定 service 能否调用 method，不产生副作用
+	DryRun(ctx context.Context, serviceName, method string) (*DryRunResult, error)
+}
+
+// DryRunResult 试运行判定结果
+type DryRunResult struct {
+	ServiceName    string
+	Method         string
+	Allowed        bool
+	Reason         string // denied_method / allowed_method / method_not_allowed / service_disabled / default_policy
+	MatchedPattern string
+	DefaultPolicy  string
+	// Enforced 本实例是否启用了 ACL 拦截器；未启用时判定结果仅供参考
+	Enforced bool
+}
@@ -0,0 +1,21 @@
+package serviceacl
+
+import "context"
+
+// Repository 服务 ACL 仓储接口（Driven Port）
+type Repository interface {
+	// Create 创建条目
+	Create(ctx context.Context, entry *Entry) error
+	// Update 更新条目
+	Update(ctx context.Context, entry *Entry) error
+	// Delete 删除条目（物理删除，便于同名服务重新登记）
+	Delete(ctx context.Context, serviceName string) error
+	// FindByService 根据服务名查询，不存在时返回 ErrServiceACLNotFound
+	FindByService(ctx context.Context, serviceName string) (*Entry, error)
+	// List 列出全部条目（按服务名排序）
+	List(ctx context.Context) ([]*Entry, error)
+	// Count 统计条目数量
+	Count(ctx context.Context) (int64, error)
+	// Seed 写入初始条目并在同一事务中记录导入标记；已导入过时不写入任何条目并返回 false
+	Seed(ctx context.Context, entries []*Entry) (bool, error)
+}
@@ -0,0 +1,42 @@
+package oidcprovider
+
+import (
+	"context"
+	"errors"
+)
+
+type creator struct {
+	repo Repository
+}
+
+// 确保 creator 实现了 Creator 接口
+var _ Creator = (*creator)(nil)
+
+// NewCreator 创建上游 OIDC 身份提供商创建器
+func NewCreator(repo Repository) Creator {
+	return &creator{repo: repo}
+}
+
+// Create 创建上游 OIDC 身份提供商
+func (c *creator) Create(ctx context.Context, slug, name, issuer, clientID string) (*OIDCProvider, error) {
+	if name == "" {
+		return nil, errors.New("name cannot be empty")
+	}
+
+	provider := NewOIDCProvider(
+		slug, issuer, clientID,
+		WithOIDCProviderName(name),
+		WithOIDCProviderStatus(StatusEnabled), // 默认启用
+	)
+	if err := provider.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 确定 slug 唯一性
+	existing, err := c.repo.GetBySlug(ctx, slug)
+	if err == nil && existing != nil {
+		return nil, errors.New("oidc provider with the given slug already exists")
+	}
+
+	return provider, nil
+}
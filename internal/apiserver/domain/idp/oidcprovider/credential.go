@@ -0,0 +1,20 @@
+package oidcprovider
+
+import (
+	"time"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+)
+
+// ClientSecret 上游 IdP 分配给本系统的 client_secret
+type ClientSecret struct {
+	SecretCipher  []byte // client_secret 密文（AES-GCM/KMS 包装）
+	Fingerprint   string // 指纹（明文 SHA256）
+	Version       int
+	LastRotatedAt *time.Time
+}
+
+// IsMatch 检查明文密钥是否匹配指纹
+func (s *ClientSecret) IsMatch(plainSecret string) bool {
+	return wechatapp.Fingerprint(plainSecret) == s.Fingerprint
+}
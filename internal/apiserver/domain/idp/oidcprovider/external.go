@@ -0,0 +1,38 @@
+package oidcprovider
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+)
+
+// ================== External Service Interfaces (Driven Ports) ==================
+// 定义领域模型所依赖的外部服务接口，由基础设施层提供实现
+
+// SecretVault 秘钥保险库接口，与微信应用共用同一套密钥托管
+type SecretVault = wechatapp.SecretVault
+
+// Discovery 上游 OIDC discovery 元数据解析（/.well-known/openid-configuration）
+type Discovery interface {
+	// AuthorizationEndpoint 返回上游授权端点
+	AuthorizationEndpoint(ctx context.Context, issuer string) (string, error)
+}
+
+// ErrAuthorizationNotFound 授权请求不存在、已过期或已被消费
+var ErrAuthorizationNotFound = errors.New("oidc authorization request not found")
+
+// AuthorizationRequest 已发出的上游授权请求，登录时按 state 取回并校验
+type AuthorizationRequest struct {
+	Slug        string // 提供商标识
+	RedirectURI string // 发起授权时使用的回调地址
+	Nonce       string // 下发给上游的 nonce，id_token 必须回传
+}
+
+// AuthorizationStore 已发出授权请求的登记，登录时按 state 一次性消费
+type AuthorizationStore interface {
+	SaveAuthorization(ctx context.Context, state string, req AuthorizationRequest, ttl time.Duration) error
+	// ConsumeAuthorization 取出并删除授权登记，不存在时返回 ErrAuthorizationNotFound
+	ConsumeAuthorization(ctx context.Context, state string) (*AuthorizationRequest, error)
+}
@@ -0,0 +1,17 @@
+package oidcprovider
+
+import "context"
+
+// ================== Domain Service Interfaces (Driving Ports) ==================
+// 这些接口由领域层（领域服务）实现，供应用层调用
+
+// Creator 上游 OIDC 身份提供商创建器
+type Creator interface {
+	Create(ctx context.Context, slug, name, issuer, clientID string) (*OIDCProvider, error)
+}
+
+// CredentialRotater 凭据轮换器
+type CredentialRotater interface {
+	// RotateClientSecret 轮换 client_secret
+	RotateClientSecret(ctx context.Context, provider *OIDCProvider, newPlain string) error
+}
@@ -0,0 +1,210 @@
+package oidcprovider
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// OIDCProvider 上游 OIDC/OAuth2 身份提供商领域对象
+// 以 Slug 唯一标识，Slug 同时写入联合登录凭据的 app_id
+type OIDCProvider struct {
+	ID meta.ID
+
+	Slug     string
+	Name     string
+	Issuer   string // 上游 Issuer，用于 discovery 与 id_token iss 校验
+	ClientID string
+	Scopes   []string
+	Status   Status
+
+	// Claims 上游声明到本地账户字段的映射
+	Claims ClaimMapping
+	// RoleMappings 上游声明到本地角色的映射规则
+	RoleMappings []RoleMappingRule
+	// AutoProvision 首次登录且未绑定时是否即时开通账户
+	AutoProvision bool
+	// TenantID 映射角色所在租户；为零时使用默认租户
+	TenantID meta.ID
+
+	Cred *ClientSecret
+}
+
+// ClaimMapping 上游声明名映射，未配置的字段使用 OIDC 标准声明名
+type ClaimMapping struct {
+	Subject  string `json:"subject,omitempty"`
+	Username string `json:"username,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Phone    string `json:"phone,omitempty"`
+}
+
+// RoleMappingRule 角色映射规则：声明 Claim（支持 a.b 路径）等于或包含 Value 时授予 Role
+type RoleMappingRule struct {
+	Claim string `json:"claim"`
+	Value string `json:"value"`
+	Role  string `json:"role"`
+}
+
+// NewOIDCProvider 创建新的上游 OIDC 身份提供商领域对象
+func NewOIDCProvider(slug, issuer, clientID string, opts ...OIDCProviderOption) *OIDCProvider {
+	p := &OIDCProvider{
+		Slug:     slug,
+		Issuer:   strings.TrimRight(issuer, "/"),
+		ClientID: clientID,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// OIDCProviderOption 上游 OIDC 身份提供商选项
+type OIDCProviderOption func(*OIDCProvider)
+
+func WithOIDCProviderID(id meta.ID) OIDCProviderOption { return func(p *OIDCProvider) { p.ID = id } }
+func WithOIDCProviderName(name string) OIDCProviderOption {
+	return func(p *OIDCProvider) { p.Name = name }
+}
+func WithOIDCProviderStatus(status Status) OIDCProviderOption {
+	return func(p *OIDCProvider) { p.Status = status }
+}
+func WithOIDCProviderScopes(scopes ...string) OIDCProviderOption {
+	return func(p *OIDCProvider) { p.Scopes = scopes }
+}
+
+// 状态检查方法
+func (p *OIDCProvider) IsEnabled() bool  { return p.Status == StatusEnabled }
+func (p *OIDCProvider) IsDisabled() bool { return p.Status == StatusDisabled }
+func (p *OIDCProvider) IsArchived() bool { return p.Status == StatusArchived }
+
+// 状态变更方法
+func (p *OIDCProvider) Enable()  { p.Status = StatusEnabled }
+func (p *OIDCProvider) Disable() { p.Status = StatusDisabled }
+func (p *OIDCProvider) Archive() { p.Status = StatusArchived }
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,63}$`)
+
+// Validate 校验配置完整性
+func (p *OIDCProvider) Validate() error {
+	if !slugPattern.MatchString(p.Slug) {
+		return errors.New("slug must be 2-64 lowercase letters, digits or hyphens")
+	}
+	u, err := url.Parse(p.Issuer)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return errors.New("issuer must be an absolute http(s) URL")
+	}
+	if strings.TrimSpace(p.ClientID) == "" {
+		return errors.New("client_id cannot be empty")
+	}
+	for i, rule := range p.RoleMappings {
+		if rule.Claim == "" || rule.Value == "" || rule.Role == "" {
+			return fmt.Errorf("role mapping #%d requires claim, value and role", i+1)
+		}
+	}
+	return nil
+}
+
+// ScopesOrDefault 授权请求使用的 scope，始终包含 openid
+func (p *OIDCProvider) ScopesOrDefault() []string {
+	scopes := []string{ScopeOpenID}
+	for _, s := range p.Scopes {
+		s = strings.TrimSpace(s)
+		if s != "" && s != ScopeOpenID {
+			scopes = append(scopes, s)
+		}
+	}
+	if len(scopes) == 1 {
+		scopes = append(scopes, "profile", "email")
+	}
+	return scopes
+}
+
+// MapRoles 按映射规则计算上游声明对应的本地角色（去重，保持规则顺序）
+func (p *OIDCProvider) MapRoles(claims map[string]any) []string {
+	var roles []string
+	seen := make(map[string]struct{})
+	for _, rule := range p.RoleMappings {
+		if _, ok := seen[rule.Role]; ok {
+			continue
+		}
+		if claimContains(LookupClaim(claims, rule.Claim), rule.Value) {
+			seen[rule.Role] = struct{}{}
+			roles = append(roles, rule.Role)
+		}
+	}
+	return roles
+}
+
+// WithDefaults 返回补齐标准声明名后的映射
+func (m ClaimMapping) WithDefaults() ClaimMapping {
+	if m.Subject == "" {
+		m.Subject = DefaultSubjectClaim
+	}
+	if m.Username == "" {
+		m.Username = DefaultUsernameClaim
+	}
+	if m.Email == "" {
+		m.Email = DefaultEmailClaim
+	}
+	if m.Name == "" {
+		m.Name = DefaultNameClaim
+	}
+	if m.Phone == "" {
+		m.Phone = DefaultPhoneClaim
+	}
+	return m
+}
+
+// LookupClaim 按 a.b.c 路径读取嵌套声明，不存在时返回 nil
+func LookupClaim(claims map[string]any, path string) any {
+	if path == "" {
+		return nil
+	}
+	// 完整声明名优先（如 https://example.com/roles 这类带点号的命名空间声明）
+	if v, ok := claims[path]; ok {
+		return v
+	}
+	var cur any = claims
+	for _, part := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if cur, ok = m[part]; !ok {
+			return nil
+		}
+	}
+	return cur
+}
+
+// claimContains 声明值等于 want，或为数组时包含 want
+func claimContains(v any, want string) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case string:
+		return val == want
+	case []string:
+		for _, item := range val {
+			if item == want {
+				return true
+			}
+		}
+	case []any:
+		for _, item := range val {
+			if claimContains(item, want) {
+				return true
+			}
+		}
+	default:
+		return fmt.Sprint(val) == want
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package oidcprovider_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/oidcprovider"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type providerRepoStub struct {
+	existing *oidcprovider.OIDCProvider
+}
+
+func (s *providerRepoStub) Create(ctx context.Context, p *oidcprovider.OIDCProvider) error {
+	return nil
+}
+func (s *providerRepoStub) GetByID(ctx context.Context, id idutil.ID) (*oidcprovider.OIDCProvider, error) {
+	return s.existing, nil
+}
+func (s *providerRepoStub) GetBySlug(ctx context.Context, slug string) (*oidcprovider.OIDCProvider, error) {
+	return s.existing, nil
+}
+func (s *providerRepoStub) List(ctx context.Context, filter oidcprovider.ListFilter) ([]*oidcprovider.OIDCProvider, error) {
+	return nil, nil
+}
+func (s *providerRepoStub) Update(ctx context.Context, p *oidcprovider.OIDCProvider) error {
+	return nil
+}
+
+func TestCreator_Create(t *testing.T) {
+	c := oidcprovider.NewCreator(&providerRepoStub{})
+	p, err := c.Create(context.Background(), "corp-sso", "Corp SSO", "https://sso.example.com/", "iam")
+	require.NoError(t, err)
+	assert.Equal(t, "https://sso.example.com", p.Issuer)
+	assert.True(t, p.IsEnabled())
+
+	_, err = c.Create(context.Background(), "Corp SSO", "Corp SSO", "https://sso.example.com", "iam")
+	require.Error(t, err)
+	_, err = c.Create(context.Background(), "corp-sso", "Corp SSO", "sso.example.com", "iam")
+	require.Error(t, err)
+
+	dup := oidcprovider.NewCreator(&providerRepoStub{existing: oidcprovider.NewOIDCProvider("corp-sso", "https://sso.example.com", "iam")})
+	_, err = dup.Create(context.Background(), "corp-sso", "Corp SSO", "https://sso.example.com", "iam")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+func TestRotateClientSecret(t *testing.T) {
+	now := time.Now()
+	r := oidcprovider.NewCredentialRotater(&testhelpers.VaultStub{}, func() time.Time { return now })
+
+	p := oidcprovider.NewOIDCProvider("corp-sso", "https://sso.example.com", "iam", oidcprovider.WithOIDCProviderStatus(oidcprovider.StatusEnabled))
+	require.Error(t, r.RotateClientSecret(context.Background(), p, " "))
+
+	require.NoError(t, r.RotateClientSecret(context.Background(), p, "client-secret"))
+	assert.Equal(t, []byte("cipher:client-secret"), p.Cred.SecretCipher)
+	assert.Equal(t, 1, p.Cred.Version)
+
+	// 幂等：相同 Secret 不递增版本
+	require.NoError(t, r.RotateClientSecret(context.Background(), p, "client-secret"))
+	assert.Equal(t, 1, p.Cred.Version)
+
+	p.Archive()
+	require.Error(t, r.RotateClientSecret(context.Background(), p, "client-secret-2"))
+}
+
+func TestMapRoles(t *testing.T) {
+	p := oidcprovider.NewOIDCProvider("corp-sso", "https://sso.example.com", "iam")
+	p.RoleMappings = []oidcprovider.RoleMappingRule{
+		{Claim: "groups", Value: "iam-admins", Role: "admin"},
+		{Claim: "realm_access.roles", Value: "auditor", Role: "auditor"},
+		{Claim: "https://example.com/dept", Value: "ops", Role: "operator"},
+		{Claim: "email_verified", Value: "true", Role: "verified"},
+		{Claim: "groups", Value: "iam-owners", Role: "admin"},
+	}
+
+	claims := map[string]any{
+		"groups":                   []any{"staff", "iam-admins", "iam-owners"},
+		"realm_access":             map[string]any{"roles": []any{"auditor"}},
+		"https://example.com/dept": "ops",
+		"email_verified":           true,
+	}
+	assert.Equal(t, []string{"admin", "auditor", "operator", "verified"}, p.MapRoles(claims))
+	assert.Empty(t, p.MapRoles(map[string]any{"groups": "staff"}))
+}
+
+func TestScopesOrDefault(t *testing.T) {
+	p := oidcprovider.NewOIDCProvider("corp-sso", "https://sso.example.com", "iam")
+	assert.Equal(t, []string{"openid", "profile", "email"}, p.ScopesOrDefault())
+
+	p.Scopes = []string{"email", "openid", "groups"}
+	assert.Equal(t, []string{"openid", "email", "groups"}, p.ScopesOrDefault())
+}
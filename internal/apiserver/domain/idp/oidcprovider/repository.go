@@ -0,0 +1,29 @@
+package oidcprovider
+
+import (
+	"context"
+
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+)
+
+// ================== Repository Interface (Driven Port) ==================
+// 定义领域模型所依赖的仓储接口，由基础设施层提供实现
+
+// Repository 上游 OIDC 身份提供商存储库接口
+type Repository interface {
+	// 创建接口
+	Create(ctx context.Context, provider *OIDCProvider) error
+
+	// 查询接口
+	GetByID(ctx context.Context, id idutil.ID) (*OIDCProvider, error)
+	GetBySlug(ctx context.Context, slug string) (*OIDCProvider, error)
+	List(ctx context.Context, filter ListFilter) ([]*OIDCProvider, error)
+
+	// 更新接口
+	Update(ctx context.Context, provider *OIDCProvider) error
+}
+
+// ListFilter 上游 OIDC 身份提供商列表过滤条件。
+type ListFilter struct {
+	Status *Status
+}
@@ -0,0 +1,72 @@
+package oidcprovider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+)
+
+// credentialRotater 凭据轮换器
+type credentialRotater struct {
+	vault SecretVault
+	now   func() time.Time
+}
+
+// 确保 credentialRotater 实现了相应的接口
+var _ CredentialRotater = (*credentialRotater)(nil)
+
+// NewCredentialRotater 创建凭据轮换器实例
+func NewCredentialRotater(vault SecretVault, now func() time.Time) CredentialRotater {
+	if now == nil {
+		now = time.Now
+	}
+
+	return &credentialRotater{
+		vault: vault,
+		now:   now,
+	}
+}
+
+// RotateClientSecret 轮换 client_secret
+func (m *credentialRotater) RotateClientSecret(ctx context.Context, provider *OIDCProvider, newPlain string) error {
+	if provider == nil {
+		return errors.New("provider cannot be nil")
+	}
+	// 各家 IdP 的 client_secret 格式不一，仅做非空校验
+	if strings.TrimSpace(newPlain) == "" {
+		return errors.New("invalid client secret")
+	}
+
+	if provider.IsArchived() {
+		return errors.New("cannot change credentials for archived provider")
+	}
+
+	// 幂等：指纹相同则不变更，直接返回
+	if provider.Cred != nil && provider.Cred.IsMatch(newPlain) {
+		return nil
+	}
+
+	if m.vault == nil {
+		return errors.New("missing secret vault for credential rotater")
+	}
+
+	cipher, err := m.vault.Encrypt(ctx, []byte(newPlain))
+	if err != nil {
+		return err
+	}
+
+	if provider.Cred == nil {
+		provider.Cred = &ClientSecret{}
+	}
+
+	provider.Cred.SecretCipher = cipher
+	provider.Cred.Fingerprint = wechatapp.Fingerprint(newPlain)
+	provider.Cred.Version++
+	now := m.now()
+	provider.Cred.LastRotatedAt = &now
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package oidcprovider
+
+// Status 上游 OIDC 身份提供商状态
+type Status string
+
+const (
+	StatusEnabled  Status = "Enabled"  // 已启用
+	StatusDisabled Status = "Disabled" // 已禁用
+	StatusArchived Status = "Archived" // 已归档
+)
+
+// 默认声明名（OIDC Core 标准声明）
+const (
+	DefaultSubjectClaim  = "sub"
+	DefaultUsernameClaim = "preferred_username"
+	DefaultEmailClaim    = "email"
+	DefaultNameClaim     = "name"
+	DefaultPhoneClaim    = "phone_number"
+)
+
+// ScopeOpenID 授权码模式必须携带的 scope
+const ScopeOpenID = "openid"
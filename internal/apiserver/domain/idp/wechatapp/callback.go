@@ -0,0 +1,62 @@
+package wechatapp
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// 微信回调消息类型与事件
+const (
+	MsgTypeEvent = "event"
+
+	EventSubscribe               = "subscribe"                 // 关注
+	EventUnsubscribe             = "unsubscribe"               // 取消关注
+	EventUserAuthorizationRevoke = "user_authorization_revoke" // 用户撤回授权
+)
+
+// CallbackEnvelope 安全模式下的加密信封
+type CallbackEnvelope struct {
+	XMLName    xml.Name `xml:"xml"`
+	ToUserName string   `xml:"ToUserName"`
+	Encrypt    string   `xml:"Encrypt"`
+}
+
+// CallbackMessage 微信服务器推送的消息（解密后的明文）
+type CallbackMessage struct {
+	XMLName      xml.Name `xml:"xml"`
+	ToUserName   string   `xml:"ToUserName"`
+	FromUserName string   `xml:"FromUserName"`
+	CreateTime   int64    `xml:"CreateTime"`
+	MsgType      string   `xml:"MsgType"`
+	Event        string   `xml:"Event"`
+	EventKey     string   `xml:"EventKey"`
+	OpenID       string   `xml:"OpenID"`     // 授权用户资料变更类事件携带
+	AppID        string   `xml:"AppID"`      // 授权用户资料变更类事件携带
+	RevokeInfo   string   `xml:"RevokeInfo"` // 撤回的授权信息
+
+	// Raw 明文 XML 原文，转发给下游时保留完整字段
+	Raw []byte `xml:"-"`
+}
+
+// ParseCallbackMessage 解析明文 XML 消息
+func ParseCallbackMessage(raw []byte) (*CallbackMessage, error) {
+	var msg CallbackMessage
+	if err := xml.Unmarshal(raw, &msg); err != nil {
+		return nil, err
+	}
+	msg.Raw = raw
+	return &msg, nil
+}
+
+// IsEvent 是否为指定事件推送（事件名大小写不敏感）
+func (m *CallbackMessage) IsEvent(event string) bool {
+	return m.MsgType == MsgTypeEvent && strings.EqualFold(m.Event, event)
+}
+
+// SubjectOpenID 事件关联用户的 OpenID：撤回授权事件使用 OpenID 字段，其余使用 FromUserName
+func (m *CallbackMessage) SubjectOpenID() string {
+	if m.OpenID != "" {
+		return m.OpenID
+	}
+	return m.FromUserName
+}
@@ -34,3 +34,31 @@ type AppTokenProvider interface {
 	// Fetch 获取访问令牌
 	Fetch(ctx context.Context, app *WechatApp) (*AppAccessToken, error)
 }
+
+// CallbackCodec 微信服务器推送消息的验签与解密（安全模式）
+type CallbackCodec interface {
+	// Sign 按微信规则对 token、timestamp、nonce（及可选的密文）做字典序 SHA1 签名
+	Sign(token, timestamp, nonce string, extra ...string) string
+	// Decrypt 使用 EncodingAESKey 解密密文，并校验密文中携带的 AppID
+	Decrypt(appID, encodingAESKey, ciphertext string) ([]byte, error)
+}
+
+// AuthorizationRevoker 用户撤回授权后的凭据处理（由 authn 侧提供实现）
+type AuthorizationRevoker interface {
+	// RevokeWechatAuthorization 禁用与 appID + openID 关联的 OAuth 凭据，返回被禁用的凭据数量
+	RevokeWechatAuthorization(ctx context.Context, appID, openID string) (int, error)
+}
+
+// CallbackEventPublisher 回调事件发布器（转发到消息总线）
+type CallbackEventPublisher interface {
+	// PublishCallbackEvent 发布回调事件
+	PublishCallbackEvent(ctx context.Context, appID string, msg *CallbackMessage) error
+}
+
+// CallbackNonceCache 回调 nonce 防重放缓存
+type CallbackNonceCache interface {
+	// MarkNonce 记录应用内的回调 nonce，保留 ttl；首次记录返回 true
+	MarkNonce(ctx context.Context, appID, nonce string, ttl time.Duration) (bool, error)
+	// ReleaseNonce 撤回 nonce 记录，处理失败后允许微信重试同一条消息
+	ReleaseNonce(ctx context.Context, appID, nonce string) error
+}
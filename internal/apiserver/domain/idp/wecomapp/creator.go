@@ -0,0 +1,45 @@
+package wecomapp
+
+import (
+	"context"
+	"errors"
+)
+
+type creator struct {
+	repo Repository
+}
+
+// 确保 creator 实现了 Creator 接口
+var _ Creator = (*creator)(nil)
+
+// NewCreator 创建企业微信应用创建器
+func NewCreator(repo Repository) Creator {
+	return &creator{repo: repo}
+}
+
+// Create 创建企业微信应用
+func (c *creator) Create(ctx context.Context, corpID, agentID, name string) (*WecomApp, error) {
+	// 参数校验
+	if corpID == "" {
+		return nil, errors.New("corpID cannot be empty")
+	}
+	if agentID == "" {
+		return nil, errors.New("agentID cannot be empty")
+	}
+	if name == "" {
+		return nil, errors.New("name cannot be empty")
+	}
+
+	// 确定 corpID + agentID 唯一性
+	existing, err := c.repo.GetByCorpAgent(ctx, corpID, agentID)
+	if err == nil && existing != nil {
+		return nil, errors.New("wecom app with the given corpID and agentID already exists")
+	}
+
+	// 创建企业微信应用实体
+	return NewWecomApp(
+		corpID, agentID,
+		WithWecomAppName(name),
+		WithWecomAppStatus(StatusEnabled), // 默认启用
+	), nil
+}
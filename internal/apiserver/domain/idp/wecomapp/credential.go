@@ -0,0 +1,25 @@
+package wecomapp
+
+import (
+	"time"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+)
+
+// Credentials 企业微信应用凭据集合
+type Credentials struct {
+	Auth *CorpSecret // 换 token / 网页授权：CorpSecret
+}
+
+// CorpSecret 应用 Secret
+type CorpSecret struct {
+	SecretCipher  []byte // CorpSecret 密文（AES-GCM/KMS 包装）
+	Fingerprint   string // 指纹（明文 SHA256）
+	Version       int
+	LastRotatedAt *time.Time
+}
+
+// IsMatch 检查明文密钥是否匹配指纹
+func (s *CorpSecret) IsMatch(plainSecret string) bool {
+	return wechatapp.Fingerprint(plainSecret) == s.Fingerprint
+}
@@ -0,0 +1,26 @@
+package wecomapp
+
+import (
+	"context"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+)
+
+// ================== External Service Interfaces (Driven Ports) ==================
+// 定义领域模型所依赖的外部服务接口，由基础设施层提供实现
+// 密钥托管与令牌缓存与微信应用共用同一套基础设施
+
+// SecretVault 秘钥保险库接口
+type SecretVault = wechatapp.SecretVault
+
+// AppAccessToken 应用访问令牌
+type AppAccessToken = wechatapp.AppAccessToken
+
+// AccessTokenCache 访问令牌缓存接口，键为 WecomApp.TokenCacheKey()
+type AccessTokenCache = wechatapp.AccessTokenCache
+
+// AppTokenProvider 企业微信应用访问令牌提供器接口
+type AppTokenProvider interface {
+	// Fetch 获取访问令牌
+	Fetch(ctx context.Context, app *WecomApp) (*AppAccessToken, error)
+}
@@ -0,0 +1,26 @@
+package wecomapp
+
+import (
+	"context"
+	"time"
+)
+
+// ================== Domain Service Interfaces (Driving Ports) ==================
+// 这些接口由领域层（领域服务）实现，供应用层调用
+
+// Creator 企业微信应用创建器
+type Creator interface {
+	Create(ctx context.Context, corpID, agentID, name string) (*WecomApp, error)
+}
+
+// CredentialRotater 凭据轮换器
+type CredentialRotater interface {
+	// RotateCorpSecret 轮换应用 Secret
+	RotateCorpSecret(ctx context.Context, app *WecomApp, newPlain string) error
+}
+
+// AccessTokenCacher 访问令牌缓存器（单飞刷新 + 过期缓冲）
+type AccessTokenCacher interface {
+	// 确保获取有效的访问令牌
+	EnsureToken(ctx context.Context, app *WecomApp, skew time.Duration) (string, error)
+}
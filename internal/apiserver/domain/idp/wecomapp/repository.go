@@ -0,0 +1,30 @@
+package wecomapp
+
+import (
+	"context"
+
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+)
+
+// ================== Repository Interface (Driven Port) ==================
+// 定义领域模型所依赖的仓储接口，由基础设施层提供实现
+
+// Repository 企业微信应用存储库接口
+type Repository interface {
+	// 创建接口
+	Create(ctx context.Context, app *WecomApp) error
+
+	// 查询接口
+	GetByID(ctx context.Context, id idutil.ID) (*WecomApp, error)
+	GetByCorpAgent(ctx context.Context, corpID, agentID string) (*WecomApp, error)
+	List(ctx context.Context, filter ListFilter) ([]*WecomApp, error)
+
+	// 更新接口
+	Update(ctx context.Context, app *WecomApp) error
+}
+
+// ListFilter 企业微信应用列表过滤条件。
+type ListFilter struct {
+	CorpID *string
+	Status *Status
+}
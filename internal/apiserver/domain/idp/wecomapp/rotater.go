@@ -0,0 +1,79 @@
+package wecomapp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+)
+
+// credentialRotater 凭据轮换器
+type credentialRotater struct {
+	vault SecretVault
+	now   func() time.Time
+}
+
+// 确保 credentialRotater 实现了相应的接口
+var _ CredentialRotater = (*credentialRotater)(nil)
+
+// NewCredentialRotater 创建凭据轮换器实例
+func NewCredentialRotater(vault SecretVault, now func() time.Time) CredentialRotater {
+	if now == nil {
+		now = time.Now
+	}
+
+	return &credentialRotater{
+		vault: vault,
+		now:   now,
+	}
+}
+
+// RotateCorpSecret 轮换应用 Secret
+func (m *credentialRotater) RotateCorpSecret(ctx context.Context, app *WecomApp, newPlain string) error {
+	// 验证参数
+	if app == nil {
+		return errors.New("app cannot be nil")
+	}
+	// CorpSecret 为 43 位字母数字；此处做“非空 + 长度>=16”宽松校验
+	if strings.TrimSpace(newPlain) == "" || len(newPlain) < 16 {
+		return errors.New("invalid corp secret")
+	}
+
+	// 验证 app 状态
+	if app.IsArchived() {
+		return errors.New("cannot change credentials for archived app")
+	}
+
+	if app.Cred == nil {
+		app.Cred = &Credentials{}
+	}
+
+	// 幂等：指纹相同则不变更，直接返回
+	if app.Cred.Auth != nil && app.Cred.Auth.IsMatch(newPlain) {
+		return nil
+	}
+
+	if m.vault == nil {
+		return errors.New("missing secret vault for credential rotater")
+	}
+
+	// 加密存储新的 CorpSecret
+	cipher, err := m.vault.Encrypt(ctx, []byte(newPlain))
+	if err != nil {
+		return err
+	}
+
+	if app.Cred.Auth == nil {
+		app.Cred.Auth = &CorpSecret{}
+	}
+
+	app.Cred.Auth.SecretCipher = cipher
+	app.Cred.Auth.Fingerprint = wechatapp.Fingerprint(newPlain)
+	app.Cred.Auth.Version++
+	now := m.now()
+	app.Cred.Auth.LastRotatedAt = &now
+
+	return nil
+}
@@ -0,0 +1,10 @@
+package wecomapp
+
+// Status 企业微信应用状态
+type Status string
+
+const (
+	StatusEnabled  Status = "Enabled"  // 已启用
+	StatusDisabled Status = "Disabled" // 已禁用
+	StatusArchived Status = "Archived" // 已归档
+)
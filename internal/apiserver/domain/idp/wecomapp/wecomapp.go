@@ -0,0 +1,56 @@
+package wecomapp
+
+import (
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// WecomApp 企业微信自建应用领域对象
+// 以 CorpID + AgentID 唯一标识，同一企业可登记多个应用
+type WecomApp struct {
+	ID meta.ID
+
+	CorpID  string
+	AgentID string
+	Name    string
+	Status  Status
+
+	Cred *Credentials
+}
+
+// NewWecomApp 创建新的企业微信应用领域对象
+func NewWecomApp(corpID, agentID string, opts ...WecomAppOption) *WecomApp {
+	app := &WecomApp{
+		CorpID:  corpID,
+		AgentID: agentID,
+	}
+
+	for _, opt := range opts {
+		opt(app)
+	}
+
+	return app
+}
+
+// WecomAppOption 企业微信应用选项
+type WecomAppOption func(*WecomApp)
+
+func WithWecomAppID(id meta.ID) WecomAppOption    { return func(w *WecomApp) { w.ID = id } }
+func WithWecomAppName(name string) WecomAppOption { return func(w *WecomApp) { w.Name = name } }
+func WithWecomAppStatus(status Status) WecomAppOption {
+	return func(w *WecomApp) { w.Status = status }
+}
+
+// TokenCacheKey 访问令牌缓存键（企业微信 access_token 按 CorpID + AgentID 隔离）
+func (w *WecomApp) TokenCacheKey() string {
+	return "wecom:" + w.CorpID + ":" + w.AgentID
+}
+
+// 状态检查方法
+func (w *WecomApp) IsEnabled() bool  { return w.Status == StatusEnabled }
+func (w *WecomApp) IsDisabled() bool { return w.Status == StatusDisabled }
+func (w *WecomApp) IsArchived() bool { return w.Status == StatusArchived }
+
+// 状态变更方法
+func (w *WecomApp) Enable()  { w.Status = StatusEnabled }
+func (w *WecomApp) Disable() { w.Status = StatusDisabled }
+func (w *WecomApp) Archive() { w.Status = StatusArchived }
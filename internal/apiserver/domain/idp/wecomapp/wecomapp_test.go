@@ -0,0 +1,114 @@
+package wecomapp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+	wecomapp "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type wecomRepoStub struct {
+	existing *wecomapp.WecomApp
+}
+
+func (s *wecomRepoStub) Create(ctx context.Context, app *wecomapp.WecomApp) error { return nil }
+func (s *wecomRepoStub) GetByID(ctx context.Context, id idutil.ID) (*wecomapp.WecomApp, error) {
+	return s.existing, nil
+}
+func (s *wecomRepoStub) GetByCorpAgent(ctx context.Context, corpID, agentID string) (*wecomapp.WecomApp, error) {
+	return s.existing, nil
+}
+func (s *wecomRepoStub) List(ctx context.Context, filter wecomapp.ListFilter) ([]*wecomapp.WecomApp, error) {
+	return nil, nil
+}
+func (s *wecomRepoStub) Update(ctx context.Context, app *wecomapp.WecomApp) error { return nil }
+
+type tokenCacheStub struct {
+	tokens map[string]*wecomapp.AppAccessToken
+}
+
+func (s *tokenCacheStub) Get(ctx context.Context, key string) (*wecomapp.AppAccessToken, error) {
+	return s.tokens[key], nil
+}
+func (s *tokenCacheStub) Set(ctx context.Context, key string, aat *wecomapp.AppAccessToken, ttl time.Duration) error {
+	s.tokens[key] = aat
+	return nil
+}
+func (s *tokenCacheStub) TryLockRefresh(ctx context.Context, key string, ttl time.Duration) (bool, func(), error) {
+	return true, func() {}, nil
+}
+
+type tokenProviderStub struct {
+	calls int
+}
+
+func (p *tokenProviderStub) Fetch(ctx context.Context, app *wecomapp.WecomApp) (*wecomapp.AppAccessToken, error) {
+	p.calls++
+	return &wecomapp.AppAccessToken{Token: "tok-" + app.AgentID, ExpiresAt: time.Now().Add(2 * time.Hour)}, nil
+}
+
+func TestCreator_Create(t *testing.T) {
+	c := wecomapp.NewCreator(&wecomRepoStub{})
+	app, err := c.Create(context.Background(), "ww-corp", "1000002", "OA")
+	require.NoError(t, err)
+	assert.Equal(t, "ww-corp", app.CorpID)
+	assert.Equal(t, "1000002", app.AgentID)
+	assert.True(t, app.IsEnabled())
+
+	_, err = c.Create(context.Background(), "ww-corp", "", "OA")
+	require.Error(t, err)
+
+	dup := wecomapp.NewCreator(&wecomRepoStub{existing: wecomapp.NewWecomApp("ww-corp", "1000002")})
+	_, err = dup.Create(context.Background(), "ww-corp", "1000002", "OA")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+func TestRotateCorpSecret(t *testing.T) {
+	now := time.Now()
+	r := wecomapp.NewCredentialRotater(&testhelpers.VaultStub{}, func() time.Time { return now })
+
+	app := wecomapp.NewWecomApp("ww-corp", "1000002", wecomapp.WithWecomAppStatus(wecomapp.StatusEnabled))
+	require.Error(t, r.RotateCorpSecret(context.Background(), app, "short"))
+
+	require.NoError(t, r.RotateCorpSecret(context.Background(), app, "corp-secret-value-0001"))
+	require.NotNil(t, app.Cred.Auth)
+	assert.Equal(t, []byte("cipher:corp-secret-value-0001"), app.Cred.Auth.SecretCipher)
+	assert.Equal(t, 1, app.Cred.Auth.Version)
+	assert.True(t, app.Cred.Auth.IsMatch("corp-secret-value-0001"))
+
+	// 幂等：相同 Secret 不递增版本
+	require.NoError(t, r.RotateCorpSecret(context.Background(), app, "corp-secret-value-0001"))
+	assert.Equal(t, 1, app.Cred.Auth.Version)
+
+	app.Archive()
+	require.Error(t, r.RotateCorpSecret(context.Background(), app, "corp-secret-value-0002"))
+}
+
+func TestAccessTokenCacher_KeyedByCorpAgent(t *testing.T) {
+	cache := &tokenCacheStub{tokens: map[string]*wecomapp.AppAccessToken{}}
+	provider := &tokenProviderStub{}
+	cacher := wecomapp.NewAccessTokenCacher(cache, provider)
+
+	a1 := wecomapp.NewWecomApp("ww-corp", "1000002")
+	a2 := wecomapp.NewWecomApp("ww-corp", "1000003")
+
+	tok, err := cacher.EnsureToken(context.Background(), a1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "tok-1000002", tok)
+	tok, err = cacher.EnsureToken(context.Background(), a1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "tok-1000002", tok)
+	assert.Equal(t, 1, provider.calls)
+
+	tok, err = cacher.EnsureToken(context.Background(), a2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "tok-1000003", tok)
+	assert.Contains(t, cache.tokens, "wecom:ww-corp:1000002")
+	assert.Contains(t, cache.tokens, "wecom:ww-corp:1000003")
+}
@@ -0,0 +1,130 @@
+// Package outbox 事务性发件箱领域包
+//
+// 领域事件与业务数据在同一事务中写入 outbox 表，由中继（relay）异步投递到消息总线，
+// 保证进程在提交后、发布前崩溃时事件不会丢失。
+package outbox
+
+import (
+	"time"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// Status 发件箱事件状态
+type Status string
+
+const (
+	// StatusPending 待投递（包含等待重试）
+	StatusPending Status = "pending"
+	// StatusPublished 已投递
+	StatusPublished Status = "published"
+	// StatusDead 超过最大重试次数，需人工介入
+	StatusDead Status = "dead"
+)
+
+func (s Status) String() string { return string(s) }
+
+// Event 发件箱事件（聚合根）
+type Event struct {
+	ID             meta.ID
+	Topic          string            // 目标主题
+	EventType      string            // 事件类型，如 authz.version.changed
+	AggregateType  string            // 聚合类型，如 authz_policy_version
+	AggregateID    string            // 聚合标识，如租户ID
+	IdempotencyKey string            // 幂等键，同时作为消息 UUID 供消费端去重
+	Payload        []byte            // 消息体
+	Metadata       map[string]string // 消息元数据
+	Status         Status
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	CreatedAt      time.Time
+	PublishedAt    *time.Time
+}
+
+// NewEvent 创建待投递事件
+func NewEvent(topic, eventType, idempotencyKey string, payload []byte, opts ...EventOption) *Event {
+	now := time.Now()
+	e := &Event{
+		Topic:          topic,
+		EventType:      eventType,
+		IdempotencyKey: idempotencyKey,
+		Payload:        payload,
+		Metadata:       map[string]string{},
+		Status:         StatusPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// EventOption 事件选项
+type EventOption func(*Event)
+
+// WithAggregate 设置事件所属聚合
+func WithAggregate(aggregateType, aggregateID string) EventOption {
+	return func(e *Event) {
+		e.AggregateType = aggregateType
+		e.AggregateID = aggregateID
+	}
+}
+
+// WithMetadata 追加消息元数据
+func WithMetadata(key, value string) EventOption {
+	return func(e *Event) { e.Metadata[key] = value }
+}
+
+// MarkPublished 标记为已投递
+func (e *Event) MarkPublished(at time.Time) {
+	e.Status = StatusPublished
+	e.Attempts++
+	e.LastError = ""
+	e.PublishedAt = &at
+}
+
+// MarkFailed 记录一次投递失败，按重试策略安排下次投递或转为死信
+func (e *Event) MarkFailed(cause error, now time.Time, policy RetryPolicy) {
+	e.Attempts++
+	if cause != nil {
+		e.LastError = cause.Error()
+	}
+	if policy.Exhausted(e.Attempts) {
+		e.Status = StatusDead
+		return
+	}
+	e.Status = StatusPending
+	e.NextAttemptAt = now.Add(policy.Backoff(e.Attempts))
+}
+
+// RetryPolicy 重试策略（指数退避）
+type RetryPolicy struct {
+	MaxAttempts int           // 最大投递次数，<=0 表示不限
+	BaseBackoff time.Duration // 首次失败后的等待时间
+	MaxBackoff  time.Duration // 退避上限
+}
+
+// Exhausted 是否已用尽重试次数
+func (p RetryPolicy) Exhausted(attempts int) bool {
+	return p.MaxAttempts > 0 && attempts >= p.MaxAttempts
+}
+
+// Backoff 返回第 attempts 次失败后的等待时间：base * 2^(attempts-1)，不超过 MaxBackoff
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	if p.BaseBackoff <= 0 || attempts <= 0 {
+		return 0
+	}
+	d := p.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return d
+}
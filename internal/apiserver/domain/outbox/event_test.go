@@ -0,0 +1,51 @@
+package outbox
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, BaseBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, p.Backoff(1))
+	assert.Equal(t, 2*time.Second, p.Backoff(2))
+	assert.Equal(t, 4*time.Second, p.Backoff(3))
+	assert.Equal(t, 5*time.Second, p.Backoff(4))
+	assert.Equal(t, 5*time.Second, p.Backoff(30))
+	assert.False(t, p.Exhausted(4))
+	assert.True(t, p.Exhausted(5))
+	assert.False(t, RetryPolicy{}.Exhausted(100))
+}
+
+func TestEventLifecycle(t *testing.T) {
+	e := NewEvent("topic", "type", "key-1", []byte("{}"), WithAggregate("agg", "t1"), WithMetadata("tenant_id", "t1"))
+	assert.Equal(t, StatusPending, e.Status)
+	assert.Equal(t, "t1", e.Metadata["tenant_id"])
+	assert.Equal(t, "agg", e.AggregateType)
+
+	now := time.Now()
+	policy := RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Second}
+	e.MarkFailed(errors.New("boom"), now, policy)
+	assert.Equal(t, StatusPending, e.Status)
+	assert.Equal(t, 1, e.Attempts)
+	assert.Equal(t, "boom", e.LastError)
+	assert.Equal(t, now.Add(time.Second), e.NextAttemptAt)
+
+	e.MarkFailed(errors.New("boom again"), now, policy)
+	assert.Equal(t, StatusDead, e.Status)
+
+	ok := NewEvent("topic", "type", "key-2", nil)
+	ok.MarkPublished(now)
+	assert.Equal(t, StatusPublished, ok.Status)
+	assert.NotNil(t, ok.PublishedAt)
+}
+
+func TestStatsLag(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, time.Duration(0), Stats{}.Lag(now))
+	oldest := now.Add(-3 * time.Second)
+	assert.Equal(t, 3*time.Second, Stats{OldestPendingAt: &oldest}.Lag(now))
+}
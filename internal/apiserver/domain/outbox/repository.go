@@ -0,0 +1,38 @@
+package outbox
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 发件箱仓储接口（Driven Port）
+type Repository interface {
+	// Append 追加事件（应在业务事务内调用）
+	Append(ctx context.Context, events ...*Event) error
+	// ClaimDue 认领到期的待投递事件，认领在 lease 时长内对其他中继实例不可见
+	ClaimDue(ctx context.Context, owner string, now time.Time, lease time.Duration, limit int) ([]*Event, error)
+	// Save 保存投递结果（状态、次数、下次投递时间、错误），并释放认领
+	Save(ctx context.Context, event *Event) error
+	// Stats 统计积压情况
+	Stats(ctx context.Context) (Stats, error)
+	// PurgePublished 清理早于 before 的已投递事件
+	PurgePublished(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Stats 发件箱积压统计
+type Stats struct {
+	Pending         int64      // 待投递数量
+	Dead            int64      // 死信数量
+	OldestPendingAt *time.Time // 最早一条待投递事件的创建时间
+}
+
+// Lag 返回相对 now 的积压时延
+func (s Stats) Lag(now time.Time) time.Duration {
+	if s.OldestPendingAt == nil {
+		return 0
+	}
+	if lag := now.Sub(*s.OldestPendingAt); lag > 0 {
+		return lag
+	}
+	return 0
+}
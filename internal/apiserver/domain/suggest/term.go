@@ -6,4 +6,26 @@ type Term struct {
 	ID     int64  `json:"id,string" swaggertype:"string"`
 	Mobile string `json:"mobile"`
 	Weight int    `json:"weight"`
+
+	TenantID  string  `json:"-"` // 所属租户
+	Guardians []int64 `json:"-"` // 监护人用户ID
+}
+
+// Scope 联想查询范围
+type Scope struct {
+	TenantID   string // 租户分区
+	GuardianID int64  // 非 0 时仅返回该用户监护的儿童
+}
+
+// Allows 判断结果项是否落在查询范围内
+func (s Scope) Allows(t Term) bool {
+	if s.GuardianID == 0 {
+		return true
+	}
+	for _, g := range t.Guardians {
+		if g == s.GuardianID {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,81 @@
+package authentication
+
+import (
+	"context"
+	"time"
+
+	authPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	credPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/credential"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// CredentialLockoutAdapter 凭据锁定适配器
+// 基于凭据领域的 Usage 服务与锁定策略，将失败计数与锁定时间持久化到 auth_credentials
+type CredentialLockoutAdapter struct {
+	credentials credPort.Repository
+	usage       credPort.Usage
+	policy      credPort.LockoutPolicy
+	now         func() time.Time
+}
+
+var _ authPort.CredentialLockout = (*CredentialLockoutAdapter)(nil)
+
+// NewCredentialLockoutAdapter 创建凭据锁定适配器
+func NewCredentialLockoutAdapter(credentials credPort.Repository, policy credPort.LockoutPolicy) *CredentialLockoutAdapter {
+	return &CredentialLockoutAdapter{
+		credentials: credentials,
+		usage:       credPort.NewUsage(),
+		policy:      policy,
+		now:         time.Now,
+	}
+}
+
+// IsLocked 凭据是否处于锁定期
+func (a *CredentialLockoutAdapter) IsLocked(ctx context.Context, credentialID meta.ID) (bool, error) {
+	cred, err := a.credentials.GetByID(ctx, credentialID)
+	if err != nil || cred == nil {
+		return false, err
+	}
+	return cred.IsLockedByTime(a.now()), nil
+}
+
+// RecordFailure 累加失败次数，达到策略阈值时锁定
+func (a *CredentialLockoutAdapter) RecordFailure(ctx context.Context, credentialID meta.ID) (bool, error) {
+	cred, err := a.credentials.GetByID(ctx, credentialID)
+	if err != nil || cred == nil {
+		return false, err
+	}
+
+	now := a.now()
+	locked := a.usage.RecordFailure(cred, now, a.policy)
+	if err := a.credentials.UpdateFailedAttempts(ctx, cred.ID, cred.FailedAttempts); err != nil {
+		return false, err
+	}
+	if err := a.credentials.UpdateLastFailureAt(ctx, cred.ID, now); err != nil {
+		return false, err
+	}
+	if locked {
+		if err := a.credentials.UpdateLockedUntil(ctx, cred.ID, cred.LockedUntil); err != nil {
+			return false, err
+		}
+	}
+	return locked, nil
+}
+
+// RecordSuccess 记录成功并清零失败次数
+func (a *CredentialLockoutAdapter) RecordSuccess(ctx context.Context, credentialID meta.ID) error {
+	cred, err := a.credentials.GetByID(ctx, credentialID)
+	if err != nil || cred == nil {
+		return err
+	}
+
+	now := a.now()
+	hadFailures := cred.FailedAttempts > 0
+	a.usage.RecordSuccess(cred, now)
+	if hadFailures {
+		if err := a.credentials.UpdateFailedAttempts(ctx, cred.ID, 0); err != nil {
+			return err
+		}
+	}
+	return a.credentials.UpdateLastSuccessAt(ctx, cred.ID, now)
+}
@@ -0,0 +1,42 @@
+package authentication
+
+import (
+	"context"
+	"time"
+
+	authPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	credPort "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/credential"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// PasswordExpiryAdapter 密码有效期检查适配器
+// 结合凭据的密码设置时间与租户密码策略判断是否过期
+type PasswordExpiryAdapter struct {
+	credentials credPort.Repository
+	policies    credPort.PasswordPolicyProvider
+	now         func() time.Time
+}
+
+var _ authPort.PasswordExpiryChecker = (*PasswordExpiryAdapter)(nil)
+
+// NewPasswordExpiryAdapter 创建密码有效期检查适配器
+func NewPasswordExpiryAdapter(credentials credPort.Repository, policies credPort.PasswordPolicyProvider) *PasswordExpiryAdapter {
+	return &PasswordExpiryAdapter{
+		credentials: credentials,
+		policies:    policies,
+		now:         time.Now,
+	}
+}
+
+// IsPasswordExpired 判断密码是否已过期
+func (a *PasswordExpiryAdapter) IsPasswordExpired(ctx context.Context, tenantID, credentialID meta.ID) (bool, error) {
+	policy := a.policies.PolicyFor(tenantID)
+	if policy.MaxAge <= 0 {
+		return false, nil
+	}
+	cred, err := a.credentials.GetByID(ctx, credentialID)
+	if err != nil || cred == nil {
+		return false, err
+	}
+	return policy.IsExpired(cred.PasswordChangedAt, a.now()), nil
+}
@@ -1,6 +1,12 @@
 package cache
 
-var inspectOnly = []GovernanceCapability{GovernanceCapabilityInspect}
+var (
+	inspectOnly = []GovernanceCapability{GovernanceCapabilityInspect}
+	// 可清理、可抽样：删除后由业务侧重新生成或视为失效
+	purgeable = []GovernanceCapability{GovernanceCapabilityInspect, GovernanceCapabilityPurge, GovernanceCapabilitySample}
+	// 只可抽样：删除 marker 会改变安全语义（如撤销令牌重新生效）
+	sampleOnly = []GovernanceCapability{GovernanceCapabilityInspect, GovernanceCapabilitySample}
+)
 
 var catalog = []FamilyDescriptor{
 	{
@@ -19,7 +25,7 @@ var catalog = []FamilyDescriptor{
 			InvalidationMode:               "TTL 到期或显式删除",
 			HasInternalRefreshCoordination: false,
 		},
-		Capabilities: inspectOnly,
+		Capabilities: purgeable,
 	},
 	{
 		Family:          FamilyAuthnRevokedAccessToken,
@@ -37,7 +43,7 @@ var catalog = []FamilyDescriptor{
 			InvalidationMode:               "TTL 到期",
 			HasInternalRefreshCoordination: false,
 		},
-		Capabilities: inspectOnly,
+		Capabilities: sampleOnly,
 	},
 	{
 		Family:          FamilyAuthnSession,
@@ -55,7 +61,7 @@ var catalog = []FamilyDescriptor{
 			InvalidationMode:               "TTL 到期或主动撤销后保留到自然过期",
 			HasInternalRefreshCoordination: false,
 		},
-		Capabilities: inspectOnly,
+		Capabilities: purgeable,
 	},
 	{
 		Family:          FamilyAuthnUserSessionIndex,
@@ -73,7 +79,7 @@ var catalog = []FamilyDescriptor{
 			InvalidationMode:               "撤销时移除成员，读取前懒清理过期成员",
 			HasInternalRefreshCoordination: false,
 		},
-		Capabilities: inspectOnly,
+		Capabilities: purgeable,
 	},
 	{
 		Family:          FamilyAuthnAccountSessionIndex,
@@ -91,7 +97,7 @@ var catalog = []FamilyDescriptor{
 			InvalidationMode:               "撤销时移除成员，读取前懒清理过期成员",
 			HasInternalRefreshCoordination: false,
 		},
-		Capabilities: inspectOnly,
+		Capabilities: purgeable,
 	},
 	{
 		Family:          FamilyAuthnLoginOTP,
@@ -109,7 +115,7 @@ var catalog = []FamilyDescriptor{
 			InvalidationMode:               "消费删除或 TTL 到期",
 			HasInternalRefreshCoordination: false,
 		},
-		Capabilities: inspectOnly,
+		Capabilities: purgeable,
 	},
 	{
 		Family:          FamilyAuthnLoginOTPSendGate,
@@ -127,7 +133,25 @@ var catalog = []FamilyDescriptor{
 			InvalidationMode:               "TTL 到期",
 			HasInternalRefreshCoordination: false,
 		},
-		Capabilities: inspectOnly,
+		Capabilities: purgeable,
+	},
+	{
+		Family:          FamilyAuthnLoginThrottle,
+		Backend:         BackendKindRedis,
+		RedisType:       RedisDataTypeString,
+		Codec:           ValueCodecKindString,
+		Role:            DataRoleMarkerState,
+		OwnerModule:     "authn",
+		KeyPattern:      "login_throttle:{ip|username|phone}:{value}",
+		TTLSource:       "节流计数窗口",
+		SelectionReason: "固定窗口计数器，INCR 原子累加，窗口到期自动清零。",
+		Policy: FamilyPolicy{
+			TTLSource:                      "节流计数窗口",
+			WriteMode:                      "INCR + 首次 PEXPIRE（Lua）",
+			InvalidationMode:               "登录成功/管理员解锁删除或 TTL 到期",
+			HasInternalRefreshCoordination: false,
+		},
+		Capabilities: purgeable,
 	},
 	{
 		Family:          FamilyIDPWechatAccessToken,
@@ -145,7 +169,7 @@ var catalog = []FamilyDescriptor{
 			InvalidationMode:               "TTL 到期或刷新覆盖",
 			HasInternalRefreshCoordination: true,
 		},
-		Capabilities: inspectOnly,
+		Capabilities: []GovernanceCapability{GovernanceCapabilityInspect, GovernanceCapabilityPurge, GovernanceCapabilityWarm, GovernanceCapabilitySample},
 	},
 	{
 		Family:          FamilyIDPWechatSDK,
@@ -181,7 +205,7 @@ var catalog = []FamilyDescriptor{
 			InvalidationMode:               "重建刷新",
 			HasInternalRefreshCoordination: false,
 		},
-		Capabilities: inspectOnly,
+		Capabilities: []GovernanceCapability{GovernanceCapabilityInspect, GovernanceCapabilityWarm},
 	},
 }
 
@@ -4,8 +4,8 @@ import "testing"
 
 func TestCatalogContainsAllCurrentFamilies(t *testing.T) {
 	families := Families()
-	if len(families) != 10 {
-		t.Fatalf("Families() count = %d, want %d", len(families), 10)
+	if len(families) != 11 {
+		t.Fatalf("Families() count = %d, want %d", len(families), 11)
 	}
 
 	expected := map[Family]struct{}{
@@ -16,6 +16,7 @@ func TestCatalogContainsAllCurrentFamilies(t *testing.T) {
 		FamilyAuthnAccountSessionIndex: {},
 		FamilyAuthnLoginOTP:            {},
 		FamilyAuthnLoginOTPSendGate:    {},
+		FamilyAuthnLoginThrottle:       {},
 		FamilyIDPWechatAccessToken:     {},
 		FamilyIDPWechatSDK:             {},
 		FamilyAuthnJWKSPublishSnapshot: {},
@@ -42,6 +43,7 @@ func TestCurrentRedisBackedFamiliesUseExpectedDataTypes(t *testing.T) {
 		FamilyAuthnAccountSessionIndex: RedisDataTypeZSet,
 		FamilyAuthnLoginOTP:            RedisDataTypeString,
 		FamilyAuthnLoginOTPSendGate:    RedisDataTypeString,
+		FamilyAuthnLoginThrottle:       RedisDataTypeString,
 		FamilyIDPWechatAccessToken:     RedisDataTypeString,
 		FamilyIDPWechatSDK:             RedisDataTypeString,
 	}
@@ -3,7 +3,7 @@
 // 这一层只负责回答三个问题：
 // 1. 当前有哪些 cache family；
 // 2. 每个 family 使用什么后端、什么 Redis 数据结构、什么编码；
-// 3. 第一版治理面可以如何只读地观察它们。
+// 3. 治理面可以如何观察它们，以及开放了哪些控制能力（清理、预热、抽样）。
 //
-// 它不负责实际 Redis 读写，也不负责控制动作。
+// 它不负责实际 Redis 读写；控制动作由适配器实现 FamilyOperator / FamilyWarmer。
 package cache
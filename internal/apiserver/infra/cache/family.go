@@ -11,6 +11,7 @@ const (
 	FamilyAuthnAccountSessionIndex Family = "authn.account_session_index"
 	FamilyAuthnLoginOTP            Family = "authn.login_otp"
 	FamilyAuthnLoginOTPSendGate    Family = "authn.login_otp_send_gate"
+	FamilyAuthnLoginThrottle       Family = "authn.login_throttle"
 	FamilyIDPWechatAccessToken     Family = "idp.wechat_access_token"
 	FamilyIDPWechatSDK             Family = "idp.wechat_sdk"
 	FamilyAuthnJWKSPublishSnapshot Family = "authn.jwks_publish_snapshot"
@@ -34,9 +35,12 @@ const (
 	DataRoleDerivedSnapshot    DataRole = "derived_snapshot"
 )
 
-// GovernanceCapability 表示第一版治理面对 family 暴露的能力。
+// GovernanceCapability 表示治理面对 family 暴露的能力。
 type GovernanceCapability string
 
 const (
 	GovernanceCapabilityInspect GovernanceCapability = "inspect"
+	GovernanceCapabilityPurge   GovernanceCapability = "purge"
+	GovernanceCapabilityWarm    GovernanceCapability = "warm"
+	GovernanceCapabilitySample  GovernanceCapability = "sample"
 )
@@ -1,6 +1,9 @@
 package cache
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // FamilyInspector 负责读取某个缓存族的只读状态。
 type FamilyInspector interface {
@@ -13,3 +16,24 @@ type RuntimeStatusReader interface {
 	Backend() BackendKind
 	Status(ctx context.Context) (RuntimeStatus, error)
 }
+
+// KeySample 描述一次抽样得到的缓存 key 及其剩余 TTL。
+type KeySample struct {
+	Key        string
+	TTL        time.Duration
+	Persistent bool
+}
+
+// FamilyOperator 负责对某个缓存族执行清理与 key 抽样。
+type FamilyOperator interface {
+	Family() Family
+	// Purge 删除单个 key；key 为空时清理整个缓存族，返回删除数量。
+	Purge(ctx context.Context, key string) (int64, error)
+	Sample(ctx context.Context, limit int) ([]KeySample, error)
+}
+
+// FamilyWarmer 负责触发某个缓存族的预热，target 的含义由缓存族决定（如 appID）。
+type FamilyWarmer interface {
+	Family() Family
+	Warm(ctx context.Context, target string) error
+}
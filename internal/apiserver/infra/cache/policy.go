@@ -22,3 +22,13 @@ type FamilyDescriptor struct {
 	Policy          FamilyPolicy
 	Capabilities    []GovernanceCapability
 }
+
+// Supports 判断缓存族是否开放指定治理能力。
+func (d FamilyDescriptor) Supports(capability GovernanceCapability) bool {
+	for _, c := range d.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package crypto
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1" // #nosec G505 -- 泄露库按 SHA-1 索引，仅用于查表
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/credential"
+)
+
+// breachedPrefixLen k-匿名前缀长度（与 HIBP range API 一致）
+const breachedPrefixLen = 5
+
+// PrefixFileBreachedChecker 基于本地 k-匿名哈希前缀库的泄露密码检查（完全离线）
+//
+// 支持两种布局（均为 HIBP Pwned Passwords 兼容格式）：
+//   - 目录：每个前缀一个文件 <PREFIX>.txt，行格式 SUFFIX:COUNT（官方 downloader 产物），按需读取；
+//   - 单文件：行格式 SHA1:COUNT，启动后首次检查时按前缀分桶载入内存，适合裁剪后的高频子集。
+type PrefixFileBreachedChecker struct {
+	path     string
+	minCount int
+
+	once    sync.Once
+	isDir   bool
+	buckets map[string]map[string]int
+	loadErr error
+}
+
+var _ credential.BreachedPasswordChecker = (*PrefixFileBreachedChecker)(nil)
+
+// NewPrefixFileBreachedChecker 创建泄露密码检查器；minCount 为判定泄露的最小出现次数（<=0 时为 1）
+func NewPrefixFileBreachedChecker(path string, minCount int) *PrefixFileBreachedChecker {
+	if minCount <= 0 {
+		minCount = 1
+	}
+	return &PrefixFileBreachedChecker{path: path, minCount: minCount}
+}
+
+// IsBreached 判断密码是否出现在泄露库中
+func (c *PrefixFileBreachedChecker) IsBreached(_ context.Context, plain string) (bool, error) {
+	c.once.Do(c.load)
+	if c.loadErr != nil {
+		return false, c.loadErr
+	}
+
+	sum := sha1.Sum([]byte(plain)) // #nosec G401
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := digest[:breachedPrefixLen], digest[breachedPrefixLen:]
+
+	if !c.isDir {
+		return c.buckets[prefix][suffix] >= c.minCount, nil
+	}
+
+	f, err := os.Open(filepath.Join(c.path, prefix+".txt"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("open breached prefix file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		s, count, ok := parseBreachedLine(scanner.Text())
+		if ok && s == suffix {
+			return count >= c.minCount, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+func (c *PrefixFileBreachedChecker) load() {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		c.loadErr = fmt.Errorf("stat breached password corpus: %w", err)
+		return
+	}
+	if info.IsDir() {
+		c.isDir = true
+		return
+	}
+
+	f, err := os.Open(c.path)
+	if err != nil {
+		c.loadErr = fmt.Errorf("open breached password corpus: %w", err)
+		return
+	}
+	defer f.Close()
+
+	c.buckets = make(map[string]map[string]int)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		hash, count, ok := parseBreachedLine(scanner.Text())
+		if !ok || len(hash) != sha1.Size*2 {
+			continue
+		}
+		prefix, suffix := hash[:breachedPrefixLen], hash[breachedPrefixLen:]
+		bucket := c.buckets[prefix]
+		if bucket == nil {
+			bucket = make(map[string]int)
+			c.buckets[prefix] = bucket
+		}
+		bucket[suffix] = count
+	}
+	c.loadErr = scanner.Err()
+}
+
+// parseBreachedLine 解析 HASH[:COUNT] 行；缺省计数视为 1
+func parseBreachedLine(line string) (string, int, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", 0, false
+	}
+	hash, rawCount, hasCount := strings.Cut(line, ":")
+	count := 1
+	if hasCount {
+		n, err := strconv.Atoi(strings.TrimSpace(rawCount))
+		if err != nil {
+			return "", 0, false
+		}
+		count = n
+	}
+	return strings.ToUpper(strings.TrimSpace(hash)), count, true
+}
@@ -0,0 +1,59 @@
+package crypto
+
+import (
+	"context"
+	"crypto/sha1" // #nosec G505
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha1Upper(s string) string {
+	sum := sha1.Sum([]byte(s)) // #nosec G401
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func TestPrefixFileBreachedCheckerDirectoryLayout(t *testing.T) {
+	dir := t.TempDir()
+	h := sha1Upper("password123")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, h[:5]+".txt"),
+		[]byte("0000000000000000000000000000000000A:3\n"+h[5:]+":42\n"), 0o600))
+
+	checker := NewPrefixFileBreachedChecker(dir, 10)
+	breached, err := checker.IsBreached(context.Background(), "password123")
+	require.NoError(t, err)
+	assert.True(t, breached)
+
+	breached, err = checker.IsBreached(context.Background(), "N0t-In-The-Corpus!")
+	require.NoError(t, err)
+	assert.False(t, breached)
+
+	// 出现次数低于阈值不判定为泄露
+	breached, err = NewPrefixFileBreachedChecker(dir, 100).IsBreached(context.Background(), "password123")
+	require.NoError(t, err)
+	assert.False(t, breached)
+}
+
+func TestPrefixFileBreachedCheckerSingleFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	content := "# subset\n" + strings.ToLower(sha1Upper("qwerty")) + ":5\n" + sha1Upper("letmein") + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	checker := NewPrefixFileBreachedChecker(path, 0)
+	for _, pw := range []string{"qwerty", "letmein"} {
+		breached, err := checker.IsBreached(context.Background(), pw)
+		require.NoError(t, err)
+		assert.True(t, breached, pw)
+	}
+	breached, err := checker.IsBreached(context.Background(), "correct horse battery staple")
+	require.NoError(t, err)
+	assert.False(t, breached)
+
+	_, err = NewPrefixFileBreachedChecker(filepath.Join(t.TempDir(), "missing"), 1).IsBreached(context.Background(), "x")
+	assert.Error(t, err)
+}
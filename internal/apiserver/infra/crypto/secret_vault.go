@@ -18,11 +18,38 @@ type secretVault struct {
 	gcm       cipher.AEAD
 }
 
+// AEADVault 支持附加认证数据（AAD）的密钥加密服务
+// AAD 不加密但参与认证，用于把密文绑定到所属记录，防止密文在记录之间被替换
+type AEADVault interface {
+	wechatapp.SecretVault
+	// EncryptWithAAD 加密明文并以 aad 认证
+	EncryptWithAAD(ctx context.Context, plaintext, aad []byte) ([]byte, error)
+	// DecryptWithAAD 解密密文，aad 与加密时不一致则失败
+	DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) ([]byte, error)
+}
+
 // 确保实现了接口
-var _ wechatapp.SecretVault = (*secretVault)(nil)
+var _ AEADVault = (*secretVault)(nil)
 
 // NewSecretVault 创建密钥加密服务实例
 func NewSecretVault(masterKey []byte) (wechatapp.SecretVault, error) {
+	v, err := newSecretVault(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// NewAEADVault 创建支持 AAD 的密钥加密服务实例
+func NewAEADVault(masterKey []byte) (AEADVault, error) {
+	v, err := newSecretVault(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func newSecretVault(masterKey []byte) (*secretVault, error) {
 	if len(masterKey) != 32 {
 		return nil, errors.New("master key must be 32 bytes for AES-256")
 	}
@@ -47,6 +74,11 @@ func NewSecretVault(masterKey []byte) (wechatapp.SecretVault, error) {
 
 // Encrypt 加密明文
 func (v *secretVault) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return v.EncryptWithAAD(ctx, plaintext, nil)
+}
+
+// EncryptWithAAD 加密明文并以 aad 认证
+func (v *secretVault) EncryptWithAAD(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
 	if len(plaintext) == 0 {
 		return nil, errors.New("plaintext cannot be empty")
 	}
@@ -59,13 +91,18 @@ func (v *secretVault) Encrypt(ctx context.Context, plaintext []byte) ([]byte, er
 
 	// 加密
 	// 格式: nonce || ciphertext
-	ciphertext := v.gcm.Seal(nonce, nonce, plaintext, nil)
+	ciphertext := v.gcm.Seal(nonce, nonce, plaintext, aad)
 
 	return ciphertext, nil
 }
 
 // Decrypt 解密密文
 func (v *secretVault) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return v.DecryptWithAAD(ctx, ciphertext, nil)
+}
+
+// DecryptWithAAD 解密密文，aad 与加密时不一致则失败
+func (v *secretVault) DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
 	if len(ciphertext) == 0 {
 		return nil, errors.New("ciphertext cannot be empty")
 	}
@@ -79,7 +116,7 @@ func (v *secretVault) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, e
 	nonce, encryptedData := ciphertext[:nonceSize], ciphertext[nonceSize:]
 
 	// 解密
-	plaintext, err := v.gcm.Open(nil, nonce, encryptedData, nil)
+	plaintext, err := v.gcm.Open(nil, nonce, encryptedData, aad)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt: %w", err)
 	}
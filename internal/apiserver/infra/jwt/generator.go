@@ -241,20 +241,15 @@ func (g *Generator) signClaims(ctx context.Context, claims CustomClaims) (string
 		return "", fmt.Errorf("failed to get active key: %w", err)
 	}
 
-	privKey, err := g.privKeyResolver.ResolveSigningKey(ctx, activeKey.Kid, activeKey.JWK.Alg)
+	signer, err := g.resolveSigner(ctx, activeKey.Kid, activeKey.JWK.Alg)
 	if err != nil {
-		return "", fmt.Errorf("failed to resolve private key: %w", err)
+		return "", err
 	}
 
-	rsaPrivKey, ok := privKey.(*rsa.PrivateKey)
-	if !ok {
-		return "", fmt.Errorf("expected RSA private key, got %T", privKey)
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token := jwt.NewWithClaims(signingMethodSignerRS256, claims)
 	token.Header["kid"] = activeKey.Kid
 
-	tokenString, err := token.SignedString(rsaPrivKey)
+	tokenString, err := token.SignedString(signer)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -2,9 +2,12 @@ package jwt
 
 import (
 	"context"
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/base64"
+	"errors"
+	"io"
 	"math/big"
 	"testing"
 	"time"
@@ -72,6 +75,31 @@ func TestGeneratorServiceTokenUsesRegisteredAudience(t *testing.T) {
 	require.False(t, hasLegacyAudience)
 }
 
+func TestGeneratorSignsThroughExternalSigner(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	kid := "hsm-key"
+	manager := &jwksManagerStub{
+		activeKey: newRSAJWKKey(t, kid, &privKey.PublicKey),
+		keys: map[string]*domainjwks.Key{
+			kid: newRSAJWKKey(t, kid, &privKey.PublicKey),
+		},
+	}
+	hsm := &hsmSignerResolverStub{slots: map[string]*rsa.PrivateKey{kid: privKey}}
+	generator := NewGenerator("https://iam.fangcunmount.cn", nil, manager, hsm)
+
+	token, err := generator.GenerateServiceToken(context.Background(), "svc:worker", []string{"collection-api"}, nil, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 1, hsm.signs)
+
+	claims, err := generator.ParseAccessToken(context.Background(), token.Value)
+	require.NoError(t, err)
+	require.Equal(t, "svc:worker", claims.Subject)
+}
+
 func newTestGenerator(t *testing.T, issuer string, accessAudience []string) (*Generator, *rsa.PrivateKey) {
 	t.Helper()
 
@@ -171,3 +199,35 @@ type privateKeyResolverStub struct {
 func (s *privateKeyResolverStub) ResolveSigningKey(ctx context.Context, kid, alg string) (any, error) {
 	return s.keys[kid], nil
 }
+
+// hsmSignerResolverStub 模拟 softHSM：只交出 crypto.Signer，拒绝导出私钥
+type hsmSignerResolverStub struct {
+	slots map[string]*rsa.PrivateKey
+	signs int
+}
+
+func (s *hsmSignerResolverStub) ResolveSigningKey(ctx context.Context, kid, alg string) (any, error) {
+	return nil, errors.New("key material is not exportable")
+}
+
+func (s *hsmSignerResolverStub) ResolveSigner(ctx context.Context, kid, alg string) (crypto.Signer, error) {
+	key, ok := s.slots[kid]
+	if !ok {
+		return nil, errors.New("slot not found")
+	}
+	return &hsmSigner{stub: s, key: key}, nil
+}
+
+type hsmSigner struct {
+	stub *hsmSignerResolverStub
+	key  *rsa.PrivateKey
+}
+
+func (h *hsmSigner) Public() crypto.PublicKey {
+	return &h.key.PublicKey
+}
+
+func (h *hsmSigner) Sign(r io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	h.stub.signs++
+	return rsa.SignPKCS1v15(r, h.key, opts.HashFunc(), digest)
+}
@@ -0,0 +1,72 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/jwks"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// signerMethod 基于 crypto.Signer 的 RS256 签名方法
+// 签名只经过 Signer.Sign，私钥可以留在外部签名设备内；验签复用标准 RS256
+type signerMethod struct {
+	hash crypto.Hash
+}
+
+var signingMethodSignerRS256 jwt.SigningMethod = &signerMethod{hash: crypto.SHA256}
+
+func (m *signerMethod) Alg() string {
+	return jwt.SigningMethodRS256.Alg()
+}
+
+func (m *signerMethod) Verify(signingString, signature string, key interface{}) error {
+	return jwt.SigningMethodRS256.Verify(signingString, signature, key)
+}
+
+func (m *signerMethod) Sign(signingString string, key interface{}) (string, error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return "", jwt.ErrInvalidKey
+	}
+	hasher := m.hash.New()
+	hasher.Write([]byte(signingString))
+	sig, err := signer.Sign(rand.Reader, hasher.Sum(nil), m.hash)
+	if err != nil {
+		return "", err
+	}
+	return jwt.EncodeSegment(sig), nil
+}
+
+// resolveSigner 解析签名器：优先使用 SignerResolver，否则把解析出的私钥当作 Signer
+func (g *Generator) resolveSigner(ctx context.Context, kid, alg string) (crypto.Signer, error) {
+	var (
+		signer crypto.Signer
+		err    error
+	)
+	if sr, ok := g.privKeyResolver.(jwks.SignerResolver); ok {
+		signer, err = sr.ResolveSigner(ctx, kid, alg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve signer: %w", err)
+		}
+	} else {
+		privKey, err := g.privKeyResolver.ResolveSigningKey(ctx, kid, alg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve private key: %w", err)
+		}
+		s, ok := privKey.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("private key %T cannot sign", privKey)
+		}
+		signer = s
+	}
+
+	// 当前仅签发 RS256，签名器公钥必须是 RSA
+	if _, ok := signer.Public().(*rsa.PublicKey); !ok {
+		return nil, fmt.Errorf("expected RSA signer, got %T", signer.Public())
+	}
+	return signer, nil
+}
@@ -0,0 +1,54 @@
+package messaging
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// outboxMetrics 发件箱中继指标，注册到 Prometheus 默认注册表（与 /metrics 一致）
+type outboxMetrics struct {
+	pending    prometheus.Gauge
+	dead       prometheus.Gauge
+	lagSeconds prometheus.Gauge
+	published  prometheus.Counter
+	failed     prometheus.Counter
+}
+
+var (
+	outboxMetricsOnce    sync.Once
+	defaultOutboxMetrics *outboxMetrics
+)
+
+// getOutboxMetrics 返回进程内唯一的发件箱指标集合
+func getOutboxMetrics() *outboxMetrics {
+	outboxMetricsOnce.Do(func() {
+		m := &outboxMetrics{
+			pending: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: "iam", Subsystem: "outbox", Name: "pending_events",
+				Help: "Number of outbox events waiting to be relayed.",
+			}),
+			dead: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: "iam", Subsystem: "outbox", Name: "dead_events",
+				Help: "Number of outbox events that exhausted their retries.",
+			}),
+			lagSeconds: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: "iam", Subsystem: "outbox", Name: "lag_seconds",
+				Help: "Age in seconds of the oldest pending outbox event.",
+			}),
+			published: prometheus.NewCounter(prometheus.CounterOpts{
+				Namespace: "iam", Subsystem: "outbox", Name: "published_total",
+				Help: "Total number of outbox events relayed successfully.",
+			}),
+			failed: prometheus.NewCounter(prometheus.CounterOpts{
+				Namespace: "iam", Subsystem: "outbox", Name: "publish_failures_total",
+				Help: "Total number of failed outbox relay attempts.",
+			}),
+		}
+		for _, c := range []prometheus.Collector{m.pending, m.dead, m.lagSeconds, m.published, m.failed} {
+			_ = prometheus.DefaultRegisterer.Register(c)
+		}
+		defaultOutboxMetrics = m
+	})
+	return defaultOutboxMetrics
+}
@@ -0,0 +1,379 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/log"
+	"github.com/FangcunMount/component-base/pkg/messaging"
+	"github.com/spf13/viper"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/outbox"
+)
+
+// OutboxRelayConfig 发件箱中继配置
+type OutboxRelayConfig struct {
+	Enabled      bool
+	PollInterval time.Duration // 轮询间隔
+	BatchSize    int           // 单次认领数量
+	ClaimTTL     time.Duration // 认领租期，实例崩溃后超过租期的事件会被其他实例接管
+	MaxAttempts  int           // 最大投递次数，超过后转为死信
+	BaseBackoff  time.Duration // 失败重试初始退避
+	MaxBackoff   time.Duration // 失败重试退避上限
+	Retention    time.Duration // 已投递事件保留时长，<=0 表示不清理
+}
+
+// DefaultOutboxRelayConfig 返回默认配置
+func DefaultOutboxRelayConfig() OutboxRelayConfig {
+	return OutboxRelayConfig{
+		Enabled:      true,
+		PollInterval: time.Second,
+		BatchSize:    100,
+		ClaimTTL:     30 * time.Second,
+		MaxAttempts:  10,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   5 * time.Minute,
+		Retention:    7 * 24 * time.Hour,
+	}
+}
+
+// LoadOutboxRelayConfig 从 viper 读取 outbox 配置
+func LoadOutboxRelayConfig() OutboxRelayConfig {
+	cfg := DefaultOutboxRelayConfig()
+
+	sub := viper.Sub("outbox")
+	if sub == nil {
+		return cfg
+	}
+
+	if sub.IsSet("enabled") {
+		cfg.Enabled = sub.GetBool("enabled")
+	}
+	if v := sub.GetDuration("poll_interval"); v > 0 {
+		cfg.PollInterval = v
+	}
+	if v := sub.GetInt("batch_size"); v > 0 {
+		cfg.BatchSize = v
+	}
+	if v := sub.GetDuration("claim_ttl"); v > 0 {
+		cfg.ClaimTTL = v
+	}
+	if sub.IsSet("max_attempts") {
+		cfg.MaxAttempts = sub.GetInt("max_attempts")
+	}
+	if v := sub.GetDuration("base_backoff"); v > 0 {
+		cfg.BaseBackoff = v
+	}
+	if v := sub.GetDuration("max_backoff"); v > 0 {
+		cfg.MaxBackoff = v
+	}
+	if sub.IsSet("retention") {
+		cfg.Retention = sub.GetDuration("retention")
+	}
+	return cfg
+}
+
+// RetryPolicy 转换为领域重试策略
+func (c OutboxRelayConfig) RetryPolicy() outbox.RetryPolicy {
+	return outbox.RetryPolicy{
+		MaxAttempts: c.MaxAttempts,
+		BaseBackoff: c.BaseBackoff,
+		MaxBackoff:  c.MaxBackoff,
+	}
+}
+
+// OutboxRelayStatus 中继运行状态（用于 /debug/modules）
+type OutboxRelayStatus struct {
+	Running         bool       `json:"running"`
+	Owner           string     `json:"owner"`
+	Pending         int64      `json:"pending"`
+	Dead            int64      `json:"dead"`
+	LagSeconds      float64    `json:"lag_seconds"`
+	OldestPendingAt *time.Time `json:"oldest_pending_at,omitempty"`
+	PublishedTotal  int64      `json:"published_total"`
+	FailedTotal     int64      `json:"failed_total"`
+	LastRelayAt     *time.Time `json:"last_relay_at,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+}
+
+// OutboxRelay 发件箱中继
+//
+// 周期性认领到期事件并投递到消息总线。消息 UUID 使用事件幂等键，
+// 中继在“已发布未标记”时崩溃导致的重复投递可由消费端按 UUID 去重。
+type OutboxRelay struct {
+	repo      outbox.Repository
+	publisher messaging.Publisher
+	cfg       OutboxRelayConfig
+	owner     string
+	metrics   *outboxMetrics
+	now       func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu             sync.RWMutex
+	running        bool
+	publishedTotal int64
+	failedTotal    int64
+	lastRelayAt    *time.Time
+	lastErr        string
+	lastPurgeAt    time.Time
+}
+
+// NewOutboxRelay 创建发件箱中继
+func NewOutboxRelay(repo outbox.Repository, publisher messaging.Publisher, cfg OutboxRelayConfig) *OutboxRelay {
+	defaults := DefaultOutboxRelayConfig()
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaults.PollInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaults.BatchSize
+	}
+	if cfg.ClaimTTL <= 0 {
+		cfg.ClaimTTL = defaults.ClaimTTL
+	}
+
+	hostname, _ := os.Hostname()
+	return &OutboxRelay{
+		repo:      repo,
+		publisher: publisher,
+		cfg:       cfg,
+		owner:     fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		metrics:   getOutboxMetrics(),
+		now:       time.Now,
+	}
+}
+
+// Start 启动中继
+func (r *OutboxRelay) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running {
+		return nil
+	}
+
+	r.ctx, r.cancel = context.WithCancel(ctx)
+	r.running = true
+
+	r.wg.Add(1)
+	go r.run()
+
+	log.Infow("outbox relay started",
+		"owner", r.owner,
+		"pollInterval", r.cfg.PollInterval,
+		"batchSize", r.cfg.BatchSize,
+	)
+	return nil
+}
+
+// Stop 停止中继，等待当前批次处理完成
+func (r *OutboxRelay) Stop() error {
+	r.mu.Lock()
+	if !r.running {
+		r.mu.Unlock()
+		return nil
+	}
+	r.cancel()
+	r.mu.Unlock()
+
+	r.wg.Wait()
+
+	r.mu.Lock()
+	r.running = false
+	r.mu.Unlock()
+
+	log.Info("outbox relay stopped")
+	return nil
+}
+
+// IsRunning 返回中继是否正在运行
+func (r *OutboxRelay) IsRunning() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.running
+}
+
+// run 中继主循环
+func (r *OutboxRelay) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.tick(r.ctx)
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick 处理一轮：持续投递直到没有到期事件，然后刷新指标并按需清理
+func (r *OutboxRelay) tick(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, err := r.RelayOnce(ctx)
+		if err != nil {
+			log.Errorw("outbox relay batch failed", "owner", r.owner, "error", err)
+			break
+		}
+		if n < r.cfg.BatchSize {
+			break
+		}
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	if _, err := r.refreshStats(ctx); err != nil {
+		log.Warnw("failed to refresh outbox stats", "error", err)
+	}
+	r.purge(ctx)
+}
+
+// RelayOnce 认领并投递一批到期事件，返回本批处理的事件数
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	now := r.now()
+	events, err := r.repo.ClaimDue(ctx, r.owner, now, r.cfg.ClaimTTL, r.cfg.BatchSize)
+	if err != nil {
+		r.recordError(err)
+		return 0, err
+	}
+
+	policy := r.cfg.RetryPolicy()
+	for _, event := range events {
+		if pubErr := r.publish(ctx, event); pubErr != nil {
+			event.MarkFailed(pubErr, r.now(), policy)
+			r.metrics.failed.Inc()
+			r.recordFailure(pubErr)
+			if event.Status == outbox.StatusDead {
+				log.Errorw("outbox event moved to dead letter",
+					"id", event.ID.String(),
+					"topic", event.Topic,
+					"idempotencyKey", event.IdempotencyKey,
+					"attempts", event.Attempts,
+					"error", pubErr,
+				)
+			} else {
+				log.Warnw("outbox event publish failed, will retry",
+					"id", event.ID.String(),
+					"topic", event.Topic,
+					"attempts", event.Attempts,
+					"nextAttemptAt", event.NextAttemptAt,
+					"error", pubErr,
+				)
+			}
+		} else {
+			event.MarkPublished(r.now())
+			r.metrics.published.Inc()
+			r.recordSuccess()
+		}
+		if err := r.repo.Save(ctx, event); err != nil {
+			// 保存失败时事件仍处于认领状态，租期过后会被重新投递，由幂等键兜底
+			r.recordError(err)
+			return len(events), fmt.Errorf("save outbox event %s: %w", event.ID.String(), err)
+		}
+	}
+	return len(events), nil
+}
+
+// publish 投递单个事件，消息 UUID 即幂等键
+func (r *OutboxRelay) publish(ctx context.Context, event *outbox.Event) error {
+	if r.publisher == nil {
+		return fmt.Errorf("publisher not configured")
+	}
+	msg := messaging.NewMessage(event.IdempotencyKey, event.Payload)
+	if msg.Metadata == nil {
+		msg.Metadata = map[string]string{}
+	}
+	for k, v := range event.Metadata {
+		msg.Metadata[k] = v
+	}
+	msg.Metadata["idempotency_key"] = event.IdempotencyKey
+	msg.Metadata["event_type"] = event.EventType
+	msg.Metadata["outbox_id"] = event.ID.String()
+	return r.publisher.PublishMessage(ctx, event.Topic, msg)
+}
+
+// Status 返回中继状态与积压情况
+func (r *OutboxRelay) Status(ctx context.Context) OutboxRelayStatus {
+	r.mu.RLock()
+	status := OutboxRelayStatus{
+		Running:        r.running,
+		Owner:          r.owner,
+		PublishedTotal: r.publishedTotal,
+		FailedTotal:    r.failedTotal,
+		LastRelayAt:    r.lastRelayAt,
+		LastError:      r.lastErr,
+	}
+	r.mu.RUnlock()
+
+	stats, err := r.refreshStats(ctx)
+	if err != nil {
+		status.LastError = err.Error()
+		return status
+	}
+	status.Pending = stats.Pending
+	status.Dead = stats.Dead
+	status.OldestPendingAt = stats.OldestPendingAt
+	status.LagSeconds = stats.Lag(r.now()).Seconds()
+	return status
+}
+
+// refreshStats 查询积压并更新指标
+func (r *OutboxRelay) refreshStats(ctx context.Context) (outbox.Stats, error) {
+	stats, err := r.repo.Stats(ctx)
+	if err != nil {
+		return stats, err
+	}
+	r.metrics.pending.Set(float64(stats.Pending))
+	r.metrics.dead.Set(float64(stats.Dead))
+	r.metrics.lagSeconds.Set(stats.Lag(r.now()).Seconds())
+	return stats, nil
+}
+
+// purge 每小时最多清理一次过期的已投递事件
+func (r *OutboxRelay) purge(ctx context.Context) {
+	if r.cfg.Retention <= 0 {
+		return
+	}
+	now := r.now()
+	if now.Sub(r.lastPurgeAt) < time.Hour {
+		return
+	}
+	r.lastPurgeAt = now
+	n, err := r.repo.PurgePublished(ctx, now.Add(-r.cfg.Retention))
+	if err != nil {
+		log.Warnw("failed to purge published outbox events", "error", err)
+		return
+	}
+	if n > 0 {
+		log.Infow("purged published outbox events", "count", n)
+	}
+}
+
+func (r *OutboxRelay) recordSuccess() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	r.publishedTotal++
+	r.lastRelayAt = &now
+}
+
+func (r *OutboxRelay) recordFailure(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failedTotal++
+	r.lastErr = err.Error()
+}
+
+func (r *OutboxRelay) recordError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastErr = err.Error()
+}
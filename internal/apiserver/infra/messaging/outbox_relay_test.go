@@ -0,0 +1,223 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	policyDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/outbox"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+func TestOutboxRelay_RetriesThenPublishesWithIdempotencyKey(t *testing.T) {
+	repo := newMemoryOutboxRepo()
+	version := policyDomain.NewPolicyVersion("tenant-a", 3)
+	require.NoError(t, NewVersionOutbox(repo).Enqueue(context.Background(), &version))
+
+	publisher := &flakyPublisher{failures: 1}
+	relay := NewOutboxRelay(repo, publisher, OutboxRelayConfig{
+		BatchSize:   10,
+		MaxAttempts: 3,
+		BaseBackoff: time.Minute,
+	})
+	now := time.Now()
+	relay.now = func() time.Time { return now }
+
+	n, err := relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Empty(t, publisher.messages)
+
+	event := repo.only(t)
+	assert.Equal(t, outbox.StatusPending, event.Status)
+	assert.Equal(t, 1, event.Attempts)
+	assert.Equal(t, now.Add(time.Minute), event.NextAttemptAt)
+
+	// 退避未到期，不会重新投递
+	n, err = relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	now = now.Add(2 * time.Minute)
+	n, err = relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	require.Len(t, publisher.messages, 1)
+	msg := publisher.messages[0]
+	assert.Equal(t, AuthzVersionTopic, msg.topic)
+	assert.Equal(t, VersionIdempotencyKey("tenant-a", 3), msg.UUID)
+	assert.Equal(t, msg.UUID, msg.Metadata["idempotency_key"])
+	assert.Equal(t, "tenant-a", msg.Metadata["tenant_id"])
+	assert.JSONEq(t, `{"tenant_id":"tenant-a","version":3}`, string(msg.Payload))
+	assert.Equal(t, outbox.StatusPublished, repo.only(t).Status)
+
+	status := relay.Status(context.Background())
+	assert.Equal(t, int64(1), status.PublishedTotal)
+	assert.Equal(t, int64(1), status.FailedTotal)
+	assert.Equal(t, int64(0), status.Pending)
+}
+
+func TestOutboxRelay_MovesToDeadAfterMaxAttempts(t *testing.T) {
+	repo := newMemoryOutboxRepo()
+	require.NoError(t, repo.Append(context.Background(), outbox.NewEvent("topic", "type", "k", []byte(`{}`))))
+
+	relay := NewOutboxRelay(repo, &flakyPublisher{failures: 100}, OutboxRelayConfig{
+		BatchSize:   10,
+		MaxAttempts: 2,
+	})
+
+	for i := 0; i < 3; i++ {
+		_, err := relay.RelayOnce(context.Background())
+		require.NoError(t, err)
+	}
+
+	event := repo.only(t)
+	assert.Equal(t, outbox.StatusDead, event.Status)
+	assert.Equal(t, 2, event.Attempts)
+
+	status := relay.Status(context.Background())
+	assert.Equal(t, int64(1), status.Dead)
+	assert.NotEmpty(t, status.LastError)
+}
+
+func TestOutboxRelay_StartStop(t *testing.T) {
+	repo := newMemoryOutboxRepo()
+	require.NoError(t, repo.Append(context.Background(), outbox.NewEvent("topic", "type", "k", []byte(`{}`))))
+	publisher := &flakyPublisher{}
+
+	relay := NewOutboxRelay(repo, publisher, OutboxRelayConfig{PollInterval: 10 * time.Millisecond})
+	require.NoError(t, relay.Start(context.Background()))
+	assert.True(t, relay.IsRunning())
+
+	assert.Eventually(t, func() bool { return publisher.count() == 1 }, time.Second, 10*time.Millisecond)
+	require.NoError(t, relay.Stop())
+	assert.False(t, relay.IsRunning())
+}
+
+type publishedMessage struct {
+	topic string
+	*messaging.Message
+}
+
+type flakyPublisher struct {
+	mu       sync.Mutex
+	failures int
+	messages []publishedMessage
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, topic string, body []byte) error {
+	return p.PublishMessage(ctx, topic, messaging.NewMessage("", body))
+}
+
+func (p *flakyPublisher) PublishMessage(_ context.Context, topic string, msg *messaging.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("nsqd unavailable")
+	}
+	p.messages = append(p.messages, publishedMessage{topic: topic, Message: msg})
+	return nil
+}
+
+func (p *flakyPublisher) Close() error { return nil }
+
+func (p *flakyPublisher) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.messages)
+}
+
+// memoryOutboxRepo 内存发件箱仓储，仅用于测试
+type memoryOutboxRepo struct {
+	mu     sync.Mutex
+	nextID uint64
+	events map[meta.ID]*outbox.Event
+	locks  map[meta.ID]time.Time
+}
+
+func newMemoryOutboxRepo() *memoryOutboxRepo {
+	return &memoryOutboxRepo{events: map[meta.ID]*outbox.Event{}, locks: map[meta.ID]time.Time{}}
+}
+
+func (r *memoryOutboxRepo) Append(_ context.Context, events ...*outbox.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range events {
+		r.nextID++
+		e.ID = meta.FromUint64(r.nextID)
+		copied := *e
+		r.events[e.ID] = &copied
+	}
+	return nil
+}
+
+func (r *memoryOutboxRepo) ClaimDue(_ context.Context, _ string, now time.Time, lease time.Duration, limit int) ([]*outbox.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var claimed []*outbox.Event
+	for id := uint64(1); id <= r.nextID && len(claimed) < limit; id++ {
+		e, ok := r.events[meta.FromUint64(id)]
+		if !ok || e.Status != outbox.StatusPending || e.NextAttemptAt.After(now) {
+			continue
+		}
+		if until, locked := r.locks[e.ID]; locked && !until.Before(now) {
+			continue
+		}
+		r.locks[e.ID] = now.Add(lease)
+		copied := *e
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (r *memoryOutboxRepo) Save(_ context.Context, e *outbox.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *e
+	r.events[e.ID] = &copied
+	delete(r.locks, e.ID)
+	return nil
+}
+
+func (r *memoryOutboxRepo) Stats(_ context.Context) (outbox.Stats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var stats outbox.Stats
+	for _, e := range r.events {
+		switch e.Status {
+		case outbox.StatusPending:
+			stats.Pending++
+			if stats.OldestPendingAt == nil || e.CreatedAt.Before(*stats.OldestPendingAt) {
+				created := e.CreatedAt
+				stats.OldestPendingAt = &created
+			}
+		case outbox.StatusDead:
+			stats.Dead++
+		}
+	}
+	return stats, nil
+}
+
+func (r *memoryOutboxRepo) PurgePublished(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *memoryOutboxRepo) only(t *testing.T) *outbox.Event {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	require.Len(t, r.events, 1)
+	for _, e := range r.events {
+		return e
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/outbox"
+)
+
+const (
+	// AuthzVersionEventType 授权版本变更事件类型
+	AuthzVersionEventType = "authz.version.changed"
+
+	// authzVersionAggregate 授权版本事件的聚合类型
+	authzVersionAggregate = "authz_policy_version"
+)
+
+// VersionOutbox 基于发件箱的版本变更登记器
+type VersionOutbox struct {
+	repo outbox.Repository
+}
+
+var _ domain.VersionOutbox = (*VersionOutbox)(nil)
+
+// NewVersionOutbox 创建版本变更登记器；repo 应绑定在当前事务上
+func NewVersionOutbox(repo outbox.Repository) domain.VersionOutbox {
+	return &VersionOutbox{repo: repo}
+}
+
+// Enqueue 把版本变更写入发件箱，消息格式与 VersionNotifier.Publish 一致
+func (o *VersionOutbox) Enqueue(ctx context.Context, version *domain.PolicyVersion) error {
+	if version == nil {
+		return nil
+	}
+	payload, err := json.Marshal(VersionChangeMessage{
+		TenantID: version.TenantID,
+		Version:  version.Version,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	event := outbox.NewEvent(
+		AuthzVersionTopic,
+		AuthzVersionEventType,
+		VersionIdempotencyKey(version.TenantID, version.Version),
+		payload,
+		outbox.WithAggregate(authzVersionAggregate, version.TenantID),
+		outbox.WithMetadata("tenant_id", version.TenantID),
+	)
+	return o.repo.Append(ctx, event)
+}
+
+// VersionIdempotencyKey 返回版本变更事件的幂等键，同一租户同一版本只会登记一次
+func VersionIdempotencyKey(tenantID string, version int64) string {
+	return fmt.Sprintf("%s:%s:%d", AuthzVersionEventType, tenantID, version)
+}
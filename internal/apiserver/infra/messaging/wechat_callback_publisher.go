@@ -0,0 +1,93 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/FangcunMount/component-base/pkg/messaging"
+	"github.com/google/uuid"
+
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+)
+
+// WechatCallbackTopic 微信消息推送转发主题
+const WechatCallbackTopic = "iam.idp.wechat.callback"
+
+// 微信回调事件类型（与消息 metadata.event_type 一致，供消费者筛选）
+const (
+	EventWechatSubscribed           = "iam.idp.wechat.subscribed"
+	EventWechatUnsubscribed         = "iam.idp.wechat.unsubscribed"
+	EventWechatAuthorizationRevoked = "iam.idp.wechat.authorization_revoked"
+	EventWechatCallback             = "iam.idp.wechat.callback"
+)
+
+// WechatCallbackMessage 转发到消息总线的回调消息体
+type WechatCallbackMessage struct {
+	EventType  string `json:"event_type"`
+	AppID      string `json:"app_id"`
+	OpenID     string `json:"openid"`
+	MsgType    string `json:"msg_type"`
+	Event      string `json:"event,omitempty"`
+	CreateTime int64  `json:"create_time"`
+	RawXML     string `json:"raw_xml"`
+}
+
+// WechatCallbackPublisher 将微信回调消息发布到消息总线
+type WechatCallbackPublisher struct {
+	publisher messaging.Publisher
+	topic     string
+}
+
+var _ domain.CallbackEventPublisher = (*WechatCallbackPublisher)(nil)
+
+// NewWechatCallbackPublisher 创建回调事件发布器
+func NewWechatCallbackPublisher(bus messaging.EventBus, topic string) *WechatCallbackPublisher {
+	if topic == "" {
+		topic = WechatCallbackTopic
+	}
+	return &WechatCallbackPublisher{
+		publisher: bus.Publisher(),
+		topic:     topic,
+	}
+}
+
+// PublishCallbackEvent 发布回调事件
+func (p *WechatCallbackPublisher) PublishCallbackEvent(ctx context.Context, appID string, msg *domain.CallbackMessage) error {
+	eventType := wechatCallbackEventType(msg)
+	payload, err := json.Marshal(WechatCallbackMessage{
+		EventType:  eventType,
+		AppID:      appID,
+		OpenID:     msg.SubjectOpenID(),
+		MsgType:    msg.MsgType,
+		Event:      msg.Event,
+		CreateTime: msg.CreateTime,
+		RawXML:     string(msg.Raw),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal wechat callback payload: %w", err)
+	}
+
+	m := messaging.NewMessage(uuid.New().String(), payload)
+	if m.Metadata == nil {
+		m.Metadata = make(map[string]string)
+	}
+	m.Metadata["event_type"] = eventType
+	if err := p.publisher.PublishMessage(ctx, p.topic, m); err != nil {
+		return fmt.Errorf("publish wechat callback: %w", err)
+	}
+	return nil
+}
+
+func wechatCallbackEventType(msg *domain.CallbackMessage) string {
+	switch {
+	case msg.IsEvent(domain.EventSubscribe):
+		return EventWechatSubscribed
+	case msg.IsEvent(domain.EventUnsubscribe):
+		return EventWechatUnsubscribed
+	case msg.IsEvent(domain.EventUserAuthorizationRevoke):
+		return EventWechatAuthorizationRevoked
+	default:
+		return EventWechatCallback
+	}
+}
@@ -116,6 +116,22 @@ func (r *AccountRepository) UpdateMeta(ctx context.Context, id meta.ID, metaData
 	return nil
 }
 
+// UpdateUserID 将账号改挂到另一用户
+func (r *AccountRepository) UpdateUserID(ctx context.Context, id meta.ID, userID meta.ID) error {
+	result := r.WithContext(ctx).
+		Model(&AccountPO{}).
+		Where("id = ?", id.Uint64()).
+		Update("user_id", userID.Uint64())
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update account user_id: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 // ==================== 查询 ====================
 
 // GetByID 根据ID查询账号
@@ -172,6 +188,22 @@ func (r *AccountRepository) GetByExternalIDAppId(ctx context.Context, externalID
 	return r.mapper.ToAccountDO(&po), nil
 }
 
+// ListByUserID 列出用户名下全部账号
+func (r *AccountRepository) ListByUserID(ctx context.Context, userID meta.ID) ([]*domain.Account, error) {
+	var pos []*AccountPO
+	if err := r.WithContext(ctx).
+		Where("user_id = ?", userID.Uint64()).
+		Order("id ASC").
+		Find(&pos).Error; err != nil {
+		return nil, fmt.Errorf("failed to list accounts by user_id: %w", err)
+	}
+	accounts := make([]*domain.Account, 0, len(pos))
+	for _, po := range pos {
+		accounts = append(accounts, r.mapper.ToAccountDO(po))
+	}
+	return accounts, nil
+}
+
 // ==================== 认证端口实现 ====================
 
 // FindAccountByUsername 根据用户名查找账户（用于密码认证）
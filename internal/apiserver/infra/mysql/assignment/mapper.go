@@ -26,6 +26,12 @@ func (m *Mapper) ToBO(po *AssignmentPO) *assignment.Assignment {
 		RoleID:      po.RoleID,
 		TenantID:    po.TenantID,
 		GrantedBy:   po.GrantedBy,
+		NotBefore:   po.NotBefore,
+		ExpiresAt:   po.ExpiresAt,
+		Status:      assignment.Status(po.Status),
+	}
+	if a.Status == "" {
+		a.Status = assignment.StatusActive
 	}
 
 	return a
@@ -43,6 +49,12 @@ func (m *Mapper) ToPO(bo *assignment.Assignment) *AssignmentPO {
 		RoleID:      bo.RoleID,
 		TenantID:    bo.TenantID,
 		GrantedBy:   bo.GrantedBy,
+		NotBefore:   bo.NotBefore,
+		ExpiresAt:   bo.ExpiresAt,
+		Status:      string(bo.Status),
+	}
+	if po.Status == "" {
+		po.Status = string(assignment.StatusActive)
 	}
 	id := meta.FromUint64(bo.ID.Uint64()) // 来自业务对象，必定有效
 	po.ID = id
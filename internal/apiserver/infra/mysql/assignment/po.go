@@ -12,12 +12,15 @@ import (
 // AssignmentPO 赋权持久化对象
 type AssignmentPO struct {
 	base.AuditFields
-	SubjectType string    `gorm:"column:subject_type;type:varchar(16);not null;index:idx_subject,priority:1"`
-	SubjectID   string    `gorm:"column:subject_id;type:varchar(64);not null;index:idx_subject,priority:2"`
-	RoleID      uint64    `gorm:"column:role_id;type:bigint unsigned;not null;index"`
-	TenantID    string    `gorm:"column:tenant_id;type:varchar(64);not null;index"`
-	GrantedBy   string    `gorm:"column:granted_by;type:varchar(64)"`
-	GrantedAt   time.Time `gorm:"column:granted_at;type:datetime"`
+	SubjectType string     `gorm:"column:subject_type;type:varchar(16);not null;index:idx_subject,priority:1"`
+	SubjectID   string     `gorm:"column:subject_id;type:varchar(64);not null;index:idx_subject,priority:2"`
+	RoleID      uint64     `gorm:"column:role_id;type:bigint unsigned;not null;index"`
+	TenantID    string     `gorm:"column:tenant_id;type:varchar(64);not null;index"`
+	GrantedBy   string     `gorm:"column:granted_by;type:varchar(64)"`
+	GrantedAt   time.Time  `gorm:"column:granted_at;type:datetime"`
+	NotBefore   *time.Time `gorm:"column:not_before;type:datetime;index:idx_status_not_before,priority:2"`
+	ExpiresAt   *time.Time `gorm:"column:expires_at;type:datetime;index:idx_status_expires_at,priority:2"`
+	Status      string     `gorm:"column:status;type:varchar(16);not null;default:active;index:idx_status_not_before,priority:1;index:idx_status_expires_at,priority:1"`
 }
 
 // TableName 指定表名
@@ -3,6 +3,7 @@ package assignment
 import (
 	"context"
 	"fmt"
+	"time"
 
 	perrors "github.com/FangcunMount/component-base/pkg/errors"
 	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
@@ -18,7 +19,10 @@ type AssignmentRepository struct {
 	db     *gorm.DB
 }
 
-var _ domain.Repository = (*AssignmentRepository)(nil)
+var (
+	_ domain.Repository         = (*AssignmentRepository)(nil)
+	_ domain.ScheduleRepository = (*AssignmentRepository)(nil)
+)
 
 // NewAssignmentRepository 创建 Assignment 仓储
 func NewAssignmentRepository(db *gorm.DB) domain.Repository {
@@ -34,6 +38,15 @@ func NewAssignmentRepository(db *gorm.DB) domain.Repository {
 	}
 }
 
+// NewScheduleRepository 创建赋权有效期调度仓储
+func NewScheduleRepository(db *gorm.DB) domain.ScheduleRepository {
+	return &AssignmentRepository{
+		BaseRepository: mysql.NewBaseRepository[*AssignmentPO](db),
+		mapper:         NewMapper(),
+		db:             db,
+	}
+}
+
 // Create 创建新分配
 func (r *AssignmentRepository) Create(ctx context.Context, a *domain.Assignment) error {
 	po := r.mapper.ToPO(a)
@@ -73,6 +86,20 @@ func (r *AssignmentRepository) ListBySubject(ctx context.Context, subjectType do
 	return bos, nil
 }
 
+// ListBySubjectAcrossTenants 列出主体在所有租户下的赋权
+func (r *AssignmentRepository) ListBySubjectAcrossTenants(ctx context.Context, subjectType domain.SubjectType, subjectID string) ([]*domain.Assignment, error) {
+	var pos []*AssignmentPO
+
+	err := r.db.WithContext(ctx).Where("subject_type = ? AND subject_id = ?", string(subjectType), subjectID).
+		Order("id ASC").
+		Find(&pos).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list domains by subject across tenants: %w", err)
+	}
+
+	return r.mapper.ToBOList(pos), nil
+}
+
 // ListByRole 根据角色列出赋权
 func (r *AssignmentRepository) ListByRole(ctx context.Context, roleID uint64, tenantID string) ([]*domain.Assignment, error) {
 	var pos []*AssignmentPO
@@ -109,3 +136,33 @@ func (r *AssignmentRepository) DeleteBySubjectAndRole(ctx context.Context, subje
 
 	return nil
 }
+
+// ListDue 列出到达生效时间的待生效赋权与到达失效时间的未过期赋权
+func (r *AssignmentRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.Assignment, error) {
+	var pos []*AssignmentPO
+
+	err := r.db.WithContext(ctx).
+		Where("(status = ? AND not_before <= ?) OR (status IN ? AND expires_at <= ?)",
+			string(domain.StatusPending), now,
+			[]string{string(domain.StatusPending), string(domain.StatusActive)}, now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&pos).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due assignments: %w", err)
+	}
+
+	return r.mapper.ToBOList(pos), nil
+}
+
+// TransitionStatus 以当前状态为条件切换赋权状态
+func (r *AssignmentRepository) TransitionStatus(ctx context.Context, id domain.AssignmentID, from, to domain.Status) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&AssignmentPO{}).
+		Where("id = ? AND status = ?", id.Uint64(), string(from)).
+		Update("status", string(to))
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to transition assignment status: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,66 @@
+package assignment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
+	testhelpers "github.com/FangcunMount/iam-contracts/internal/apiserver/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleRepository_ListDueAndTransition(t *testing.T) {
+	db := testhelpers.SetupTempSQLiteDB(t)
+	require.NoError(t, db.AutoMigrate(&AssignmentPO{}))
+
+	repo := NewAssignmentRepository(db)
+	schedule := NewScheduleRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	hourAgo, inHour := now.Add(-time.Hour), now.Add(time.Hour)
+
+	create := func(roleID uint64, status domain.Status, notBefore, expiresAt *time.Time) *domain.Assignment {
+		a := domain.NewAssignment(domain.SubjectTypeUser, "u1", roleID, "t1",
+			domain.WithValidity(notBefore, expiresAt), domain.WithStatus(status))
+		require.NoError(t, repo.Create(ctx, &a))
+		return &a
+	}
+	starting := create(1, domain.StatusPending, &hourAgo, &inHour)
+	ending := create(2, domain.StatusActive, nil, &hourAgo)
+	create(3, domain.StatusPending, &inHour, nil)
+	create(4, domain.StatusActive, nil, nil)
+	create(5, domain.StatusExpired, nil, &hourAgo)
+
+	due, err := schedule.ListDue(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.ElementsMatch(t, []domain.AssignmentID{starting.ID, ending.ID}, []domain.AssignmentID{due[0].ID, due[1].ID})
+
+	ok, err := schedule.TransitionStatus(ctx, starting.ID, domain.StatusPending, domain.StatusActive)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = schedule.TransitionStatus(ctx, starting.ID, domain.StatusPending, domain.StatusActive)
+	require.NoError(t, err)
+	assert.False(t, ok, "状态已切换时条件更新不生效")
+
+	ok, err = schedule.TransitionStatus(ctx, ending.ID, domain.StatusActive, domain.StatusExpired)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	due, err = schedule.ListDue(ctx, now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	history, err := repo.ListBySubject(ctx, domain.SubjectTypeUser, "u1", "t1")
+	require.NoError(t, err)
+	require.Len(t, history, 5)
+	statuses := make(map[uint64]domain.Status, len(history))
+	for _, a := range history {
+		statuses[a.RoleID] = a.Status
+	}
+	assert.Equal(t, domain.StatusActive, statuses[1])
+	assert.Equal(t, domain.StatusExpired, statuses[2])
+	assert.Equal(t, domain.StatusPending, statuses[3])
+}
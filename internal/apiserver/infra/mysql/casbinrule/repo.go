@@ -25,19 +25,54 @@ func (rulePO) TableName() string {
 
 type Repository struct {
 	db *gorm.DB
+	// recorder 非空时逐条写入并登记实际生效的变更（已存在的添加、不存在的删除不登记）
+	recorder *policyDomain.ChangeRecorder
 }
 
-var _ policyDomain.RuleStore = (*Repository)(nil)
+var (
+	_ policyDomain.RuleStore  = (*Repository)(nil)
+	_ policyDomain.RuleReader = (*Repository)(nil)
+)
 
 func NewRepository(db *gorm.DB) policyDomain.RuleStore {
 	return &Repository{db: db}
 }
 
+// NewRecordingRepository 创建登记规则变更的规则存储，供授权写事务记录版本变更集
+func NewRecordingRepository(db *gorm.DB, recorder *policyDomain.ChangeRecorder) policyDomain.RuleStore {
+	return &Repository{db: db, recorder: recorder}
+}
+
+// NewRuleReader 创建规则读取器
+func NewRuleReader(db *gorm.DB) policyDomain.RuleReader {
+	return &Repository{db: db}
+}
+
+// ListPolicies 列出租户域下的全部 p 规则
+func (r *Repository) ListPolicies(ctx context.Context, domain string) ([]policyDomain.PolicyRule, error) {
+	if r == nil || r.db == nil {
+		return nil, nil
+	}
+	var rows []rulePO
+	if err := r.db.WithContext(ctx).
+		Where("ptype = ? AND v1 = ?", "p", domain).
+		Order("id ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	rules := make([]policyDomain.PolicyRule, 0, len(rows))
+	for _, row := range rows {
+		rules = append(rules, policyDomain.NewPolicyRule(value(row.V0), value(row.V1), value(row.V2), value(row.V3)))
+	}
+	return rules, nil
+}
+
 func (r *Repository) AddPolicy(ctx context.Context, rules ...policyDomain.PolicyRule) error {
 	if len(rules) == 0 || r == nil || r.db == nil {
 		return nil
 	}
 	rows := make([]rulePO, 0, len(rules))
+	changes := make([]policyDomain.RuleChange, 0, len(rules))
 	for _, rule := range rules {
 		rows = append(rows, rulePO{
 			PType: "p",
@@ -46,16 +81,21 @@ func (r *Repository) AddPolicy(ctx context.Context, rules ...policyDomain.Policy
 			V2:    stringPtr(rule.Obj),
 			V3:    stringPtr(rule.Act),
 		})
+		changes = append(changes, policyDomain.PolicyChange(policyDomain.ChangeOpAdd, rule))
 	}
-	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	return r.insert(ctx, rows, changes)
 }
 
 func (r *Repository) RemovePolicy(ctx context.Context, rules ...policyDomain.PolicyRule) error {
 	for _, rule := range rules {
-		if err := r.db.WithContext(ctx).
+		result := r.db.WithContext(ctx).
 			Where("ptype = ? AND v0 = ? AND v1 = ? AND v2 = ? AND v3 = ?", "p", rule.Sub, rule.Dom, rule.Obj, rule.Act).
-			Delete(&rulePO{}).Error; err != nil {
-			return err
+			Delete(&rulePO{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			r.recorder.Record(policyDomain.PolicyChange(policyDomain.ChangeOpRemove, rule))
 		}
 	}
 	return nil
@@ -66,6 +106,7 @@ func (r *Repository) AddGroupingPolicy(ctx context.Context, rules ...policyDomai
 		return nil
 	}
 	rows := make([]rulePO, 0, len(rules))
+	changes := make([]policyDomain.RuleChange, 0, len(rules))
 	for _, rule := range rules {
 		rows = append(rows, rulePO{
 			PType: "g",
@@ -73,16 +114,38 @@ func (r *Repository) AddGroupingPolicy(ctx context.Context, rules ...policyDomai
 			V1:    stringPtr(rule.Role),
 			V2:    stringPtr(rule.Dom),
 		})
+		changes = append(changes, policyDomain.GroupingChange(policyDomain.ChangeOpAdd, rule))
 	}
-	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	return r.insert(ctx, rows, changes)
 }
 
 func (r *Repository) RemoveGroupingPolicy(ctx context.Context, rules ...policyDomain.GroupingRule) error {
 	for _, rule := range rules {
-		if err := r.db.WithContext(ctx).
+		result := r.db.WithContext(ctx).
 			Where("ptype = ? AND v0 = ? AND v1 = ? AND v2 = ?", "g", rule.Sub, rule.Role, rule.Dom).
-			Delete(&rulePO{}).Error; err != nil {
-			return err
+			Delete(&rulePO{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			r.recorder.Record(policyDomain.GroupingChange(policyDomain.ChangeOpRemove, rule))
+		}
+	}
+	return nil
+}
+
+// insert 写入规则，已存在的规则忽略；需要登记变更时逐条写入以区分是否实际插入
+func (r *Repository) insert(ctx context.Context, rows []rulePO, changes []policyDomain.RuleChange) error {
+	if r.recorder == nil {
+		return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	}
+	for i := range rows {
+		result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows[i])
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			r.recorder.Record(changes[i])
 		}
 	}
 	return nil
@@ -91,3 +154,10 @@ func (r *Repository) RemoveGroupingPolicy(ctx context.Context, rules ...policyDo
 func stringPtr(value string) *string {
 	return &value
 }
+
+func value(ptr *string) string {
+	if ptr == nil {
+		return ""
+	}
+	return *ptr
+}
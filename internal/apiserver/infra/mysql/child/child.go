@@ -6,6 +6,7 @@ import (
 	"github.com/FangcunMount/component-base/pkg/util/idutil"
 	base "github.com/FangcunMount/iam-contracts/internal/pkg/database/mysql"
 	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+	"github.com/FangcunMount/iam-contracts/pkg/tenant"
 	"gorm.io/gorm"
 )
 
@@ -13,7 +14,9 @@ import (
 // 对应数据库表结构
 type ChildPO struct {
 	base.AuditFields
-	Name string `gorm:"column:name;type:varchar(64);not null;index:idx_name_gender_birthday,priority:1;comment:儿童姓名"`
+	// TenantID 所属租户；创建时取请求上下文中的租户，缺省为默认租户
+	TenantID string `gorm:"column:tenant_id;type:varchar(64);not null;default:fangcun;index:idx_tenant_id;comment:所属租户ID"`
+	Name     string `gorm:"column:name;type:varchar(64);not null;index:idx_name_gender_birthday,priority:1;comment:儿童姓名"`
 	// IDCard 是可空的；使用指针以便将空值写入 NULL，避免唯一索引对空字符串的冲突
 	IDCard   *meta.IDCard `gorm:"column:id_card;type:varchar(20);uniqueIndex;comment:身份证号码"`
 	Gender   uint8        `gorm:"column:gender;type:tinyint;not null;default:0;index:idx_name_gender_birthday,priority:2;comment:性别"`
@@ -40,6 +43,13 @@ func (p *ChildPO) BeforeCreate(tx *gorm.DB) error {
 	p.UpdatedBy = updatedBy
 	p.DeletedBy = deletedBy
 	p.Version = base.InitialVersion
+	if p.TenantID == "" {
+		if tenantID, ok := base.TenantIDFromContext(tx.Statement.Context); ok {
+			p.TenantID = tenantID
+		} else {
+			p.TenantID = tenant.DefaultID
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,113 @@
+package credential
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+	"gorm.io/gorm"
+
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/credential"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// HistoryPO 密码历史持久化对象，与 auth_credentials 一对多。
+type HistoryPO struct {
+	ID           meta.ID   `gorm:"column:id;type:bigint unsigned;primaryKey"`
+	CredentialID meta.ID   `gorm:"column:credential_id;type:bigint unsigned;not null;index:idx_credential_created,priority:1"`
+	Material     []byte    `gorm:"column:material;type:varbinary(512);not null"`
+	Algo         string    `gorm:"column:algo;type:varchar(32);not null"`
+	CreatedAt    time.Time `gorm:"column:created_at;type:datetime;not null;index:idx_credential_created,priority:2"`
+}
+
+// TableName 指定密码历史表名。
+func (HistoryPO) TableName() string {
+	return "auth_password_history"
+}
+
+// BeforeCreate 在创建前生成 ID。
+func (p *HistoryPO) BeforeCreate(*gorm.DB) error {
+	if p.ID.IsZero() {
+		p.ID = meta.FromUint64(idutil.GetIntID())
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = time.Now()
+	}
+	return nil
+}
+
+// HistoryRepository 密码历史仓储实现。
+type HistoryRepository struct {
+	db *gorm.DB
+}
+
+var _ domain.PasswordHistoryRepository = (*HistoryRepository)(nil)
+
+// NewHistoryRepository 创建密码历史仓储。
+func NewHistoryRepository(db *gorm.DB) *HistoryRepository {
+	return &HistoryRepository{db: db}
+}
+
+// Append 追加一条历史哈希。
+func (r *HistoryRepository) Append(ctx context.Context, entry domain.PasswordHistoryEntry) error {
+	po := &HistoryPO{
+		CredentialID: entry.CredentialID,
+		Material:     cloneBytes(entry.Material),
+		Algo:         entry.Algo,
+		CreatedAt:    entry.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(po).Error; err != nil {
+		return fmt.Errorf("failed to append password history: %w", err)
+	}
+	return nil
+}
+
+// ListRecent 按时间倒序返回最近 limit 条历史。
+func (r *HistoryRepository) ListRecent(ctx context.Context, credentialID meta.ID, limit int) ([]domain.PasswordHistoryEntry, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	var pos []HistoryPO
+	if err := r.db.WithContext(ctx).
+		Where("credential_id = ?", credentialID.Uint64()).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&pos).Error; err != nil {
+		return nil, fmt.Errorf("failed to list password history: %w", err)
+	}
+	out := make([]domain.PasswordHistoryEntry, 0, len(pos))
+	for _, po := range pos {
+		out = append(out, domain.PasswordHistoryEntry{
+			CredentialID: po.CredentialID,
+			Material:     cloneBytes(po.Material),
+			Algo:         po.Algo,
+			CreatedAt:    po.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+// Prune 仅保留最近 keep 条历史。
+func (r *HistoryRepository) Prune(ctx context.Context, credentialID meta.ID, keep int) error {
+	if keep < 0 {
+		keep = 0
+	}
+	var stale []uint64
+	if err := r.db.WithContext(ctx).
+		Model(&HistoryPO{}).
+		Where("credential_id = ?", credentialID.Uint64()).
+		Order("created_at DESC, id DESC").
+		Offset(keep).
+		Limit(1000).
+		Pluck("id", &stale).Error; err != nil {
+		return fmt.Errorf("failed to list stale password history: %w", err)
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Where("id IN ?", stale).Delete(&HistoryPO{}).Error; err != nil {
+		return fmt.Errorf("failed to prune password history: %w", err)
+	}
+	return nil
+}
@@ -20,7 +20,7 @@ func (m *Mapper) ToPO(cred *domain.Credential) *PO {
 		return nil
 	}
 
-	credType := inferCredentialType(cred)
+	credType := cred.Type()
 
 	po := &PO{
 		AccountID:      cred.AccountID,
@@ -36,6 +36,8 @@ func (m *Mapper) ToPO(cred *domain.Credential) *PO {
 		LockedUntil:    copyTimePtr(cred.LockedUntil),
 		LastSuccessAt:  copyTimePtr(cred.LastSuccessAt),
 		LastFailureAt:  copyTimePtr(cred.LastFailureAt),
+
+		PasswordChangedAt: copyTimePtr(cred.PasswordChangedAt),
 	}
 
 	if !cred.ID.IsZero() {
@@ -65,23 +67,9 @@ func (m *Mapper) ToDO(po *PO) *domain.Credential {
 		LockedUntil:    copyTimePtr(po.LockedUntil),
 		LastSuccessAt:  copyTimePtr(po.LastSuccessAt),
 		LastFailureAt:  copyTimePtr(po.LastFailureAt),
-	}
-}
 
-func inferCredentialType(cred *domain.Credential) domain.CredentialType {
-	if cred.IDP == nil && len(cred.Material) > 0 && cred.Algo != nil {
-		return domain.CredPassword
-	}
-	if cred.IDP != nil && *cred.IDP == "phone" {
-		return domain.CredPhoneOTP
-	}
-	if cred.IDP != nil && *cred.IDP == "wechat" {
-		return domain.CredOAuthWxMinip
-	}
-	if cred.IDP != nil && *cred.IDP == "wecom" {
-		return domain.CredOAuthWecom
+		PasswordChangedAt: copyTimePtr(po.PasswordChangedAt),
 	}
-	return domain.CredPassword
 }
 
 func cloneBytes(src []byte) []byte {
@@ -31,6 +31,9 @@ type PO struct {
 	LockedUntil    *time.Time `gorm:"column:locked_until;type:datetime"`
 	LastSuccessAt  *time.Time `gorm:"column:last_success_at;type:datetime"`
 	LastFailureAt  *time.Time `gorm:"column:last_failure_at;type:datetime"`
+
+	// 密码设置时间（password 专用，用于最长有效期）
+	PasswordChangedAt *time.Time `gorm:"column:password_changed_at;type:datetime"`
 }
 
 // TableName 指定凭据表名。
@@ -110,6 +110,22 @@ func (r *Repository) UpdateLockedUntil(ctx context.Context, id meta.ID, lockedUn
 	return nil
 }
 
+// UpdatePasswordChangedAt 更新密码设置时间。
+func (r *Repository) UpdatePasswordChangedAt(ctx context.Context, id meta.ID, changedAt time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&PO{}).
+		Where("id = ?", id.Uint64()).
+		Update("password_changed_at", changedAt)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update credential password_changed_at: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 // UpdateLastSuccessAt 更新最近成功时间。
 func (r *Repository) UpdateLastSuccessAt(ctx context.Context, id meta.ID, lastSuccessAt time.Time) error {
 	result := r.db.WithContext(ctx).
@@ -288,7 +304,7 @@ func (r *Repository) FindPhoneOTPCredential(ctx context.Context, phoneE164 strin
 }
 
 // FindOAuthCredential 根据身份提供商标识查找OAuth凭据绑定
-// idpType: "wx_minip" | "wecom" | ...
+// idpType: "oauth_wx_minip" | "oauth_wx_mp" | "oauth_wecom" | ...
 // idpIdentifier: OpenID/UnionID/UserID
 // 返回：账户ID、用户ID、凭据ID
 func (r *Repository) FindOAuthCredential(ctx context.Context, idpType, appID, idpIdentifier string) (accountID, userID, credentialID meta.ID, err error) {
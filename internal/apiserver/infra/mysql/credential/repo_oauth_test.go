@@ -0,0 +1,64 @@
+package credential
+
+import (
+	"context"
+	"testing"
+
+	testutil "github.com/FangcunMount/iam-contracts/internal/apiserver/application/uc/testutil"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/credential"
+	accountInfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/mysql/account"
+	m "github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+	"github.com/stretchr/testify/require"
+)
+
+type enabledAccountRepoStub struct{}
+
+func (enabledAccountRepoStub) FindAccountByUsername(context.Context, m.ID, string) (*authentication.UsernameLoginLookup, error) {
+	return nil, nil
+}
+
+func (enabledAccountRepoStub) GetAccountStatus(context.Context, m.ID) (bool, bool, error) {
+	return true, false, nil
+}
+
+type wecomIDPStub struct {
+	authentication.IdentityProvider
+	userID string
+}
+
+func (s wecomIDPStub) ExchangeWecomCode(context.Context, string, string, string, string) (string, string, error) {
+	return "", s.userID, nil
+}
+
+// 绑定企业微信写入的凭据类型必须能被企业微信登录策略查到
+func TestCredentialRepository_WecomBindingCanLogin(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&PO{}, &accountInfra.AccountPO{}))
+	ctx := context.Background()
+
+	acct := &accountInfra.AccountPO{UserID: m.FromUint64(42), Type: "wecom", ExternalID: "zhangsan"}
+	require.NoError(t, db.Create(acct).Error)
+
+	repo := NewRepository(db)
+	cred, err := domain.NewIssuer(nil).IssueWecom(ctx, domain.IssueOAuthRequest{
+		AccountID:     acct.ID,
+		IDPIdentifier: "zhangsan",
+		AppID:         "ww-corp",
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, cred))
+
+	auth := authentication.NewAuthenticater(repo, enabledAccountRepoStub{}, nil, nil, wecomIDPStub{userID: "zhangsan"}, nil)
+	decision, err := auth.Authenticate(ctx, authentication.AuthWecom, authentication.AuthInput{
+		WecomCorpID:     "ww-corp",
+		WecomAgentID:    "1000002",
+		WecomCorpSecret: "secret",
+		WecomCode:       "code",
+	})
+	require.NoError(t, err)
+	require.True(t, decision.OK)
+	require.Equal(t, acct.ID, decision.Principal.AccountID)
+	require.Equal(t, m.FromUint64(42), decision.Principal.UserID)
+	require.Equal(t, cred.ID, decision.CredentialID)
+}
@@ -25,3 +25,18 @@ type KeyPO struct {
 func (KeyPO) TableName() string {
 	return "jwks_keys"
 }
+
+// PrivateKeyPO 加密私钥持久化对象，对应 jwks_private_keys 表
+// ciphertext 为 PKCS#8 DER 经主密钥 AES-GCM 加密后的密文，以 kid 作为附加认证数据
+type PrivateKeyPO struct {
+	ID         uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	Kid        string    `gorm:"column:kid;type:varchar(64);not null;uniqueIndex:uk_private_kid"`
+	Alg        string    `gorm:"column:alg;type:varchar(32);not null"`
+	Ciphertext []byte    `gorm:"column:ciphertext;type:varbinary(8192);not null"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (PrivateKeyPO) TableName() string {
+	return "jwks_private_keys"
+}
@@ -0,0 +1,189 @@
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	stderrors "errors"
+	"strings"
+	"sync"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/jwks"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/database/mysql"
+	"gorm.io/gorm"
+)
+
+// KeyCipher 私钥加解密器（主密钥由调用方注入，如 AES-256-GCM）
+// aad 为附加认证数据，存储以 kid 作为 aad 把密文绑定到所属记录
+type KeyCipher interface {
+	EncryptWithAAD(ctx context.Context, plaintext, aad []byte) ([]byte, error)
+	DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) ([]byte, error)
+}
+
+// PrivateKeyStore 加密私钥存储
+// 私钥以 PKCS#8 编码后经主密钥加密写入 jwks_private_keys，多副本共享数据库即可共享签名密钥
+// 解密后的私钥按 kid 缓存在进程内，避免每次签发都查库、解密与解析；保存或删除私钥时失效对应缓存
+type PrivateKeyStore struct {
+	db     *gorm.DB
+	cipher KeyCipher
+
+	mu   sync.RWMutex
+	keys map[string]cachedPrivateKey
+}
+
+// cachedPrivateKey 已解密的私钥及其登记算法
+type cachedPrivateKey struct {
+	key any
+	alg string
+}
+
+var (
+	_ domain.PrivateKeyStorage  = (*PrivateKeyStore)(nil)
+	_ domain.PrivateKeyResolver = (*PrivateKeyStore)(nil)
+	_ domain.SignerResolver     = (*PrivateKeyStore)(nil)
+)
+
+// NewPrivateKeyStore 创建加密私钥存储
+func NewPrivateKeyStore(db *gorm.DB, cipher KeyCipher) *PrivateKeyStore {
+	return &PrivateKeyStore{db: db, cipher: cipher, keys: make(map[string]cachedPrivateKey)}
+}
+
+// SavePrivateKey 加密并保存私钥
+func (s *PrivateKeyStore) SavePrivateKey(ctx context.Context, kid string, privateKey any, alg string) error {
+	if err := checkKeyAlg(privateKey, alg); err != nil {
+		return err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return errors.WithCode(code.ErrUnknown, "failed to marshal private key to PKCS#8: %v", err)
+	}
+	ciphertext, err := s.cipher.EncryptWithAAD(ctx, der, []byte(kid))
+	if err != nil {
+		return errors.WithCode(code.ErrUnknown, "failed to encrypt private key %s: %v", kid, err)
+	}
+
+	po := &PrivateKeyPO{Kid: kid, Alg: alg, Ciphertext: ciphertext}
+	if err := s.db.WithContext(ctx).Create(po).Error; err != nil {
+		if mysql.IsDuplicateError(err) {
+			return errors.WithCode(code.ErrKeyAlreadyExists, "private key %s already exists", kid)
+		}
+		return errors.WithCode(code.ErrUnknown, "failed to save private key %s: %v", kid, err)
+	}
+	s.invalidate(kid)
+	return nil
+}
+
+// DeletePrivateKey 物理删除私钥密文
+func (s *PrivateKeyStore) DeletePrivateKey(ctx context.Context, kid string) error {
+	s.invalidate(kid)
+	result := s.db.WithContext(ctx).Where("kid = ?", kid).Delete(&PrivateKeyPO{})
+	if result.Error != nil {
+		return errors.WithCode(code.ErrUnknown, "failed to delete private key %s: %v", kid, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.WithCode(code.ErrKeyNotFound, "private key not found: %s", kid)
+	}
+	return nil
+}
+
+// KeyExists 检查私钥是否存在
+func (s *PrivateKeyStore) KeyExists(ctx context.Context, kid string) (bool, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&PrivateKeyPO{}).Where("kid = ?", kid).Count(&count).Error; err != nil {
+		return false, errors.WithCode(code.ErrUnknown, "failed to check key existence: %v", err)
+	}
+	return count > 0, nil
+}
+
+// ResolveSigningKey 读取并解密私钥，命中缓存时不再访问数据库
+func (s *PrivateKeyStore) ResolveSigningKey(ctx context.Context, kid, alg string) (any, error) {
+	cached, err := s.load(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+	if alg == "" {
+		alg = cached.alg
+	}
+	if err := checkKeyAlg(cached.key, alg); err != nil {
+		return nil, err
+	}
+	return cached.key, nil
+}
+
+// load 返回 kid 对应的已解密私钥，未缓存时从数据库读取并解密
+func (s *PrivateKeyStore) load(ctx context.Context, kid string) (cachedPrivateKey, error) {
+	s.mu.RLock()
+	cached, ok := s.keys[kid]
+	s.mu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	var po PrivateKeyPO
+	if err := s.db.WithContext(ctx).Where("kid = ?", kid).First(&po).Error; err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return cachedPrivateKey{}, errors.WithCode(code.ErrKeyNotFound, "private key not found: %s", kid)
+		}
+		return cachedPrivateKey{}, errors.WithCode(code.ErrUnknown, "failed to load private key %s: %v", kid, err)
+	}
+
+	// 密文以 kid 为 AAD 加密，被挪到其他 kid 名下时解密失败
+	der, err := s.cipher.DecryptWithAAD(ctx, po.Ciphertext, []byte(po.Kid))
+	if err != nil {
+		return cachedPrivateKey{}, errors.WithCode(code.ErrUnknown, "failed to decrypt private key %s: %v", kid, err)
+	}
+	privateKey, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return cachedPrivateKey{}, errors.WithCode(code.ErrInvalidJWK, "failed to parse private key %s: %v", kid, err)
+	}
+
+	cached = cachedPrivateKey{key: privateKey, alg: po.Alg}
+	s.mu.Lock()
+	s.keys[kid] = cached
+	s.mu.Unlock()
+	return cached, nil
+}
+
+// invalidate 失效 kid 的缓存私钥
+func (s *PrivateKeyStore) invalidate(kid string) {
+	s.mu.Lock()
+	delete(s.keys, kid)
+	s.mu.Unlock()
+}
+
+// ResolveSigner 解析签名器
+func (s *PrivateKeyStore) ResolveSigner(ctx context.Context, kid, alg string) (crypto.Signer, error) {
+	privateKey, err := s.ResolveSigningKey(ctx, kid, alg)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.WithCode(code.ErrUnsupportedKty, "private key %s cannot sign", kid)
+	}
+	return signer, nil
+}
+
+// checkKeyAlg 校验私钥类型与算法族一致
+func checkKeyAlg(privateKey any, alg string) error {
+	var ok bool
+	switch {
+	case strings.HasPrefix(alg, "RS"), strings.HasPrefix(alg, "PS"):
+		_, ok = privateKey.(*rsa.PrivateKey)
+	case strings.HasPrefix(alg, "ES"):
+		_, ok = privateKey.(*ecdsa.PrivateKey)
+	case alg == "EdDSA":
+		_, ok = privateKey.(ed25519.PrivateKey)
+	default:
+		return errors.WithCode(code.ErrUnsupportedKty, "unsupported algorithm: %s", alg)
+	}
+	if !ok {
+		return errors.WithCode(code.ErrInvalidJWK, "private key %T does not match algorithm %s", privateKey, alg)
+	}
+	return nil
+}
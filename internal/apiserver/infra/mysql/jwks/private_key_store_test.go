@@ -0,0 +1,156 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"testing"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	infracrypto "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/crypto"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupPrivateKeyStore(t *testing.T) (*PrivateKeyStore, *gorm.DB) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&PrivateKeyPO{}))
+
+	vault, err := infracrypto.NewAEADVault([]byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+	return NewPrivateKeyStore(db, vault), db
+}
+
+func TestPrivateKeyStore_SaveResolveRoundTrip(t *testing.T) {
+	store, db := setupPrivateKeyStore(t)
+	ctx := context.Background()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	require.NoError(t, store.SavePrivateKey(ctx, "kid-1", key, "RS256"))
+
+	exists, err := store.KeyExists(ctx, "kid-1")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	// 落库的是密文，不能出现 PKCS#8 明文
+	var po PrivateKeyPO
+	require.NoError(t, db.Where("kid = ?", "kid-1").First(&po).Error)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	require.NotContains(t, string(po.Ciphertext), string(der))
+
+	resolved, err := store.ResolveSigningKey(ctx, "kid-1", "RS256")
+	require.NoError(t, err)
+	require.True(t, key.Equal(resolved))
+
+	signer, err := store.ResolveSigner(ctx, "kid-1", "RS256")
+	require.NoError(t, err)
+	require.True(t, key.PublicKey.Equal(signer.Public()))
+}
+
+func TestPrivateKeyStore_RejectsMismatchedAlg(t *testing.T) {
+	store, _ := setupPrivateKeyStore(t)
+	ctx := context.Background()
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	err = store.SavePrivateKey(ctx, "kid-ec", ecKey, "RS256")
+	require.Error(t, err)
+	require.True(t, perrors.IsCode(err, code.ErrInvalidJWK))
+
+	require.NoError(t, store.SavePrivateKey(ctx, "kid-ec", ecKey, "ES256"))
+	_, err = store.ResolveSigningKey(ctx, "kid-ec", "RS256")
+	require.True(t, perrors.IsCode(err, code.ErrInvalidJWK))
+}
+
+func TestPrivateKeyStore_DeleteAndNotFound(t *testing.T) {
+	store, _ := setupPrivateKeyStore(t)
+	ctx := context.Background()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	require.NoError(t, store.SavePrivateKey(ctx, "kid-del", key, "RS256"))
+
+	err = store.SavePrivateKey(ctx, "kid-del", key, "RS256")
+	require.True(t, perrors.IsCode(err, code.ErrKeyAlreadyExists))
+
+	require.NoError(t, store.DeletePrivateKey(ctx, "kid-del"))
+	exists, err := store.KeyExists(ctx, "kid-del")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	_, err = store.ResolveSigningKey(ctx, "kid-del", "RS256")
+	require.True(t, perrors.IsCode(err, code.ErrKeyNotFound))
+	require.True(t, perrors.IsCode(store.DeletePrivateKey(ctx, "kid-del"), code.ErrKeyNotFound))
+}
+
+// countingCipher 统计解密次数，用于确认缓存命中
+type countingCipher struct {
+	KeyCipher
+	decrypts int
+}
+
+func (c *countingCipher) DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	c.decrypts++
+	return c.KeyCipher.DecryptWithAAD(ctx, ciphertext, aad)
+}
+
+func TestPrivateKeyStore_CiphertextBoundToKid(t *testing.T) {
+	store, db := setupPrivateKeyStore(t)
+	ctx := context.Background()
+
+	keyA, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyB, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	require.NoError(t, store.SavePrivateKey(ctx, "kid-a", keyA, "RS256"))
+	require.NoError(t, store.SavePrivateKey(ctx, "kid-b", keyB, "RS256"))
+
+	// 把 kid-a 的密文挪到 kid-b 名下，解密必须失败而不是签出 kid-a 的签名
+	var poA PrivateKeyPO
+	require.NoError(t, db.Where("kid = ?", "kid-a").First(&poA).Error)
+	require.NoError(t, db.Model(&PrivateKeyPO{}).Where("kid = ?", "kid-b").Update("ciphertext", poA.Ciphertext).Error)
+
+	_, err = store.ResolveSigningKey(ctx, "kid-b", "RS256")
+	require.Error(t, err)
+}
+
+func TestPrivateKeyStore_CachesUntilDeleted(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&PrivateKeyPO{}))
+	vault, err := infracrypto.NewAEADVault([]byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+	cipher := &countingCipher{KeyCipher: vault}
+	store := NewPrivateKeyStore(db, cipher)
+	ctx := context.Background()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	require.NoError(t, store.SavePrivateKey(ctx, "kid-1", key, "RS256"))
+
+	for i := 0; i < 3; i++ {
+		signer, err := store.ResolveSigner(ctx, "kid-1", "RS256")
+		require.NoError(t, err)
+		require.True(t, key.PublicKey.Equal(signer.Public()))
+	}
+	require.Equal(t, 1, cipher.decrypts)
+
+	// 缓存命中同样校验算法
+	_, err = store.ResolveSigningKey(ctx, "kid-1", "ES256")
+	require.True(t, perrors.IsCode(err, code.ErrInvalidJWK))
+
+	require.NoError(t, store.DeletePrivateKey(ctx, "kid-1"))
+	_, err = store.ResolveSigner(ctx, "kid-1", "RS256")
+	require.True(t, perrors.IsCode(err, code.ErrKeyNotFound))
+}
@@ -0,0 +1,138 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/oidcprovider"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	dbmysql "github.com/FangcunMount/iam-contracts/internal/pkg/database/mysql"
+	"gorm.io/gorm"
+)
+
+// oidcProviderRepository 上游 OIDC 身份提供商仓储实现
+type oidcProviderRepository struct {
+	dbmysql.BaseRepository[*OIDCProviderPO]
+	dbConn *gorm.DB
+}
+
+// 确保实现了接口
+var _ oidcprovider.Repository = (*oidcProviderRepository)(nil)
+
+// NewOIDCProviderRepository 创建上游 OIDC 身份提供商仓储实例
+func NewOIDCProviderRepository(db *gorm.DB) oidcprovider.Repository {
+	base := dbmysql.NewBaseRepository[*OIDCProviderPO](db)
+	base.SetErrorTranslator(dbmysql.NewDuplicateToTranslator(func(e error) error {
+		return perrors.WithCode(code.ErrOIDCProviderAlreadyExists, "oidc provider already exists")
+	}))
+
+	return &oidcProviderRepository{dbConn: db, BaseRepository: base}
+}
+
+// Create 创建上游 OIDC 身份提供商
+func (r *oidcProviderRepository) Create(ctx context.Context, provider *oidcprovider.OIDCProvider) error {
+	if provider == nil {
+		return errors.New("provider cannot be nil")
+	}
+
+	po := &OIDCProviderPO{}
+	if err := po.FromDomain(provider); err != nil {
+		return fmt.Errorf("failed to encode oidc provider: %w", err)
+	}
+
+	return r.CreateAndSync(ctx, po, func(updated *OIDCProviderPO) {
+		provider.ID = updated.ID
+	})
+}
+
+// GetByID 根据 ID 查询上游 OIDC 身份提供商
+func (r *oidcProviderRepository) GetByID(ctx context.Context, id idutil.ID) (*oidcprovider.OIDCProvider, error) {
+	po, err := r.FindByID(ctx, id.Uint64())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get oidc provider by id: %w", err)
+	}
+	if po == nil {
+		return nil, nil
+	}
+	return po.ToDomain(), nil
+}
+
+// GetBySlug 根据 Slug 查询上游 OIDC 身份提供商
+func (r *oidcProviderRepository) GetBySlug(ctx context.Context, slug string) (*oidcprovider.OIDCProvider, error) {
+	if slug == "" {
+		return nil, errors.New("slug cannot be empty")
+	}
+
+	var po OIDCProviderPO
+	if err := r.WithContext(ctx).Where("slug = ?", slug).First(&po).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get oidc provider by slug: %w", err)
+	}
+
+	return po.ToDomain(), nil
+}
+
+// List 查询上游 OIDC 身份提供商列表。
+func (r *oidcProviderRepository) List(ctx context.Context, filter oidcprovider.ListFilter) ([]*oidcprovider.OIDCProvider, error) {
+	query := r.dbConn.WithContext(ctx).Model(&OIDCProviderPO{})
+	if filter.Status != nil {
+		query = query.Where("status = ?", string(*filter.Status))
+	}
+
+	var pos []*OIDCProviderPO
+	if err := query.Order("slug ASC").Find(&pos).Error; err != nil {
+		return nil, fmt.Errorf("failed to list oidc providers: %w", err)
+	}
+
+	providers := make([]*oidcprovider.OIDCProvider, 0, len(pos))
+	for _, po := range pos {
+		providers = append(providers, po.ToDomain())
+	}
+	return providers, nil
+}
+
+// Update 更新上游 OIDC 身份提供商
+func (r *oidcProviderRepository) Update(ctx context.Context, provider *oidcprovider.OIDCProvider) error {
+	if provider == nil {
+		return errors.New("provider cannot be nil")
+	}
+
+	po := &OIDCProviderPO{}
+	if err := po.FromDomain(provider); err != nil {
+		return fmt.Errorf("failed to encode oidc provider: %w", err)
+	}
+
+	result := r.dbConn.WithContext(ctx).Model(&OIDCProviderPO{}).Where("id = ?", po.ID).Updates(map[string]interface{}{
+		"name":                     po.Name,
+		"issuer":                   po.Issuer,
+		"client_id":                po.ClientID,
+		"scopes":                   po.Scopes,
+		"claim_mapping":            po.ClaimMapping,
+		"role_mappings":            po.RoleMappings,
+		"auto_provision":           po.AutoProvision,
+		"tenant_id":                po.TenantID,
+		"status":                   po.Status,
+		"client_secret_cipher":     po.ClientSecretCipher,
+		"client_secret_fp":         po.ClientSecretFP,
+		"client_secret_version":    po.ClientSecretVersion,
+		"client_secret_rotated_at": po.ClientSecretRotatedAt,
+	})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update oidc provider: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("oidc provider not found")
+	}
+
+	return nil
+}
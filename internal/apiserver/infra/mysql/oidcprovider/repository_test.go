@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/oidcprovider"
+	testhelpers "github.com/FangcunMount/iam-contracts/internal/apiserver/testhelpers"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOIDCProviderRepository_RoundTrip(t *testing.T) {
+	db := testhelpers.SetupTempSQLiteDB(t)
+	require.NoError(t, db.AutoMigrate(&OIDCProviderPO{}))
+	repo := NewOIDCProviderRepository(db)
+	ctx := context.Background()
+
+	newProvider := func(slug string) *oidcprovider.OIDCProvider {
+		return oidcprovider.NewOIDCProvider(slug, "https://sso.example.com", "iam",
+			oidcprovider.WithOIDCProviderID(meta.FromUint64(idutil.GetIntID())),
+			oidcprovider.WithOIDCProviderName("SSO "+slug),
+			oidcprovider.WithOIDCProviderStatus(oidcprovider.StatusEnabled),
+		)
+	}
+
+	p := newProvider("corp-sso")
+	p.Scopes = []string{"openid", "groups"}
+	p.Claims = oidcprovider.ClaimMapping{Username: "upn"}
+	p.RoleMappings = []oidcprovider.RoleMappingRule{{Claim: "groups", Value: "iam-admins", Role: "admin"}}
+	p.AutoProvision = true
+	p.TenantID = meta.FromUint64(7)
+	p.Cred = &oidcprovider.ClientSecret{SecretCipher: []byte("cipher"), Fingerprint: "fp", Version: 1}
+	require.NoError(t, repo.Create(ctx, p))
+	require.NoError(t, repo.Create(ctx, newProvider("partner-sso")))
+
+	// 重复 slug 映射为业务错误码
+	err := repo.Create(ctx, newProvider("corp-sso"))
+	require.Error(t, err)
+	mapped := false
+	for ue := err; ue != nil; ue = errors.Unwrap(ue) {
+		if perrors.IsCode(ue, code.ErrOIDCProviderAlreadyExists) {
+			mapped = true
+			break
+		}
+	}
+	require.True(t, mapped, "duplicate should map to ErrOIDCProviderAlreadyExists, got %v", err)
+
+	got, err := repo.GetBySlug(ctx, "corp-sso")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, []string{"openid", "groups"}, got.Scopes)
+	require.Equal(t, "upn", got.Claims.Username)
+	require.Equal(t, p.RoleMappings, got.RoleMappings)
+	require.True(t, got.AutoProvision)
+	require.Equal(t, uint64(7), got.TenantID.Uint64())
+	require.Equal(t, []byte("cipher"), got.Cred.SecretCipher)
+
+	missing, err := repo.GetBySlug(ctx, "unknown")
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	got.Disable()
+	got.RoleMappings = nil
+	got.Cred.Version = 2
+	require.NoError(t, repo.Update(ctx, got))
+
+	enabled := oidcprovider.StatusEnabled
+	providers, err := repo.List(ctx, oidcprovider.ListFilter{Status: &enabled})
+	require.NoError(t, err)
+	require.Len(t, providers, 1)
+	require.Equal(t, "partner-sso", providers[0].Slug)
+
+	reloaded, err := repo.GetByID(ctx, idutil.NewID(got.ID.Uint64()))
+	require.NoError(t, err)
+	require.True(t, reloaded.IsDisabled())
+	require.Empty(t, reloaded.RoleMappings)
+	require.Equal(t, 2, reloaded.Cred.Version)
+}
@@ -0,0 +1,82 @@
+package outbox
+
+import (
+	"encoding/json"
+
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/outbox"
+)
+
+// Mapper 负责 Domain Entity 和 PO 之间的转换
+type Mapper struct{}
+
+// NewMapper 创建 Mapper 实例
+func NewMapper() *Mapper {
+	return &Mapper{}
+}
+
+// ToPO 将领域事件转换为 PO
+func (m *Mapper) ToPO(e *domain.Event) (*EventPO, error) {
+	if e == nil {
+		return nil, nil
+	}
+	var metadata []byte
+	if len(e.Metadata) > 0 {
+		raw, err := json.Marshal(e.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		metadata = raw
+	}
+	po := &EventPO{
+		ID:             e.ID,
+		Topic:          e.Topic,
+		EventType:      e.EventType,
+		AggregateType:  e.AggregateType,
+		AggregateID:    e.AggregateID,
+		IdempotencyKey: e.IdempotencyKey,
+		Payload:        e.Payload,
+		Metadata:       metadata,
+		Status:         e.Status.String(),
+		Attempts:       e.Attempts,
+		NextAttemptAt:  e.NextAttemptAt,
+		PublishedAt:    e.PublishedAt,
+		CreatedAt:      e.CreatedAt,
+	}
+	if e.LastError != "" {
+		lastErr := e.LastError
+		po.LastError = &lastErr
+	}
+	return po, nil
+}
+
+// ToBO 将 PO 转换为领域事件
+func (m *Mapper) ToBO(po *EventPO) (*domain.Event, error) {
+	if po == nil {
+		return nil, nil
+	}
+	metadata := map[string]string{}
+	if len(po.Metadata) > 0 {
+		if err := json.Unmarshal(po.Metadata, &metadata); err != nil {
+			return nil, err
+		}
+	}
+	e := &domain.Event{
+		ID:             po.ID,
+		Topic:          po.Topic,
+		EventType:      po.EventType,
+		AggregateType:  po.AggregateType,
+		AggregateID:    po.AggregateID,
+		IdempotencyKey: po.IdempotencyKey,
+		Payload:        po.Payload,
+		Metadata:       metadata,
+		Status:         domain.Status(po.Status),
+		Attempts:       po.Attempts,
+		NextAttemptAt:  po.NextAttemptAt,
+		CreatedAt:      po.CreatedAt,
+		PublishedAt:    po.PublishedAt,
+	}
+	if po.LastError != nil {
+		e.LastError = *po.LastError
+	}
+	return e, nil
+}
@@ -0,0 +1,47 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+	"gorm.io/gorm"
+)
+
+// EventPO 发件箱事件持久化对象，对应 outbox_events 表
+//
+// 发件箱记录只追加、按状态流转，不需要软删除和审计字段。
+type EventPO struct {
+	ID             meta.ID    `gorm:"primaryKey;type:bigint unsigned"`
+	Topic          string     `gorm:"column:topic;type:varchar(128);not null"`
+	EventType      string     `gorm:"column:event_type;type:varchar(128);not null"`
+	AggregateType  string     `gorm:"column:aggregate_type;type:varchar(64);not null;default:''"`
+	AggregateID    string     `gorm:"column:aggregate_id;type:varchar(128);not null;default:''"`
+	IdempotencyKey string     `gorm:"column:idempotency_key;type:varchar(191);not null;uniqueIndex:uk_idempotency_key"`
+	Payload        []byte     `gorm:"column:payload;type:blob;not null"`
+	Metadata       []byte     `gorm:"column:metadata;type:json"`
+	Status         string     `gorm:"column:status;type:varchar(16);not null;default:pending;index:idx_status_next_attempt,priority:1"`
+	Attempts       int        `gorm:"column:attempts;type:int unsigned;not null;default:0"`
+	NextAttemptAt  time.Time  `gorm:"column:next_attempt_at;type:datetime;not null;index:idx_status_next_attempt,priority:2"`
+	LastError      *string    `gorm:"column:last_error;type:varchar(1024)"`
+	LockedBy       *string    `gorm:"column:locked_by;type:varchar(128);index:idx_locked_by"`
+	LockedUntil    *time.Time `gorm:"column:locked_until;type:datetime"`
+	PublishedAt    *time.Time `gorm:"column:published_at;type:datetime;index:idx_published_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at;type:datetime;not null"`
+}
+
+// TableName 指定表名
+func (EventPO) TableName() string {
+	return "outbox_events"
+}
+
+// BeforeCreate 在创建前设置 ID
+func (p *EventPO) BeforeCreate(tx *gorm.DB) error {
+	if p.ID.IsZero() {
+		p.ID = meta.FromUint64(idutil.GetIntID())
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/outbox"
+)
+
+// maxLastErrorLen last_error 列长度上限
+const maxLastErrorLen = 1024
+
+// Repository 发件箱 MySQL 仓储实现
+type Repository struct {
+	db     *gorm.DB
+	mapper *Mapper
+}
+
+var _ domain.Repository = (*Repository)(nil)
+
+// NewRepository 创建发件箱仓储；传入事务句柄即可让写入参与业务事务
+func NewRepository(db *gorm.DB) domain.Repository {
+	return &Repository{db: db, mapper: NewMapper()}
+}
+
+// Append 追加事件
+func (r *Repository) Append(ctx context.Context, events ...*domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	rows := make([]*EventPO, 0, len(events))
+	for _, e := range events {
+		po, err := r.mapper.ToPO(e)
+		if err != nil {
+			return fmt.Errorf("map outbox event: %w", err)
+		}
+		rows = append(rows, po)
+	}
+	if err := r.db.WithContext(ctx).Create(&rows).Error; err != nil {
+		return err
+	}
+	for i, po := range rows {
+		events[i].ID = po.ID
+	}
+	return nil
+}
+
+// ClaimDue 认领到期事件
+//
+// 先挑选候选 ID，再以“认领已过期”为条件更新 locked_by，最后按本次认领令牌回查；
+// 不依赖 SELECT ... FOR UPDATE SKIP LOCKED，多实例并发时由条件更新保证同一事件只被一个实例拿到。
+func (r *Repository) ClaimDue(ctx context.Context, owner string, now time.Time, lease time.Duration, limit int) ([]*domain.Event, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	db := r.db.WithContext(ctx)
+
+	var ids []uint64
+	if err := db.Model(&EventPO{}).
+		Where("status = ? AND next_attempt_at <= ?", domain.StatusPending.String(), now).
+		Where("locked_until IS NULL OR locked_until < ?", now).
+		Order("next_attempt_at, id").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	token := owner + "/" + uuid.NewString()
+	until := now.Add(lease)
+	if err := db.Model(&EventPO{}).
+		Where("id IN ?", ids).
+		Where("status = ?", domain.StatusPending.String()).
+		Where("locked_until IS NULL OR locked_until < ?", now).
+		Updates(map[string]interface{}{"locked_by": token, "locked_until": until}).Error; err != nil {
+		return nil, err
+	}
+
+	var pos []*EventPO
+	if err := db.Where("locked_by = ?", token).Order("next_attempt_at, id").Find(&pos).Error; err != nil {
+		return nil, err
+	}
+	events := make([]*domain.Event, 0, len(pos))
+	for _, po := range pos {
+		e, err := r.mapper.ToBO(po)
+		if err != nil {
+			return nil, fmt.Errorf("map outbox event %s: %w", po.ID, err)
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// Save 保存投递结果并释放认领
+func (r *Repository) Save(ctx context.Context, e *domain.Event) error {
+	if e == nil {
+		return nil
+	}
+	var lastErr interface{}
+	if e.LastError != "" {
+		msg := e.LastError
+		if len(msg) > maxLastErrorLen {
+			msg = msg[:maxLastErrorLen]
+		}
+		lastErr = msg
+	}
+	return r.db.WithContext(ctx).Model(&EventPO{}).
+		Where("id = ?", e.ID).
+		Updates(map[string]interface{}{
+			"status":          e.Status.String(),
+			"attempts":        e.Attempts,
+			"next_attempt_at": e.NextAttemptAt,
+			"last_error":      lastErr,
+			"published_at":    e.PublishedAt,
+			"locked_by":       nil,
+			"locked_until":    nil,
+		}).Error
+}
+
+// Stats 统计积压情况
+func (r *Repository) Stats(ctx context.Context) (domain.Stats, error) {
+	db := r.db.WithContext(ctx)
+	var stats domain.Stats
+	if err := db.Model(&EventPO{}).Where("status = ?", domain.StatusPending.String()).Count(&stats.Pending).Error; err != nil {
+		return stats, err
+	}
+	if err := db.Model(&EventPO{}).Where("status = ?", domain.StatusDead.String()).Count(&stats.Dead).Error; err != nil {
+		return stats, err
+	}
+	if stats.Pending > 0 {
+		var oldest EventPO
+		if err := db.Select("created_at").
+			Where("status = ?", domain.StatusPending.String()).
+			Order("created_at").
+			Limit(1).
+			Find(&oldest).Error; err != nil {
+			return stats, err
+		}
+		if !oldest.CreatedAt.IsZero() {
+			stats.OldestPendingAt = &oldest.CreatedAt
+		}
+	}
+	return stats, nil
+}
+
+// PurgePublished 清理早于 before 的已投递事件
+func (r *Repository) PurgePublished(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND published_at < ?", domain.StatusPublished.String(), before).
+		Delete(&EventPO{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,98 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/outbox"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&EventPO{}))
+	return db
+}
+
+func TestRepository_AppendAndClaim(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	e1 := domain.NewEvent("topic", "type", "k1", []byte(`{"a":1}`), domain.WithMetadata("tenant_id", "t1"))
+	e2 := domain.NewEvent("topic", "type", "k2", []byte(`{"a":2}`))
+	require.NoError(t, repo.Append(ctx, e1, e2))
+	assert.False(t, e1.ID.IsZero())
+
+	// 幂等键唯一
+	assert.Error(t, repo.Append(ctx, domain.NewEvent("topic", "type", "k1", []byte(`{}`))))
+
+	now := time.Now().Add(time.Second)
+	claimed, err := repo.ClaimDue(ctx, "relay-a", now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, "t1", claimed[0].Metadata["tenant_id"])
+
+	// 认领未过期时其他实例拿不到
+	other, err := repo.ClaimDue(ctx, "relay-b", now, time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, other)
+
+	// 认领过期后可被重新认领
+	other, err = repo.ClaimDue(ctx, "relay-b", now.Add(2*time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	assert.Len(t, other, 2)
+}
+
+func TestRepository_SaveAndStats(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	ok := domain.NewEvent("topic", "type", "ok", []byte(`{}`))
+	retry := domain.NewEvent("topic", "type", "retry", []byte(`{}`))
+	dead := domain.NewEvent("topic", "type", "dead", []byte(`{}`))
+	require.NoError(t, repo.Append(ctx, ok, retry, dead))
+
+	now := time.Now().Add(time.Second)
+	claimed, err := repo.ClaimDue(ctx, "relay", now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 3)
+
+	policy := domain.RetryPolicy{MaxAttempts: 1, BaseBackoff: time.Second}
+	ok.MarkPublished(now.Add(-48 * time.Hour))
+	retry.MarkFailed(errors.New("nsq down"), now, domain.RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Hour})
+	dead.MarkFailed(errors.New("nsq down"), now, policy)
+	for _, e := range []*domain.Event{ok, retry, dead} {
+		require.NoError(t, repo.Save(ctx, e))
+	}
+
+	stats, err := repo.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Pending)
+	assert.Equal(t, int64(1), stats.Dead)
+	require.NotNil(t, stats.OldestPendingAt)
+
+	// 重试事件未到期前不会被认领
+	claimed, err = repo.ClaimDue(ctx, "relay", now, time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	claimed, err = repo.ClaimDue(ctx, "relay", now.Add(2*time.Hour), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, "retry", claimed[0].IdempotencyKey)
+	assert.Equal(t, 1, claimed[0].Attempts)
+	assert.Equal(t, "nsq down", claimed[0].LastError)
+
+	purged, err := repo.PurgePublished(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
@@ -27,6 +27,13 @@ func (m *Mapper) ToBO(po *PolicyVersionPO) *policy.PolicyVersion {
 		Version:   po.PolicyVersion,
 		ChangedBy: po.ChangedBy,
 		Reason:    po.Reason,
+		CreatedAt: po.CreatedAt,
+	}
+	if po.Changes != nil {
+		changes := make([]policy.RuleChange, 0)
+		if err := json.Unmarshal([]byte(*po.Changes), &changes); err == nil {
+			pv.Changes = changes
+		}
 	}
 
 	return pv
@@ -44,6 +51,12 @@ func (m *Mapper) ToPO(bo *policy.PolicyVersion) *PolicyVersionPO {
 		ChangedBy:     bo.ChangedBy,
 		Reason:        bo.Reason,
 	}
+	if bo.Changes != nil {
+		if data, err := json.Marshal(bo.Changes); err == nil {
+			changes := string(data)
+			po.Changes = &changes
+		}
+	}
 	id := meta.FromUint64(bo.ID.Uint64()) // 来自业务对象，必定有效
 	po.ID = id
 
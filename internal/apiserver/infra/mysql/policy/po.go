@@ -16,6 +16,8 @@ type PolicyVersionPO struct {
 	PolicyVersion int64  `gorm:"column:policy_version;type:bigint;not null;uniqueIndex:idx_tenant_version,priority:2"`
 	ChangedBy     string `gorm:"column:changed_by;type:varchar(64)"`
 	Reason        string `gorm:"column:reason;type:varchar(512)"`
+	// Changes 规则变更集 JSON；NULL 表示该版本未记录变更
+	Changes *string `gorm:"column:changes;type:json"`
 }
 
 // TableName 指定表名
@@ -18,7 +18,10 @@ type PolicyVersionRepository struct {
 	db     *gorm.DB
 }
 
-var _ domain.Repository = (*PolicyVersionRepository)(nil)
+var (
+	_ domain.Repository     = (*PolicyVersionRepository)(nil)
+	_ domain.VersionHistory = (*PolicyVersionRepository)(nil)
+)
 
 // NewPolicyVersionRepository 创建 PolicyVersion 仓储
 func NewPolicyVersionRepository(db *gorm.DB) domain.Repository {
@@ -34,6 +37,11 @@ func NewPolicyVersionRepository(db *gorm.DB) domain.Repository {
 	}
 }
 
+// NewVersionHistory 创建策略版本历史读取器
+func NewVersionHistory(db *gorm.DB) domain.VersionHistory {
+	return NewPolicyVersionRepository(db).(*PolicyVersionRepository)
+}
+
 // Create 创建新版本
 func (r *PolicyVersionRepository) Create(ctx context.Context, pv *domain.PolicyVersion) error {
 	po := r.mapper.ToPO(pv)
@@ -107,7 +115,7 @@ func (r *PolicyVersionRepository) GetOrCreate(ctx context.Context, tenantID stri
 }
 
 // Increment 递增版本号并记录变更
-func (r *PolicyVersionRepository) Increment(ctx context.Context, tenantID, changedBy, reason string) (*domain.PolicyVersion, error) {
+func (r *PolicyVersionRepository) Increment(ctx context.Context, tenantID, changedBy, reason string, opts ...domain.PolicyVersionOption) (*domain.PolicyVersion, error) {
 	// 获取当前版本号
 	currentVersion, err := r.GetVersionNumber(ctx, tenantID)
 	if err != nil {
@@ -118,8 +126,7 @@ func (r *PolicyVersionRepository) Increment(ctx context.Context, tenantID, chang
 	newVersion := domain.NewPolicyVersion(
 		tenantID,
 		currentVersion+1,
-		domain.WithChangedBy(changedBy),
-		domain.WithReason(reason),
+		append([]domain.PolicyVersionOption{domain.WithChangedBy(changedBy), domain.WithReason(reason)}, opts...)...,
 	)
 
 	if err := r.Create(ctx, &newVersion); err != nil {
@@ -170,6 +177,39 @@ func (r *PolicyVersionRepository) ListByTenant(ctx context.Context, tenantID str
 	return bos, total, nil
 }
 
+// ListVersions 按版本号倒序分页列出租户版本
+func (r *PolicyVersionRepository) ListVersions(ctx context.Context, tenantID string, offset, limit int) ([]*domain.PolicyVersion, int64, error) {
+	return r.ListByTenant(ctx, tenantID, offset, limit)
+}
+
+// FindVersion 获取租户的指定版本
+func (r *PolicyVersionRepository) FindVersion(ctx context.Context, tenantID string, version int64) (*domain.PolicyVersion, error) {
+	var po PolicyVersionPO
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND policy_version = ?", tenantID, version).
+		First(&po).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find policy version: %w", err)
+	}
+	return r.mapper.ToBO(&po), nil
+}
+
+// ListRange 按版本号升序列出 (from, to] 区间内的版本
+func (r *PolicyVersionRepository) ListRange(ctx context.Context, tenantID string, from, to int64) ([]*domain.PolicyVersion, error) {
+	var pos []*PolicyVersionPO
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND policy_version > ? AND policy_version <= ?", tenantID, from, to).
+		Order("policy_version ASC").
+		Find(&pos).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policy versions: %w", err)
+	}
+	return r.mapper.ToBOList(pos), nil
+}
+
 // Delete 删除版本（软删除）
 func (r *PolicyVersionRepository) Delete(ctx context.Context, id domain.PolicyVersionID) error {
 	err := r.BaseRepository.DeleteByID(ctx, id.Uint64())
@@ -0,0 +1,120 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/saml"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	dbmysql "github.com/FangcunMount/iam-contracts/internal/pkg/database/mysql"
+	"gorm.io/gorm"
+)
+
+// samlIdPRepository SAML IdP 登记仓储实现
+type samlIdPRepository struct {
+	dbmysql.BaseRepository[*SAMLIdPPO]
+	dbConn *gorm.DB
+}
+
+// 确保实现了接口
+var _ saml.Repository = (*samlIdPRepository)(nil)
+
+// NewSAMLIdPRepository 创建 SAML IdP 登记仓储实例
+func NewSAMLIdPRepository(db *gorm.DB) saml.Repository {
+	base := dbmysql.NewBaseRepository[*SAMLIdPPO](db)
+	base.SetErrorTranslator(dbmysql.NewDuplicateToTranslator(func(e error) error {
+		return perrors.WithCode(code.ErrSAMLIdPAlreadyExists, "saml idp already exists")
+	}))
+
+	return &samlIdPRepository{dbConn: db, BaseRepository: base}
+}
+
+// Create 创建 SAML IdP 登记
+func (r *samlIdPRepository) Create(ctx context.Context, idp *saml.IdentityProvider) error {
+	if idp == nil {
+		return errors.New("idp cannot be nil")
+	}
+
+	po := &SAMLIdPPO{}
+	if err := po.FromDomain(idp); err != nil {
+		return fmt.Errorf("failed to encode saml idp: %w", err)
+	}
+
+	return r.CreateAndSync(ctx, po, func(updated *SAMLIdPPO) {
+		idp.ID = updated.ID
+	})
+}
+
+// GetBySlug 根据 Slug 查询 SAML IdP 登记
+func (r *samlIdPRepository) GetBySlug(ctx context.Context, slug string) (*saml.IdentityProvider, error) {
+	if slug == "" {
+		return nil, errors.New("slug cannot be empty")
+	}
+
+	var po SAMLIdPPO
+	if err := r.WithContext(ctx).Where("slug = ?", slug).First(&po).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get saml idp by slug: %w", err)
+	}
+
+	return po.ToDomain(), nil
+}
+
+// List 查询 SAML IdP 登记列表
+func (r *samlIdPRepository) List(ctx context.Context, filter saml.ListFilter) ([]*saml.IdentityProvider, error) {
+	query := r.dbConn.WithContext(ctx).Model(&SAMLIdPPO{})
+	if filter.TenantID != nil {
+		query = query.Where("tenant_id = ?", filter.TenantID.Uint64())
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", string(*filter.Status))
+	}
+
+	var pos []*SAMLIdPPO
+	if err := query.Order("slug ASC").Find(&pos).Error; err != nil {
+		return nil, fmt.Errorf("failed to list saml idps: %w", err)
+	}
+
+	idps := make([]*saml.IdentityProvider, 0, len(pos))
+	for _, po := range pos {
+		idps = append(idps, po.ToDomain())
+	}
+	return idps, nil
+}
+
+// Update 更新 SAML IdP 登记
+func (r *samlIdPRepository) Update(ctx context.Context, idp *saml.IdentityProvider) error {
+	if idp == nil {
+		return errors.New("idp cannot be nil")
+	}
+
+	po := &SAMLIdPPO{}
+	if err := po.FromDomain(idp); err != nil {
+		return fmt.Errorf("failed to encode saml idp: %w", err)
+	}
+
+	result := r.dbConn.WithContext(ctx).Model(&SAMLIdPPO{}).Where("id = ?", po.ID).Updates(map[string]interface{}{
+		"name":              po.Name,
+		"entity_id":         po.EntityID,
+		"sso_url":           po.SSOURL,
+		"certificates":      po.Certificates,
+		"attribute_mapping": po.AttributeMapping,
+		"auto_provision":    po.AutoProvision,
+		"status":            po.Status,
+		"metadata_xml":      po.MetadataXML,
+	})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update saml idp: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("saml idp not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/saml"
+	testhelpers "github.com/FangcunMount/iam-contracts/internal/apiserver/testhelpers"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSAMLIdPRepository_RoundTrip(t *testing.T) {
+	db := testhelpers.SetupTempSQLiteDB(t)
+	require.NoError(t, db.AutoMigrate(&SAMLIdPPO{}))
+	repo := NewSAMLIdPRepository(db)
+	ctx := context.Background()
+
+	newIdP := func(tenant uint64, slug string) *saml.IdentityProvider {
+		return saml.NewIdentityProvider(meta.FromUint64(tenant), slug, saml.IdPMetadata{
+			EntityID:     "https://idp.example.com/" + slug,
+			SSOURL:       "https://idp.example.com/" + slug + "/sso",
+			Certificates: []string{"Y2VydA=="},
+		},
+			saml.WithIdentityProviderID(meta.FromUint64(idutil.GetIntID())),
+			saml.WithIdentityProviderName("IdP "+slug),
+			saml.WithIdentityProviderStatus(saml.StatusEnabled),
+		)
+	}
+
+	p := newIdP(7, "city-hospital")
+	p.AttributeMapping = map[string]string{"mail": "email"}
+	p.AutoProvision = true
+	p.MetadataXML = "<md:EntityDescriptor/>"
+	require.NoError(t, repo.Create(ctx, p))
+	require.NoError(t, repo.Create(ctx, newIdP(7, "county-school")))
+	require.NoError(t, repo.Create(ctx, newIdP(8, "other-tenant")))
+
+	// 重复 slug 映射为业务错误码
+	err := repo.Create(ctx, newIdP(9, "city-hospital"))
+	require.Error(t, err)
+	mapped := false
+	for ue := err; ue != nil; ue = errors.Unwrap(ue) {
+		if perrors.IsCode(ue, code.ErrSAMLIdPAlreadyExists) {
+			mapped = true
+			break
+		}
+	}
+	require.True(t, mapped, "duplicate should map to ErrSAMLIdPAlreadyExists, got %v", err)
+
+	got, err := repo.GetBySlug(ctx, "city-hospital")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, uint64(7), got.TenantID.Uint64())
+	require.Equal(t, []string{"Y2VydA=="}, got.Certificates)
+	require.Equal(t, map[string]string{"mail": "email"}, got.AttributeMapping)
+	require.True(t, got.AutoProvision)
+	require.Equal(t, "<md:EntityDescriptor/>", got.MetadataXML)
+
+	missing, err := repo.GetBySlug(ctx, "unknown")
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	got.Disable()
+	got.Certificates = []string{"bmV3", "b2xk"}
+	require.NoError(t, repo.Update(ctx, got))
+
+	tenant := meta.FromUint64(7)
+	enabled := saml.StatusEnabled
+	idps, err := repo.List(ctx, saml.ListFilter{TenantID: &tenant, Status: &enabled})
+	require.NoError(t, err)
+	require.Len(t, idps, 1)
+	require.Equal(t, "county-school", idps[0].Slug)
+
+	reloaded, err := repo.GetBySlug(ctx, "city-hospital")
+	require.NoError(t, err)
+	require.True(t, reloaded.IsDisabled())
+	require.Equal(t, []string{"bmV3", "b2xk"}, reloaded.Certificates)
+}
@@ -0,0 +1,152 @@
+package serviceacl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/serviceacl"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	base "github.com/FangcunMount/iam-contracts/internal/pkg/database/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// seedName 启动配置导入标记的来源标识
+const seedName = "grpc_acl.yaml"
+
+// serviceACLRepository 服务 ACL 仓储实现
+type serviceACLRepository struct {
+	base.BaseRepository[*ServiceACLPO]
+	db *gorm.DB
+}
+
+// 确保实现了接口
+var _ serviceacl.Repository = (*serviceACLRepository)(nil)
+
+// NewServiceACLRepository 创建服务 ACL 仓储实例
+func NewServiceACLRepository(db *gorm.DB) serviceacl.Repository {
+	repo := base.NewBaseRepository[*ServiceACLPO](db)
+	repo.SetErrorTranslator(base.NewDuplicateToTranslator(func(e error) error {
+		return perrors.WithCode(code.ErrServiceACLAlreadyExists, "service acl already exists")
+	}))
+	return &serviceACLRepository{BaseRepository: repo, db: db}
+}
+
+// Create 创建条目
+func (r *serviceACLRepository) Create(ctx context.Context, entry *serviceacl.Entry) error {
+	po := &ServiceACLPO{}
+	if err := po.FromDomain(entry); err != nil {
+		return fmt.Errorf("failed to encode service acl: %w", err)
+	}
+	return r.CreateAndSync(ctx, po, func(saved *ServiceACLPO) {
+		entry.ID = saved.ID
+	})
+}
+
+// Update 更新条目
+func (r *serviceACLRepository) Update(ctx context.Context, entry *serviceacl.Entry) error {
+	po := &ServiceACLPO{}
+	if err := po.FromDomain(entry); err != nil {
+		return fmt.Errorf("failed to encode service acl: %w", err)
+	}
+
+	result := r.db.WithContext(ctx).Model(&ServiceACLPO{}).
+		Where("service_name = ?", entry.ServiceName).
+		Updates(map[string]interface{}{
+			"enabled":         po.Enabled,
+			"description":     po.Description,
+			"allowed_methods": po.AllowedMethods,
+			"denied_methods":  po.DeniedMethods,
+			"version":         gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update service acl: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return perrors.WithCode(code.ErrServiceACLNotFound, "service acl %s not found", entry.ServiceName)
+	}
+	return nil
+}
+
+// Delete 删除条目
+func (r *serviceACLRepository) Delete(ctx context.Context, serviceName string) error {
+	result := r.db.WithContext(ctx).Where("service_name = ?", serviceName).Delete(&ServiceACLPO{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete service acl: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return perrors.WithCode(code.ErrServiceACLNotFound, "service acl %s not found", serviceName)
+	}
+	return nil
+}
+
+// FindByService 根据服务名查询
+func (r *serviceACLRepository) FindByService(ctx context.Context, serviceName string) (*serviceacl.Entry, error) {
+	var po ServiceACLPO
+	if err := r.db.WithContext(ctx).Where("service_name = ?", serviceName).First(&po).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, perrors.WithCode(code.ErrServiceACLNotFound, "service acl %s not found", serviceName)
+		}
+		return nil, fmt.Errorf("failed to get service acl: %w", err)
+	}
+	return po.ToDomain(), nil
+}
+
+// List 列出全部条目
+func (r *serviceACLRepository) List(ctx context.Context) ([]*serviceacl.Entry, error) {
+	var pos []*ServiceACLPO
+	if err := r.db.WithContext(ctx).Order("service_name ASC").Find(&pos).Error; err != nil {
+		return nil, fmt.Errorf("failed to list service acls: %w", err)
+	}
+
+	entries := make([]*serviceacl.Entry, 0, len(pos))
+	for _, po := range pos {
+		entries = append(entries, po.ToDomain())
+	}
+	return entries, nil
+}
+
+// Count 统计条目数量
+func (r *serviceACLRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&ServiceACLPO{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count service acls: %w", err)
+	}
+	return count, nil
+}
+
+// Seed 先以 ON CONFLICT DO NOTHING 抢占导入标记，抢到的副本在同一事务中写入条目；
+// 条目写入失败时标记随事务回滚，下次启动重新导入
+func (r *serviceACLRepository) Seed(ctx context.Context, entries []*serviceacl.Entry) (bool, error) {
+	seeded := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		marker := &ServiceACLSeedPO{Name: seedName, Services: len(entries), SeededAt: time.Now()}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(marker)
+		if result.Error != nil {
+			return fmt.Errorf("failed to mark service acl seed: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		for _, entry := range entries {
+			po := &ServiceACLPO{}
+			if err := po.FromDomain(entry); err != nil {
+				return fmt.Errorf("failed to encode service acl: %w", err)
+			}
+			if err := tx.Create(po).Error; err != nil {
+				return fmt.Errorf("failed to seed service acl %s: %w", entry.ServiceName, err)
+			}
+			entry.ID = po.ID
+		}
+		seeded = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return seeded, nil
+}
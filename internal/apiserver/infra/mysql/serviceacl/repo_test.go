@@ -0,0 +1,110 @@
+package serviceacl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/serviceacl"
+	testhelpers "github.com/FangcunMount/iam-contracts/internal/apiserver/testhelpers"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/stretchr/testify/require"
+)
+
+func hasCode(err error, c int) bool {
+	for ue := err; ue != nil; ue = errors.Unwrap(ue) {
+		if perrors.IsCode(ue, c) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestServiceACLRepository_RoundTrip(t *testing.T) {
+	db := testhelpers.SetupTempSQLiteDB(t)
+	require.NoError(t, db.AutoMigrate(&ServiceACLPO{}))
+	repo := NewServiceACLRepository(db)
+	ctx := context.Background()
+
+	qs := serviceacl.NewEntry("qs-apiserver.svc",
+		[]string{"/iam.identity.v1.IdentityRead/GetUser"},
+		[]string{"/iam.identity.v1.IdentityLifecycle/*"},
+		serviceacl.WithDescription("QS"),
+	)
+	require.NoError(t, repo.Create(ctx, qs))
+	require.False(t, qs.ID.IsZero())
+	require.NoError(t, repo.Create(ctx, serviceacl.NewEntry("admin", []string{"*"}, nil)))
+
+	// 重复服务名映射为业务错误码
+	err := repo.Create(ctx, serviceacl.NewEntry("qs-apiserver.svc", nil, nil))
+	require.True(t, hasCode(err, code.ErrServiceACLAlreadyExists), "got %v", err)
+
+	got, err := repo.FindByService(ctx, "qs-apiserver.svc")
+	require.NoError(t, err)
+	require.True(t, got.Enabled)
+	require.Equal(t, "QS", got.Description)
+	require.Equal(t, []string{"/iam.identity.v1.IdentityRead/GetUser"}, got.AllowedMethods)
+	require.Equal(t, []string{"/iam.identity.v1.IdentityLifecycle/*"}, got.DeniedMethods)
+
+	got.Enabled = false
+	got.ReplaceMethods([]string{"/iam.identity.v1.IdentityRead/*"}, nil)
+	require.NoError(t, repo.Update(ctx, got))
+
+	entries, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "admin", entries[0].ServiceName)
+	require.False(t, entries[1].Enabled)
+	require.Equal(t, []string{"/iam.identity.v1.IdentityRead/*"}, entries[1].AllowedMethods)
+	require.Empty(t, entries[1].DeniedMethods)
+
+	require.NoError(t, repo.Delete(ctx, "qs-apiserver.svc"))
+	_, err = repo.FindByService(ctx, "qs-apiserver.svc")
+	require.True(t, hasCode(err, code.ErrServiceACLNotFound))
+	require.True(t, hasCode(repo.Delete(ctx, "qs-apiserver.svc"), code.ErrServiceACLNotFound))
+	require.True(t, hasCode(repo.Update(ctx, got), code.ErrServiceACLNotFound))
+
+	// 删除后可重新登记同名服务
+	require.NoError(t, repo.Create(ctx, serviceacl.NewEntry("qs-apiserver.svc", nil, nil)))
+	count, err := repo.Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+}
+
+func TestServiceACLRepository_SeedOnce(t *testing.T) {
+	db := testhelpers.SetupTempSQLiteDB(t)
+	require.NoError(t, db.AutoMigrate(&ServiceACLPO{}, &ServiceACLSeedPO{}))
+	repo := NewServiceACLRepository(db)
+	ctx := context.Background()
+
+	seeded, err := repo.Seed(ctx, []*serviceacl.Entry{serviceacl.NewEntry("qs-apiserver.svc", []string{"*"}, nil)})
+	require.NoError(t, err)
+	require.True(t, seeded)
+
+	require.NoError(t, repo.Delete(ctx, "qs-apiserver.svc"))
+	seeded, err = repo.Seed(ctx, []*serviceacl.Entry{serviceacl.NewEntry("qs-apiserver.svc", []string{"*"}, nil)})
+	require.NoError(t, err)
+	require.False(t, seeded, "已导入过时不再写入")
+	count, err := repo.Count(ctx)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func TestServiceACLRepository_SeedRollsBackMarkerOnFailure(t *testing.T) {
+	db := testhelpers.SetupTempSQLiteDB(t)
+	require.NoError(t, db.AutoMigrate(&ServiceACLPO{}, &ServiceACLSeedPO{}))
+	repo := NewServiceACLRepository(db)
+	ctx := context.Background()
+
+	// 同名条目触发唯一键冲突，整批连同标记回滚
+	_, err := repo.Seed(ctx, []*serviceacl.Entry{
+		serviceacl.NewEntry("qs-apiserver.svc", []string{"*"}, nil),
+		serviceacl.NewEntry("qs-apiserver.svc", []string{"*"}, nil),
+	})
+	require.Error(t, err)
+
+	seeded, err := repo.Seed(ctx, []*serviceacl.Entry{serviceacl.NewEntry("qs-apiserver.svc", []string{"*"}, nil)})
+	require.NoError(t, err)
+	require.True(t, seeded)
+}
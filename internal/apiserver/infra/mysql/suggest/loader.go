@@ -3,37 +3,77 @@ package suggest
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/FangcunMount/component-base/pkg/log"
 	"gorm.io/gorm"
+
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/suggest"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/infra/suggest/search"
 )
 
+// 基础权重 = 有效监护人数 * 10 + 近 30 天内的活跃度（资料或监护关系越新越高），
+// 匹配类型带来的相关度加权在搜索阶段叠加。
 const (
 	defaultFullSQL = `
 SELECT
   c.id,
   c.name,
+  c.tenant_id,
   GROUP_CONCAT(DISTINCT u.phone) AS mobiles,
-  1 AS weight
+  GROUP_CONCAT(DISTINCT g.user_id) AS guardians,
+  COUNT(DISTINCT g.user_id) * 10
+    + GREATEST(0, 30 - DATEDIFF(NOW(), GREATEST(c.updated_at, MAX(g.updated_at)))) AS weight
 FROM children c
-INNER JOIN guardianships g ON g.child_id = c.id AND g.deleted_at IS NULL
+INNER JOIN guardianships g ON g.child_id = c.id AND g.deleted_at IS NULL AND g.revoked_at IS NULL
 INNER JOIN users u ON u.id = g.user_id AND u.deleted_at IS NULL
 WHERE c.deleted_at IS NULL
-GROUP BY c.id;
+GROUP BY c.id, c.name, c.tenant_id, c.updated_at;
 `
+	// 增量：先找出 since 之后儿童、监护关系（含撤销/删除）或监护人有变动的儿童，
+	// 仍可检索的输出最新行，已删除或不再有有效监护人的输出墓碑行（deleted = 1）。
 	defaultDeltaSQL = `
 SELECT
   c.id,
   c.name,
+  c.tenant_id,
   GROUP_CONCAT(DISTINCT u.phone) AS mobiles,
-  1 AS weight
+  GROUP_CONCAT(DISTINCT g.user_id) AS guardians,
+  COUNT(DISTINCT g.user_id) * 10
+    + GREATEST(0, 30 - DATEDIFF(NOW(), GREATEST(c.updated_at, MAX(g.updated_at)))) AS weight,
+  0 AS deleted
 FROM children c
-INNER JOIN guardianships g ON g.child_id = c.id AND g.deleted_at IS NULL
+INNER JOIN guardianships g ON g.child_id = c.id AND g.deleted_at IS NULL AND g.revoked_at IS NULL
 INNER JOIN users u ON u.id = g.user_id AND u.deleted_at IS NULL
-WHERE c.deleted_at IS NULL AND GREATEST(c.updated_at, g.updated_at, u.updated_at) > ?
-GROUP BY c.id;
+WHERE c.deleted_at IS NULL AND c.id IN (
+  SELECT id FROM children WHERE updated_at > @since
+  UNION SELECT child_id FROM guardianships WHERE updated_at > @since
+  UNION SELECT gg.child_id FROM guardianships gg INNER JOIN users uu ON uu.id = gg.user_id WHERE uu.updated_at > @since
+)
+GROUP BY c.id, c.name, c.tenant_id, c.updated_at
+UNION ALL
+SELECT
+  c.id,
+  c.name,
+  c.tenant_id,
+  NULL AS mobiles,
+  NULL AS guardians,
+  0 AS weight,
+  1 AS deleted
+FROM children c
+WHERE c.id IN (
+  SELECT id FROM children WHERE updated_at > @since
+  UNION SELECT child_id FROM guardianships WHERE updated_at > @since
+  UNION SELECT gg.child_id FROM guardianships gg INNER JOIN users uu ON uu.id = gg.user_id WHERE uu.updated_at > @since
+) AND (
+  c.deleted_at IS NOT NULL OR NOT EXISTS (
+    SELECT 1 FROM guardianships g
+    INNER JOIN users u ON u.id = g.user_id AND u.deleted_at IS NULL
+    WHERE g.child_id = c.id AND g.deleted_at IS NULL AND g.revoked_at IS NULL
+  )
+);
 `
 )
 
@@ -74,19 +114,27 @@ func (l *Loader) Full(ctx context.Context) ([]string, error) {
 	return l.query(ctx, l.config.FullSQL)
 }
 
-// Delta 增量拉取，按时间过滤
+// Delta 增量拉取，按时间过滤；返回的行可能包含墓碑
+//
+// SQL 中使用 @since 命名参数时按名绑定，否则按位置绑定单个 ? 占位符（兼容旧配置）。
 func (l *Loader) Delta(ctx context.Context, since time.Time) ([]string, error) {
 	if strings.TrimSpace(l.config.DeltaSQL) == "" {
 		return nil, nil
 	}
+	if strings.Contains(l.config.DeltaSQL, "@since") {
+		return l.query(ctx, l.config.DeltaSQL, map[string]interface{}{"since": since})
+	}
 	return l.query(ctx, l.config.DeltaSQL, since)
 }
 
 type record struct {
-	ID      int64   `gorm:"column:id"`
-	Name    string  `gorm:"column:name"`
-	Mobiles *string `gorm:"column:mobiles"`
-	Weight  int     `gorm:"column:weight"`
+	ID        int64   `gorm:"column:id"`
+	Name      string  `gorm:"column:name"`
+	TenantID  *string `gorm:"column:tenant_id"`
+	Mobiles   *string `gorm:"column:mobiles"`
+	Guardians *string `gorm:"column:guardians"`
+	Weight    int     `gorm:"column:weight"`
+	Deleted   bool    `gorm:"column:deleted"`
 }
 
 func (l *Loader) query(ctx context.Context, sql string, args ...interface{}) ([]string, error) {
@@ -101,13 +149,20 @@ func (l *Loader) query(ctx context.Context, sql string, args ...interface{}) ([]
 
 	lines := make([]string, 0, len(rows))
 	for _, row := range rows {
-		mobiles := ""
-		if row.Mobiles != nil {
-			mobiles = *row.Mobiles
+		// 行格式见 search.FormatLine；租户为空时写占位符 "-"，由索引归入默认租户
+		term := domain.Term{
+			Name:     strings.TrimSpace(row.Name),
+			ID:       row.ID,
+			Mobile:   strings.TrimSpace(deref(row.Mobiles)),
+			Weight:   row.Weight,
+			TenantID: strings.TrimSpace(deref(row.TenantID)),
+		}
+		for _, g := range strings.Split(deref(row.Guardians), ",") {
+			if gid, err := strconv.ParseInt(strings.TrimSpace(g), 10, 64); err == nil && gid != 0 {
+				term.Guardians = append(term.Guardians, gid)
+			}
 		}
-		// 保持行格式：name|id|mobiles|-|weight，中间的占位符与旧格式兼容
-		line := fmt.Sprintf("%s|%d|%s|-|%d", strings.TrimSpace(row.Name), row.ID, strings.TrimSpace(mobiles), row.Weight)
-		lines = append(lines, line)
+		lines = append(lines, search.FormatLine(term, row.Deleted))
 	}
 
 	log.Infow("suggest loader finished query", "sql", sanitizeSQL(sql), "count", len(lines))
@@ -115,6 +170,13 @@ func (l *Loader) query(ctx context.Context, sql string, args ...interface{}) ([]
 	return lines, nil
 }
 
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 // sanitizeSQL 仅用于日志，避免输出换行
 func sanitizeSQL(sql string) string {
 	return strings.Join(strings.Fields(sql), " ")
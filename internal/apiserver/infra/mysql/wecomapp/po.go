@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"time"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/database/mysql"
+)
+
+// WecomAppPO 企业微信应用持久化对象
+type WecomAppPO struct {
+	mysql.AuditFields
+
+	CorpID  string `gorm:"column:corp_id;type:varchar(64);not null;uniqueIndex:uk_corp_agent,priority:1" json:"corp_id"`
+	AgentID string `gorm:"column:agent_id;type:varchar(64);not null;uniqueIndex:uk_corp_agent,priority:2" json:"agent_id"`
+	Name    string `gorm:"column:name;type:varchar(255);not null" json:"name"`
+	Status  string `gorm:"column:status;type:varchar(32);not null;default:'Enabled';index:idx_status" json:"status"`
+
+	// 凭据字段（加密存储）
+	CorpSecretCipher    []byte     `gorm:"column:corp_secret_cipher;type:blob" json:"-"`
+	CorpSecretFP        string     `gorm:"column:corp_secret_fp;type:varchar(128)" json:"-"`
+	CorpSecretVersion   int        `gorm:"column:corp_secret_version;default:0" json:"-"`
+	CorpSecretRotatedAt *time.Time `gorm:"column:corp_secret_rotated_at" json:"-"`
+}
+
+// TableName 指定表名
+func (WecomAppPO) TableName() string {
+	return "idp_wecom_apps"
+}
+
+// ToDomain 转换为领域对象
+func (po *WecomAppPO) ToDomain() *wecomapp.WecomApp {
+	if po == nil {
+		return nil
+	}
+
+	app := &wecomapp.WecomApp{
+		ID:      po.ID,
+		CorpID:  po.CorpID,
+		AgentID: po.AgentID,
+		Name:    po.Name,
+		Status:  wecomapp.Status(po.Status),
+		Cred:    &wecomapp.Credentials{},
+	}
+
+	if len(po.CorpSecretCipher) > 0 {
+		app.Cred.Auth = &wecomapp.CorpSecret{
+			SecretCipher:  po.CorpSecretCipher,
+			Fingerprint:   po.CorpSecretFP,
+			Version:       po.CorpSecretVersion,
+			LastRotatedAt: po.CorpSecretRotatedAt,
+		}
+	}
+
+	return app
+}
+
+// FromDomain 从领域对象转换
+func (po *WecomAppPO) FromDomain(app *wecomapp.WecomApp) {
+	if app == nil {
+		return
+	}
+
+	po.ID = app.ID
+	po.CorpID = app.CorpID
+	po.AgentID = app.AgentID
+	po.Name = app.Name
+	po.Status = string(app.Status)
+
+	if app.Cred != nil && app.Cred.Auth != nil {
+		po.CorpSecretCipher = app.Cred.Auth.SecretCipher
+		po.CorpSecretFP = app.Cred.Auth.Fingerprint
+		po.CorpSecretVersion = app.Cred.Auth.Version
+		po.CorpSecretRotatedAt = app.Cred.Auth.LastRotatedAt
+	}
+}
@@ -0,0 +1,130 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	dbmysql "github.com/FangcunMount/iam-contracts/internal/pkg/database/mysql"
+	"gorm.io/gorm"
+)
+
+// wecomAppRepository 企业微信应用仓储实现
+type wecomAppRepository struct {
+	dbmysql.BaseRepository[*WecomAppPO]
+	dbConn *gorm.DB
+}
+
+// 确保实现了接口
+var _ wecomapp.Repository = (*wecomAppRepository)(nil)
+
+// NewWecomAppRepository 创建企业微信应用仓储实例
+func NewWecomAppRepository(db *gorm.DB) wecomapp.Repository {
+	base := dbmysql.NewBaseRepository[*WecomAppPO](db)
+	base.SetErrorTranslator(dbmysql.NewDuplicateToTranslator(func(e error) error {
+		return perrors.WithCode(code.ErrWecomAppAlreadyExists, "wecom app already exists")
+	}))
+
+	return &wecomAppRepository{dbConn: db, BaseRepository: base}
+}
+
+// Create 创建企业微信应用
+func (r *wecomAppRepository) Create(ctx context.Context, app *wecomapp.WecomApp) error {
+	if app == nil {
+		return errors.New("app cannot be nil")
+	}
+
+	po := &WecomAppPO{}
+	po.FromDomain(app)
+
+	return r.CreateAndSync(ctx, po, func(updated *WecomAppPO) {
+		app.ID = updated.ID
+	})
+}
+
+// GetByID 根据 ID 查询企业微信应用
+func (r *wecomAppRepository) GetByID(ctx context.Context, id idutil.ID) (*wecomapp.WecomApp, error) {
+	po, err := r.FindByID(ctx, id.Uint64())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get wecom app by id: %w", err)
+	}
+	if po == nil {
+		return nil, nil
+	}
+	return po.ToDomain(), nil
+}
+
+// GetByCorpAgent 根据 CorpID + AgentID 查询企业微信应用
+func (r *wecomAppRepository) GetByCorpAgent(ctx context.Context, corpID, agentID string) (*wecomapp.WecomApp, error) {
+	if corpID == "" || agentID == "" {
+		return nil, errors.New("corpID and agentID cannot be empty")
+	}
+
+	var po WecomAppPO
+	if err := r.WithContext(ctx).Where("corp_id = ? AND agent_id = ?", corpID, agentID).First(&po).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get wecom app by corp and agent: %w", err)
+	}
+
+	return po.ToDomain(), nil
+}
+
+// List 查询企业微信应用列表。
+func (r *wecomAppRepository) List(ctx context.Context, filter wecomapp.ListFilter) ([]*wecomapp.WecomApp, error) {
+	query := r.dbConn.WithContext(ctx).Model(&WecomAppPO{})
+	if filter.CorpID != nil {
+		query = query.Where("corp_id = ?", *filter.CorpID)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", string(*filter.Status))
+	}
+
+	var pos []*WecomAppPO
+	if err := query.Order("corp_id ASC, agent_id ASC").Find(&pos).Error; err != nil {
+		return nil, fmt.Errorf("failed to list wecom apps: %w", err)
+	}
+
+	apps := make([]*wecomapp.WecomApp, 0, len(pos))
+	for _, po := range pos {
+		apps = append(apps, po.ToDomain())
+	}
+	return apps, nil
+}
+
+// Update 更新企业微信应用
+func (r *wecomAppRepository) Update(ctx context.Context, app *wecomapp.WecomApp) error {
+	if app == nil {
+		return errors.New("app cannot be nil")
+	}
+
+	po := &WecomAppPO{}
+	po.FromDomain(app)
+
+	result := r.dbConn.WithContext(ctx).Model(&WecomAppPO{}).Where("id = ?", po.ID).Updates(map[string]interface{}{
+		"name":                   po.Name,
+		"status":                 po.Status,
+		"corp_secret_cipher":     po.CorpSecretCipher,
+		"corp_secret_fp":         po.CorpSecretFP,
+		"corp_secret_version":    po.CorpSecretVersion,
+		"corp_secret_rotated_at": po.CorpSecretRotatedAt,
+	})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update wecom app: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("wecom app not found")
+	}
+
+	return nil
+}
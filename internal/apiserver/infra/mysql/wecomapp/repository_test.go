@@ -0,0 +1,73 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/component-base/pkg/util/idutil"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
+	testhelpers "github.com/FangcunMount/iam-contracts/internal/apiserver/testhelpers"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWecomAppRepository_RoundTrip(t *testing.T) {
+	db := testhelpers.SetupTempSQLiteDB(t)
+	require.NoError(t, db.AutoMigrate(&WecomAppPO{}))
+	repo := NewWecomAppRepository(db)
+	ctx := context.Background()
+
+	newApp := func(agentID string) *wecomapp.WecomApp {
+		return wecomapp.NewWecomApp("ww-corp", agentID,
+			wecomapp.WithWecomAppID(meta.FromUint64(idutil.GetIntID())),
+			wecomapp.WithWecomAppName("OA-"+agentID),
+			wecomapp.WithWecomAppStatus(wecomapp.StatusEnabled),
+		)
+	}
+
+	app := newApp("1000002")
+	app.Cred = &wecomapp.Credentials{Auth: &wecomapp.CorpSecret{SecretCipher: []byte("cipher"), Fingerprint: "fp", Version: 1}}
+	require.NoError(t, repo.Create(ctx, app))
+	require.NoError(t, repo.Create(ctx, newApp("1000003")))
+
+	// 同一 CorpID + AgentID 重复登记映射为业务错误码
+	err := repo.Create(ctx, newApp("1000002"))
+	require.Error(t, err)
+	mapped := false
+	for ue := err; ue != nil; ue = errors.Unwrap(ue) {
+		if perrors.IsCode(ue, code.ErrWecomAppAlreadyExists) {
+			mapped = true
+			break
+		}
+	}
+	require.True(t, mapped, "duplicate should map to ErrWecomAppAlreadyExists, got %v", err)
+
+	got, err := repo.GetByCorpAgent(ctx, "ww-corp", "1000002")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, []byte("cipher"), got.Cred.Auth.SecretCipher)
+	require.Equal(t, 1, got.Cred.Auth.Version)
+
+	missing, err := repo.GetByCorpAgent(ctx, "ww-corp", "9999")
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	got.Disable()
+	got.Cred.Auth.Version = 2
+	require.NoError(t, repo.Update(ctx, got))
+
+	corpID := "ww-corp"
+	enabled := wecomapp.StatusEnabled
+	apps, err := repo.List(ctx, wecomapp.ListFilter{CorpID: &corpID, Status: &enabled})
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	require.Equal(t, "1000003", apps[0].AgentID)
+
+	reloaded, err := repo.GetByID(ctx, idutil.NewID(got.ID.Uint64()))
+	require.NoError(t, err)
+	require.True(t, reloaded.IsDisabled())
+	require.Equal(t, 2, reloaded.Cred.Auth.Version)
+}
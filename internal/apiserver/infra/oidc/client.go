@@ -0,0 +1,325 @@
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/oidcprovider"
+)
+
+const (
+	defaultMetadataTTL = 10 * time.Minute
+	clockSkew          = time.Minute
+	maxResponseBytes   = 1 << 20
+)
+
+// Metadata 上游 discovery 元数据（仅保留授权码模式需要的字段）
+type Metadata struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	UserinfoEndpoint      string   `json:"userinfo_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	TokenAuthMethods      []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// Client 上游 OIDC 客户端
+// 职责：discovery 元数据与 JWKS 缓存、授权码换取令牌、id_token 校验与 userinfo 合并
+type Client struct {
+	httpClient *http.Client
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu       sync.Mutex
+	metadata map[string]cachedMetadata
+	keySets  map[string]cachedKeySet
+}
+
+type cachedMetadata struct {
+	value     *Metadata
+	fetchedAt time.Time
+}
+
+type cachedKeySet struct {
+	value     jwk.Set
+	fetchedAt time.Time
+}
+
+var (
+	_ authentication.OIDCFederation = (*Client)(nil)
+	_ oidcprovider.Discovery        = (*Client)(nil)
+)
+
+// NewClient 创建上游 OIDC 客户端；httpClient 为空时使用 10s 超时的默认客户端
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Client{
+		httpClient: httpClient,
+		ttl:        defaultMetadataTTL,
+		now:        time.Now,
+		metadata:   make(map[string]cachedMetadata),
+		keySets:    make(map[string]cachedKeySet),
+	}
+}
+
+// AuthorizationEndpoint 返回上游授权端点
+func (c *Client) AuthorizationEndpoint(ctx context.Context, issuer string) (string, error) {
+	md, err := c.Discover(ctx, issuer)
+	if err != nil {
+		return "", err
+	}
+	return md.AuthorizationEndpoint, nil
+}
+
+// Discover 读取 discovery 元数据（带缓存），并校验 issuer 一致
+func (c *Client) Discover(ctx context.Context, issuer string) (*Metadata, error) {
+	issuer = strings.TrimRight(issuer, "/")
+
+	c.mu.Lock()
+	cached, ok := c.metadata[issuer]
+	c.mu.Unlock()
+	if ok && c.now().Sub(cached.fetchedAt) < c.ttl {
+		return cached.value, nil
+	}
+
+	var md Metadata
+	if err := c.getJSON(ctx, issuer+"/.well-known/openid-configuration", "", &md); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	if strings.TrimRight(md.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc discovery issuer mismatch: got %q, want %q", md.Issuer, issuer)
+	}
+	if md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return nil, errors.New("oidc discovery document lacks token_endpoint or jwks_uri")
+	}
+
+	c.mu.Lock()
+	c.metadata[issuer] = cachedMetadata{value: &md, fetchedAt: c.now()}
+	c.mu.Unlock()
+	return &md, nil
+}
+
+// ExchangeOIDCCode 授权码换取令牌，校验 id_token 后返回声明（合并 userinfo）
+func (c *Client) ExchangeOIDCCode(ctx context.Context, req authentication.OIDCCodeExchange) (map[string]any, error) {
+	issuer := strings.TrimRight(req.Issuer, "/")
+	md, err := c.Discover(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := c.exchangeCode(ctx, md, req)
+	if err != nil {
+		return nil, err
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("oidc token response lacks id_token")
+	}
+
+	claims, err := c.verifyIDToken(ctx, md, issuer, req.ClientID, req.Nonce, tokens.IDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if md.UserinfoEndpoint != "" && tokens.AccessToken != "" {
+		if err := c.mergeUserinfo(ctx, md.UserinfoEndpoint, tokens.AccessToken, claims); err != nil {
+			return nil, err
+		}
+	}
+	return claims, nil
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	TokenType        string `json:"token_type"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// exchangeCode 调用 token 端点；上游仅声明支持 client_secret_post 时改用表单传递密钥
+func (c *Client) exchangeCode(ctx context.Context, md *Metadata, req authentication.OIDCCodeExchange) (*tokenResponse, error) {
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {req.Code},
+		"redirect_uri": {req.RedirectURI},
+	}
+	if req.CodeVerifier != "" {
+		form.Set("code_verifier", req.CodeVerifier)
+	}
+	useBasic := req.ClientSecret != "" && !onlyPostAuth(md.TokenAuthMethods)
+	if !useBasic {
+		form.Set("client_id", req.ClientID)
+		if req.ClientSecret != "" {
+			form.Set("client_secret", req.ClientSecret)
+		}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.Header.Set("Accept", "application/json")
+	if useBasic {
+		httpReq.SetBasicAuth(url.QueryEscape(req.ClientID), url.QueryEscape(req.ClientSecret))
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("oidc token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tokens tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("oidc token response is malformed (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || tokens.Error != "" {
+		return nil, fmt.Errorf("oidc token request rejected (status %d): %s %s", resp.StatusCode, tokens.Error, tokens.ErrorDescription)
+	}
+	return &tokens, nil
+}
+
+// verifyIDToken 校验签名、iss、aud、exp 与 nonce；密钥轮换导致校验失败时强制刷新一次 JWKS
+func (c *Client) verifyIDToken(ctx context.Context, md *Metadata, issuer, clientID, nonce, idToken string) (map[string]any, error) {
+	tok, err := c.parseIDToken(ctx, md.JWKSURI, issuer, clientID, idToken, false)
+	if err != nil {
+		tok, err = c.parseIDToken(ctx, md.JWKSURI, issuer, clientID, idToken, true)
+		if err != nil {
+			return nil, fmt.Errorf("oidc id_token verification failed: %w", err)
+		}
+	}
+	if tok.Expiration().IsZero() {
+		return nil, errors.New("oidc id_token lacks exp")
+	}
+	if len(tok.Audience()) > 1 {
+		if azp, _ := tok.PrivateClaims()["azp"].(string); azp != clientID {
+			return nil, errors.New("oidc id_token azp does not match client_id")
+		}
+	}
+	if nonce == "" {
+		return nil, errors.New("oidc authorization nonce is required")
+	}
+	if got, _ := tok.PrivateClaims()["nonce"].(string); got != nonce {
+		return nil, errors.New("oidc id_token nonce mismatch")
+	}
+
+	// 签名已校验，按原始 JSON 解码载荷以保留声明的原生类型
+	parts := strings.Split(idToken, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("oidc id_token payload is malformed: %w", err)
+	}
+	claims := make(map[string]any)
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("oidc id_token payload is malformed: %w", err)
+	}
+	return claims, nil
+}
+
+func (c *Client) parseIDToken(ctx context.Context, jwksURI, issuer, clientID, idToken string, refresh bool) (jwt.Token, error) {
+	set, err := c.keySet(ctx, jwksURI, refresh)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.Parse([]byte(idToken),
+		jwt.WithKeySet(set, jws.WithInferAlgorithmFromKey(true)),
+		jwt.WithValidate(true),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(clientID),
+		jwt.WithAcceptableSkew(clockSkew),
+		jwt.WithClock(jwt.ClockFunc(c.now)),
+	)
+}
+
+// keySet 读取 JWKS（带缓存）
+func (c *Client) keySet(ctx context.Context, jwksURI string, refresh bool) (jwk.Set, error) {
+	c.mu.Lock()
+	cached, ok := c.keySets[jwksURI]
+	c.mu.Unlock()
+	if ok && !refresh && c.now().Sub(cached.fetchedAt) < c.ttl {
+		return cached.value, nil
+	}
+
+	set, err := jwk.Fetch(ctx, jwksURI, jwk.WithHTTPClient(c.httpClient))
+	if err != nil {
+		return nil, fmt.Errorf("oidc jwks fetch failed: %w", err)
+	}
+
+	c.mu.Lock()
+	c.keySets[jwksURI] = cachedKeySet{value: set, fetchedAt: c.now()}
+	c.mu.Unlock()
+	return set, nil
+}
+
+// mergeUserinfo 合并 userinfo 声明，id_token 中已有的声明优先；sub 不一致时拒绝
+func (c *Client) mergeUserinfo(ctx context.Context, endpoint, accessToken string, claims map[string]any) error {
+	info := make(map[string]any)
+	if err := c.getJSON(ctx, endpoint, accessToken, &info); err != nil {
+		return fmt.Errorf("oidc userinfo request failed: %w", err)
+	}
+	if sub, _ := info["sub"].(string); sub != "" && sub != claims["sub"] {
+		return errors.New("oidc userinfo sub does not match id_token")
+	}
+	for k, v := range info {
+		if _, exists := claims[k]; !exists {
+			claims[k] = v
+		}
+	}
+	return nil
+}
+
+func (c *Client) getJSON(ctx context.Context, endpoint, bearer string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(out)
+}
+
+func onlyPostAuth(methods []string) bool {
+	if len(methods) == 0 {
+		return false
+	}
+	for _, m := range methods {
+		if m == "client_secret_basic" {
+			return false
+		}
+	}
+	for _, m := range methods {
+		if m == "client_secret_post" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+)
+
+// fakeProvider 本地 OIDC 提供商：discovery / jwks / token / userinfo
+type fakeProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    jwk.Key
+
+	// 下一次 token 请求签发的 id_token 声明
+	claims map[string]any
+	// 收到的 token 请求
+	form     map[string]string
+	basicID  string
+	userinfo map[string]any
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+	raw, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	key, err := jwk.FromRaw(raw)
+	require.NoError(t, err)
+	require.NoError(t, key.Set(jwk.KeyIDKey, "k1"))
+	require.NoError(t, key.Set(jwk.AlgorithmKey, jwa.RS256))
+
+	p := &fakeProvider{t: t, key: key, form: map[string]string{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"userinfo_endpoint":      p.server.URL + "/userinfo",
+			"jwks_uri":               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		pub, err := jwk.PublicSetOf(setOf(t, p.key))
+		require.NoError(t, err)
+		writeJSON(w, pub)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		for k := range r.PostForm {
+			p.form[k] = r.PostForm.Get(k)
+		}
+		p.basicID, _, _ = r.BasicAuth()
+		if r.PostForm.Get("code") != "good-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]any{"error": "invalid_grant"})
+			return
+		}
+		writeJSON(w, map[string]any{
+			"access_token": "at-1",
+			"token_type":   "Bearer",
+			"id_token":     p.sign(p.claims),
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer at-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, p.userinfo)
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *fakeProvider) sign(claims map[string]any) string {
+	tok := jwt.New()
+	for k, v := range claims {
+		require.NoError(p.t, tok.Set(k, v))
+	}
+	signed, err := jwt.Sign(tok, jwt.WithKey(jwa.RS256, p.key))
+	require.NoError(p.t, err)
+	return string(signed)
+}
+
+func (p *fakeProvider) baseClaims() map[string]any {
+	now := time.Now()
+	return map[string]any{
+		"iss":   p.server.URL,
+		"sub":   "s-1",
+		"aud":   "iam",
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": "n-1",
+		"email": "alice@example.com",
+	}
+}
+
+func setOf(t *testing.T, key jwk.Key) jwk.Set {
+	set := jwk.NewSet()
+	require.NoError(t, set.AddKey(key))
+	return set
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestClient_ExchangeOIDCCode(t *testing.T) {
+	p := newFakeProvider(t)
+	p.userinfo = map[string]any{"sub": "s-1", "email": "other@example.com", "groups": []any{"iam-admins"}}
+	c := NewClient(p.server.Client())
+	ctx := context.Background()
+
+	req := authentication.OIDCCodeExchange{
+		Issuer:       p.server.URL + "/",
+		ClientID:     "iam",
+		ClientSecret: "secret",
+		Code:         "good-code",
+		RedirectURI:  "https://app.example.com/cb",
+		CodeVerifier: "verifier",
+		Nonce:        "n-1",
+	}
+
+	p.claims = p.baseClaims()
+	claims, err := c.ExchangeOIDCCode(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, "s-1", claims["sub"])
+	// id_token 中的声明优先，userinfo 仅补充缺失的声明
+	require.Equal(t, "alice@example.com", claims["email"])
+	require.Equal(t, []any{"iam-admins"}, claims["groups"])
+	require.Equal(t, "verifier", p.form["code_verifier"])
+	require.Equal(t, "https://app.example.com/cb", p.form["redirect_uri"])
+	require.Equal(t, "iam", p.basicID)
+
+	endpoint, err := c.AuthorizationEndpoint(ctx, p.server.URL)
+	require.NoError(t, err)
+	require.Equal(t, p.server.URL+"/authorize", endpoint)
+
+	tests := []struct {
+		name   string
+		mutate func(claims map[string]any, req *authentication.OIDCCodeExchange)
+	}{
+		{"wrong audience", func(c map[string]any, _ *authentication.OIDCCodeExchange) { c["aud"] = "other" }},
+		{"wrong issuer", func(c map[string]any, _ *authentication.OIDCCodeExchange) { c["iss"] = "https://evil.example.com" }},
+		{"expired", func(c map[string]any, _ *authentication.OIDCCodeExchange) {
+			c["exp"] = time.Now().Add(-time.Hour).Unix()
+		}},
+		{"nonce mismatch", func(c map[string]any, _ *authentication.OIDCCodeExchange) { c["nonce"] = "replayed" }},
+		{"nonce missing", func(c map[string]any, _ *authentication.OIDCCodeExchange) { delete(c, "nonce") }},
+		{"nonce not requested", func(_ map[string]any, r *authentication.OIDCCodeExchange) { r.Nonce = "" }},
+		{"invalid code", func(_ map[string]any, r *authentication.OIDCCodeExchange) { r.Code = "bad-code" }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			claims := p.baseClaims()
+			r := req
+			tc.mutate(claims, &r)
+			p.claims = claims
+			_, err := c.ExchangeOIDCCode(ctx, r)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestClient_RejectsForeignSignature(t *testing.T) {
+	p := newFakeProvider(t)
+	c := NewClient(p.server.Client())
+
+	// 用不在 JWKS 中的密钥签名
+	foreign := newFakeProvider(t)
+	p.claims = p.baseClaims()
+	idToken := foreign.sign(p.claims)
+
+	md, err := c.Discover(context.Background(), p.server.URL)
+	require.NoError(t, err)
+	_, err = c.verifyIDToken(context.Background(), md, p.server.URL, "iam", "", idToken)
+	require.Error(t, err)
+}
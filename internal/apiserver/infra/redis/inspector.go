@@ -85,6 +85,14 @@ func OTPVerifierInspectors(verifier *OTPVerifierImpl) []cacheinfra.FamilyInspect
 	return verifier.FamilyInspectors()
 }
 
+// LoginThrottleInspectors 返回登录节流计数对应的状态读取器。
+func LoginThrottleInspectors(throttle *LoginThrottle) []cacheinfra.FamilyInspector {
+	if throttle == nil {
+		return nil
+	}
+	return throttle.FamilyInspectors()
+}
+
 // AccessTokenCacheInspectors 返回微信 access token 缓存对应的状态读取器。
 func AccessTokenCacheInspectors(cache wechatapp.AccessTokenCache) []cacheinfra.FamilyInspector {
 	typed, ok := cache.(*accessTokenCache)
@@ -14,8 +14,15 @@ var (
 	accountSessionIndexKeyspace   = rediskeyspace.New("account_session_index")
 	otpKeyspace                   = rediskeyspace.New("otp")
 	otpSendGateKeyspace           = otpKeyspace.Child("sendgate")
+	loginThrottleKeyspace         = rediskeyspace.New("login_throttle")
 	wechatAccessTokenKeyspace     = rediskeyspace.New("idp").Child("wechat").Child("token")
 	wechatAccessTokenLockKeyspace = wechatAccessTokenKeyspace.Child("lock")
+	wechatCallbackNonceKeyspace   = rediskeyspace.New("idp").Child("wechat").Child("callback_nonce")
+	oidcAuthorizationKeyspace     = rediskeyspace.New("idp").Child("oidc").Child("authorization")
+	schedulerLeaseKeyspace        = rediskeyspace.New("scheduler").Child("lease")
+	schedulerClaimKeyspace        = rediskeyspace.New("scheduler").Child("claim")
+	samlRequestKeyspace           = rediskeyspace.New("saml").Child("request")
+	samlAssertionKeyspace         = rediskeyspace.New("saml").Child("assertion")
 )
 
 func refreshTokenRedisKey(tokenValue string) string {
@@ -46,6 +53,14 @@ func otpSendGateRedisKey(phoneE164, scene string) string {
 	return otpSendGateKeyspace.Prefix(fmt.Sprintf("%s:%s", scene, phoneE164))
 }
 
+func loginThrottleIPRedisKey(ip string) string {
+	return loginThrottleKeyspace.Prefix("ip:" + ip)
+}
+
+func loginThrottleSubjectRedisKey(kind, value string) string {
+	return loginThrottleKeyspace.Prefix(fmt.Sprintf("%s:%s", kind, value))
+}
+
 func wechatAccessTokenRedisKey(appID string) string {
 	return wechatAccessTokenKeyspace.Prefix(appID)
 }
@@ -53,3 +68,27 @@ func wechatAccessTokenRedisKey(appID string) string {
 func wechatAccessTokenLockRedisKey(appID string) string {
 	return wechatAccessTokenLockKeyspace.Prefix(appID)
 }
+
+func wechatCallbackNonceRedisKey(appID, nonce string) string {
+	return wechatCallbackNonceKeyspace.Prefix(fmt.Sprintf("%s:%s", appID, nonce))
+}
+
+func oidcAuthorizationRedisKey(state string) string {
+	return oidcAuthorizationKeyspace.Prefix(state)
+}
+
+func schedulerLeaseRedisKey(name string) string {
+	return schedulerLeaseKeyspace.Prefix(name)
+}
+
+func schedulerClaimRedisKey(name, key string) string {
+	return schedulerClaimKeyspace.Prefix(fmt.Sprintf("%s:%s", name, key))
+}
+
+func samlRequestRedisKey(requestID string) string {
+	return samlRequestKeyspace.Prefix(requestID)
+}
+
+func samlAssertionRedisKey(assertionID string) string {
+	return samlAssertionKeyspace.Prefix(assertionID)
+}
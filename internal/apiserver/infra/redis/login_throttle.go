@@ -0,0 +1,138 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	cacheinfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/cache"
+)
+
+// LoginThrottle 登录失败计数的 Redis 实现（固定窗口）
+// 每个维度一个计数 key：首次失败时设置窗口 TTL，窗口内累加，到期自动清零
+type LoginThrottle struct {
+	client *redis.Client
+	policy authentication.ThrottlePolicy
+}
+
+var _ authentication.LoginThrottle = (*LoginThrottle)(nil)
+
+// NewLoginThrottle 创建登录节流器；Window 未配置时取 15 分钟
+func NewLoginThrottle(client *redis.Client, policy authentication.ThrottlePolicy) *LoginThrottle {
+	if policy.Window <= 0 {
+		policy.Window = 15 * time.Minute
+	}
+	return &LoginThrottle{client: client, policy: policy}
+}
+
+// FamilyInspectors 返回登录节流缓存族的状态读取器。
+func (t *LoginThrottle) FamilyInspectors() []cacheinfra.FamilyInspector {
+	return []cacheinfra.FamilyInspector{
+		newRedisFamilyInspector(cacheinfra.FamilyAuthnLoginThrottle, t.client, "固定窗口失败计数，INCR + 首次 PEXPIRE。"),
+	}
+}
+
+// Check 读取当前计数并判定
+func (t *LoginThrottle) Check(ctx context.Context, attempt authentication.LoginAttempt) (authentication.ThrottleVerdict, error) {
+	keys := t.keys(attempt)
+	if len(keys) == 0 {
+		return authentication.ThrottleVerdict{}, nil
+	}
+	pipe := t.client.Pipeline()
+	gets := make([]*redis.StringCmd, len(keys))
+	ttls := make([]*redis.DurationCmd, len(keys))
+	for i, key := range keys {
+		gets[i] = pipe.Get(ctx, key)
+		ttls[i] = pipe.PTTL(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return authentication.ThrottleVerdict{}, err
+	}
+
+	counts := make([]int, len(keys))
+	remaining := make([]time.Duration, len(keys))
+	for i := range keys {
+		n, err := gets[i].Int()
+		if err != nil && err != redis.Nil {
+			return authentication.ThrottleVerdict{}, err
+		}
+		counts[i] = n
+		remaining[i] = ttls[i].Val()
+	}
+	return t.evaluate(attempt, counts, remaining), nil
+}
+
+// recordFailureScript 对每个 key 执行 INCR，首次计数时设置窗口 TTL；返回 {count, pttl, ...}
+// 使用脚本而非 EXPIRE NX，以兼容 Redis 7 以下版本
+var recordFailureScript = redis.NewScript(`
+local out = {}
+for i, key in ipairs(KEYS) do
+  local n = redis.call('INCR', key)
+  if n == 1 then
+    redis.call('PEXPIRE', key, ARGV[1])
+  end
+  out[#out + 1] = n
+  out[#out + 1] = redis.call('PTTL', key)
+end
+return out
+`)
+
+// RecordFailure 累加失败计数并判定
+func (t *LoginThrottle) RecordFailure(ctx context.Context, attempt authentication.LoginAttempt) (authentication.ThrottleVerdict, error) {
+	keys := t.keys(attempt)
+	if len(keys) == 0 {
+		return authentication.ThrottleVerdict{}, nil
+	}
+	res, err := recordFailureScript.Run(ctx, t.client, keys, t.policy.Window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return authentication.ThrottleVerdict{}, err
+	}
+
+	counts := make([]int, len(keys))
+	ttls := make([]time.Duration, len(keys))
+	for i := range keys {
+		counts[i] = int(res[2*i])
+		ttls[i] = time.Duration(res[2*i+1]) * time.Millisecond
+	}
+	return t.evaluate(attempt, counts, ttls), nil
+}
+
+// Reset 清除主体计数
+func (t *LoginThrottle) Reset(ctx context.Context, subject authentication.ThrottleSubject) error {
+	if subject.IsZero() {
+		return nil
+	}
+	return t.client.Del(ctx, loginThrottleSubjectRedisKey(string(subject.Kind), subject.Value)).Err()
+}
+
+// keys 返回 [主体 key, IP key]（IP 为空时仅主体）
+func (t *LoginThrottle) keys(attempt authentication.LoginAttempt) []string {
+	keys := make([]string, 0, 2)
+	if !attempt.Subject.IsZero() {
+		keys = append(keys, loginThrottleSubjectRedisKey(string(attempt.Subject.Kind), attempt.Subject.Value))
+	}
+	if attempt.IP != "" {
+		keys = append(keys, loginThrottleIPRedisKey(attempt.IP))
+	}
+	return keys
+}
+
+func (t *LoginThrottle) evaluate(attempt authentication.LoginAttempt, counts []int, ttls []time.Duration) authentication.ThrottleVerdict {
+	var c authentication.ThrottleCounts
+	i := 0
+	if !attempt.Subject.IsZero() {
+		c.SubjectFailures = counts[i]
+		i++
+	}
+	if attempt.IP != "" {
+		c.IPFailures = counts[i]
+	}
+	for _, d := range ttls {
+		if d > c.RetryAfter {
+			c.RetryAfter = d
+		}
+	}
+	return t.policy.Evaluate(attempt.Subject.Kind, c)
+}
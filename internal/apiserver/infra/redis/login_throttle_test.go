@@ -0,0 +1,111 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+)
+
+func TestLoginThrottleRecordFailureAndReset(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	throttle := NewLoginThrottle(client, authentication.ThrottlePolicy{
+		Window:             time.Minute,
+		MaxFailuresPerIP:   10,
+		MaxFailuresPerUser: 3,
+		CaptchaAfter:       2,
+	})
+	ctx := context.Background()
+	attempt := authentication.LoginAttempt{
+		IP:      "10.0.0.1",
+		Subject: authentication.ThrottleSubject{Kind: authentication.ThrottleSubjectUsername, Value: "alice"},
+	}
+
+	verdict, err := throttle.RecordFailure(ctx, attempt)
+	if err != nil {
+		t.Fatalf("RecordFailure() error = %v", err)
+	}
+	if verdict.Blocked || verdict.CaptchaRequired {
+		t.Fatalf("unexpected verdict after first failure: %+v", verdict)
+	}
+	subjectKey := loginThrottleSubjectRedisKey("username", "alice")
+	if ttl := mr.TTL(subjectKey); ttl != time.Minute {
+		t.Fatalf("subject key TTL = %v, want %v", ttl, time.Minute)
+	}
+
+	if verdict, _ = throttle.RecordFailure(ctx, attempt); !verdict.CaptchaRequired || verdict.Blocked {
+		t.Fatalf("expected captcha after second failure, got %+v", verdict)
+	}
+	// 窗口不随后续失败顺延
+	mr.FastForward(10 * time.Second)
+	if verdict, _ = throttle.RecordFailure(ctx, attempt); !verdict.Blocked {
+		t.Fatalf("expected blocked after third failure, got %+v", verdict)
+	}
+	if verdict.RetryAfter <= 0 || verdict.RetryAfter > 50*time.Second {
+		t.Fatalf("RetryAfter = %v, want within remaining window", verdict.RetryAfter)
+	}
+
+	checked, err := throttle.Check(ctx, attempt)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !checked.Blocked {
+		t.Fatalf("expected Check() to report blocked")
+	}
+
+	if err := throttle.Reset(ctx, attempt.Subject); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if mr.Exists(subjectKey) {
+		t.Fatalf("expected subject key %q to be deleted", subjectKey)
+	}
+	// IP 计数保留至窗口结束
+	if !mr.Exists(loginThrottleIPRedisKey("10.0.0.1")) {
+		t.Fatalf("expected IP counter to survive subject reset")
+	}
+	if checked, _ = throttle.Check(ctx, attempt); checked.Blocked {
+		t.Fatalf("expected attempt to pass after reset, got %+v", checked)
+	}
+}
+
+func TestLoginThrottleBlocksByIPAcrossSubjects(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	throttle := NewLoginThrottle(client, authentication.ThrottlePolicy{MaxFailuresPerIP: 2})
+	ctx := context.Background()
+	for _, user := range []string{"alice", "bob"} {
+		if _, err := throttle.RecordFailure(ctx, authentication.LoginAttempt{
+			IP:      "10.0.0.2",
+			Subject: authentication.ThrottleSubject{Kind: authentication.ThrottleSubjectUsername, Value: user},
+		}); err != nil {
+			t.Fatalf("RecordFailure(%s) error = %v", user, err)
+		}
+	}
+
+	verdict, err := throttle.Check(ctx, authentication.LoginAttempt{
+		IP:      "10.0.0.2",
+		Subject: authentication.ThrottleSubject{Kind: authentication.ThrottleSubjectUsername, Value: "carol"},
+	})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !verdict.Blocked {
+		t.Fatalf("expected IP to be blocked across subjects")
+	}
+	if ttl := mr.TTL(loginThrottleIPRedisKey("10.0.0.2")); ttl != 15*time.Minute {
+		t.Fatalf("IP key TTL = %v, want default window", ttl)
+	}
+}
@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/oidcprovider"
+)
+
+// OIDCAuthorizationStore 上游 OIDC 授权请求登记（state → slug、redirect_uri、nonce）
+type OIDCAuthorizationStore struct {
+	client *redis.Client
+}
+
+var _ oidcprovider.AuthorizationStore = (*OIDCAuthorizationStore)(nil)
+
+// NewOIDCAuthorizationStore 创建 OIDC 授权请求 Redis 适配器
+func NewOIDCAuthorizationStore(client *redis.Client) *OIDCAuthorizationStore {
+	return &OIDCAuthorizationStore{client: client}
+}
+
+// SaveAuthorization 登记已发出的授权请求
+func (s *OIDCAuthorizationStore) SaveAuthorization(ctx context.Context, state string, req oidcprovider.AuthorizationRequest, ttl time.Duration) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("encode oidc authorization: %w", err)
+	}
+	if err := s.client.Set(ctx, oidcAuthorizationRedisKey(state), payload, ttl).Err(); err != nil {
+		return fmt.Errorf("save oidc authorization: %w", err)
+	}
+	return nil
+}
+
+// ConsumeAuthorization 使用 GETDEL 原子取出并删除授权登记，保证每个 state 只能登录一次
+func (s *OIDCAuthorizationStore) ConsumeAuthorization(ctx context.Context, state string) (*oidcprovider.AuthorizationRequest, error) {
+	payload, err := s.client.GetDel(ctx, oidcAuthorizationRedisKey(state)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, oidcprovider.ErrAuthorizationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("consume oidc authorization: %w", err)
+	}
+	var req oidcprovider.AuthorizationRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return nil, fmt.Errorf("decode oidc authorization: %w", err)
+	}
+	return &req, nil
+}
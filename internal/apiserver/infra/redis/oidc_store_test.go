@@ -0,0 +1,40 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/oidcprovider"
+)
+
+func TestOIDCAuthorizationStoreConsumeOnce(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	store := NewOIDCAuthorizationStore(client)
+	ctx := context.Background()
+	want := oidcprovider.AuthorizationRequest{Slug: "corp-sso", RedirectURI: "https://app.example.com/cb", Nonce: "n-1"}
+
+	if err := store.SaveAuthorization(ctx, "st-1", want, 10*time.Minute); err != nil {
+		t.Fatalf("SaveAuthorization() error = %v", err)
+	}
+	if ttl := mr.TTL(oidcAuthorizationRedisKey("st-1")); ttl != 10*time.Minute {
+		t.Fatalf("authorization ttl = %v, want 10m", ttl)
+	}
+
+	got, err := store.ConsumeAuthorization(ctx, "st-1")
+	if err != nil || *got != want {
+		t.Fatalf("ConsumeAuthorization() = %+v, %v", got, err)
+	}
+	if _, err := store.ConsumeAuthorization(ctx, "st-1"); !errors.Is(err, oidcprovider.ErrAuthorizationNotFound) {
+		t.Fatalf("second ConsumeAuthorization() error = %v, want ErrAuthorizationNotFound", err)
+	}
+}
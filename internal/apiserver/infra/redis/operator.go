@@ -0,0 +1,219 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	rediskeyspace "github.com/FangcunMount/component-base/pkg/redis/keyspace"
+	redisinfra "github.com/redis/go-redis/v9"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+	cacheinfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/cache"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+const (
+	operatorScanCount = 200
+	operatorDelBatch  = 500
+)
+
+// redisFamilyOperator 按 keyspace 前缀对缓存族执行 SCAN 抽样与删除。
+type redisFamilyOperator struct {
+	family   cacheinfra.Family
+	client   *redisinfra.Client
+	keyspace rediskeyspace.Keyspace
+	// exclude 同前缀下属于其他缓存族的子空间，清理与抽样时跳过
+	exclude []rediskeyspace.Keyspace
+}
+
+func newRedisFamilyOperator(family cacheinfra.Family, client *redisinfra.Client, keyspace rediskeyspace.Keyspace, exclude ...rediskeyspace.Keyspace) cacheinfra.FamilyOperator {
+	return &redisFamilyOperator{
+		family:   family,
+		client:   client,
+		keyspace: keyspace,
+		exclude:  exclude,
+	}
+}
+
+func (o *redisFamilyOperator) Family() cacheinfra.Family {
+	return o.family
+}
+
+func (o *redisFamilyOperator) Purge(ctx context.Context, key string) (int64, error) {
+	if o.client == nil {
+		return 0, fmt.Errorf("redis client not configured")
+	}
+	if key != "" {
+		if !o.owns(key) {
+			return 0, errors.WithCode(code.ErrInvalidArgument, "key %q does not belong to cache family %s", key, o.family)
+		}
+		return o.client.Del(ctx, key).Result()
+	}
+
+	var (
+		deleted int64
+		batch   = make([]string, 0, operatorDelBatch)
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		n, err := o.client.Del(ctx, batch...).Result()
+		deleted += n
+		batch = batch[:0]
+		return err
+	}
+	err := o.scan(ctx, 0, func(k string) error {
+		batch = append(batch, k)
+		if len(batch) >= operatorDelBatch {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	return deleted, err
+}
+
+func (o *redisFamilyOperator) Sample(ctx context.Context, limit int) ([]cacheinfra.KeySample, error) {
+	if o.client == nil {
+		return nil, fmt.Errorf("redis client not configured")
+	}
+	var keys []string
+	if err := o.scan(ctx, limit, func(k string) error {
+		keys = append(keys, k)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return []cacheinfra.KeySample{}, nil
+	}
+
+	pipe := o.client.Pipeline()
+	cmds := make([]*redisinfra.DurationCmd, len(keys))
+	for i, k := range keys {
+		cmds[i] = pipe.PTTL(ctx, k)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redisinfra.Nil {
+		return nil, err
+	}
+
+	samples := make([]cacheinfra.KeySample, 0, len(keys))
+	for i, k := range keys {
+		ttl := cmds[i].Val()
+		// PTTL 返回 -2 表示 key 在抽样期间已过期，-1 表示未设置 TTL
+		if ttl == -2 {
+			continue
+		}
+		sample := cacheinfra.KeySample{Key: k}
+		if ttl < 0 {
+			sample.Persistent = true
+		} else {
+			sample.TTL = ttl
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+// scan 遍历缓存族下的 key，limit > 0 时达到数量即停止。
+func (o *redisFamilyOperator) scan(ctx context.Context, limit int, fn func(string) error) error {
+	var (
+		cursor uint64
+		seen   int
+	)
+	match := o.keyspace.Prefix("*")
+	for {
+		keys, next, err := o.client.Scan(ctx, cursor, match, operatorScanCount).Result()
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if !o.owns(k) {
+				continue
+			}
+			if err := fn(k); err != nil {
+				return err
+			}
+			seen++
+			if limit > 0 && seen >= limit {
+				return nil
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func (o *redisFamilyOperator) owns(key string) bool {
+	if !strings.HasPrefix(key, o.keyspace.Prefix("")) {
+		return false
+	}
+	for _, ks := range o.exclude {
+		if strings.HasPrefix(key, ks.Prefix("")) {
+			return false
+		}
+	}
+	return true
+}
+
+// RedisStoreOperators 返回 RedisStore 对应的缓存族治理操作器。
+func RedisStoreOperators(store *RedisStore) []cacheinfra.FamilyOperator {
+	if store == nil {
+		return nil
+	}
+	return []cacheinfra.FamilyOperator{
+		newRedisFamilyOperator(cacheinfra.FamilyAuthnRefreshToken, store.client, refreshTokenKeyspace),
+		newRedisFamilyOperator(cacheinfra.FamilyAuthnRevokedAccessToken, store.client, revokedAccessTokenKeyspace),
+	}
+}
+
+// SessionStoreOperators 返回 SessionStore 对应的缓存族治理操作器。
+func SessionStoreOperators(store *SessionStore) []cacheinfra.FamilyOperator {
+	if store == nil {
+		return nil
+	}
+	return []cacheinfra.FamilyOperator{
+		newRedisFamilyOperator(cacheinfra.FamilyAuthnSession, store.client, sessionKeyspace),
+		newRedisFamilyOperator(cacheinfra.FamilyAuthnUserSessionIndex, store.client, userSessionIndexKeyspace),
+		newRedisFamilyOperator(cacheinfra.FamilyAuthnAccountSessionIndex, store.client, accountSessionIndexKeyspace),
+	}
+}
+
+// OTPVerifierOperators 返回 OTP 适配器对应的缓存族治理操作器。
+func OTPVerifierOperators(verifier *OTPVerifierImpl) []cacheinfra.FamilyOperator {
+	if verifier == nil {
+		return nil
+	}
+	return []cacheinfra.FamilyOperator{
+		newRedisFamilyOperator(cacheinfra.FamilyAuthnLoginOTP, verifier.client, otpKeyspace, otpSendGateKeyspace),
+		newRedisFamilyOperator(cacheinfra.FamilyAuthnLoginOTPSendGate, verifier.client, otpSendGateKeyspace),
+	}
+}
+
+// LoginThrottleOperators 返回登录节流计数对应的缓存族治理操作器。
+func LoginThrottleOperators(throttle *LoginThrottle) []cacheinfra.FamilyOperator {
+	if throttle == nil {
+		return nil
+	}
+	return []cacheinfra.FamilyOperator{
+		newRedisFamilyOperator(cacheinfra.FamilyAuthnLoginThrottle, throttle.client, loginThrottleKeyspace),
+	}
+}
+
+// AccessTokenCacheOperators 返回微信 access token 缓存对应的治理操作器（不含刷新 lease）。
+func AccessTokenCacheOperators(cache wechatapp.AccessTokenCache) []cacheinfra.FamilyOperator {
+	typed, ok := cache.(*accessTokenCache)
+	if !ok || typed == nil {
+		return nil
+	}
+	return []cacheinfra.FamilyOperator{
+		newRedisFamilyOperator(cacheinfra.FamilyIDPWechatAccessToken, typed.client, wechatAccessTokenKeyspace, wechatAccessTokenLockKeyspace),
+	}
+}
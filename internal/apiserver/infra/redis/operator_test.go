@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+
+	cacheinfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/cache"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+func TestRedisFamilyOperatorPurgeAndSample(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	ctx := context.Background()
+	mr.Set(otpRedisKey("+8613800000000", "login", "123456"), "1")
+	mr.SetTTL(otpRedisKey("+8613800000000", "login", "123456"), time.Minute)
+	mr.Set(otpRedisKey("+8613800000001", "login", "654321"), "1")
+	mr.Set(otpSendGateRedisKey("+8613800000000", "login"), "1")
+
+	operators := OTPVerifierOperators(NewOTPVerifier(client))
+	if len(operators) != 2 || operators[0].Family() != cacheinfra.FamilyAuthnLoginOTP {
+		t.Fatalf("unexpected operators %v", operators)
+	}
+	otp := operators[0]
+
+	samples, err := otp.Sample(ctx, 10)
+	if err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("sample count = %d, want 2 (send gate keys excluded)", len(samples))
+	}
+	for _, s := range samples {
+		if s.Key == otpRedisKey("+8613800000000", "login", "123456") && (s.Persistent || s.TTL <= 0) {
+			t.Fatalf("expected ttl on sampled key, got %+v", s)
+		}
+	}
+
+	if _, err := otp.Purge(ctx, "session:abc"); !errors.IsCode(err, code.ErrInvalidArgument) {
+		t.Fatalf("Purge(foreign key) error = %v, want invalid argument", err)
+	}
+	n, err := otp.Purge(ctx, otpRedisKey("+8613800000001", "login", "654321"))
+	if err != nil || n != 1 {
+		t.Fatalf("Purge(key) = %d, %v; want 1, nil", n, err)
+	}
+
+	n, err = otp.Purge(ctx, "")
+	if err != nil || n != 1 {
+		t.Fatalf("Purge(family) = %d, %v; want 1, nil", n, err)
+	}
+	if !mr.Exists(otpSendGateRedisKey("+8613800000000", "login")) {
+		t.Fatal("send gate key should survive OTP family purge")
+	}
+}
@@ -0,0 +1,71 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	redisstore "github.com/FangcunMount/component-base/pkg/redis/store"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/saml"
+)
+
+// SAMLStore SAML SP 的 AuthnRequest 登记与断言防重放缓存
+type SAMLStore struct {
+	client     *redis.Client
+	assertions *redisstore.ValueStore[string]
+	now        func() time.Time
+}
+
+var (
+	_ saml.RequestStore = (*SAMLStore)(nil)
+	_ saml.ReplayCache  = (*SAMLStore)(nil)
+)
+
+// NewSAMLStore 创建 SAML Redis 适配器
+func NewSAMLStore(client *redis.Client) *SAMLStore {
+	return &SAMLStore{
+		client:     client,
+		assertions: newStringStore(client),
+		now:        time.Now,
+	}
+}
+
+// SaveRequest 登记已发出的 AuthnRequest，值为 IdP slug
+func (s *SAMLStore) SaveRequest(ctx context.Context, requestID, slug string, ttl time.Duration) error {
+	if err := s.client.Set(ctx, samlRequestRedisKey(requestID), slug, ttl).Err(); err != nil {
+		return fmt.Errorf("save saml request: %w", err)
+	}
+	return nil
+}
+
+// ConsumeRequest 使用 GETDEL 原子取出并删除请求登记，保证每个请求只能被回应一次
+func (s *SAMLStore) ConsumeRequest(ctx context.Context, requestID string) (string, error) {
+	slug, err := s.client.GetDel(ctx, samlRequestRedisKey(requestID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", saml.ErrRequestNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("consume saml request: %w", err)
+	}
+	return slug, nil
+}
+
+// MarkAssertion 使用 SET NX 记录断言 ID，保留到断言失效为止
+func (s *SAMLStore) MarkAssertion(ctx context.Context, assertionID string, until time.Time) (bool, error) {
+	ttl := until.Sub(s.now())
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	storeKey, err := newStoreKey(samlAssertionRedisKey(assertionID))
+	if err != nil {
+		return false, fmt.Errorf("saml replay cache: %w", err)
+	}
+	ok, err := s.assertions.SetIfAbsent(ctx, storeKey, "1", ttl)
+	if err != nil {
+		return false, fmt.Errorf("saml replay cache: %w", err)
+	}
+	return ok, nil
+}
@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/saml"
+)
+
+func TestSAMLStoreConsumeRequestOnce(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	store := NewSAMLStore(client)
+	ctx := context.Background()
+
+	if err := store.SaveRequest(ctx, "_req1", "city-hospital", time.Minute); err != nil {
+		t.Fatalf("SaveRequest() error = %v", err)
+	}
+	if ttl := mr.TTL(samlRequestRedisKey("_req1")); ttl != time.Minute {
+		t.Fatalf("request ttl = %v, want 1m", ttl)
+	}
+
+	slug, err := store.ConsumeRequest(ctx, "_req1")
+	if err != nil || slug != "city-hospital" {
+		t.Fatalf("ConsumeRequest() = %q, %v", slug, err)
+	}
+	if _, err := store.ConsumeRequest(ctx, "_req1"); !errors.Is(err, saml.ErrRequestNotFound) {
+		t.Fatalf("second ConsumeRequest() error = %v, want ErrRequestNotFound", err)
+	}
+}
+
+func TestSAMLStoreMarkAssertion(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	store := NewSAMLStore(client)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	first, err := store.MarkAssertion(ctx, "_a1", now.Add(5*time.Minute))
+	if err != nil || !first {
+		t.Fatalf("first MarkAssertion() = %v, %v", first, err)
+	}
+	if ttl := mr.TTL(samlAssertionRedisKey("_a1")); ttl != 5*time.Minute {
+		t.Fatalf("assertion ttl = %v, want 5m", ttl)
+	}
+	again, err := store.MarkAssertion(ctx, "_a1", now.Add(5*time.Minute))
+	if err != nil || again {
+		t.Fatalf("replayed MarkAssertion() = %v, %v", again, err)
+	}
+
+	mr.FastForward(5 * time.Minute)
+	afterExpiry, err := store.MarkAssertion(ctx, "_a1", now.Add(10*time.Minute))
+	if err != nil || !afterExpiry {
+		t.Fatalf("MarkAssertion() after expiry = %v, %v", afterExpiry, err)
+	}
+}
@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	redislease "github.com/FangcunMount/component-base/pkg/redis/lease"
+	"github.com/redis/go-redis/v9"
+)
+
+// SchedulerLease 基于 Redis 租约的调度领导权，多个 apiserver 副本竞争同一 name。
+type SchedulerLease struct {
+	name   string
+	ttl    time.Duration
+	leases *redislease.Service
+
+	mu   sync.Mutex
+	held *redislease.Lease
+}
+
+// NewSchedulerLease 创建调度租约；ttl 为租约有效期，持有者需在到期前续约。
+func NewSchedulerLease(client *redis.Client, name string, ttl time.Duration) *SchedulerLease {
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return &SchedulerLease{
+		name:   name,
+		ttl:    ttl,
+		leases: newLeaseService(client),
+	}
+}
+
+// Acquire 获取租约；已持有时续约，续约失败（租约过期被他人抢占）则重新竞争。
+func (l *SchedulerLease) Acquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held != nil {
+		renewed, err := l.leases.Renew(ctx, *l.held, l.ttl)
+		if err == nil {
+			l.held = &renewed
+			return true, nil
+		}
+		if !errors.Is(err, redislease.ErrLeaseLost) {
+			return false, fmt.Errorf("renew scheduler lease: %w", err)
+		}
+		l.held = nil
+	}
+
+	leaseKey, err := newLeaseKey(schedulerLeaseRedisKey(l.name))
+	if err != nil {
+		return false, err
+	}
+	attempt, err := l.leases.Acquire(ctx, leaseKey, l.ttl, nil)
+	if err != nil {
+		return false, fmt.Errorf("acquire scheduler lease: %w", err)
+	}
+	if !attempt.Acquired {
+		return false, nil
+	}
+	held := attempt.Lease
+	l.held = &held
+	return true, nil
+}
+
+// Release 释放自己持有的租约，便于其它副本尽快接管。
+func (l *SchedulerLease) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held == nil {
+		return nil
+	}
+	held := *l.held
+	l.held = nil
+	return l.leases.Release(ctx, held)
+}
+
+// ClaimOnce 以 key 声明一次性执行，ttl 内其它声明返回 false。
+func (l *SchedulerLease) ClaimOnce(ctx context.Context, key string, ttl time.Duration) (bool, func(), error) {
+	leaseKey, err := newLeaseKey(schedulerClaimRedisKey(l.name, key))
+	if err != nil {
+		return false, nil, err
+	}
+	attempt, err := l.leases.Acquire(ctx, leaseKey, ttl, nil)
+	if err != nil {
+		return false, nil, fmt.Errorf("claim %s: %w", key, err)
+	}
+	if !attempt.Acquired {
+		return false, func() {}, nil
+	}
+	claim := attempt.Lease
+	release := func() {
+		_ = l.leases.Release(context.Background(), claim)
+	}
+	return true, release, nil
+}
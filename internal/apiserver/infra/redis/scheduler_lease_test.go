@@ -0,0 +1,93 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func TestSchedulerLeaseSingleLeader(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	ctx := context.Background()
+	a := NewSchedulerLease(client, "jwks_rotation", 30*time.Second)
+	b := NewSchedulerLease(client, "jwks_rotation", 30*time.Second)
+
+	if ok, err := a.Acquire(ctx); err != nil || !ok {
+		t.Fatalf("a.Acquire() = %v, %v; want true", ok, err)
+	}
+	if ok, err := b.Acquire(ctx); err != nil || ok {
+		t.Fatalf("b.Acquire() = %v, %v; want false while a leads", ok, err)
+	}
+	// 持有者续约仍为 leader
+	if ok, err := a.Acquire(ctx); err != nil || !ok {
+		t.Fatalf("a renew = %v, %v; want true", ok, err)
+	}
+
+	if err := a.Release(ctx); err != nil {
+		t.Fatalf("a.Release() error = %v", err)
+	}
+	if ok, err := b.Acquire(ctx); err != nil || !ok {
+		t.Fatalf("b.Acquire() after release = %v, %v; want true", ok, err)
+	}
+}
+
+func TestSchedulerLeaseTakeoverAfterExpiry(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	ctx := context.Background()
+	a := NewSchedulerLease(client, "jwks_rotation", 10*time.Second)
+	b := NewSchedulerLease(client, "jwks_rotation", 10*time.Second)
+
+	if ok, _ := a.Acquire(ctx); !ok {
+		t.Fatal("a should acquire the lease")
+	}
+	mr.FastForward(11 * time.Second)
+	if ok, err := b.Acquire(ctx); err != nil || !ok {
+		t.Fatalf("b.Acquire() after expiry = %v, %v; want true", ok, err)
+	}
+	// 原 leader 续约失败后不再持有租约
+	if ok, err := a.Acquire(ctx); err != nil || ok {
+		t.Fatalf("a.Acquire() after takeover = %v, %v; want false", ok, err)
+	}
+}
+
+func TestSchedulerLeaseClaimOnce(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	ctx := context.Background()
+	a := NewSchedulerLease(client, "jwks_rotation", 30*time.Second)
+	b := NewSchedulerLease(client, "jwks_rotation", 30*time.Second)
+
+	claimed, release, err := a.ClaimOnce(ctx, "window:1", time.Hour)
+	if err != nil || !claimed {
+		t.Fatalf("a.ClaimOnce() = %v, %v; want true", claimed, err)
+	}
+	if claimed, _, err := b.ClaimOnce(ctx, "window:1", time.Hour); err != nil || claimed {
+		t.Fatalf("b.ClaimOnce() same window = %v, %v; want false", claimed, err)
+	}
+	if claimed, _, err := b.ClaimOnce(ctx, "window:2", time.Hour); err != nil || !claimed {
+		t.Fatalf("b.ClaimOnce() next window = %v, %v; want true", claimed, err)
+	}
+
+	// 撤回声明后允许重试
+	release()
+	if claimed, _, err := b.ClaimOnce(ctx, "window:1", time.Hour); err != nil || !claimed {
+		t.Fatalf("b.ClaimOnce() after release = %v, %v; want true", claimed, err)
+	}
+}
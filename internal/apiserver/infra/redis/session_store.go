@@ -123,6 +123,42 @@ func (s *SessionStore) RevokeByAccount(ctx context.Context, accountID meta.ID, r
 	return s.revokeByIndex(ctx, accountSessionIndexRedisKey(accountID.String()), reason, revokedBy)
 }
 
+// ListByUser 列出指定用户下的活跃会话。
+func (s *SessionStore) ListByUser(ctx context.Context, userID meta.ID) ([]*sessiondomain.Session, error) {
+	return s.listByIndex(ctx, userSessionIndexRedisKey(userID.String()))
+}
+
+// ListByAccount 列出指定账号下的活跃会话。
+func (s *SessionStore) ListByAccount(ctx context.Context, accountID meta.ID) ([]*sessiondomain.Session, error) {
+	return s.listByIndex(ctx, accountSessionIndexRedisKey(accountID.String()))
+}
+
+func (s *SessionStore) listByIndex(ctx context.Context, indexKey string) ([]*sessiondomain.Session, error) {
+	if s == nil || s.client == nil {
+		return nil, fmt.Errorf("redis client is nil")
+	}
+	if err := s.removeExpiredIndexMembers(ctx, indexKey); err != nil {
+		return nil, err
+	}
+	sessionIDs, err := s.client.ZRange(ctx, indexKey, 0, -1).Result()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("list indexed sessions: %w", err)
+	}
+	sessions := make([]*sessiondomain.Session, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		sess, err := s.Get(ctx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		// 主对象已过期或被撤销但索引尚未清理时跳过
+		if !sess.IsActive() {
+			continue
+		}
+		sessions = append(sessions, sess)
+	}
+	return sessions, nil
+}
+
 func (s *SessionStore) revokeByIndex(ctx context.Context, indexKey string, reason string, revokedBy string) error {
 	if err := s.removeExpiredIndexMembers(ctx, indexKey); err != nil {
 		return err
@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+
+	sessiondomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/session"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+func TestSessionStoreListByUserSkipsRevokedSessions(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	store := NewSessionStore(client)
+	ctx := context.Background()
+	userID := meta.FromUint64(1001)
+	expiresAt := time.Now().Add(time.Hour)
+
+	phone := sessiondomain.New("sess-phone", userID, meta.FromUint64(2001), meta.FromUint64(1), []string{"otp"}, nil, expiresAt)
+	phone.ClientIP = "10.0.0.1"
+	phone.UserAgent = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) MicroMessenger/8.0"
+	phone.DeviceName = "iPhone · 微信"
+	laptop := sessiondomain.New("sess-laptop", userID, meta.FromUint64(2002), meta.FromUint64(1), []string{"pwd"}, nil, expiresAt)
+	other := sessiondomain.New("sess-other", meta.FromUint64(1002), meta.FromUint64(2003), meta.FromUint64(1), []string{"pwd"}, nil, expiresAt)
+	for _, sess := range []*sessiondomain.Session{phone, laptop, other} {
+		if err := store.Save(ctx, sess); err != nil {
+			t.Fatalf("Save(%s) error = %v", sess.SessionID, err)
+		}
+	}
+	if err := store.Revoke(ctx, laptop.SessionID, "test", "tester"); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+
+	sessions, err := store.ListByUser(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListByUser() error = %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].SessionID != phone.SessionID {
+		t.Fatalf("ListByUser() = %+v, want only %s", sessions, phone.SessionID)
+	}
+	got := sessions[0]
+	if got.ClientIP != phone.ClientIP || got.UserAgent != phone.UserAgent || got.DeviceName != phone.DeviceName {
+		t.Fatalf("device metadata not persisted: %+v", got)
+	}
+
+	byAccount, err := store.ListByAccount(ctx, other.AccountID)
+	if err != nil {
+		t.Fatalf("ListByAccount() error = %v", err)
+	}
+	if len(byAccount) != 1 || byAccount[0].SessionID != other.SessionID {
+		t.Fatalf("ListByAccount() = %+v, want only %s", byAccount, other.SessionID)
+	}
+}
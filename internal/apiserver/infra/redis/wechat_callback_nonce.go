@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	redisstore "github.com/FangcunMount/component-base/pkg/redis/store"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+)
+
+// WechatCallbackNonceCache 微信回调 nonce 防重放缓存
+type WechatCallbackNonceCache struct {
+	nonces *redisstore.ValueStore[string]
+}
+
+var _ wechatapp.CallbackNonceCache = (*WechatCallbackNonceCache)(nil)
+
+// NewWechatCallbackNonceCache 创建微信回调 nonce 缓存
+func NewWechatCallbackNonceCache(client *redis.Client) *WechatCallbackNonceCache {
+	return &WechatCallbackNonceCache{nonces: newStringStore(client)}
+}
+
+// MarkNonce 使用 SET NX 记录 nonce，窗口期内重复出现即视为重放
+func (c *WechatCallbackNonceCache) MarkNonce(ctx context.Context, appID, nonce string, ttl time.Duration) (bool, error) {
+	if appID == "" || nonce == "" {
+		return false, errors.New("appID and nonce cannot be empty")
+	}
+	storeKey, err := newStoreKey(wechatCallbackNonceRedisKey(appID, nonce))
+	if err != nil {
+		return false, fmt.Errorf("wechat callback nonce cache: %w", err)
+	}
+	ok, err := c.nonces.SetIfAbsent(ctx, storeKey, "1", ttl)
+	if err != nil {
+		return false, fmt.Errorf("wechat callback nonce cache: %w", err)
+	}
+	return ok, nil
+}
+
+// ReleaseNonce 删除 nonce 记录
+func (c *WechatCallbackNonceCache) ReleaseNonce(ctx context.Context, appID, nonce string) error {
+	if appID == "" || nonce == "" {
+		return errors.New("appID and nonce cannot be empty")
+	}
+	storeKey, err := newStoreKey(wechatCallbackNonceRedisKey(appID, nonce))
+	if err != nil {
+		return fmt.Errorf("wechat callback nonce cache: %w", err)
+	}
+	if err := c.nonces.Delete(ctx, storeKey); err != nil {
+		return fmt.Errorf("wechat callback nonce cache: %w", err)
+	}
+	return nil
+}
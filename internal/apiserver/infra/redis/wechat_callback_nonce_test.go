@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func TestWechatCallbackNonceCacheMarkNonce(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	cache := NewWechatCallbackNonceCache(client)
+	ctx := context.Background()
+
+	first, err := cache.MarkNonce(ctx, "wx-mp", "n1", 10*time.Minute)
+	if err != nil || !first {
+		t.Fatalf("first MarkNonce() = %v, %v", first, err)
+	}
+	if ttl := mr.TTL(wechatCallbackNonceRedisKey("wx-mp", "n1")); ttl != 10*time.Minute {
+		t.Fatalf("nonce ttl = %v, want 10m", ttl)
+	}
+	again, err := cache.MarkNonce(ctx, "wx-mp", "n1", 10*time.Minute)
+	if err != nil || again {
+		t.Fatalf("replayed MarkNonce() = %v, %v, want false", again, err)
+	}
+	other, err := cache.MarkNonce(ctx, "wx-other", "n1", 10*time.Minute)
+	if err != nil || !other {
+		t.Fatalf("other app MarkNonce() = %v, %v, want true", other, err)
+	}
+}
+
+func TestWechatCallbackNonceCacheReleaseNonce(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	cache := NewWechatCallbackNonceCache(client)
+	ctx := context.Background()
+
+	if _, err := cache.MarkNonce(ctx, "wx-mp", "n1", 10*time.Minute); err != nil {
+		t.Fatalf("MarkNonce() error = %v", err)
+	}
+	if err := cache.ReleaseNonce(ctx, "wx-mp", "n1"); err != nil {
+		t.Fatalf("ReleaseNonce() error = %v", err)
+	}
+	retry, err := cache.MarkNonce(ctx, "wx-mp", "n1", 10*time.Minute)
+	if err != nil || !retry {
+		t.Fatalf("MarkNonce() after release = %v, %v, want true", retry, err)
+	}
+}
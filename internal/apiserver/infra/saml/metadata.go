@@ -0,0 +1,100 @@
+package saml
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/saml"
+)
+
+type idpEntityDescriptor struct {
+	XMLName  xml.Name           `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+	EntityID string             `xml:"entityID,attr"`
+	IdPSSO   []idpSSODescriptor `xml:"urn:oasis:names:tc:SAML:2.0:metadata IDPSSODescriptor"`
+}
+
+type idpSSODescriptor struct {
+	KeyDescriptors []idpKeyDescriptor `xml:"urn:oasis:names:tc:SAML:2.0:metadata KeyDescriptor"`
+	SSOServices    []idpEndpoint      `xml:"urn:oasis:names:tc:SAML:2.0:metadata SingleSignOnService"`
+}
+
+type idpKeyDescriptor struct {
+	Use     string `xml:"use,attr"`
+	KeyInfo struct {
+		X509Data struct {
+			Certificates []string `xml:"http://www.w3.org/2000/09/xmldsig# X509Certificate"`
+		} `xml:"http://www.w3.org/2000/09/xmldsig# X509Data"`
+	} `xml:"http://www.w3.org/2000/09/xmldsig# KeyInfo"`
+}
+
+type idpEndpoint struct {
+	Binding  string `xml:"Binding,attr"`
+	Location string `xml:"Location,attr"`
+}
+
+// ParseIdPMetadata 解析 IdP 元数据：EntityID、HTTP-Redirect 绑定的 SSO 地址与签名证书
+func (sp *ServiceProvider) ParseIdPMetadata(raw []byte) (domain.IdPMetadata, error) {
+	return ParseIdPMetadata(raw)
+}
+
+// ParseIdPMetadata 解析 IdP 元数据（仅支持单个 EntityDescriptor）
+func ParseIdPMetadata(raw []byte) (domain.IdPMetadata, error) {
+	if hasDoctype(raw) {
+		return domain.IdPMetadata{}, errors.New("idp metadata must not contain a DOCTYPE")
+	}
+	var ed idpEntityDescriptor
+	if err := xml.Unmarshal(raw, &ed); err != nil {
+		return domain.IdPMetadata{}, fmt.Errorf("parse idp metadata: %w", err)
+	}
+	if ed.EntityID == "" {
+		return domain.IdPMetadata{}, errors.New("idp metadata has no entityID")
+	}
+	if len(ed.IdPSSO) == 0 {
+		return domain.IdPMetadata{}, errors.New("idp metadata has no IDPSSODescriptor")
+	}
+
+	md := domain.IdPMetadata{EntityID: ed.EntityID}
+	seen := make(map[string]struct{})
+	for _, desc := range ed.IdPSSO {
+		for _, svc := range desc.SSOServices {
+			if md.SSOURL == "" && svc.Binding == bindingHTTPRedirect {
+				md.SSOURL = svc.Location
+			}
+		}
+		for _, kd := range desc.KeyDescriptors {
+			if kd.Use != "" && kd.Use != "signing" {
+				continue
+			}
+			for _, c := range kd.KeyInfo.X509Data.Certificates {
+				c = strings.Join(strings.Fields(c), "")
+				der, err := base64.StdEncoding.DecodeString(c)
+				if err != nil {
+					return domain.IdPMetadata{}, fmt.Errorf("decode idp certificate: %w", err)
+				}
+				if _, err := x509.ParseCertificate(der); err != nil {
+					return domain.IdPMetadata{}, fmt.Errorf("parse idp certificate: %w", err)
+				}
+				if _, ok := seen[c]; !ok {
+					seen[c] = struct{}{}
+					md.Certificates = append(md.Certificates, c)
+				}
+			}
+		}
+	}
+	if md.SSOURL == "" {
+		return domain.IdPMetadata{}, errors.New("idp metadata has no HTTP-Redirect SingleSignOnService")
+	}
+	if len(md.Certificates) == 0 {
+		return domain.IdPMetadata{}, errors.New("idp metadata has no signing certificate")
+	}
+	return md, nil
+}
+
+func hasDoctype(raw []byte) bool {
+	return bytes.Contains(bytes.ToUpper(raw), []byte("<!DOCTYPE"))
+}
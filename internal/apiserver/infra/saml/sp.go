@@ -0,0 +1,251 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/saml"
+)
+
+// SAML 协议常量
+const (
+	nsMetadata  = "urn:oasis:names:tc:SAML:2.0:metadata"
+	nsAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+	nsProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	nsDSig      = "http://www.w3.org/2000/09/xmldsig#"
+
+	bindingHTTPRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	bindingHTTPPost     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	statusSuccess       = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	methodBearer        = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	nameIDUnspecified   = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+	sigAlgRSASHA256     = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+
+	// maxRelayStateBytes SAML Bindings 3.4.3 对 RelayState 的长度上限
+	maxRelayStateBytes = 80
+)
+
+// Config SP 配置
+type Config struct {
+	// EntityID 本系统的 SP EntityID，也是断言 Audience 的期望值
+	EntityID string
+	// BaseURL 对外可访问的 authn 接口前缀，如 https://iam.example.com/api/v1
+	BaseURL string
+	// Certificate / PrivateKey SP 签名证书与私钥（RSA）
+	Certificate *x509.Certificate
+	PrivateKey  *rsa.PrivateKey
+	// ClockSkew 断言时间条件允许的时钟偏差，零值使用默认值
+	ClockSkew time.Duration
+	// RequestTTL AuthnRequest 等待回传的有效期，零值使用默认值
+	RequestTTL time.Duration
+}
+
+// LoadKeyPair 从 PEM 文件加载 SP 签名证书与 RSA 私钥
+func LoadKeyPair(certFile, keyFile string) (*x509.Certificate, *rsa.PrivateKey, error) {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load saml sp key pair: %w", err)
+	}
+	key, ok := pair.PrivateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, nil, errors.New("saml sp private key must be RSA")
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse saml sp certificate: %w", err)
+	}
+	return cert, key, nil
+}
+
+// ServiceProvider SAML 2.0 SP 实现
+// 职责：SP 元数据、IdP 元数据解析、签名 AuthnRequest 与 SAMLResponse 校验
+type ServiceProvider struct {
+	cfg      Config
+	requests domain.RequestStore
+	replay   domain.ReplayCache
+	now      func() time.Time
+}
+
+var (
+	_ domain.ServiceProvider               = (*ServiceProvider)(nil)
+	_ authentication.SAMLAssertionVerifier = (*ServiceProvider)(nil)
+)
+
+// NewServiceProvider 创建 SAML SP
+func NewServiceProvider(cfg Config, requests domain.RequestStore, replay domain.ReplayCache) (*ServiceProvider, error) {
+	if cfg.EntityID == "" || cfg.BaseURL == "" {
+		return nil, errors.New("saml sp entity_id and base_url are required")
+	}
+	if cfg.Certificate == nil || cfg.PrivateKey == nil {
+		return nil, errors.New("saml sp signing certificate and key are required")
+	}
+	if requests == nil || replay == nil {
+		return nil, errors.New("saml sp requires request store and replay cache")
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.ClockSkew <= 0 {
+		cfg.ClockSkew = domain.DefaultClockSkew
+	}
+	if cfg.RequestTTL <= 0 {
+		cfg.RequestTTL = domain.DefaultRequestTTL
+	}
+	return &ServiceProvider{cfg: cfg, requests: requests, replay: replay, now: time.Now}, nil
+}
+
+// ACSURL 指定 IdP 登记的断言消费地址
+func (sp *ServiceProvider) ACSURL(slug string) string {
+	return sp.cfg.BaseURL + "/authn/saml/" + url.PathEscape(slug) + "/acs"
+}
+
+// ==================== SP 元数据 ====================
+
+type spEntityDescriptor struct {
+	XMLName  xml.Name        `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+	EntityID string          `xml:"entityID,attr"`
+	SSO      spSSODescriptor `xml:"SPSSODescriptor"`
+}
+
+type spSSODescriptor struct {
+	AuthnRequestsSigned  bool              `xml:"AuthnRequestsSigned,attr"`
+	WantAssertionsSigned bool              `xml:"WantAssertionsSigned,attr"`
+	Protocol             string            `xml:"protocolSupportEnumeration,attr"`
+	KeyDescriptor        spKeyDescriptor   `xml:"KeyDescriptor"`
+	NameIDFormat         string            `xml:"NameIDFormat"`
+	ACS                  []spIndexEndpoint `xml:"AssertionConsumerService"`
+}
+
+type spKeyDescriptor struct {
+	Use     string    `xml:"use,attr"`
+	KeyInfo spKeyInfo `xml:"http://www.w3.org/2000/09/xmldsig# KeyInfo"`
+}
+
+type spKeyInfo struct {
+	Certificate string `xml:"X509Data>X509Certificate"`
+}
+
+type spIndexEndpoint struct {
+	Binding   string `xml:"Binding,attr"`
+	Location  string `xml:"Location,attr"`
+	Index     int    `xml:"index,attr"`
+	IsDefault bool   `xml:"isDefault,attr"`
+}
+
+// Metadata 返回 SP 元数据；每个 IdP 登记使用独立的 ACS 地址
+func (sp *ServiceProvider) Metadata(slug string) ([]byte, error) {
+	md := spEntityDescriptor{
+		EntityID: sp.cfg.EntityID,
+		SSO: spSSODescriptor{
+			AuthnRequestsSigned:  true,
+			WantAssertionsSigned: true,
+			Protocol:             nsProtocol,
+			KeyDescriptor: spKeyDescriptor{
+				Use:     "signing",
+				KeyInfo: spKeyInfo{Certificate: base64.StdEncoding.EncodeToString(sp.cfg.Certificate.Raw)},
+			},
+			NameIDFormat: nameIDUnspecified,
+			ACS: []spIndexEndpoint{{
+				Binding:   bindingHTTPPost,
+				Location:  sp.ACSURL(slug),
+				IsDefault: true,
+			}},
+		},
+	}
+	out, err := xml.MarshalIndent(md, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// ==================== AuthnRequest ====================
+
+// AuthnRequestURL 生成签名的 HTTP-Redirect AuthnRequest 地址
+func (sp *ServiceProvider) AuthnRequestURL(ctx context.Context, idp *domain.IdentityProvider, relayState string) (string, error) {
+	if len(relayState) > maxRelayStateBytes {
+		return "", fmt.Errorf("relay state exceeds %d bytes", maxRelayStateBytes)
+	}
+	requestID, err := newRequestID()
+	if err != nil {
+		return "", err
+	}
+
+	doc := etree.NewDocument()
+	req := doc.CreateElement("samlp:AuthnRequest")
+	req.CreateAttr("xmlns:samlp", nsProtocol)
+	req.CreateAttr("xmlns:saml", nsAssertion)
+	req.CreateAttr("ID", requestID)
+	req.CreateAttr("Version", "2.0")
+	req.CreateAttr("IssueInstant", sp.now().UTC().Format(time.RFC3339))
+	req.CreateAttr("Destination", idp.SSOURL)
+	req.CreateAttr("ProtocolBinding", bindingHTTPPost)
+	req.CreateAttr("AssertionConsumerServiceURL", sp.ACSURL(idp.Slug))
+	req.CreateElement("saml:Issuer").SetText(sp.cfg.EntityID)
+	policy := req.CreateElement("samlp:NameIDPolicy")
+	policy.CreateAttr("Format", nameIDUnspecified)
+	policy.CreateAttr("AllowCreate", "true")
+	raw, err := doc.WriteToBytes()
+	if err != nil {
+		return "", err
+	}
+
+	// HTTP-Redirect 绑定：DEFLATE -> Base64 -> URL 编码，签名覆盖按固定顺序拼接的查询串
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(raw); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	query := "SAMLRequest=" + url.QueryEscape(base64.StdEncoding.EncodeToString(buf.Bytes()))
+	if relayState != "" {
+		query += "&RelayState=" + url.QueryEscape(relayState)
+	}
+	query += "&SigAlg=" + url.QueryEscape(sigAlgRSASHA256)
+	digest := sha256.Sum256([]byte(query))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, sp.cfg.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign authn request: %w", err)
+	}
+	query += "&Signature=" + url.QueryEscape(base64.StdEncoding.EncodeToString(sig))
+
+	if err := sp.requests.SaveRequest(ctx, requestID, idp.Slug, sp.cfg.RequestTTL); err != nil {
+		return "", fmt.Errorf("save authn request: %w", err)
+	}
+
+	sep := "?"
+	if strings.Contains(idp.SSOURL, "?") {
+		sep = "&"
+	}
+	return idp.SSOURL + sep + query, nil
+}
+
+// newRequestID 生成 AuthnRequest ID（xs:ID 不能以数字开头）
+func newRequestID() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "_" + hex.EncodeToString(b), nil
+}
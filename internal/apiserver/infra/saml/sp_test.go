@@ -0,0 +1,378 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/saml"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+const (
+	testSPEntityID  = "https://iam.example.com/saml/sp"
+	testIdPEntityID = "https://idp.hospital.example/saml"
+)
+
+// testKeyPair 运行时生成的自签名证书与私钥
+type testKeyPair struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newTestKeyPair(t *testing.T, cn string) testKeyPair {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return testKeyPair{key: key, cert: cert}
+}
+
+func (k testKeyPair) certBase64() string { return base64.StdEncoding.EncodeToString(k.cert.Raw) }
+
+type memoryRequestStore struct{ requests map[string]string }
+
+func (s *memoryRequestStore) SaveRequest(_ context.Context, id, slug string, _ time.Duration) error {
+	s.requests[id] = slug
+	return nil
+}
+
+func (s *memoryRequestStore) ConsumeRequest(_ context.Context, id string) (string, error) {
+	slug, ok := s.requests[id]
+	if !ok {
+		return "", domain.ErrRequestNotFound
+	}
+	delete(s.requests, id)
+	return slug, nil
+}
+
+type memoryReplayCache struct{ seen map[string]time.Time }
+
+func (c *memoryReplayCache) MarkAssertion(_ context.Context, id string, until time.Time) (bool, error) {
+	if _, ok := c.seen[id]; ok {
+		return false, nil
+	}
+	c.seen[id] = until
+	return true, nil
+}
+
+type fixture struct {
+	sp       *ServiceProvider
+	spKeys   testKeyPair
+	idpKeys  testKeyPair
+	idp      *domain.IdentityProvider
+	requests *memoryRequestStore
+	now      time.Time
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	spKeys := newTestKeyPair(t, "iam-sp")
+	idpKeys := newTestKeyPair(t, "hospital-idp")
+	requests := &memoryRequestStore{requests: map[string]string{}}
+	sp, err := NewServiceProvider(Config{
+		EntityID:    testSPEntityID,
+		BaseURL:     "https://iam.example.com/api/v1/",
+		Certificate: spKeys.cert,
+		PrivateKey:  spKeys.key,
+	}, requests, &memoryReplayCache{seen: map[string]time.Time{}})
+	require.NoError(t, err)
+	now := time.Now().UTC().Truncate(time.Second)
+	sp.now = func() time.Time { return now }
+
+	idp := domain.NewIdentityProvider(meta.FromUint64(7), "city-hospital", domain.IdPMetadata{
+		EntityID:     testIdPEntityID,
+		SSOURL:       "https://idp.hospital.example/sso",
+		Certificates: []string{idpKeys.certBase64()},
+	})
+	return &fixture{sp: sp, spKeys: spKeys, idpKeys: idpKeys, idp: idp, requests: requests, now: now}
+}
+
+// startLogin 发起 AuthnRequest 并返回请求 ID
+func (f *fixture) startLogin(t *testing.T) string {
+	t.Helper()
+	redirect, err := f.sp.AuthnRequestURL(context.Background(), f.idp, "state-1")
+	require.NoError(t, err)
+	u, err := url.Parse(redirect)
+	require.NoError(t, err)
+	deflated, err := base64.StdEncoding.DecodeString(u.Query().Get("SAMLRequest"))
+	require.NoError(t, err)
+	raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(deflated)))
+	require.NoError(t, err)
+	doc := etree.NewDocument()
+	require.NoError(t, doc.ReadFromBytes(raw))
+	return doc.Root().SelectAttrValue("ID", "")
+}
+
+// assertionOptions 测试断言的可变部分
+type assertionOptions struct {
+	id           string
+	inResponseTo string
+	audience     string
+	notOnOrAfter time.Time
+	nameID       string
+	signResponse bool
+	signer       testKeyPair
+}
+
+func (f *fixture) defaultOptions(requestID string) assertionOptions {
+	return assertionOptions{
+		id:           "_a1",
+		inResponseTo: requestID,
+		audience:     testSPEntityID,
+		notOnOrAfter: f.now.Add(5 * time.Minute),
+		nameID:       "u-1001",
+		signer:       f.idpKeys,
+	}
+}
+
+// buildResponse 以测试 IdP 身份签发 Base64 SAMLResponse
+func (f *fixture) buildResponse(t *testing.T, opts assertionOptions) string {
+	t.Helper()
+	ts := func(v time.Time) string { return v.UTC().Format(time.RFC3339) }
+	acs := f.sp.ACSURL(f.idp.Slug)
+
+	assertion := etree.NewElement("saml:Assertion")
+	assertion.CreateAttr("xmlns:saml", nsAssertion)
+	assertion.CreateAttr("ID", opts.id)
+	assertion.CreateAttr("Version", "2.0")
+	assertion.CreateAttr("IssueInstant", ts(f.now))
+	assertion.CreateElement("saml:Issuer").SetText(testIdPEntityID)
+	subject := assertion.CreateElement("saml:Subject")
+	nameID := subject.CreateElement("saml:NameID")
+	nameID.CreateAttr("Format", "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent")
+	nameID.SetText(opts.nameID)
+	sc := subject.CreateElement("saml:SubjectConfirmation")
+	sc.CreateAttr("Method", methodBearer)
+	scd := sc.CreateElement("saml:SubjectConfirmationData")
+	scd.CreateAttr("InResponseTo", opts.inResponseTo)
+	scd.CreateAttr("Recipient", acs)
+	scd.CreateAttr("NotOnOrAfter", ts(opts.notOnOrAfter))
+	cond := assertion.CreateElement("saml:Conditions")
+	cond.CreateAttr("NotBefore", ts(f.now.Add(-time.Minute)))
+	cond.CreateAttr("NotOnOrAfter", ts(opts.notOnOrAfter))
+	cond.CreateElement("saml:AudienceRestriction").CreateElement("saml:Audience").SetText(opts.audience)
+	authn := assertion.CreateElement("saml:AuthnStatement")
+	authn.CreateAttr("AuthnInstant", ts(f.now))
+	authn.CreateAttr("SessionIndex", "_s1")
+	attrs := assertion.CreateElement("saml:AttributeStatement")
+	for name, values := range map[string][]string{"mail": {"doctor@hospital.example"}, "memberOf": {"cardiology", "staff"}} {
+		attr := attrs.CreateElement("saml:Attribute")
+		attr.CreateAttr("Name", name)
+		for _, v := range values {
+			attr.CreateElement("saml:AttributeValue").SetText(v)
+		}
+	}
+
+	signer, err := dsig.NewSigningContext(opts.signer.key, [][]byte{opts.signer.cert.Raw})
+	require.NoError(t, err)
+	signer.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+	if !opts.signResponse {
+		assertion, err = signer.SignEnveloped(assertion)
+		require.NoError(t, err)
+	}
+
+	resp := etree.NewElement("samlp:Response")
+	resp.CreateAttr("xmlns:samlp", nsProtocol)
+	resp.CreateAttr("xmlns:saml", nsAssertion)
+	resp.CreateAttr("ID", "_r1")
+	resp.CreateAttr("Version", "2.0")
+	resp.CreateAttr("IssueInstant", ts(f.now))
+	resp.CreateAttr("Destination", acs)
+	resp.CreateAttr("InResponseTo", opts.inResponseTo)
+	resp.CreateElement("saml:Issuer").SetText(testIdPEntityID)
+	resp.CreateElement("samlp:Status").CreateElement("samlp:StatusCode").CreateAttr("Value", statusSuccess)
+	resp.AddChild(assertion)
+	if opts.signResponse {
+		resp, err = signer.SignEnveloped(resp)
+		require.NoError(t, err)
+	}
+
+	doc := etree.NewDocument()
+	doc.SetRoot(resp)
+	raw, err := doc.WriteToBytes()
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func (f *fixture) verify(response string) (authentication.SAMLAssertion, error) {
+	return f.sp.VerifySAMLResponse(context.Background(), authentication.SAMLResponseVerification{
+		IdP:          f.idp.Slug,
+		IdPEntityID:  f.idp.EntityID,
+		Certificates: f.idp.Certificates,
+		Response:     response,
+	})
+}
+
+func TestServiceProvider_AuthnRequestURLIsSigned(t *testing.T) {
+	f := newFixture(t)
+	redirect, err := f.sp.AuthnRequestURL(context.Background(), f.idp, "state-1")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(redirect, "https://idp.hospital.example/sso?SAMLRequest="))
+
+	u, err := url.Parse(redirect)
+	require.NoError(t, err)
+	q := u.Query()
+	assert.Equal(t, "state-1", q.Get("RelayState"))
+	assert.Equal(t, sigAlgRSASHA256, q.Get("SigAlg"))
+
+	// IdP 按原始查询串验签
+	signed := u.RawQuery[:strings.Index(u.RawQuery, "&Signature=")]
+	sig, err := base64.StdEncoding.DecodeString(q.Get("Signature"))
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(signed))
+	require.NoError(t, rsa.VerifyPKCS1v15(&f.spKeys.key.PublicKey, crypto.SHA256, digest[:], sig))
+
+	deflated, err := base64.StdEncoding.DecodeString(q.Get("SAMLRequest"))
+	require.NoError(t, err)
+	raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(deflated)))
+	require.NoError(t, err)
+	doc := etree.NewDocument()
+	require.NoError(t, doc.ReadFromBytes(raw))
+	req := doc.Root()
+	assert.Equal(t, "AuthnRequest", req.Tag)
+	assert.Equal(t, "https://iam.example.com/api/v1/authn/saml/city-hospital/acs", req.SelectAttrValue("AssertionConsumerServiceURL", ""))
+	assert.Equal(t, "city-hospital", f.requests.requests[req.SelectAttrValue("ID", "")])
+
+	_, err = f.sp.AuthnRequestURL(context.Background(), f.idp, strings.Repeat("x", maxRelayStateBytes+1))
+	assert.Error(t, err)
+}
+
+func TestServiceProvider_VerifySAMLResponse(t *testing.T) {
+	t.Run("signed assertion", func(t *testing.T) {
+		f := newFixture(t)
+		response := f.buildResponse(t, f.defaultOptions(f.startLogin(t)))
+
+		assertion, err := f.verify(response)
+		require.NoError(t, err)
+		assert.Equal(t, "_a1", assertion.ID)
+		assert.Equal(t, "u-1001", assertion.NameID)
+		assert.Equal(t, "_s1", assertion.SessionIndex)
+		assert.Equal(t, []string{"doctor@hospital.example"}, assertion.Attributes["mail"])
+		assert.Equal(t, []string{"cardiology", "staff"}, assertion.Attributes["memberOf"])
+
+		// 同一断言再次提交视为重放
+		_, err = f.verify(response)
+		assert.True(t, errors.Is(err, authentication.ErrSAMLReplay))
+	})
+
+	t.Run("signed response", func(t *testing.T) {
+		f := newFixture(t)
+		opts := f.defaultOptions(f.startLogin(t))
+		opts.signResponse = true
+
+		assertion, err := f.verify(f.buildResponse(t, opts))
+		require.NoError(t, err)
+		assert.Equal(t, "u-1001", assertion.NameID)
+	})
+
+	rejected := map[string]func(f *fixture, opts *assertionOptions){
+		"untrusted signer":   func(f *fixture, opts *assertionOptions) { opts.signer = newTestKeyPair(t, "evil-idp") },
+		"wrong audience":     func(f *fixture, opts *assertionOptions) { opts.audience = "https://other.example.com/sp" },
+		"expired":            func(f *fixture, opts *assertionOptions) { opts.notOnOrAfter = f.now.Add(-10 * time.Minute) },
+		"unknown request":    func(f *fixture, opts *assertionOptions) { opts.inResponseTo = "_unknown" },
+		"unsolicited":        func(f *fixture, opts *assertionOptions) { opts.inResponseTo = "" },
+		"request of another": func(f *fixture, opts *assertionOptions) { f.requests.requests[opts.inResponseTo] = "other-idp" },
+	}
+	for name, mutate := range rejected {
+		t.Run(name, func(t *testing.T) {
+			f := newFixture(t)
+			opts := f.defaultOptions(f.startLogin(t))
+			mutate(f, &opts)
+
+			_, err := f.verify(f.buildResponse(t, opts))
+			require.Error(t, err)
+			assert.False(t, errors.Is(err, authentication.ErrSAMLReplay))
+		})
+	}
+
+	t.Run("tampered name id", func(t *testing.T) {
+		f := newFixture(t)
+		response := f.buildResponse(t, f.defaultOptions(f.startLogin(t)))
+		raw, err := base64.StdEncoding.DecodeString(response)
+		require.NoError(t, err)
+		tampered := bytes.Replace(raw, []byte(">u-1001<"), []byte(">admin<"), 1)
+
+		_, err = f.verify(base64.StdEncoding.EncodeToString(tampered))
+		assert.Error(t, err)
+	})
+
+	t.Run("doctype", func(t *testing.T) {
+		f := newFixture(t)
+		_, err := f.verify(base64.StdEncoding.EncodeToString([]byte(`<!DOCTYPE x [<!ENTITY a "b">]><x/>`)))
+		assert.Error(t, err)
+	})
+}
+
+func TestServiceProvider_Metadata(t *testing.T) {
+	f := newFixture(t)
+	raw, err := f.sp.Metadata("city-hospital")
+	require.NoError(t, err)
+	doc := etree.NewDocument()
+	require.NoError(t, doc.ReadFromBytes(raw))
+	root := doc.Root()
+	assert.Equal(t, testSPEntityID, root.SelectAttrValue("entityID", ""))
+	acs := root.FindElement("//AssertionConsumerService")
+	require.NotNil(t, acs)
+	assert.Equal(t, "https://iam.example.com/api/v1/authn/saml/city-hospital/acs", acs.SelectAttrValue("Location", ""))
+	assert.Equal(t, bindingHTTPPost, acs.SelectAttrValue("Binding", ""))
+	cert := root.FindElement("//X509Certificate")
+	require.NotNil(t, cert)
+	assert.Equal(t, f.spKeys.certBase64(), cert.Text())
+}
+
+func TestParseIdPMetadata(t *testing.T) {
+	keys := newTestKeyPair(t, "hospital-idp")
+	raw := `<?xml version="1.0"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" xmlns:ds="http://www.w3.org/2000/09/xmldsig#" entityID="` + testIdPEntityID + `">
+  <md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor use="encryption"><ds:KeyInfo><ds:X509Data><ds:X509Certificate>bm90LWEtY2VydA==</ds:X509Certificate></ds:X509Data></ds:KeyInfo></md:KeyDescriptor>
+    <md:KeyDescriptor use="signing"><ds:KeyInfo><ds:X509Data><ds:X509Certificate>
+      ` + keys.certBase64() + `
+    </ds:X509Certificate></ds:X509Data></ds:KeyInfo></md:KeyDescriptor>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://idp.hospital.example/sso/post"/>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.hospital.example/sso"/>
+  </md:IDPSSODescriptor>
+</md:EntityDescriptor>`
+
+	md, err := ParseIdPMetadata([]byte(raw))
+	require.NoError(t, err)
+	assert.Equal(t, testIdPEntityID, md.EntityID)
+	assert.Equal(t, "https://idp.hospital.example/sso", md.SSOURL)
+	assert.Equal(t, []string{keys.certBase64()}, md.Certificates)
+
+	_, err = ParseIdPMetadata([]byte(strings.Replace(raw, "HTTP-Redirect", "SOAP", 1)))
+	assert.Error(t, err)
+	_, err = ParseIdPMetadata([]byte(`<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="x"/>`))
+	assert.Error(t, err)
+}
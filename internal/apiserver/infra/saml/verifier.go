@@ -0,0 +1,327 @@
+package saml
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/authentication"
+)
+
+// maxResponseBytes 解码后 SAMLResponse 的大小上限
+const maxResponseBytes = 512 << 10
+
+// VerifySAMLResponse 校验 HTTP-POST 绑定回传的 SAMLResponse
+// 校验顺序：XML 结构与状态 -> XML 签名（Response 或 Assertion）-> Issuer -> 时间条件与 Audience
+// -> Bearer 确认（Recipient、有效期）-> 断言防重放 -> InResponseTo 对应本系统发出的请求
+// 只读取签名覆盖的内容，防止签名包装攻击
+func (sp *ServiceProvider) VerifySAMLResponse(ctx context.Context, req authentication.SAMLResponseVerification) (authentication.SAMLAssertion, error) {
+	var out authentication.SAMLAssertion
+
+	raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(req.Response), ""))
+	if err != nil {
+		return out, fmt.Errorf("decode saml response: %w", err)
+	}
+	if len(raw) > maxResponseBytes {
+		return out, errors.New("saml response too large")
+	}
+	if hasDoctype(raw) {
+		return out, errors.New("saml response must not contain a DOCTYPE")
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(raw); err != nil {
+		return out, fmt.Errorf("parse saml response: %w", err)
+	}
+	resp := doc.Root()
+	if resp == nil || !isElement(resp, nsProtocol, "Response") {
+		return out, errors.New("root element is not a saml protocol Response")
+	}
+	if status := statusCode(resp); status != statusSuccess {
+		return out, fmt.Errorf("saml response status is %q", status)
+	}
+	acsURL := sp.ACSURL(req.IdP)
+	if dest := resp.SelectAttrValue("Destination", ""); dest != "" && dest != acsURL {
+		return out, fmt.Errorf("saml response destination %q does not match acs", dest)
+	}
+	if len(children(resp, nsAssertion, "EncryptedAssertion")) > 0 {
+		return out, errors.New("encrypted assertions are not supported")
+	}
+
+	certs, err := parseCertificates(req.Certificates)
+	if err != nil {
+		return out, err
+	}
+	now := sp.now()
+	assertion, err := sp.verifiedAssertion(resp, certs, now)
+	if err != nil {
+		return out, err
+	}
+
+	// Issuer
+	if issuer := childText(assertion, nsAssertion, "Issuer"); issuer != req.IdPEntityID {
+		return out, fmt.Errorf("assertion issuer %q does not match idp entity id", issuer)
+	}
+
+	// Conditions：有效期与 Audience
+	notOnOrAfter, err := sp.checkConditions(assertion, now)
+	if err != nil {
+		return out, err
+	}
+
+	// Subject：NameID 与 Bearer 确认
+	subject := child(assertion, nsAssertion, "Subject")
+	if subject == nil {
+		return out, errors.New("assertion has no subject")
+	}
+	nameID := child(subject, nsAssertion, "NameID")
+	if nameID == nil || strings.TrimSpace(nameID.Text()) == "" {
+		return out, errors.New("assertion has no name id")
+	}
+	inResponseTo, confirmedUntil, err := sp.checkBearerConfirmation(subject, acsURL, now)
+	if err != nil {
+		return out, err
+	}
+	if confirmedUntil.After(notOnOrAfter) {
+		notOnOrAfter = confirmedUntil
+	}
+
+	// 防重放：断言 ID 在有效期内只能被消费一次
+	assertionID := assertion.SelectAttrValue("ID", "")
+	if assertionID == "" {
+		return out, errors.New("assertion has no id")
+	}
+	first, err := sp.replay.MarkAssertion(ctx, assertionID, notOnOrAfter.Add(sp.cfg.ClockSkew))
+	if err != nil {
+		return out, fmt.Errorf("mark assertion: %w", err)
+	}
+	if !first {
+		return out, fmt.Errorf("%w: %s", authentication.ErrSAMLReplay, assertionID)
+	}
+
+	// 仅接受对本系统发出的 AuthnRequest 的回应（不支持 IdP 发起的登录）
+	slug, err := sp.requests.ConsumeRequest(ctx, inResponseTo)
+	if err != nil {
+		return out, fmt.Errorf("consume authn request %s: %w", inResponseTo, err)
+	}
+	if slug != req.IdP {
+		return out, fmt.Errorf("authn request %s was issued for idp %q", inResponseTo, slug)
+	}
+
+	out = authentication.SAMLAssertion{
+		ID:           assertionID,
+		NameID:       strings.TrimSpace(nameID.Text()),
+		NameIDFormat: nameID.SelectAttrValue("Format", ""),
+		Attributes:   attributes(assertion),
+	}
+	if authn := child(assertion, nsAssertion, "AuthnStatement"); authn != nil {
+		out.SessionIndex = authn.SelectAttrValue("SessionIndex", "")
+	}
+	return out, nil
+}
+
+// verifiedAssertion 校验 Response 或 Assertion 上的签名，返回签名覆盖的断言
+func (sp *ServiceProvider) verifiedAssertion(resp *etree.Element, certs []*x509.Certificate, now time.Time) (*etree.Element, error) {
+	vctx := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: certs})
+	vctx.Clock = dsig.NewFakeClockAt(now)
+
+	if child(resp, nsDSig, "Signature") != nil {
+		verified, err := vctx.Validate(resp)
+		if err != nil {
+			return nil, fmt.Errorf("verify response signature: %w", err)
+		}
+		return singleAssertion(verified)
+	}
+
+	assertion, err := singleAssertion(resp)
+	if err != nil {
+		return nil, err
+	}
+	if child(assertion, nsDSig, "Signature") == nil {
+		return nil, errors.New("neither response nor assertion is signed")
+	}
+	// 断言的命名空间可能声明在 Response 上，校验前先补齐
+	nsCtx, err := etreeutils.NSBuildParentContext(assertion)
+	if err != nil {
+		return nil, err
+	}
+	detached, err := etreeutils.NSDetatch(nsCtx, assertion)
+	if err != nil {
+		return nil, err
+	}
+	verified, err := vctx.Validate(detached)
+	if err != nil {
+		return nil, fmt.Errorf("verify assertion signature: %w", err)
+	}
+	return verified, nil
+}
+
+// checkConditions 校验 NotBefore/NotOnOrAfter 与 AudienceRestriction，返回断言失效时间
+func (sp *ServiceProvider) checkConditions(assertion *etree.Element, now time.Time) (time.Time, error) {
+	cond := child(assertion, nsAssertion, "Conditions")
+	if cond == nil {
+		return time.Time{}, errors.New("assertion has no conditions")
+	}
+	skew := sp.cfg.ClockSkew
+	if v := cond.SelectAttrValue("NotBefore", ""); v != "" {
+		notBefore, err := parseTime(v)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if now.Add(skew).Before(notBefore) {
+			return time.Time{}, errors.New("assertion is not yet valid")
+		}
+	}
+	notOnOrAfter, err := parseTime(cond.SelectAttrValue("NotOnOrAfter", ""))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("conditions NotOnOrAfter: %w", err)
+	}
+	if !now.Add(-skew).Before(notOnOrAfter) {
+		return time.Time{}, errors.New("assertion has expired")
+	}
+
+	restrictions := children(cond, nsAssertion, "AudienceRestriction")
+	if len(restrictions) == 0 {
+		return time.Time{}, errors.New("assertion has no audience restriction")
+	}
+	// 多个 AudienceRestriction 须同时满足
+	for _, r := range restrictions {
+		matched := false
+		for _, aud := range children(r, nsAssertion, "Audience") {
+			if strings.TrimSpace(aud.Text()) == sp.cfg.EntityID {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return time.Time{}, errors.New("assertion audience does not include this service provider")
+		}
+	}
+	return notOnOrAfter, nil
+}
+
+// checkBearerConfirmation 校验 Bearer 确认的 Recipient 与有效期，返回 InResponseTo 与确认失效时间
+func (sp *ServiceProvider) checkBearerConfirmation(subject *etree.Element, acsURL string, now time.Time) (string, time.Time, error) {
+	for _, sc := range children(subject, nsAssertion, "SubjectConfirmation") {
+		if sc.SelectAttrValue("Method", "") != methodBearer {
+			continue
+		}
+		data := child(sc, nsAssertion, "SubjectConfirmationData")
+		if data == nil {
+			continue
+		}
+		if data.SelectAttrValue("Recipient", "") != acsURL {
+			continue
+		}
+		notOnOrAfter, err := parseTime(data.SelectAttrValue("NotOnOrAfter", ""))
+		if err != nil || !now.Add(-sp.cfg.ClockSkew).Before(notOnOrAfter) {
+			continue
+		}
+		inResponseTo := data.SelectAttrValue("InResponseTo", "")
+		if inResponseTo == "" {
+			return "", time.Time{}, errors.New("unsolicited responses are not accepted")
+		}
+		return inResponseTo, notOnOrAfter, nil
+	}
+	return "", time.Time{}, errors.New("assertion has no valid bearer subject confirmation")
+}
+
+// ==================== XML 辅助函数 ====================
+
+func isElement(el *etree.Element, ns, tag string) bool {
+	return el.Tag == tag && el.NamespaceURI() == ns
+}
+
+func child(el *etree.Element, ns, tag string) *etree.Element {
+	for _, c := range el.ChildElements() {
+		if isElement(c, ns, tag) {
+			return c
+		}
+	}
+	return nil
+}
+
+func children(el *etree.Element, ns, tag string) []*etree.Element {
+	var out []*etree.Element
+	for _, c := range el.ChildElements() {
+		if isElement(c, ns, tag) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func childText(el *etree.Element, ns, tag string) string {
+	if c := child(el, ns, tag); c != nil {
+		return strings.TrimSpace(c.Text())
+	}
+	return ""
+}
+
+func singleAssertion(resp *etree.Element) (*etree.Element, error) {
+	assertions := children(resp, nsAssertion, "Assertion")
+	if len(assertions) != 1 {
+		return nil, fmt.Errorf("saml response must contain exactly one assertion, got %d", len(assertions))
+	}
+	return assertions[0], nil
+}
+
+func statusCode(resp *etree.Element) string {
+	status := child(resp, nsProtocol, "Status")
+	if status == nil {
+		return ""
+	}
+	if sc := child(status, nsProtocol, "StatusCode"); sc != nil {
+		return sc.SelectAttrValue("Value", "")
+	}
+	return ""
+}
+
+func attributes(assertion *etree.Element) map[string][]string {
+	out := make(map[string][]string)
+	for _, stmt := range children(assertion, nsAssertion, "AttributeStatement") {
+		for _, attr := range children(stmt, nsAssertion, "Attribute") {
+			name := attr.SelectAttrValue("Name", "")
+			if name == "" {
+				continue
+			}
+			for _, v := range children(attr, nsAssertion, "AttributeValue") {
+				out[name] = append(out[name], strings.TrimSpace(v.Text()))
+			}
+		}
+	}
+	return out
+}
+
+func parseCertificates(encoded []string) ([]*x509.Certificate, error) {
+	certs := make([]*x509.Certificate, 0, len(encoded))
+	for _, c := range encoded {
+		der, err := base64.StdEncoding.DecodeString(c)
+		if err != nil {
+			return nil, fmt.Errorf("decode idp certificate: %w", err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("parse idp certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no idp signing certificate")
+	}
+	return certs, nil
+}
+
+func parseTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, errors.New("missing timestamp")
+	}
+	return time.Parse(time.RFC3339Nano, v)
+}
@@ -22,6 +22,9 @@ type KeyRotationCronScheduler struct {
 
 	ctx    context.Context
 	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	election *leaderElection // 多副本领导者选举
 
 	mu      sync.RWMutex
 	running bool
@@ -42,6 +45,7 @@ func NewKeyRotationCronScheduler(
 	rotationApp *jwks.KeyRotationAppService,
 	cronSpec string,
 	logger log.Logger,
+	opts ...Option,
 ) *KeyRotationCronScheduler {
 	if cronSpec == "" {
 		// 默认每天凌晨2点检查一次
@@ -53,6 +57,7 @@ func NewKeyRotationCronScheduler(
 		rotationApp: rotationApp,
 		logger:      logger,
 		cronSpec:    cronSpec,
+		election:    newLeaderElection(logger, opts...),
 	}
 }
 
@@ -84,6 +89,7 @@ func (s *KeyRotationCronScheduler) Start(ctx context.Context) error {
 	}
 
 	s.entryID = entryID
+	s.election.run(s.ctx, &s.wg)
 	s.cron.Start()
 	s.running = true
 
@@ -112,6 +118,7 @@ func (s *KeyRotationCronScheduler) Stop() error {
 	<-ctx.Done()
 
 	s.cancel()
+	s.wg.Wait()
 	s.running = false
 
 	s.logger.Info("Key rotation cron scheduler stopped")
@@ -125,6 +132,11 @@ func (s *KeyRotationCronScheduler) IsRunning() bool {
 	return s.running
 }
 
+// Leadership 返回领导者选举状态
+func (s *KeyRotationCronScheduler) Leadership() LeadershipStatus {
+	return s.election.Status()
+}
+
 // TriggerNow 立即触发一次密钥轮换检查
 func (s *KeyRotationCronScheduler) TriggerNow(ctx context.Context) error {
 	s.logger.Info("Manually triggering key rotation check")
@@ -149,25 +161,10 @@ func (s *KeyRotationCronScheduler) GetNextRunTime() string {
 }
 
 // checkAndRotate 检查并执行密钥轮换
+// 仅 leader 实例执行，且每个轮换窗口至多轮换一次
 func (s *KeyRotationCronScheduler) checkAndRotate(ctx context.Context) error {
-	// 检查是否需要轮换
-	shouldRotateResp, err := s.rotationApp.ShouldRotate(ctx)
-	if err != nil {
-		s.logger.Errorw("Failed to check if rotation is needed", "error", err)
-		return err
-	}
-
-	if !shouldRotateResp.ShouldRotate {
-		s.logger.Debugw("Key rotation not needed", "reason", shouldRotateResp.Reason)
-		return nil
-	}
-
-	// 执行轮换
-	s.logger.Infow("Starting automatic key rotation", "reason", shouldRotateResp.Reason)
-
-	resp, err := s.rotationApp.RotateKey(ctx)
-	if err != nil {
-		s.logger.Errorw("Automatic key rotation failed", "error", err)
+	resp, err := s.election.rotateOnce(ctx, s.rotationApp)
+	if err != nil || resp == nil {
 		return err
 	}
 
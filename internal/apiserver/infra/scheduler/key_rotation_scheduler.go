@@ -21,6 +21,8 @@ type KeyRotationScheduler struct {
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
 
+	election *leaderElection // 多副本领导者选举
+
 	mu      sync.RWMutex
 	running bool
 }
@@ -30,6 +32,7 @@ func NewKeyRotationScheduler(
 	rotationApp *jwks.KeyRotationAppService,
 	checkInterval time.Duration,
 	logger log.Logger,
+	opts ...Option,
 ) *KeyRotationScheduler {
 	if checkInterval == 0 {
 		checkInterval = 1 * time.Hour // 默认每小时检查一次
@@ -39,6 +42,7 @@ func NewKeyRotationScheduler(
 		rotationApp:   rotationApp,
 		logger:        logger,
 		checkInterval: checkInterval,
+		election:      newLeaderElection(logger, opts...),
 	}
 }
 
@@ -55,6 +59,7 @@ func (s *KeyRotationScheduler) Start(ctx context.Context) error {
 	s.ctx, s.cancel = context.WithCancel(ctx)
 	s.running = true
 
+	s.election.run(s.ctx, &s.wg)
 	s.wg.Add(1)
 	go s.run()
 
@@ -92,6 +97,11 @@ func (s *KeyRotationScheduler) IsRunning() bool {
 	return s.running
 }
 
+// Leadership 返回领导者选举状态
+func (s *KeyRotationScheduler) Leadership() LeadershipStatus {
+	return s.election.Status()
+}
+
 // TriggerNow 立即触发一次密钥轮换检查
 func (s *KeyRotationScheduler) TriggerNow(ctx context.Context) error {
 	s.logger.Info("Manually triggering key rotation check")
@@ -129,25 +139,10 @@ func (s *KeyRotationScheduler) run() {
 }
 
 // checkAndRotate 检查并执行密钥轮换
+// 仅 leader 实例执行，且每个轮换窗口至多轮换一次
 func (s *KeyRotationScheduler) checkAndRotate(ctx context.Context) error {
-	// 检查是否需要轮换
-	shouldRotateResp, err := s.rotationApp.ShouldRotate(ctx)
-	if err != nil {
-		s.logger.Errorw("Failed to check if rotation is needed", "error", err)
-		return err
-	}
-
-	if !shouldRotateResp.ShouldRotate {
-		s.logger.Debugw("Key rotation not needed", "reason", shouldRotateResp.Reason)
-		return nil
-	}
-
-	// 执行轮换
-	s.logger.Infow("Starting automatic key rotation", "reason", shouldRotateResp.Reason)
-
-	resp, err := s.rotationApp.RotateKey(ctx)
-	if err != nil {
-		s.logger.Errorw("Automatic key rotation failed", "error", err)
+	resp, err := s.election.rotateOnce(ctx, s.rotationApp)
+	if err != nil || resp == nil {
 		return err
 	}
 
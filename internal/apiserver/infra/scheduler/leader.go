@@ -0,0 +1,217 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/log"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/jwks"
+)
+
+// LeaderLease 多副本间的调度领导权租约
+// 由 Redis 等共享存储实现；同一时刻只有一个实例持有
+type LeaderLease interface {
+	// Acquire 获取租约，已持有时续约；返回当前实例是否持有租约
+	Acquire(ctx context.Context) (bool, error)
+	// Release 主动释放租约（仅释放自己持有的）
+	Release(ctx context.Context) error
+	// ClaimOnce 以 key 声明一次性执行，ttl 内重复声明返回 false；release 用于执行失败时撤回声明
+	ClaimOnce(ctx context.Context, key string, ttl time.Duration) (claimed bool, release func(), err error)
+}
+
+// LeadershipStatus 调度领导权状态，供 /debug/modules 展示
+type LeadershipStatus struct {
+	Enabled            bool       `json:"enabled"`
+	Instance           string     `json:"instance"`
+	Leader             bool       `json:"leader"`
+	LastRenewedAt      *time.Time `json:"last_renewed_at,omitempty"`
+	LastError          string     `json:"last_error,omitempty"`
+	LastRotationWindow string     `json:"last_rotation_window,omitempty"`
+}
+
+// Option 调度器可选项
+type Option func(*leaderElection)
+
+// WithLeaderLease 启用领导者选举：仅持有租约的实例执行轮换，renewInterval 应明显小于租约 TTL
+func WithLeaderLease(lease LeaderLease, renewInterval time.Duration) Option {
+	return func(e *leaderElection) {
+		e.lease = lease
+		if renewInterval > 0 {
+			e.renewInterval = renewInterval
+		}
+	}
+}
+
+// leaderElection 两种调度器共用的选举状态
+// 未配置租约时视为单实例部署，始终为 leader
+type leaderElection struct {
+	lease         LeaderLease
+	renewInterval time.Duration
+	logger        log.Logger
+
+	mu     sync.RWMutex
+	status LeadershipStatus
+}
+
+func newLeaderElection(logger log.Logger, opts ...Option) *leaderElection {
+	e := &leaderElection{
+		renewInterval: 10 * time.Second,
+		logger:        logger,
+		status:        LeadershipStatus{Instance: instanceID()},
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.status.Enabled = e.lease != nil
+	e.status.Leader = e.lease == nil
+	return e
+}
+
+// run 周期性获取/续约租约，ctx 结束时释放
+func (e *leaderElection) run(ctx context.Context, wg *sync.WaitGroup) {
+	if e.lease == nil {
+		return
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(e.renewInterval)
+		defer ticker.Stop()
+
+		e.tryLead(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				e.resign()
+				return
+			case <-ticker.C:
+				e.tryLead(ctx)
+			}
+		}
+	}()
+}
+
+// tryLead 获取或续约租约并记录状态
+func (e *leaderElection) tryLead(ctx context.Context) bool {
+	if e.lease == nil {
+		return true
+	}
+	leader, err := e.lease.Acquire(ctx)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	wasLeader := e.status.Leader
+	if err != nil {
+		// 无法确认租约时按失去领导权处理，宁可跳过一次轮换也不重复轮换
+		e.status.Leader = false
+		e.status.LastError = err.Error()
+		e.logger.Warnw("Failed to acquire key rotation lease", "error", err)
+		return false
+	}
+	e.status.Leader = leader
+	e.status.LastError = ""
+	if leader {
+		now := time.Now()
+		e.status.LastRenewedAt = &now
+	}
+	if leader != wasLeader {
+		e.logger.Infow("Key rotation leadership changed", "instance", e.status.Instance, "leader", leader)
+	}
+	return leader
+}
+
+func (e *leaderElection) resign() {
+	e.mu.Lock()
+	wasLeader := e.status.Leader
+	e.status.Leader = false
+	e.mu.Unlock()
+	if !wasLeader {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := e.lease.Release(ctx); err != nil {
+		e.logger.Warnw("Failed to release key rotation lease", "error", err)
+	}
+}
+
+// Status 返回当前领导权状态
+func (e *leaderElection) Status() LeadershipStatus {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.status
+}
+
+// rotateOnce 在领导权与轮换窗口双重保护下执行一次检查与轮换
+// 执行前重新确认租约；同一轮换窗口内只会成功轮换一次，失败则撤回窗口声明以便重试
+func (e *leaderElection) rotateOnce(ctx context.Context, rotationApp *jwks.KeyRotationAppService) (*jwks.RotateKeyResponse, error) {
+	if !e.tryLead(ctx) {
+		e.logger.Debugw("Skip key rotation check: not the leader", "instance", e.Status().Instance)
+		return nil, nil
+	}
+
+	shouldRotateResp, err := rotationApp.ShouldRotate(ctx)
+	if err != nil {
+		e.logger.Errorw("Failed to check if rotation is needed", "error", err)
+		return nil, err
+	}
+	if !shouldRotateResp.ShouldRotate {
+		e.logger.Debugw("Key rotation not needed", "reason", shouldRotateResp.Reason)
+		return nil, nil
+	}
+
+	release := func() {}
+	if e.lease != nil {
+		// 窗口标识与声明 TTL 使用同一个兜底后的间隔，未配置时声明不会立即过期
+		interval := rotationInterval(rotationApp.GetRotationPolicy(ctx).Policy.RotationInterval)
+		window := rotationWindow(time.Now(), interval)
+		claimed, undo, err := e.lease.ClaimOnce(ctx, window, interval)
+		if err != nil {
+			return nil, fmt.Errorf("claim rotation window: %w", err)
+		}
+		if !claimed {
+			e.logger.Infow("Skip key rotation: window already rotated", "window", window)
+			return nil, nil
+		}
+		release = undo
+		e.mu.Lock()
+		e.status.LastRotationWindow = window
+		e.mu.Unlock()
+	}
+
+	e.logger.Infow("Starting automatic key rotation", "reason", shouldRotateResp.Reason)
+	resp, err := rotationApp.RotateKey(ctx)
+	if err != nil {
+		release()
+		e.logger.Errorw("Automatic key rotation failed", "error", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// defaultRotationInterval 轮换策略未配置间隔时使用的窗口长度
+const defaultRotationInterval = 24 * time.Hour
+
+// rotationInterval 返回兜底后的轮换间隔
+func rotationInterval(interval time.Duration) time.Duration {
+	if interval <= 0 {
+		return defaultRotationInterval
+	}
+	return interval
+}
+
+// rotationWindow 返回 now 所在的轮换窗口标识
+func rotationWindow(now time.Time, interval time.Duration) string {
+	return fmt.Sprintf("window:%d", now.Truncate(rotationInterval(interval)).Unix())
+}
+
+func instanceID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/log"
+)
+
+type leaseStub struct {
+	leader bool
+	err    error
+}
+
+func (s *leaseStub) Acquire(context.Context) (bool, error) { return s.leader, s.err }
+
+func (s *leaseStub) Release(context.Context) error { return nil }
+
+func (s *leaseStub) ClaimOnce(context.Context, string, time.Duration) (bool, func(), error) {
+	return true, func() {}, nil
+}
+
+func TestLeaderElectionWithoutLeaseAlwaysLeads(t *testing.T) {
+	e := newLeaderElection(log.New(log.NewOptions()))
+	if !e.tryLead(context.Background()) {
+		t.Fatal("single instance should always lead")
+	}
+	if status := e.Status(); status.Enabled || !status.Leader {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
+
+func TestLeaderElectionTracksLease(t *testing.T) {
+	lease := &leaseStub{leader: true}
+	e := newLeaderElection(log.New(log.NewOptions()), WithLeaderLease(lease, time.Second))
+	if status := e.Status(); !status.Enabled || status.Leader {
+		t.Fatalf("status before election = %+v", status)
+	}
+
+	if !e.tryLead(context.Background()) {
+		t.Fatal("expected leadership")
+	}
+	if status := e.Status(); !status.Leader || status.LastRenewedAt == nil {
+		t.Fatalf("status after acquire = %+v", status)
+	}
+
+	// 租约不可用时放弃领导权
+	lease.err = errors.New("redis down")
+	if e.tryLead(context.Background()) {
+		t.Fatal("lease error must not be treated as leadership")
+	}
+	if status := e.Status(); status.Leader || status.LastError == "" {
+		t.Fatalf("status after lease error = %+v", status)
+	}
+}
+
+func TestRotationWindowStableWithinInterval(t *testing.T) {
+	interval := 24 * time.Hour
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if rotationWindow(start.Add(time.Hour), interval) != rotationWindow(start.Add(23*time.Hour), interval) {
+		t.Fatal("same interval should map to the same window")
+	}
+	if rotationWindow(start.Add(time.Hour), interval) == rotationWindow(start.Add(25*time.Hour), interval) {
+		t.Fatal("next interval should map to a new window")
+	}
+}
+
+func TestRotationIntervalDefaultsUnsetPolicy(t *testing.T) {
+	if got := rotationInterval(0); got != 24*time.Hour {
+		t.Fatalf("rotationInterval(0) = %v, want 24h", got)
+	}
+	if got := rotationInterval(time.Hour); got != time.Hour {
+		t.Fatalf("rotationInterval(1h) = %v, want 1h", got)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if rotationWindow(start.Add(time.Hour), 0) != rotationWindow(start.Add(23*time.Hour), 0) {
+		t.Fatal("unset interval should fall back to a daily window")
+	}
+}
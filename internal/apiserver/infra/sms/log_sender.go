@@ -6,12 +6,18 @@ import (
 	"github.com/FangcunMount/component-base/pkg/logger"
 )
 
-// LogSender 将登录 OTP 打到日志（仅用于开发/联调，禁止在生产依赖）
+// LogSender 将验证码打到日志（仅用于开发/联调，禁止在生产依赖）
 type LogSender struct{}
 
 // SendLoginOTP 记录验证码，不调用真实短信网关
-func (LogSender) SendLoginOTP(ctx context.Context, phoneE164, code string) error {
-	logger.L(ctx).Infow("sms login otp",
+func (s LogSender) SendLoginOTP(ctx context.Context, phoneE164, code string) error {
+	return s.SendOTP(ctx, phoneE164, "login", code)
+}
+
+// SendOTP 记录指定场景的验证码
+func (LogSender) SendOTP(ctx context.Context, phoneE164, scene, code string) error {
+	logger.L(ctx).Infow("sms otp",
+		"scene", scene,
 		"phone", phoneE164,
 		"code", code,
 	)
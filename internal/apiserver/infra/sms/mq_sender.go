@@ -14,10 +14,10 @@ import (
 // LoginOTPSMSTopicDefault NSQ topic：下游消费者（短信网关等）订阅并真正发送短信
 const LoginOTPSMSTopicDefault = "iam.notify.sms"
 
-// LoginOTPSMSPayload 登录 OTP 短信投递消息体（与具体厂商解耦）
+// LoginOTPSMSPayload OTP 短信投递消息体（与具体厂商解耦）
 type LoginOTPSMSPayload struct {
-	EventType string `json:"event_type"` // EventLoginOTPSMS
-	Scene     string `json:"scene"`      // login
+	EventType string `json:"event_type"` // EventLoginOTPSMS | EventOTPSMS
+	Scene     string `json:"scene"`      // login | reset_password
 	PhoneE164 string `json:"phone_e164"`
 	Code      string `json:"code"`
 }
@@ -25,6 +25,9 @@ type LoginOTPSMSPayload struct {
 // EventLoginOTPSMS 与 LoginOTPSMSPayload.event_type 一致，供消费者筛选
 const EventLoginOTPSMS = "iam.login_otp_sms"
 
+// EventOTPSMS 非登录场景的验证码短信（scene 区分具体用途）
+const EventOTPSMS = "iam.otp_sms"
+
 // MQLoginOTPSender 通过消息队列投递「待发短信」意图，不直连运营商
 type MQLoginOTPSender struct {
 	publisher messaging.Publisher
@@ -46,23 +49,32 @@ func NewMQLoginOTPSender(bus messaging.EventBus, topic string) *MQLoginOTPSender
 
 // SendLoginOTP 发布一条 MQ 消息，由下游完成实际发送
 func (s *MQLoginOTPSender) SendLoginOTP(ctx context.Context, phoneE164, code string) error {
+	return s.SendOTP(ctx, phoneE164, "login", code)
+}
+
+// SendOTP 发布指定场景的验证码短信任务
+func (s *MQLoginOTPSender) SendOTP(ctx context.Context, phoneE164, scene, code string) error {
+	eventType := EventOTPSMS
+	if scene == "login" {
+		eventType = EventLoginOTPSMS
+	}
 	p := LoginOTPSMSPayload{
-		EventType: EventLoginOTPSMS,
-		Scene:     "login",
+		EventType: eventType,
+		Scene:     scene,
 		PhoneE164: phoneE164,
 		Code:      code,
 	}
 	payload, err := json.Marshal(p)
 	if err != nil {
-		return fmt.Errorf("marshal login otp sms payload: %w", err)
+		return fmt.Errorf("marshal %s otp sms payload: %w", scene, err)
 	}
 	msg := messaging.NewMessage(uuid.New().String(), payload)
 	if msg.Metadata == nil {
 		msg.Metadata = make(map[string]string)
 	}
-	msg.Metadata["event_type"] = EventLoginOTPSMS
+	msg.Metadata["event_type"] = eventType
 	if err := s.publisher.PublishMessage(ctx, s.topic, msg); err != nil {
-		return fmt.Errorf("publish login otp sms: %w", err)
+		return fmt.Errorf("publish %s otp sms: %w", scene, err)
 	}
 	return nil
 }
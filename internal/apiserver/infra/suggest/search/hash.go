@@ -2,58 +2,92 @@ package search
 
 import (
 	"strconv"
-	"strings"
 
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/suggest"
 )
 
-// Hash 支持手机号/ID 精确匹配
+// Hash 支持手机号/ID 精确匹配与手机尾号匹配
 type Hash struct {
-	table map[int64][]suggest.Term
+	table  map[int64][]suggest.Term
+	suffix map[string][]suggest.Term
 }
 
 // NewHash constructs a Hash store.
 func NewHash() *Hash {
-	return &Hash{table: make(map[int64][]suggest.Term)}
+	return &Hash{
+		table:  make(map[int64][]suggest.Term),
+		suffix: make(map[string][]suggest.Term),
+	}
 }
 
-// ImportLines loads name|id|mobiles|disease|weight formatted rows.
+// ImportLines loads name|id|mobiles|tenant|weight|guardians|deleted formatted rows.
 func (h *Hash) ImportLines(lines []string) {
 	for _, line := range lines {
-		parts := strings.Split(line, "|")
-		if len(parts) < 5 {
+		row, ok := ParseLine(line)
+		if !ok {
 			continue
 		}
-		name := strings.TrimSpace(parts[0])
-		idStr := strings.TrimSpace(parts[1])
-		mobileStr := strings.TrimSpace(parts[2])
-		_ = strings.TrimSpace(parts[3]) // disease field ignored
-		weight, _ := strconv.Atoi(strings.TrimSpace(parts[4]))
-		id, _ := strconv.ParseInt(idStr, 10, 64)
-		mobiles := strings.Split(mobileStr, ",")
-		term := suggest.Term{Name: name, ID: id, Mobile: mobileStr, Weight: weight}
-		if id != 0 {
-			h.table[id] = append(h.table[id], term)
+		if row.Deleted {
+			h.Remove(row.Term)
+			continue
 		}
-		for _, m := range mobiles {
-			m = strings.TrimSpace(m)
-			if m == "" {
-				continue
-			}
-			mid, err := strconv.ParseInt(m, 10, 64)
-			if err != nil {
-				continue
+		h.Add(row.Term)
+	}
+}
+
+// Add 以 ID、完整手机号和手机尾号为键插入术语
+func (h *Hash) Add(term suggest.Term) {
+	exact := withBonus(term, weightExactNumber)
+	if term.ID != 0 {
+		h.table[term.ID] = append(h.table[term.ID], exact)
+	}
+	for _, m := range mobiles(term) {
+		if mid, err := strconv.ParseInt(m, 10, 64); err == nil {
+			h.table[mid] = append(h.table[mid], exact)
+		}
+		for n := minSuffixLen; n < len(m); n++ {
+			key := m[len(m)-n:]
+			h.suffix[key] = append(h.suffix[key], withBonus(term, weightPhoneSuffix))
+		}
+	}
+}
+
+// Remove 从 ID、手机号与尾号键下移除该术语
+func (h *Hash) Remove(term suggest.Term) {
+	if term.ID != 0 {
+		h.removeFrom(h.table, term.ID, term.ID)
+	}
+	for _, m := range mobiles(term) {
+		if mid, err := strconv.ParseInt(m, 10, 64); err == nil {
+			h.removeFrom(h.table, mid, term.ID)
+		}
+		for n := minSuffixLen; n < len(m); n++ {
+			key := m[len(m)-n:]
+			if left := removeID(h.suffix[key], term.ID); len(left) > 0 {
+				h.suffix[key] = left
+			} else {
+				delete(h.suffix, key)
 			}
-			h.table[mid] = append(h.table[mid], term)
 		}
 	}
 }
 
-// Search returns entries for an exact numeric key.
+func (h *Hash) removeFrom(table map[int64][]suggest.Term, key, id int64) {
+	if left := removeID(table[key], id); len(left) > 0 {
+		table[key] = left
+	} else {
+		delete(table, key)
+	}
+}
+
+// Search returns entries for an exact numeric key, followed by phone-suffix matches.
 func (h *Hash) Search(key string) []suggest.Term {
-	k, err := strconv.ParseInt(key, 10, 64)
-	if err != nil {
-		return nil
+	var out []suggest.Term
+	if k, err := strconv.ParseInt(key, 10, 64); err == nil {
+		out = append(out, h.table[k]...)
+	}
+	if len(key) >= minSuffixLen {
+		out = append(out, h.suffix[key]...)
 	}
-	return h.table[k]
+	return out
 }
@@ -0,0 +1,106 @@
+package search
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/suggest"
+	"github.com/FangcunMount/iam-contracts/pkg/tenant"
+)
+
+// 匹配类型对应的相关度加权，最终 Weight = 基础权重 + 匹配加权
+const (
+	weightExactNumber = 400 // ID/手机号精确匹配
+	weightName        = 300 // 中文名前缀
+	weightFullPinyin  = 200 // 全拼前缀
+	weightPhoneSuffix = 150 // 手机尾号
+	weightInitials    = 100 // 简拼前缀
+	weightExactKey    = 50  // 关键词与索引键完全相等
+)
+
+// minSuffixLen 手机尾号最短匹配位数
+const minSuffixLen = 4
+
+// Row 一条索引行；Deleted 为 true 时表示墓碑，需从索引中移除对应 ID
+type Row struct {
+	Term    suggest.Term
+	Deleted bool
+}
+
+// ParseLine 解析 name|id|mobiles|tenant|weight|guardians|deleted 行
+//
+// 第 4 列早期为占位符 "-"，此时归入默认租户；第 6、7 列可省略，第 7 列为 "1" 表示墓碑。
+func ParseLine(line string) (Row, bool) {
+	parts := strings.Split(line, "|")
+	if len(parts) < 5 {
+		return Row{}, false
+	}
+	name := strings.TrimSpace(parts[0])
+	id, _ := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+	tenantID := strings.TrimSpace(parts[3])
+	if tenantID == "" || tenantID == "-" {
+		tenantID = tenant.DefaultID
+	}
+	weight, _ := strconv.Atoi(strings.TrimSpace(parts[4]))
+	term := suggest.Term{
+		Name:     name,
+		ID:       id,
+		Mobile:   strings.TrimSpace(parts[2]),
+		Weight:   weight,
+		TenantID: tenantID,
+	}
+	if len(parts) > 5 {
+		for _, g := range strings.Split(parts[5], ",") {
+			if gid, err := strconv.ParseInt(strings.TrimSpace(g), 10, 64); err == nil && gid != 0 {
+				term.Guardians = append(term.Guardians, gid)
+			}
+		}
+	}
+	row := Row{Term: term}
+	if len(parts) > 6 {
+		row.Deleted = strings.TrimSpace(parts[6]) == "1"
+	}
+	return row, true
+}
+
+// FormatLine 把术语编码为索引行，与 ParseLine 互逆
+func FormatLine(term suggest.Term, deleted bool) string {
+	guardians := make([]string, 0, len(term.Guardians))
+	for _, g := range term.Guardians {
+		guardians = append(guardians, strconv.FormatInt(g, 10))
+	}
+	tenantID := term.TenantID
+	if tenantID == "" {
+		tenantID = "-"
+	}
+	flag := "0"
+	if deleted {
+		flag = "1"
+	}
+	return strings.Join([]string{
+		term.Name,
+		strconv.FormatInt(term.ID, 10),
+		term.Mobile,
+		tenantID,
+		strconv.Itoa(term.Weight),
+		strings.Join(guardians, ","),
+		flag,
+	}, "|")
+}
+
+// withBonus 返回加权后的副本
+func withBonus(term suggest.Term, bonus int) suggest.Term {
+	term.Weight += bonus
+	return term
+}
+
+// mobiles 拆分逗号分隔的手机号
+func mobiles(term suggest.Term) []string {
+	var out []string
+	for _, m := range strings.Split(term.Mobile, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			out = append(out, m)
+		}
+	}
+	return out
+}
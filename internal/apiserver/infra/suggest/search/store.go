@@ -10,22 +10,27 @@ import (
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/suggest"
 )
 
-// Store 存储器
+// Store 存储器，按租户分区，不同租户的数据互不可见
 type Store struct {
+	partitions map[string]*partition
+	rows       map[int64]suggest.Term // 按 ID 记录已入索引的原始术语，用于更新与删除
+	mu         sync.RWMutex
+}
+
+// partition 单个租户的索引
+type partition struct {
 	trie  *Trie
 	table *Hash
-	mu    sync.RWMutex
+	size  int
 }
 
 var active atomic.Value // 当前活跃的存储器
 
 // Load 从原始行构建 Store
 func Load(lines []string) *Store {
-	t := NewTrie()
-	h := NewHash()
-	t.ImportLines(lines)
-	h.ImportLines(lines)
-	return &Store{trie: t, table: h}
+	s := &Store{partitions: make(map[string]*partition), rows: make(map[int64]suggest.Term)}
+	s.importLines(lines)
+	return s
 }
 
 // Swap 原子替换当前活跃的存储器
@@ -39,19 +44,103 @@ func Current() *Store {
 	return nil
 }
 
-// ImportLines 追加新数据到现有存储器（受锁保护）
+// ImportLines 把增量行应用到现有存储器（受锁保护）：同 ID 原地更新，墓碑行移除
 func (s *Store) ImportLines(lines []string) {
 	if s == nil || len(lines) == 0 {
 		return
 	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.trie.ImportLines(lines)
-	s.table.ImportLines(lines)
+	s.importLines(lines)
+}
+
+func (s *Store) importLines(lines []string) {
+	for _, line := range lines {
+		row, ok := ParseLine(line)
+		if !ok {
+			continue
+		}
+		if row.Deleted {
+			s.remove(row.Term.ID)
+			continue
+		}
+		if row.Term.Name == "" {
+			continue
+		}
+		s.upsert(row.Term)
+	}
+}
+
+// upsert 先移除旧术语（可能位于其他租户分区），再插入新术语
+func (s *Store) upsert(term suggest.Term) {
+	if term.ID != 0 {
+		s.remove(term.ID)
+		s.rows[term.ID] = term
+	}
+	p := s.partitions[term.TenantID]
+	if p == nil {
+		p = &partition{trie: NewTrie(), table: NewHash()}
+		s.partitions[term.TenantID] = p
+	}
+	p.trie.Add(term)
+	p.table.Add(term)
+	p.size++
+}
+
+// remove 按 ID 从所属分区移除术语
+func (s *Store) remove(id int64) {
+	old, ok := s.rows[id]
+	if !ok {
+		return
+	}
+	delete(s.rows, id)
+	p := s.partitions[old.TenantID]
+	if p == nil {
+		return
+	}
+	p.trie.Remove(old)
+	p.table.Remove(old)
+	p.size--
+	if p.size <= 0 {
+		delete(s.partitions, old.TenantID)
+	}
+}
+
+// Lines 按 ID 升序导出当前全部术语，可直接用于快照与 Load
+func (s *Store) Lines() []string {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := make([]int64, 0, len(s.rows))
+	for id := range s.rows {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, FormatLine(s.rows[id], false))
+	}
+	return out
+}
+
+// Tenants 返回各租户分区的条目数
+func (s *Store) Tenants() map[string]int {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[string]int, len(s.partitions))
+	for id, p := range s.partitions {
+		out[id] = p.size
+	}
+	return out
 }
 
-// Suggest 返回有序且去重的术语
-func (s *Store) Suggest(keyword string, max int, pad int) []suggest.Term {
+// Suggest 在 scope 指定的租户分区内查询，返回按相关度降序且去重的术语
+func (s *Store) Suggest(scope suggest.Scope, keyword string, max int, pad int) []suggest.Term {
 	if s == nil {
 		return nil
 	}
@@ -59,38 +148,67 @@ func (s *Store) Suggest(keyword string, max int, pad int) []suggest.Term {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	p := s.partitions[scope.TenantID]
+	if p == nil {
+		return nil
+	}
+
 	if max <= 0 {
 		max = 20
 	}
 
-	// 数字走 Hash
+	keyword = normalizeKeyword(keyword)
+	if keyword == "" {
+		return nil
+	}
+
+	var out Terms
 	if isDigits(keyword) {
-		terms := Terms(s.table.Search(keyword))
-		terms = RemoveDuplicate(terms)
-		sort.Sort(terms)
-		if len(terms) > max {
-			terms = terms[:max]
+		// 数字走 Hash：ID/手机号精确匹配 + 手机尾号
+		out = Terms(p.table.Search(keyword))
+	} else {
+		// 前缀通配：中文名/全拼/简拼
+		k := keyword
+		if len([]rune(k)) < pad {
+			k = k + strings.Repeat("*", pad-len([]rune(k)))
+		}
+		for _, key := range p.trie.Wildcard(k) {
+			v, ok := p.trie.Get(key).(Terms)
+			if !ok {
+				continue
+			}
+			if key == keyword {
+				for _, term := range v {
+					out = append(out, withBonus(term, weightExactKey))
+				}
+				continue
+			}
+			out = append(out, v...)
 		}
-		return terms
-	}
-	// 前缀通配
-	k := keyword
-	if len([]rune(k)) < pad {
-		k = k + strings.Repeat("*", pad-len([]rune(k)))
 	}
-	keys := s.trie.Wildcard(k)
-	var out Terms
-	for _, key := range keys {
-		if v := s.trie.Get(key); v != nil {
-			out = append(out, v.(Terms)...)
+
+	return rank(out, scope, max)
+}
+
+// rank 过滤作用域外的结果，去重后按相关度降序截断
+func rank(list Terms, scope suggest.Scope, max int) Terms {
+	filtered := list[:0:0]
+	for _, term := range list {
+		if scope.Allows(term) {
+			filtered = append(filtered, term)
 		}
 	}
-	out = RemoveDuplicate(out)
-	sort.Sort(out)
-	if len(out) > max {
-		out = out[:max]
+	filtered = RemoveDuplicate(filtered)
+	sort.Stable(filtered)
+	if len(filtered) > max {
+		filtered = filtered[:max]
 	}
-	return out
+	return filtered
+}
+
+// normalizeKeyword 去除空白并统一小写，便于拼音匹配
+func normalizeKeyword(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), ""))
 }
 
 // isDigits 判断是否为数字
@@ -1,6 +1,13 @@
 package search
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/suggest"
+	"github.com/FangcunMount/iam-contracts/pkg/tenant"
+)
+
+var defaultScope = suggest.Scope{TenantID: tenant.DefaultID}
 
 func TestSuggestByPrefixAndPinyin(t *testing.T) {
 	lines := []string{
@@ -12,7 +19,7 @@ func TestSuggestByPrefixAndPinyin(t *testing.T) {
 
 	store := Load(lines)
 
-	out := store.Suggest("张", 5, 6)
+	out := store.Suggest(defaultScope, "张", 5, 6)
 	if len(out) != 2 {
 		t.Fatalf("expected 2 results, got %d", len(out))
 	}
@@ -20,12 +27,12 @@ func TestSuggestByPrefixAndPinyin(t *testing.T) {
 		t.Fatalf("expected first id 3, got %d", out[0].ID)
 	}
 
-	abbr := store.Suggest("zsf", 3, 6)
+	abbr := store.Suggest(defaultScope, "zsf", 3, 6)
 	if len(abbr) != 1 || abbr[0].ID != 3 {
 		t.Fatalf("abbr expected id 3, got %+v", abbr)
 	}
 
-	pinyin := store.Suggest("zhang", 5, 8)
+	pinyin := store.Suggest(defaultScope, "zhang", 5, 8)
 	if len(pinyin) != 2 {
 		t.Fatalf("pinyin expected 2 results, got %d", len(pinyin))
 	}
@@ -40,11 +47,155 @@ func TestSuggestNumericDedupAndSort(t *testing.T) {
 
 	store := Load(lines)
 
-	out := store.Suggest("13900139000", 5, 4)
+	out := store.Suggest(defaultScope, "13900139000", 5, 4)
 	if len(out) != 2 {
 		t.Fatalf("expected 2 results, got %d", len(out))
 	}
-	if out[0].ID != 2 || out[0].Weight != 10 {
-		t.Fatalf("expected highest weight record first, got %+v", out[0])
+	if out[0].ID != 2 || out[0].Weight <= out[1].Weight {
+		t.Fatalf("expected highest weight record first, got %+v", out)
+	}
+}
+
+func TestSuggestIsPartitionedByTenant(t *testing.T) {
+	store := Load([]string{
+		"张三|1|13800138000|tenant-a|5|100",
+		"张三丰|2|13800138001|tenant-b|5|200",
+	})
+
+	a := store.Suggest(suggest.Scope{TenantID: "tenant-a"}, "zhang", 5, 8)
+	if len(a) != 1 || a[0].ID != 1 {
+		t.Fatalf("tenant-a expected only id 1, got %+v", a)
+	}
+	b := store.Suggest(suggest.Scope{TenantID: "tenant-b"}, "13800138000", 5, 8)
+	if len(b) != 0 {
+		t.Fatalf("tenant-b must not see tenant-a phone, got %+v", b)
+	}
+	if none := store.Suggest(suggest.Scope{TenantID: "tenant-c"}, "张", 5, 8); len(none) != 0 {
+		t.Fatalf("unknown tenant expected no results, got %+v", none)
+	}
+	if got := store.Tenants(); got["tenant-a"] != 1 || got["tenant-b"] != 1 {
+		t.Fatalf("unexpected partition sizes %+v", got)
+	}
+}
+
+func TestSuggestGuardianScope(t *testing.T) {
+	store := Load([]string{
+		"张三|1|13800138000|-|5|100,101",
+		"张四|2|13800138001|-|9|200",
+	})
+
+	out := store.Suggest(suggest.Scope{TenantID: tenant.DefaultID, GuardianID: 101}, "zh", 5, 8)
+	if len(out) != 1 || out[0].ID != 1 {
+		t.Fatalf("guardian 101 expected only id 1, got %+v", out)
+	}
+	all := store.Suggest(defaultScope, "zh", 5, 8)
+	if len(all) != 2 {
+		t.Fatalf("tenant scope expected 2 results, got %+v", all)
+	}
+}
+
+func TestSuggestPhoneSuffixAndRelevance(t *testing.T) {
+	store := Load([]string{
+		"张三|1|13800138000|-|5",
+		"李四|2|13900008000|-|5",
+		"王五|3|18800001111|-|5",
+	})
+
+	out := store.Suggest(defaultScope, "8000", 5, 8)
+	if len(out) != 2 {
+		t.Fatalf("suffix expected 2 results, got %+v", out)
+	}
+
+	// 中文名精确 > 中文名前缀 > 全拼 > 简拼
+	store = Load([]string{
+		"张三|1|-|-|0",
+		"张三丰|2|-|-|0",
+	})
+	exact := store.Suggest(defaultScope, "张三", 5, 8)
+	if len(exact) != 2 || exact[0].ID != 1 || exact[0].Weight <= exact[1].Weight {
+		t.Fatalf("exact name expected first, got %+v", exact)
+	}
+	name := store.Suggest(defaultScope, "张", 5, 8)
+	full := store.Suggest(defaultScope, "zhang", 5, 8)
+	abbr := store.Suggest(defaultScope, "zs", 5, 8)
+	if !(name[0].Weight > full[0].Weight && full[0].Weight > abbr[0].Weight) {
+		t.Fatalf("unexpected relevance order name=%d full=%d abbr=%d", name[0].Weight, full[0].Weight, abbr[0].Weight)
+	}
+}
+
+func TestParseLineLegacyAndExtendedFormats(t *testing.T) {
+	legacy, ok := ParseLine("张三|1|13800138000|-|5")
+	if !ok || legacy.Term.TenantID != tenant.DefaultID || len(legacy.Term.Guardians) != 0 || legacy.Deleted {
+		t.Fatalf("unexpected legacy parse %+v", legacy)
+	}
+	ext, ok := ParseLine("张三|1|13800138000|t1|5|7,8")
+	if !ok || ext.Term.TenantID != "t1" || len(ext.Term.Guardians) != 2 || ext.Term.Guardians[1] != 8 {
+		t.Fatalf("unexpected extended parse %+v", ext)
+	}
+	tomb, ok := ParseLine("张三|1||t1|0||1")
+	if !ok || !tomb.Deleted || tomb.Term.ID != 1 {
+		t.Fatalf("unexpected tombstone parse %+v", tomb)
+	}
+	if _, ok := ParseLine("bad"); ok {
+		t.Fatal("expected malformed line to be rejected")
+	}
+}
+
+func TestStoreImportLinesUpdatesInPlace(t *testing.T) {
+	store := Load([]string{"张三|1|13800138000|-|5"})
+	store.ImportLines([]string{"李四|1|13900139000|-|5"})
+
+	if got := store.Suggest(defaultScope, "张三", 5, 8); len(got) != 0 {
+		t.Fatalf("expected old name removed, got %+v", got)
+	}
+	if got := store.Suggest(defaultScope, "8000", 5, 8); len(got) != 0 {
+		t.Fatalf("expected old mobile suffix removed, got %+v", got)
+	}
+	got := store.Suggest(defaultScope, "lisi", 5, 8)
+	if len(got) != 1 || got[0].ID != 1 {
+		t.Fatalf("expected renamed term, got %+v", got)
+	}
+	if size := store.Tenants()[tenant.DefaultID]; size != 1 {
+		t.Fatalf("expected partition size 1, got %d", size)
+	}
+}
+
+func TestStoreImportLinesAppliesTombstones(t *testing.T) {
+	store := Load([]string{
+		"张三|1|13800138000|t1|5|7",
+		"张四|2|13800138001|t1|5|7",
+	})
+	store.ImportLines([]string{"张三|1||t1|0||1"})
+
+	scope := suggest.Scope{TenantID: "t1"}
+	got := store.Suggest(scope, "zhang", 5, 8)
+	if len(got) != 1 || got[0].ID != 2 {
+		t.Fatalf("expected only term 2 after tombstone, got %+v", got)
+	}
+	if got := store.Suggest(scope, "1", 5, 8); len(got) != 0 {
+		t.Fatalf("expected id lookup removed, got %+v", got)
+	}
+
+	// 同一 ID 迁移到其他租户后，旧分区不再可见
+	store.ImportLines([]string{"张四|2|13800138001|t2|5|7"})
+	if got := store.Suggest(scope, "zhang", 5, 8); len(got) != 0 {
+		t.Fatalf("expected t1 empty after move, got %+v", got)
+	}
+	if _, ok := store.Tenants()["t1"]; ok {
+		t.Fatal("expected empty partition to be dropped")
+	}
+	if got := store.Suggest(suggest.Scope{TenantID: "t2"}, "zhang", 5, 8); len(got) != 1 {
+		t.Fatalf("expected term in t2, got %+v", got)
+	}
+}
+
+func TestStoreLinesRoundTrip(t *testing.T) {
+	store := Load([]string{"李四|2|13900139000|t1|3|9", "张三|1|13800138000|-|5"})
+	reloaded := Load(store.Lines())
+	if got := reloaded.Suggest(suggest.Scope{TenantID: "t1"}, "lisi", 5, 8); len(got) != 1 || got[0].Guardians[0] != 9 {
+		t.Fatalf("unexpected round trip result %+v", got)
+	}
+	if len(reloaded.Lines()) != 2 {
+		t.Fatalf("expected 2 lines, got %v", reloaded.Lines())
 	}
 }
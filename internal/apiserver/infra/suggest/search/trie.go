@@ -3,7 +3,6 @@ package search
 import (
 	"bufio"
 	"os"
-	"strconv"
 	"strings"
 
 	"github.com/mozillazg/go-pinyin"
@@ -35,20 +34,19 @@ func (t Terms) Len() int           { return len(t) }
 func (t Terms) Less(i, j int) bool { return t[i].Weight > t[j].Weight }
 func (t Terms) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
 
-// RemoveDuplicate 去重，保留顺序
+// RemoveDuplicate 按 ID 去重，保留首次出现的位置，权重取各次出现中的最大值
 func RemoveDuplicate(list Terms) Terms {
 	var out Terms
+	index := make(map[int64]int, len(list))
 	for _, cur := range list {
-		found := false
-		for _, v := range out {
-			if v.ID == cur.ID {
-				found = true
-				break
+		if i, ok := index[cur.ID]; ok {
+			if cur.Weight > out[i].Weight {
+				out[i] = cur
 			}
+			continue
 		}
-		if !found {
-			out = append(out, cur)
-		}
+		index[cur.ID] = len(out)
+		out = append(out, cur)
 	}
 	return out
 }
@@ -58,43 +56,80 @@ func NewTrie() *Trie {
 	return &Trie{}
 }
 
-// ImportLines 解析 name|id|mobiles|disease|weight 行并插入术语
+// ImportLines 解析索引行并插入术语（墓碑行会移除对应术语）
 func (t *Trie) ImportLines(lines []string) {
-	pyArgs := pinyin.NewArgs()
 	for _, line := range lines {
-		parts := strings.Split(line, "|")
-		if len(parts) < 5 {
+		row, ok := ParseLine(line)
+		if !ok {
 			continue
 		}
-		name := strings.TrimSpace(parts[0])
-		if name == "" {
+		if row.Deleted {
+			t.Remove(row.Term)
 			continue
 		}
-		idStr := strings.TrimSpace(parts[1])
-		id, _ := strconv.ParseInt(idStr, 10, 64)
-		mobile := strings.TrimSpace(parts[2])
-		_ = strings.TrimSpace(parts[3]) // disease field ignored
-		weight, _ := strconv.Atoi(strings.TrimSpace(parts[4]))
-		term := suggest.Term{Name: name, ID: id, Mobile: mobile, Weight: weight}
-
-		// 原始中文名
-		t.Put(name, term)
-		// 拼音/简拼
-		py := pinyin.Pinyin(name, pyArgs)
-		if len(py) == 0 {
+		t.Add(row.Term)
+	}
+}
+
+// Add 以中文名、全拼、简拼为键插入术语，不同键携带不同的匹配加权
+func (t *Trie) Add(term suggest.Term) {
+	for _, k := range trieKeys(term.Name) {
+		t.Put(k.key, withBonus(term, k.bonus))
+	}
+}
+
+// Remove 从术语名对应的所有键下移除该 ID
+func (t *Trie) Remove(term suggest.Term) {
+	for _, k := range trieKeys(term.Name) {
+		n := t.find(k.key)
+		if n == nil {
 			continue
 		}
-		py[0] = uniq(py[0])
-		for _, a := range py[0] {
-			full, abbr := a, string(a[0])
-			for _, b := range py[1:] {
-				full += b[0]
-				abbr += string(b[0][0])
-			}
-			t.Put(full, term)
-			t.Put(abbr, term)
+		n.value = removeID(n.value, term.ID)
+		if len(n.value) == 0 {
+			// 保留节点结构，仅清除终止标记，避免重排三元树
+			n.end = false
+		}
+	}
+}
+
+// trieKey 索引键及其匹配加权
+type trieKey struct {
+	key   string
+	bonus int
+}
+
+// trieKeys 返回名称对应的索引键：中文名、全拼、简拼
+func trieKeys(name string) []trieKey {
+	if name == "" {
+		return nil
+	}
+	keys := []trieKey{{key: name, bonus: weightName}}
+	py := pinyin.Pinyin(name, pinyin.NewArgs())
+	if len(py) == 0 {
+		return keys
+	}
+	py[0] = uniq(py[0])
+	for _, a := range py[0] {
+		full, abbr := a, string(a[0])
+		for _, b := range py[1:] {
+			full += b[0]
+			abbr += string(b[0][0])
 		}
+		keys = append(keys, trieKey{key: full, bonus: weightFullPinyin}, trieKey{key: abbr, bonus: weightInitials})
 	}
+	return keys
+}
+
+// removeID 移除指定 ID 的术语
+func removeID(list Terms, id int64) Terms {
+	out := list[:0]
+	for _, term := range list {
+		if term.ID != id {
+			out = append(out, term)
+		}
+	}
+	return out
 }
 
 // uniq 去重
@@ -159,6 +194,14 @@ func (t *Trie) putRecursive(n *node, key []rune, idx int, term suggest.Term) *no
 
 // Get 获取精确匹配的术语
 func (t *Trie) Get(key string) interface{} {
+	if n := t.find(key); n != nil {
+		return n.value
+	}
+	return nil
+}
+
+// find 返回精确匹配 key 的终止节点
+func (t *Trie) find(key string) *node {
 	n := t.root
 	rkey := []rune(key)
 	for i, r := range rkey {
@@ -169,7 +212,7 @@ func (t *Trie) Get(key string) interface{} {
 				n = n.large
 			} else {
 				if i == len(rkey)-1 && n.end {
-					return n.value
+					return n
 				}
 				n = n.equal
 				break
@@ -13,7 +13,7 @@ import (
 )
 
 // IdentityProviderImpl 微信身份提供商的实现
-// - 微信小程序登录：委托 IDP 模块提供的 AuthProvider 调用微信接口
+// - 微信小程序 / 公众号网页授权登录：委托 IDP 模块提供的 AuthProvider 调用微信接口
 // - 企业微信登录：暂时保留 silenceper SDK 实现
 type IdentityProviderImpl struct {
 	miniAuth wechatAuthPort.AuthProvider
@@ -48,6 +48,26 @@ func (p *IdentityProviderImpl) ExchangeWxMinipCode(ctx context.Context, appID, a
 	return result.OpenID, result.UnionID, nil
 }
 
+// ExchangeWxMPCode 微信公众号网页授权 code 换取用户身份
+// 文档: https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/Wechat_webpage_authorization.html
+func (p *IdentityProviderImpl) ExchangeWxMPCode(ctx context.Context, appID, appSecret, code string) (authPort.WxMPIdentity, error) {
+	if p.miniAuth == nil {
+		return authPort.WxMPIdentity{}, fmt.Errorf("wechat auth provider is not configured")
+	}
+
+	result, err := p.miniAuth.OAuth2Exchange(ctx, appID, appSecret, code)
+	if err != nil {
+		return authPort.WxMPIdentity{}, fmt.Errorf("failed to exchange oauth code: %w", err)
+	}
+	return authPort.WxMPIdentity{
+		OpenID:    result.OpenID,
+		UnionID:   result.UnionID,
+		Scope:     result.Scope,
+		Nickname:  result.Nickname,
+		AvatarURL: result.AvatarURL,
+	}, nil
+}
+
 // ExchangeWecomCode 企业微信 code 换取用户信息
 // 文档: https://developer.work.weixin.qq.com/document/path/91023
 func (p *IdentityProviderImpl) ExchangeWecomCode(ctx context.Context, corpID, agentID, corpSecret, code string) (openUserID, userID string, err error) {
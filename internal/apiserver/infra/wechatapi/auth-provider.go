@@ -9,6 +9,7 @@ import (
 	"github.com/silenceper/wechat/v2"
 	"github.com/silenceper/wechat/v2/cache"
 	miniConfig "github.com/silenceper/wechat/v2/miniprogram/config"
+	offConfig "github.com/silenceper/wechat/v2/officialaccount/config"
 
 	wechatapp "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/wechatapi/port"
 )
@@ -106,3 +107,47 @@ func (p *AuthProvider) DecryptPhone(ctx context.Context, appID, appSecret, sessi
 
 	return result, nil
 }
+
+// OAuth2Exchange 公众号网页授权 code 换取用户身份
+// scope 为 snsapi_userinfo 时继续拉取昵称、头像
+func (p *AuthProvider) OAuth2Exchange(ctx context.Context, appID, appSecret, code string) (wechatapp.OAuth2Result, error) {
+	result := wechatapp.OAuth2Result{}
+	if appID == "" || appSecret == "" {
+		return result, errors.New("appID and appSecret cannot be empty")
+	}
+	if code == "" {
+		return result, errors.New("code cannot be empty")
+	}
+
+	cfg := &offConfig.Config{
+		AppID:     appID,
+		AppSecret: appSecret,
+		Cache:     p.cache,
+	}
+	oauth := wechat.NewWechat().GetOfficialAccount(cfg).GetOauth()
+
+	token, err := oauth.GetUserAccessTokenContext(ctx, code)
+	if err != nil {
+		return result, fmt.Errorf("failed to exchange oauth code: %w", err)
+	}
+	if token.OpenID == "" {
+		return result, errors.New("empty openid returned")
+	}
+	result.OpenID = token.OpenID
+	result.UnionID = token.UnionID
+	result.Scope = token.Scope
+
+	if token.Scope == "snsapi_userinfo" {
+		info, err := oauth.GetUserInfoContext(ctx, token.AccessToken, token.OpenID, "zh_CN")
+		if err != nil {
+			return result, fmt.Errorf("failed to get oauth user info: %w", err)
+		}
+		result.Nickname = info.Nickname
+		result.AvatarURL = info.HeadImgURL
+		if result.UnionID == "" {
+			result.UnionID = info.Unionid
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,37 @@
+package wechatapi
+
+import (
+	"errors"
+
+	"github.com/silenceper/wechat/v2/util"
+
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+)
+
+// CallbackCodec 微信消息推送验签与安全模式解密（使用 silenceper SDK 的算法实现）
+type CallbackCodec struct{}
+
+var _ domain.CallbackCodec = (*CallbackCodec)(nil)
+
+// NewCallbackCodec 创建回调验签/解密器
+func NewCallbackCodec() *CallbackCodec {
+	return &CallbackCodec{}
+}
+
+// Sign 对 token、timestamp、nonce 及可选密文做字典序 SHA1 签名
+func (c *CallbackCodec) Sign(token, timestamp, nonce string, extra ...string) string {
+	params := append([]string{token, timestamp, nonce}, extra...)
+	return util.Signature(params...)
+}
+
+// Decrypt AES-CBC 解密安全模式密文并校验 AppID
+func (c *CallbackCodec) Decrypt(appID, encodingAESKey, ciphertext string) ([]byte, error) {
+	if appID == "" || encodingAESKey == "" || ciphertext == "" {
+		return nil, errors.New("appID, encodingAESKey and ciphertext cannot be empty")
+	}
+	_, plaintext, err := util.DecryptMsg(appID, ciphertext, encodingAESKey)
+	if err != nil {
+		return nil, err
+	}
+	return plaintext, nil
+}
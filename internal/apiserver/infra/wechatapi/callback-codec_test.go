@@ -0,0 +1,33 @@
+package wechatapi
+
+import (
+	"testing"
+
+	"github.com/silenceper/wechat/v2/util"
+	"github.com/stretchr/testify/require"
+)
+
+const testEncodingAESKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+
+func TestCallbackCodec_DecryptRoundTrip(t *testing.T) {
+	codec := NewCallbackCodec()
+	raw := []byte("<xml><MsgType><![CDATA[event]]></MsgType></xml>")
+
+	// EncryptMsg 返回的已是 Base64 密文（即 Encrypt 字段）
+	encrypted, err := util.EncryptMsg([]byte("0123456789abcdef"), raw, "wx-mp", testEncodingAESKey)
+	require.NoError(t, err)
+
+	plaintext, err := codec.Decrypt("wx-mp", testEncodingAESKey, string(encrypted))
+	require.NoError(t, err)
+	require.Equal(t, raw, plaintext)
+
+	// 密文中的 AppID 与回调路径不一致时拒绝
+	_, err = codec.Decrypt("wx-other", testEncodingAESKey, string(encrypted))
+	require.Error(t, err)
+}
+
+func TestCallbackCodec_SignIsOrderIndependent(t *testing.T) {
+	codec := NewCallbackCodec()
+	require.Equal(t, util.Signature("nonce", "token", "123"), codec.Sign("token", "123", "nonce"))
+	require.NotEqual(t, codec.Sign("token", "123", "nonce"), codec.Sign("token", "123", "nonce", "cipher"))
+}
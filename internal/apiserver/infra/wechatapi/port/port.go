@@ -3,10 +3,11 @@ package port
 import "context"
 
 // AuthProvider covers the subset of WeChat authentication APIs
-// that higher layers depend on (code2Session / phone decrypt).
+// that higher layers depend on (code2Session / phone decrypt / official account web OAuth).
 type AuthProvider interface {
 	Code2Session(ctx context.Context, appID, appSecret, jsCode string) (Code2SessionResult, error)
 	DecryptPhone(ctx context.Context, appID, appSecret, sessionKey, encryptedData, iv string) (DecryptPhoneResult, error)
+	OAuth2Exchange(ctx context.Context, appID, appSecret, code string) (OAuth2Result, error)
 }
 
 // Code2SessionResult captures the response we care about when exchanging jsCode.
@@ -22,3 +23,13 @@ type DecryptPhoneResult struct {
 	PurePhoneNumber string
 	CountryCode     string
 }
+
+// OAuth2Result carries the official account web OAuth identity.
+// Nickname / AvatarURL are only filled for the snsapi_userinfo scope.
+type OAuth2Result struct {
+	OpenID    string
+	UnionID   string
+	Scope     string
+	Nickname  string
+	AvatarURL string
+}
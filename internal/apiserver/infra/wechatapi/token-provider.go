@@ -10,6 +10,7 @@ import (
 	"github.com/silenceper/wechat/v2/cache"
 	miniConfig "github.com/silenceper/wechat/v2/miniprogram/config"
 	offiaConfig "github.com/silenceper/wechat/v2/officialaccount/config"
+	workConfig "github.com/silenceper/wechat/v2/work/config"
 )
 
 // AccessTokenResult 访问令牌结果
@@ -64,6 +65,23 @@ func (p *TokenProvider) FetchOfficialAccountToken(ctx context.Context, appID, ap
 	}, nil
 }
 
+// FetchWorkToken 获取企业微信应用访问令牌
+func (p *TokenProvider) FetchWorkToken(ctx context.Context, corpID, agentID, corpSecret string) (*AccessTokenResult, error) {
+	if corpID == "" || corpSecret == "" {
+		return nil, errors.New("corpID and corpSecret cannot be empty")
+	}
+
+	accessToken, expiresIn, err := p.fetchWorkToken(corpID, agentID, corpSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccessTokenResult{
+		Token:     accessToken,
+		ExpiresAt: time.Now().Add(time.Duration(expiresIn) * time.Second),
+	}, nil
+}
+
 // fetchMiniProgramToken 获取小程序 access_token
 func (p *TokenProvider) fetchMiniProgramToken(appID, appSecret string) (string, int64, error) {
 	wc := wechat.NewWechat()
@@ -103,3 +121,22 @@ func (p *TokenProvider) fetchOfficialAccountToken(appID, appSecret string) (stri
 	// SDK 返回的 token 已经是字符串，默认有效期 7200 秒
 	return accessToken, 7200, nil
 }
+
+// fetchWorkToken 获取企业微信 access_token
+func (p *TokenProvider) fetchWorkToken(corpID, agentID, corpSecret string) (string, int64, error) {
+	cfg := &workConfig.Config{
+		CorpID:     corpID,
+		AgentID:    agentID,
+		CorpSecret: corpSecret,
+		Cache:      p.cache,
+	}
+
+	workApp := wechat.NewWechat().GetWork(cfg)
+	accessToken, err := workApp.GetContext().GetAccessToken()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get wecom access token: %w", err)
+	}
+
+	// SDK 返回的 token 已经是字符串，默认有效期 7200 秒
+	return accessToken, 7200, nil
+}
@@ -96,8 +96,7 @@ func (s *authServiceServer) VerifyToken(ctx context.Context, req *authnv1.Verify
 			resp.Metadata = buildTokenMetadata(result.Claims)
 		}
 	} else {
-		resp.Status = authnv1.TokenStatus_TOKEN_STATUS_REVOKED
-		resp.FailureReason = "token invalid or expired"
+		resp.Status, resp.FailureReason = verifyFailure(result)
 	}
 	return resp, nil
 }
@@ -315,6 +314,20 @@ func toProtoTokenClaims(claims *tokenDomain.TokenClaims) *authnv1.TokenClaims {
 	return resp
 }
 
+// verifyFailure 将无效原因映射为令牌状态；会话超时视为过期，需重新登录
+func verifyFailure(result *tokenApp.TokenVerifyResult) (authnv1.TokenStatus, string) {
+	reason := ""
+	if result != nil {
+		reason = result.InvalidReason
+	}
+	switch reason {
+	case tokenApp.InvalidReasonExpired, tokenApp.InvalidReasonSessionIdle, tokenApp.InvalidReasonSessionLifetime:
+		return authnv1.TokenStatus_TOKEN_STATUS_EXPIRED, reason
+	default:
+		return authnv1.TokenStatus_TOKEN_STATUS_REVOKED, "token invalid or expired"
+	}
+}
+
 func buildTokenMetadata(claims *tokenDomain.TokenClaims) *authnv1.TokenMetadata {
 	if claims == nil {
 		return nil
@@ -101,6 +101,26 @@ func (s *memorySessionStore) RevokeByAccount(_ context.Context, accountID meta.I
 	return nil
 }
 
+func (s *memorySessionStore) ListByUser(_ context.Context, userID meta.ID) ([]*sessiondomain.Session, error) {
+	var out []*sessiondomain.Session
+	for _, sess := range s.sessions {
+		if sess.UserID == userID && sess.IsActive() {
+			out = append(out, sess)
+		}
+	}
+	return out, nil
+}
+
+func (s *memorySessionStore) ListByAccount(_ context.Context, accountID meta.ID) ([]*sessiondomain.Session, error) {
+	var out []*sessiondomain.Session
+	for _, sess := range s.sessions {
+		if sess.AccountID == accountID && sess.IsActive() {
+			out = append(out, sess)
+		}
+	}
+	return out, nil
+}
+
 type allowAllSubjectAccessEvaluator struct{}
 
 func (allowAllSubjectAccessEvaluator) Evaluate(context.Context, meta.ID, meta.ID) (sessiondomain.SubjectAccessDecision, error) {
@@ -149,7 +169,7 @@ func rsaPublicJWK(kid string, pub *rsa.PublicKey) jwksdomain.PublicJWK {
 	}
 }
 
-func newTestTokenStack(t *testing.T) (
+func newTestTokenStack(t *testing.T, opts ...sessiondomain.ManagerOption) (
 	tokenapp.TokenApplicationService,
 	*jwt.Generator,
 	*domaintoken.TokenIssuer,
@@ -171,7 +191,7 @@ func newTestTokenStack(t *testing.T) (
 	gen := jwt.NewGenerator("https://iam.integration.test", []string{"qs-api", "collection-api"}, &fixedKeyManager{active: active}, &staticPrivResolver{key: priv})
 	store := noopTokenStore{}
 	sessionStore := &memorySessionStore{}
-	sessionManager := sessiondomain.NewManager(sessionStore)
+	sessionManager := sessiondomain.NewManager(sessionStore, opts...)
 	issuer := domaintoken.NewTokenIssuer(gen, store, sessionManager, time.Hour, 24*time.Hour)
 	verifier := domaintoken.NewTokenVerifyer(gen, store, sessionManager, allowAllSubjectAccessEvaluator{})
 	svc := tokenapp.NewTokenApplicationService(issuer, nil, verifier)
@@ -312,3 +332,29 @@ func TestIntegration_VerifyToken_GRPC_IncludeMetadata(t *testing.T) {
 	require.True(t, gresp.Valid)
 	require.NotNil(t, gresp.Metadata)
 }
+
+func TestIntegration_VerifyToken_SessionIdleTimeoutReportsExpired(t *testing.T) {
+	ctx := context.Background()
+	tokenSvc, _, issuer := newTestTokenStack(t, sessiondomain.WithTimeoutPolicy(&sessiondomain.StaticTimeoutPolicyProvider{
+		Default: sessiondomain.TimeoutPolicy{IdleTimeout: time.Millisecond},
+	}))
+
+	pair, err := issuer.IssueToken(ctx, &authentication.Principal{
+		UserID:    meta.FromUint64(42),
+		AccountID: meta.FromUint64(43),
+	})
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	grpcSrv := &authServiceServer{tokenSvc: tokenSvc}
+	gresp, err := grpcSrv.VerifyToken(ctx, &authnv1.VerifyTokenRequest{AccessToken: pair.AccessToken.Value})
+	require.NoError(t, err)
+	require.False(t, gresp.Valid)
+	require.Equal(t, authnv1.TokenStatus_TOKEN_STATUS_EXPIRED, gresp.Status)
+	require.Equal(t, tokenapp.InvalidReasonSessionIdle, gresp.FailureReason)
+
+	// 超时会话被撤销，之后按普通失效处理
+	gresp, err = grpcSrv.VerifyToken(ctx, &authnv1.VerifyTokenRequest{AccessToken: pair.AccessToken.Value})
+	require.NoError(t, err)
+	require.Equal(t, authnv1.TokenStatus_TOKEN_STATUS_REVOKED, gresp.Status)
+}
@@ -360,3 +360,27 @@ func (h *AccountHandler) EnableAccount(c *gin.Context) {
 
 	h.Success(c, resp.MessageResponse{Message: "Account enabled successfully"})
 }
+
+// UnlockAccount 解锁账户
+// @Summary 解锁账户
+// @Description 清除账户凭据的失败计数与锁定时间，并清除登录名/手机号的登录失败计数（管理员操作）
+// @Tags 账户管理
+// @Param accountId path string true "账户ID"
+// @Success 200 {object} resp.MessageResponse "解锁成功"
+// @Failure 400 {object} map[string]interface{} "请求参数错误"
+// @Failure 404 {object} map[string]interface{} "账户不存在"
+// @Router /authn/accounts/{accountId}/unlock [post]
+func (h *AccountHandler) UnlockAccount(c *gin.Context) {
+	accountID, err := parseAccountID(c.Param("accountId"))
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	if err := h.accountService.UnlockAccount(c.Request.Context(), accountID); err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, resp.MessageResponse{Message: "Account unlocked successfully"})
+}
@@ -42,7 +42,7 @@ func NewAuthHandler(
 
 // Login 统一登录端点
 // @Summary 用户登录
-// @Description 支持多种登录方式：密码登录、手机验证码登录、微信小程序登录、企业微信登录
+// @Description 支持多种登录方式：密码登录、手机验证码登录、微信小程序登录、微信公众号网页授权登录、企业微信登录、上游 OIDC 联合登录
 // @Tags 认证
 // @Accept json
 // @Produce json
@@ -71,8 +71,12 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		h.handlePhoneOTPLogin(c, reqBody)
 	case "wechat":
 		h.handleWeChatLogin(c, reqBody)
+	case "wechat_mp":
+		h.handleWeChatMPLogin(c, reqBody)
 	case "wecom":
 		h.handleWeComLogin(c, reqBody)
+	case "oidc":
+		h.handleOIDCLogin(c, reqBody)
 	default:
 		h.Error(c, perrors.WithCode(code.ErrInvalidArgument, "unsupported authentication method: %s", reqBody.Method))
 	}
@@ -95,7 +99,7 @@ func (h *AuthHandler) handlePasswordLogin(c *gin.Context, reqBody req.LoginReque
 		loginReq.TenantID = meta.FromUint64(creds.TenantID)
 	}
 
-	h.executeLogin(c, loginReq)
+	h.executeLogin(c, reqBody, loginReq)
 }
 
 // handlePhoneOTPLogin 处理手机验证码登录
@@ -112,7 +116,7 @@ func (h *AuthHandler) handlePhoneOTPLogin(c *gin.Context, reqBody req.LoginReque
 		OTPCode:   &creds.OTPCode,
 	}
 
-	h.executeLogin(c, loginReq)
+	h.executeLogin(c, reqBody, loginReq)
 }
 
 // PreparePhoneOTPLogin 登录预准备：写入 Redis 后发布 OTP（sms.provider=mq 走 NSQ；log 仅打日志）
@@ -154,7 +158,24 @@ func (h *AuthHandler) handleWeChatLogin(c *gin.Context, reqBody req.LoginRequest
 		WechatJSCode: &creds.Code,
 	}
 
-	h.executeLogin(c, loginReq)
+	h.executeLogin(c, reqBody, loginReq)
+}
+
+// handleWeChatMPLogin 处理微信公众号网页授权登录
+func (h *AuthHandler) handleWeChatMPLogin(c *gin.Context, reqBody req.LoginRequest) {
+	var creds req.WeChatMPCredentials
+	if err := json.Unmarshal(reqBody.Credentials, &creds); err != nil {
+		h.Error(c, perrors.WithCode(code.ErrBind, "invalid wechat mp credentials: %v", err))
+		return
+	}
+
+	loginReq := login.LoginRequest{
+		AuthType:      login.AuthTypeWechatMP,
+		WechatMPAppID: &creds.AppID,
+		WechatMPCode:  &creds.Code,
+	}
+
+	h.executeLogin(c, reqBody, loginReq)
 }
 
 // handleWeComLogin 处理企业微信登录
@@ -170,12 +191,44 @@ func (h *AuthHandler) handleWeComLogin(c *gin.Context, reqBody req.LoginRequest)
 		WecomCorpID: &creds.CorpID,
 		WecomCode:   &creds.AuthCode,
 	}
+	if creds.AgentID != "" {
+		loginReq.WecomAgentID = &creds.AgentID
+	}
+
+	h.executeLogin(c, reqBody, loginReq)
+}
+
+// handleOIDCLogin 处理上游 OIDC 联合登录
+func (h *AuthHandler) handleOIDCLogin(c *gin.Context, reqBody req.LoginRequest) {
+	var creds req.OIDCCredentials
+	if err := json.Unmarshal(reqBody.Credentials, &creds); err != nil {
+		h.Error(c, perrors.WithCode(code.ErrBind, "invalid oidc credentials: %v", err))
+		return
+	}
+	if creds.Provider == "" || creds.Code == "" || creds.RedirectURI == "" || creds.State == "" {
+		h.Error(c, perrors.WithCode(code.ErrInvalidArgument, "provider, code, redirect_uri and state are required"))
+		return
+	}
+
+	loginReq := login.LoginRequest{
+		AuthType:        login.AuthTypeOIDC,
+		OIDCProvider:    &creds.Provider,
+		OIDCCode:        &creds.Code,
+		OIDCRedirectURI: &creds.RedirectURI,
+		OIDCState:       &creds.State,
+	}
+	if creds.CodeVerifier != "" {
+		loginReq.OIDCCodeVerifier = &creds.CodeVerifier
+	}
 
-	h.executeLogin(c, loginReq)
+	h.executeLogin(c, reqBody, loginReq)
 }
 
 // executeLogin 执行登录并返回令牌
-func (h *AuthHandler) executeLogin(c *gin.Context, loginReq login.LoginRequest) {
+func (h *AuthHandler) executeLogin(c *gin.Context, reqBody req.LoginRequest, loginReq login.LoginRequest) {
+	loginReq.RemoteIP = c.ClientIP()
+	loginReq.UserAgent = c.Request.UserAgent()
+	loginReq.DeviceName = reqBody.DeviceName
 	result, err := h.loginService.Login(c.Request.Context(), loginReq)
 	if err != nil {
 		h.Error(c, err)
@@ -183,7 +236,7 @@ func (h *AuthHandler) executeLogin(c *gin.Context, loginReq login.LoginRequest)
 	}
 
 	// 转换为 HTTP 响应格式
-	tokenPair := h.convertTokenPair(result.TokenPair)
+	tokenPair := convertTokenPair(result.TokenPair)
 	h.Success(c, tokenPair)
 }
 
@@ -251,7 +304,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	tokenPair := h.convertTokenPair(result.TokenPair)
+	tokenPair := convertTokenPair(result.TokenPair)
 	h.Success(c, tokenPair)
 }
 
@@ -291,6 +344,7 @@ func (h *AuthHandler) VerifyToken(c *gin.Context) {
 	response := resp.TokenVerifyResponse{
 		Valid:  result.Valid,
 		Claims: nil,
+		Reason: result.InvalidReason,
 	}
 
 	if result.Valid && result.Claims != nil {
@@ -367,7 +421,7 @@ func (h *AuthHandler) RevokeRefreshToken(c *gin.Context) {
 }
 
 // convertTokenPair 转换令牌对为 HTTP 响应格式
-func (h *AuthHandler) convertTokenPair(tokenPair *domainToken.TokenPair) *resp.TokenPair {
+func convertTokenPair(tokenPair *domainToken.TokenPair) *resp.TokenPair {
 	response := &resp.TokenPair{
 		TokenType: "Bearer",
 	}
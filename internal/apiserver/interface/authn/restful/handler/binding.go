@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	bindingApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/binding"
+	req "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/authn/restful/request"
+	resp "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/authn/restful/response"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// BindingHandler 账户身份绑定 HTTP 处理器
+type BindingHandler struct {
+	*BaseHandler
+	service bindingApp.BindingApplicationService
+}
+
+// NewBindingHandler 创建身份绑定处理器
+func NewBindingHandler(service bindingApp.BindingApplicationService) *BindingHandler {
+	return &BindingHandler{
+		BaseHandler: NewBaseHandler(),
+		service:     service,
+	}
+}
+
+// ListBindings 列出当前账户的登录方式
+// @Summary 列出登录方式
+// @Tags 账户绑定
+// @Produce json
+// @Success 200 {object} resp.BindingList
+// @Failure 401 {object} map[string]interface{} "未登录"
+// @Router /authn/bindings [get]
+func (h *BindingHandler) ListBindings(c *gin.Context) {
+	accountID, err := currentAccountID(c)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	results, err := h.service.ListBindings(c.Request.Context(), accountID)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	list := resp.BindingList{Items: make([]resp.Binding, 0, len(results))}
+	for _, r := range results {
+		list.Items = append(list.Items, toBindingResponse(r))
+	}
+	h.Success(c, list)
+}
+
+// BindWechat 绑定微信小程序
+// @Summary 绑定微信小程序
+// @Description 已登录账户凭 wx.login code 追加微信登录方式；该微信已属于其他账户时返回冲突
+// @Tags 账户绑定
+// @Accept json
+// @Produce json
+// @Param request body req.BindWechatRequest true "绑定微信请求"
+// @Success 200 {object} resp.Binding
+// @Failure 409 {object} map[string]interface{} "身份已绑定其他账户或已绑定其他微信"
+// @Router /authn/bindings/wechat [post]
+func (h *BindingHandler) BindWechat(c *gin.Context) {
+	accountID, err := currentAccountID(c)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	var reqBody req.BindWechatRequest
+	if err := h.BindJSON(c, &reqBody); err != nil {
+		h.Error(c, err)
+		return
+	}
+	if err := reqBody.Validate(); err != nil {
+		h.Error(c, err)
+		return
+	}
+	result, err := h.service.BindWechat(c.Request.Context(), bindingApp.BindWechatRequest{
+		AccountID: accountID,
+		AppID:     reqBody.AppID,
+		JsCode:    reqBody.Code,
+	})
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, toBindingResponse(result))
+}
+
+// BindWecom 绑定企业微信
+// @Summary 绑定企业微信
+// @Tags 账户绑定
+// @Accept json
+// @Produce json
+// @Param request body req.BindWecomRequest true "绑定企业微信请求"
+// @Success 200 {object} resp.Binding
+// @Failure 409 {object} map[string]interface{} "身份已绑定其他账户或已绑定其他企业微信"
+// @Router /authn/bindings/wecom [post]
+func (h *BindingHandler) BindWecom(c *gin.Context) {
+	accountID, err := currentAccountID(c)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	var reqBody req.BindWecomRequest
+	if err := h.BindJSON(c, &reqBody); err != nil {
+		h.Error(c, err)
+		return
+	}
+	if err := reqBody.Validate(); err != nil {
+		h.Error(c, err)
+		return
+	}
+	result, err := h.service.BindWecom(c.Request.Context(), bindingApp.BindWecomRequest{
+		AccountID: accountID,
+		CorpID:    reqBody.CorpID,
+		AgentID:   reqBody.AgentID,
+		Code:      reqBody.Code,
+	})
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, toBindingResponse(result))
+}
+
+// SendPhoneBindCode 发送绑定手机号验证码
+// @Summary 发送绑定手机号验证码
+// @Tags 账户绑定
+// @Accept json
+// @Produce json
+// @Param request body req.SendPhoneBindCodeRequest true "手机号"
+// @Success 200 {object} resp.MessageResponse
+// @Router /authn/bindings/phone/code [post]
+func (h *BindingHandler) SendPhoneBindCode(c *gin.Context) {
+	var reqBody req.SendPhoneBindCodeRequest
+	if err := h.BindJSON(c, &reqBody); err != nil {
+		h.Error(c, err)
+		return
+	}
+	if err := reqBody.Validate(); err != nil {
+		h.Error(c, err)
+		return
+	}
+	if err := h.service.SendPhoneBindCode(c.Request.Context(), reqBody.Phone); err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, resp.MessageResponse{Message: "verification code sent"})
+}
+
+// BindPhone 绑定手机号
+// @Summary 绑定手机号
+// @Description 凭短信验证码追加手机号登录方式；该手机号已属于其他账户时返回冲突
+// @Tags 账户绑定
+// @Accept json
+// @Produce json
+// @Param request body req.BindPhoneRequest true "绑定手机号请求"
+// @Success 200 {object} resp.Binding
+// @Failure 401 {object} map[string]interface{} "验证码无效"
+// @Failure 409 {object} map[string]interface{} "手机号已绑定其他账户或已绑定其他手机号"
+// @Router /authn/bindings/phone [post]
+func (h *BindingHandler) BindPhone(c *gin.Context) {
+	accountID, err := currentAccountID(c)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	var reqBody req.BindPhoneRequest
+	if err := h.BindJSON(c, &reqBody); err != nil {
+		h.Error(c, err)
+		return
+	}
+	if err := reqBody.Validate(); err != nil {
+		h.Error(c, err)
+		return
+	}
+	result, err := h.service.BindPhone(c.Request.Context(), bindingApp.BindPhoneRequest{
+		AccountID: accountID,
+		Phone:     reqBody.Phone,
+		Code:      strings.TrimSpace(reqBody.OTPCode),
+	})
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, toBindingResponse(result))
+}
+
+// Unbind 解除绑定
+// @Summary 解除登录方式绑定
+// @Description 解除微信、企业微信或手机号绑定；账户至少保留一种启用的登录方式
+// @Tags 账户绑定
+// @Param credentialId path string true "凭据ID"
+// @Success 200 {object} resp.MessageResponse
+// @Failure 404 {object} map[string]interface{} "凭据不存在"
+// @Failure 409 {object} map[string]interface{} "最后一种登录方式不可解绑"
+// @Router /authn/bindings/{credentialId} [delete]
+func (h *BindingHandler) Unbind(c *gin.Context) {
+	accountID, err := currentAccountID(c)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	credentialID, err := meta.ParseID(strings.TrimSpace(c.Param("credentialId")))
+	if err != nil || credentialID.IsZero() {
+		h.Error(c, perrors.WithCode(code.ErrInvalidArgument, "invalid credentialId"))
+		return
+	}
+	if err := h.service.Unbind(c.Request.Context(), accountID, credentialID); err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, resp.MessageResponse{Message: "binding removed"})
+}
+
+func toBindingResponse(r *bindingApp.BindingResult) resp.Binding {
+	return resp.Binding{
+		CredentialID: r.CredentialID.String(),
+		Type:         string(r.Type),
+		IDP:          r.IDP,
+		Identifier:   r.Identifier,
+		AppID:        r.AppID,
+		Enabled:      r.Enabled,
+	}
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	passwordApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/password"
+	req "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/authn/restful/request"
+	resp "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/authn/restful/response"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// PasswordHandler 密码自助服务 HTTP 处理器
+type PasswordHandler struct {
+	*BaseHandler
+	service passwordApp.PasswordApplicationService
+}
+
+// NewPasswordHandler 创建密码处理器
+func NewPasswordHandler(service passwordApp.PasswordApplicationService) *PasswordHandler {
+	return &PasswordHandler{
+		BaseHandler: NewBaseHandler(),
+		service:     service,
+	}
+}
+
+// ChangePassword 修改密码
+// @Summary 修改密码
+// @Description 凭当前密码修改密码；成功后该账户除当前会话外的全部会话被撤销
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body req.ChangePasswordRequest true "修改密码请求"
+// @Success 200 {object} resp.MessageResponse
+// @Failure 401 {object} map[string]interface{} "未登录或当前密码错误"
+// @Router /authn/password/change [post]
+func (h *PasswordHandler) ChangePassword(c *gin.Context) {
+	accountID, err := currentAccountID(c)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	var reqBody req.ChangePasswordRequest
+	if err := h.BindJSON(c, &reqBody); err != nil {
+		h.Error(c, err)
+		return
+	}
+	if err := reqBody.Validate(); err != nil {
+		h.Error(c, err)
+		return
+	}
+	if err := h.service.ChangePassword(c.Request.Context(), passwordApp.ChangePasswordRequest{
+		AccountID:       accountID,
+		TenantID:        currentTenantID(c),
+		SessionID:       currentSessionID(c),
+		CurrentPassword: reqBody.CurrentPassword,
+		NewPassword:     reqBody.NewPassword,
+	}); err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, resp.MessageResponse{Message: "password changed, other sessions signed out"})
+}
+
+// SendResetCode 发送重置密码验证码
+// @Summary 发送重置密码验证码
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body req.SendPasswordResetCodeRequest true "手机号"
+// @Success 200 {object} resp.MessageResponse
+// @Router /authn/password/reset/code [post]
+func (h *PasswordHandler) SendResetCode(c *gin.Context) {
+	var reqBody req.SendPasswordResetCodeRequest
+	if err := h.BindJSON(c, &reqBody); err != nil {
+		h.Error(c, err)
+		return
+	}
+	if err := reqBody.Validate(); err != nil {
+		h.Error(c, err)
+		return
+	}
+	if err := h.service.SendResetCode(c.Request.Context(), reqBody.Phone); err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, resp.MessageResponse{Message: "verification code sent"})
+}
+
+// ResetPassword 凭验证码重置密码
+// @Summary 重置密码
+// @Description 凭短信验证码重置密码；成功后该账户全部会话被撤销
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body req.ResetPasswordRequest true "重置密码请求"
+// @Success 200 {object} resp.MessageResponse
+// @Failure 401 {object} map[string]interface{} "验证码无效"
+// @Router /authn/password/reset [post]
+func (h *PasswordHandler) ResetPassword(c *gin.Context) {
+	var reqBody req.ResetPasswordRequest
+	if err := h.BindJSON(c, &reqBody); err != nil {
+		h.Error(c, err)
+		return
+	}
+	if err := reqBody.Validate(); err != nil {
+		h.Error(c, err)
+		return
+	}
+	if err := h.service.ResetPassword(c.Request.Context(), passwordApp.ResetPasswordRequest{
+		Phone:       reqBody.Phone,
+		Code:        strings.TrimSpace(reqBody.OTPCode),
+		NewPassword: reqBody.NewPassword,
+	}); err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, resp.MessageResponse{Message: "password reset, please login again"})
+}
+
+// ChangeExpiredPassword 修改已过期的密码
+// @Summary 修改过期密码
+// @Description 密码超过租户策略的最长有效期后登录返回 password expired，凭用户名与当前密码在此修改；成功后需重新登录
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body req.ExpiredPasswordChangeRequest true "过期密码修改请求"
+// @Success 200 {object} resp.MessageResponse
+// @Failure 400 {object} map[string]interface{} "新密码不符合密码策略"
+// @Failure 401 {object} map[string]interface{} "用户名或密码错误（含锁定与失败次数过多）"
+// @Router /authn/password/expired [post]
+func (h *PasswordHandler) ChangeExpiredPassword(c *gin.Context) {
+	var reqBody req.ExpiredPasswordChangeRequest
+	if err := h.BindJSON(c, &reqBody); err != nil {
+		h.Error(c, err)
+		return
+	}
+	if err := reqBody.Validate(); err != nil {
+		h.Error(c, err)
+		return
+	}
+	if err := h.service.ChangeExpiredPassword(c.Request.Context(), passwordApp.ExpiredPasswordChangeRequest{
+		TenantID:        meta.FromUint64(reqBody.TenantID),
+		RemoteIP:        c.ClientIP(),
+		UserAgent:       c.Request.UserAgent(),
+		Username:        strings.TrimSpace(reqBody.Username),
+		CurrentPassword: reqBody.CurrentPassword,
+		NewPassword:     reqBody.NewPassword,
+	}); err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, resp.MessageResponse{Message: "password changed, please login again"})
+}
+
+// currentAccountID 从认证上下文解析当前账户 ID
+func currentAccountID(c *gin.Context) (meta.ID, error) {
+	raw, ok := c.Get("account_id")
+	if !ok {
+		return meta.FromUint64(0), perrors.WithCode(code.ErrUnauthenticated, "authentication required")
+	}
+	s, _ := raw.(string)
+	id, err := meta.ParseID(s)
+	if err != nil || id.IsZero() {
+		return meta.FromUint64(0), perrors.WithCode(code.ErrUnauthenticated, "invalid account in token")
+	}
+	return id, nil
+}
+
+// currentTenantID 从认证上下文解析当前租户 ID，缺失时为 0
+func currentTenantID(c *gin.Context) meta.ID {
+	s := c.GetString("tenant_id")
+	id, err := meta.ParseID(s)
+	if err != nil {
+		return meta.FromUint64(0)
+	}
+	return id
+}
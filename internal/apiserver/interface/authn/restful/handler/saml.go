@@ -0,0 +1,333 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/login"
+	samlapp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/saml"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/saml"
+	req "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/authn/restful/request"
+	resp "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/authn/restful/response"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// samlMetadataContentType SAML 元数据的标准媒体类型
+const samlMetadataContentType = "application/samlmetadata+xml"
+
+// SAMLHandler SAML 2.0 SP 协议端点与 IdP 登记管理处理器
+type SAMLHandler struct {
+	*BaseHandler
+	idpService   samlapp.SAMLIdPApplicationService
+	spService    samlapp.SAMLServiceProviderApplicationService
+	loginService login.LoginApplicationService
+}
+
+// NewSAMLHandler 创建 SAML 处理器
+func NewSAMLHandler(
+	idpService samlapp.SAMLIdPApplicationService,
+	spService samlapp.SAMLServiceProviderApplicationService,
+	loginService login.LoginApplicationService,
+) *SAMLHandler {
+	return &SAMLHandler{
+		BaseHandler:  NewBaseHandler(),
+		idpService:   idpService,
+		spService:    spService,
+		loginService: loginService,
+	}
+}
+
+// Metadata 返回供 IdP 导入的 SP 元数据
+// @Summary 获取 SAML SP 元数据
+// @Tags 认证-SAML
+// @Produce xml
+// @Param slug path string true "IdP 登记标识"
+// @Success 200 {string} string "SP EntityDescriptor"
+// @Failure 404 {object} map[string]interface{} "IdP 未登记"
+// @Router /authn/saml/{slug}/metadata [get]
+func (h *SAMLHandler) Metadata(c *gin.Context) {
+	var uri req.SAMLIdPURIRequest
+	if err := h.BindURI(c, &uri); err != nil {
+		return
+	}
+
+	metadata, err := h.spService.Metadata(c.Request.Context(), uri.Slug)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	c.Data(http.StatusOK, samlMetadataContentType, metadata)
+}
+
+// BeginLogin 生成签名 AuthnRequest 并重定向到 IdP
+// @Summary 发起 SAML 登录（SP-initiated）
+// @Tags 认证-SAML
+// @Param slug path string true "IdP 登记标识"
+// @Param relay_state query string false "原样回传的 RelayState（≤80 字节）"
+// @Success 302 "重定向到 IdP 单点登录地址"
+// @Failure 400 {object} map[string]interface{} "IdP 已禁用或参数错误"
+// @Failure 404 {object} map[string]interface{} "IdP 未登记"
+// @Router /authn/saml/{slug}/login [get]
+func (h *SAMLHandler) BeginLogin(c *gin.Context) {
+	var uri req.SAMLIdPURIRequest
+	if err := h.BindURI(c, &uri); err != nil {
+		return
+	}
+	var query req.SAMLLoginRequest
+	if err := h.BindQuery(c, &query); err != nil {
+		return
+	}
+
+	redirectURL, err := h.spService.BeginLogin(c.Request.Context(), uri.Slug, query.RelayState)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	c.Redirect(http.StatusFound, redirectURL)
+}
+
+// ACS 断言消费端点：校验 IdP 回传的 SAMLResponse 并签发令牌对
+// @Summary SAML 断言消费（ACS，HTTP-POST 绑定）
+// @Tags 认证-SAML
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param slug path string true "IdP 登记标识"
+// @Param SAMLResponse formData string true "Base64 编码的 SAML Response"
+// @Param RelayState formData string false "发起登录时的 RelayState"
+// @Success 200 {object} resp.TokenPair "登录成功"
+// @Failure 400 {object} map[string]interface{} "请求参数错误"
+// @Failure 401 {object} map[string]interface{} "断言校验失败或重放"
+// @Failure 404 {object} map[string]interface{} "IdP 未登记"
+// @Router /authn/saml/{slug}/acs [post]
+func (h *SAMLHandler) ACS(c *gin.Context) {
+	var uri req.SAMLIdPURIRequest
+	if err := h.BindURI(c, &uri); err != nil {
+		return
+	}
+	var form req.SAMLACSRequest
+	if err := c.ShouldBind(&form); err != nil {
+		h.Error(c, perrors.WithCode(code.ErrBind, "invalid SAML ACS form: %v", err))
+		return
+	}
+
+	result, err := h.loginService.Login(c.Request.Context(), login.LoginRequest{
+		AuthType:     login.AuthTypeSAML,
+		SAMLIdP:      &uri.Slug,
+		SAMLResponse: &form.SAMLResponse,
+		RemoteIP:     c.ClientIP(),
+		UserAgent:    c.Request.UserAgent(),
+	})
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, convertTokenPair(result.TokenPair))
+}
+
+// RegisterIdP 以元数据登记 SAML IdP
+// @Summary 登记 SAML IdP
+// @Tags 认证-SAML管理
+// @Accept json
+// @Produce json
+// @Param request body req.RegisterSAMLIdPRequest true "登记请求"
+// @Success 200 {object} resp.SAMLIdPResponse "登记成功"
+// @Failure 400 {object} map[string]interface{} "元数据无效或参数错误"
+// @Failure 409 {object} map[string]interface{} "IdP 已存在"
+// @Router /authn/admin/saml/idps [post]
+func (h *SAMLHandler) RegisterIdP(c *gin.Context) {
+	var body req.RegisterSAMLIdPRequest
+	if err := h.BindJSON(c, &body); err != nil {
+		return
+	}
+	tenantID, err := meta.ParseID(body.TenantID)
+	if err != nil {
+		h.Error(c, perrors.WithCode(code.ErrInvalidArgument, "invalid tenant_id: %s", body.TenantID))
+		return
+	}
+
+	result, err := h.idpService.RegisterIdP(c.Request.Context(), samlapp.RegisterIdPDTO{
+		TenantID:         tenantID,
+		Slug:             body.Slug,
+		Name:             body.Name,
+		MetadataXML:      body.MetadataXML,
+		AttributeMapping: body.AttributeMapping,
+		AutoProvision:    body.AutoProvision,
+	})
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, toSAMLIdPResponse(result))
+}
+
+// ListIdPs 列出 SAML IdP 登记
+// @Summary 查询 SAML IdP 登记列表
+// @Tags 认证-SAML管理
+// @Produce json
+// @Param tenant_id query string false "租户 ID"
+// @Param status query string false "状态 (Enabled/Disabled)"
+// @Success 200 {object} resp.SAMLIdPListResponse "查询成功"
+// @Failure 400 {object} map[string]interface{} "请求参数错误"
+// @Router /authn/admin/saml/idps [get]
+func (h *SAMLHandler) ListIdPs(c *gin.Context) {
+	var query req.ListSAMLIdPsRequest
+	if err := h.BindQuery(c, &query); err != nil {
+		return
+	}
+
+	filter := samlapp.ListIdPsFilter{}
+	if query.TenantID != "" {
+		tenantID, err := meta.ParseID(query.TenantID)
+		if err != nil {
+			h.Error(c, perrors.WithCode(code.ErrInvalidArgument, "invalid tenant_id: %s", query.TenantID))
+			return
+		}
+		filter.TenantID = &tenantID
+	}
+	if query.Status != "" {
+		status := domain.Status(query.Status)
+		if status != domain.StatusEnabled && status != domain.StatusDisabled {
+			h.Error(c, perrors.WithCode(code.ErrInvalidArgument, "invalid saml idp status: %s", query.Status))
+			return
+		}
+		filter.Status = &status
+	}
+
+	results, err := h.idpService.ListIdPs(c.Request.Context(), filter)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	items := make([]*resp.SAMLIdPResponse, 0, len(results))
+	for _, result := range results {
+		items = append(items, toSAMLIdPResponse(result))
+	}
+	h.Success(c, &resp.SAMLIdPListResponse{Total: len(items), Items: items})
+}
+
+// GetIdP 查询 SAML IdP 登记
+// @Summary 查询 SAML IdP 登记
+// @Tags 认证-SAML管理
+// @Produce json
+// @Param slug path string true "IdP 登记标识"
+// @Success 200 {object} resp.SAMLIdPResponse "查询成功"
+// @Failure 404 {object} map[string]interface{} "IdP 未登记"
+// @Router /authn/admin/saml/idps/{slug} [get]
+func (h *SAMLHandler) GetIdP(c *gin.Context) {
+	var uri req.SAMLIdPURIRequest
+	if err := h.BindURI(c, &uri); err != nil {
+		return
+	}
+
+	result, err := h.idpService.GetIdP(c.Request.Context(), uri.Slug)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, toSAMLIdPResponse(result))
+}
+
+// UpdateIdP 更新 SAML IdP 登记，提交新元数据即完成证书轮换
+// @Summary 更新 SAML IdP 登记
+// @Tags 认证-SAML管理
+// @Accept json
+// @Produce json
+// @Param slug path string true "IdP 登记标识"
+// @Param request body req.UpdateSAMLIdPRequest true "更新请求"
+// @Success 200 {object} resp.SAMLIdPResponse "更新成功"
+// @Failure 400 {object} map[string]interface{} "元数据无效或参数错误"
+// @Failure 404 {object} map[string]interface{} "IdP 未登记"
+// @Router /authn/admin/saml/idps/{slug} [put]
+func (h *SAMLHandler) UpdateIdP(c *gin.Context) {
+	var uri req.SAMLIdPURIRequest
+	if err := h.BindURI(c, &uri); err != nil {
+		return
+	}
+	var body req.UpdateSAMLIdPRequest
+	if err := h.BindJSON(c, &body); err != nil {
+		return
+	}
+
+	dto := samlapp.UpdateIdPDTO{
+		Name:             body.Name,
+		MetadataXML:      body.MetadataXML,
+		AttributeMapping: body.AttributeMapping,
+		AutoProvision:    body.AutoProvision,
+	}
+	if dto == (samlapp.UpdateIdPDTO{}) {
+		h.Error(c, perrors.WithCode(code.ErrInvalidArgument, "at least one field must be updated"))
+		return
+	}
+
+	result, err := h.idpService.UpdateIdP(c.Request.Context(), uri.Slug, dto)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, toSAMLIdPResponse(result))
+}
+
+// EnableIdP 启用 SAML IdP 登记
+// @Summary 启用 SAML IdP
+// @Tags 认证-SAML管理
+// @Produce json
+// @Param slug path string true "IdP 登记标识"
+// @Success 200 {object} resp.SAMLIdPResponse "启用成功"
+// @Failure 404 {object} map[string]interface{} "IdP 未登记"
+// @Router /authn/admin/saml/idps/{slug}/enable [post]
+func (h *SAMLHandler) EnableIdP(c *gin.Context) {
+	var uri req.SAMLIdPURIRequest
+	if err := h.BindURI(c, &uri); err != nil {
+		return
+	}
+
+	result, err := h.idpService.EnableIdP(c.Request.Context(), uri.Slug)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, toSAMLIdPResponse(result))
+}
+
+// DisableIdP 禁用 SAML IdP 登记，禁用后拒绝新的登录与断言
+// @Summary 禁用 SAML IdP
+// @Tags 认证-SAML管理
+// @Produce json
+// @Param slug path string true "IdP 登记标识"
+// @Success 200 {object} resp.SAMLIdPResponse "禁用成功"
+// @Failure 404 {object} map[string]interface{} "IdP 未登记"
+// @Router /authn/admin/saml/idps/{slug}/disable [post]
+func (h *SAMLHandler) DisableIdP(c *gin.Context) {
+	var uri req.SAMLIdPURIRequest
+	if err := h.BindURI(c, &uri); err != nil {
+		return
+	}
+
+	result, err := h.idpService.DisableIdP(c.Request.Context(), uri.Slug)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, toSAMLIdPResponse(result))
+}
+
+func toSAMLIdPResponse(result *samlapp.IdPResult) *resp.SAMLIdPResponse {
+	if result == nil {
+		return nil
+	}
+	return &resp.SAMLIdPResponse{
+		ID:               result.ID,
+		TenantID:         result.TenantID,
+		Slug:             result.Slug,
+		Name:             result.Name,
+		EntityID:         result.EntityID,
+		SSOURL:           result.SSOURL,
+		CertificateCount: result.CertificateCount,
+		AttributeMapping: result.AttributeMapping,
+		AutoProvision:    result.AutoProvision,
+		Status:           string(result.Status),
+	}
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	sessionapp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/session"
+	tokenDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authn/token"
+	resp "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/authn/restful/response"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+// SessionHandler 我的会话（设备）管理处理器
+type SessionHandler struct {
+	*BaseHandler
+	service sessionapp.SelfSessionApplicationService
+}
+
+// NewSessionHandler 创建会话自助处理器
+func NewSessionHandler(service sessionapp.SelfSessionApplicationService) *SessionHandler {
+	return &SessionHandler{
+		BaseHandler: NewBaseHandler(),
+		service:     service,
+	}
+}
+
+// ListMySessions 列出当前用户的登录设备
+// @Summary 我的登录设备
+// @Tags 会话管理
+// @Produce json
+// @Success 200 {object} resp.SessionList
+// @Failure 401 {object} map[string]interface{} "未登录"
+// @Router /authn/me/sessions [get]
+func (h *SessionHandler) ListMySessions(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	results, err := h.service.ListMySessions(c.Request.Context(), userID, currentSessionID(c))
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, toSessionList(results))
+}
+
+// RevokeMySession 注销单个登录设备
+// @Summary 注销登录设备
+// @Description 仅能注销本人名下的会话；注销当前会话等同于登出
+// @Tags 会话管理
+// @Produce json
+// @Param sessionId path string true "会话 ID"
+// @Success 200 {object} resp.MessageResponse
+// @Failure 401 {object} map[string]interface{} "未登录"
+// @Failure 404 {object} map[string]interface{} "会话不存在"
+// @Router /authn/me/sessions/{sessionId} [delete]
+func (h *SessionHandler) RevokeMySession(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	if err := h.service.RevokeMySession(c.Request.Context(), userID, c.Param("sessionId")); err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, resp.MessageResponse{Message: "session revoked"})
+}
+
+// currentUserID 从认证上下文读取当前用户 ID
+func currentUserID(c *gin.Context) (string, error) {
+	raw, ok := c.Get("user_id")
+	if !ok {
+		return "", perrors.WithCode(code.ErrUnauthenticated, "authentication required")
+	}
+	userID, _ := raw.(string)
+	if userID == "" {
+		return "", perrors.WithCode(code.ErrUnauthenticated, "invalid user in token")
+	}
+	return userID, nil
+}
+
+// currentSessionID 从认证上下文读取当前令牌所属会话
+func currentSessionID(c *gin.Context) string {
+	raw, ok := c.Get("claims")
+	if !ok {
+		return ""
+	}
+	if claims, ok := raw.(*tokenDomain.TokenClaims); ok && claims != nil {
+		return claims.SessionID
+	}
+	return ""
+}
+
+func toSessionList(results []*sessionapp.SessionResult) resp.SessionList {
+	list := resp.SessionList{Items: make([]resp.Session, 0, len(results))}
+	for _, r := range results {
+		list.Items = append(list.Items, resp.Session{
+			SessionID:  r.SessionID,
+			UserID:     r.UserID,
+			AccountID:  r.AccountID,
+			AMR:        r.AMR,
+			ClientIP:   r.ClientIP,
+			UserAgent:  r.UserAgent,
+			DeviceName: r.DeviceName,
+			CreatedAt:  r.CreatedAt,
+			ExpiresAt:  r.ExpiresAt,
+			Current:    r.Current,
+		})
+	}
+	return list
+}
@@ -65,6 +65,34 @@ func (h *SessionAdminHandler) RevokeUserSessions(c *gin.Context) {
 	h.Success(c, resp.MessageResponse{Message: "user sessions revoked"})
 }
 
+// ListUserSessions 列出某用户的活跃会话。
+func (h *SessionAdminHandler) ListUserSessions(c *gin.Context) {
+	if h == nil || h.service == nil {
+		h.Error(c, perrors.WithCode(code.ErrInternalServerError, "session service not initialized"))
+		return
+	}
+	results, err := h.service.ListSessionsByUser(c.Request.Context(), c.Param("userId"))
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, toSessionList(results))
+}
+
+// ListAccountSessions 列出某账号的活跃会话。
+func (h *SessionAdminHandler) ListAccountSessions(c *gin.Context) {
+	if h == nil || h.service == nil {
+		h.Error(c, perrors.WithCode(code.ErrInternalServerError, "session service not initialized"))
+		return
+	}
+	results, err := h.service.ListSessionsByAccount(c.Request.Context(), c.Param("accountId"))
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, toSessionList(results))
+}
+
 func currentActor(c *gin.Context) string {
 	if c == nil {
 		return ""
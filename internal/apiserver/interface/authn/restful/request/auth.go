@@ -10,9 +10,10 @@ import (
 
 // LoginRequest 统一登录请求
 type LoginRequest struct {
-	Method      string          `json:"method" binding:"required"`      // 认证方式：password | phone_otp | wechat | wecom
-	DeviceID    string          `json:"device_id,omitempty"`            // 设备 ID
-	Credentials json.RawMessage `json:"credentials" binding:"required"` // 凭证（根据 method 不同而不同）
+	Method      string          `json:"method" binding:"required"`               // 认证方式：password | phone_otp | wechat | wechat_mp | wecom | oidc
+	DeviceID    string          `json:"device_id,omitempty"`                     // 设备 ID
+	DeviceName  string          `json:"device_name,omitempty" binding:"max=128"` // 设备名（展示于"我的设备"，最长 128 字符）
+	Credentials json.RawMessage `json:"credentials" binding:"required"`          // 凭证（根据 method 不同而不同）
 }
 
 // Validate 验证登录请求
@@ -21,7 +22,9 @@ func (r *LoginRequest) Validate() error {
 		"password":  true,
 		"phone_otp": true,
 		"wechat":    true,
+		"wechat_mp": true,
 		"wecom":     true,
+		"oidc":      true,
 	}
 	if !validMethods[r.Method] {
 		return perrors.WithCode(code.ErrInvalidArgument, "invalid authentication method: %s", r.Method)
@@ -65,12 +68,28 @@ type WeChatCredentials struct {
 	Code  string `json:"code" binding:"required"`   // 微信 JS Code
 }
 
+// WeChatMPCredentials 微信公众号网页授权凭证（snsapi_base / snsapi_userinfo 回调的 code）
+type WeChatMPCredentials struct {
+	AppID string `json:"app_id" binding:"required"` // 公众号AppID
+	Code  string `json:"code" binding:"required"`   // 网页授权 code
+}
+
 // WeComCredentials 企业微信凭证
 type WeComCredentials struct {
 	CorpID   string `json:"corp_id" binding:"required"`   // 企业ID
+	AgentID  string `json:"agent_id"`                     // 应用AgentID（企业下登记了多个应用时必填）
 	AuthCode string `json:"auth_code" binding:"required"` // 授权码
 }
 
+// OIDCCredentials 上游 OIDC 联合登录凭证（授权回调的 code 与 state）
+type OIDCCredentials struct {
+	Provider     string `json:"provider" binding:"required"`     // 提供商 slug
+	Code         string `json:"code" binding:"required"`         // 授权码
+	RedirectURI  string `json:"redirect_uri" binding:"required"` // 发起授权时使用的回调地址
+	State        string `json:"state" binding:"required"`        // 授权回调携带的 state（由构造授权地址接口下发）
+	CodeVerifier string `json:"code_verifier,omitempty"`         // PKCE code_verifier
+}
+
 // RefreshTokenRequest 刷新令牌请求
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
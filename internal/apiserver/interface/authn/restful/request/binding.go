@@ -0,0 +1,64 @@
+package request
+
+import (
+	"strings"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+// BindWechatRequest 绑定微信小程序（需登录）
+type BindWechatRequest struct {
+	AppID string `json:"app_id" binding:"required"` // 微信应用ID
+	Code  string `json:"code" binding:"required"`   // wx.login 返回的 code
+}
+
+// Validate 校验绑定微信请求
+func (r *BindWechatRequest) Validate() error {
+	if strings.TrimSpace(r.AppID) == "" || strings.TrimSpace(r.Code) == "" {
+		return perrors.WithCode(code.ErrInvalidArgument, "app_id and code are required")
+	}
+	return nil
+}
+
+// BindWecomRequest 绑定企业微信（需登录）
+type BindWecomRequest struct {
+	CorpID  string `json:"corp_id" binding:"required"` // 企业ID
+	AgentID string `json:"agent_id"`                   // 应用AgentID（企业下登记了多个应用时必填）
+	Code    string `json:"code" binding:"required"`    // 企业微信授权 code
+}
+
+// Validate 校验绑定企业微信请求
+func (r *BindWecomRequest) Validate() error {
+	if strings.TrimSpace(r.CorpID) == "" || strings.TrimSpace(r.Code) == "" {
+		return perrors.WithCode(code.ErrInvalidArgument, "corp_id and code are required")
+	}
+	return nil
+}
+
+// SendPhoneBindCodeRequest 发送绑定手机号验证码
+type SendPhoneBindCodeRequest struct {
+	Phone string `json:"phone" binding:"required"` // 支持 E.164 或国内手机号
+}
+
+// Validate 校验发送绑定验证码请求
+func (r *SendPhoneBindCodeRequest) Validate() error {
+	if strings.TrimSpace(r.Phone) == "" {
+		return perrors.WithCode(code.ErrInvalidArgument, "phone is required")
+	}
+	return nil
+}
+
+// BindPhoneRequest 凭短信验证码绑定手机号（需登录）
+type BindPhoneRequest struct {
+	Phone   string `json:"phone" binding:"required"`
+	OTPCode string `json:"otp_code" binding:"required"`
+}
+
+// Validate 校验绑定手机号请求
+func (r *BindPhoneRequest) Validate() error {
+	if strings.TrimSpace(r.Phone) == "" || strings.TrimSpace(r.OTPCode) == "" {
+		return perrors.WithCode(code.ErrInvalidArgument, "phone and otp_code are required")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package request
+
+import (
+	"strings"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+// ChangePasswordRequest 修改密码请求（需登录）
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// Validate 校验修改密码请求
+func (r *ChangePasswordRequest) Validate() error {
+	if r.CurrentPassword == "" || r.NewPassword == "" {
+		return perrors.WithCode(code.ErrInvalidArgument, "current_password and new_password are required")
+	}
+	return nil
+}
+
+// SendPasswordResetCodeRequest 请求发送重置密码短信验证码
+type SendPasswordResetCodeRequest struct {
+	Phone string `json:"phone" binding:"required"` // 支持 E.164 或国内手机号
+}
+
+// Validate 校验发送重置验证码请求
+func (r *SendPasswordResetCodeRequest) Validate() error {
+	if strings.TrimSpace(r.Phone) == "" {
+		return perrors.WithCode(code.ErrInvalidArgument, "phone is required")
+	}
+	return nil
+}
+
+// ResetPasswordRequest 凭短信验证码重置密码
+type ResetPasswordRequest struct {
+	Phone       string `json:"phone" binding:"required"`
+	OTPCode     string `json:"otp_code" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// Validate 校验重置密码请求
+func (r *ResetPasswordRequest) Validate() error {
+	if strings.TrimSpace(r.Phone) == "" || strings.TrimSpace(r.OTPCode) == "" || r.NewPassword == "" {
+		return perrors.WithCode(code.ErrInvalidArgument, "phone, otp_code and new_password are required")
+	}
+	return nil
+}
+
+// ExpiredPasswordChangeRequest 密码过期时修改密码（无需登录，凭用户名与当前密码）
+type ExpiredPasswordChangeRequest struct {
+	TenantID        uint64 `json:"tenant_id,omitempty"`
+	Username        string `json:"username" binding:"required"`
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// Validate 校验过期密码修改请求
+func (r *ExpiredPasswordChangeRequest) Validate() error {
+	if strings.TrimSpace(r.Username) == "" || r.CurrentPassword == "" || r.NewPassword == "" {
+		return perrors.WithCode(code.ErrInvalidArgument, "username, current_password and new_password are required")
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package request
+
+// SAMLIdPURIRequest SAML IdP 路径参数
+type SAMLIdPURIRequest struct {
+	Slug string `uri:"slug" binding:"required"` // IdP 登记标识
+}
+
+// SAMLLoginRequest 发起 SAML 登录请求（Query 参数）
+type SAMLLoginRequest struct {
+	RelayState string `form:"relay_state"` // 原样回传的 RelayState（≤80 字节）
+}
+
+// SAMLACSRequest ACS 回调表单（HTTP-POST 绑定）
+type SAMLACSRequest struct {
+	SAMLResponse string `form:"SAMLResponse" binding:"required"` // Base64 编码的 SAML Response
+	RelayState   string `form:"RelayState"`                      // 发起登录时的 RelayState
+}
+
+// ListSAMLIdPsRequest SAML IdP 登记列表请求（Query 参数）
+type ListSAMLIdPsRequest struct {
+	TenantID string `form:"tenant_id"` // 按租户过滤
+	Status   string `form:"status"`    // 按状态过滤（Enabled/Disabled）
+}
+
+// RegisterSAMLIdPRequest 登记 SAML IdP 请求
+type RegisterSAMLIdPRequest struct {
+	TenantID         string            `json:"tenant_id" binding:"required"`    // 所属租户（必填）
+	Slug             string            `json:"slug" binding:"required"`         // 唯一标识（必填）
+	Name             string            `json:"name" binding:"required"`         // 显示名称（必填）
+	MetadataXML      string            `json:"metadata_xml" binding:"required"` // IdP 元数据 XML（必填）
+	AttributeMapping map[string]string `json:"attribute_mapping"`               // SAML 属性名 -> Claims 键
+	AutoProvision    bool              `json:"auto_provision"`                  // 是否即时开通账户
+}
+
+// UpdateSAMLIdPRequest 更新 SAML IdP 请求，未提供的字段保持不变
+type UpdateSAMLIdPRequest struct {
+	Name             *string            `json:"name"`
+	MetadataXML      *string            `json:"metadata_xml"` // 提交新元数据即完成证书轮换
+	AttributeMapping *map[string]string `json:"attribute_mapping"`
+	AutoProvision    *bool              `json:"auto_provision"`
+}
@@ -14,6 +14,7 @@ type TokenPair struct {
 type TokenVerifyResponse struct {
 	Valid  bool         `json:"valid"`            // 令牌是否有效
 	Claims *TokenClaims `json:"claims,omitempty"` // 令牌声明（如果有效）
+	Reason string       `json:"reason,omitempty"` // 无效原因：invalid | expired | session_idle_timeout | session_max_lifetime
 }
 
 // TokenClaims JWT 声明
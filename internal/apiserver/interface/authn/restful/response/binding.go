@@ -0,0 +1,16 @@
+package response
+
+// Binding 登录方式
+type Binding struct {
+	CredentialID string `json:"credential_id"`
+	Type         string `json:"type"`                 // password | phone_otp | oauth_wx_minip | oauth_wecom
+	IDP          string `json:"idp,omitempty"`        // wechat | wecom | phone
+	Identifier   string `json:"identifier,omitempty"` // UnionID/OpenID/UserID/手机号
+	AppID        string `json:"app_id,omitempty"`     // AppID/CorpID
+	Enabled      bool   `json:"enabled"`
+}
+
+// BindingList 登录方式列表
+type BindingList struct {
+	Items []Binding `json:"items"`
+}
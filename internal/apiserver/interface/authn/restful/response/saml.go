@@ -0,0 +1,21 @@
+package response
+
+// SAMLIdPResponse SAML IdP 登记响应（不回显元数据与证书原文）
+type SAMLIdPResponse struct {
+	ID               string            `json:"id"`                          // 内部 ID
+	TenantID         string            `json:"tenant_id"`                   // 所属租户
+	Slug             string            `json:"slug"`                        // 唯一标识
+	Name             string            `json:"name"`                        // 显示名称
+	EntityID         string            `json:"entity_id"`                   // IdP EntityID
+	SSOURL           string            `json:"sso_url"`                     // IdP 单点登录地址（HTTP-Redirect）
+	CertificateCount int               `json:"certificate_count"`           // 受信签名证书数量
+	AttributeMapping map[string]string `json:"attribute_mapping,omitempty"` // SAML 属性名 -> Claims 键
+	AutoProvision    bool              `json:"auto_provision"`              // 是否即时开通账户
+	Status           string            `json:"status"`                      // 状态（Enabled/Disabled）
+}
+
+// SAMLIdPListResponse SAML IdP 登记列表响应
+type SAMLIdPListResponse struct {
+	Total int                `json:"total"`
+	Items []*SAMLIdPResponse `json:"items"`
+}
@@ -0,0 +1,22 @@
+package response
+
+import "time"
+
+// Session 登录会话（设备）
+type Session struct {
+	SessionID  string    `json:"session_id"`
+	UserID     string    `json:"user_id"`
+	AccountID  string    `json:"account_id"`
+	AMR        []string  `json:"amr,omitempty"`
+	ClientIP   string    `json:"client_ip,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	DeviceName string    `json:"device_name,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // 是否为发起本次请求的会话
+}
+
+// SessionList 会话列表
+type SessionList struct {
+	Items []Session `json:"items"`
+}
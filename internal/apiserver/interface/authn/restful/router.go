@@ -11,10 +11,15 @@ import (
 
 // Dependencies describes the external collaborators needed to expose authn endpoints.
 type Dependencies struct {
-	AuthHandler      *authhandler.AuthHandler    // 新的认证处理器
-	AccountHandler   *authhandler.AccountHandler // 账户管理处理器
-	JWKSHandler      *authhandler.JWKSHandler    // JWKS 处理器
-	AdminMiddlewares []gin.HandlerFunc           // 管理接口中间件
+	AuthHandler      *authhandler.AuthHandler     // 新的认证处理器
+	AccountHandler   *authhandler.AccountHandler  // 账户管理处理器
+	JWKSHandler      *authhandler.JWKSHandler     // JWKS 处理器
+	PasswordHandler  *authhandler.PasswordHandler // 密码自助服务处理器
+	BindingHandler   *authhandler.BindingHandler  // 账户身份绑定处理器
+	SessionHandler   *authhandler.SessionHandler  // 我的会话（设备）处理器
+	SAMLHandler      *authhandler.SAMLHandler     // SAML 2.0 SP 处理器
+	AuthMiddleware   gin.HandlerFunc              // 登录态校验（修改密码等自助接口）
+	AdminMiddlewares []gin.HandlerFunc            // 管理接口中间件
 }
 
 var deps Dependencies
@@ -36,7 +41,22 @@ func Register(engine *gin.Engine) {
 	registerAuthEndpointsV2(api.Group(""), deps.AuthHandler)
 
 	// 注册账户管理端点
-	registerAccountEndpoints(api.Group(""), deps.AccountHandler)
+	registerAccountEndpoints(api.Group(""), deps.AccountHandler, deps.AdminMiddlewares...)
+
+	// 注册密码自助端点
+	registerPasswordEndpoints(api.Group("/password"), deps.PasswordHandler, deps.AuthMiddleware)
+
+	// 注册账户身份绑定端点
+	registerBindingEndpoints(api.Group("/bindings"), deps.BindingHandler, deps.AuthMiddleware)
+
+	// 注册我的会话（设备）端点
+	registerSessionEndpoints(api.Group("/me/sessions"), deps.SessionHandler, deps.AuthMiddleware)
+
+	// 注册 SAML SP 协议端点（公开端点）
+	registerSAMLEndpoints(api.Group("/saml"), deps.SAMLHandler)
+
+	// 注册 SAML IdP 登记管理端点（管理员接口）
+	registerSAMLAdminEndpoints(api.Group("/admin/saml"), deps.SAMLHandler, deps.AdminMiddlewares...)
 
 	// 注册 JWKS 端点（公开端点）
 	registerJWKSPublicEndpoints(engine, deps.JWKSHandler)
@@ -75,6 +95,79 @@ func registerAuthEndpointsV2(group *gin.RouterGroup, handler *authhandler.AuthHa
 	group.POST("/verify", handler.VerifyToken)         // POST /v1/auth/verify - 验证令牌
 }
 
+// registerPasswordEndpoints 注册修改/重置密码端点
+// 修改密码需要登录态；未提供认证中间件时不注册，避免绕过
+func registerPasswordEndpoints(group *gin.RouterGroup, handler *authhandler.PasswordHandler, authMiddleware gin.HandlerFunc) {
+	if group == nil || handler == nil {
+		return
+	}
+
+	group.POST("/reset/code", handler.SendResetCode)      // POST /v1/authn/password/reset/code - 发送重置验证码
+	group.POST("/reset", handler.ResetPassword)           // POST /v1/authn/password/reset - 凭验证码重置
+	group.POST("/expired", handler.ChangeExpiredPassword) // POST /v1/authn/password/expired - 过期密码修改
+	if authMiddleware != nil {
+		group.POST("/change", authMiddleware, handler.ChangePassword) // POST /v1/authn/password/change - 修改密码
+	}
+}
+
+// registerBindingEndpoints 注册登录方式绑定/解绑端点
+// 均作用于当前登录账户；未提供认证中间件时不注册
+func registerBindingEndpoints(group *gin.RouterGroup, handler *authhandler.BindingHandler, authMiddleware gin.HandlerFunc) {
+	if group == nil || handler == nil || authMiddleware == nil {
+		return
+	}
+	group.Use(authMiddleware)
+
+	group.GET("", handler.ListBindings)                  // GET /v1/authn/bindings - 登录方式列表
+	group.POST("/wechat", handler.BindWechat)            // POST /v1/authn/bindings/wechat - 绑定微信小程序
+	group.POST("/wecom", handler.BindWecom)              // POST /v1/authn/bindings/wecom - 绑定企业微信
+	group.POST("/phone/code", handler.SendPhoneBindCode) // POST /v1/authn/bindings/phone/code - 发送绑定验证码
+	group.POST("/phone", handler.BindPhone)              // POST /v1/authn/bindings/phone - 绑定手机号
+	group.DELETE("/:credentialId", handler.Unbind)       // DELETE /v1/authn/bindings/:credentialId - 解绑
+}
+
+// registerSessionEndpoints 注册当前用户的会话（设备）端点
+// 未提供认证中间件时不注册
+func registerSessionEndpoints(group *gin.RouterGroup, handler *authhandler.SessionHandler, authMiddleware gin.HandlerFunc) {
+	if group == nil || handler == nil || authMiddleware == nil {
+		return
+	}
+	group.Use(authMiddleware)
+
+	group.GET("", handler.ListMySessions)                // GET /v1/authn/me/sessions - 我的登录设备
+	group.DELETE("/:sessionId", handler.RevokeMySession) // DELETE /v1/authn/me/sessions/:sessionId - 注销单个设备
+}
+
+// registerSAMLEndpoints 注册 SAML SP 协议端点
+func registerSAMLEndpoints(group *gin.RouterGroup, handler *authhandler.SAMLHandler) {
+	if group == nil || handler == nil {
+		return
+	}
+
+	group.GET("/:slug/metadata", handler.Metadata) // GET /v1/authn/saml/:slug/metadata - SP 元数据
+	group.GET("/:slug/login", handler.BeginLogin)  // GET /v1/authn/saml/:slug/login - 跳转 IdP
+	group.POST("/:slug/acs", handler.ACS)          // POST /v1/authn/saml/:slug/acs - 断言消费
+}
+
+// registerSAMLAdminEndpoints 注册 SAML IdP 登记管理端点
+// 未提供管理中间件时不注册
+func registerSAMLAdminEndpoints(group *gin.RouterGroup, handler *authhandler.SAMLHandler, middlewares ...gin.HandlerFunc) {
+	if group == nil || handler == nil || len(middlewares) == 0 {
+		return
+	}
+	group.Use(middlewares...)
+
+	idps := group.Group("/idps")
+	{
+		idps.POST("", handler.RegisterIdP)              // 登记 IdP
+		idps.GET("", handler.ListIdPs)                  // 列出 IdP
+		idps.GET("/:slug", handler.GetIdP)              // 查询 IdP
+		idps.PUT("/:slug", handler.UpdateIdP)           // 更新 IdP（含证书轮换）
+		idps.POST("/:slug/enable", handler.EnableIdP)   // 启用 IdP
+		idps.POST("/:slug/disable", handler.DisableIdP) // 禁用 IdP
+	}
+}
+
 // registerJWKSPublicEndpoints 注册 JWKS 公开端点
 func registerJWKSPublicEndpoints(engine *gin.Engine, handler *authhandler.JWKSHandler) {
 	if engine == nil || handler == nil {
@@ -108,7 +201,9 @@ func registerJWKSAdminEndpoints(admin *gin.RouterGroup, handler *authhandler.JWK
 	}
 }
 
-func registerAccountEndpoints(v1 *gin.RouterGroup, h *authhandler.AccountHandler) {
+// registerAccountEndpoints 注册账户端点
+// 解锁为管理员操作，未提供管理中间件时不注册
+func registerAccountEndpoints(v1 *gin.RouterGroup, h *authhandler.AccountHandler, adminMiddlewares ...gin.HandlerFunc) {
 	if v1 == nil || h == nil {
 		return
 	}
@@ -124,13 +219,17 @@ func registerAccountEndpoints(v1 *gin.RouterGroup, h *authhandler.AccountHandler
 	accounts.PUT("/:accountId/unionid", h.SetUnionID)
 	accounts.POST("/:accountId/enable", h.EnableAccount)
 	accounts.POST("/:accountId/disable", h.DisableAccount)
+	if len(adminMiddlewares) > 0 {
+		unlock := append(append([]gin.HandlerFunc{}, adminMiddlewares...), h.UnlockAccount)
+		accounts.POST("/:accountId/unlock", unlock...)
+	}
 
 	// TODO: 以下端点待实现
 	// accounts.GET("/:accountId/credentials", h.GetCredentials) // 待实现凭据查询服务
 	// accounts.POST("/operation", h.CreateOperationAccount)
-	// accounts.PATCH("/operation/:username", h.UpdateOperationCredential)
+	// 运营账号改密请使用 POST /authn/password/change
 	// accounts.POST("/operation/:username/change", h.ChangeOperationUsername)
-	// accounts.POST("/wechat/bind", h.BindWeChatAccount)
+	// 微信/企业微信/手机号绑定见 /bindings
 	// accounts.GET("/operation/:username", h.GetOperationAccountByUsername)
 	// v1.GET("/accounts/by-ref", h.FindAccountByRef)
 	// users := v1.Group("/users")
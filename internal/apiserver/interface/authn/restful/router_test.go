@@ -0,0 +1,82 @@
+package restful
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	appAccount "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authn/account"
+	authhandler "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/authn/restful/handler"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+type fakeAccountService struct {
+	appAccount.AccountApplicationService
+	unlocked []meta.ID
+}
+
+func (f *fakeAccountService) UnlockAccount(_ context.Context, accountID meta.ID) error {
+	f.unlocked = append(f.unlocked, accountID)
+	return nil
+}
+
+func TestRegister_UnlockAccountRequiresAdminMiddleware(t *testing.T) {
+	service := &fakeAccountService{}
+	engine := newAuthnRouter(t, []gin.HandlerFunc{requireAdminHeader()}, service)
+
+	unauthorized := httptest.NewRecorder()
+	engine.ServeHTTP(unauthorized, httptest.NewRequest(http.MethodPost, "/api/v1/authn/accounts/100/unlock", nil))
+	require.Equal(t, http.StatusUnauthorized, unauthorized.Code)
+	require.Empty(t, service.unlocked)
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/authn/accounts/100/unlock", nil)
+	req.Header.Set("X-Admin", "1")
+	engine.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, []meta.ID{meta.FromUint64(100)}, service.unlocked)
+}
+
+func TestRegister_UnlockAccountNotRegisteredWithoutAdminMiddlewares(t *testing.T) {
+	service := &fakeAccountService{}
+	engine := newAuthnRouter(t, nil, service)
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/authn/accounts/100/unlock", nil))
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+	require.Empty(t, service.unlocked)
+}
+
+func newAuthnRouter(t *testing.T, middlewares []gin.HandlerFunc, accountService appAccount.AccountApplicationService) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	Provide(Dependencies{
+		AccountHandler:   authhandler.NewAccountHandler(accountService, nil),
+		AdminMiddlewares: middlewares,
+	})
+	t.Cleanup(func() {
+		Provide(Dependencies{})
+	})
+
+	engine := gin.New()
+	Register(engine)
+	return engine
+}
+
+func requireAdminHeader() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("X-Admin") != "1" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"code":    http.StatusUnauthorized,
+				"message": "unauthorized",
+			})
+			return
+		}
+		c.Next()
+	}
+}
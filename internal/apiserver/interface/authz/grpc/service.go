@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"strings"
+	"time"
 
 	authzv1 "github.com/FangcunMount/iam-contracts/api/grpc/iam/authz/v1"
 	assignmentDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/assignment"
@@ -12,6 +13,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Service 聚合 authz gRPC（PDP + snapshot/assignment facade）。
@@ -126,6 +128,8 @@ func (s *authorizationServer) GrantAssignment(ctx context.Context, req *authzv1.
 		RoleID:      role.ID.Uint64(),
 		TenantID:    req.Domain,
 		GrantedBy:   req.GrantedBy,
+		NotBefore:   optionalTime(req.GetNotBefore()),
+		ExpiresAt:   optionalTime(req.GetExpiresAt()),
 	})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "grant assignment: %v", err)
@@ -164,6 +168,14 @@ func (s *authorizationServer) RevokeAssignment(ctx context.Context, req *authzv1
 	return &authzv1.RevokeAssignmentResponse{}, nil
 }
 
+func optionalTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
 func parseSubject(subject string) (assignmentDomain.SubjectType, string, error) {
 	parts := strings.SplitN(subject, ":", 2)
 	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
@@ -1,14 +1,20 @@
 // Package dto 赋权相关的 DTO 定义
 package dto
 
-import "github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+import (
+	"time"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
 
 // GrantRequest 授权请求
 type GrantRequest struct {
-	SubjectType string  `json:"subject_type" binding:"required,oneof=user"`
-	SubjectID   string  `json:"subject_id" binding:"required"`
-	RoleID      meta.ID `json:"role_id" binding:"required" swaggertype:"string"`
-	GrantedBy   string  `json:"granted_by,omitempty"`
+	SubjectType string     `json:"subject_type" binding:"required,oneof=user"`
+	SubjectID   string     `json:"subject_id" binding:"required"`
+	RoleID      meta.ID    `json:"role_id" binding:"required" swaggertype:"string"`
+	GrantedBy   string     `json:"granted_by,omitempty"`
+	NotBefore   *time.Time `json:"not_before,omitempty"` // 生效时间，缺省立即生效
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // 失效时间，缺省永久有效
 }
 
 // RevokeRequest 撤销授权请求
@@ -20,12 +26,15 @@ type RevokeRequest struct {
 
 // AssignmentResponse 赋权响应
 type AssignmentResponse struct {
-	ID          meta.ID `json:"id" swaggertype:"string"`
-	SubjectType string  `json:"subject_type"`
-	SubjectID   string  `json:"subject_id"`
-	RoleID      meta.ID `json:"role_id" swaggertype:"string"`
-	TenantID    string  `json:"tenant_id"`
-	GrantedBy   string  `json:"granted_by"`
+	ID          meta.ID    `json:"id" swaggertype:"string"`
+	SubjectType string     `json:"subject_type"`
+	SubjectID   string     `json:"subject_id"`
+	RoleID      meta.ID    `json:"role_id" swaggertype:"string"`
+	TenantID    string     `json:"tenant_id"`
+	GrantedBy   string     `json:"granted_by"`
+	NotBefore   *time.Time `json:"not_before,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Status      string     `json:"status" enums:"pending,active,expired"`
 }
 
 // ListAssignmentQuery 列出赋权查询参数
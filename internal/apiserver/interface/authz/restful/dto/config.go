@@ -0,0 +1,32 @@
+// Package dto 声明式授权配置相关的 DTO 定义
+package dto
+
+// AuthzConfigChange 计划中的单项变更
+type AuthzConfigChange struct {
+	Kind   string   `json:"kind"`             // resource / role / policy / assignment
+	Op     string   `json:"op"`               // create / update / delete
+	Key    string   `json:"key"`              // 变更对象的可读标识
+	Fields []string `json:"fields,omitempty"` // update 时发生变化的字段
+}
+
+// AuthzConfigSummary 变更数量汇总
+type AuthzConfigSummary struct {
+	Create int `json:"create"`
+	Update int `json:"update"`
+	Delete int `json:"delete"`
+}
+
+// AuthzConfigPlanResponse 导入计划响应
+type AuthzConfigPlanResponse struct {
+	TenantID string              `json:"tenant_id"`
+	Prune    bool                `json:"prune"`
+	Summary  AuthzConfigSummary  `json:"summary"`
+	Changes  []AuthzConfigChange `json:"changes"`
+}
+
+// AuthzConfigApplyResponse 导入结果响应
+type AuthzConfigApplyResponse struct {
+	AuthzConfigPlanResponse
+	Applied bool  `json:"applied"` // 计划为空时为 false
+	Version int64 `json:"version"` // 变更后的策略版本；未变更时为 0
+}
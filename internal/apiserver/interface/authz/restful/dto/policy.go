@@ -1,7 +1,11 @@
 // Package dto 策略相关的 DTO 定义
 package dto
 
-import "github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+import (
+	"time"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
 
 // AddPolicyRequest 添加策略规则请求
 type AddPolicyRequest struct {
@@ -36,3 +40,48 @@ type PolicyVersionResponse struct {
 	ChangedBy string `json:"changed_by"`
 	Reason    string `json:"reason"`
 }
+
+// ListPolicyVersionsQuery 策略版本列表查询参数
+type ListPolicyVersionsQuery struct {
+	Offset int `form:"offset"`
+	Limit  int `form:"limit"`
+}
+
+// PolicyRuleChangeResponse 单条规则变更
+type PolicyRuleChangeResponse struct {
+	Op      string `json:"op"`    // add / remove
+	PType   string `json:"ptype"` // p / g
+	Subject string `json:"subject"`
+	Domain  string `json:"domain"`
+	Object  string `json:"object,omitempty"`
+	Action  string `json:"action,omitempty"`
+	Role    string `json:"role,omitempty"`
+}
+
+// PolicyVersionHistoryResponse 策略版本历史条目
+type PolicyVersionHistoryResponse struct {
+	PolicyVersionResponse
+	CreatedAt   time.Time                  `json:"created_at"`
+	Recorded    bool                       `json:"recorded"` // 是否记录了变更集，历史数据为 false
+	ChangeCount int                        `json:"change_count"`
+	Changes     []PolicyRuleChangeResponse `json:"changes,omitempty"` // 仅详情接口返回
+}
+
+// PolicyDiffQuery 版本差异查询参数
+type PolicyDiffQuery struct {
+	From int64 `form:"from" binding:"required"`
+	To   int64 `form:"to" binding:"required"`
+}
+
+// PolicyDiffResponse 版本差异响应
+type PolicyDiffResponse struct {
+	TenantID string                     `json:"tenant_id"`
+	From     int64                      `json:"from"`
+	To       int64                      `json:"to"`
+	Changes  []PolicyRuleChangeResponse `json:"changes"`
+}
+
+// RollbackPolicyRequest 策略回滚请求
+type RollbackPolicyRequest struct {
+	Reason string `json:"reason"`
+}
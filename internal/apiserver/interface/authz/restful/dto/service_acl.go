@@ -0,0 +1,42 @@
+// Package dto gRPC 服务 ACL 相关的 DTO 定义
+package dto
+
+import "github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+
+// CreateServiceACLRequest 登记调用方服务请求
+type CreateServiceACLRequest struct {
+	ServiceName    string   `json:"service_name" binding:"required"` // 调用方证书 CN
+	Enabled        *bool    `json:"enabled"`                         // 默认启用
+	Description    string   `json:"description"`
+	AllowedMethods []string `json:"allowed_methods"`
+	DeniedMethods  []string `json:"denied_methods"`
+}
+
+// UpdateServiceACLRequest 更新调用方服务请求（方法列表全量替换）
+type UpdateServiceACLRequest struct {
+	Enabled        *bool    `json:"enabled"`
+	Description    *string  `json:"description"`
+	AllowedMethods []string `json:"allowed_methods"`
+	DeniedMethods  []string `json:"denied_methods"`
+}
+
+// ServiceACLResponse 调用方服务 ACL 响应
+type ServiceACLResponse struct {
+	ID             meta.ID  `json:"id" swaggertype:"string"`
+	ServiceName    string   `json:"service_name"`
+	Enabled        bool     `json:"enabled"`
+	Description    string   `json:"description"`
+	AllowedMethods []string `json:"allowed_methods"`
+	DeniedMethods  []string `json:"denied_methods"`
+}
+
+// ServiceACLDryRunResponse 试运行判定响应
+type ServiceACLDryRunResponse struct {
+	ServiceName    string `json:"service_name"`
+	Method         string `json:"method"`
+	Allowed        bool   `json:"allowed"`
+	Reason         string `json:"reason"`
+	MatchedPattern string `json:"matched_pattern,omitempty"`
+	DefaultPolicy  string `json:"default_policy"`
+	Enforced       bool   `json:"enforced"` // 本实例是否启用了 gRPC ACL 拦截器
+}
@@ -36,6 +36,7 @@ func convertToSubjectType(s string) (assignmentDomain.SubjectType, error) {
 
 // GrantRole 授予角色
 // @Summary 授予角色
+// @Description 可指定有效期；未到生效时间的赋权状态为 pending，由调度器在生效/失效时间点增删角色并递增策略版本
 // @Tags Authorization-Assignments
 // @Accept json
 // @Produce json
@@ -72,6 +73,8 @@ func (h *AssignmentHandler) GrantRole(c *gin.Context) {
 		RoleID:      req.RoleID.Uint64(),
 		TenantID:    tenantID,
 		GrantedBy:   grantedBy,
+		NotBefore:   req.NotBefore,
+		ExpiresAt:   req.ExpiresAt,
 	}
 
 	grantedAssignment, err := h.commander.Grant(c.Request.Context(), cmd)
@@ -256,5 +259,8 @@ func (h *AssignmentHandler) toAssignmentResponse(a *assignmentDomain.Assignment)
 		RoleID:      meta.FromUint64(a.RoleID),
 		TenantID:    a.TenantID,
 		GrantedBy:   a.GrantedBy,
+		NotBefore:   a.NotBefore,
+		ExpiresAt:   a.ExpiresAt,
+		Status:      a.Status.String(),
 	}
 }
@@ -0,0 +1,209 @@
+// Package handler 声明式授权配置处理器
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	manifestDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/manifest"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/interface/authz/restful/dto"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/gin-gonic/gin"
+)
+
+// maxConfigBytes 导入文档大小上限
+const maxConfigBytes = 4 << 20
+
+// ConfigHandler 声明式授权配置处理器
+type ConfigHandler struct {
+	commander manifestDomain.Commander
+	queryer   manifestDomain.Queryer
+}
+
+// NewConfigHandler 创建声明式授权配置处理器
+func NewConfigHandler(commander manifestDomain.Commander, queryer manifestDomain.Queryer) *ConfigHandler {
+	return &ConfigHandler{
+		commander: commander,
+		queryer:   queryer,
+	}
+}
+
+// ExportConfig 导出租户授权配置
+// @Summary 导出声明式授权配置
+// @Description format=yaml 时直接返回 YAML 文档，便于保存到 git；默认返回 JSON 统一响应
+// @Tags Authorization-Config
+// @Produce json
+// @Produce application/yaml
+// @Param include_assignments query bool false "是否包含赋权"
+// @Param format query string false "json 或 yaml" default(json)
+// @Success 200 {object} dto.Response
+// @Router /authz/config [get]
+func (h *ConfigHandler) ExportConfig(c *gin.Context) {
+	tenantID, err := getTenantID(c)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	includeAssignments, err := boolQuery(c, "include_assignments")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "yaml" {
+		handleError(c, errors.WithCode(code.ErrInvalidArgument, "format 仅支持 json 或 yaml"))
+		return
+	}
+
+	doc, err := h.queryer.Export(c.Request.Context(), manifestDomain.ExportQuery{
+		TenantID:           tenantID,
+		IncludeAssignments: includeAssignments,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if format == "yaml" {
+		data, err := manifestDomain.EncodeYAML(doc)
+		if err != nil {
+			handleError(c, errors.WithCode(code.ErrEncodingFailed, "编码 YAML 失败: %v", err))
+			return
+		}
+		c.Data(http.StatusOK, "application/yaml; charset=utf-8", data)
+		return
+	}
+	success(c, doc)
+}
+
+// PlanConfig 计算导入计划
+// @Summary 预览声明式授权配置导入
+// @Description 请求体为 YAML 或 JSON 文档；只计算差异，不产生副作用
+// @Tags Authorization-Config
+// @Accept application/yaml
+// @Accept json
+// @Produce json
+// @Param prune query bool false "是否删除文档中未声明的租户角色"
+// @Success 200 {object} dto.Response{data=dto.AuthzConfigPlanResponse}
+// @Router /authz/config/plan [post]
+func (h *ConfigHandler) PlanConfig(c *gin.Context) {
+	cmd, ok := h.bindApplyCommand(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.queryer.Plan(c.Request.Context(), cmd)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	success(c, toConfigPlanResponse(plan, cmd.Prune))
+}
+
+// ApplyConfig 导入声明式授权配置
+// @Summary 导入声明式授权配置
+// @Description 全部变更在一个事务内执行并只递增一次策略版本；文档无变化时不递增
+// @Tags Authorization-Config
+// @Accept application/yaml
+// @Accept json
+// @Produce json
+// @Param prune query bool false "是否删除文档中未声明的租户角色"
+// @Param reason query string false "变更原因，记录到策略版本"
+// @Success 200 {object} dto.Response{data=dto.AuthzConfigApplyResponse}
+// @Router /authz/config/apply [post]
+func (h *ConfigHandler) ApplyConfig(c *gin.Context) {
+	cmd, ok := h.bindApplyCommand(c)
+	if !ok {
+		return
+	}
+	changedBy, err := getUserID(c)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	cmd.ChangedBy = changedBy
+	cmd.Reason = c.Query("reason")
+
+	result, err := h.commander.Apply(c.Request.Context(), cmd)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	success(c, dto.AuthzConfigApplyResponse{
+		AuthzConfigPlanResponse: toConfigPlanResponse(result.Plan, cmd.Prune),
+		Applied:                 !result.Plan.Empty(),
+		Version:                 result.Version,
+	})
+}
+
+func (h *ConfigHandler) bindApplyCommand(c *gin.Context) (manifestDomain.ApplyCommand, bool) {
+	tenantID, err := getTenantID(c)
+	if err != nil {
+		handleError(c, err)
+		return manifestDomain.ApplyCommand{}, false
+	}
+	prune, err := boolQuery(c, "prune")
+	if err != nil {
+		handleError(c, err)
+		return manifestDomain.ApplyCommand{}, false
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxConfigBytes))
+	if err != nil {
+		handleError(c, errors.WithCode(code.ErrBind, "读取请求体失败: %v", err))
+		return manifestDomain.ApplyCommand{}, false
+	}
+	doc, err := manifestDomain.Parse(data)
+	if err != nil {
+		handleError(c, err)
+		return manifestDomain.ApplyCommand{}, false
+	}
+
+	return manifestDomain.ApplyCommand{
+		TenantID: tenantID,
+		Manifest: doc,
+		Prune:    prune,
+	}, true
+}
+
+func boolQuery(c *gin.Context, name string) (bool, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, errors.WithCode(code.ErrInvalidArgument, "%s 必须为布尔值", name)
+	}
+	return value, nil
+}
+
+func toConfigPlanResponse(plan *manifestDomain.Plan, prune bool) dto.AuthzConfigPlanResponse {
+	resp := dto.AuthzConfigPlanResponse{
+		Prune:   prune,
+		Changes: make([]dto.AuthzConfigChange, 0),
+	}
+	if plan == nil {
+		return resp
+	}
+	resp.TenantID = plan.TenantID
+	for _, change := range plan.Changes {
+		switch change.Op {
+		case manifestDomain.ChangeOpCreate:
+			resp.Summary.Create++
+		case manifestDomain.ChangeOpUpdate:
+			resp.Summary.Update++
+		case manifestDomain.ChangeOpDelete:
+			resp.Summary.Delete++
+		}
+		resp.Changes = append(resp.Changes, dto.AuthzConfigChange{
+			Kind:   string(change.Kind),
+			Op:     string(change.Op),
+			Key:    change.Key,
+			Fields: change.Fields,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,226 @@
+// Package handler 策略变更历史处理器
+package handler
+
+import (
+	"strconv"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	policyDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/policy"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/interface/authz/restful/dto"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/gin-gonic/gin"
+)
+
+// PolicyHistoryHandler 策略变更历史处理器
+type PolicyHistoryHandler struct {
+	commander policyDomain.HistoryCommander
+	queryer   policyDomain.HistoryQueryer
+}
+
+// NewPolicyHistoryHandler 创建策略变更历史处理器
+func NewPolicyHistoryHandler(commander policyDomain.HistoryCommander, queryer policyDomain.HistoryQueryer) *PolicyHistoryHandler {
+	return &PolicyHistoryHandler{
+		commander: commander,
+		queryer:   queryer,
+	}
+}
+
+// ListVersions 列出策略版本历史
+// @Summary 列出策略版本历史
+// @Description 按版本号倒序返回，每个版本附带其变更数量
+// @Tags Authorization-Policies
+// @Produce json
+// @Param offset query int false "偏移量"
+// @Param limit query int false "每页数量"
+// @Success 200 {object} dto.ListResponse{data=[]dto.PolicyVersionHistoryResponse}
+// @Router /authz/policies/versions [get]
+func (h *PolicyHistoryHandler) ListVersions(c *gin.Context) {
+	var query dto.ListPolicyVersionsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		handleError(c, errors.WithCode(code.ErrBind, "请求参数错误: %v", err))
+		return
+	}
+
+	tenantID, err := getTenantID(c)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	versions, total, err := h.queryer.ListVersions(c.Request.Context(), policyDomain.ListVersionsQuery{
+		TenantID: tenantID,
+		Offset:   query.Offset,
+		Limit:    query.Limit,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	items := make([]dto.PolicyVersionHistoryResponse, 0, len(versions))
+	for _, v := range versions {
+		items = append(items, toPolicyVersionHistoryResponse(v, false))
+	}
+	successList(c, items, total, query.Offset, query.Limit)
+}
+
+// GetVersion 获取策略版本详情
+// @Summary 获取策略版本详情
+// @Description 返回产生该版本的规则变更集
+// @Tags Authorization-Policies
+// @Produce json
+// @Param version path int true "版本号"
+// @Success 200 {object} dto.Response{data=dto.PolicyVersionHistoryResponse}
+// @Router /authz/policies/versions/{version} [get]
+func (h *PolicyHistoryHandler) GetVersion(c *gin.Context) {
+	version, ok := versionParam(c)
+	if !ok {
+		return
+	}
+
+	tenantID, err := getTenantID(c)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	found, err := h.queryer.GetVersion(c.Request.Context(), tenantID, version)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	success(c, toPolicyVersionHistoryResponse(found, true))
+}
+
+// DiffVersions 比较两个策略版本
+// @Summary 比较两个策略版本
+// @Description 返回从 from 版本变为 to 版本的净规则变更；from 大于 to 时即回滚所需的变更
+// @Tags Authorization-Policies
+// @Produce json
+// @Param from query int true "起始版本"
+// @Param to query int true "目标版本"
+// @Success 200 {object} dto.Response{data=dto.PolicyDiffResponse}
+// @Router /authz/policies/diff [get]
+func (h *PolicyHistoryHandler) DiffVersions(c *gin.Context) {
+	var query dto.PolicyDiffQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		handleError(c, errors.WithCode(code.ErrBind, "请求参数错误: %v", err))
+		return
+	}
+
+	tenantID, err := getTenantID(c)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	changes, err := h.queryer.Diff(c.Request.Context(), policyDomain.DiffVersionsQuery{
+		TenantID: tenantID,
+		From:     query.From,
+		To:       query.To,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	success(c, dto.PolicyDiffResponse{
+		TenantID: tenantID,
+		From:     query.From,
+		To:       query.To,
+		Changes:  toPolicyRuleChangeResponses(changes),
+	})
+}
+
+// RollbackVersion 回滚到指定策略版本
+// @Summary 回滚到指定策略版本
+// @Description 应用逆向变更集并产生一个新版本；期间规则无净变化时不产生新版本
+// @Tags Authorization-Policies
+// @Accept json
+// @Produce json
+// @Param version path int true "回滚到的版本号"
+// @Param request body dto.RollbackPolicyRequest false "回滚请求"
+// @Success 200 {object} dto.Response{data=dto.PolicyVersionHistoryResponse}
+// @Router /authz/policies/versions/{version}/rollback [post]
+func (h *PolicyHistoryHandler) RollbackVersion(c *gin.Context) {
+	version, ok := versionParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.RollbackPolicyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			handleError(c, errors.WithCode(code.ErrBind, "请求参数错误: %v", err))
+			return
+		}
+	}
+
+	tenantID, err := getTenantID(c)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	changedBy, err := getUserID(c)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	current, err := h.commander.Rollback(c.Request.Context(), policyDomain.RollbackCommand{
+		TenantID:      tenantID,
+		TargetVersion: version,
+		ChangedBy:     changedBy,
+		Reason:        req.Reason,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	success(c, toPolicyVersionHistoryResponse(current, true))
+}
+
+func versionParam(c *gin.Context) (int64, bool) {
+	version, err := strconv.ParseInt(c.Param("version"), 10, 64)
+	if err != nil || version <= 0 {
+		handleError(c, errors.WithCode(code.ErrInvalidArgument, "版本号格式错误"))
+		return 0, false
+	}
+	return version, true
+}
+
+func toPolicyVersionHistoryResponse(v *policyDomain.PolicyVersion, withChanges bool) dto.PolicyVersionHistoryResponse {
+	resp := dto.PolicyVersionHistoryResponse{
+		PolicyVersionResponse: dto.PolicyVersionResponse{
+			TenantID:  v.TenantID,
+			Version:   v.Version,
+			ChangedBy: v.ChangedBy,
+			Reason:    v.Reason,
+		},
+		CreatedAt:   v.CreatedAt,
+		Recorded:    v.Recorded(),
+		ChangeCount: len(v.Changes),
+	}
+	if withChanges {
+		resp.Changes = toPolicyRuleChangeResponses(v.Changes)
+	}
+	return resp
+}
+
+func toPolicyRuleChangeResponses(changes []policyDomain.RuleChange) []dto.PolicyRuleChangeResponse {
+	items := make([]dto.PolicyRuleChangeResponse, 0, len(changes))
+	for _, c := range changes {
+		items = append(items, dto.PolicyRuleChangeResponse{
+			Op:      string(c.Op),
+			PType:   c.PType,
+			Subject: c.Sub,
+			Domain:  c.Dom,
+			Object:  c.Obj,
+			Action:  c.Act,
+			Role:    c.Role,
+		})
+	}
+	return items
+}
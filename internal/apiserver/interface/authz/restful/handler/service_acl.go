@@ -0,0 +1,187 @@
+// Package handler gRPC 服务 ACL 管理处理器
+package handler
+
+import (
+	"github.com/FangcunMount/component-base/pkg/errors"
+	serviceACLDomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/authz/serviceacl"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/interface/authz/restful/dto"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceACLHandler gRPC 服务 ACL 处理器（平台管理员）
+type ServiceACLHandler struct {
+	commander serviceACLDomain.Commander
+	queryer   serviceACLDomain.Queryer
+}
+
+// NewServiceACLHandler 创建 gRPC 服务 ACL 处理器
+func NewServiceACLHandler(
+	commander serviceACLDomain.Commander,
+	queryer serviceACLDomain.Queryer,
+) *ServiceACLHandler {
+	return &ServiceACLHandler{
+		commander: commander,
+		queryer:   queryer,
+	}
+}
+
+// CreateService 登记调用方服务
+// @Summary 登记 gRPC 调用方服务
+// @Tags Admin-GRPC-ACL
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateServiceACLRequest true "登记请求"
+// @Success 200 {object} dto.Response{data=dto.ServiceACLResponse}
+// @Router /admin/grpc-acl/services [post]
+func (h *ServiceACLHandler) CreateService(c *gin.Context) {
+	var req dto.CreateServiceACLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, errors.WithCode(code.ErrBind, "请求参数错误: %v", err))
+		return
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	entry, err := h.commander.CreateEntry(c.Request.Context(), serviceACLDomain.CreateEntryCommand{
+		ServiceName:    req.ServiceName,
+		Enabled:        enabled,
+		Description:    req.Description,
+		AllowedMethods: req.AllowedMethods,
+		DeniedMethods:  req.DeniedMethods,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	success(c, toServiceACLResponse(entry))
+}
+
+// UpdateService 更新调用方服务
+// @Summary 更新 gRPC 调用方服务的方法授权
+// @Tags Admin-GRPC-ACL
+// @Accept json
+// @Produce json
+// @Param service path string true "调用方服务名（证书 CN）"
+// @Param request body dto.UpdateServiceACLRequest true "更新请求"
+// @Success 200 {object} dto.Response{data=dto.ServiceACLResponse}
+// @Router /admin/grpc-acl/services/{service} [put]
+func (h *ServiceACLHandler) UpdateService(c *gin.Context) {
+	var req dto.UpdateServiceACLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, errors.WithCode(code.ErrBind, "请求参数错误: %v", err))
+		return
+	}
+
+	entry, err := h.commander.UpdateEntry(c.Request.Context(), serviceACLDomain.UpdateEntryCommand{
+		ServiceName:    c.Param("service"),
+		Enabled:        req.Enabled,
+		Description:    req.Description,
+		AllowedMethods: req.AllowedMethods,
+		DeniedMethods:  req.DeniedMethods,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	success(c, toServiceACLResponse(entry))
+}
+
+// DeleteService 删除调用方服务
+// @Summary 删除 gRPC 调用方服务（之后按默认策略判定）
+// @Tags Admin-GRPC-ACL
+// @Param service path string true "调用方服务名（证书 CN）"
+// @Success 200 {object} dto.Response
+// @Router /admin/grpc-acl/services/{service} [delete]
+func (h *ServiceACLHandler) DeleteService(c *gin.Context) {
+	if err := h.commander.DeleteEntry(c.Request.Context(), c.Param("service")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	successNoContent(c)
+}
+
+// GetService 查询调用方服务
+// @Summary 查询 gRPC 调用方服务
+// @Tags Admin-GRPC-ACL
+// @Produce json
+// @Param service path string true "调用方服务名（证书 CN）"
+// @Success 200 {object} dto.Response{data=dto.ServiceACLResponse}
+// @Router /admin/grpc-acl/services/{service} [get]
+func (h *ServiceACLHandler) GetService(c *gin.Context) {
+	entry, err := h.queryer.GetEntry(c.Request.Context(), c.Param("service"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	success(c, toServiceACLResponse(entry))
+}
+
+// ListServices 列出调用方服务
+// @Summary 列出 gRPC 调用方服务
+// @Tags Admin-GRPC-ACL
+// @Produce json
+// @Success 200 {object} dto.Response{data=[]dto.ServiceACLResponse}
+// @Router /admin/grpc-acl/services [get]
+func (h *ServiceACLHandler) ListServices(c *gin.Context) {
+	entries, err := h.queryer.ListEntries(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	items := make([]*dto.ServiceACLResponse, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, toServiceACLResponse(entry))
+	}
+	success(c, items)
+}
+
+// DryRun 试运行判定
+// @Summary 判定调用方服务能否调用指定 gRPC 方法（不产生副作用）
+// @Tags Admin-GRPC-ACL
+// @Produce json
+// @Param service query string true "调用方服务名（证书 CN）"
+// @Param method query string true "完整方法名，如 /iam.identity.v1.IdentityRead/GetUser"
+// @Success 200 {object} dto.Response{data=dto.ServiceACLDryRunResponse}
+// @Router /admin/grpc-acl/dry-run [get]
+func (h *ServiceACLHandler) DryRun(c *gin.Context) {
+	service, method := c.Query("service"), c.Query("method")
+	if service == "" || method == "" {
+		handleError(c, errors.WithCode(code.ErrInvalidArgument, "service 与 method 不能为空"))
+		return
+	}
+
+	result, err := h.queryer.DryRun(c.Request.Context(), service, method)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	success(c, &dto.ServiceACLDryRunResponse{
+		ServiceName:    result.ServiceName,
+		Method:         result.Method,
+		Allowed:        result.Allowed,
+		Reason:         result.Reason,
+		MatchedPattern: result.MatchedPattern,
+		DefaultPolicy:  result.DefaultPolicy,
+		Enforced:       result.Enforced,
+	})
+}
+
+func toServiceACLResponse(entry *serviceACLDomain.Entry) *dto.ServiceACLResponse {
+	return &dto.ServiceACLResponse{
+		ID:             entry.ID,
+		ServiceName:    entry.ServiceName,
+		Enabled:        entry.Enabled,
+		Description:    entry.Description,
+		AllowedMethods: entry.AllowedMethods,
+		DeniedMethods:  entry.DeniedMethods,
+	}
+}
@@ -14,6 +14,10 @@ type Dependencies struct {
 	PolicyHandler     *handler.PolicyHandler
 	ResourceHandler   *handler.ResourceHandler
 	CheckHandler      *handler.CheckHandler
+	// ConfigHandler 声明式授权配置导入导出（可选）
+	ConfigHandler *handler.ConfigHandler
+	// HistoryHandler 策略变更历史、差异与回滚（可选）
+	HistoryHandler *handler.PolicyHistoryHandler
 	// AuthMiddleware 保护除 /health 外的管理面与 PDP；若为空则不注册受保护路由。
 	AuthMiddleware gin.HandlerFunc
 }
@@ -80,6 +84,12 @@ func Register(engine *gin.Engine) {
 			policies.POST("", deps.PolicyHandler.AddPolicyRule)
 			policies.DELETE("", deps.PolicyHandler.RemovePolicyRule)
 			policies.GET("/version", deps.PolicyHandler.GetCurrentVersion)
+			if deps.HistoryHandler != nil {
+				policies.GET("/versions", deps.HistoryHandler.ListVersions)
+				policies.GET("/versions/:version", deps.HistoryHandler.GetVersion)
+				policies.POST("/versions/:version/rollback", deps.HistoryHandler.RollbackVersion)
+				policies.GET("/diff", deps.HistoryHandler.DiffVersions)
+			}
 		}
 
 		resources := g.Group("/resources")
@@ -92,5 +102,15 @@ func Register(engine *gin.Engine) {
 			resources.GET("", deps.ResourceHandler.ListResources)
 			resources.POST("/validate-action", deps.ResourceHandler.ValidateAction)
 		}
+
+		// ============ 声明式配置 ============
+		if deps.ConfigHandler != nil {
+			config := g.Group("/config")
+			{
+				config.GET("", deps.ConfigHandler.ExportConfig)
+				config.POST("/plan", deps.ConfigHandler.PlanConfig)
+				config.POST("/apply", deps.ConfigHandler.ApplyConfig)
+			}
+		}
 	}
 }
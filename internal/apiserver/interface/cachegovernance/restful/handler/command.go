@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	cachegovernance "github.com/FangcunMount/iam-contracts/internal/apiserver/application/cachegovernance"
+	cacheinfra "github.com/FangcunMount/iam-contracts/internal/apiserver/infra/cache"
+
+	requestdto "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/cachegovernance/restful/request"
+	responsedto "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/cachegovernance/restful/response"
+)
+
+// CommandHandler 提供需要管理员授权的缓存治理写接口。
+type CommandHandler struct {
+	*BaseHandler
+	service *cachegovernance.CommandService
+}
+
+// NewCommandHandler 创建缓存治理写接口处理器。
+func NewCommandHandler(service *cachegovernance.CommandService) *CommandHandler {
+	return &CommandHandler{
+		BaseHandler: NewBaseHandler(),
+		service:     service,
+	}
+}
+
+// Purge 清理单个 key 或整个缓存族。
+func (h *CommandHandler) Purge(c *gin.Context) {
+	if h.service == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "cache governance command service not initialized"})
+		return
+	}
+
+	var req requestdto.PurgeRequest
+	if c.Request.ContentLength > 0 {
+		if err := h.BindJSON(c, &req); err != nil {
+			return
+		}
+	}
+
+	family := cacheinfra.Family(c.Param("family"))
+	deleted, err := h.service.Purge(c.Request.Context(), h.actor(c), family, req.Key)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, responsedto.PurgeResponse{
+		Family:  string(family),
+		Key:     req.Key,
+		Deleted: deleted,
+	})
+}
+
+// Warm 触发缓存族预热。
+func (h *CommandHandler) Warm(c *gin.Context) {
+	if h.service == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "cache governance command service not initialized"})
+		return
+	}
+
+	var req requestdto.WarmRequest
+	if c.Request.ContentLength > 0 {
+		if err := h.BindJSON(c, &req); err != nil {
+			return
+		}
+	}
+
+	family := cacheinfra.Family(c.Param("family"))
+	if err := h.service.Warm(c.Request.Context(), h.actor(c), family, req.Target); err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, responsedto.WarmResponse{
+		Family: string(family),
+		Target: req.Target,
+		Warmed: true,
+	})
+}
+
+// SampleKeys 抽样缓存族下的 key 与剩余 TTL。
+func (h *CommandHandler) SampleKeys(c *gin.Context) {
+	if h.service == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "cache governance command service not initialized"})
+		return
+	}
+
+	family := cacheinfra.Family(c.Param("family"))
+	limit := h.GetQueryParamInt(c, "limit", 0)
+	samples, err := h.service.SampleKeys(c.Request.Context(), h.actor(c), family, limit)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	h.Success(c, responsedto.FromKeySamples(family, samples))
+}
+
+func (h *CommandHandler) actor(c *gin.Context) string {
+	if userID, ok := h.GetUserID(c); ok {
+		return userID
+	}
+	return "unknown"
+}
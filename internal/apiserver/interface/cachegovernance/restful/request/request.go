@@ -0,0 +1,11 @@
+package request
+
+// PurgeRequest 清理缓存族；Key 为空时清理整个缓存族。
+type PurgeRequest struct {
+	Key string `json:"key"`
+}
+
+// WarmRequest 预热缓存族；Target 的含义由缓存族决定（如微信 appID）。
+type WarmRequest struct {
+	Target string `json:"target"`
+}
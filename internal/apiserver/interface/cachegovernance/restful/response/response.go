@@ -135,3 +135,43 @@ func fromRuntimeStatus(status cacheinfra.RuntimeStatus) RuntimeStatusResponse {
 		Notes:      append([]string{}, status.Notes...),
 	}
 }
+
+type PurgeResponse struct {
+	Family  string `json:"family"`
+	Key     string `json:"key,omitempty"`
+	Deleted int64  `json:"deleted"`
+}
+
+type WarmResponse struct {
+	Family string `json:"family"`
+	Target string `json:"target,omitempty"`
+	Warmed bool   `json:"warmed"`
+}
+
+type KeySampleResponse struct {
+	Key        string `json:"key"`
+	TTLSeconds int64  `json:"ttl_seconds"`
+	Persistent bool   `json:"persistent"`
+}
+
+type KeySamplesResponse struct {
+	Family string              `json:"family"`
+	Total  int                 `json:"total"`
+	Keys   []KeySampleResponse `json:"keys"`
+}
+
+func FromKeySamples(family cacheinfra.Family, samples []cacheinfra.KeySample) KeySamplesResponse {
+	keys := make([]KeySampleResponse, 0, len(samples))
+	for _, sample := range samples {
+		keys = append(keys, KeySampleResponse{
+			Key:        sample.Key,
+			TTLSeconds: int64(sample.TTL.Seconds()),
+			Persistent: sample.Persistent,
+		})
+	}
+	return KeySamplesResponse{
+		Family: string(family),
+		Total:  len(keys),
+		Keys:   keys,
+	}
+}
@@ -0,0 +1,398 @@
+// Package handler 上游 OIDC 身份提供商管理 REST API 处理器
+package handler
+
+import (
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/idp/oidcprovider"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/oidcprovider"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/interface/idp/restful/request"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/interface/idp/restful/response"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/meta"
+)
+
+// OIDCProviderHandler 上游 OIDC 身份提供商 REST 处理器
+type OIDCProviderHandler struct {
+	*BaseHandler
+	providerService   oidcprovider.OIDCProviderApplicationService
+	credentialService oidcprovider.OIDCProviderCredentialApplicationService
+	authorizeService  oidcprovider.OIDCAuthorizeApplicationService
+}
+
+// NewOIDCProviderHandler 创建上游 OIDC 身份提供商处理器
+func NewOIDCProviderHandler(
+	providerService oidcprovider.OIDCProviderApplicationService,
+	credentialService oidcprovider.OIDCProviderCredentialApplicationService,
+	authorizeService oidcprovider.OIDCAuthorizeApplicationService,
+) *OIDCProviderHandler {
+	return &OIDCProviderHandler{
+		BaseHandler:       NewBaseHandler(),
+		providerService:   providerService,
+		credentialService: credentialService,
+		authorizeService:  authorizeService,
+	}
+}
+
+// ListOIDCProviders 查询上游 OIDC 身份提供商列表
+// @Summary 查询上游 OIDC 身份提供商列表
+// @Tags IDP-OIDC
+// @Accept json
+// @Produce json
+// @Param status query string false "状态 (Enabled/Disabled/Archived)"
+// @Success 200 {object} response.OIDCProviderListResponse "查询成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数错误"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/oidc-providers [get]
+func (h *OIDCProviderHandler) ListOIDCProviders(c *gin.Context) {
+	var req request.ListOIDCProvidersRequest
+	if err := h.BindQuery(c, &req); err != nil {
+		return
+	}
+
+	filter := oidcprovider.ListOIDCProvidersFilter{}
+	if req.Status != "" {
+		status, err := parseOIDCProviderStatus(req.Status)
+		if err != nil {
+			h.Error(c, err)
+			return
+		}
+		filter.Status = &status
+	}
+
+	results, err := h.providerService.ListProviders(c.Request.Context(), filter)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	items := make([]*response.OIDCProviderResponse, 0, len(results))
+	for _, result := range results {
+		items = append(items, toOIDCProviderResponse(result))
+	}
+
+	h.Success(c, &response.OIDCProviderListResponse{
+		Total: len(items),
+		Items: items,
+	})
+}
+
+// CreateOIDCProvider 登记上游 OIDC 身份提供商
+// @Summary 登记上游 OIDC 身份提供商
+// @Tags IDP-OIDC
+// @Accept json
+// @Produce json
+// @Param request body request.CreateOIDCProviderRequest true "登记上游 OIDC 身份提供商请求"
+// @Success 201 {object} response.OIDCProviderResponse "创建成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数错误"
+// @Failure 409 {object} response.ErrorResponse "提供商已存在"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/oidc-providers [post]
+func (h *OIDCProviderHandler) CreateOIDCProvider(c *gin.Context) {
+	var req request.CreateOIDCProviderRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	tenantID, err := parseOptionalTenantID(req.TenantID)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	result, err := h.providerService.CreateProvider(c.Request.Context(), oidcprovider.CreateOIDCProviderDTO{
+		Slug:          req.Slug,
+		Name:          req.Name,
+		Issuer:        req.Issuer,
+		ClientID:      req.ClientID,
+		ClientSecret:  req.ClientSecret,
+		Scopes:        req.Scopes,
+		Claims:        toClaimMapping(req.ClaimMapping),
+		RoleMappings:  toRoleMappingRules(req.RoleMappings),
+		AutoProvision: req.AutoProvision,
+		TenantID:      tenantID,
+	})
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, toOIDCProviderResponse(result))
+}
+
+// GetOIDCProvider 查询上游 OIDC 身份提供商
+// @Summary 查询上游 OIDC 身份提供商
+// @Tags IDP-OIDC
+// @Accept json
+// @Produce json
+// @Param slug path string true "提供商标识"
+// @Success 200 {object} response.OIDCProviderResponse "查询成功"
+// @Failure 404 {object} response.ErrorResponse "提供商不存在"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/oidc-providers/{slug} [get]
+func (h *OIDCProviderHandler) GetOIDCProvider(c *gin.Context) {
+	var req request.GetOIDCProviderRequest
+	if err := h.BindURI(c, &req); err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	result, err := h.providerService.GetProvider(c.Request.Context(), req.Slug)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, toOIDCProviderResponse(result))
+}
+
+// UpdateOIDCProvider 更新上游 OIDC 身份提供商配置
+// @Summary 更新上游 OIDC 身份提供商配置
+// @Tags IDP-OIDC
+// @Accept json
+// @Produce json
+// @Param slug path string true "提供商标识"
+// @Param request body request.UpdateOIDCProviderRequest true "更新上游 OIDC 身份提供商请求"
+// @Success 200 {object} response.OIDCProviderResponse "更新成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数错误"
+// @Failure 404 {object} response.ErrorResponse "提供商不存在"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/oidc-providers/{slug} [patch]
+func (h *OIDCProviderHandler) UpdateOIDCProvider(c *gin.Context) {
+	var uri request.GetOIDCProviderRequest
+	if err := h.BindURI(c, &uri); err != nil {
+		return
+	}
+
+	var req request.UpdateOIDCProviderRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	dto := oidcprovider.UpdateOIDCProviderDTO{
+		Name:          req.Name,
+		Issuer:        req.Issuer,
+		ClientID:      req.ClientID,
+		Scopes:        req.Scopes,
+		AutoProvision: req.AutoProvision,
+	}
+	if req.ClaimMapping != nil {
+		claims := toClaimMapping(*req.ClaimMapping)
+		dto.Claims = &claims
+	}
+	if req.RoleMappings != nil {
+		rules := toRoleMappingRules(*req.RoleMappings)
+		dto.RoleMappings = &rules
+	}
+	if req.TenantID != nil {
+		tenantID, err := parseOptionalTenantID(*req.TenantID)
+		if err != nil {
+			h.Error(c, err)
+			return
+		}
+		dto.TenantID = &tenantID
+	}
+	if dto == (oidcprovider.UpdateOIDCProviderDTO{}) {
+		h.Error(c, perrors.WithCode(code.ErrInvalidArgument, "at least one field must be updated"))
+		return
+	}
+
+	result, err := h.providerService.UpdateProvider(c.Request.Context(), uri.Slug, dto)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, toOIDCProviderResponse(result))
+}
+
+// EnableOIDCProvider 启用上游 OIDC 身份提供商
+// @Summary 启用上游 OIDC 身份提供商
+// @Tags IDP-OIDC
+// @Accept json
+// @Produce json
+// @Param slug path string true "提供商标识"
+// @Success 200 {object} response.OIDCProviderResponse "启用成功"
+// @Failure 404 {object} response.ErrorResponse "提供商不存在"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/oidc-providers/{slug}/enable [post]
+func (h *OIDCProviderHandler) EnableOIDCProvider(c *gin.Context) {
+	var req request.GetOIDCProviderRequest
+	if err := h.BindURI(c, &req); err != nil {
+		return
+	}
+
+	result, err := h.providerService.EnableProvider(c.Request.Context(), req.Slug)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, toOIDCProviderResponse(result))
+}
+
+// DisableOIDCProvider 禁用上游 OIDC 身份提供商
+// @Summary 禁用上游 OIDC 身份提供商
+// @Tags IDP-OIDC
+// @Accept json
+// @Produce json
+// @Param slug path string true "提供商标识"
+// @Success 200 {object} response.OIDCProviderResponse "禁用成功"
+// @Failure 404 {object} response.ErrorResponse "提供商不存在"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/oidc-providers/{slug}/disable [post]
+func (h *OIDCProviderHandler) DisableOIDCProvider(c *gin.Context) {
+	var req request.GetOIDCProviderRequest
+	if err := h.BindURI(c, &req); err != nil {
+		return
+	}
+
+	result, err := h.providerService.DisableProvider(c.Request.Context(), req.Slug)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, toOIDCProviderResponse(result))
+}
+
+// RotateClientSecret 轮换 client_secret
+// @Summary 轮换上游 OIDC 身份提供商 client_secret
+// @Tags IDP-OIDC
+// @Accept json
+// @Produce json
+// @Param slug path string true "提供商标识"
+// @Param request body request.RotateClientSecretRequest true "轮换 client_secret 请求"
+// @Success 200 {object} response.RotateSecretResponse "轮换成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数错误"
+// @Failure 404 {object} response.ErrorResponse "提供商不存在"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/oidc-providers/{slug}/rotate-client-secret [post]
+func (h *OIDCProviderHandler) RotateClientSecret(c *gin.Context) {
+	var uri request.GetOIDCProviderRequest
+	if err := h.BindURI(c, &uri); err != nil {
+		return
+	}
+
+	var req request.RotateClientSecretRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	if err := h.credentialService.RotateClientSecret(c.Request.Context(), uri.Slug, req.NewSecret); err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, &response.RotateSecretResponse{
+		Success: true,
+		Message: "Client secret rotated successfully",
+	})
+}
+
+// GetAuthorizeURL 构造上游授权地址
+// @Summary 构造上游 OIDC 授权地址（前端跳转后回调携带 code 与 state 调用 /authn/login）
+// @Tags IDP-OIDC
+// @Accept json
+// @Produce json
+// @Param slug path string true "提供商标识"
+// @Param redirect_uri query string true "回调地址"
+// @Param code_challenge query string false "PKCE S256 challenge"
+// @Success 200 {object} response.OIDCAuthorizeURLResponse "构造成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数错误"
+// @Failure 404 {object} response.ErrorResponse "提供商不存在"
+// @Failure 502 {object} response.ErrorResponse "上游 discovery 失败"
+// @Router /idp/oidc-providers/{slug}/authorize-url [get]
+func (h *OIDCProviderHandler) GetAuthorizeURL(c *gin.Context) {
+	var uri request.GetOIDCProviderRequest
+	if err := h.BindURI(c, &uri); err != nil {
+		return
+	}
+
+	var req request.OIDCAuthorizeURLRequest
+	if err := h.BindQuery(c, &req); err != nil {
+		return
+	}
+
+	result, err := h.authorizeService.BuildAuthorizeURL(c.Request.Context(), uri.Slug, oidcprovider.AuthorizeURLDTO{
+		RedirectURI:   req.RedirectURI,
+		CodeChallenge: req.CodeChallenge,
+	})
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, &response.OIDCAuthorizeURLResponse{AuthorizeURL: result.AuthorizeURL, State: result.State})
+}
+
+func toOIDCProviderResponse(result *oidcprovider.OIDCProviderResult) *response.OIDCProviderResponse {
+	if result == nil {
+		return nil
+	}
+	rules := make([]response.OIDCRoleMappingResponse, 0, len(result.RoleMappings))
+	for _, rule := range result.RoleMappings {
+		rules = append(rules, response.OIDCRoleMappingResponse{Claim: rule.Claim, Value: rule.Value, Role: rule.Role})
+	}
+	return &response.OIDCProviderResponse{
+		ID:       result.ID,
+		Slug:     result.Slug,
+		Name:     result.Name,
+		Issuer:   result.Issuer,
+		ClientID: result.ClientID,
+		Scopes:   result.Scopes,
+		ClaimMapping: response.OIDCClaimMappingResponse{
+			Subject:  result.Claims.Subject,
+			Username: result.Claims.Username,
+			Email:    result.Claims.Email,
+			Name:     result.Claims.Name,
+			Phone:    result.Claims.Phone,
+		},
+		RoleMappings:    rules,
+		AutoProvision:   result.AutoProvision,
+		TenantID:        result.TenantID,
+		Status:          string(result.Status),
+		HasClientSecret: result.HasClientSecret,
+	}
+}
+
+func toClaimMapping(req request.OIDCClaimMappingRequest) domain.ClaimMapping {
+	return domain.ClaimMapping{
+		Subject:  req.Subject,
+		Username: req.Username,
+		Email:    req.Email,
+		Name:     req.Name,
+		Phone:    req.Phone,
+	}
+}
+
+func toRoleMappingRules(reqs []request.OIDCRoleMappingRequest) []domain.RoleMappingRule {
+	rules := make([]domain.RoleMappingRule, 0, len(reqs))
+	for _, r := range reqs {
+		rules = append(rules, domain.RoleMappingRule{Claim: r.Claim, Value: r.Value, Role: r.Role})
+	}
+	return rules
+}
+
+func parseOptionalTenantID(raw string) (meta.ID, error) {
+	if raw == "" {
+		return meta.ID(0), nil
+	}
+	id, err := meta.ParseID(raw)
+	if err != nil {
+		return meta.ID(0), perrors.WithCode(code.ErrInvalidArgument, "invalid tenant_id: %s", raw)
+	}
+	return id, nil
+}
+
+func parseOIDCProviderStatus(raw string) (domain.Status, error) {
+	switch domain.Status(raw) {
+	case domain.StatusEnabled, domain.StatusDisabled, domain.StatusArchived:
+		return domain.Status(raw), nil
+	default:
+		return "", perrors.WithCode(code.ErrOIDCProviderStatusInvalid, "invalid oidc provider status: %s", raw)
+	}
+}
@@ -0,0 +1,113 @@
+// Package handler 微信消息推送回调处理器
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/idp/wechatapp"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/interface/idp/restful/request"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+// maxCallbackBodyBytes 回调请求体上限（微信推送消息远小于该值）
+const maxCallbackBodyBytes = 1 << 20
+
+// WechatCallbackHandler 微信服务器消息推送回调处理器
+//
+// 回调由微信服务器直接调用，依靠签名校验而非管理员认证
+type WechatCallbackHandler struct {
+	*BaseHandler
+	callbackService wechatapp.WechatAppCallbackApplicationService
+}
+
+// NewWechatCallbackHandler 创建微信回调处理器
+func NewWechatCallbackHandler(callbackService wechatapp.WechatAppCallbackApplicationService) *WechatCallbackHandler {
+	return &WechatCallbackHandler{
+		BaseHandler:     NewBaseHandler(),
+		callbackService: callbackService,
+	}
+}
+
+// VerifyCallbackURL 服务器配置校验
+// @Summary 微信服务器配置校验
+// @Description 微信公众平台配置服务器地址时调用，验签通过后原样返回 echostr
+// @Tags IDP-Wechat
+// @Produce plain
+// @Param app_id path string true "微信应用 ID"
+// @Param signature query string true "签名"
+// @Param timestamp query string true "时间戳"
+// @Param nonce query string true "随机数"
+// @Param echostr query string true "回显字符串"
+// @Success 200 {string} string "echostr"
+// @Failure 401 {object} response.ErrorResponse "签名校验失败"
+// @Failure 404 {object} response.ErrorResponse "微信应用不存在"
+// @Router /idp/wechat-apps/{app_id}/callback [get]
+func (h *WechatCallbackHandler) VerifyCallbackURL(c *gin.Context) {
+	var query request.WechatCallbackQuery
+	if err := h.BindQuery(c, &query); err != nil {
+		return
+	}
+
+	echo, err := h.callbackService.VerifyURL(c.Request.Context(), wechatapp.VerifyCallbackURLDTO{
+		AppID:     c.Param("app_id"),
+		Signature: query.Signature,
+		Timestamp: query.Timestamp,
+		Nonce:     query.Nonce,
+		EchoStr:   query.EchoStr,
+	})
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	c.String(http.StatusOK, echo)
+}
+
+// ReceiveCallbackMessage 接收推送消息
+// @Summary 接收微信推送消息
+// @Description 支持明文与安全模式（encrypt_type=aes）；撤回授权事件会禁用关联凭据，其余消息转发到消息总线
+// @Tags IDP-Wechat
+// @Accept xml
+// @Produce plain
+// @Param app_id path string true "微信应用 ID"
+// @Param signature query string false "明文模式签名"
+// @Param timestamp query string true "时间戳"
+// @Param nonce query string true "随机数"
+// @Param encrypt_type query string false "加密类型（aes）"
+// @Param msg_signature query string false "安全模式签名"
+// @Success 200 {string} string "success"
+// @Failure 400 {object} response.ErrorResponse "消息格式错误"
+// @Failure 401 {object} response.ErrorResponse "签名校验失败"
+// @Failure 404 {object} response.ErrorResponse "微信应用不存在"
+// @Router /idp/wechat-apps/{app_id}/callback [post]
+func (h *WechatCallbackHandler) ReceiveCallbackMessage(c *gin.Context) {
+	var query request.WechatCallbackQuery
+	if err := h.BindQuery(c, &query); err != nil {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxCallbackBodyBytes))
+	if err != nil {
+		h.Error(c, perrors.WithCode(code.ErrWechatCallbackMalformed, "failed to read callback body: %v", err))
+		return
+	}
+
+	err = h.callbackService.HandleMessage(c.Request.Context(), wechatapp.CallbackMessageDTO{
+		AppID:        c.Param("app_id"),
+		Signature:    query.Signature,
+		MsgSignature: query.MsgSignature,
+		Timestamp:    query.Timestamp,
+		Nonce:        query.Nonce,
+		EncryptType:  query.EncryptType,
+		Body:         body,
+	})
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+	// 微信要求回复 success（或空串），否则会重试并向用户提示服务故障
+	c.String(http.StatusOK, "success")
+}
@@ -0,0 +1,341 @@
+// Package handler 企业微信应用管理 REST API 处理器
+package handler
+
+import (
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/application/idp/wecomapp"
+	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/interface/idp/restful/request"
+	"github.com/FangcunMount/iam-contracts/internal/apiserver/interface/idp/restful/response"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+)
+
+// WecomAppHandler 企业微信应用管理 REST 处理器
+type WecomAppHandler struct {
+	*BaseHandler
+	appService        wecomapp.WecomAppApplicationService
+	credentialService wecomapp.WecomAppCredentialApplicationService
+	tokenService      wecomapp.WecomAppTokenApplicationService
+}
+
+// NewWecomAppHandler 创建企业微信应用处理器
+func NewWecomAppHandler(
+	appService wecomapp.WecomAppApplicationService,
+	credentialService wecomapp.WecomAppCredentialApplicationService,
+	tokenService wecomapp.WecomAppTokenApplicationService,
+) *WecomAppHandler {
+	return &WecomAppHandler{
+		BaseHandler:       NewBaseHandler(),
+		appService:        appService,
+		credentialService: credentialService,
+		tokenService:      tokenService,
+	}
+}
+
+// ListWecomApps 查询企业微信应用列表
+// @Summary 查询企业微信应用列表
+// @Tags IDP-Wecom
+// @Accept json
+// @Produce json
+// @Param corp_id query string false "企业 ID"
+// @Param status query string false "应用状态 (Enabled/Disabled/Archived)"
+// @Success 200 {object} response.WecomAppListResponse "查询成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数错误"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/wecom-apps [get]
+func (h *WecomAppHandler) ListWecomApps(c *gin.Context) {
+	var req request.ListWecomAppsRequest
+	if err := h.BindQuery(c, &req); err != nil {
+		return
+	}
+
+	filter := wecomapp.ListWecomAppsFilter{}
+	if req.CorpID != "" {
+		filter.CorpID = &req.CorpID
+	}
+	if req.Status != "" {
+		status, err := parseWecomAppStatus(req.Status)
+		if err != nil {
+			h.Error(c, err)
+			return
+		}
+		filter.Status = &status
+	}
+
+	results, err := h.appService.ListApps(c.Request.Context(), filter)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	items := make([]*response.WecomAppResponse, 0, len(results))
+	for _, result := range results {
+		items = append(items, toWecomAppResponse(result))
+	}
+
+	h.Success(c, &response.WecomAppListResponse{
+		Total: len(items),
+		Items: items,
+	})
+}
+
+// CreateWecomApp 登记企业微信应用
+// @Summary 登记企业微信应用
+// @Tags IDP-Wecom
+// @Accept json
+// @Produce json
+// @Param request body request.CreateWecomAppRequest true "登记企业微信应用请求"
+// @Success 201 {object} response.WecomAppResponse "创建成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数错误"
+// @Failure 409 {object} response.ErrorResponse "应用已存在"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/wecom-apps [post]
+func (h *WecomAppHandler) CreateWecomApp(c *gin.Context) {
+	var req request.CreateWecomAppRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	result, err := h.appService.CreateApp(c.Request.Context(), wecomapp.CreateWecomAppDTO{
+		CorpID:     req.CorpID,
+		AgentID:    req.AgentID,
+		Name:       req.Name,
+		CorpSecret: req.CorpSecret,
+	})
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, toWecomAppResponse(result))
+}
+
+// GetWecomApp 查询企业微信应用
+// @Summary 查询企业微信应用
+// @Tags IDP-Wecom
+// @Accept json
+// @Produce json
+// @Param corp_id path string true "企业 ID"
+// @Param agent_id path string true "应用 AgentID"
+// @Success 200 {object} response.WecomAppResponse "查询成功"
+// @Failure 404 {object} response.ErrorResponse "应用不存在"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/wecom-apps/{corp_id}/{agent_id} [get]
+func (h *WecomAppHandler) GetWecomApp(c *gin.Context) {
+	var req request.GetWecomAppRequest
+	if err := h.BindURI(c, &req); err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	result, err := h.appService.GetApp(c.Request.Context(), req.CorpID, req.AgentID)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, toWecomAppResponse(result))
+}
+
+// UpdateWecomApp 更新企业微信应用基础信息
+// @Summary 更新企业微信应用基础信息
+// @Tags IDP-Wecom
+// @Accept json
+// @Produce json
+// @Param corp_id path string true "企业 ID"
+// @Param agent_id path string true "应用 AgentID"
+// @Param request body request.UpdateWecomAppRequest true "更新企业微信应用请求"
+// @Success 200 {object} response.WecomAppResponse "更新成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数错误"
+// @Failure 404 {object} response.ErrorResponse "应用不存在"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/wecom-apps/{corp_id}/{agent_id} [patch]
+func (h *WecomAppHandler) UpdateWecomApp(c *gin.Context) {
+	var uri request.GetWecomAppRequest
+	if err := h.BindURI(c, &uri); err != nil {
+		return
+	}
+
+	var req request.UpdateWecomAppRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+	if req.Name == nil {
+		h.Error(c, perrors.WithCode(code.ErrInvalidArgument, "at least one field must be updated"))
+		return
+	}
+
+	result, err := h.appService.UpdateApp(c.Request.Context(), uri.CorpID, uri.AgentID, wecomapp.UpdateWecomAppDTO{Name: req.Name})
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, toWecomAppResponse(result))
+}
+
+// EnableWecomApp 启用企业微信应用
+// @Summary 启用企业微信应用
+// @Tags IDP-Wecom
+// @Accept json
+// @Produce json
+// @Param corp_id path string true "企业 ID"
+// @Param agent_id path string true "应用 AgentID"
+// @Success 200 {object} response.WecomAppResponse "启用成功"
+// @Failure 404 {object} response.ErrorResponse "应用不存在"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/wecom-apps/{corp_id}/{agent_id}/enable [post]
+func (h *WecomAppHandler) EnableWecomApp(c *gin.Context) {
+	var req request.GetWecomAppRequest
+	if err := h.BindURI(c, &req); err != nil {
+		return
+	}
+
+	result, err := h.appService.EnableApp(c.Request.Context(), req.CorpID, req.AgentID)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, toWecomAppResponse(result))
+}
+
+// DisableWecomApp 禁用企业微信应用
+// @Summary 禁用企业微信应用
+// @Tags IDP-Wecom
+// @Accept json
+// @Produce json
+// @Param corp_id path string true "企业 ID"
+// @Param agent_id path string true "应用 AgentID"
+// @Success 200 {object} response.WecomAppResponse "禁用成功"
+// @Failure 404 {object} response.ErrorResponse "应用不存在"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/wecom-apps/{corp_id}/{agent_id}/disable [post]
+func (h *WecomAppHandler) DisableWecomApp(c *gin.Context) {
+	var req request.GetWecomAppRequest
+	if err := h.BindURI(c, &req); err != nil {
+		return
+	}
+
+	result, err := h.appService.DisableApp(c.Request.Context(), req.CorpID, req.AgentID)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, toWecomAppResponse(result))
+}
+
+// RotateCorpSecret 轮换应用 Secret
+// @Summary 轮换企业微信应用 Secret（CorpSecret）
+// @Tags IDP-Wecom
+// @Accept json
+// @Produce json
+// @Param request body request.RotateCorpSecretRequest true "轮换应用 Secret 请求"
+// @Success 200 {object} response.RotateSecretResponse "轮换成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数错误"
+// @Failure 404 {object} response.ErrorResponse "应用不存在"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/wecom-apps/rotate-corp-secret [post]
+func (h *WecomAppHandler) RotateCorpSecret(c *gin.Context) {
+	var req request.RotateCorpSecretRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	if err := h.credentialService.RotateCorpSecret(c.Request.Context(), req.CorpID, req.AgentID, req.NewSecret); err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, &response.RotateSecretResponse{
+		Success: true,
+		Message: "Corp secret rotated successfully",
+	})
+}
+
+// GetAccessToken 获取访问令牌
+// @Summary 获取企业微信访问令牌（带缓存和自动刷新）
+// @Tags IDP-Wecom
+// @Accept json
+// @Produce json
+// @Param corp_id path string true "企业 ID"
+// @Param agent_id path string true "应用 AgentID"
+// @Success 200 {object} response.AccessTokenResponse "获取成功"
+// @Failure 404 {object} response.ErrorResponse "应用不存在"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/wecom-apps/{corp_id}/{agent_id}/access-token [get]
+func (h *WecomAppHandler) GetAccessToken(c *gin.Context) {
+	var req request.GetWecomAppRequest
+	if err := h.BindURI(c, &req); err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	token, err := h.tokenService.GetAccessToken(c.Request.Context(), req.CorpID, req.AgentID)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, &response.AccessTokenResponse{
+		AccessToken: token,
+		ExpiresIn:   7200, // 企业微信 access_token 默认 7200 秒
+	})
+}
+
+// RefreshAccessToken 刷新访问令牌
+// @Summary 强制刷新企业微信访问令牌
+// @Tags IDP-Wecom
+// @Accept json
+// @Produce json
+// @Param request body request.RefreshWecomAccessTokenRequest true "刷新访问令牌请求"
+// @Success 200 {object} response.AccessTokenResponse "刷新成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数错误"
+// @Failure 404 {object} response.ErrorResponse "应用不存在"
+// @Failure 500 {object} response.ErrorResponse "服务器内部错误"
+// @Router /idp/wecom-apps/refresh-access-token [post]
+func (h *WecomAppHandler) RefreshAccessToken(c *gin.Context) {
+	var req request.RefreshWecomAccessTokenRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	token, err := h.tokenService.RefreshAccessToken(c.Request.Context(), req.CorpID, req.AgentID)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, &response.AccessTokenResponse{
+		AccessToken: token,
+		ExpiresIn:   7200,
+	})
+}
+
+func toWecomAppResponse(result *wecomapp.WecomAppResult) *response.WecomAppResponse {
+	if result == nil {
+		return nil
+	}
+	return &response.WecomAppResponse{
+		ID:      result.ID,
+		CorpID:  result.CorpID,
+		AgentID: result.AgentID,
+		Name:    result.Name,
+		Status:  string(result.Status),
+	}
+}
+
+func parseWecomAppStatus(raw string) (domain.Status, error) {
+	switch domain.Status(raw) {
+	case domain.StatusEnabled, domain.StatusDisabled, domain.StatusArchived:
+		return domain.Status(raw), nil
+	default:
+		return "", perrors.WithCode(code.ErrWecomAppStatusInvalid, "invalid wecom app status: %s", raw)
+	}
+}
@@ -66,3 +66,120 @@ type DecryptPhoneRequest struct {
 	EncryptedData string `json:"encrypted_data" binding:"required"` // 加密数据
 	IV            string `json:"iv" binding:"required"`             // 加密算法的初始向量
 }
+
+// ============= 企业微信应用管理请求 =============
+
+// ListWecomAppsRequest 企业微信应用列表请求（Query 参数）。
+type ListWecomAppsRequest struct {
+	CorpID string `form:"corp_id"`
+	Status string `form:"status"`
+}
+
+// CreateWecomAppRequest 登记企业微信应用请求
+type CreateWecomAppRequest struct {
+	CorpID     string `json:"corp_id" binding:"required"`      // 企业 ID（必填）
+	AgentID    string `json:"agent_id" binding:"required"`     // 应用 AgentID（必填）
+	Name       string `json:"name" binding:"required"`         // 应用名称（必填）
+	CorpSecret string `json:"corp_secret" binding:"omitempty"` // 应用 Secret（可选，创建时设置）
+}
+
+// GetWecomAppRequest 查询企业微信应用请求（URI 参数）
+type GetWecomAppRequest struct {
+	CorpID  string `uri:"corp_id" binding:"required"`  // 企业 ID
+	AgentID string `uri:"agent_id" binding:"required"` // 应用 AgentID
+}
+
+// UpdateWecomAppRequest 更新企业微信应用基础信息请求。
+type UpdateWecomAppRequest struct {
+	Name *string `json:"name"`
+}
+
+// RotateCorpSecretRequest 轮换企业微信应用 Secret 请求
+type RotateCorpSecretRequest struct {
+	CorpID    string `json:"corp_id" binding:"required"`    // 企业 ID
+	AgentID   string `json:"agent_id" binding:"required"`   // 应用 AgentID
+	NewSecret string `json:"new_secret" binding:"required"` // 新的 Secret
+}
+
+// RefreshWecomAccessTokenRequest 刷新企业微信访问令牌请求
+type RefreshWecomAccessTokenRequest struct {
+	CorpID  string `json:"corp_id" binding:"required"`  // 企业 ID
+	AgentID string `json:"agent_id" binding:"required"` // 应用 AgentID
+}
+
+// ============= 上游 OIDC 身份提供商请求 =============
+
+// OIDCClaimMappingRequest 上游声明名映射，未设置的字段使用 OIDC 标准声明名
+type OIDCClaimMappingRequest struct {
+	Subject  string `json:"subject"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Phone    string `json:"phone"`
+}
+
+// OIDCRoleMappingRequest 角色映射规则：声明 claim 等于或包含 value 时授予 role
+type OIDCRoleMappingRequest struct {
+	Claim string `json:"claim" binding:"required"`
+	Value string `json:"value" binding:"required"`
+	Role  string `json:"role" binding:"required"`
+}
+
+// ListOIDCProvidersRequest 上游 OIDC 身份提供商列表请求（Query 参数）
+type ListOIDCProvidersRequest struct {
+	Status string `form:"status"`
+}
+
+// CreateOIDCProviderRequest 登记上游 OIDC 身份提供商请求
+type CreateOIDCProviderRequest struct {
+	Slug          string                   `json:"slug" binding:"required"`           // 唯一标识（必填）
+	Name          string                   `json:"name" binding:"required"`           // 显示名称（必填）
+	Issuer        string                   `json:"issuer" binding:"required"`         // 上游 Issuer（必填）
+	ClientID      string                   `json:"client_id" binding:"required"`      // client_id（必填）
+	ClientSecret  string                   `json:"client_secret" binding:"omitempty"` // client_secret（可选）
+	Scopes        []string                 `json:"scopes"`                            // 授权 scope
+	ClaimMapping  OIDCClaimMappingRequest  `json:"claim_mapping"`                     // 声明映射
+	RoleMappings  []OIDCRoleMappingRequest `json:"role_mappings" binding:"dive"`      // 角色映射规则
+	AutoProvision bool                     `json:"auto_provision"`                    // 是否即时开通账户
+	TenantID      string                   `json:"tenant_id"`                         // 映射角色所在租户
+}
+
+// GetOIDCProviderRequest 查询上游 OIDC 身份提供商请求（URI 参数）
+type GetOIDCProviderRequest struct {
+	Slug string `uri:"slug" binding:"required"`
+}
+
+// UpdateOIDCProviderRequest 更新上游 OIDC 身份提供商请求，未提供的字段保持不变
+type UpdateOIDCProviderRequest struct {
+	Name          *string                   `json:"name"`
+	Issuer        *string                   `json:"issuer"`
+	ClientID      *string                   `json:"client_id"`
+	Scopes        *[]string                 `json:"scopes"`
+	ClaimMapping  *OIDCClaimMappingRequest  `json:"claim_mapping"`
+	RoleMappings  *[]OIDCRoleMappingRequest `json:"role_mappings"`
+	AutoProvision *bool                     `json:"auto_provision"`
+	TenantID      *string                   `json:"tenant_id"`
+}
+
+// RotateClientSecretRequest 轮换上游 OIDC client_secret 请求
+type RotateClientSecretRequest struct {
+	NewSecret string `json:"new_secret" binding:"required"` // 新的 client_secret
+}
+
+// OIDCAuthorizeURLRequest 构造上游授权地址请求（Query 参数）
+type OIDCAuthorizeURLRequest struct {
+	RedirectURI   string `form:"redirect_uri" binding:"required"` // 回调地址
+	CodeChallenge string `form:"code_challenge"`                  // PKCE S256 challenge
+}
+
+// ============= 微信消息推送回调请求 =============
+
+// WechatCallbackQuery 微信服务器回调的 Query 参数
+type WechatCallbackQuery struct {
+	Signature    string `form:"signature"`     // 明文签名（token/timestamp/nonce）
+	Timestamp    string `form:"timestamp"`     // 时间戳
+	Nonce        string `form:"nonce"`         // 随机数
+	EchoStr      string `form:"echostr"`       // 服务器配置校验回显字符串（GET）
+	EncryptType  string `form:"encrypt_type"`  // aes 表示安全模式（POST）
+	MsgSignature string `form:"msg_signature"` // 安全模式签名（POST）
+}
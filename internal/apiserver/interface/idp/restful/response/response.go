@@ -18,6 +18,65 @@ type WechatAppListResponse struct {
 	Items []*WechatAppResponse `json:"items"`
 }
 
+// WecomAppResponse 企业微信应用响应
+type WecomAppResponse struct {
+	ID      string `json:"id"`       // 内部 ID
+	CorpID  string `json:"corp_id"`  // 企业 ID
+	AgentID string `json:"agent_id"` // 应用 AgentID
+	Name    string `json:"name"`     // 应用名称
+	Status  string `json:"status"`   // 应用状态（Enabled/Disabled/Archived）
+}
+
+// WecomAppListResponse 企业微信应用列表响应。
+type WecomAppListResponse struct {
+	Total int                 `json:"total"`
+	Items []*WecomAppResponse `json:"items"`
+}
+
+// OIDCProviderResponse 上游 OIDC 身份提供商响应（不回显 client_secret）
+type OIDCProviderResponse struct {
+	ID              string                    `json:"id"`                  // 内部 ID
+	Slug            string                    `json:"slug"`                // 唯一标识
+	Name            string                    `json:"name"`                // 显示名称
+	Issuer          string                    `json:"issuer"`              // 上游 Issuer
+	ClientID        string                    `json:"client_id"`           // client_id
+	Scopes          []string                  `json:"scopes"`              // 授权 scope
+	ClaimMapping    OIDCClaimMappingResponse  `json:"claim_mapping"`       // 声明映射
+	RoleMappings    []OIDCRoleMappingResponse `json:"role_mappings"`       // 角色映射规则
+	AutoProvision   bool                      `json:"auto_provision"`      // 是否即时开通账户
+	TenantID        string                    `json:"tenant_id,omitempty"` // 映射角色所在租户
+	Status          string                    `json:"status"`              // 状态（Enabled/Disabled/Archived）
+	HasClientSecret bool                      `json:"has_client_secret"`   // 是否已设置 client_secret
+}
+
+// OIDCClaimMappingResponse 上游声明名映射
+type OIDCClaimMappingResponse struct {
+	Subject  string `json:"subject,omitempty"`
+	Username string `json:"username,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Phone    string `json:"phone,omitempty"`
+}
+
+// OIDCRoleMappingResponse 角色映射规则
+type OIDCRoleMappingResponse struct {
+	Claim string `json:"claim"`
+	Value string `json:"value"`
+	Role  string `json:"role"`
+}
+
+// OIDCProviderListResponse 上游 OIDC 身份提供商列表响应
+type OIDCProviderListResponse struct {
+	Total int                     `json:"total"`
+	Items []*OIDCProviderResponse `json:"items"`
+}
+
+// OIDCAuthorizeURLResponse 上游授权地址响应
+type OIDCAuthorizeURLResponse struct {
+	AuthorizeURL string `json:"authorize_url"` // 前端跳转的上游授权地址
+	State        string `json:"state"`         // 服务端生成的 state，回调后随 code 提交登录
+}
+
 // AccessTokenResponse 访问令牌响应
 type AccessTokenResponse struct {
 	AccessToken string `json:"access_token"` // 访问令牌
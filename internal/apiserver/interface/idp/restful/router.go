@@ -13,7 +13,12 @@ import (
 // Dependencies IDP 模块的依赖
 type Dependencies struct {
 	WechatAppHandler *handler.WechatAppHandler
-	AdminMiddlewares []gin.HandlerFunc
+	WecomAppHandler  *handler.WecomAppHandler
+	// OIDCProviderHandler 上游 OIDC 身份提供商管理与授权地址构造
+	OIDCProviderHandler *handler.OIDCProviderHandler
+	// WechatCallbackHandler 微信消息推送回调（公开路由，依靠签名校验）
+	WechatCallbackHandler *handler.WechatCallbackHandler
+	AdminMiddlewares      []gin.HandlerFunc
 	// WechatAuthHandler 已移除 - 认证功能由 authn 模块统一提供
 }
 
@@ -28,10 +33,14 @@ func Provide(d Dependencies) {
 //
 // IDP 模块职责：
 // - 微信应用管理（创建、查询、凭据轮换、令牌管理）
+// - 企业微信应用管理（CorpID + AgentID 登记、CorpSecret 轮换、令牌管理）
+// - 微信服务器消息推送回调（验签、安全模式解密、事件分发）
+// - 上游 OIDC 身份提供商管理（登记、声明/角色映射、client_secret 轮换、授权地址构造）
 // - 提供基础设施服务供其他模块使用（通过容器依赖注入）
 //
 // 认证功能由 authn 模块统一提供：
 // - POST /api/v1/auth/login (method: "wx:minip") - 微信小程序登录
+// - POST /api/v1/auth/login (method: "oidc") - 上游 OIDC 联合登录
 func Register(engine *gin.Engine) {
 	if engine == nil {
 		return
@@ -52,6 +61,19 @@ func Register(engine *gin.Engine) {
 			return
 		}
 
+		// ============ 微信消息推送回调 ============
+		// 由微信服务器调用，不挂管理员中间件
+		if deps.WechatCallbackHandler != nil {
+			idpGroup.GET("/wechat-apps/:app_id/callback", deps.WechatCallbackHandler.VerifyCallbackURL)
+			idpGroup.POST("/wechat-apps/:app_id/callback", deps.WechatCallbackHandler.ReceiveCallbackMessage)
+		}
+
+		// ============ 上游 OIDC 授权地址 ============
+		// 登录前由前端调用，不挂管理员中间件
+		if deps.OIDCProviderHandler != nil {
+			idpGroup.GET("/oidc-providers/:slug/authorize-url", deps.OIDCProviderHandler.GetAuthorizeURL)
+		}
+
 		// ============ 微信应用管理 ============
 		wechatApps := idpGroup.Group("/wechat-apps")
 		if len(deps.AdminMiddlewares) == 0 {
@@ -89,6 +111,36 @@ func Register(engine *gin.Engine) {
 			wechatApps.POST("/refresh-access-token", deps.WechatAppHandler.RefreshAccessToken)
 		}
 
+		// ============ 企业微信应用管理 ============
+		if deps.WecomAppHandler != nil {
+			wecomApps := idpGroup.Group("/wecom-apps")
+			wecomApps.Use(deps.AdminMiddlewares...)
+
+			wecomApps.GET("", deps.WecomAppHandler.ListWecomApps)
+			wecomApps.POST("", deps.WecomAppHandler.CreateWecomApp)
+			wecomApps.GET("/:corp_id/:agent_id", deps.WecomAppHandler.GetWecomApp)
+			wecomApps.PATCH("/:corp_id/:agent_id", deps.WecomAppHandler.UpdateWecomApp)
+			wecomApps.POST("/:corp_id/:agent_id/enable", deps.WecomAppHandler.EnableWecomApp)
+			wecomApps.POST("/:corp_id/:agent_id/disable", deps.WecomAppHandler.DisableWecomApp)
+			wecomApps.GET("/:corp_id/:agent_id/access-token", deps.WecomAppHandler.GetAccessToken)
+			wecomApps.POST("/rotate-corp-secret", deps.WecomAppHandler.RotateCorpSecret)
+			wecomApps.POST("/refresh-access-token", deps.WecomAppHandler.RefreshAccessToken)
+		}
+
+		// ============ 上游 OIDC 身份提供商管理 ============
+		if deps.OIDCProviderHandler != nil {
+			oidcProviders := idpGroup.Group("/oidc-providers")
+			oidcProviders.Use(deps.AdminMiddlewares...)
+
+			oidcProviders.GET("", deps.OIDCProviderHandler.ListOIDCProviders)
+			oidcProviders.POST("", deps.OIDCProviderHandler.CreateOIDCProvider)
+			oidcProviders.GET("/:slug", deps.OIDCProviderHandler.GetOIDCProvider)
+			oidcProviders.PATCH("/:slug", deps.OIDCProviderHandler.UpdateOIDCProvider)
+			oidcProviders.POST("/:slug/enable", deps.OIDCProviderHandler.EnableOIDCProvider)
+			oidcProviders.POST("/:slug/disable", deps.OIDCProviderHandler.DisableOIDCProvider)
+			oidcProviders.POST("/:slug/rotate-client-secret", deps.OIDCProviderHandler.RotateClientSecret)
+		}
+
 		// ============ 微信认证 ============
 		// 已移除 - 认证功能由 authn 模块统一提供
 		// 使用方式：
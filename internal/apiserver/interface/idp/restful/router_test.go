@@ -9,8 +9,11 @@ import (
 	"testing"
 
 	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	oidcsvc "github.com/FangcunMount/iam-contracts/internal/apiserver/application/idp/oidcprovider"
 	appsvc "github.com/FangcunMount/iam-contracts/internal/apiserver/application/idp/wechatapp"
+	wecomsvc "github.com/FangcunMount/iam-contracts/internal/apiserver/application/idp/wecomapp"
 	domain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wechatapp"
+	wecomdomain "github.com/FangcunMount/iam-contracts/internal/apiserver/domain/idp/wecomapp"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/interface/idp/restful/handler"
 	idpresponse "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/idp/restful/response"
 	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
@@ -296,3 +299,251 @@ func (f *fakeWechatAppServiceWithError) DisableApp(context.Context, string) (*ap
 func newIDPRouterWithError(t *testing.T, middlewares []gin.HandlerFunc, appService appsvc.WechatAppApplicationService) *gin.Engine {
 	return newIDPRouter(t, middlewares, appService)
 }
+
+type fakeWecomAppService struct {
+	lastCorpID  string
+	lastAgentID string
+}
+
+func (f *fakeWecomAppService) result(corpID, agentID string, status wecomdomain.Status) *wecomsvc.WecomAppResult {
+	f.lastCorpID, f.lastAgentID = corpID, agentID
+	return &wecomsvc.WecomAppResult{ID: "3001", CorpID: corpID, AgentID: agentID, Name: "OA", Status: status}
+}
+
+func (f *fakeWecomAppService) CreateApp(_ context.Context, dto wecomsvc.CreateWecomAppDTO) (*wecomsvc.WecomAppResult, error) {
+	return f.result(dto.CorpID, dto.AgentID, wecomdomain.StatusEnabled), nil
+}
+
+func (f *fakeWecomAppService) GetApp(_ context.Context, corpID, agentID string) (*wecomsvc.WecomAppResult, error) {
+	return f.result(corpID, agentID, wecomdomain.StatusEnabled), nil
+}
+
+func (f *fakeWecomAppService) ListApps(context.Context, wecomsvc.ListWecomAppsFilter) ([]*wecomsvc.WecomAppResult, error) {
+	return nil, nil
+}
+
+func (f *fakeWecomAppService) UpdateApp(_ context.Context, corpID, agentID string, _ wecomsvc.UpdateWecomAppDTO) (*wecomsvc.WecomAppResult, error) {
+	return f.result(corpID, agentID, wecomdomain.StatusEnabled), nil
+}
+
+func (f *fakeWecomAppService) EnableApp(_ context.Context, corpID, agentID string) (*wecomsvc.WecomAppResult, error) {
+	return f.result(corpID, agentID, wecomdomain.StatusEnabled), nil
+}
+
+func (f *fakeWecomAppService) DisableApp(_ context.Context, corpID, agentID string) (*wecomsvc.WecomAppResult, error) {
+	return f.result(corpID, agentID, wecomdomain.StatusDisabled), nil
+}
+
+type fakeWecomCredentialService struct {
+	lastSecret string
+}
+
+func (f *fakeWecomCredentialService) RotateCorpSecret(_ context.Context, _, _, newSecret string) error {
+	f.lastSecret = newSecret
+	return nil
+}
+
+type fakeWecomTokenService struct{}
+
+func (fakeWecomTokenService) GetAccessToken(context.Context, string, string) (string, error) {
+	return "wecom-token", nil
+}
+func (fakeWecomTokenService) RefreshAccessToken(context.Context, string, string) (string, error) {
+	return "wecom-token-2", nil
+}
+
+func TestRegister_WecomAppRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	appService := &fakeWecomAppService{}
+	credService := &fakeWecomCredentialService{}
+	Provide(Dependencies{
+		WechatAppHandler: handler.NewWechatAppHandler(&fakeWechatAppService{}, fakeCredentialService{}, fakeTokenService{}),
+		WecomAppHandler:  handler.NewWecomAppHandler(appService, credService, fakeWecomTokenService{}),
+		AdminMiddlewares: []gin.HandlerFunc{requireAdminHeader()},
+	})
+	t.Cleanup(func() { Provide(Dependencies{}) })
+	engine := gin.New()
+	Register(engine)
+
+	unauthorized := httptest.NewRecorder()
+	engine.ServeHTTP(unauthorized, httptest.NewRequest(http.MethodGet, "/api/v1/idp/wecom-apps/ww-corp/1000002", nil))
+	require.Equal(t, http.StatusUnauthorized, unauthorized.Code)
+
+	getRecorder := httptest.NewRecorder()
+	getReq := httptest.NewRequest(http.MethodGet, "/api/v1/idp/wecom-apps/ww-corp/1000002", nil)
+	getReq.Header.Set("X-Admin", "1")
+	engine.ServeHTTP(getRecorder, getReq)
+	require.Equal(t, http.StatusOK, getRecorder.Code)
+	got := decodeAPIResponse[idpresponse.WecomAppResponse](t, getRecorder)
+	require.Equal(t, "ww-corp", got.CorpID)
+	require.Equal(t, "1000002", got.AgentID)
+
+	disableRecorder := httptest.NewRecorder()
+	disableReq := httptest.NewRequest(http.MethodPost, "/api/v1/idp/wecom-apps/ww-corp/1000003/disable", nil)
+	disableReq.Header.Set("X-Admin", "1")
+	engine.ServeHTTP(disableRecorder, disableReq)
+	require.Equal(t, http.StatusOK, disableRecorder.Code)
+	require.Equal(t, "1000003", appService.lastAgentID)
+
+	rotateRecorder := httptest.NewRecorder()
+	rotateReq := httptest.NewRequest(http.MethodPost, "/api/v1/idp/wecom-apps/rotate-corp-secret",
+		bytes.NewBufferString(`{"corp_id":"ww-corp","agent_id":"1000002","new_secret":"corp-secret-value-0002"}`))
+	rotateReq.Header.Set("Content-Type", "application/json")
+	rotateReq.Header.Set("X-Admin", "1")
+	engine.ServeHTTP(rotateRecorder, rotateReq)
+	require.Equal(t, http.StatusOK, rotateRecorder.Code)
+	require.Equal(t, "corp-secret-value-0002", credService.lastSecret)
+
+	refreshRecorder := httptest.NewRecorder()
+	refreshReq := httptest.NewRequest(http.MethodPost, "/api/v1/idp/wecom-apps/refresh-access-token",
+		bytes.NewBufferString(`{"corp_id":"ww-corp","agent_id":"1000002"}`))
+	refreshReq.Header.Set("Content-Type", "application/json")
+	refreshReq.Header.Set("X-Admin", "1")
+	engine.ServeHTTP(refreshRecorder, refreshReq)
+	require.Equal(t, http.StatusOK, refreshRecorder.Code)
+	token := decodeAPIResponse[idpresponse.AccessTokenResponse](t, refreshRecorder)
+	require.Equal(t, "wecom-token-2", token.AccessToken)
+}
+
+type fakeCallbackService struct {
+	lastMessage appsvc.CallbackMessageDTO
+}
+
+func (f *fakeCallbackService) VerifyURL(_ context.Context, dto appsvc.VerifyCallbackURLDTO) (string, error) {
+	if dto.Signature != "good" {
+		return "", perrors.WithCode(code.ErrWechatCallbackSignatureInvalid, "bad signature")
+	}
+	return dto.EchoStr, nil
+}
+
+func (f *fakeCallbackService) HandleMessage(_ context.Context, dto appsvc.CallbackMessageDTO) error {
+	f.lastMessage = dto
+	return nil
+}
+
+func TestRegister_WechatCallbackRoutesArePublic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	callbackService := &fakeCallbackService{}
+	Provide(Dependencies{
+		WechatAppHandler:      handler.NewWechatAppHandler(&fakeWechatAppService{}, fakeCredentialService{}, fakeTokenService{}),
+		WechatCallbackHandler: handler.NewWechatCallbackHandler(callbackService),
+		AdminMiddlewares:      []gin.HandlerFunc{requireAdminHeader()},
+	})
+	t.Cleanup(func() { Provide(Dependencies{}) })
+	engine := gin.New()
+	Register(engine)
+
+	verify := httptest.NewRecorder()
+	engine.ServeHTTP(verify, httptest.NewRequest(http.MethodGet,
+		"/api/v1/idp/wechat-apps/wx-mp/callback?signature=good&timestamp=1&nonce=2&echostr=hello", nil))
+	require.Equal(t, http.StatusOK, verify.Code)
+	require.Equal(t, "hello", verify.Body.String())
+
+	rejected := httptest.NewRecorder()
+	engine.ServeHTTP(rejected, httptest.NewRequest(http.MethodGet,
+		"/api/v1/idp/wechat-apps/wx-mp/callback?signature=bad&timestamp=1&nonce=2&echostr=hello", nil))
+	require.Equal(t, http.StatusUnauthorized, rejected.Code)
+
+	receive := httptest.NewRecorder()
+	engine.ServeHTTP(receive, httptest.NewRequest(http.MethodPost,
+		"/api/v1/idp/wechat-apps/wx-mp/callback?timestamp=1&nonce=2&encrypt_type=aes&msg_signature=sig",
+		bytes.NewBufferString(`<xml><Encrypt>cipher</Encrypt></xml>`)))
+	require.Equal(t, http.StatusOK, receive.Code)
+	require.Equal(t, "success", receive.Body.String())
+	require.Equal(t, "wx-mp", callbackService.lastMessage.AppID)
+	require.Equal(t, "aes", callbackService.lastMessage.EncryptType)
+	require.Equal(t, "sig", callbackService.lastMessage.MsgSignature)
+	require.Equal(t, `<xml><Encrypt>cipher</Encrypt></xml>`, string(callbackService.lastMessage.Body))
+}
+
+type fakeOIDCProviderService struct {
+	lastCreate oidcsvc.CreateOIDCProviderDTO
+}
+
+func (f *fakeOIDCProviderService) CreateProvider(_ context.Context, dto oidcsvc.CreateOIDCProviderDTO) (*oidcsvc.OIDCProviderResult, error) {
+	f.lastCreate = dto
+	return &oidcsvc.OIDCProviderResult{ID: "4001", Slug: dto.Slug, Issuer: dto.Issuer, RoleMappings: dto.RoleMappings, HasClientSecret: true}, nil
+}
+
+func (f *fakeOIDCProviderService) GetProvider(context.Context, string) (*oidcsvc.OIDCProviderResult, error) {
+	return nil, perrors.WithCode(code.ErrOIDCProviderNotFound, "not found")
+}
+
+func (f *fakeOIDCProviderService) ListProviders(context.Context, oidcsvc.ListOIDCProvidersFilter) ([]*oidcsvc.OIDCProviderResult, error) {
+	return nil, nil
+}
+
+func (f *fakeOIDCProviderService) UpdateProvider(context.Context, string, oidcsvc.UpdateOIDCProviderDTO) (*oidcsvc.OIDCProviderResult, error) {
+	return nil, nil
+}
+
+func (f *fakeOIDCProviderService) EnableProvider(context.Context, string) (*oidcsvc.OIDCProviderResult, error) {
+	return nil, nil
+}
+
+func (f *fakeOIDCProviderService) DisableProvider(context.Context, string) (*oidcsvc.OIDCProviderResult, error) {
+	return nil, nil
+}
+
+type fakeOIDCCredentialService struct{}
+
+func (fakeOIDCCredentialService) RotateClientSecret(context.Context, string, string) error {
+	return nil
+}
+
+type fakeOIDCAuthorizeService struct{}
+
+func (fakeOIDCAuthorizeService) BuildAuthorizeURL(_ context.Context, slug string, dto oidcsvc.AuthorizeURLDTO) (*oidcsvc.AuthorizeURLResult, error) {
+	return &oidcsvc.AuthorizeURLResult{
+		AuthorizeURL: "https://sso.example.com/authorize?client_id=iam&state=st-1",
+		State:        "st-1",
+	}, nil
+}
+
+func TestRegister_OIDCProviderRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := &fakeOIDCProviderService{}
+	Provide(Dependencies{
+		WechatAppHandler:    handler.NewWechatAppHandler(&fakeWechatAppService{}, fakeCredentialService{}, fakeTokenService{}),
+		OIDCProviderHandler: handler.NewOIDCProviderHandler(service, fakeOIDCCredentialService{}, fakeOIDCAuthorizeService{}),
+		AdminMiddlewares:    []gin.HandlerFunc{requireAdminHeader()},
+	})
+	t.Cleanup(func() { Provide(Dependencies{}) })
+	engine := gin.New()
+	Register(engine)
+
+	// 授权地址为公开路由
+	authorize := httptest.NewRecorder()
+	engine.ServeHTTP(authorize, httptest.NewRequest(http.MethodGet,
+		"/api/v1/idp/oidc-providers/corp-sso/authorize-url?redirect_uri=https://app.example.com/cb", nil))
+	require.Equal(t, http.StatusOK, authorize.Code)
+	got := decodeAPIResponse[idpresponse.OIDCAuthorizeURLResponse](t, authorize)
+	require.Contains(t, got.AuthorizeURL, "state=st-1")
+	require.Equal(t, "st-1", got.State)
+
+	unauthorized := httptest.NewRecorder()
+	engine.ServeHTTP(unauthorized, httptest.NewRequest(http.MethodGet, "/api/v1/idp/oidc-providers", nil))
+	require.Equal(t, http.StatusUnauthorized, unauthorized.Code)
+
+	createRecorder := httptest.NewRecorder()
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/idp/oidc-providers", bytes.NewBufferString(`{
+		"slug":"corp-sso","name":"Corp SSO","issuer":"https://sso.example.com","client_id":"iam",
+		"client_secret":"s3cret","tenant_id":"7",
+		"role_mappings":[{"claim":"groups","value":"iam-admins","role":"admin"}]}`))
+	createReq.Header.Set("Content-Type", "application/json")
+	createReq.Header.Set("X-Admin", "1")
+	engine.ServeHTTP(createRecorder, createReq)
+	require.Equal(t, http.StatusOK, createRecorder.Code)
+	created := decodeAPIResponse[idpresponse.OIDCProviderResponse](t, createRecorder)
+	require.Equal(t, "corp-sso", created.Slug)
+	require.True(t, created.HasClientSecret)
+	require.Equal(t, uint64(7), service.lastCreate.TenantID.Uint64())
+	require.Len(t, service.lastCreate.RoleMappings, 1)
+	require.NotContains(t, createRecorder.Body.String(), "s3cret")
+
+	missing := httptest.NewRecorder()
+	missingReq := httptest.NewRequest(http.MethodGet, "/api/v1/idp/oidc-providers/unknown", nil)
+	missingReq.Header.Set("X-Admin", "1")
+	engine.ServeHTTP(missing, missingReq)
+	require.Equal(t, http.StatusNotFound, missing.Code)
+}
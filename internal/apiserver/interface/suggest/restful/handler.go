@@ -5,6 +5,7 @@ import (
 
 	appsuggest "github.com/FangcunMount/iam-contracts/internal/apiserver/application/suggest"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/domain/suggest"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/middleware/authn"
 	"github.com/FangcunMount/iam-contracts/pkg/core"
 )
 
@@ -50,13 +51,15 @@ func NewHandler(svc *appsuggest.Service) *Handler {
 
 // Child 处理儿童联想查询
 // @Summary 儿童联想搜索
-// @Description 支持中文/拼音前缀联想，数字关键词走手机号/ID 精确匹配
+// @Description 在调用方租户内联想：支持中文名、全拼、简拼前缀；数字关键词匹配手机号/ID 或手机尾号（至少 4 位）。
+// @Description 拥有 iam:children#search 权限可检索整个租户，否则仅返回调用方监护的儿童。
 // @Tags Suggest
 // @Accept  json
 // @Produce  json
-// @Param k query string true "关键词；数字=精确匹配手机号/ID，其他=前缀联想"
-// @Success 200 {array} suggest.Term "联想结果（按权重降序，去重）"
+// @Param k query string true "关键词；数字=手机号/ID/手机尾号，其他=中文/拼音前缀联想"
+// @Success 200 {array} suggest.Term "联想结果（按相关度降序，去重）"
 // @Failure 400 {object} core.ErrResponse "参数缺失"
+// @Failure 401 {object} core.ErrResponse "未认证"
 // @Router /suggest/child [get]
 // @Security BearerAuth
 func (h *Handler) Child(c *gin.Context) {
@@ -67,7 +70,20 @@ func (h *Handler) Child(c *gin.Context) {
 		return
 	}
 
-	list := h.svc.Suggest(c, query.K)
+	userID, ok := h.GetUserID(c)
+	if !ok {
+		h.UnauthorizedResponse(c, "Not authenticated")
+		return
+	}
+
+	list, err := h.svc.Suggest(c.Request.Context(), appsuggest.Caller{
+		UserID:   userID,
+		TenantID: authn.TenantIDFromGin(c),
+	}, query.K)
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
 	if list == nil {
 		list = []suggest.Term{}
 	}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	appmerge "github.com/FangcunMount/iam-contracts/internal/apiserver/application/uc/merge"
+	requestdto "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/uc/restful/request"
+	responsedto "github.com/FangcunMount/iam-contracts/internal/apiserver/interface/uc/restful/response"
+)
+
+// UserMergeHandler 管理员用户合并处理器
+type UserMergeHandler struct {
+	*BaseHandler
+	mergeApp appmerge.UserMergeApplicationService
+}
+
+// NewUserMergeHandler 创建用户合并处理器
+func NewUserMergeHandler(mergeApp appmerge.UserMergeApplicationService) *UserMergeHandler {
+	return &UserMergeHandler{
+		BaseHandler: NewBaseHandler(),
+		mergeApp:    mergeApp,
+	}
+}
+
+// MergeUser 将路径中的源用户合并到目标用户
+// @Summary 合并重复用户
+// @Description 将源用户的账号（含凭据）、监护关系与授权赋权迁移到目标用户，停用源用户并撤销其会话；dryRun 仅返回迁移计划
+// @Tags Admin-Users
+// @Accept json
+// @Produce json
+// @Param userId path string true "源用户 ID"
+// @Param request body requestdto.UserMergeRequest true "合并请求"
+// @Success 200 {object} responsedto.UserMergeResponse "合并成功或迁移计划"
+// @Failure 400 {object} core.ErrResponse "参数错误"
+// @Failure 404 {object} core.ErrResponse "用户不存在"
+// @Failure 409 {object} core.ErrResponse "存在需人工处理的冲突"
+// @Failure 500 {object} core.ErrResponse "服务器内部错误"
+// @Router /admin/users/{userId}/merge [post]
+// @Security BearerAuth
+func (h *UserMergeHandler) MergeUser(c *gin.Context) {
+	var req requestdto.UserMergeRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	operator, _ := h.GetUserID(c)
+	report, err := h.mergeApp.Merge(c.Request.Context(), appmerge.MergeCommand{
+		SourceUserID: c.Param("userId"),
+		TargetUserID: req.TargetUserID,
+		DryRun:       req.DryRun,
+		Reason:       req.Reason,
+		Operator:     operator,
+	})
+	if err != nil {
+		h.Error(c, err)
+		return
+	}
+
+	h.Success(c, mergeReportToResponse(report))
+}
+
+func mergeReportToResponse(report *appmerge.MergeReport) responsedto.UserMergeResponse {
+	resp := responsedto.UserMergeResponse{
+		SourceUserID:  report.SourceUserID,
+		TargetUserID:  report.TargetUserID,
+		DryRun:        report.DryRun,
+		Accounts:      make([]responsedto.MergedAccount, 0, len(report.Accounts)),
+		Guardianships: make([]responsedto.MergedGuardianship, 0, len(report.Guardianships)),
+		Assignments:   make([]responsedto.MergedAssignment, 0, len(report.Assignments)),
+	}
+	for _, a := range report.Accounts {
+		resp.Accounts = append(resp.Accounts, responsedto.MergedAccount(a))
+	}
+	for _, g := range report.Guardianships {
+		resp.Guardianships = append(resp.Guardianships, responsedto.MergedGuardianship(g))
+	}
+	for _, a := range report.Assignments {
+		resp.Assignments = append(resp.Assignments, responsedto.MergedAssignment(a))
+	}
+	return resp
+}
@@ -0,0 +1,8 @@
+package request
+
+// UserMergeRequest 用户合并请求（源用户由路径参数指定）
+type UserMergeRequest struct {
+	TargetUserID string `json:"targetUserId" binding:"required"`
+	DryRun       bool   `json:"dryRun"`
+	Reason       string `json:"reason"`
+}
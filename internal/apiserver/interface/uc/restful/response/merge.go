@@ -0,0 +1,36 @@
+package response
+
+// UserMergeResponse 用户合并结果（dryRun 时为迁移计划）
+type UserMergeResponse struct {
+	SourceUserID  string               `json:"sourceUserId"`
+	TargetUserID  string               `json:"targetUserId"`
+	DryRun        bool                 `json:"dryRun"`
+	Accounts      []MergedAccount      `json:"accounts"`
+	Guardianships []MergedGuardianship `json:"guardianships"`
+	Assignments   []MergedAssignment   `json:"assignments"`
+}
+
+// MergedAccount 迁移的账号（凭据随账号迁移）
+type MergedAccount struct {
+	AccountID   string `json:"accountId"`
+	Type        string `json:"type"`
+	ExternalID  string `json:"externalId"`
+	Credentials int    `json:"credentials"`
+}
+
+// MergedGuardianship 监护关系迁移项
+type MergedGuardianship struct {
+	GuardianshipID string `json:"guardianshipId"`
+	ChildID        string `json:"childId"`
+	Relation       string `json:"relation"`
+	Active         bool   `json:"active"`
+	Action         string `json:"action"`
+}
+
+// MergedAssignment 授权赋权迁移项
+type MergedAssignment struct {
+	AssignmentID string `json:"assignmentId"`
+	TenantID     string `json:"tenantId"`
+	RoleID       uint64 `json:"roleId"`
+	Action       string `json:"action"`
+}
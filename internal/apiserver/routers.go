@@ -27,17 +27,21 @@ type Router struct {
 	container              *container.Container
 	engine                 *gin.Engine // 保存 engine 引用用于调试
 	cacheGovernanceHandler *cachegovernancehandler.GovernanceHandler
+	cacheCommandHandler    *cachegovernancehandler.CommandHandler
 }
 
 // NewRouter 创建路由管理器
 func NewRouter(c *container.Container) *Router {
 	var governanceHandler = cachegovernancehandler.NewGovernanceHandler(nil)
+	var commandHandler = cachegovernancehandler.NewCommandHandler(nil)
 	if c != nil {
 		governanceHandler = cachegovernancehandler.NewGovernanceHandler(c.CacheGovernanceService)
+		commandHandler = cachegovernancehandler.NewCommandHandler(c.CacheGovernanceCommand)
 	}
 	return &Router{
 		container:              c,
 		cacheGovernanceHandler: governanceHandler,
+		cacheCommandHandler:    commandHandler,
 	}
 }
 
@@ -81,10 +85,19 @@ func (r *Router) RegisterRoutes(engine *gin.Engine) {
 
 	// Authn 模块（公开端点）
 	if r.container.AuthnModule != nil {
+		var selfServiceAuth gin.HandlerFunc
+		if authMiddleware != nil {
+			selfServiceAuth = authMiddleware.AuthRequired()
+		}
 		authnhttp.Provide(authnhttp.Dependencies{
 			AuthHandler:      r.container.AuthnModule.AuthHandler,
 			AccountHandler:   r.container.AuthnModule.AccountHandler,
 			JWKSHandler:      r.container.AuthnModule.JWKSHandler,
+			PasswordHandler:  r.container.AuthnModule.PasswordHandler,
+			BindingHandler:   r.container.AuthnModule.BindingHandler,
+			SessionHandler:   r.container.AuthnModule.SessionHandler,
+			SAMLHandler:      r.container.AuthnModule.SAMLHandler,
+			AuthMiddleware:   selfServiceAuth,
 			AdminMiddlewares: adminMiddlewares,
 		})
 		authnhttp.Register(engine)
@@ -110,6 +123,8 @@ func (r *Router) RegisterRoutes(engine *gin.Engine) {
 			PolicyHandler:     r.container.AuthzModule.PolicyHandler,
 			ResourceHandler:   r.container.AuthzModule.ResourceHandler,
 			CheckHandler:      r.container.AuthzModule.CheckHandler,
+			ConfigHandler:     r.container.AuthzModule.ConfigHandler,
+			HistoryHandler:    r.container.AuthzModule.HistoryHandler,
 			AuthMiddleware:    authMiddleware.AuthRequired(),
 		})
 		authzhttp.Register(engine)
@@ -123,8 +138,11 @@ func (r *Router) RegisterRoutes(engine *gin.Engine) {
 	// IDP 模块（身份提供者）
 	if r.container.IDPModule != nil {
 		idphttp.Provide(idphttp.Dependencies{
-			WechatAppHandler: r.container.IDPModule.WechatAppHandler,
-			AdminMiddlewares: adminMiddlewares,
+			WechatAppHandler:      r.container.IDPModule.WechatAppHandler,
+			WecomAppHandler:       r.container.IDPModule.WecomAppHandler,
+			OIDCProviderHandler:   r.container.IDPModule.OIDCProviderHandler,
+			WechatCallbackHandler: r.container.IDPModule.WechatCallbackHandler,
+			AdminMiddlewares:      adminMiddlewares,
 			// WechatAuthHandler 已移除 - 认证由 authn 模块统一提供
 		})
 		idphttp.Register(engine)
@@ -196,24 +214,34 @@ func (r *Router) registerCacheGovernanceDebugRoutes(engine *gin.Engine, authMidd
 		return
 	}
 
+	adminAvailable := authMiddleware != nil && authMiddleware.SupportsRoleCheck()
+
 	if !r.cacheGovernanceDebugRequireAdmin() {
 		engine.GET("/debug/cache-governance/catalog", r.debugCacheCatalog)
 		engine.GET("/debug/cache-governance/overview", r.debugCacheOverview)
 		engine.GET("/debug/cache-governance/families/:family", r.debugCacheFamily)
+	} else if !adminAvailable {
+		log.Warn("Skip cache governance debug routes: admin protection enabled but authz middleware is unavailable")
 		return
 	}
 
-	if authMiddleware == nil || !authMiddleware.SupportsRoleCheck() {
-		log.Warn("Skip cache governance debug routes: admin protection enabled but authz middleware is unavailable")
+	// 写操作（清理、预热、抽样）无论 require_admin 配置如何都必须经过管理员鉴权
+	if !adminAvailable {
+		log.Warn("Skip cache governance command routes: authz middleware is unavailable")
 		return
 	}
 
 	debug := engine.Group("/debug/cache-governance")
 	debug.Use(authMiddleware.AuthRequired(), authMiddleware.RequirePlatformAdmin())
 	{
-		debug.GET("/catalog", r.debugCacheCatalog)
-		debug.GET("/overview", r.debugCacheOverview)
-		debug.GET("/families/:family", r.debugCacheFamily)
+		if r.cacheGovernanceDebugRequireAdmin() {
+			debug.GET("/catalog", r.debugCacheCatalog)
+			debug.GET("/overview", r.debugCacheOverview)
+			debug.GET("/families/:family", r.debugCacheFamily)
+		}
+		debug.GET("/families/:family/keys", r.cacheCommandHandler.SampleKeys)
+		debug.POST("/families/:family/purge", r.cacheCommandHandler.Purge)
+		debug.POST("/families/:family/warm", r.cacheCommandHandler.Warm)
 	}
 }
 
@@ -255,6 +283,20 @@ func (r *Router) registerAdminRoutes(engine *gin.Engine, authMiddleware *authnMi
 			admin.POST("/sessions/:sessionId/revoke", r.container.AuthnModule.SessionAdminHandler.RevokeSession)
 			admin.POST("/accounts/:accountId/sessions/revoke", r.container.AuthnModule.SessionAdminHandler.RevokeAccountSessions)
 			admin.POST("/users/:userId/sessions/revoke", r.container.AuthnModule.SessionAdminHandler.RevokeUserSessions)
+			admin.GET("/accounts/:accountId/sessions", r.container.AuthnModule.SessionAdminHandler.ListAccountSessions)
+			admin.GET("/users/:userId/sessions", r.container.AuthnModule.SessionAdminHandler.ListUserSessions)
+		}
+		if r.container != nil && r.container.UserModule != nil && r.container.UserModule.UserMergeHandler != nil {
+			admin.POST("/users/:userId/merge", r.container.UserModule.UserMergeHandler.MergeUser)
+		}
+		if r.container != nil && r.container.AuthzModule != nil && r.container.AuthzModule.ServiceACLHandler != nil {
+			aclHandler := r.container.AuthzModule.ServiceACLHandler
+			admin.GET("/grpc-acl/services", aclHandler.ListServices)
+			admin.POST("/grpc-acl/services", aclHandler.CreateService)
+			admin.GET("/grpc-acl/services/:service", aclHandler.GetService)
+			admin.PUT("/grpc-acl/services/:service", aclHandler.UpdateService)
+			admin.DELETE("/grpc-acl/services/:service", aclHandler.DeleteService)
+			admin.GET("/grpc-acl/dry-run", aclHandler.DryRun)
 		}
 	}
 }
@@ -372,6 +414,16 @@ func (r *Router) debugModules(c *gin.Context) {
 			"user":  r.container.UserModule != nil,
 			"idp":   r.container.IDPModule != nil,
 		}
+		if r.container.OutboxRelay != nil {
+			response["outbox"] = r.container.OutboxRelay.Status(c.Request.Context())
+		} else {
+			response["outbox"] = gin.H{"enabled": false}
+		}
+		if r.container.AuthnModule != nil && r.container.AuthnModule.RotationScheduler != nil {
+			response["jwks_rotation"] = r.container.AuthnModule.RotationScheduler.Leadership()
+		} else {
+			response["jwks_rotation"] = gin.H{"enabled": false}
+		}
 		response["container_status"] = "initialized"
 	} else {
 		response["container_status"] = "not_initialized"
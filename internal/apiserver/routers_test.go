@@ -95,6 +95,31 @@ func TestRouterForcesAdminProtectionForCacheGovernanceDebugRoutesInProduction(t
 	assertDebugRouteStatus(t, engine, http.MethodGet, "/debug/cache-governance/catalog", http.StatusNotFound, false)
 }
 
+func TestCacheGovernanceCommandRoutesAlwaysRequireAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("app.mode", "development")
+
+	engine := gin.New()
+	router := NewRouter(&container.Container{
+		CacheGovernanceService: cachegovernance.NewReadService(nil),
+		CacheGovernanceCommand: cachegovernance.NewCommandService(nil),
+	})
+	router.registerCacheGovernanceDebugRoutes(engine, authnMiddleware.NewJWTAuthMiddleware(nil, casbinStub{}))
+
+	// 读接口在开发环境保持开放，写接口仍需认证
+	assertDebugRouteStatus(t, engine, http.MethodGet, "/debug/cache-governance/catalog", http.StatusOK, true)
+	assertRouteRegistered(t, engine, http.MethodPost, "/debug/cache-governance/families/:family/purge")
+	assertRouteRegistered(t, engine, http.MethodPost, "/debug/cache-governance/families/:family/warm")
+	assertRouteRegistered(t, engine, http.MethodGet, "/debug/cache-governance/families/:family/keys")
+	assertDebugRouteStatus(t, engine, http.MethodPost, "/debug/cache-governance/families/authn.session/purge", http.StatusUnauthorized, false)
+
+	unprotected := gin.New()
+	router.registerCacheGovernanceDebugRoutes(unprotected, nil)
+	assertDebugRouteStatus(t, unprotected, http.MethodPost, "/debug/cache-governance/families/authn.session/purge", http.StatusNotFound, false)
+}
+
 func TestRouterRegistersSeedMockRouteWhenEnabled(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	viper.Reset()
@@ -148,6 +173,8 @@ func TestRegisterAdminRoutesRegistersSessionControlRoutes(t *testing.T) {
 	assertRouteRegistered(t, engine, http.MethodPost, "/api/v1/admin/sessions/:sessionId/revoke")
 	assertRouteRegistered(t, engine, http.MethodPost, "/api/v1/admin/accounts/:accountId/sessions/revoke")
 	assertRouteRegistered(t, engine, http.MethodPost, "/api/v1/admin/users/:userId/sessions/revoke")
+	assertRouteRegistered(t, engine, http.MethodGet, "/api/v1/admin/users/:userId/sessions")
+	assertRouteRegistered(t, engine, http.MethodGet, "/api/v1/admin/accounts/:accountId/sessions")
 }
 
 func TestRegisterAdminRoutesFailsClosedWithoutAdminProtection(t *testing.T) {
@@ -165,6 +192,7 @@ func TestRegisterAdminRoutesFailsClosedWithoutAdminProtection(t *testing.T) {
 	assertRouteNotRegistered(t, engine, http.MethodPost, "/api/v1/admin/sessions/:sessionId/revoke")
 	assertRouteNotRegistered(t, engine, http.MethodPost, "/api/v1/admin/accounts/:accountId/sessions/revoke")
 	assertRouteNotRegistered(t, engine, http.MethodPost, "/api/v1/admin/users/:userId/sessions/revoke")
+	assertRouteNotRegistered(t, engine, http.MethodGet, "/api/v1/admin/users/:userId/sessions")
 }
 
 func TestRouterRegistersIdentityGuardiansRoutes(t *testing.T) {
@@ -269,6 +297,14 @@ func (sessionServiceStub) RevokeAllSessionsByUser(_ context.Context, _ string, _
 	return nil
 }
 
+func (sessionServiceStub) ListSessionsByUser(_ context.Context, _ string) ([]*sessionapp.SessionResult, error) {
+	return nil, nil
+}
+
+func (sessionServiceStub) ListSessionsByAccount(_ context.Context, _ string) ([]*sessionapp.SessionResult, error) {
+	return nil, nil
+}
+
 var _ sessionapp.SessionApplicationService = sessionServiceStub{}
 
 type casbinStub struct{}
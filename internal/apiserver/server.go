@@ -13,9 +13,12 @@ import (
 	_ "github.com/FangcunMount/component-base/pkg/messaging/nsq" // 注册 NSQ Provider
 	"github.com/FangcunMount/component-base/pkg/shutdown"
 	"github.com/FangcunMount/component-base/pkg/shutdown/shutdownmanagers/posixsignal"
+	serviceACLApp "github.com/FangcunMount/iam-contracts/internal/apiserver/application/authz/serviceacl"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/config"
 	"github.com/FangcunMount/iam-contracts/internal/apiserver/container"
 	"github.com/FangcunMount/iam-contracts/internal/pkg/grpc"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/middleware"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/ratelimit"
 	genericapiserver "github.com/FangcunMount/iam-contracts/internal/pkg/server"
 	"github.com/spf13/viper"
 )
@@ -33,6 +36,10 @@ type apiServer struct {
 	dbManager *DatabaseManager
 	// Container 主容器
 	container *container.Container
+	// 分布式限流执行器（未启用时为 nil）
+	rateLimiter *ratelimit.Enforcer
+	// gRPC 服务 ACL 同步器（未启用 ACL 时为 nil）
+	aclSyncer *serviceACLApp.Syncer
 }
 
 // preparedAPIServer 定义了准备运行的 API 服务器
@@ -53,8 +60,14 @@ func createAPIServer(cfg *config.Config) (*apiServer, error) {
 		return nil, err
 	}
 
+	// 限流执行器先于 Redis 创建，Redis 就绪后再绑定后端
+	var rateLimiter *ratelimit.Enforcer
+	if rlCfg := ratelimit.LoadConfig(); rlCfg.Enabled {
+		rateLimiter = ratelimit.NewEnforcer(rlCfg)
+	}
+
 	// 创建 GRPC 服务器
-	grpcServer, err := buildGRPCServer(cfg)
+	grpcServer, err := buildGRPCServer(cfg, rateLimiter)
 	if err != nil {
 		log.Fatalf("Failed to build GRPC server: %v", err)
 		return nil, err
@@ -70,6 +83,7 @@ func createAPIServer(cfg *config.Config) (*apiServer, error) {
 		genericAPIServer: genericServer,
 		dbManager:        dbManager,
 		grpcServer:       grpcServer,
+		rateLimiter:      rateLimiter,
 	}
 
 	return server, nil
@@ -146,12 +160,25 @@ func (s *apiServer) PrepareRun() (preparedAPIServer, error) {
 		return preparedAPIServer{}, err
 	}
 
+	// 限流需在业务路由注册前挂载；无缓存 Redis 时执行器保持未绑定（放行）
+	if s.rateLimiter != nil {
+		if cacheClient != nil {
+			s.rateLimiter.Bind(ratelimit.NewRedisLimiter(cacheClient))
+		} else {
+			log.Warn("rate limiting enabled but cache redis unavailable; requests will not be limited")
+		}
+		s.genericAPIServer.Engine.Use(middleware.RateLimit(s.rateLimiter))
+	}
+
 	// 创建并初始化路由器
 	NewRouter(s.container).RegisterRoutes(s.genericAPIServer.Engine)
 
 	// 注册 gRPC 服务
 	s.registerGRPCServices()
 
+	// gRPC 服务 ACL 改由数据库管理：首次启动时导入启动配置，之后定期同步到拦截器
+	s.startServiceACLSync()
+
 	// 如果认证模块提供了密钥轮换调度器，启动它并在优雅关闭时停止
 	if s.container != nil && s.container.AuthnModule != nil && s.container.AuthnModule.RotationScheduler != nil {
 		go func() {
@@ -162,6 +189,20 @@ func (s *apiServer) PrepareRun() (preparedAPIServer, error) {
 		log.Infow("Key rotation scheduler initialized", "description", "periodic key rotation scheduler started")
 	}
 
+	// 启动赋权有效期调度器，在限时赋权的生效/失效边界增删 g 规则
+	if s.container != nil && s.container.AuthzModule != nil && s.container.AuthzModule.AssignmentScheduler != nil {
+		if err := s.container.AuthzModule.AssignmentScheduler.Start(context.Background()); err != nil {
+			log.Errorf("failed to start authz assignment validity scheduler: %v", err)
+		}
+	}
+
+	// 启动发件箱中继，投递随业务事务落库的领域事件
+	if s.container != nil && s.container.OutboxRelay != nil {
+		if err := s.container.OutboxRelay.Start(context.Background()); err != nil {
+			log.Errorf("failed to start outbox relay: %v", err)
+		}
+	}
+
 	log.Infow("hexagonal architecture initialized", "mode", mode, "degraded_startup_allowed", degradedAllowed)
 
 	// 添加关闭回调
@@ -173,6 +214,27 @@ func (s *apiServer) PrepareRun() (preparedAPIServer, error) {
 			}
 		}
 
+		// 停止 gRPC 服务 ACL 同步器（需在关闭数据库之前）
+		if s.aclSyncer != nil && s.aclSyncer.IsRunning() {
+			if err := s.aclSyncer.Stop(); err != nil {
+				log.Errorf("Failed to stop grpc service acl syncer: %v", err)
+			}
+		}
+
+		// 停止赋权有效期调度器（需在关闭数据库之前）
+		if s.container != nil && s.container.AuthzModule != nil && s.container.AuthzModule.AssignmentScheduler != nil && s.container.AuthzModule.AssignmentScheduler.IsRunning() {
+			if err := s.container.AuthzModule.AssignmentScheduler.Stop(); err != nil {
+				log.Errorf("Failed to stop authz assignment validity scheduler: %v", err)
+			}
+		}
+
+		// 停止发件箱中继（需在关闭数据库与消息总线之前）
+		if s.container != nil && s.container.OutboxRelay != nil && s.container.OutboxRelay.IsRunning() {
+			if err := s.container.OutboxRelay.Stop(); err != nil {
+				log.Errorf("Failed to stop outbox relay: %v", err)
+			}
+		}
+
 		// 停止 suggest 更新任务
 		if s.container != nil && s.container.SuggestModule != nil {
 			if err := s.container.SuggestModule.Cleanup(); err != nil {
@@ -205,6 +267,41 @@ func (s *apiServer) PrepareRun() (preparedAPIServer, error) {
 	return preparedAPIServer{s}, nil
 }
 
+// startServiceACLSync 将数据库中的服务 ACL 绑定到 gRPC 拦截器并启动跨副本同步
+// 数据库不可用时保留配置文件中的规则
+func (s *apiServer) startServiceACLSync() {
+	if s.grpcServer == nil || s.grpcServer.ACL() == nil {
+		return
+	}
+	if s.container == nil || s.container.AuthzModule == nil || s.container.AuthzModule.ServiceACLService == nil {
+		log.Warn("gRPC ACL enabled but authz module unavailable; using ACL config file only")
+		return
+	}
+
+	acl := s.grpcServer.ACL()
+	svc := s.container.AuthzModule.ServiceACLService
+	svc.BindRuntime(acl)
+
+	ctx := context.Background()
+	if seeded, err := svc.SeedOnce(ctx, acl.Policy().Services); err != nil {
+		log.Warnw("failed to seed grpc service acl from config file", "error", err)
+	} else if seeded > 0 {
+		log.Infow("grpc service acl seeded from config file", "services", seeded)
+	}
+	if _, err := svc.Sync(ctx); err != nil {
+		log.Warnw("failed to load grpc service acl from database; keep config file rules", "error", err)
+	}
+
+	var interval time.Duration
+	if s.cfg.GRPCOptions != nil && s.cfg.GRPCOptions.ACL != nil {
+		interval = s.cfg.GRPCOptions.ACL.ReloadInterval
+	}
+	s.aclSyncer = serviceACLApp.NewSyncer(svc, interval)
+	if err := s.aclSyncer.Start(ctx); err != nil {
+		log.Errorf("failed to start grpc service acl syncer: %v", err)
+	}
+}
+
 // registerGRPCServices 注册所有 gRPC 服务到 gRPC 服务器
 func (s *apiServer) registerGRPCServices() {
 	if s.grpcServer == nil {
@@ -418,7 +515,7 @@ func buildGenericConfig(cfg *config.Config) (genericConfig *genericapiserver.Con
 }
 
 // buildGRPCServer 构建 GRPC 服务器
-func buildGRPCServer(cfg *config.Config) (*grpc.Server, error) {
+func buildGRPCServer(cfg *config.Config, rateLimiter *ratelimit.Enforcer) (*grpc.Server, error) {
 	// 创建 GRPC 配置
 	grpcConfig := grpc.NewConfig()
 
@@ -426,6 +523,9 @@ func buildGRPCServer(cfg *config.Config) (*grpc.Server, error) {
 	if err := applyGRPCOptions(cfg, grpcConfig); err != nil {
 		return nil, err
 	}
+	if rateLimiter != nil {
+		grpcConfig.UnaryInterceptors = append(grpcConfig.UnaryInterceptors, middleware.UnaryServerRateLimitInterceptor(rateLimiter))
+	}
 
 	// 完成配置并创建服务器
 	return grpcConfig.Complete().New()
@@ -35,6 +35,9 @@ func (s *AssignmentRepoStub) FindByID(ctx context.Context, id assignment.Assignm
 func (s *AssignmentRepoStub) ListBySubject(ctx context.Context, subjectType assignment.SubjectType, subjectID, tenantID string) ([]*assignment.Assignment, error) {
 	return s.Assignments, s.Err
 }
+func (s *AssignmentRepoStub) ListBySubjectAcrossTenants(ctx context.Context, subjectType assignment.SubjectType, subjectID string) ([]*assignment.Assignment, error) {
+	return s.Assignments, s.Err
+}
 func (s *AssignmentRepoStub) ListByRole(ctx context.Context, roleID uint64, tenantID string) ([]*assignment.Assignment, error) {
 	return nil, s.Err
 }
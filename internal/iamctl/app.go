@@ -0,0 +1,27 @@
+// Package iamctl 实现 IAM 管理命令行工具 iamctl
+package iamctl
+
+import (
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/cmd"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/options"
+	"github.com/FangcunMount/iam-contracts/pkg/app"
+)
+
+// commandDesc 命令描述
+const commandDesc = `iamctl controls the iam contracts API server.
+
+It manages users, accounts, roles, assignments, policies, JWKS signing keys,
+WeChat apps and sessions through the REST and gRPC APIs. Connection settings
+are read from contexts in ~/.iam/iamctl.yaml (see "iamctl config").`
+
+// NewApp 创建 iamctl App
+func NewApp(basename string) *app.App {
+	return app.NewApp("IAM administrative command-line tool",
+		basename,
+		app.WithDescription(commandDesc),
+		app.WithNoConfig(),
+		app.WithNoVersion(),
+		app.WithSilence(),
+		app.WithCommands(cmd.NewCommands(options.NewGlobalOptions())...),
+	)
+}
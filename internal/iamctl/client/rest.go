@@ -0,0 +1,206 @@
+// Package client iamctl 访问 IAM 的客户端
+//
+// 管理类接口（角色、策略、JWKS、微信应用、会话等）只在 REST 上提供，由 RESTClient 调用；
+// 用户与运营账号等已在 gRPC 上提供的能力通过 pkg/sdk 调用。
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// APIError REST 接口返回的业务错误
+type APIError struct {
+	StatusCode int
+	Code       int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("%s (HTTP %d, code %d)", e.Message, e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("%s (HTTP %d)", e.Message, e.StatusCode)
+}
+
+// RESTConfig REST 客户端配置
+type RESTConfig struct {
+	Server             string
+	Token              string
+	CAFile             string
+	TLSServerName      string
+	InsecureSkipVerify bool
+	Timeout            time.Duration
+}
+
+// RESTClient IAM REST API 客户端
+type RESTClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewRESTClient 创建 REST 客户端
+func NewRESTClient(cfg RESTConfig) (*RESTClient, error) {
+	if cfg.Server == "" {
+		return nil, fmt.Errorf("REST server is not configured: set --server or the context's server")
+	}
+	base, err := url.Parse(cfg.Server)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid REST server %q", cfg.Server)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if base.Scheme == "https" {
+		tlsConfig := &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			ServerName:         cfg.TLSServerName,
+			InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // 由操作者显式开启
+		}
+		if cfg.CAFile != "" {
+			pem, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in CA file %s", cfg.CAFile)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &RESTClient{
+		baseURL: strings.TrimSuffix(cfg.Server, "/"),
+		token:   cfg.Token,
+		http:    &http.Client{Transport: transport, Timeout: cfg.Timeout},
+	}, nil
+}
+
+// Get 发送 GET 请求
+func (c *RESTClient) Get(ctx context.Context, path string, query url.Values) (interface{}, error) {
+	return c.Do(ctx, http.MethodGet, path, query, nil)
+}
+
+// Post 发送 POST 请求
+func (c *RESTClient) Post(ctx context.Context, path string, body interface{}) (interface{}, error) {
+	return c.Do(ctx, http.MethodPost, path, nil, body)
+}
+
+// Put 发送 PUT 请求
+func (c *RESTClient) Put(ctx context.Context, path string, body interface{}) (interface{}, error) {
+	return c.Do(ctx, http.MethodPut, path, nil, body)
+}
+
+// Patch 发送 PATCH 请求
+func (c *RESTClient) Patch(ctx context.Context, path string, body interface{}) (interface{}, error) {
+	return c.Do(ctx, http.MethodPatch, path, nil, body)
+}
+
+// Delete 发送 DELETE 请求
+func (c *RESTClient) Delete(ctx context.Context, path string, query url.Values, body interface{}) (interface{}, error) {
+	return c.Do(ctx, http.MethodDelete, path, query, body)
+}
+
+// Do 发送请求并返回解码后的响应数据
+// 响应为 {code, message, data} 信封时返回 data，否则返回整个响应体；无响应体时返回 nil
+func (c *RESTClient) Do(ctx context.Context, method, path string, query url.Values, body interface{}) (interface{}, error) {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, decodeError(resp.StatusCode, data)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	return decodeData(data)
+}
+
+// envelope 统一响应信封
+type envelope struct {
+	Code    *int            `json:"code"`
+	Message *string         `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func decodeData(data []byte) (interface{}, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err == nil && env.Code != nil && env.Message != nil {
+		if len(env.Data) == 0 {
+			return nil, nil
+		}
+		data = env.Data
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return v, nil
+}
+
+func decodeError(status int, data []byte) error {
+	apiErr := &APIError{StatusCode: status, Message: http.StatusText(status)}
+
+	var env struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &env); err == nil {
+		apiErr.Code = env.Code
+		if env.Message != "" {
+			apiErr.Message = env.Message
+		}
+		if env.Error != "" {
+			apiErr.Message += ": " + env.Error
+		}
+	}
+	return apiErr
+}
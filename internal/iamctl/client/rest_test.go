@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRESTClientDo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer admin-token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/v1/authz/roles":
+			assert.Equal(t, "20", r.URL.Query().Get("limit"))
+			_, _ = w.Write([]byte(`{"code":200,"message":"success","data":[{"id":"1","name":"admin"}],"total":1}`))
+		case "/api/v1/authn/admin/jwks/keys":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "RS256", body["algorithm"])
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"kid":"k1","status":"active"}`))
+		case "/api/v1/authn/admin/jwks/keys/k1/retire":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":103500,"message":"gRPC service ACL entry not found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewRESTClient(RESTConfig{Server: srv.URL + "/", Token: "admin-token"})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// 信封响应返回 data
+	roles, err := c.Get(ctx, "/api/v1/authz/roles", url.Values{"limit": {"20"}})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": "1", "name": "admin"}}, roles)
+
+	// 非信封响应返回整个对象
+	key, err := c.Post(ctx, "/api/v1/authn/admin/jwks/keys", map[string]string{"algorithm": "RS256"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"kid": "k1", "status": "active"}, key)
+
+	empty, err := c.Post(ctx, "/api/v1/authn/admin/jwks/keys/k1/retire", nil)
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+
+	_, err = c.Get(ctx, "/api/v1/admin/grpc-acl/services/unknown", nil)
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, 103500, apiErr.Code)
+	assert.Equal(t, "gRPC service ACL entry not found (HTTP 404, code 103500)", apiErr.Error())
+}
+
+func TestNewRESTClientRequiresServer(t *testing.T) {
+	_, err := NewRESTClient(RESTConfig{})
+	assert.Error(t, err)
+	_, err = NewRESTClient(RESTConfig{Server: "localhost:8080"})
+	assert.Error(t, err)
+}
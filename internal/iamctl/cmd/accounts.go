@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	authnv1 "github.com/FangcunMount/iam-contracts/api/grpc/iam/authn/v1"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/options"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/printer"
+	"github.com/FangcunMount/iam-contracts/pkg/app"
+)
+
+const accountsPath = "/api/v1/authn/accounts/"
+
+// newAccountsCommand 账号管理
+func newAccountsCommand(global *options.GlobalOptions) *app.Command {
+	return newGroup("accounts", "Get accounts, create operator accounts, enable, disable and unlock accounts.",
+		newCommand(global, "get ACCOUNT_ID", "Show an account.", cobra.ExactArgs(1), nil,
+			func(ctx context.Context, f *Factory, args []string) error {
+				rest, err := f.REST()
+				if err != nil {
+					return err
+				}
+				account, err := rest.Get(ctx, accountsPath+url.PathEscape(args[0]), nil)
+				if err != nil {
+					return err
+				}
+				return f.Print(account, printer.Table{Columns: []printer.Column{
+					{Header: "id", Field: "id"},
+					{Header: "user", Field: "userId"},
+					{Header: "provider", Field: "provider"},
+					{Header: "external-id", Field: "externalId"},
+					{Header: "status", Field: "status"},
+				}})
+			}),
+		newCreateOperatorCommand(global),
+		newAccountActionCommand(global, "enable", "Enable an account.", "enabled"),
+		newAccountActionCommand(global, "disable", "Disable an account.", "disabled"),
+		newAccountActionCommand(global, "unlock", "Clear password lockout of an account.", "unlocked"),
+	)
+}
+
+// newCreateOperatorCommand 创建运营后台账号（经 gRPC SDK）
+func newCreateOperatorCommand(global *options.GlobalOptions) *app.Command {
+	req := &authnv1.RegisterOperationAccountRequest{}
+	flags := func(fs *pflag.FlagSet) {
+		fs.StringVar(&req.OperaLoginId, "login-id", "", "Operator login ID (username).")
+		fs.StringVar(&req.Password, "password", "", "Initial password.")
+		fs.StringVar(&req.Name, "name", "", "Display name of a new user.")
+		fs.StringVar(&req.Phone, "phone", "", "Phone of a new user.")
+		fs.StringVar(&req.Email, "email", "", "Email of a new user.")
+		fs.StringVar(&req.ExistingUserId, "user-id", "", "Attach the account to an existing user instead of creating one.")
+		fs.StringVar(&req.ScopedTenantId, "tenant-id", "", "Tenant the operator account is scoped to.")
+	}
+
+	return newCommand(global, "create-operator", "Create an operator account.", cobra.NoArgs, flags,
+		func(ctx context.Context, f *Factory, _ []string) error {
+			client, err := f.SDK(ctx)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			resp, err := client.Auth().RegisterOperationAccount(ctx, req)
+			if err != nil {
+				return err
+			}
+			return f.PrintProto(resp, printer.Table{Columns: []printer.Column{
+				{Header: "user", Field: "user_id"},
+				{Header: "account", Field: "account_id"},
+				{Header: "new-user", Field: "is_new_user"},
+				{Header: "new-account", Field: "is_new_account"},
+			}})
+		})
+}
+
+func newAccountActionCommand(global *options.GlobalOptions, action, desc, done string) *app.Command {
+	return newCommand(global, action+" ACCOUNT_ID", desc, cobra.ExactArgs(1), nil,
+		func(ctx context.Context, f *Factory, args []string) error {
+			rest, err := f.REST()
+			if err != nil {
+				return err
+			}
+			result, err := rest.Post(ctx, accountsPath+url.PathEscape(args[0])+"/"+action, nil)
+			if err != nil {
+				return err
+			}
+			return f.Done(result, "Account %s %s.", args[0], done)
+		})
+}
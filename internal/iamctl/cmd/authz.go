@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/options"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/printer"
+	"github.com/FangcunMount/iam-contracts/pkg/app"
+)
+
+const (
+	rolesPath       = "/api/v1/authz/roles"
+	assignmentsPath = "/api/v1/authz/assignments"
+	policiesPath    = "/api/v1/authz/policies"
+)
+
+var (
+	roleTable = printer.Table{Columns: []printer.Column{
+		{Header: "id", Field: "id"},
+		{Header: "name", Field: "name"},
+		{Header: "display-name", Field: "display_name"},
+		{Header: "tenant", Field: "tenant_id"},
+		{Header: "description", Field: "description"},
+	}}
+	assignmentTable = printer.Table{Columns: []printer.Column{
+		{Header: "id", Field: "id"},
+		{Header: "subject-type", Field: "subject_type"},
+		{Header: "subject", Field: "subject_id"},
+		{Header: "role", Field: "role_id"},
+		{Header: "tenant", Field: "tenant_id"},
+		{Header: "granted-by", Field: "granted_by"},
+	}}
+	policyTable = printer.Table{Columns: []printer.Column{
+		{Header: "subject", Field: "subject"},
+		{Header: "domain", Field: "domain"},
+		{Header: "object", Field: "object"},
+		{Header: "action", Field: "action"},
+	}}
+)
+
+// newRolesCommand 角色管理
+func newRolesCommand(global *options.GlobalOptions) *app.Command {
+	var offset, limit int
+	listFlags := func(fs *pflag.FlagSet) {
+		fs.IntVar(&offset, "offset", 0, "Page offset.")
+		fs.IntVar(&limit, "limit", 20, "Page size.")
+	}
+
+	return newGroup("roles", "Manage roles and inspect their assignments and policies.",
+		newCommand(global, "list", "List roles.", cobra.NoArgs, listFlags,
+			func(ctx context.Context, f *Factory, _ []string) error {
+				return getAndPrint(ctx, f, rolesPath, url.Values{
+					"offset": {strconv.Itoa(offset)},
+					"limit":  {strconv.Itoa(limit)},
+				}, roleTable)
+			}),
+		newCommand(global, "get ROLE_ID", "Show a role.", cobra.ExactArgs(1), nil,
+			func(ctx context.Context, f *Factory, args []string) error {
+				return getAndPrint(ctx, f, rolesPath+"/"+url.PathEscape(args[0]), nil, roleTable)
+			}),
+		newCreateRoleCommand(global),
+		newUpdateRoleCommand(global),
+		newCommand(global, "delete ROLE_ID", "Delete a role.", cobra.ExactArgs(1), nil,
+			func(ctx context.Context, f *Factory, args []string) error {
+				rest, err := f.REST()
+				if err != nil {
+					return err
+				}
+				result, err := rest.Delete(ctx, rolesPath+"/"+url.PathEscape(args[0]), nil, nil)
+				if err != nil {
+					return err
+				}
+				return f.Done(result, "Role %s deleted.", args[0])
+			}),
+		newCommand(global, "assignments ROLE_ID", "List subjects assigned to a role.", cobra.ExactArgs(1), nil,
+			func(ctx context.Context, f *Factory, args []string) error {
+				return getAndPrint(ctx, f, rolesPath+"/"+url.PathEscape(args[0])+"/assignments", nil, assignmentTable)
+			}),
+		newCommand(global, "policies ROLE_ID", "List policy rules of a role.", cobra.ExactArgs(1), nil,
+			func(ctx context.Context, f *Factory, args []string) error {
+				return getAndPrint(ctx, f, rolesPath+"/"+url.PathEscape(args[0])+"/policies", nil, policyTable)
+			}),
+	)
+}
+
+func newCreateRoleCommand(global *options.GlobalOptions) *app.Command {
+	var name, displayName, description string
+	flags := func(fs *pflag.FlagSet) {
+		fs.StringVar(&name, "name", "", "Role name, unique within the tenant.")
+		fs.StringVar(&displayName, "display-name", "", "Display name.")
+		fs.StringVar(&description, "description", "", "Description.")
+	}
+
+	return newCommand(global, "create", "Create a role.", cobra.NoArgs, flags,
+		func(ctx context.Context, f *Factory, _ []string) error {
+			rest, err := f.REST()
+			if err != nil {
+				return err
+			}
+			role, err := rest.Post(ctx, rolesPath, map[string]string{
+				"name":         name,
+				"display_name": displayName,
+				"description":  description,
+			})
+			if err != nil {
+				return err
+			}
+			return f.Print(role, roleTable)
+		})
+}
+
+func newUpdateRoleCommand(global *options.GlobalOptions) *app.Command {
+	var displayName, description string
+	flags := func(fs *pflag.FlagSet) {
+		fs.StringVar(&displayName, "display-name", "", "Display name.")
+		fs.StringVar(&description, "description", "", "Description.")
+	}
+
+	return newCommand(global, "update ROLE_ID", "Update a role.", cobra.ExactArgs(1), flags,
+		func(ctx context.Context, f *Factory, args []string) error {
+			rest, err := f.REST()
+			if err != nil {
+				return err
+			}
+			role, err := rest.Put(ctx, rolesPath+"/"+url.PathEscape(args[0]), map[string]string{
+				"display_name": displayName,
+				"description":  description,
+			})
+			if err != nil {
+				return err
+			}
+			return f.Print(role, roleTable)
+		})
+}
+
+// newAssignmentsCommand 角色赋权
+func newAssignmentsCommand(global *options.GlobalOptions) *app.Command {
+	var subjectID string
+	listFlags := func(fs *pflag.FlagSet) {
+		fs.StringVar(&subjectID, "subject-id", "", "User ID of the subject.")
+	}
+
+	return newGroup("assignments", "List, grant and revoke role assignments.",
+		newCommand(global, "list", "List roles assigned to a user.", cobra.NoArgs, listFlags,
+			func(ctx context.Context, f *Factory, _ []string) error {
+				return getAndPrint(ctx, f, assignmentsPath+"/subject", url.Values{
+					"subject_type": {"user"},
+					"subject_id":   {subjectID},
+				}, assignmentTable)
+			}),
+		newAssignmentChangeCommand(global, "grant", "Grant a role to a user."),
+		newAssignmentChangeCommand(global, "revoke", "Revoke a role from a user."),
+		newCommand(global, "delete ASSIGNMENT_ID", "Revoke an assignment by its ID.", cobra.ExactArgs(1), nil,
+			func(ctx context.Context, f *Factory, args []string) error {
+				rest, err := f.REST()
+				if err != nil {
+					return err
+				}
+				result, err := rest.Delete(ctx, assignmentsPath+"/"+url.PathEscape(args[0]), nil, nil)
+				if err != nil {
+					return err
+				}
+				return f.Done(result, "Assignment %s revoked.", args[0])
+			}),
+	)
+}
+
+func newAssignmentChangeCommand(global *options.GlobalOptions, action, desc string) *app.Command {
+	var subjectID, roleID string
+	flags := func(fs *pflag.FlagSet) {
+		fs.StringVar(&subjectID, "subject-id", "", "User ID of the subject.")
+		fs.StringVar(&roleID, "role-id", "", "Role ID.")
+	}
+
+	return newCommand(global, action, desc, cobra.NoArgs, flags,
+		func(ctx context.Context, f *Factory, _ []string) error {
+			rest, err := f.REST()
+			if err != nil {
+				return err
+			}
+			result, err := rest.Post(ctx, assignmentsPath+"/"+action, map[string]string{
+				"subject_type": "user",
+				"subject_id":   subjectID,
+				"role_id":      roleID,
+			})
+			if err != nil {
+				return err
+			}
+			if action == "grant" {
+				return f.Print(result, assignmentTable)
+			}
+			return f.Done(result, "Role %s revoked from user %s.", roleID, subjectID)
+		})
+}
+
+// newPoliciesCommand 策略规则
+func newPoliciesCommand(global *options.GlobalOptions) *app.Command {
+	return newGroup("policies", "Add and remove role policy rules and show the policy version.",
+		newPolicyChangeCommand(global, "add", "Allow a role to perform an action on a resource."),
+		newPolicyChangeCommand(global, "remove", "Remove a policy rule from a role."),
+		newCommand(global, "version", "Show the current policy version of the tenant.", cobra.NoArgs, nil,
+			func(ctx context.Context, f *Factory, _ []string) error {
+				return getAndPrint(ctx, f, policiesPath+"/version", nil, printer.Table{Columns: []printer.Column{
+					{Header: "tenant", Field: "tenant_id"},
+					{Header: "version", Field: "version"},
+					{Header: "changed-by", Field: "changed_by"},
+					{Header: "reason", Field: "reason"},
+				}})
+			}),
+	)
+}
+
+func newPolicyChangeCommand(global *options.GlobalOptions, action, desc string) *app.Command {
+	var roleID, resourceID, act, reason string
+	flags := func(fs *pflag.FlagSet) {
+		fs.StringVar(&roleID, "role-id", "", "Role ID.")
+		fs.StringVar(&resourceID, "resource-id", "", "Resource ID.")
+		fs.StringVar(&act, "action", "", "Action on the resource.")
+		fs.StringVar(&reason, "reason", "", "Reason recorded with the policy version.")
+	}
+
+	return newCommand(global, action, desc, cobra.NoArgs, flags,
+		func(ctx context.Context, f *Factory, _ []string) error {
+			rest, err := f.REST()
+			if err != nil {
+				return err
+			}
+			body := map[string]string{
+				"role_id":     roleID,
+				"resource_id": resourceID,
+				"action":      act,
+				"reason":      reason,
+			}
+			var result interface{}
+			if action == "add" {
+				result, err = rest.Post(ctx, policiesPath, body)
+			} else {
+				result, err = rest.Delete(ctx, policiesPath, nil, body)
+			}
+			if err != nil {
+				return err
+			}
+			return f.Done(result, "Policy %s: role %s %s on resource %s.", action, roleID, act, resourceID)
+		})
+}
+
+// getAndPrint 发送 GET 请求并输出
+func getAndPrint(ctx context.Context, f *Factory, path string, query url.Values, table printer.Table) error {
+	rest, err := f.REST()
+	if err != nil {
+		return err
+	}
+	result, err := rest.Get(ctx, path, query)
+	if err != nil {
+		return err
+	}
+	return f.Print(result, table)
+}
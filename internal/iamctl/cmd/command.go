@@ -0,0 +1,81 @@
+// Package cmd iamctl 子命令
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/options"
+	"github.com/FangcunMount/iam-contracts/pkg/app"
+	cliflag "github.com/FangcunMount/iam-contracts/pkg/flag"
+)
+
+// runner 子命令执行体
+type runner func(ctx context.Context, f *Factory, args []string) error
+
+// commandOptions 子命令的命令行选项：自身标志加上全局标志
+type commandOptions struct {
+	global *options.GlobalOptions
+	name   string
+	flags  func(fs *pflag.FlagSet)
+}
+
+// Flags 返回子命令标志分组
+func (o *commandOptions) Flags() (fss cliflag.NamedFlagSets) {
+	if o.flags != nil {
+		o.flags(fss.FlagSet(o.name))
+	}
+	o.global.AddFlags(fss.FlagSet("global"))
+	return fss
+}
+
+// Validate 校验选项
+func (o *commandOptions) Validate() []error {
+	return o.global.Validate()
+}
+
+// newCommand 创建可执行的子命令
+func newCommand(global *options.GlobalOptions, usage, desc string, args cobra.PositionalArgs,
+	flags func(fs *pflag.FlagSet), run runner,
+) *app.Command {
+	opts := &commandOptions{global: global, name: strings.Fields(usage)[0], flags: flags}
+	return app.NewCommand(usage, desc,
+		app.WithCommandOptions(opts),
+		app.WithCommandValidArgs(args),
+		app.WithCommandRunE(func(cmd *cobra.Command, args []string) error {
+			if errs := opts.Validate(); len(errs) != 0 {
+				return errors.NewAggregate(errs)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), global.Timeout)
+			defer cancel()
+			return run(ctx, NewFactory(global, cmd.OutOrStdout()), args)
+		}),
+	)
+}
+
+// newGroup 创建只包含子命令的命令组
+func newGroup(usage, desc string, commands ...*app.Command) *app.Command {
+	group := app.NewCommand(usage, desc)
+	group.AddCommands(commands...)
+	return group
+}
+
+// NewCommands 创建 iamctl 的全部子命令
+func NewCommands(global *options.GlobalOptions) []*app.Command {
+	return []*app.Command{
+		newConfigCommand(global),
+		newUsersCommand(global),
+		newAccountsCommand(global),
+		newRolesCommand(global),
+		newAssignmentsCommand(global),
+		newPoliciesCommand(global),
+		newJWKSCommand(global),
+		newWechatAppsCommand(global),
+		newSessionsCommand(global),
+		newVersionCommand(global),
+	}
+}
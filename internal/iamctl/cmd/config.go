@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/config"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/options"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/printer"
+	"github.com/FangcunMount/iam-contracts/pkg/app"
+)
+
+// newConfigCommand 管理多环境上下文
+func newConfigCommand(global *options.GlobalOptions) *app.Command {
+	return newGroup("config", "Manage iamctl contexts for multiple environments.",
+		newCommand(global, "view", "Show the iamctl config file (tokens are redacted).", cobra.NoArgs, nil,
+			func(_ context.Context, f *Factory, _ []string) error {
+				cfg, err := config.Load(global.ConfigFile)
+				if err != nil {
+					return err
+				}
+				for i := range cfg.Contexts {
+					if cfg.Contexts[i].Context.Token != "" {
+						cfg.Contexts[i].Context.Token = "REDACTED"
+					}
+				}
+				return printer.Print(f.Out(), printer.FormatYAML, cfg, printer.Table{})
+			}),
+		newCommand(global, "get-contexts", "List contexts.", cobra.NoArgs, nil,
+			func(_ context.Context, f *Factory, _ []string) error {
+				cfg, err := config.Load(global.ConfigFile)
+				if err != nil {
+					return err
+				}
+				rows := make([]interface{}, 0, len(cfg.Contexts))
+				for _, c := range cfg.Contexts {
+					current := ""
+					if c.Name == cfg.CurrentContext {
+						current = "*"
+					}
+					rows = append(rows, map[string]interface{}{
+						"current":     current,
+						"name":        c.Name,
+						"server":      c.Context.Server,
+						"grpc_server": c.Context.GRPCServer,
+					})
+				}
+				return f.Print(rows, printer.Table{Columns: []printer.Column{
+					{Header: "current", Field: "current"},
+					{Header: "name", Field: "name"},
+					{Header: "server", Field: "server"},
+					{Header: "grpc-server", Field: "grpc_server"},
+				}})
+			}),
+		newCommand(global, "current-context", "Print the current context.", cobra.NoArgs, nil,
+			func(_ context.Context, f *Factory, _ []string) error {
+				cfg, err := config.Load(global.ConfigFile)
+				if err != nil {
+					return err
+				}
+				if cfg.CurrentContext == "" {
+					return fmt.Errorf("current-context is not set")
+				}
+				_, err = fmt.Fprintln(f.Out(), cfg.CurrentContext)
+				return err
+			}),
+		newCommand(global, "use-context NAME", "Set the current context.", cobra.ExactArgs(1), nil,
+			func(_ context.Context, f *Factory, args []string) error {
+				cfg, err := config.Load(global.ConfigFile)
+				if err != nil {
+					return err
+				}
+				if err := cfg.Use(args[0]); err != nil {
+					return err
+				}
+				if err := cfg.Save(global.ConfigFile); err != nil {
+					return err
+				}
+				return f.Done(nil, "Switched to context %q.", args[0])
+			}),
+		newSetContextCommand(global),
+		newCommand(global, "delete-context NAME", "Delete a context.", cobra.ExactArgs(1), nil,
+			func(_ context.Context, f *Factory, args []string) error {
+				cfg, err := config.Load(global.ConfigFile)
+				if err != nil {
+					return err
+				}
+				if !cfg.Delete(args[0]) {
+					return fmt.Errorf("context %q not found", args[0])
+				}
+				if err := cfg.Save(global.ConfigFile); err != nil {
+					return err
+				}
+				return f.Done(nil, "Deleted context %q.", args[0])
+			}),
+	)
+}
+
+// newSetContextCommand 新增或更新上下文；只修改显式给出的字段
+func newSetContextCommand(global *options.GlobalOptions) *app.Command {
+	var (
+		c       config.Context
+		changed *pflag.FlagSet
+	)
+	flags := func(fs *pflag.FlagSet) {
+		changed = fs
+		fs.StringVar(&c.CAFile, "ca-file", "", "CA certificate used to verify the IAM servers.")
+		fs.StringVar(&c.CertFile, "cert-file", "", "Client certificate for gRPC mTLS.")
+		fs.StringVar(&c.KeyFile, "key-file", "", "Client private key for gRPC mTLS.")
+		fs.StringVar(&c.TLSServerName, "tls-server-name", "", "Server name used to verify the server certificate.")
+		fs.BoolVar(&c.InsecureSkipVerify, "insecure-skip-tls-verify", false, "Skip server certificate verification.")
+	}
+
+	return newCommand(global, "set-context NAME", "Create or update a context; --server, --grpc-server and --token are stored in it.",
+		cobra.ExactArgs(1), flags,
+		func(_ context.Context, f *Factory, args []string) error {
+			cfg, err := config.Load(global.ConfigFile)
+			if err != nil {
+				return err
+			}
+
+			ctx, _ := cfg.Get(args[0])
+			next := config.Context{}
+			if ctx != nil {
+				next = *ctx
+			}
+			if global.Server != "" {
+				next.Server = global.Server
+			}
+			if global.GRPCServer != "" {
+				next.GRPCServer = global.GRPCServer
+			}
+			if global.Token != "" {
+				next.Token = global.Token
+			}
+			for name, apply := range map[string]func(){
+				"ca-file":                  func() { next.CAFile = c.CAFile },
+				"cert-file":                func() { next.CertFile = c.CertFile },
+				"key-file":                 func() { next.KeyFile = c.KeyFile },
+				"tls-server-name":          func() { next.TLSServerName = c.TLSServerName },
+				"insecure-skip-tls-verify": func() { next.InsecureSkipVerify = c.InsecureSkipVerify },
+			} {
+				if changed != nil && changed.Changed(name) {
+					apply()
+				}
+			}
+
+			cfg.Set(args[0], next)
+			if err := cfg.Save(global.ConfigFile); err != nil {
+				return err
+			}
+			return f.Done(nil, "Context %q saved to %s.", args[0], global.ConfigFile)
+		})
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/client"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/config"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/options"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/printer"
+	sdk "github.com/FangcunMount/iam-contracts/pkg/sdk"
+)
+
+// Factory 按当前上下文创建客户端并输出结果
+type Factory struct {
+	opts *options.GlobalOptions
+	out  io.Writer
+}
+
+// NewFactory 创建 Factory；out 为空时输出到标准输出
+func NewFactory(opts *options.GlobalOptions, out io.Writer) *Factory {
+	if out == nil {
+		out = os.Stdout
+	}
+	return &Factory{opts: opts, out: out}
+}
+
+// Out 返回命令输出流
+func (f *Factory) Out() io.Writer {
+	return f.out
+}
+
+// REST 创建 REST 客户端
+func (f *Factory) REST() (*client.RESTClient, error) {
+	ctx, err := f.opts.ResolveContext()
+	if err != nil {
+		return nil, err
+	}
+	return client.NewRESTClient(client.RESTConfig{
+		Server:             ctx.Server,
+		Token:              ctx.Token,
+		CAFile:             ctx.CAFile,
+		TLSServerName:      ctx.TLSServerName,
+		InsecureSkipVerify: ctx.InsecureSkipVerify,
+		Timeout:            f.opts.Timeout,
+	})
+}
+
+// SDK 创建 gRPC SDK 客户端；调用方负责 Close
+func (f *Factory) SDK(ctx context.Context) (*sdk.Client, error) {
+	iamCtx, err := f.opts.ResolveContext()
+	if err != nil {
+		return nil, err
+	}
+	if iamCtx.GRPCServer == "" {
+		return nil, fmt.Errorf("gRPC server is not configured: set --grpc-server or the context's grpc-server")
+	}
+	return sdk.NewClient(ctx, sdkConfig(iamCtx, f.opts))
+}
+
+func sdkConfig(iamCtx config.Context, opts *options.GlobalOptions) *sdk.Config {
+	cfg := &sdk.Config{
+		Endpoint: iamCtx.GRPCServer,
+		Timeout:  opts.Timeout,
+		TLS: &sdk.TLSConfig{
+			Enabled:            iamCtx.CAFile != "" || iamCtx.CertFile != "" || iamCtx.InsecureSkipVerify,
+			CACert:             iamCtx.CAFile,
+			ClientCert:         iamCtx.CertFile,
+			ClientKey:          iamCtx.KeyFile,
+			ServerName:         iamCtx.TLSServerName,
+			InsecureSkipVerify: iamCtx.InsecureSkipVerify,
+		},
+	}
+	if iamCtx.Token != "" {
+		cfg.Metadata = map[string]string{"authorization": "Bearer " + iamCtx.Token}
+	}
+	return cfg
+}
+
+// Print 按 --output 输出结果
+func (f *Factory) Print(obj interface{}, table printer.Table) error {
+	return printer.Print(f.out, f.opts.Output, obj, table)
+}
+
+// PrintProto 将 gRPC 响应转为通用 JSON 结构后输出，字段名与 proto 定义一致
+func (f *Factory) PrintProto(msg proto.Message, table printer.Table) error {
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	return f.Print(obj, table)
+}
+
+// Done 输出操作结果：table 格式打印提示信息，json/yaml 输出接口返回内容
+func (f *Factory) Done(obj interface{}, format string, args ...interface{}) error {
+	if f.opts.Output == printer.FormatTable {
+		_, err := fmt.Fprintf(f.out, format+"\n", args...)
+		return err
+	}
+	if obj == nil {
+		obj = map[string]interface{}{}
+	}
+	return f.Print(obj, printer.Table{})
+}
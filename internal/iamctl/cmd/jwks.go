@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/options"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/printer"
+	"github.com/FangcunMount/iam-contracts/pkg/app"
+)
+
+const jwksKeysPath = "/api/v1/authn/admin/jwks/keys"
+
+var keyTable = printer.Table{Columns: []printer.Column{
+	{Header: "kid", Field: "kid"},
+	{Header: "status", Field: "status"},
+	{Header: "algorithm", Field: "algorithm"},
+	{Header: "not-before", Field: "notBefore"},
+	{Header: "not-after", Field: "notAfter"},
+	{Header: "created", Field: "createdAt"},
+}}
+
+// newJWKSCommand JWKS 签名密钥管理
+func newJWKSCommand(global *options.GlobalOptions) *app.Command {
+	return newGroup("jwks", "Manage JWT signing keys: create, rotate, retire and clean up.",
+		newListKeysCommand(global),
+		newCommand(global, "get KID", "Show a signing key.", cobra.ExactArgs(1), nil,
+			func(ctx context.Context, f *Factory, args []string) error {
+				return getAndPrint(ctx, f, jwksKeysPath+"/"+url.PathEscape(args[0]), nil, keyTable)
+			}),
+		newCreateKeyCommand(global),
+		newKeyActionCommand(global, "retire", "Retire a key that is in its grace period.", "retired"),
+		newKeyActionCommand(global, "force-retire", "Retire a key immediately regardless of its status.", "force retired"),
+		newKeyActionCommand(global, "grace", "Move an active key into its grace period.", "entered grace period"),
+		newCommand(global, "cleanup", "Delete expired keys.", cobra.NoArgs, nil,
+			func(ctx context.Context, f *Factory, _ []string) error {
+				rest, err := f.REST()
+				if err != nil {
+					return err
+				}
+				result, err := rest.Post(ctx, jwksKeysPath+"/cleanup", nil)
+				if err != nil {
+					return err
+				}
+				return f.Done(result, "Deleted %s expired key(s).", printer.FormatValue(printer.Lookup(result, "deletedCount")))
+			}),
+		newCommand(global, "publishable", "List keys currently published in the JWKS.", cobra.NoArgs, nil,
+			func(ctx context.Context, f *Factory, _ []string) error {
+				table := keyTable
+				table.ItemsField = "keys"
+				return getAndPrint(ctx, f, jwksKeysPath+"/publishable", nil, table)
+			}),
+	)
+}
+
+func newListKeysCommand(global *options.GlobalOptions) *app.Command {
+	var (
+		status        string
+		limit, offset int
+	)
+	flags := func(fs *pflag.FlagSet) {
+		fs.StringVar(&status, "status", "", "Filter by status: active|grace|retired.")
+		fs.IntVar(&limit, "limit", 20, "Page size.")
+		fs.IntVar(&offset, "offset", 0, "Page offset.")
+	}
+
+	return newCommand(global, "list", "List signing keys.", cobra.NoArgs, flags,
+		func(ctx context.Context, f *Factory, _ []string) error {
+			query := url.Values{"limit": {strconv.Itoa(limit)}, "offset": {strconv.Itoa(offset)}}
+			if status != "" {
+				query.Set("status", status)
+			}
+			table := keyTable
+			table.ItemsField = "keys"
+			return getAndPrint(ctx, f, jwksKeysPath, query, table)
+		})
+}
+
+func newCreateKeyCommand(global *options.GlobalOptions) *app.Command {
+	var (
+		algorithm string
+		validFor  time.Duration
+	)
+	flags := func(fs *pflag.FlagSet) {
+		fs.StringVar(&algorithm, "algorithm", "RS256", "Signing algorithm: RS256|RS384|RS512.")
+		fs.DurationVar(&validFor, "valid-for", 0, "Key lifetime from now, e.g. 2160h; unlimited when zero.")
+	}
+
+	return newCommand(global, "create", "Create a signing key.", cobra.NoArgs, flags,
+		func(ctx context.Context, f *Factory, _ []string) error {
+			rest, err := f.REST()
+			if err != nil {
+				return err
+			}
+			body := map[string]interface{}{"algorithm": algorithm}
+			if validFor > 0 {
+				body["notAfter"] = time.Now().Add(validFor).UTC().Format(time.RFC3339)
+			}
+			key, err := rest.Post(ctx, jwksKeysPath, body)
+			if err != nil {
+				return err
+			}
+			return f.Print(key, keyTable)
+		})
+}
+
+func newKeyActionCommand(global *options.GlobalOptions, action, desc, done string) *app.Command {
+	return newCommand(global, action+" KID", desc, cobra.ExactArgs(1), nil,
+		func(ctx context.Context, f *Factory, args []string) error {
+			rest, err := f.REST()
+			if err != nil {
+				return err
+			}
+			result, err := rest.Post(ctx, jwksKeysPath+"/"+url.PathEscape(args[0])+"/"+action, nil)
+			if err != nil {
+				return err
+			}
+			return f.Done(result, "Key %s %s.", args[0], done)
+		})
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	identityv1 "github.com/FangcunMount/iam-contracts/api/grpc/iam/identity/v1"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/options"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/printer"
+	"github.com/FangcunMount/iam-contracts/pkg/app"
+)
+
+// operatorChannel 通过 iamctl 发起的写操作在审计中的渠道标识
+const operatorChannel = "iamctl"
+
+var userTable = printer.Table{Columns: []printer.Column{
+	{Header: "id", Field: "id"},
+	{Header: "nickname", Field: "nickname"},
+	{Header: "status", Field: "status"},
+	{Header: "created", Field: "created_at"},
+}}
+
+// newUsersCommand 用户管理（经 gRPC SDK）
+func newUsersCommand(global *options.GlobalOptions) *app.Command {
+	return newGroup("users", "Get, search, create, deactivate and block users.",
+		newCommand(global, "get USER_ID", "Show a user.", cobra.ExactArgs(1), nil,
+			func(ctx context.Context, f *Factory, args []string) error {
+				client, err := f.SDK(ctx)
+				if err != nil {
+					return err
+				}
+				defer client.Close()
+
+				resp, err := client.Identity().GetUser(ctx, args[0])
+				if err != nil {
+					return err
+				}
+				return f.PrintProto(resp.GetUser(), userTable)
+			}),
+		newSearchUsersCommand(global),
+		newCreateUserCommand(global),
+		newChangeUserStatusCommand(global, "deactivate", "Deactivate a user."),
+		newChangeUserStatusCommand(global, "block", "Block a user."),
+	)
+}
+
+func newSearchUsersCommand(global *options.GlobalOptions) *app.Command {
+	var (
+		keyword       string
+		phones        []string
+		emails        []string
+		limit, offset uint32
+	)
+	flags := func(fs *pflag.FlagSet) {
+		fs.StringVar(&keyword, "keyword", "", "Fuzzy match on nickname, email or phone.")
+		fs.StringSliceVar(&phones, "phone", nil, "Exact phone match, repeatable.")
+		fs.StringSliceVar(&emails, "email", nil, "Exact email match, repeatable.")
+		fs.Uint32Var(&limit, "limit", 20, "Page size.")
+		fs.Uint32Var(&offset, "offset", 0, "Page offset.")
+	}
+
+	return newCommand(global, "search", "Search users.", cobra.NoArgs, flags,
+		func(ctx context.Context, f *Factory, _ []string) error {
+			client, err := f.SDK(ctx)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			resp, err := client.Identity().SearchUsers(ctx, &identityv1.SearchUsersRequest{
+				Keyword: keyword,
+				Phones:  phones,
+				Emails:  emails,
+				Page:    &identityv1.OffsetPagination{Limit: limit, Offset: offset},
+			})
+			if err != nil {
+				return err
+			}
+			table := userTable
+			table.ItemsField = "users"
+			return f.PrintProto(resp, table)
+		})
+}
+
+func newCreateUserCommand(global *options.GlobalOptions) *app.Command {
+	req := &identityv1.CreateUserRequest{}
+	flags := func(fs *pflag.FlagSet) {
+		fs.StringVar(&req.Nickname, "nickname", "", "Nickname.")
+		fs.StringVar(&req.Phone, "phone", "", "Phone number.")
+		fs.StringVar(&req.Email, "email", "", "Email address.")
+		fs.StringVar(&req.AvatarUrl, "avatar-url", "", "Avatar URL.")
+	}
+
+	return newCommand(global, "create", "Create a user.", cobra.NoArgs, flags,
+		func(ctx context.Context, f *Factory, _ []string) error {
+			client, err := f.SDK(ctx)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			req.Operator = &identityv1.OperatorContext{Channel: operatorChannel}
+			resp, err := client.Identity().CreateUser(ctx, req)
+			if err != nil {
+				return err
+			}
+			return f.PrintProto(resp.GetUser(), userTable)
+		})
+}
+
+func newChangeUserStatusCommand(global *options.GlobalOptions, action, desc string) *app.Command {
+	var reason string
+	flags := func(fs *pflag.FlagSet) {
+		fs.StringVar(&reason, "reason", "", "Reason recorded in the audit log.")
+	}
+
+	return newCommand(global, action+" USER_ID", desc, cobra.ExactArgs(1), flags,
+		func(ctx context.Context, f *Factory, args []string) error {
+			client, err := f.SDK(ctx)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			req := &identityv1.ChangeUserStatusRequest{
+				UserId:   args[0],
+				Reason:   reason,
+				Operator: &identityv1.OperatorContext{Channel: operatorChannel, Reason: reason},
+			}
+			var resp *identityv1.UserOperationResponse
+			if action == "block" {
+				resp, err = client.Identity().BlockUser(ctx, req)
+			} else {
+				resp, err = client.Identity().DeactivateUser(ctx, req)
+			}
+			if err != nil {
+				return err
+			}
+			return f.PrintProto(resp.GetUser(), userTable)
+		})
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/options"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/printer"
+	"github.com/FangcunMount/iam-contracts/pkg/app"
+	"github.com/FangcunMount/iam-contracts/pkg/version"
+)
+
+// newVersionCommand 打印 iamctl 版本信息，无需连接服务端
+func newVersionCommand(global *options.GlobalOptions) *app.Command {
+	return newCommand(global, "version", "Print the iamctl version information.", cobra.NoArgs, nil,
+		func(_ context.Context, f *Factory, _ []string) error {
+			info := version.Get()
+			if f.opts.Output == printer.FormatTable {
+				_, err := fmt.Fprint(f.Out(), info.String())
+				return err
+			}
+			return f.Print(info, printer.Table{})
+		})
+}
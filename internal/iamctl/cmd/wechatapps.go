@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/options"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/printer"
+	"github.com/FangcunMount/iam-contracts/pkg/app"
+)
+
+const wechatAppsPath = "/api/v1/idp/wechat-apps"
+
+var wechatAppTable = printer.Table{Columns: []printer.Column{
+	{Header: "app-id", Field: "app_id"},
+	{Header: "name", Field: "name"},
+	{Header: "type", Field: "type"},
+	{Header: "status", Field: "status"},
+}}
+
+// newWechatAppsCommand 微信应用管理
+func newWechatAppsCommand(global *options.GlobalOptions) *app.Command {
+	return newGroup("wechat-apps", "Manage WeChat apps: register, enable, disable and rotate secrets.",
+		newListWechatAppsCommand(global),
+		newCommand(global, "get APP_ID", "Show a WeChat app.", cobra.ExactArgs(1), nil,
+			func(ctx context.Context, f *Factory, args []string) error {
+				return getAndPrint(ctx, f, wechatAppsPath+"/"+url.PathEscape(args[0]), nil, wechatAppTable)
+			}),
+		newCreateWechatAppCommand(global),
+		newWechatAppActionCommand(global, "enable", "Enable a WeChat app.", "enabled"),
+		newWechatAppActionCommand(global, "disable", "Disable a WeChat app.", "disabled"),
+		newRotateWechatSecretCommand(global),
+	)
+}
+
+func newListWechatAppsCommand(global *options.GlobalOptions) *app.Command {
+	var appType, status string
+	flags := func(fs *pflag.FlagSet) {
+		fs.StringVar(&appType, "type", "", "Filter by type: MiniProgram|MP.")
+		fs.StringVar(&status, "status", "", "Filter by status: Enabled|Disabled|Archived.")
+	}
+
+	return newCommand(global, "list", "List WeChat apps.", cobra.NoArgs, flags,
+		func(ctx context.Context, f *Factory, _ []string) error {
+			query := url.Values{}
+			if appType != "" {
+				query.Set("type", appType)
+			}
+			if status != "" {
+				query.Set("status", status)
+			}
+			table := wechatAppTable
+			table.ItemsField = "items"
+			return getAndPrint(ctx, f, wechatAppsPath, query, table)
+		})
+}
+
+func newCreateWechatAppCommand(global *options.GlobalOptions) *app.Command {
+	var appID, name, appType, secret string
+	flags := func(fs *pflag.FlagSet) {
+		fs.StringVar(&appID, "app-id", "", "WeChat AppID.")
+		fs.StringVar(&name, "name", "", "App name.")
+		fs.StringVar(&appType, "type", "MiniProgram", "App type: MiniProgram|MP.")
+		fs.StringVar(&secret, "app-secret", "", "AppSecret, stored encrypted.")
+	}
+
+	return newCommand(global, "create", "Register a WeChat app.", cobra.NoArgs, flags,
+		func(ctx context.Context, f *Factory, _ []string) error {
+			rest, err := f.REST()
+			if err != nil {
+				return err
+			}
+			result, err := rest.Post(ctx, wechatAppsPath, map[string]string{
+				"app_id":     appID,
+				"name":       name,
+				"type":       appType,
+				"app_secret": secret,
+			})
+			if err != nil {
+				return err
+			}
+			return f.Print(result, wechatAppTable)
+		})
+}
+
+func newWechatAppActionCommand(global *options.GlobalOptions, action, desc, done string) *app.Command {
+	return newCommand(global, action+" APP_ID", desc, cobra.ExactArgs(1), nil,
+		func(ctx context.Context, f *Factory, args []string) error {
+			rest, err := f.REST()
+			if err != nil {
+				return err
+			}
+			result, err := rest.Post(ctx, wechatAppsPath+"/"+url.PathEscape(args[0])+"/"+action, nil)
+			if err != nil {
+				return err
+			}
+			return f.Done(result, "WeChat app %s %s.", args[0], done)
+		})
+}
+
+func newRotateWechatSecretCommand(global *options.GlobalOptions) *app.Command {
+	var secret string
+	flags := func(fs *pflag.FlagSet) {
+		fs.StringVar(&secret, "new-secret", "", "New AppSecret.")
+	}
+
+	return newCommand(global, "rotate-auth-secret APP_ID", "Rotate the AppSecret of a WeChat app.", cobra.ExactArgs(1), flags,
+		func(ctx context.Context, f *Factory, args []string) error {
+			rest, err := f.REST()
+			if err != nil {
+				return err
+			}
+			result, err := rest.Post(ctx, wechatAppsPath+"/rotate-auth-secret", map[string]string{
+				"app_id":     args[0],
+				"new_secret": secret,
+			})
+			if err != nil {
+				return err
+			}
+			return f.Done(result, "AppSecret of WeChat app %s rotated.", args[0])
+		})
+}
@@ -0,0 +1,148 @@
+// Package config iamctl 多环境上下文配置
+//
+// 配置文件结构参照 kubeconfig：一个文件登记多个命名上下文（环境），
+// current-context 指定默认使用的上下文，命令行可通过 --context 临时切换。
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvConfigPath 覆盖默认配置文件路径的环境变量
+const EnvConfigPath = "IAMCONFIG"
+
+// DefaultConfigPath 默认配置文件路径：~/.iam/iamctl.yaml
+func DefaultConfigPath() string {
+	if path := os.Getenv(EnvConfigPath); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(home, ".iam", "iamctl.yaml")
+}
+
+// Config iamctl 配置文件
+type Config struct {
+	CurrentContext string         `yaml:"current-context"`
+	Contexts       []NamedContext `yaml:"contexts"`
+}
+
+// NamedContext 命名上下文
+type NamedContext struct {
+	Name    string  `yaml:"name"`
+	Context Context `yaml:"context"`
+}
+
+// Context 单个环境的连接参数
+type Context struct {
+	// Server REST API 地址，如 https://iam.fangcunmount.cn
+	Server string `yaml:"server,omitempty"`
+	// GRPCServer gRPC 地址，如 iam.fangcunmount.cn:9090；users/accounts 等命令经 SDK 调用
+	GRPCServer string `yaml:"grpc-server,omitempty"`
+	// Token 平台管理员的 Bearer 访问令牌
+	Token string `yaml:"token,omitempty"`
+
+	CAFile             string `yaml:"ca-file,omitempty"`
+	CertFile           string `yaml:"cert-file,omitempty"` // gRPC mTLS 客户端证书
+	KeyFile            string `yaml:"key-file,omitempty"`
+	TLSServerName      string `yaml:"tls-server-name,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecure-skip-tls-verify,omitempty"`
+}
+
+// Load 读取配置文件；文件不存在时返回空配置
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read iamctl config %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse iamctl config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Save 写入配置文件；文件包含令牌，仅对当前用户可读写
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Get 按名称查找上下文
+func (c *Config) Get(name string) (*Context, bool) {
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == name {
+			return &c.Contexts[i].Context, true
+		}
+	}
+	return nil, false
+}
+
+// Set 新增或替换上下文；首个上下文自动成为当前上下文
+func (c *Config) Set(name string, ctx Context) {
+	if existing, ok := c.Get(name); ok {
+		*existing = ctx
+		return
+	}
+	c.Contexts = append(c.Contexts, NamedContext{Name: name, Context: ctx})
+	sort.Slice(c.Contexts, func(i, j int) bool { return c.Contexts[i].Name < c.Contexts[j].Name })
+	if c.CurrentContext == "" {
+		c.CurrentContext = name
+	}
+}
+
+// Delete 删除上下文；删除的是当前上下文时清空 current-context
+func (c *Config) Delete(name string) bool {
+	for i := range c.Contexts {
+		if c.Contexts[i].Name != name {
+			continue
+		}
+		c.Contexts = append(c.Contexts[:i], c.Contexts[i+1:]...)
+		if c.CurrentContext == name {
+			c.CurrentContext = ""
+		}
+		return true
+	}
+	return false
+}
+
+// Use 切换当前上下文
+func (c *Config) Use(name string) error {
+	if _, ok := c.Get(name); !ok {
+		return fmt.Errorf("context %q not found", name)
+	}
+	c.CurrentContext = name
+	return nil
+}
+
+// Resolve 返回 name 指定的上下文，name 为空时使用当前上下文
+func (c *Config) Resolve(name string) (string, Context, error) {
+	if name == "" {
+		name = c.CurrentContext
+	}
+	if name == "" {
+		return "", Context{}, fmt.Errorf("no context selected: run \"iamctl config set-context\" or pass --context")
+	}
+	ctx, ok := c.Get(name)
+	if !ok {
+		return "", Context{}, fmt.Errorf("context %q not found", name)
+	}
+	return name, *ctx, nil
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigContexts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "iam", "iamctl.yaml")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	_, _, err = cfg.Resolve("")
+	assert.Error(t, err, "no context selected")
+
+	cfg.Set("prod", Context{Server: "https://iam.fangcunmount.cn", GRPCServer: "iam.fangcunmount.cn:9090", Token: "t1"})
+	cfg.Set("dev", Context{Server: "http://localhost:18081"})
+	assert.Equal(t, "prod", cfg.CurrentContext, "first context becomes current")
+	require.NoError(t, cfg.Save(path))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev", "prod"}, []string{loaded.Contexts[0].Name, loaded.Contexts[1].Name})
+
+	name, ctx, err := loaded.Resolve("")
+	require.NoError(t, err)
+	assert.Equal(t, "prod", name)
+	assert.Equal(t, "t1", ctx.Token)
+
+	name, ctx, err = loaded.Resolve("dev")
+	require.NoError(t, err)
+	assert.Equal(t, "dev", name)
+	assert.Equal(t, "http://localhost:18081", ctx.Server)
+
+	require.NoError(t, loaded.Use("dev"))
+	assert.Error(t, loaded.Use("staging"))
+	assert.True(t, loaded.Delete("dev"))
+	assert.False(t, loaded.Delete("dev"))
+	assert.Empty(t, loaded.CurrentContext)
+
+	_, _, err = loaded.Resolve("staging")
+	assert.Error(t, err)
+}
@@ -0,0 +1,83 @@
+// Package options iamctl 全局命令行选项
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/config"
+	"github.com/FangcunMount/iam-contracts/internal/iamctl/printer"
+)
+
+// GlobalOptions 所有子命令共享的选项：选择上下文、覆盖连接参数、输出格式
+type GlobalOptions struct {
+	ConfigFile string
+	Context    string
+	Output     string
+	Timeout    time.Duration
+
+	// 以下参数非空时覆盖上下文中的同名配置
+	Server     string
+	GRPCServer string
+	Token      string
+}
+
+// NewGlobalOptions 创建默认全局选项
+func NewGlobalOptions() *GlobalOptions {
+	return &GlobalOptions{
+		ConfigFile: config.DefaultConfigPath(),
+		Output:     printer.FormatTable,
+		Timeout:    30 * time.Second,
+	}
+}
+
+// AddFlags 注册全局标志
+func (o *GlobalOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.ConfigFile, "iamconfig", o.ConfigFile,
+		fmt.Sprintf("Path to the iamctl config file (env %s).", config.EnvConfigPath))
+	fs.StringVar(&o.Context, "context", o.Context, "Name of the context to use, defaults to current-context.")
+	fs.StringVarP(&o.Output, "output", "o", o.Output, "Output format: table|json|yaml.")
+	fs.DurationVar(&o.Timeout, "request-timeout", o.Timeout, "Timeout for a single command.")
+	fs.StringVar(&o.Server, "server", o.Server, "IAM REST API address, overrides the context.")
+	fs.StringVar(&o.GRPCServer, "grpc-server", o.GRPCServer, "IAM gRPC address, overrides the context.")
+	fs.StringVar(&o.Token, "token", o.Token, "Bearer token for the IAM API, overrides the context.")
+}
+
+// Validate 校验全局选项
+func (o *GlobalOptions) Validate() []error {
+	var errs []error
+	if err := printer.ValidateFormat(o.Output); err != nil {
+		errs = append(errs, err)
+	}
+	if o.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("--request-timeout must be positive"))
+	}
+	return errs
+}
+
+// ResolveContext 读取配置文件并合并命令行覆盖项
+// 未登记任何上下文但通过命令行给出了服务地址时，允许不依赖配置文件直接使用
+func (o *GlobalOptions) ResolveContext() (config.Context, error) {
+	cfg, err := config.Load(o.ConfigFile)
+	if err != nil {
+		return config.Context{}, err
+	}
+
+	_, ctx, err := cfg.Resolve(o.Context)
+	if err != nil && (o.Context != "" || (o.Server == "" && o.GRPCServer == "")) {
+		return config.Context{}, err
+	}
+
+	if o.Server != "" {
+		ctx.Server = o.Server
+	}
+	if o.GRPCServer != "" {
+		ctx.GRPCServer = o.GRPCServer
+	}
+	if o.Token != "" {
+		ctx.Token = o.Token
+	}
+	return ctx, nil
+}
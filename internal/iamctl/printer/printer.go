@@ -0,0 +1,180 @@
+// Package printer iamctl 输出格式化：table / json / yaml
+//
+// 命令的返回值统一解码为通用 JSON 结构（map / slice），
+// json 与 yaml 原样输出，table 按命令声明的列从每一项中取值。
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 输出格式
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+)
+
+// Formats 支持的输出格式
+var Formats = []string{FormatTable, FormatJSON, FormatYAML}
+
+// Column 表格列：Field 为点分隔的字段路径，如 "user.id"
+type Column struct {
+	Header string
+	Field  string
+}
+
+// Table 表格输出规格
+type Table struct {
+	// ItemsField 列表所在字段，如 "items"；为空时对象本身即列表（或单个对象）
+	ItemsField string
+	Columns    []Column
+}
+
+// ValidateFormat 校验输出格式
+func ValidateFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported output format %q, expected one of %s", format, strings.Join(Formats, "|"))
+}
+
+// Print 按 format 输出 obj；table 格式下未声明列时按对象字段输出
+func Print(w io.Writer, format string, obj interface{}, table Table) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(obj)
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		defer enc.Close()
+		return enc.Encode(obj)
+	case FormatTable, "":
+		return printTable(w, obj, table)
+	default:
+		return ValidateFormat(format)
+	}
+}
+
+func printTable(w io.Writer, obj interface{}, table Table) error {
+	if table.ItemsField != "" {
+		obj = Lookup(obj, table.ItemsField)
+	}
+
+	var items []interface{}
+	switch v := obj.(type) {
+	case nil:
+	case []interface{}:
+		items = v
+	default:
+		items = []interface{}{v}
+	}
+
+	columns := table.Columns
+	if len(columns) == 0 {
+		columns = inferColumns(items)
+	}
+	if len(columns) == 0 {
+		_, err := fmt.Fprintln(w, "No resources found.")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 3, ' ', 0)
+	headers := make([]string, 0, len(columns))
+	for _, col := range columns {
+		headers = append(headers, strings.ToUpper(col.Header))
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, item := range items {
+		cells := make([]string, 0, len(columns))
+		for _, col := range columns {
+			cells = append(cells, FormatValue(Lookup(item, col.Field)))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// inferColumns 未声明列时使用首项的标量字段（按字母序）
+func inferColumns(items []interface{}) []Column {
+	if len(items) == 0 {
+		return nil
+	}
+	m, ok := items[0].(map[string]interface{})
+	if !ok {
+		return []Column{{Header: "value"}}
+	}
+
+	keys := make([]string, 0, len(m))
+	for k, v := range m {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	columns := make([]Column, 0, len(keys))
+	for _, k := range keys {
+		columns = append(columns, Column{Header: k, Field: k})
+	}
+	return columns
+}
+
+// Lookup 按点分隔路径取值；路径为空时返回对象本身
+func Lookup(obj interface{}, path string) interface{} {
+	if path == "" {
+		return obj
+	}
+	for _, key := range strings.Split(path, ".") {
+		m, ok := obj.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		obj = m[key]
+	}
+	return obj
+}
+
+// FormatValue 将通用 JSON 值格式化为单元格文本
+func FormatValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		if val == "" {
+			return "<none>"
+		}
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case []interface{}:
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			parts = append(parts, FormatValue(item))
+		}
+		return strings.Join(parts, ",")
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,62 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &v))
+	return v
+}
+
+func TestPrintTable(t *testing.T) {
+	obj := decode(t, `{"total":2,"items":[
+		{"id":"1","name":"admin","user":{"id":"10"},"amr":["pwd","otp"],"current":true},
+		{"id":"2","name":"","user":{"id":"11"},"amr":[],"current":false}
+	]}`)
+
+	var buf bytes.Buffer
+	err := Print(&buf, FormatTable, obj, Table{
+		ItemsField: "items",
+		Columns: []Column{
+			{Header: "id", Field: "id"},
+			{Header: "name", Field: "name"},
+			{Header: "user", Field: "user.id"},
+			{Header: "amr", Field: "amr"},
+			{Header: "current", Field: "current"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t,
+		"ID   NAME     USER   AMR       CURRENT\n"+
+			"1    admin    10     pwd,otp   true\n"+
+			"2    <none>   11               false\n",
+		buf.String())
+}
+
+func TestPrintTableInfersColumnsForSingleObject(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Print(&buf, FormatTable, decode(t, `{"version":3,"tenant_id":"t1","meta":{"a":1}}`), Table{}))
+	assert.Equal(t, "TENANT_ID   VERSION\nt1          3\n", buf.String())
+}
+
+func TestPrintStructured(t *testing.T) {
+	obj := decode(t, `{"kid":"k1","status":"active"}`)
+
+	var buf bytes.Buffer
+	require.NoError(t, Print(&buf, FormatYAML, obj, Table{}))
+	assert.Equal(t, "kid: k1\nstatus: active\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, Print(&buf, FormatJSON, obj, Table{}))
+	assert.JSONEq(t, `{"kid":"k1","status":"active"}`, buf.String())
+
+	assert.Error(t, Print(&buf, "xml", obj, Table{}))
+}
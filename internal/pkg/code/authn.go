@@ -119,6 +119,12 @@ const (
 	ErrCredentialDisabled  = 102304
 	ErrInvalidCredential   = 102305
 	ErrCredentialNotUsable = 102306
+
+	// ErrIdentityBoundElsewhere - 409: External identity is already bound to another account.
+	ErrIdentityBoundElsewhere = 102307
+
+	// ErrLastLoginMethod - 409: Cannot unbind the last login method of an account.
+	ErrLastLoginMethod = 102308
 )
 
 // Authn: 认证流程相关错误码 (102400～102499).
@@ -129,6 +135,62 @@ const (
 	ErrIDPExchangeFailed    = 102403
 	ErrNoBinding            = 102404
 	ErrOTPSendTooFrequent   = 102405
+	ErrLoginThrottled       = 102406 // 来源 IP 或登录名失败次数过多，暂时拒绝登录
+	ErrCaptchaRequired      = 102407 // 失败次数达到阈值，客户端须先完成人机验证
+)
+
+// Authn: 密码策略相关错误码 (102500～102599).
+const (
+	// ErrPasswordTooShort - 400: Password is shorter than the policy minimum.
+	ErrPasswordTooShort = 102500
+
+	// ErrPasswordTooWeak - 400: Password does not contain enough character classes.
+	ErrPasswordTooWeak = 102501
+
+	// ErrPasswordReused - 400: Password matches one of the recently used passwords.
+	ErrPasswordReused = 102502
+
+	// ErrPasswordBreached - 400: Password appears in a known breach corpus.
+	ErrPasswordBreached = 102503
+
+	// ErrPasswordExpired - 403: Password exceeded its maximum age and must be changed.
+	ErrPasswordExpired = 102504
+)
+
+// Authn: 会话相关错误码 (102600～102699).
+const (
+	// ErrSessionNotFound - 404: Session not found or not owned by the caller.
+	ErrSessionNotFound = 102600
+
+	// ErrSessionLimitExceeded - 409: Concurrent session limit reached for the account.
+	ErrSessionLimitExceeded = 102601
+
+	// ErrSessionIdleTimeout - 401: Session idle timeout, re-authentication required.
+	ErrSessionIdleTimeout = 102602
+
+	// ErrSessionLifetimeExceeded - 401: Session reached its absolute lifetime, re-authentication required.
+	ErrSessionLifetimeExceeded = 102603
+)
+
+// Authn: SAML 单点登录相关错误码 (102700～102799).
+const (
+	// ErrSAMLIdPNotFound - 404: SAML identity provider not found.
+	ErrSAMLIdPNotFound = 102700
+
+	// ErrSAMLIdPAlreadyExists - 409: SAML identity provider already exists.
+	ErrSAMLIdPAlreadyExists = 102701
+
+	// ErrSAMLIdPDisabled - 400: SAML identity provider is disabled.
+	ErrSAMLIdPDisabled = 102702
+
+	// ErrSAMLMetadataInvalid - 400: SAML IdP metadata is invalid.
+	ErrSAMLMetadataInvalid = 102703
+
+	// ErrSAMLAssertionInvalid - 401: SAML response or assertion failed validation.
+	ErrSAMLAssertionInvalid = 102704
+
+	// ErrSAMLAssertionReplayed - 401: SAML assertion has already been consumed.
+	ErrSAMLAssertionReplayed = 102705
 )
 
 // nolint: gochecknoinits
@@ -185,6 +247,8 @@ func registerAuthn() {
 	errors.MustRegister(&authnCoder{code: ErrCredentialDisabled, status: http.StatusForbidden, msg: "Credential is disabled"})
 	errors.MustRegister(&authnCoder{code: ErrInvalidCredential, status: http.StatusBadRequest, msg: "Invalid credential"})
 	errors.MustRegister(&authnCoder{code: ErrCredentialNotUsable, status: http.StatusForbidden, msg: "Credential is not usable"})
+	errors.MustRegister(&authnCoder{code: ErrIdentityBoundElsewhere, status: http.StatusConflict, msg: "Identity is already bound to another account"})
+	errors.MustRegister(&authnCoder{code: ErrLastLoginMethod, status: http.StatusConflict, msg: "Cannot unbind the last login method"})
 
 	// Authentication flow errors
 	errors.MustRegister(&authnCoder{code: ErrAuthenticationFailed, status: http.StatusUnauthorized, msg: "Authentication failed"})
@@ -193,6 +257,29 @@ func registerAuthn() {
 	errors.MustRegister(&authnCoder{code: ErrIDPExchangeFailed, status: http.StatusBadGateway, msg: "Failed to exchange code with identity provider"})
 	errors.MustRegister(&authnCoder{code: ErrNoBinding, status: http.StatusUnauthorized, msg: "No account binding found"})
 	errors.MustRegister(&authnCoder{code: ErrOTPSendTooFrequent, status: http.StatusTooManyRequests, msg: "OTP send too frequent"})
+	errors.MustRegister(&authnCoder{code: ErrLoginThrottled, status: http.StatusTooManyRequests, msg: "Too many failed login attempts"})
+	errors.MustRegister(&authnCoder{code: ErrCaptchaRequired, status: http.StatusUnauthorized, msg: "Captcha verification required"})
+
+	// Password policy errors
+	errors.MustRegister(&authnCoder{code: ErrPasswordTooShort, status: http.StatusBadRequest, msg: "Password is too short"})
+	errors.MustRegister(&authnCoder{code: ErrPasswordTooWeak, status: http.StatusBadRequest, msg: "Password does not meet complexity requirements"})
+	errors.MustRegister(&authnCoder{code: ErrPasswordReused, status: http.StatusBadRequest, msg: "Password was used recently"})
+	errors.MustRegister(&authnCoder{code: ErrPasswordBreached, status: http.StatusBadRequest, msg: "Password has appeared in a data breach"})
+	errors.MustRegister(&authnCoder{code: ErrPasswordExpired, status: http.StatusForbidden, msg: "Password has expired and must be changed"})
+
+	// Session errors
+	errors.MustRegister(&authnCoder{code: ErrSessionNotFound, status: http.StatusNotFound, msg: "Session not found"})
+	errors.MustRegister(&authnCoder{code: ErrSessionLimitExceeded, status: http.StatusConflict, msg: "Too many concurrent sessions"})
+	errors.MustRegister(&authnCoder{code: ErrSessionIdleTimeout, status: http.StatusUnauthorized, msg: "Session idle timeout"})
+	errors.MustRegister(&authnCoder{code: ErrSessionLifetimeExceeded, status: http.StatusUnauthorized, msg: "Session lifetime exceeded"})
+
+	// SAML errors
+	errors.MustRegister(&authnCoder{code: ErrSAMLIdPNotFound, status: http.StatusNotFound, msg: "SAML identity provider not found"})
+	errors.MustRegister(&authnCoder{code: ErrSAMLIdPAlreadyExists, status: http.StatusConflict, msg: "SAML identity provider already exists"})
+	errors.MustRegister(&authnCoder{code: ErrSAMLIdPDisabled, status: http.StatusBadRequest, msg: "SAML identity provider is disabled"})
+	errors.MustRegister(&authnCoder{code: ErrSAMLMetadataInvalid, status: http.StatusBadRequest, msg: "SAML IdP metadata is invalid"})
+	errors.MustRegister(&authnCoder{code: ErrSAMLAssertionInvalid, status: http.StatusUnauthorized, msg: "SAML assertion is invalid"})
+	errors.MustRegister(&authnCoder{code: ErrSAMLAssertionReplayed, status: http.StatusUnauthorized, msg: "SAML assertion has already been used"})
 }
 
 // authnCoder 实现 errors.Coder 接口
@@ -77,6 +77,90 @@ func TestAuthnErrorCodesUsage(t *testing.T) {
 			expectedStatus: http.StatusTooManyRequests,
 			shouldRegister: true,
 		},
+		{
+			name:           "ErrIdentityBoundElsewhere",
+			errorCode:      code.ErrIdentityBoundElsewhere,
+			expectedStatus: http.StatusConflict,
+			shouldRegister: true,
+		},
+		{
+			name:           "ErrLoginThrottled",
+			errorCode:      code.ErrLoginThrottled,
+			expectedStatus: http.StatusTooManyRequests,
+			shouldRegister: true,
+		},
+		{
+			name:           "ErrPasswordBreached",
+			errorCode:      code.ErrPasswordBreached,
+			expectedStatus: http.StatusBadRequest,
+			shouldRegister: true,
+		},
+		{
+			name:           "ErrSessionNotFound",
+			errorCode:      code.ErrSessionNotFound,
+			expectedStatus: http.StatusNotFound,
+			shouldRegister: true,
+		},
+		{
+			name:           "ErrSessionLimitExceeded",
+			errorCode:      code.ErrSessionLimitExceeded,
+			expectedStatus: http.StatusConflict,
+			shouldRegister: true,
+		},
+		{
+			name:           "ErrSessionIdleTimeout",
+			errorCode:      code.ErrSessionIdleTimeout,
+			expectedStatus: http.StatusUnauthorized,
+			shouldRegister: true,
+		},
+		{
+			name:           "ErrSessionLifetimeExceeded",
+			errorCode:      code.ErrSessionLifetimeExceeded,
+			expectedStatus: http.StatusUnauthorized,
+			shouldRegister: true,
+		},
+		{
+			name:           "ErrSAMLIdPNotFound",
+			errorCode:      code.ErrSAMLIdPNotFound,
+			expectedStatus: http.StatusNotFound,
+			shouldRegister: true,
+		},
+		{
+			name:           "ErrSAMLIdPAlreadyExists",
+			errorCode:      code.ErrSAMLIdPAlreadyExists,
+			expectedStatus: http.StatusConflict,
+			shouldRegister: true,
+		},
+		{
+			name:           "ErrSAMLIdPDisabled",
+			errorCode:      code.ErrSAMLIdPDisabled,
+			expectedStatus: http.StatusBadRequest,
+			shouldRegister: true,
+		},
+		{
+			name:           "ErrSAMLMetadataInvalid",
+			errorCode:      code.ErrSAMLMetadataInvalid,
+			expectedStatus: http.StatusBadRequest,
+			shouldRegister: true,
+		},
+		{
+			name:           "ErrSAMLAssertionInvalid",
+			errorCode:      code.ErrSAMLAssertionInvalid,
+			expectedStatus: http.StatusUnauthorized,
+			shouldRegister: true,
+		},
+		{
+			name:           "ErrSAMLAssertionReplayed",
+			errorCode:      code.ErrSAMLAssertionReplayed,
+			expectedStatus: http.StatusUnauthorized,
+			shouldRegister: true,
+		},
+		{
+			name:           "ErrPasswordExpired",
+			errorCode:      code.ErrPasswordExpired,
+			expectedStatus: http.StatusForbidden,
+			shouldRegister: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -53,6 +53,28 @@ const (
 	ErrPolicyVersionNotFound = 103400
 	// ErrPolicyVersionAlreadyExists - 409: Policy version already exists.
 	ErrPolicyVersionAlreadyExists = 103401
+	// ErrPolicyHistoryIncomplete - 409: Policy history between versions is incomplete.
+	ErrPolicyHistoryIncomplete = 103402
+	// ErrPolicyRollbackConflict - 409: Policy rollback conflicts with current state.
+	ErrPolicyRollbackConflict = 103403
+)
+
+// Authz: gRPC 服务 ACL 相关错误 (103500～103599).
+const (
+	// ErrServiceACLNotFound - 404: gRPC service ACL entry not found.
+	ErrServiceACLNotFound = 103500
+
+	// ErrServiceACLAlreadyExists - 409: gRPC service ACL entry already exists.
+	ErrServiceACLAlreadyExists = 103501
+
+	// ErrServiceACLInvalid - 400: gRPC service ACL entry is invalid.
+	ErrServiceACLInvalid = 103502
+)
+
+// Authz: 声明式授权配置相关错误 (103600～103699).
+const (
+	// ErrAuthzConfigInvalid - 400: Declarative authz config document is invalid.
+	ErrAuthzConfigInvalid = 103600
 )
 
 // nolint: gochecknoinits
@@ -81,8 +103,16 @@ func registerAuthz() {
 	// 策略版本相关错误
 	registerAuthzCode(ErrPolicyVersionNotFound, http.StatusNotFound, "Policy version not found")
 	registerAuthzCode(ErrPolicyVersionAlreadyExists, http.StatusConflict, "Policy version already exists")
+	registerAuthzCode(ErrPolicyHistoryIncomplete, http.StatusConflict, "Policy change history is incomplete")
+	registerAuthzCode(ErrPolicyRollbackConflict, http.StatusConflict, "Policy rollback conflicts with current state")
+
+	// gRPC 服务 ACL 相关错误
+	registerAuthzCode(ErrServiceACLNotFound, http.StatusNotFound, "gRPC service ACL entry not found")
+	registerAuthzCode(ErrServiceACLAlreadyExists, http.StatusConflict, "gRPC service ACL entry already exists")
+	registerAuthzCode(ErrServiceACLInvalid, http.StatusBadRequest, "gRPC service ACL entry is invalid")
 
-	// 策略相关错误
+	// 声明式授权配置相关错误
+	registerAuthzCode(ErrAuthzConfigInvalid, http.StatusBadRequest, "Authz config document is invalid")
 }
 
 func registerAuthzCode(code int, httpStatus int, message string) {
@@ -0,0 +1,63 @@
+package code_test
+
+import (
+	"net/http"
+	"testing"
+
+	perrors "github.com/FangcunMount/component-base/pkg/errors"
+	"github.com/FangcunMount/iam-contracts/internal/pkg/code"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthzErrorCodesRegistration(t *testing.T) {
+	tests := []struct {
+		name           string
+		errorCode      int
+		expectedStatus int
+	}{
+		{
+			name:           "ErrServiceACLNotFound",
+			errorCode:      code.ErrServiceACLNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "ErrServiceACLAlreadyExists",
+			errorCode:      code.ErrServiceACLAlreadyExists,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "ErrServiceACLInvalid",
+			errorCode:      code.ErrServiceACLInvalid,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "ErrPolicyHistoryIncomplete",
+			errorCode:      code.ErrPolicyHistoryIncomplete,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "ErrPolicyRollbackConflict",
+			errorCode:      code.ErrPolicyRollbackConflict,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "ErrAuthzConfigInvalid",
+			errorCode:      code.ErrAuthzConfigInvalid,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := perrors.WithCode(tt.errorCode, "test error")
+			coder := perrors.ParseCoder(err)
+
+			if assert.NotNil(t, coder) {
+				assert.Equal(t, tt.errorCode, coder.Code())
+				assert.Equal(t, tt.expectedStatus, coder.HTTPStatus())
+				assert.NotEmpty(t, coder.String())
+			}
+			assert.True(t, perrors.IsCode(err, tt.errorCode))
+		})
+	}
+}
@@ -30,6 +30,9 @@ const (
 
 	// ErrUserInactive - 403: User is inactive.
 	ErrUserInactive = 101006
+
+	// ErrUserMergeConflict - 409: User merge conflict.
+	ErrUserMergeConflict = 101007
 )
 
 // Identity: 儿童档案错误 (101100～101199).
@@ -64,6 +67,7 @@ func registerIdentity() {
 	errors.MustRegister(&identityCoder{code: ErrUserInvalid, status: http.StatusBadRequest, msg: "User is invalid"})
 	errors.MustRegister(&identityCoder{code: ErrUserBlocked, status: http.StatusForbidden, msg: "User is blocked"})
 	errors.MustRegister(&identityCoder{code: ErrUserInactive, status: http.StatusForbidden, msg: "User is inactive"})
+	errors.MustRegister(&identityCoder{code: ErrUserMergeConflict, status: http.StatusConflict, msg: "User merge conflict"})
 
 	// 儿童档案错误
 	errors.MustRegister(&identityCoder{code: ErrIdentityChildExists, status: http.StatusBadRequest, msg: "儿童档案已存在"})
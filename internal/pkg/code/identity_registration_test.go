@@ -60,6 +60,12 @@ func TestIdentityErrorCodesRegistration(t *testing.T) {
 			expectedStatus: http.StatusForbidden,
 			shouldRegister: true,
 		},
+		{
+			name:           "ErrUserMergeConflict",
+			errorCode:      code.ErrUserMergeConflict,
+			expectedStatus: http.StatusConflict,
+			shouldRegister: true,
+		},
 		{
 			name:           "ErrIdentityChildExists",
 			errorCode:      code.ErrIdentityChildExists,
@@ -19,6 +19,42 @@ const (
 
 	// ErrWechatAppStatusInvalid - 400: Wechat app status is invalid.
 	ErrWechatAppStatusInvalid = 104003
+
+	// ErrWechatCallbackSignatureInvalid - 401: Wechat callback signature is invalid.
+	ErrWechatCallbackSignatureInvalid = 104004
+
+	// ErrWechatCallbackMalformed - 400: Wechat callback message is malformed.
+	ErrWechatCallbackMalformed = 104005
+
+	// ErrWechatMsgSecretNotConfigured - 400: Wechat app message secret is not configured.
+	ErrWechatMsgSecretNotConfigured = 104006
+
+	// ErrWecomAppNotFound - 404: Wecom app not found.
+	ErrWecomAppNotFound = 104010
+
+	// ErrWecomAppAlreadyExists - 409: Wecom app already exists.
+	ErrWecomAppAlreadyExists = 104011
+
+	// ErrWecomAppStatusInvalid - 400: Wecom app status is invalid.
+	ErrWecomAppStatusInvalid = 104012
+
+	// ErrWecomAppAmbiguous - 400: Multiple wecom apps match the corp, agent id is required.
+	ErrWecomAppAmbiguous = 104013
+
+	// ErrOIDCProviderNotFound - 404: OIDC provider not found.
+	ErrOIDCProviderNotFound = 104020
+
+	// ErrOIDCProviderAlreadyExists - 409: OIDC provider already exists.
+	ErrOIDCProviderAlreadyExists = 104021
+
+	// ErrOIDCProviderStatusInvalid - 400: OIDC provider status is invalid.
+	ErrOIDCProviderStatusInvalid = 104022
+
+	// ErrOIDCProviderInvalid - 400: OIDC provider configuration is invalid.
+	ErrOIDCProviderInvalid = 104023
+
+	// ErrOIDCStateInvalid - 401: OIDC authorization state is invalid or expired.
+	ErrOIDCStateInvalid = 104024
 )
 
 // nolint: gochecknoinits
@@ -31,6 +67,18 @@ func registerIDP() {
 	registerIDPCode(ErrWechatAppAlreadyExists, http.StatusConflict, "Wechat app already exists")
 	registerIDPCode(ErrWechatAppTypeInvalid, http.StatusBadRequest, "Wechat app type is invalid")
 	registerIDPCode(ErrWechatAppStatusInvalid, http.StatusBadRequest, "Wechat app status is invalid")
+	registerIDPCode(ErrWechatCallbackSignatureInvalid, http.StatusUnauthorized, "Wechat callback signature is invalid")
+	registerIDPCode(ErrWechatCallbackMalformed, http.StatusBadRequest, "Wechat callback message is malformed")
+	registerIDPCode(ErrWechatMsgSecretNotConfigured, http.StatusBadRequest, "Wechat app message secret is not configured")
+	registerIDPCode(ErrWecomAppNotFound, http.StatusNotFound, "Wecom app not found")
+	registerIDPCode(ErrWecomAppAlreadyExists, http.StatusConflict, "Wecom app already exists")
+	registerIDPCode(ErrWecomAppStatusInvalid, http.StatusBadRequest, "Wecom app status is invalid")
+	registerIDPCode(ErrWecomAppAmbiguous, http.StatusBadRequest, "Multiple wecom apps match the corp, agent id is required")
+	registerIDPCode(ErrOIDCProviderNotFound, http.StatusNotFound, "OIDC provider not found")
+	registerIDPCode(ErrOIDCProviderAlreadyExists, http.StatusConflict, "OIDC provider already exists")
+	registerIDPCode(ErrOIDCProviderStatusInvalid, http.StatusBadRequest, "OIDC provider status is invalid")
+	registerIDPCode(ErrOIDCProviderInvalid, http.StatusBadRequest, "OIDC provider configuration is invalid")
+	registerIDPCode(ErrOIDCStateInvalid, http.StatusUnauthorized, "OIDC authorization state is invalid or expired")
 }
 
 func registerIDPCode(code int, httpStatus int, message string) {
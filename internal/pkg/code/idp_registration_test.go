@@ -35,6 +35,66 @@ func TestIDPErrorCodesRegistration(t *testing.T) {
 			errorCode:      code.ErrWechatAppStatusInvalid,
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name:           "ErrWechatCallbackSignatureInvalid",
+			errorCode:      code.ErrWechatCallbackSignatureInvalid,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "ErrWechatCallbackMalformed",
+			errorCode:      code.ErrWechatCallbackMalformed,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "ErrWechatMsgSecretNotConfigured",
+			errorCode:      code.ErrWechatMsgSecretNotConfigured,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "ErrWecomAppNotFound",
+			errorCode:      code.ErrWecomAppNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "ErrWecomAppAlreadyExists",
+			errorCode:      code.ErrWecomAppAlreadyExists,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "ErrWecomAppStatusInvalid",
+			errorCode:      code.ErrWecomAppStatusInvalid,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "ErrWecomAppAmbiguous",
+			errorCode:      code.ErrWecomAppAmbiguous,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "ErrOIDCProviderNotFound",
+			errorCode:      code.ErrOIDCProviderNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "ErrOIDCProviderAlreadyExists",
+			errorCode:      code.ErrOIDCProviderAlreadyExists,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "ErrOIDCProviderStatusInvalid",
+			errorCode:      code.ErrOIDCProviderStatusInvalid,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "ErrOIDCProviderInvalid",
+			errorCode:      code.ErrOIDCProviderInvalid,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "ErrOIDCStateInvalid",
+			errorCode:      code.ErrOIDCStateInvalid,
+			expectedStatus: http.StatusUnauthorized,
+		},
 	}
 
 	for _, tt := range tests {
@@ -163,3 +163,24 @@ func UserIDOrZero(ctx context.Context) meta.ID {
 	}
 	return meta.FromUint64(0)
 }
+
+// TenantIDFromContext extracts the authenticated tenant id (Casbin domain) from context when available.
+func TenantIDFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	switch v := ctx.Value(authn.ContextKeyTenantID).(type) {
+	case string:
+		if v == "" {
+			return "", false
+		}
+		return v, true
+	case meta.ID:
+		if v.IsZero() {
+			return "", false
+		}
+		return v.String(), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,200 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+
+	"github.com/FangcunMount/component-base/pkg/log"
+)
+
+// ACL 默认策略
+const (
+	ACLPolicyAllow = "allow"
+	ACLPolicyDeny  = "deny"
+)
+
+// ACLRule 单个调用方服务（证书 CN）的方法授权，字段与 grpc_acl.yaml 一致
+type ACLRule struct {
+	ServiceName    string   `yaml:"service_name"`
+	Enabled        bool     `yaml:"enabled"`
+	Description    string   `yaml:"description"`
+	AllowedMethods []string `yaml:"allowed_methods"`
+	DeniedMethods  []string `yaml:"denied_methods"`
+}
+
+// ACLPolicy 服务 ACL 全量策略
+type ACLPolicy struct {
+	DefaultPolicy string    `yaml:"default_policy"`
+	Services      []ACLRule `yaml:"services"`
+}
+
+// ACLDecision ACL 判定结果
+type ACLDecision struct {
+	Allowed bool
+	// MatchedPattern 命中的方法模式；按默认策略判定时为空
+	MatchedPattern string
+	// Reason 判定依据：denied_method / allowed_method / method_not_allowed / service_disabled / default_policy
+	Reason string
+}
+
+// 判定依据
+const (
+	ACLReasonDeniedMethod    = "denied_method"
+	ACLReasonAllowedMethod   = "allowed_method"
+	ACLReasonNotAllowed      = "method_not_allowed"
+	ACLReasonServiceDisabled = "service_disabled"
+	ACLReasonDefaultPolicy   = "default_policy"
+)
+
+// Evaluate 判定 service 能否调用 method
+// 规则：已登记服务按白名单判定，denied_methods 优先于 allowed_methods；已禁用的服务一律拒绝；未登记的服务按默认策略
+func (p *ACLPolicy) Evaluate(service, method string) ACLDecision {
+	for i := range p.Services {
+		rule := &p.Services[i]
+		if rule.ServiceName != service {
+			continue
+		}
+		if !rule.Enabled {
+			return ACLDecision{Reason: ACLReasonServiceDisabled}
+		}
+		if pattern, ok := matchMethod(rule.DeniedMethods, method); ok {
+			return ACLDecision{MatchedPattern: pattern, Reason: ACLReasonDeniedMethod}
+		}
+		if pattern, ok := matchMethod(rule.AllowedMethods, method); ok {
+			return ACLDecision{Allowed: true, MatchedPattern: pattern, Reason: ACLReasonAllowedMethod}
+		}
+		return ACLDecision{Reason: ACLReasonNotAllowed}
+	}
+	return ACLDecision{Allowed: p.DefaultPolicy == ACLPolicyAllow, Reason: ACLReasonDefaultPolicy}
+}
+
+// matchMethod 支持三种模式：完整方法名、/pkg.Service/* 与 *
+func matchMethod(patterns []string, method string) (string, bool) {
+	for _, pattern := range patterns {
+		switch {
+		case pattern == "*", pattern == method:
+			return pattern, true
+		case strings.HasSuffix(pattern, "/*") && strings.HasPrefix(method, strings.TrimSuffix(pattern, "*")):
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// LoadACLPolicyFile 从 YAML 文件加载 ACL 策略；文件未指定默认策略时使用 defaultPolicy
+func LoadACLPolicyFile(configFile, defaultPolicy string) (*ACLPolicy, error) {
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ACL config file: %w", err)
+	}
+
+	var policy ACLPolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse ACL config: %w", err)
+	}
+	if policy.DefaultPolicy == "" {
+		policy.DefaultPolicy = defaultPolicy
+	}
+	return &policy, nil
+}
+
+// DynamicACL 可热更新的服务 ACL
+// 拦截器每次调用读取当前快照；ReplaceServices 原子替换服务规则，默认策略保持启动配置
+type DynamicACL struct {
+	policy atomic.Pointer[ACLPolicy]
+}
+
+// NewDynamicACL 以初始策略创建可热更新的 ACL
+func NewDynamicACL(initial *ACLPolicy) *DynamicACL {
+	acl := &DynamicACL{}
+	if initial == nil {
+		initial = &ACLPolicy{DefaultPolicy: ACLPolicyDeny}
+	}
+	acl.policy.Store(initial)
+	return acl
+}
+
+// Policy 返回当前策略快照（调用方不得修改）
+func (a *DynamicACL) Policy() *ACLPolicy {
+	return a.policy.Load()
+}
+
+// DefaultPolicy 返回默认策略
+func (a *DynamicACL) DefaultPolicy() string {
+	return a.policy.Load().DefaultPolicy
+}
+
+// ReplaceServices 原子替换全部服务规则
+func (a *DynamicACL) ReplaceServices(services []ACLRule) {
+	next := &ACLPolicy{
+		DefaultPolicy: a.policy.Load().DefaultPolicy,
+		Services:      append([]ACLRule(nil), services...),
+	}
+	a.policy.Store(next)
+}
+
+// Evaluate 按当前快照判定
+func (a *DynamicACL) Evaluate(service, method string) ACLDecision {
+	return a.policy.Load().Evaluate(service, method)
+}
+
+// UnaryInterceptor 返回 ACL 一元拦截器
+func (a *DynamicACL) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor 返回 ACL 流式拦截器
+func (a *DynamicACL) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (a *DynamicACL) authorize(ctx context.Context, method string) error {
+	service := PeerServiceName(ctx)
+	decision := a.Evaluate(service, method)
+	if decision.Allowed {
+		return nil
+	}
+	log.Warnw("gRPC ACL denied",
+		"service", service,
+		"method", method,
+		"reason", decision.Reason,
+		"pattern", decision.MatchedPattern,
+	)
+	return status.Errorf(codes.PermissionDenied, "service %q is not allowed to call %s", service, method)
+}
+
+// PeerServiceName 从已验证的对端证书提取调用方服务名（CN），未经 mTLS 验证的连接返回空字符串
+func PeerServiceName(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return ""
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return ""
+	}
+	if chains := tlsInfo.State.VerifiedChains; len(chains) > 0 && len(chains[0]) > 0 {
+		return chains[0][0].Subject.CommonName
+	}
+	return ""
+}
@@ -2,6 +2,8 @@ package grpc
 
 import (
 	"time"
+
+	"google.golang.org/grpc"
 )
 
 // Config GRPC 服务器配置
@@ -31,6 +33,9 @@ type Config struct {
 	// 审计日志配置
 	Audit AuditConfig
 
+	// UnaryInterceptors 业务附加的一元拦截器（位于 ACL 之后、审计之前，如限流）
+	UnaryInterceptors []grpc.UnaryServerInterceptor
+
 	EnableReflection  bool
 	EnableHealthCheck bool
 	Insecure          bool // 是否使用不安全连接
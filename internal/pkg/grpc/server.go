@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net"
 	"net/http"
-	"os"
 	"time"
 
 	"google.golang.org/grpc"
@@ -14,7 +13,6 @@ import (
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
-	"gopkg.in/yaml.v3"
 
 	"github.com/FangcunMount/component-base/pkg/grpc/interceptors"
 	"github.com/FangcunMount/component-base/pkg/grpc/mtls"
@@ -29,10 +27,10 @@ type Server struct {
 	services      []Service
 	secure        bool
 	mtlsEnabled   bool
-	mtlsCreds     *mtls.ServerCredentials  // mTLS 凭证，用于证书自动重载
-	acl           *interceptors.ServiceACL // 服务级 ACL
-	healthServer  *health.Server           // 健康检查服务器
-	healthzServer *http.Server             // 独立的 HTTP 健康检查服务器
+	mtlsCreds     *mtls.ServerCredentials // mTLS 凭证，用于证书自动重载
+	acl           *DynamicACL             // 服务级 ACL（可热更新）
+	healthServer  *health.Server          // 健康检查服务器
+	healthzServer *http.Server            // 独立的 HTTP 健康检查服务器
 }
 
 // Service GRPC 服务接口
@@ -49,16 +47,21 @@ func NewServer(config *Config) (*Server, error) {
 	secure := false
 	mtlsEnabled := false
 	var mtlsCreds *mtls.ServerCredentials
-	var acl *interceptors.ServiceACL
+	var acl *DynamicACL
 
 	// 加载 ACL 配置（需要在构建拦截器之前）
-	if config.ACL.Enabled && config.ACL.ConfigFile != "" {
-		loadedACL, err := loadACLConfig(config.ACL.ConfigFile, config.ACL.DefaultPolicy)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load ACL config: %w", err)
+	// 配置文件作为启动规则；运行期由数据库中的服务 ACL 通过 ReplaceServices 热更新
+	if config.ACL.Enabled {
+		policy := &ACLPolicy{DefaultPolicy: config.ACL.DefaultPolicy}
+		if config.ACL.ConfigFile != "" {
+			loaded, err := LoadACLPolicyFile(config.ACL.ConfigFile, config.ACL.DefaultPolicy)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load ACL config: %w", err)
+			}
+			policy = loaded
 		}
-		acl = loadedACL
-		log.Infof("ACL enabled with config file: %s, default policy: %s", config.ACL.ConfigFile, config.ACL.DefaultPolicy)
+		acl = NewDynamicACL(policy)
+		log.Infof("ACL enabled with config file: %s, default policy: %s", config.ACL.ConfigFile, policy.DefaultPolicy)
 	}
 
 	// 构建拦截器链
@@ -162,7 +165,7 @@ func NewServer(config *Config) (*Server, error) {
 }
 
 // buildUnaryInterceptors 构建 Unary 拦截器链
-func buildUnaryInterceptors(config *Config, acl *interceptors.ServiceACL) []grpc.UnaryServerInterceptor {
+func buildUnaryInterceptors(config *Config, acl *DynamicACL) []grpc.UnaryServerInterceptor {
 	var chain []grpc.UnaryServerInterceptor
 
 	// 1. 恢复拦截器（最外层，捕获 panic）
@@ -203,14 +206,14 @@ func buildUnaryInterceptors(config *Config, acl *interceptors.ServiceACL) []grpc
 
 	// 6. ACL 拦截器（如果启用）
 	if config.ACL.Enabled && acl != nil {
-		chain = append(chain, interceptors.ACLInterceptor(
-			acl,
-			interceptors.WithACLLogger(&logAdapter{}),
-		))
+		chain = append(chain, acl.UnaryInterceptor())
 		log.Info("ACL interceptor enabled")
 	}
 
-	// 7. 审计日志拦截器（如果启用）
+	// 7. 业务附加拦截器（如限流，依赖前面提取的身份信息）
+	chain = append(chain, config.UnaryInterceptors...)
+
+	// 8. 审计日志拦截器（如果启用）
 	if config.Audit.Enabled {
 		chain = append(chain, interceptors.AuditInterceptor(
 			interceptors.NewDefaultAuditLogger(&logAdapter{}),
@@ -222,7 +225,7 @@ func buildUnaryInterceptors(config *Config, acl *interceptors.ServiceACL) []grpc
 }
 
 // buildStreamInterceptors 构建 Stream 拦截器链
-func buildStreamInterceptors(config *Config, acl *interceptors.ServiceACL) []grpc.StreamServerInterceptor {
+func buildStreamInterceptors(config *Config, acl *DynamicACL) []grpc.StreamServerInterceptor {
 	var chain []grpc.StreamServerInterceptor
 
 	// 流式日志拦截器（使用增强版，支持请求范围 Logger 注入）
@@ -248,10 +251,7 @@ func buildStreamInterceptors(config *Config, acl *interceptors.ServiceACL) []grp
 
 	// ACL 流式拦截器
 	if config.ACL.Enabled && acl != nil {
-		chain = append(chain, interceptors.ACLStreamInterceptor(
-			acl,
-			interceptors.WithACLLogger(&logAdapter{}),
-		))
+		chain = append(chain, acl.StreamInterceptor())
 	}
 
 	// 审计流式拦截器
@@ -283,27 +283,6 @@ func fieldsToArgs(fields map[string]interface{}) []interface{} {
 	return args
 }
 
-// loadACLConfig 加载 ACL 配置文件
-func loadACLConfig(configFile, defaultPolicy string) (*interceptors.ServiceACL, error) {
-	// 从 YAML 文件加载 ACL 配置
-	data, err := os.ReadFile(configFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read ACL config file: %w", err)
-	}
-
-	var aclConfig interceptors.ACLConfig
-	if err := yaml.Unmarshal(data, &aclConfig); err != nil {
-		return nil, fmt.Errorf("failed to parse ACL config: %w", err)
-	}
-
-	// 如果文件中未指定默认策略，使用参数中的
-	if aclConfig.DefaultPolicy == "" {
-		aclConfig.DefaultPolicy = defaultPolicy
-	}
-
-	return interceptors.NewServiceACL(&aclConfig), nil
-}
-
 // parseTLSVersion 解析 TLS 版本字符串
 func parseTLSVersion(version string) uint16 {
 	switch version {
@@ -555,6 +534,11 @@ func (s *Server) Address() string {
 	return fmt.Sprintf("%s:%d", s.config.BindAddress, s.config.BindPort)
 }
 
+// ACL 返回可热更新的服务 ACL；未启用 ACL 时返回 nil
+func (s *Server) ACL() *DynamicACL {
+	return s.acl
+}
+
 // Config 返回服务器配置
 func (s *Server) Config() *Config {
 	return s.config
@@ -305,6 +305,9 @@ func applyVerifiedClaims(c *gin.Context, claims *tokenDomain.TokenClaims) {
 	}
 
 	ctx := context.WithValue(c.Request.Context(), ContextKeyUserID, claims.UserID)
+	if !claims.TenantID.IsZero() {
+		ctx = context.WithValue(ctx, ContextKeyTenantID, claims.TenantID.String())
+	}
 	c.Request = c.Request.WithContext(ctx)
 	c.Set(ContextKeyClaims, claims)
 
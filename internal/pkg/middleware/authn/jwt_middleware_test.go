@@ -41,6 +41,9 @@ func TestApplyVerifiedClaimsSetsTenantIDForRoleResolution(t *testing.T) {
 	if got := TenantIDFromGin(c); got != "1" {
 		t.Fatalf("TenantIDFromGin() = %q, want %q", got, "1")
 	}
+	if got := c.Request.Context().Value(ContextKeyTenantID); got != "1" {
+		t.Fatalf("request context tenant_id = %v, want %q", got, "1")
+	}
 	if got, exists := c.Get(ContextKeyUserID); !exists || got != "110001" {
 		t.Fatalf("gin user_id = %v exists=%v, want %q", got, exists, "110001")
 	}
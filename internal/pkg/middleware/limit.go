@@ -11,6 +11,8 @@ import (
 var ErrLimitExceeded = errors.New("Limit exceeded")
 
 // Limit 如果达到限制，则丢弃（HTTP 状态 429）请求
+//
+// 仅为单进程令牌桶，多副本部署下请使用基于 Redis 的 RateLimit。
 func Limit(maxEventsPerSec float64, maxBurstSize int) gin.HandlerFunc {
 	limiter := rate.NewLimiter(rate.Limit(maxEventsPerSec), maxBurstSize)
 
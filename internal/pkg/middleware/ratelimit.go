@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/ratelimit"
+)
+
+// maxRateLimitBodyPeek 解析账号维度时最多读取的请求体字节数
+const maxRateLimitBodyPeek = 64 << 10
+
+// RateLimit 按路由策略执行分布式限流，输出 X-RateLimit-* 与 Retry-After 头
+func RateLimit(enforcer *ratelimit.Enforcer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		policies := enforcer.Policies(c.Request.Method, route)
+		if len(policies) == 0 {
+			c.Next()
+			return
+		}
+
+		attrs := ratelimit.Attributes{
+			IP:      c.ClientIP(),
+			Tenant:  requestTenant(c),
+			Route:   c.Request.Method + " " + route,
+			Account: requestAccount(c, policies),
+		}
+		decision := enforcer.Check(c.Request.Context(), policies, attrs)
+		if decision.Matched {
+			setRateLimitHeaders(c.Writer.Header(), decision)
+		}
+		if decision.Allowed {
+			c.Next()
+			return
+		}
+
+		_ = c.Error(ErrLimitExceeded)
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"code":    http.StatusTooManyRequests,
+			"message": "too many requests",
+		})
+	}
+}
+
+func setRateLimitHeaders(h http.Header, d ratelimit.Decision) {
+	h.Set("X-RateLimit-Limit", strconv.Itoa(d.Limit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(d.Remaining))
+	h.Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(d.ResetAfter)))
+	h.Set("X-RateLimit-Policy", d.Policy)
+	if !d.Allowed {
+		h.Set("Retry-After", strconv.Itoa(ceilSeconds(d.RetryAfter)))
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
+
+// requestTenant 已认证请求取上下文租户，否则读取租户请求头
+func requestTenant(c *gin.Context) string {
+	if v, ok := c.Get("tenant_id"); ok {
+		if s, ok := v.(string); ok && s != "" {
+			return s
+		}
+	}
+	for _, h := range []string{"X-Tenant-ID", "Tenant-ID", "tenant_id"} {
+		if v := strings.TrimSpace(c.GetHeader(h)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// requestAccount 已认证请求取用户 ID，否则按策略配置的字段路径从 JSON 请求体中解析账号标识
+func requestAccount(c *gin.Context, policies []ratelimit.Policy) string {
+	if v, ok := c.Get("user_id"); ok {
+		if s, ok := v.(string); ok && s != "" {
+			return s
+		}
+	}
+
+	var fields []string
+	for _, p := range policies {
+		fields = append(fields, p.AccountFields...)
+	}
+	if len(fields) == 0 || c.Request.Body == nil {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRateLimitBodyPeek))
+	// 回填请求体，后续处理器仍可正常绑定
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+	if err != nil || len(body) == 0 {
+		return ""
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return ""
+	}
+	for _, field := range fields {
+		if v := lookupJSONField(doc, field); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func lookupJSONField(doc map[string]interface{}, path string) string {
+	var cur interface{} = doc
+	for _, part := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		cur = m[part]
+	}
+	switch v := cur.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/FangcunMount/iam-contracts/internal/pkg/ratelimit"
+)
+
+func newRateLimitEnforcer(t *testing.T, policies ...ratelimit.Policy) *ratelimit.Enforcer {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { _ = client.Close() })
+
+	e := ratelimit.NewEnforcer(ratelimit.Config{Enabled: true, FailOpen: true, Policies: policies})
+	e.Bind(ratelimit.NewRedisLimiter(client))
+	return e
+}
+
+func TestRateLimitHTTPByAccountField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	e := newRateLimitEnforcer(t, ratelimit.Policy{
+		Name:          "login-account",
+		Routes:        []string{"POST /api/v1/authn/login"},
+		Rate:          1,
+		Period:        time.Minute,
+		KeyBy:         []ratelimit.Dimension{ratelimit.DimensionAccount},
+		AccountFields: []string{"credentials.username"},
+	})
+
+	r := gin.New()
+	r.Use(RateLimit(e))
+	r.POST("/api/v1/authn/login", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+
+	send := func(user string) *httptest.ResponseRecorder {
+		payload := `{"method":"password","credentials":{"username":"` + user + `"}}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/authn/login", strings.NewReader(payload))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("alice")
+	if w.Code != http.StatusOK {
+		t.Fatalf("first request should pass, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"username":"alice"`) {
+		t.Fatalf("request body should be restored for the handler, got %q", w.Body.String())
+	}
+	if w.Header().Get("X-RateLimit-Limit") != "1" || w.Header().Get("X-RateLimit-Policy") != "login-account" {
+		t.Fatalf("unexpected rate limit headers: %v", w.Header())
+	}
+
+	w = send("alice")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request should be limited, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" || w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Fatalf("denied response should carry Retry-After, got %v", w.Header())
+	}
+
+	// 其他账号独立计数
+	if w = send("bob"); w.Code != http.StatusOK {
+		t.Fatalf("another account should pass, got %d", w.Code)
+	}
+}
+
+func TestRateLimitHTTPSkipsUnmatchedRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	e := newRateLimitEnforcer(t, ratelimit.Policy{
+		Name: "login-ip", Routes: []string{"POST /api/v1/authn/login"}, Rate: 1, Period: time.Minute,
+		KeyBy: []ratelimit.Dimension{ratelimit.DimensionIP},
+	})
+
+	r := gin.New()
+	r.Use(RateLimit(e))
+	r.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		if w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "" {
+			t.Fatalf("unmatched route should not be limited: %d %v", w.Code, w.Header())
+		}
+	}
+}
+
+func TestUnaryServerRateLimitInterceptor(t *testing.T) {
+	const method = "/iam.authn.v1.AuthService/VerifyToken"
+	e := newRateLimitEnforcer(t, ratelimit.Policy{
+		Name: "verify", Routes: []string{method}, Rate: 1, Period: time.Minute,
+		KeyBy: []ratelimit.Dimension{ratelimit.DimensionRoute},
+	})
+	interceptor := UnaryServerRateLimitInterceptor(e)
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("first call should pass: %v", err)
+	}
+	_, err := interceptor(context.Background(), nil, info, handler)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("second call should be exhausted, got %v", err)
+	}
+}
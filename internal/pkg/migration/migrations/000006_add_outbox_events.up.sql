@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS `outbox_events`
+(
+    `id`              BIGINT UNSIGNED NOT NULL PRIMARY KEY COMMENT '事件ID',
+    `topic`           VARCHAR(128)    NOT NULL COMMENT '目标主题',
+    `event_type`      VARCHAR(128)    NOT NULL COMMENT '事件类型',
+    `aggregate_type`  VARCHAR(64)     NOT NULL DEFAULT '' COMMENT '聚合类型',
+    `aggregate_id`    VARCHAR(128)    NOT NULL DEFAULT '' COMMENT '聚合标识',
+    `idempotency_key` VARCHAR(191)    NOT NULL COMMENT '幂等键（消息UUID）',
+    `payload`         BLOB            NOT NULL COMMENT '消息体',
+    `metadata`        JSON                     DEFAULT NULL COMMENT '消息元数据',
+    `status`          VARCHAR(16)     NOT NULL DEFAULT 'pending' COMMENT '状态：pending/published/dead',
+    `attempts`        INT UNSIGNED    NOT NULL DEFAULT 0 COMMENT '已投递次数',
+    `next_attempt_at` DATETIME(3)     NOT NULL COMMENT '下次投递时间',
+    `last_error`      VARCHAR(1024)            DEFAULT NULL COMMENT '最近一次投递错误',
+    `locked_by`       VARCHAR(128)             DEFAULT NULL COMMENT '认领该事件的中继实例',
+    `locked_until`    DATETIME(3)              DEFAULT NULL COMMENT '认领过期时间',
+    `published_at`    DATETIME(3)              DEFAULT NULL COMMENT '投递成功时间',
+    `created_at`      DATETIME(3)     NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+    UNIQUE KEY `uk_idempotency_key` (`idempotency_key`),
+    KEY `idx_status_next_attempt` (`status`, `next_attempt_at`),
+    KEY `idx_locked_by` (`locked_by`),
+    KEY `idx_published_at` (`published_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='事务性发件箱事件表';
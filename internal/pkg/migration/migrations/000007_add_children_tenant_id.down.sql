@@ -0,0 +1,3 @@
+ALTER TABLE `children`
+    DROP INDEX `idx_tenant_id`,
+    DROP COLUMN `tenant_id`;
@@ -0,0 +1,3 @@
+ALTER TABLE `children`
+    ADD COLUMN `tenant_id` VARCHAR(64) NOT NULL DEFAULT 'fangcun' COMMENT '所属租户ID' AFTER `id`,
+    ADD KEY `idx_tenant_id` (`tenant_id`);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS `auth_password_history`;
+
+ALTER TABLE `auth_credentials`
+    DROP COLUMN `password_changed_at`;
@@ -0,0 +1,21 @@
+-- 密码最长有效期：记录密码设置时间；存量密码以迁移时刻为起点，避免上线即全员强制改密
+ALTER TABLE `auth_credentials`
+    ADD COLUMN `password_changed_at` DATETIME DEFAULT NULL COMMENT '密码设置时间（仅password）' AFTER `last_failure_at`;
+
+UPDATE `auth_credentials`
+SET `password_changed_at` = CURRENT_TIMESTAMP
+WHERE `type` = 'password'
+  AND `password_changed_at` IS NULL;
+
+-- 密码历史：禁止复用最近 N 个密码
+CREATE TABLE IF NOT EXISTS `auth_password_history`
+(
+    `id`            BIGINT UNSIGNED NOT NULL PRIMARY KEY COMMENT '记录ID',
+    `credential_id` BIGINT UNSIGNED NOT NULL COMMENT '密码凭据ID（auth_credentials.id）',
+    `material`      VARBINARY(512)  NOT NULL COMMENT '历史密码 PHC 哈希',
+    `algo`          VARCHAR(32)     NOT NULL COMMENT '哈希算法',
+    `created_at`    DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '写入时间（即该密码被替换的时间）',
+    KEY `idx_credential_created` (`credential_id`, `created_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='密码历史表';
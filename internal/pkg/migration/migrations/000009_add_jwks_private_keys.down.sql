@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `jwks_private_keys`;
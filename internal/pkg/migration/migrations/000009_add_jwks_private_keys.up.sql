@@ -0,0 +1,12 @@
+-- JWKS 加密私钥：jwks.storage.backend=mysql 时私钥以主密钥加密后存库，供多副本共享
+CREATE TABLE IF NOT EXISTS `jwks_private_keys`
+(
+    `id`         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY COMMENT '记录ID',
+    `kid`        VARCHAR(64)     NOT NULL COMMENT 'Key ID（jwks_keys.kid）',
+    `alg`        VARCHAR(32)     NOT NULL COMMENT '算法: RS256/ES256/EdDSA 等',
+    `ciphertext` VARBINARY(8192) NOT NULL COMMENT 'PKCS#8 私钥密文（AES-256-GCM）',
+    `created_at` DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    UNIQUE KEY `uk_private_kid` (`kid`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='JWKS 加密私钥表';
@@ -0,0 +1,20 @@
+-- 企业微信应用：登记 CorpID/AgentID 与加密的 CorpSecret，供 oauth_wecom 登录解析
+CREATE TABLE IF NOT EXISTS `idp_wecom_apps`
+(
+    `id`                     BIGINT UNSIGNED NOT NULL COMMENT '主键 ID (Snowflake)',
+    `corp_id`                VARCHAR(64)     NOT NULL COMMENT '企业 ID (CorpID)',
+    `agent_id`               VARCHAR(64)     NOT NULL COMMENT '应用 ID (AgentID)',
+    `name`                   VARCHAR(255)    NOT NULL COMMENT '应用名称',
+    `status`                 VARCHAR(32)     NOT NULL DEFAULT 'Enabled' COMMENT '应用状态 (Enabled/Disabled/Archived)',
+    `corp_secret_cipher`     BLOB                     DEFAULT NULL COMMENT 'CorpSecret 密文 (AES-GCM 加密)',
+    `corp_secret_fp`         VARCHAR(128)             DEFAULT NULL COMMENT 'CorpSecret 指纹 (SHA256)',
+    `corp_secret_version`    INT             NOT NULL DEFAULT 0 COMMENT 'CorpSecret 版本号',
+    `corp_secret_rotated_at` DATETIME                 DEFAULT NULL COMMENT 'CorpSecret 最后轮换时间',
+    `created_at`             DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`             DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_corp_agent` (`corp_id`, `agent_id`),
+    KEY `idx_status` (`status`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='企业微信应用表 - 管理企业微信自建应用配置';
@@ -0,0 +1,26 @@
+-- 上游 OIDC 身份提供商：登记 Issuer/ClientID、加密的 client_secret 与声明映射，供 oauth_oidc 联合登录解析
+CREATE TABLE IF NOT EXISTS `idp_oidc_providers`
+(
+    `id`                       BIGINT UNSIGNED NOT NULL COMMENT '主键 ID (Snowflake)',
+    `slug`                     VARCHAR(64)     NOT NULL COMMENT '提供商标识，写入联合登录凭据 app_id',
+    `name`                     VARCHAR(255)    NOT NULL COMMENT '提供商名称',
+    `issuer`                   VARCHAR(512)    NOT NULL COMMENT '上游 Issuer（discovery 与 id_token iss 校验）',
+    `client_id`                VARCHAR(255)    NOT NULL COMMENT '上游分配的 client_id',
+    `scopes`                   JSON                     DEFAULT NULL COMMENT '授权请求 scope 列表',
+    `claim_mapping`            JSON                     DEFAULT NULL COMMENT '声明映射 (subject/username/email/name/phone)',
+    `role_mappings`            JSON                     DEFAULT NULL COMMENT '声明到本地角色的映射规则',
+    `auto_provision`           TINYINT(1)      NOT NULL DEFAULT 0 COMMENT '首次登录是否即时开通账户',
+    `tenant_id`                BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '映射角色所在租户，0 表示默认租户',
+    `status`                   VARCHAR(32)     NOT NULL DEFAULT 'Enabled' COMMENT '状态 (Enabled/Disabled/Archived)',
+    `client_secret_cipher`     BLOB                     DEFAULT NULL COMMENT 'client_secret 密文 (AES-GCM 加密)',
+    `client_secret_fp`         VARCHAR(128)             DEFAULT NULL COMMENT 'client_secret 指纹 (SHA256)',
+    `client_secret_version`    INT             NOT NULL DEFAULT 0 COMMENT 'client_secret 版本号',
+    `client_secret_rotated_at` DATETIME                 DEFAULT NULL COMMENT 'client_secret 最后轮换时间',
+    `created_at`               DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`               DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_slug` (`slug`),
+    KEY `idx_status` (`status`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='上游 OIDC 身份提供商表 - 管理联合登录配置';
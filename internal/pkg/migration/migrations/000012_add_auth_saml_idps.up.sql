@@ -0,0 +1,29 @@
+-- SAML IdP 登记：租户提交的 IdP 元数据（EntityID、SSO 地址、签名证书）与属性映射，供 SAML SP 登录校验断言
+CREATE TABLE IF NOT EXISTS `auth_saml_idps`
+(
+    `id`                BIGINT UNSIGNED NOT NULL COMMENT '主键 ID (Snowflake)',
+    `tenant_id`         BIGINT UNSIGNED NOT NULL COMMENT '登记所属租户，即时开通与登录令牌使用该租户',
+    `slug`              VARCHAR(64)     NOT NULL COMMENT 'IdP 标识，写入联合登录凭据 app_id 与 ACS 路径',
+    `name`              VARCHAR(255)    NOT NULL COMMENT 'IdP 名称',
+    `entity_id`         VARCHAR(512)    NOT NULL COMMENT 'IdP EntityID（断言 Issuer 校验）',
+    `sso_url`           VARCHAR(1024)   NOT NULL COMMENT 'HTTP-Redirect 绑定的 SSO 地址',
+    `certificates`      JSON                     DEFAULT NULL COMMENT 'IdP 签名证书列表（Base64 DER）',
+    `attribute_mapping` JSON                     DEFAULT NULL COMMENT 'SAML 属性名到 Claims 键的映射',
+    `auto_provision`    TINYINT(1)      NOT NULL DEFAULT 0 COMMENT '首次登录是否即时开通账户',
+    `status`            VARCHAR(32)     NOT NULL DEFAULT 'Enabled' COMMENT '状态 (Enabled/Disabled)',
+    `metadata_xml`      MEDIUMTEXT               DEFAULT NULL COMMENT '登记时提交的 IdP 元数据原文',
+    `created_at`        DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`        DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    `deleted_at`        DATETIME                 DEFAULT NULL COMMENT '删除时间（软删除）',
+    `created_by`        BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '创建人ID',
+    `updated_by`        BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '更新人ID',
+    `deleted_by`        BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '删除人ID',
+    `version`           INT UNSIGNED    NOT NULL DEFAULT 1 COMMENT '乐观锁版本号',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_slug` (`slug`),
+    KEY `idx_tenant_id` (`tenant_id`),
+    KEY `idx_status` (`status`),
+    KEY `idx_deleted_at` (`deleted_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='SAML IdP 登记表 - 管理租户 SAML 单点登录配置';
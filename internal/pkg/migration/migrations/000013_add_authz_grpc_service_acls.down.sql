@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `authz_grpc_service_acl_seeds`;
+DROP TABLE IF EXISTS `authz_grpc_service_acls`;
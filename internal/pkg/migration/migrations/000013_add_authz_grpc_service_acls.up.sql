@@ -0,0 +1,33 @@
+-- gRPC 服务 ACL：调用方服务（mTLS 证书 CN）允许/拒绝调用的方法，由 gRPC 拦截器热加载；表为空时以 grpc_acl.yaml 初始化
+CREATE TABLE IF NOT EXISTS `authz_grpc_service_acls`
+(
+    `id`              BIGINT UNSIGNED NOT NULL COMMENT '主键 ID (Snowflake)',
+    `service_name`    VARCHAR(128)    NOT NULL COMMENT '调用方服务名（客户端证书 CN）',
+    `enabled`         TINYINT(1)      NOT NULL DEFAULT 1 COMMENT '是否启用，禁用后该服务的全部调用被拒绝',
+    `description`     VARCHAR(512)             DEFAULT NULL COMMENT '描述',
+    `allowed_methods` JSON                     DEFAULT NULL COMMENT '允许调用的方法模式列表',
+    `denied_methods`  JSON                     DEFAULT NULL COMMENT '拒绝调用的方法模式列表（优先于允许列表）',
+    `created_at`      DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`      DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    `deleted_at`      DATETIME                 DEFAULT NULL COMMENT '删除时间',
+    `created_by`      BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '创建人ID',
+    `updated_by`      BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '更新人ID',
+    `deleted_by`      BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '删除人ID',
+    `version`         INT UNSIGNED    NOT NULL DEFAULT 1 COMMENT '乐观锁版本号',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_service_name` (`service_name`),
+    KEY `idx_deleted_at` (`deleted_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='gRPC 服务 ACL 表 - 管理内部服务可调用的 gRPC 方法';
+
+-- gRPC 服务 ACL 导入标记：grpc_acl.yaml 只在首次启动时导入一次，之后清空条目也不再重新导入
+CREATE TABLE IF NOT EXISTS `authz_grpc_service_acl_seeds`
+(
+    `name`      VARCHAR(64)  NOT NULL COMMENT '种子来源标识',
+    `services`  INT UNSIGNED NOT NULL DEFAULT 0 COMMENT '导入的条目数',
+    `seeded_at` DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '导入时间',
+    PRIMARY KEY (`name`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='gRPC 服务 ACL 导入标记表 - 记录启动配置是否已导入';
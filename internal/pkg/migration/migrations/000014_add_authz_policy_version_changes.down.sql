@@ -0,0 +1,2 @@
+ALTER TABLE `authz_policy_versions`
+    DROP COLUMN `changes`;
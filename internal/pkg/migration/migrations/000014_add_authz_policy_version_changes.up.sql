@@ -0,0 +1,3 @@
+-- 策略变更历史：每个版本记录产生它的 Casbin 规则变更集，用于版本差异与回滚；存量版本为 NULL 表示未记录
+ALTER TABLE `authz_policy_versions`
+    ADD COLUMN `changes` JSON DEFAULT NULL COMMENT '产生该版本的规则变更集（NULL 表示未记录）' AFTER `reason`;
@@ -0,0 +1,6 @@
+ALTER TABLE `authz_assignments`
+    DROP KEY `idx_status_expires_at`,
+    DROP KEY `idx_status_not_before`,
+    DROP COLUMN `status`,
+    DROP COLUMN `expires_at`,
+    DROP COLUMN `not_before`;
@@ -0,0 +1,7 @@
+-- 限时赋权：生效/失效时间由调度器在边界处增删 Casbin g 规则；过期记录保留用于历史查询
+ALTER TABLE `authz_assignments`
+    ADD COLUMN `not_before` DATETIME             DEFAULT NULL COMMENT '生效时间（NULL 表示立即生效）' AFTER `granted_at`,
+    ADD COLUMN `expires_at` DATETIME             DEFAULT NULL COMMENT '失效时间（NULL 表示永久有效）' AFTER `not_before`,
+    ADD COLUMN `status`     VARCHAR(16) NOT NULL DEFAULT 'active' COMMENT '有效期状态: pending/active/expired' AFTER `expires_at`,
+    ADD KEY `idx_status_not_before` (`status`, `not_before`),
+    ADD KEY `idx_status_expires_at` (`status`, `expires_at`);
@@ -48,9 +48,10 @@ type GRPCAuthOptions struct {
 
 // GRPCAclOptions ACL 配置
 type GRPCAclOptions struct {
-	Enabled       bool   `json:"enabled"        mapstructure:"enabled"`
-	ConfigFile    string `json:"config_file"    mapstructure:"config-file"`
-	DefaultPolicy string `json:"default_policy" mapstructure:"default-policy"`
+	Enabled        bool          `json:"enabled"         mapstructure:"enabled"`
+	ConfigFile     string        `json:"config_file"     mapstructure:"config-file"` // 启动配置，数据库为空时作为种子导入
+	DefaultPolicy  string        `json:"default_policy"  mapstructure:"default-policy"`
+	ReloadInterval time.Duration `json:"reload_interval" mapstructure:"reload-interval"` // 从数据库同步服务 ACL 的间隔（跨副本热更新）
 }
 
 // GRPCAuditOptions 审计配置
@@ -81,8 +82,9 @@ func NewGRPCOptions() *GRPCOptions {
 			RequireIdentityMatch:  true,
 		},
 		ACL: &GRPCAclOptions{
-			Enabled:       false,
-			DefaultPolicy: "deny",
+			Enabled:        false,
+			DefaultPolicy:  "deny",
+			ReloadInterval: 10 * time.Second,
 		},
 		Audit: &GRPCAuditOptions{
 			Enabled: true,
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Config 限流配置
+type Config struct {
+	Enabled bool
+	// FailOpen Redis 不可用时是否放行（默认放行，避免限流故障放大为登录故障）
+	FailOpen bool
+	Policies []Policy
+}
+
+type policyOptions struct {
+	Name          string        `mapstructure:"name"`
+	Routes        []string      `mapstructure:"routes"`
+	Rate          int           `mapstructure:"rate"`
+	Period        time.Duration `mapstructure:"period"`
+	Burst         int           `mapstructure:"burst"`
+	KeyBy         []string      `mapstructure:"key_by"`
+	AccountFields []string      `mapstructure:"account_fields"`
+}
+
+// LoadConfig 从 viper 的 ratelimit 段读取配置
+func LoadConfig() Config {
+	cfg := Config{FailOpen: true}
+	sub := viper.Sub("ratelimit")
+	if sub == nil {
+		return cfg
+	}
+	cfg.Enabled = sub.GetBool("enabled")
+	if sub.IsSet("fail_open") {
+		cfg.FailOpen = sub.GetBool("fail_open")
+	}
+
+	var raw []policyOptions
+	if err := sub.UnmarshalKey("policies", &raw); err != nil {
+		return cfg
+	}
+	for _, r := range raw {
+		p := Policy{
+			Name:          strings.TrimSpace(r.Name),
+			Routes:        r.Routes,
+			Rate:          r.Rate,
+			Period:        r.Period,
+			Burst:         r.Burst,
+			AccountFields: r.AccountFields,
+		}
+		if p.Period <= 0 {
+			p.Period = time.Minute
+		}
+		for _, d := range r.KeyBy {
+			p.KeyBy = append(p.KeyBy, Dimension(strings.ToLower(strings.TrimSpace(d))))
+		}
+		if len(p.KeyBy) == 0 {
+			p.KeyBy = []Dimension{DimensionIP}
+		}
+		cfg.Policies = append(cfg.Policies, p)
+	}
+	return cfg
+}
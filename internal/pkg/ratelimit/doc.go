@@ -0,0 +1,6 @@
+// Package ratelimit 提供基于 Redis GCRA 的分布式限流。
+//
+// 限流策略按路由匹配（HTTP 为 "METHOD /path"，gRPC 为 full method），
+// 按配置的维度（客户端 IP、账号标识、租户、路由）组合出限流 key，
+// 多副本共享同一 Redis 计数，HTTP 中间件与 gRPC 拦截器位于 internal/pkg/middleware。
+package ratelimit
@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/FangcunMount/component-base/pkg/log"
+)
+
+// Decision 多条策略合并后的判定：任一策略拒绝即拒绝，响应头取剩余配额最少的策略
+type Decision struct {
+	Allowed    bool
+	Policy     string
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+	// Matched 是否命中任何策略；未命中时不输出限流响应头
+	Matched bool
+}
+
+// Enforcer 按策略执行限流；限流后端可在服务启动后再绑定（gRPC 服务器先于 Redis 创建）
+type Enforcer struct {
+	policies []Policy
+	failOpen bool
+	limiter  atomic.Value // limiterHolder
+}
+
+type limiterHolder struct{ Limiter }
+
+// NewEnforcer 创建限流执行器
+func NewEnforcer(cfg Config) *Enforcer {
+	e := &Enforcer{failOpen: cfg.FailOpen}
+	for _, p := range cfg.Policies {
+		if p.Name == "" || p.Rate <= 0 || p.Period <= 0 || len(p.Routes) == 0 {
+			log.Warnw("skip invalid rate limit policy", "policy", p.Name)
+			continue
+		}
+		e.policies = append(e.policies, p)
+	}
+	return e
+}
+
+// Bind 绑定限流后端；未绑定前所有请求放行
+func (e *Enforcer) Bind(limiter Limiter) {
+	if e == nil || limiter == nil {
+		return
+	}
+	e.limiter.Store(limiterHolder{limiter})
+}
+
+// Policies 返回匹配该路由的策略
+func (e *Enforcer) Policies(method, route string) []Policy {
+	if e == nil {
+		return nil
+	}
+	var out []Policy
+	for _, p := range e.policies {
+		if p.Matches(method, route) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Check 对匹配的策略逐一消耗配额
+func (e *Enforcer) Check(ctx context.Context, policies []Policy, attrs Attributes) Decision {
+	decision := Decision{Allowed: true}
+	holder, _ := e.limiter.Load().(limiterHolder)
+	if holder.Limiter == nil || len(policies) == 0 {
+		return decision
+	}
+
+	for _, p := range policies {
+		key, ok := p.key(attrs)
+		if !ok {
+			continue
+		}
+		res, err := holder.Allow(ctx, key, p)
+		if err != nil {
+			log.Warnw("rate limit backend error", "policy", p.Name, "error", err, "fail_open", e.failOpen)
+			if !e.failOpen {
+				return Decision{Allowed: false, Matched: true, Policy: p.Name, Limit: p.burst(), RetryAfter: time.Second}
+			}
+			continue
+		}
+		current := Decision{
+			Allowed:    res.Allowed,
+			Policy:     p.Name,
+			Limit:      res.Limit,
+			Remaining:  res.Remaining,
+			RetryAfter: res.RetryAfter,
+			ResetAfter: res.ResetAfter,
+			Matched:    true,
+		}
+		if !res.Allowed {
+			return current
+		}
+		if !decision.Matched || res.Remaining < decision.Remaining {
+			decision = current
+		}
+	}
+	return decision
+}
@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Result 一次限流判定结果
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // 被拒绝时距离下次可用的时间
+	ResetAfter time.Duration // 桶恢复满额所需时间
+}
+
+// Limiter 限流后端
+type Limiter interface {
+	Allow(ctx context.Context, key string, policy Policy) (Result, error)
+}
+
+// gcraScript 基于 Redis 服务端时间的 GCRA，多副本间不依赖本地时钟
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+local key = KEYS[1]
+local burst = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local period = tonumber(ARGV[3])
+local emission = period / rate
+local burst_offset = emission * burst
+
+local t = redis.call("TIME")
+local now = (t[1] - 1483228800) + (t[2] / 1000000)
+
+local tat = redis.call("GET", key)
+if not tat then
+  tat = now
+else
+  tat = tonumber(tat)
+end
+tat = math.max(tat, now)
+
+local new_tat = tat + emission
+local diff = now - (new_tat - burst_offset)
+local remaining = diff / emission
+if remaining < 0 then
+  return {0, 0, tostring(-diff), tostring(tat - now)}
+end
+
+local reset_after = new_tat - now
+if reset_after > 0 then
+  redis.call("SET", key, new_tat, "EX", math.ceil(reset_after))
+end
+return {1, math.floor(remaining), "-1", tostring(reset_after)}
+`)
+
+// RedisLimiter 基于 Redis 的分布式 GCRA 限流器
+type RedisLimiter struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisLimiter 创建 Redis 限流器
+func NewRedisLimiter(client redis.UniversalClient) *RedisLimiter {
+	return &RedisLimiter{client: client, prefix: "ratelimit:"}
+}
+
+// Allow 对 key 消耗一次配额
+func (l *RedisLimiter) Allow(ctx context.Context, key string, policy Policy) (Result, error) {
+	burst := policy.burst()
+	values, err := gcraScript.Run(ctx, l.client, []string{l.prefix + key},
+		burst, policy.Rate, policy.Period.Seconds()).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{Limit: burst}
+	if len(values) == 4 {
+		allowed, _ := values[0].(int64)
+		remaining, _ := values[1].(int64)
+		res.Allowed = allowed == 1
+		res.Remaining = int(remaining)
+		res.RetryAfter = parseSeconds(values[2])
+		res.ResetAfter = parseSeconds(values[3])
+	}
+	if res.RetryAfter < 0 {
+		res.RetryAfter = 0
+	}
+	return res, nil
+}
+
+func parseSeconds(v interface{}) time.Duration {
+	s, _ := v.(string)
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(f * float64(time.Second))
+}
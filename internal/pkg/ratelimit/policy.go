@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Dimension 限流 key 的组成维度
+type Dimension string
+
+const (
+	DimensionIP      Dimension = "ip"
+	DimensionAccount Dimension = "account"
+	DimensionTenant  Dimension = "tenant"
+	DimensionRoute   Dimension = "route"
+)
+
+// Policy 单条限流策略
+type Policy struct {
+	Name string
+	// Routes HTTP 为 "METHOD /path"（gin FullPath）或 "/path"，gRPC 为 "/pkg.Service/Method"；末尾 * 表示前缀匹配
+	Routes []string
+	// Rate 每个 Period 允许的请求数
+	Rate   int
+	Period time.Duration
+	// Burst 允许的突发量，默认等于 Rate
+	Burst int
+	KeyBy []Dimension
+	// AccountFields 从 JSON 请求体解析账号标识的字段路径（如 credentials.username），仅 HTTP 使用
+	AccountFields []string
+}
+
+// Matches 判断策略是否作用于该路由
+func (p Policy) Matches(method, route string) bool {
+	for _, pattern := range p.Routes {
+		want := pattern
+		if m, path, ok := strings.Cut(pattern, " "); ok {
+			if method == "" || !strings.EqualFold(m, method) {
+				continue
+			}
+			want = strings.TrimSpace(path)
+		}
+		if strings.HasSuffix(want, "*") {
+			if strings.HasPrefix(route, strings.TrimSuffix(want, "*")) {
+				return true
+			}
+			continue
+		}
+		if want == route {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Policy) burst() int {
+	if p.Burst > 0 {
+		return p.Burst
+	}
+	return p.Rate
+}
+
+// Attributes 一次请求可用于组合限流 key 的属性
+type Attributes struct {
+	IP      string
+	Account string
+	Tenant  string
+	Route   string
+}
+
+func (a Attributes) value(d Dimension) string {
+	switch d {
+	case DimensionIP:
+		return a.IP
+	case DimensionAccount:
+		return a.Account
+	case DimensionTenant:
+		return a.Tenant
+	case DimensionRoute:
+		return a.Route
+	}
+	return ""
+}
+
+// key 组合限流 key；任一维度缺失时返回 false，该策略本次不生效（避免把无法识别的调用方归到同一个桶）
+func (p Policy) key(attrs Attributes) (string, bool) {
+	parts := make([]string, 0, len(p.KeyBy)+1)
+	parts = append(parts, p.Name)
+	for _, d := range p.KeyBy {
+		v := attrs.value(d)
+		if v == "" {
+			return "", false
+		}
+		if d == DimensionAccount {
+			// 账号标识可能是手机号或令牌，只保留摘要
+			sum := sha256.Sum256([]byte(v))
+			v = hex.EncodeToString(sum[:8])
+		}
+		parts = append(parts, string(d)+"="+v)
+	}
+	return strings.Join(parts, ":"), true
+}
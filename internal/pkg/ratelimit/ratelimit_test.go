@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestEnforcer(t *testing.T, policies ...Policy) (*Enforcer, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { _ = client.Close() })
+
+	e := NewEnforcer(Config{Enabled: true, FailOpen: true, Policies: policies})
+	e.Bind(NewRedisLimiter(client))
+	return e, mr
+}
+
+func TestEnforcerLimitsPerDimension(t *testing.T) {
+	login := Policy{Name: "login", Routes: []string{"POST /api/v1/authn/login"}, Rate: 2, Period: time.Minute, KeyBy: []Dimension{DimensionIP}}
+	e, mr := newTestEnforcer(t, login)
+	mr.SetTime(time.Now())
+	ctx := context.Background()
+
+	policies := e.Policies("POST", "/api/v1/authn/login")
+	if len(policies) != 1 {
+		t.Fatalf("expected login policy to match, got %v", policies)
+	}
+	if len(e.Policies("GET", "/api/v1/authn/login")) != 0 {
+		t.Fatal("method mismatch should not match")
+	}
+
+	a := Attributes{IP: "10.0.0.1"}
+	for i := 0; i < 2; i++ {
+		if d := e.Check(ctx, policies, a); !d.Allowed {
+			t.Fatalf("request %d should be allowed: %+v", i, d)
+		}
+	}
+	denied := e.Check(ctx, policies, a)
+	if denied.Allowed || denied.RetryAfter <= 0 || denied.Policy != "login" {
+		t.Fatalf("third request should be limited: %+v", denied)
+	}
+
+	// 另一个 IP 使用独立的桶
+	if d := e.Check(ctx, policies, Attributes{IP: "10.0.0.2"}); !d.Allowed || d.Remaining != 1 {
+		t.Fatalf("other ip should be allowed with 1 remaining: %+v", d)
+	}
+
+	// 时间推进一个发放间隔后恢复一次配额
+	mr.SetTime(time.Now().Add(31 * time.Second))
+	if d := e.Check(ctx, policies, a); !d.Allowed {
+		t.Fatalf("request after emission interval should be allowed: %+v", d)
+	}
+}
+
+func TestEnforcerSkipsPolicyWithMissingDimension(t *testing.T) {
+	byAccount := Policy{Name: "otp", Routes: []string{"/api/v1/authn/login/*"}, Rate: 1, Period: time.Minute, KeyBy: []Dimension{DimensionAccount}}
+	e, _ := newTestEnforcer(t, byAccount)
+	policies := e.Policies("POST", "/api/v1/authn/login/prep/phone-otp")
+	if len(policies) != 1 {
+		t.Fatalf("expected prefix route match, got %v", policies)
+	}
+	for i := 0; i < 3; i++ {
+		if d := e.Check(context.Background(), policies, Attributes{IP: "10.0.0.1"}); !d.Allowed || d.Matched {
+			t.Fatalf("policy without account should not apply: %+v", d)
+		}
+	}
+}
+
+func TestEnforcerFailOpenAndUnbound(t *testing.T) {
+	p := Policy{Name: "p", Routes: []string{"/x"}, Rate: 1, Period: time.Minute, KeyBy: []Dimension{DimensionRoute}}
+	unbound := NewEnforcer(Config{Policies: []Policy{p}})
+	if d := unbound.Check(context.Background(), []Policy{p}, Attributes{Route: "/x"}); !d.Allowed {
+		t.Fatal("unbound enforcer should allow")
+	}
+
+	e, mr := newTestEnforcer(t, p)
+	mr.Close()
+	if d := e.Check(context.Background(), []Policy{p}, Attributes{Route: "/x"}); !d.Allowed {
+		t.Fatal("fail-open enforcer should allow when redis is down")
+	}
+	e.failOpen = false
+	if d := e.Check(context.Background(), []Policy{p}, Attributes{Route: "/x"}); d.Allowed {
+		t.Fatal("fail-closed enforcer should deny when redis is down")
+	}
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -63,6 +64,34 @@ func WithRunFunc(run RunFunc) Option {
 	}
 }
 
+// WithNoConfig 设置应用程序不提供配置文件标志
+func WithNoConfig() Option {
+	return func(a *App) {
+		a.noConfig = true
+	}
+}
+
+// WithNoVersion 设置应用程序不提供版本标志
+func WithNoVersion() Option {
+	return func(a *App) {
+		a.noVersion = true
+	}
+}
+
+// WithSilence 设置应用程序为静默模式，不打印启动信息
+func WithSilence() Option {
+	return func(a *App) {
+		a.silence = true
+	}
+}
+
+// WithCommands 设置应用程序的子命令
+func WithCommands(commands ...*Command) Option {
+	return func(a *App) {
+		a.commands = append(a.commands, commands...)
+	}
+}
+
 // WithValidArgs 设置 args
 func WithValidArgs(args cobra.PositionalArgs) Option {
 	return func(a *App) {
@@ -300,12 +329,30 @@ func addCmdTemplate(cmd *cobra.Command, namedFlagSets cliflag.NamedFlagSets) {
 	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
 	cmd.SetUsageFunc(func(cmd *cobra.Command) error {
 		_, _ = fmt.Fprintf(cmd.OutOrStderr(), usageFmt, cmd.UseLine())
+		printSubCommands(cmd.OutOrStderr(), cmd)
 		cliflag.PrintSections(cmd.OutOrStderr(), namedFlagSets, cols)
 
 		return nil
 	})
 	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
 		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n\n"+usageFmt, cmd.Long, cmd.UseLine())
+		printSubCommands(cmd.OutOrStdout(), cmd)
 		cliflag.PrintSections(cmd.OutOrStdout(), namedFlagSets, cols)
 	})
 }
+
+// printSubCommands 打印可用的子命令
+func printSubCommands(w io.Writer, cmd *cobra.Command) {
+	if !cmd.HasAvailableSubCommands() {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "\nAvailable Commands:\n")
+	for _, sub := range cmd.Commands() {
+		if !sub.IsAvailableCommand() && sub.Name() != "help" {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "  %s %s\n", color.GreenString(fmt.Sprintf("%-*s", sub.NamePadding(), sub.Name())), sub.Short)
+	}
+	_, _ = fmt.Fprintf(w, "\nUse \"%s [command] --help\" for more information about a command.\n\n", cmd.CommandPath())
+}
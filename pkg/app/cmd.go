@@ -8,6 +8,8 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+
+	cliflag "github.com/FangcunMount/iam-contracts/pkg/flag"
 )
 
 // Command 命令
@@ -15,8 +17,10 @@ type Command struct {
 	usage    string
 	desc     string
 	options  CliOptions
+	args     cobra.PositionalArgs
 	commands []*Command
 	runFunc  RunCommandFunc
+	runE     RunCobraCommandFunc
 }
 
 // CommandOption 命令选项
@@ -42,6 +46,47 @@ func NewCommand(usage string, desc string, opts ...CommandOption) *Command {
 // RunCommandFunc 定义应用程序的命令启动回调函数
 type RunCommandFunc func(args []string) error
 
+// RunCobraCommandFunc 定义可访问 cobra 命令（如输出流）的命令启动回调函数
+type RunCobraCommandFunc func(cmd *cobra.Command, args []string) error
+
+// WithCommandOptions 设置命令的命令行选项
+func WithCommandOptions(opt CliOptions) CommandOption {
+	return func(c *Command) {
+		c.options = opt
+	}
+}
+
+// WithCommandRunFunc 设置命令的启动回调函数
+func WithCommandRunFunc(run RunCommandFunc) CommandOption {
+	return func(c *Command) {
+		c.runFunc = run
+	}
+}
+
+// WithCommandRunE 设置可访问 cobra 命令的启动回调函数，优先于 WithCommandRunFunc
+func WithCommandRunE(run RunCobraCommandFunc) CommandOption {
+	return func(c *Command) {
+		c.runE = run
+	}
+}
+
+// WithCommandValidArgs 设置命令的位置参数校验
+func WithCommandValidArgs(args cobra.PositionalArgs) CommandOption {
+	return func(c *Command) {
+		c.args = args
+	}
+}
+
+// AddCommand 添加子命令
+func (c *Command) AddCommand(cmd *Command) {
+	c.commands = append(c.commands, cmd)
+}
+
+// AddCommands 添加多个子命令
+func (c *Command) AddCommands(cmds ...*Command) {
+	c.commands = append(c.commands, cmds...)
+}
+
 // FormatBaseName 格式化基础名称
 func FormatBaseName(basename string) string {
 	// 根据操作系统，将名称转换为小写，并去除可执行文件后缀
@@ -58,6 +103,8 @@ func (c *Command) cobraCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   c.usage,
 		Short: c.desc,
+		Long:  c.desc,
+		Args:  c.args,
 	}
 	cmd.SetOutput(os.Stdout)
 	cmd.Flags().SortFlags = false
@@ -66,26 +113,34 @@ func (c *Command) cobraCommand() *cobra.Command {
 			cmd.AddCommand(command.cobraCommand())
 		}
 	}
-	if c.runFunc != nil {
+	if c.runFunc != nil || c.runE != nil {
 		cmd.Run = c.runCommand
 	}
+	var namedFlagSets cliflag.NamedFlagSets
 	if c.options != nil {
-		for _, f := range c.options.Flags().FlagSets {
+		namedFlagSets = c.options.Flags()
+		for _, f := range namedFlagSets.FlagSets {
 			cmd.Flags().AddFlagSet(f)
 		}
-		// c.options.AddFlags(cmd.Flags())
 	}
 	addHelpCommandFlag(c.usage, cmd.Flags())
+	// 子命令使用自身的标志分组输出帮助，而不是继承根命令的
+	addCmdTemplate(cmd, namedFlagSets)
 
 	return cmd
 }
 
 // runCommand 运行命令
 func (c *Command) runCommand(cmd *cobra.Command, args []string) {
-	if c.runFunc != nil {
-		if err := c.runFunc(args); err != nil {
-			fmt.Printf("%v %v\n", color.RedString("Error:"), err)
-			os.Exit(1)
-		}
+	var err error
+	switch {
+	case c.runE != nil:
+		err = c.runE(cmd, args)
+	case c.runFunc != nil:
+		err = c.runFunc(args)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%v %v\n", color.RedString("Error:"), err)
+		os.Exit(1)
 	}
 }